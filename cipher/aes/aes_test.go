@@ -5,6 +5,7 @@
 package aes
 
 import (
+	"bytes"
 	"testing"
 )
 
@@ -318,6 +319,48 @@ func TestCipherDecrypt(t *testing.T) {
 	}
 }
 
+// Test cipher returned by New (possibly using CPU instructions)
+// against FIPS 197 examples.
+func TestNew(t *testing.T) {
+	for i, tt := range encryptTests {
+		c, err := New(tt.key)
+		if err != nil {
+			t.Errorf("New(%d bytes) = %s", len(tt.key), err)
+			continue
+		}
+		out := make([]byte, len(tt.in))
+		c.Encrypt(out, tt.in)
+		if !bytes.Equal(out, tt.out) {
+			t.Errorf("New %d: Encrypt = %x, want %x", i, out, tt.out)
+		}
+		c.Decrypt(out, tt.out)
+		if !bytes.Equal(out, tt.in) {
+			t.Errorf("New %d: Decrypt = %x, want %x", i, out, tt.in)
+		}
+	}
+	if _, err := New(make([]byte, 17)); err == nil {
+		t.Error("New accepted wrong key size")
+	}
+}
+
+func TestZeroize(t *testing.T) {
+	tt := encryptTests[2]
+	c := NewCipher()
+	c.SetKey(tt.key)
+	c.Zeroize()
+	if c.enc != [len(c.enc)]uint32{} || c.dec != [len(c.dec)]uint32{} {
+		t.Error("expanded key not zeroized")
+	}
+
+	// Cipher can be keyed again
+	out := make([]byte, len(tt.in))
+	c.SetKey(tt.key)
+	c.Encrypt(out, tt.in)
+	if !bytes.Equal(out, tt.out) {
+		t.Errorf("Encrypt after Zeroize = %x, want %x", out, tt.out)
+	}
+}
+
 // Test short input/output.
 // Assembly used to not notice.
 // See issue 7928.
@@ -2,7 +2,8 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build amd64, !noasm
+//go:build amd64 && !noasm
+// +build amd64,!noasm
 
 #include "textflag.h"
 
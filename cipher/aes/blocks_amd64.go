@@ -0,0 +1,65 @@
+//go:build amd64 && !noasm
+// +build amd64,!noasm
+
+package aes
+
+import (
+	cpu "github.com/henrydcase/nobs/utils/cpu"
+)
+
+// Signals that AES-NI can be used
+var hasAES = cpu.X86.HasAES
+
+// Signals that VAES kernels with AVX-512 can be used. Otherwise AES-NI
+// kernels are used.
+var hasVAES = cpu.X86.HasVAES && cpu.X86.HasAVX512F
+
+// defined in blocks_amd64.s
+
+//go:noescape
+func encryptBlocks8Asm(nr int, xk *uint32, dst, src *byte, n int)
+
+//go:noescape
+func decryptBlocks8Asm(nr int, xk *uint32, dst, src *byte, n int)
+
+//go:noescape
+func encryptBlocksVAES(nr int, xk *uint32, dst, src *byte, n int)
+
+//go:noescape
+func decryptBlocksVAES(nr int, xk *uint32, dst, src *byte, n int)
+
+// Processes multiples of 4 blocks with VAES kernel if available, then
+// multiples of 8 blocks with AES-NI kernel and the rest block by block.
+func (c *AESAsm) encryptBlocks(dst, src []byte) {
+	nr, n := len(c.enc)/4-1, len(src)/BlockSize
+	if hasVAES && n >= 4 {
+		m := n &^ 3
+		encryptBlocksVAES(nr, &c.enc[0], &dst[0], &src[0], m)
+		dst, src, n = dst[m*BlockSize:], src[m*BlockSize:], n-m
+	}
+	if n >= 8 {
+		m := n &^ 7
+		encryptBlocks8Asm(nr, &c.enc[0], &dst[0], &src[0], m)
+		dst, src, n = dst[m*BlockSize:], src[m*BlockSize:], n-m
+	}
+	for i := 0; i < n*BlockSize; i += BlockSize {
+		encryptBlockAsm(nr, &c.enc[0], &dst[i], &src[i])
+	}
+}
+
+func (c *AESAsm) decryptBlocks(dst, src []byte) {
+	nr, n := len(c.dec)/4-1, len(src)/BlockSize
+	if hasVAES && n >= 4 {
+		m := n &^ 3
+		decryptBlocksVAES(nr, &c.dec[0], &dst[0], &src[0], m)
+		dst, src, n = dst[m*BlockSize:], src[m*BlockSize:], n-m
+	}
+	if n >= 8 {
+		m := n &^ 7
+		decryptBlocks8Asm(nr, &c.dec[0], &dst[0], &src[0], m)
+		dst, src, n = dst[m*BlockSize:], src[m*BlockSize:], n-m
+	}
+	for i := 0; i < n*BlockSize; i += BlockSize {
+		decryptBlockAsm(nr, &c.dec[0], &dst[i], &src[i])
+	}
+}
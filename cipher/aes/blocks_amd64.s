@@ -0,0 +1,205 @@
+//go:build amd64 && !noasm
+// +build amd64,!noasm
+
+#include "textflag.h"
+
+// Kernels processing multiple blocks. Arguments are:
+//   nr:  number of rounds (10, 12 or 14)
+//   xk:  expanded encryption or decryption key
+//   dst: output
+//   src: input
+//   n:   number of blocks, multiple of 8 (AES-NI) or 4 (VAES)
+// Round keys are applied in the same way as in encryptBlockAsm and
+// decryptBlockAsm, decryption uses the equivalent inverse cipher.
+
+// ---------------------------------------------------------------------------
+// AES-NI, 8 blocks interleaved to hide latency of AESENC/AESDEC
+//
+
+#define XOR8(k) \
+	PXOR k, X0; PXOR k, X1; PXOR k, X2; PXOR k, X3; \
+	PXOR k, X4; PXOR k, X5; PXOR k, X6; PXOR k, X7
+
+#define ROUND8(op, k) \
+	op k, X0; op k, X1; op k, X2; op k, X3; \
+	op k, X4; op k, X5; op k, X6; op k, X7
+
+#define LOAD8 \
+	MOVUPS 0(SI), X0; MOVUPS 16(SI), X1; MOVUPS 32(SI), X2; MOVUPS 48(SI), X3; \
+	MOVUPS 64(SI), X4; MOVUPS 80(SI), X5; MOVUPS 96(SI), X6; MOVUPS 112(SI), X7
+
+#define STORE8 \
+	MOVUPS X0, 0(DI); MOVUPS X1, 16(DI); MOVUPS X2, 32(DI); MOVUPS X3, 48(DI); \
+	MOVUPS X4, 64(DI); MOVUPS X5, 80(DI); MOVUPS X6, 96(DI); MOVUPS X7, 112(DI)
+
+// Applies rounds 1..nr with key pointer R8 at round key 1, nr in CX
+#define ROUNDS8(op, oplast, l128, l192) \
+	CMPQ CX, $12; \
+	JB l128; \
+	JE l192; \
+	MOVUPS 0(R8), X8; ROUND8(op, X8); \
+	MOVUPS 16(R8), X8; ROUND8(op, X8); \
+	ADDQ $32, R8; \
+l192: \
+	MOVUPS 0(R8), X8; ROUND8(op, X8); \
+	MOVUPS 16(R8), X8; ROUND8(op, X8); \
+	ADDQ $32, R8; \
+l128: \
+	MOVUPS 0(R8), X8; ROUND8(op, X8); \
+	MOVUPS 16(R8), X8; ROUND8(op, X8); \
+	MOVUPS 32(R8), X8; ROUND8(op, X8); \
+	MOVUPS 48(R8), X8; ROUND8(op, X8); \
+	MOVUPS 64(R8), X8; ROUND8(op, X8); \
+	MOVUPS 80(R8), X8; ROUND8(op, X8); \
+	MOVUPS 96(R8), X8; ROUND8(op, X8); \
+	MOVUPS 112(R8), X8; ROUND8(op, X8); \
+	MOVUPS 128(R8), X8; ROUND8(op, X8); \
+	MOVUPS 144(R8), X8; ROUND8(oplast, X8)
+
+// func encryptBlocks8Asm(nr int, xk *uint32, dst, src *byte, n int)
+TEXT ·encryptBlocks8Asm(SB),NOSPLIT,$0-40
+	MOVQ nr+0(FP), CX
+	MOVQ xk+8(FP), AX
+	MOVQ dst+16(FP), DI
+	MOVQ src+24(FP), SI
+	MOVQ n+32(FP), DX
+	TESTQ DX, DX
+	JZ   enc8done
+enc8loop:
+	LOAD8
+	MOVUPS 0(AX), X8
+	XOR8(X8)
+	LEAQ 16(AX), R8
+	ROUNDS8(AESENC, AESENCLAST, enc8r128, enc8r192)
+	STORE8
+	ADDQ $128, SI
+	ADDQ $128, DI
+	SUBQ $8, DX
+	JNZ  enc8loop
+enc8done:
+	RET
+
+// func decryptBlocks8Asm(nr int, xk *uint32, dst, src *byte, n int)
+TEXT ·decryptBlocks8Asm(SB),NOSPLIT,$0-40
+	MOVQ nr+0(FP), CX
+	MOVQ xk+8(FP), AX
+	MOVQ dst+16(FP), DI
+	MOVQ src+24(FP), SI
+	MOVQ n+32(FP), DX
+	TESTQ DX, DX
+	JZ   dec8done
+dec8loop:
+	LOAD8
+	MOVUPS 0(AX), X8
+	XOR8(X8)
+	LEAQ 16(AX), R8
+	ROUNDS8(AESDEC, AESDECLAST, dec8r128, dec8r192)
+	STORE8
+	ADDQ $128, SI
+	ADDQ $128, DI
+	SUBQ $8, DX
+	JNZ  dec8loop
+dec8done:
+	RET
+
+// ---------------------------------------------------------------------------
+// VAES with AVX-512, 4 blocks per instruction. Round keys are broadcast
+// to all lanes of Z16..Z30 once. Key 0 is in Z16 and the last key in
+// Z30, middle keys are aligned to end in Z29, so that AES-128 uses
+// Z21..Z29, AES-192 Z19..Z29 and AES-256 Z17..Z29.
+//
+
+// Broadcasts round keys from AX, nr in CX
+#define LOADKEYS(l128, l192) \
+	VBROADCASTI32X4 0(AX), Z16; \
+	LEAQ 16(AX), R8; \
+	CMPQ CX, $12; \
+	JB l128; \
+	JE l192; \
+	VBROADCASTI32X4 0(R8), Z17; \
+	VBROADCASTI32X4 16(R8), Z18; \
+	ADDQ $32, R8; \
+l192: \
+	VBROADCASTI32X4 0(R8), Z19; \
+	VBROADCASTI32X4 16(R8), Z20; \
+	ADDQ $32, R8; \
+l128: \
+	VBROADCASTI32X4 0(R8), Z21; \
+	VBROADCASTI32X4 16(R8), Z22; \
+	VBROADCASTI32X4 32(R8), Z23; \
+	VBROADCASTI32X4 48(R8), Z24; \
+	VBROADCASTI32X4 64(R8), Z25; \
+	VBROADCASTI32X4 80(R8), Z26; \
+	VBROADCASTI32X4 96(R8), Z27; \
+	VBROADCASTI32X4 112(R8), Z28; \
+	VBROADCASTI32X4 128(R8), Z29; \
+	VBROADCASTI32X4 144(R8), Z30
+
+#define VROUND4(op, k) \
+	op k, Z0, Z0; op k, Z1, Z1; op k, Z2, Z2; op k, Z3, Z3
+
+#define VROUND1(op, k) \
+	op k, Z0, Z0
+
+// Applies all rounds with ROUND, nr in CX
+#define VROUNDS(ROUND, op, oplast, l128, l192) \
+	CMPQ CX, $12; \
+	JB l128; \
+	JE l192; \
+	ROUND(op, Z17); ROUND(op, Z18); \
+l192: \
+	ROUND(op, Z19); ROUND(op, Z20); \
+l128: \
+	ROUND(op, Z21); ROUND(op, Z22); ROUND(op, Z23); \
+	ROUND(op, Z24); ROUND(op, Z25); ROUND(op, Z26); \
+	ROUND(op, Z27); ROUND(op, Z28); ROUND(op, Z29); \
+	ROUND(oplast, Z30)
+
+// Body of VAES kernel, processes 16 blocks per iteration and then 4.
+// Remaining arguments are labels.
+#define VAESBLOCKS(op, oplast, k128, k192, loop16, loop4, r128a, r192a, r128b, r192b, done) \
+	MOVQ nr+0(FP), CX; \
+	MOVQ xk+8(FP), AX; \
+	MOVQ dst+16(FP), DI; \
+	MOVQ src+24(FP), SI; \
+	MOVQ n+32(FP), DX; \
+	LOADKEYS(k128, k192); \
+loop16: \
+	CMPQ DX, $16; \
+	JB loop4; \
+	VMOVDQU64 0(SI), Z0; \
+	VMOVDQU64 64(SI), Z1; \
+	VMOVDQU64 128(SI), Z2; \
+	VMOVDQU64 192(SI), Z3; \
+	VROUND4(VPXORQ, Z16); \
+	VROUNDS(VROUND4, op, oplast, r128a, r192a); \
+	VMOVDQU64 Z0, 0(DI); \
+	VMOVDQU64 Z1, 64(DI); \
+	VMOVDQU64 Z2, 128(DI); \
+	VMOVDQU64 Z3, 192(DI); \
+	ADDQ $256, SI; \
+	ADDQ $256, DI; \
+	SUBQ $16, DX; \
+	JMP loop16; \
+loop4: \
+	TESTQ DX, DX; \
+	JZ done; \
+	VMOVDQU64 0(SI), Z0; \
+	VROUND1(VPXORQ, Z16); \
+	VROUNDS(VROUND1, op, oplast, r128b, r192b); \
+	VMOVDQU64 Z0, 0(DI); \
+	ADDQ $64, SI; \
+	ADDQ $64, DI; \
+	SUBQ $4, DX; \
+	JMP loop4; \
+done: \
+	VZEROUPPER; \
+	RET
+
+// func encryptBlocksVAES(nr int, xk *uint32, dst, src *byte, n int)
+TEXT ·encryptBlocksVAES(SB),NOSPLIT,$0-40
+	VAESBLOCKS(VAESENC, VAESENCLAST, enck128, enck192, encloop16, encloop4, encr128a, encr192a, encr128b, encr192b, encdone)
+
+// func decryptBlocksVAES(nr int, xk *uint32, dst, src *byte, n int)
+TEXT ·decryptBlocksVAES(SB),NOSPLIT,$0-40
+	VAESBLOCKS(VAESDEC, VAESDECLAST, deck128, deck192, decloop16, decloop4, decr128a, decr192a, decr128b, decr192b, decdone)
@@ -0,0 +1,100 @@
+//go:build amd64 && !noasm
+// +build amd64,!noasm
+
+package aes
+
+import (
+	"bytes"
+	"testing"
+
+	cpu "github.com/henrydcase/nobs/utils/cpu"
+)
+
+func resetCpuFeatures() {
+	hasVAES = cpu.X86.HasVAES && cpu.X86.HasAVX512F
+}
+
+// Kernels available on this CPU, by name
+func kernels() map[string]bool {
+	k := map[string]bool{"AES-NI": false}
+	if cpu.X86.HasVAES && cpu.X86.HasAVX512F {
+		k["VAES"] = true
+	}
+	return k
+}
+
+func TestMultiBlock(t *testing.T) {
+	if !cpu.X86.HasAES {
+		t.Skip("AES-NI not supported")
+	}
+	defer resetCpuFeatures()
+
+	for _, keySize := range []int{16, 24, 32} {
+		key := randBytes(t, keySize)
+		generic := NewCipher()
+		generic.SetKey(key)
+		c := &AESAsm{}
+		if err := c.SetKey(key); err != nil {
+			t.Fatal(err)
+		}
+
+		// Block counts cover all combinations of kernels and tails
+		src := randBytes(t, 41*BlockSize)
+		for name, vaes := range kernels() {
+			hasVAES = vaes
+			for n := 0; n <= 41; n++ {
+				in := src[:n*BlockSize]
+				want, got := make([]byte, len(in)), make([]byte, len(in))
+				generic.encryptBlocks(want, in)
+				c.encryptBlocks(got, in)
+				if !bytes.Equal(got, want) {
+					t.Errorf("%s: AES-%d: encryption of %d blocks differs", name, keySize*8, n)
+				}
+				// In place
+				c.decryptBlocks(got, got)
+				if !bytes.Equal(got, in) {
+					t.Errorf("%s: AES-%d: decryption of %d blocks differs", name, keySize*8, n)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkEncryptBlocks(b *testing.B) {
+	if !cpu.X86.HasAES {
+		b.Skip("AES-NI not supported")
+	}
+	defer resetCpuFeatures()
+
+	key := make([]byte, 16)
+	buf := make([]byte, 4096)
+	generic := NewCipher()
+	generic.SetKey(key)
+	c := &AESAsm{}
+	c.SetKey(key)
+	nr := len(c.enc)/4 - 1
+
+	b.Run("generic", func(b *testing.B) {
+		b.SetBytes(int64(len(buf)))
+		for i := 0; i < b.N; i++ {
+			generic.encryptBlocks(buf, buf)
+		}
+	})
+	b.Run("AES-NI-x1", func(b *testing.B) {
+		b.SetBytes(int64(len(buf)))
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < len(buf); j += BlockSize {
+				encryptBlockAsm(nr, &c.enc[0], &buf[j], &buf[j])
+			}
+		}
+	})
+	for name, vaes := range kernels() {
+		hasVAES = vaes
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(buf)))
+			for i := 0; i < b.N; i++ {
+				c.encryptBlocks(buf, buf)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+//go:build arm64 && !noasm
+// +build arm64,!noasm
+
+package aes
+
+import (
+	cpu "github.com/henrydcase/nobs/utils/cpu"
+)
+
+// Signals that ARMv8 AES instructions can be used
+var hasAES = cpu.ARM64.HasAES
+
+func (c *AESAsm) encryptBlocks(dst, src []byte) {
+	nr := len(c.enc)/4 - 1
+	for i := 0; i < len(src); i += BlockSize {
+		encryptBlockAsm(nr, &c.enc[0], &dst[i], &src[i])
+	}
+}
+
+func (c *AESAsm) decryptBlocks(dst, src []byte) {
+	nr := len(c.dec)/4 - 1
+	for i := 0; i < len(src); i += BlockSize {
+		decryptBlockAsm(nr, &c.dec[0], &dst[i], &src[i])
+	}
+}
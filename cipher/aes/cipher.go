@@ -0,0 +1,163 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package aes implements AES block cipher (FIPS 197), using AES-NI on
+// amd64 and ARMv8 Cryptography Extensions on arm64 if available, and
+// CTR, GCM (SP 800-38D), AES-GCM-SIV (RFC 8452) and XTS (SP 800-38E)
+// modes of operation.
+//
+// Modes accept any cipher.Block, but are faster with blocks returned by
+// New, which encrypt multiple blocks at once.
+package aes
+
+import (
+	"crypto/cipher"
+	"strconv"
+)
+
+// The AES block size in bytes.
+const BlockSize = 16
+
+// AES is generic implementation of AES. The zero value must be keyed
+// with SetKey before use.
+type AES struct {
+	enc    [32 + 28]uint32
+	dec    [32 + 28]uint32
+	keyLen int
+}
+
+// KeySizeError is returned for keys of unsupported size
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "crypto/aes: invalid key size " + strconv.Itoa(int(k))
+}
+
+// NewCipher returns generic implementation, which must be keyed with
+// SetKey before use.
+func NewCipher() *AES {
+	return new(AES)
+}
+
+// Implemented by AES and AESAsm
+type block interface {
+	cipher.Block
+	multiBlock
+	SetKey(key []byte) error
+	Zeroize()
+}
+
+// Encryption and decryption of multiple blocks in one call. Length of
+// src is a multiple of BlockSize and dst is at least as long as src.
+// Implemented by AES and AESAsm, used by modes of operation.
+type multiBlock interface {
+	encryptBlocks(dst, src []byte)
+	decryptBlocks(dst, src []byte)
+}
+
+// Encrypts blocks of src to dst, with single call if b supports it
+func encryptBlocks(b cipher.Block, dst, src []byte) {
+	if m, ok := b.(multiBlock); ok {
+		m.encryptBlocks(dst, src)
+		return
+	}
+	for i := 0; i < len(src); i += BlockSize {
+		b.Encrypt(dst[i:], src[i:])
+	}
+}
+
+// Decrypts blocks of src to dst, with single call if b supports it
+func decryptBlocks(b cipher.Block, dst, src []byte) {
+	if m, ok := b.(multiBlock); ok {
+		m.decryptBlocks(dst, src)
+		return
+	}
+	for i := 0; i < len(src); i += BlockSize {
+		b.Decrypt(dst[i:], src[i:])
+	}
+}
+
+// New returns AES cipher.Block for the given key, which must be 16, 24
+// or 32 bytes long. Implementation using CPU instructions is returned
+// if available, otherwise the generic one.
+func New(key []byte) (cipher.Block, error) {
+	c := newAsm()
+	if c == nil {
+		c = NewCipher()
+	}
+	if err := c.SetKey(key); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// SetKey sets the key, which must be 16, 24 or 32 bytes long to select
+// AES-128, AES-192 or AES-256. Returns KeySizeError otherwise, in which
+// case the cipher is left unchanged.
+func (c *AES) SetKey(key []byte) error {
+	k := len(key)
+
+	switch k {
+	default:
+		return KeySizeError(k)
+	case 16, 24, 32:
+		break
+	}
+	c.Zeroize()
+	c.keyLen = k
+	expandKeyGo(key, c.enc[:c.keyLen+28], c.dec[:c.keyLen+28])
+	return nil
+}
+
+// Zeroize overwrites the expanded key with zeros. The cipher must be
+// keyed with SetKey before next use.
+func (c *AES) Zeroize() {
+	for i := range c.enc {
+		c.enc[i] = 0
+	}
+	for i := range c.dec {
+		c.dec[i] = 0
+	}
+	c.keyLen = 0
+}
+
+func (c *AES) BlockSize() int { return BlockSize }
+
+func (c *AES) Encrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		panic("crypto/aes: input not full block")
+	}
+	if len(dst) < BlockSize {
+		panic("crypto/aes: output not full block")
+	}
+	if InexactOverlap(dst[:BlockSize], src[:BlockSize]) {
+		panic("crypto/aes: invalid buffer overlap")
+	}
+	encryptBlockGo(c.enc[:c.keyLen+28], dst, src)
+}
+
+func (c *AES) Decrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		panic("crypto/aes: input not full block")
+	}
+	if len(dst) < BlockSize {
+		panic("crypto/aes: output not full block")
+	}
+	if InexactOverlap(dst[:BlockSize], src[:BlockSize]) {
+		panic("crypto/aes: invalid buffer overlap")
+	}
+	decryptBlockGo(c.dec[:c.keyLen+28], dst, src)
+}
+
+func (c *AES) encryptBlocks(dst, src []byte) {
+	for i := 0; i < len(src); i += BlockSize {
+		encryptBlockGo(c.enc[:c.keyLen+28], dst[i:], src[i:])
+	}
+}
+
+func (c *AES) decryptBlocks(dst, src []byte) {
+	for i := 0; i < len(src); i += BlockSize {
+		decryptBlockGo(c.dec[:c.keyLen+28], dst[i:], src[i:])
+	}
+}
@@ -1,13 +1,11 @@
 // Copyright 2012 The Go Authors. All rights reserved.
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
-// +build amd64, !noasm
 
-package aes
+//go:build (amd64 && !noasm) || (arm64 && !noasm)
+// +build amd64,!noasm arm64,!noasm
 
-import (
-	"github.com/henrydcase/nobs/utils"
-)
+package aes
 
 // defined in asm_*.s
 
@@ -20,11 +18,21 @@ func decryptBlockAsm(nr int, xk *uint32, dst, src *byte)
 //go:noescape
 func expandKeyAsm(nr int, key *byte, enc *uint32, dec *uint32)
 
+// AES implementation using AES-NI (amd64) or ARMv8 crypto
+// extensions (arm64).
 type AESAsm struct {
 	enc []uint32
 	dec []uint32
 }
 
+// Returns AESAsm if CPU supports AES instructions, otherwise nil
+func newAsm() block {
+	if !hasAES {
+		return nil
+	}
+	return new(AESAsm)
+}
+
 func (c *AESAsm) SetKey(key []byte) error {
 	var rounds int
 	switch len(key) {
@@ -34,12 +42,29 @@ func (c *AESAsm) SetKey(key []byte) error {
 		rounds = 12
 	case 256 / 8:
 		rounds = 14
+	default:
+		return KeySizeError(len(key))
 	}
 
+	c.Zeroize()
+	c.enc = make([]uint32, len(key)+28)
+	c.dec = make([]uint32, len(key)+28)
 	expandKeyAsm(rounds, &key[0], &c.enc[0], &c.dec[0])
 	return nil
 }
 
+// Zeroize overwrites the expanded key with zeros. The cipher must be
+// keyed with SetKey before next use.
+func (c *AESAsm) Zeroize() {
+	for i := range c.enc {
+		c.enc[i] = 0
+	}
+	for i := range c.dec {
+		c.dec[i] = 0
+	}
+	c.enc, c.dec = nil, nil
+}
+
 func (c *AESAsm) BlockSize() int { return BlockSize }
 
 func (c *AESAsm) Encrypt(dst, src []byte) {
@@ -71,7 +96,7 @@ func (c *AESAsm) Decrypt(dst, src []byte) {
 // expandKey is used by BenchmarkExpand to ensure that the asm implementation
 // of key expansion is used for the benchmark when it is available.
 func expandKey(key []byte, enc, dec []uint32) {
-	if utils.X86.HasAES {
+	if hasAES {
 		rounds := 10 // rounds needed for AES128
 		switch len(key) {
 		case 192 / 8:
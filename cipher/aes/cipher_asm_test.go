@@ -0,0 +1,42 @@
+//go:build (amd64 && !noasm) || (arm64 && !noasm)
+// +build amd64,!noasm arm64,!noasm
+
+package aes
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAsmZeroize(t *testing.T) {
+	if !hasAES {
+		t.Skip("AES instructions not supported")
+	}
+	tt := encryptTests[2]
+	c := newAsm().(*AESAsm)
+	c.SetKey(tt.key)
+	enc, dec := c.enc, c.dec
+
+	// SetKey wipes previous key
+	c.SetKey(tt.key)
+	for i := range enc {
+		if enc[i] != 0 || dec[i] != 0 {
+			t.Fatal("previous expanded key not zeroized")
+		}
+	}
+
+	enc, dec = c.enc, c.dec
+	c.Zeroize()
+	for i := range enc {
+		if enc[i] != 0 || dec[i] != 0 {
+			t.Fatal("expanded key not zeroized")
+		}
+	}
+
+	out := make([]byte, len(tt.in))
+	c.SetKey(tt.key)
+	c.Encrypt(out, tt.in)
+	if !bytes.Equal(out, tt.out) {
+		t.Errorf("Encrypt after Zeroize = %x, want %x", out, tt.out)
+	}
+}
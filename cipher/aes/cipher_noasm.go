@@ -0,0 +1,14 @@
+//go:build (!amd64 && !arm64) || noasm
+// +build !amd64,!arm64 noasm
+
+package aes
+
+// Assembly implementation not available
+func newAsm() block {
+	return nil
+}
+
+// expandKey is used by BenchmarkExpand
+func expandKey(key []byte, enc, dec []uint32) {
+	expandKeyGo(key, enc, dec)
+}
@@ -0,0 +1,65 @@
+//go:build ctcheck
+// +build ctcheck
+
+package aes
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/henrydcase/nobs/utils/ctcheck"
+)
+
+// Number of blocks processed in each measurement
+const ctBlocks = 16
+
+// Measures timing of generic implementation. It uses lookup tables
+// indexed by secret data, so it is expected to leak: the test only
+// reports the measured t-statistic and is skipped under valgrind, which
+// would report every table lookup.
+func TestGenericCT(t *testing.T) {
+	var fixedKey [32]byte
+	var fixedIn [ctBlocks * BlockSize]byte
+
+	if ctcheck.RunningOnValgrind() {
+		t.Skip("generic AES is not constant time")
+	}
+	rand.Read(fixedKey[:])
+	rand.Read(fixedIn[:])
+
+	// Fixed or random key (class 0 or 1) and fixed plaintext, or fixed
+	// key and fixed or random plaintext.
+	target := func(randomKey, decrypt bool) ctcheck.Target {
+		return func(class int) func() {
+			key, in := fixedKey, fixedIn
+			if class == 1 && randomKey {
+				rand.Read(key[:])
+			} else if class == 1 {
+				rand.Read(in[:])
+			}
+			ctcheck.Secret(key[:])
+			c := NewCipher()
+			c.SetKey(key[:])
+			if decrypt {
+				return func() { c.decryptBlocks(in[:], in[:]) }
+			}
+			return func() { c.encryptBlocks(in[:], in[:]) }
+		}
+	}
+	n := 50000
+	if testing.Short() {
+		n /= 10
+	}
+	for _, v := range []struct {
+		name   string
+		target ctcheck.Target
+	}{
+		{"Encrypt/key", target(true, false)},
+		{"Encrypt/plaintext", target(false, false)},
+		{"Decrypt/key", target(true, true)},
+		{"Decrypt/ciphertext", target(false, true)},
+	} {
+		r := ctcheck.Measure(n, v.target)
+		t.Logf("%s: max |t| = %.2f, %d measurements (expected to leak)", v.name, r.T, r.N)
+	}
+}
@@ -0,0 +1,66 @@
+package aes
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+)
+
+// Number of blocks of key stream generated at once
+const ctrBatch = 8
+
+type ctr struct {
+	b cipher.Block
+	// Next counter block, big-endian
+	hi, lo uint64
+	// Key stream and its unused part
+	ks    [ctrBatch * BlockSize]byte
+	ksPos int
+}
+
+// NewCTR returns cipher.Stream encrypting with b in counter mode. The iv
+// is the initial counter block, which is incremented as 128-bit
+// big-endian integer. Behaves as crypto/cipher.NewCTR, but generates key
+// stream for multiple blocks at once. Panics if iv is not BlockSize long.
+func NewCTR(b cipher.Block, iv []byte) cipher.Stream {
+	if b.BlockSize() != BlockSize || len(iv) != BlockSize {
+		panic("aes: IV length must equal block size")
+	}
+	return &ctr{
+		b:     b,
+		hi:    binary.BigEndian.Uint64(iv[:8]),
+		lo:    binary.BigEndian.Uint64(iv[8:]),
+		ksPos: ctrBatch * BlockSize,
+	}
+}
+
+// Generates next ctrBatch blocks of key stream
+func (c *ctr) refill() {
+	for i := 0; i < len(c.ks); i += BlockSize {
+		binary.BigEndian.PutUint64(c.ks[i:], c.hi)
+		binary.BigEndian.PutUint64(c.ks[i+8:], c.lo)
+		c.lo++
+		if c.lo == 0 {
+			c.hi++
+		}
+	}
+	encryptBlocks(c.b, c.ks[:], c.ks[:])
+	c.ksPos = 0
+}
+
+func (c *ctr) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("aes: output smaller than input")
+	}
+	if InexactOverlap(dst[:len(src)], src) {
+		panic("aes: invalid buffer overlap")
+	}
+	for len(src) > 0 {
+		if c.ksPos == len(c.ks) {
+			c.refill()
+		}
+		n := subtle.XORBytes(dst, src, c.ks[c.ksPos:])
+		c.ksPos += n
+		dst, src = dst[n:], src[n:]
+	}
+}
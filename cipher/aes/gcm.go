@@ -0,0 +1,163 @@
+package aes
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	gcmStandardNonceSize = 12
+	gcmTagSize           = 16
+	// Plaintext can have at most 2^32-2 blocks
+	gcmMaxPlaintextSize = (1<<32 - 2) * BlockSize
+)
+
+var (
+	errOpen      = errors.New("aes: message authentication failed")
+	errBlockSize = errors.New("aes: cipher with 128-bit block required")
+	errNonceSize = errors.New("aes: invalid nonce size")
+)
+
+// Returns slice of length len(in)+n, which reuses in if it has enough
+// capacity, and its last n bytes
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
+
+// Encrypts src to dst in counter mode, starting with counter block ctr,
+// which is incremented with inc. Processes ctrBatch blocks at once.
+func ctrXOR(b cipher.Block, ctr *[BlockSize]byte, inc func(*[BlockSize]byte), dst, src []byte) {
+	var ks [ctrBatch * BlockSize]byte
+	for len(src) > 0 {
+		n := len(ks)
+		if len(src) < n {
+			n = (len(src) + BlockSize - 1) &^ (BlockSize - 1)
+		}
+		for i := 0; i < n; i += BlockSize {
+			copy(ks[i:], ctr[:])
+			inc(ctr)
+		}
+		encryptBlocks(b, ks[:n], ks[:n])
+		m := subtle.XORBytes(dst, src, ks[:n])
+		dst, src = dst[m:], src[m:]
+	}
+}
+
+// Increments the last 32 bits of ctr as big-endian integer (inc32 from
+// SP 800-38D)
+func gcmInc32(ctr *[BlockSize]byte) {
+	binary.BigEndian.PutUint32(ctr[12:], binary.BigEndian.Uint32(ctr[12:])+1)
+}
+
+type gcm struct {
+	b         cipher.Block
+	h         [BlockSize]byte
+	nonceSize int
+}
+
+// NewGCM returns b in Galois/Counter Mode (SP 800-38D) with 12 byte
+// nonces and 16 byte tags. GHASH is computed in constant time.
+func NewGCM(b cipher.Block) (cipher.AEAD, error) {
+	return NewGCMWithNonceSize(b, gcmStandardNonceSize)
+}
+
+// NewGCMWithNonceSize returns b in Galois/Counter Mode with nonces of
+// given size. Use it only for compatibility with existing systems, nonces
+// of other size than 12 bytes are hashed, which reduces security margin.
+func NewGCMWithNonceSize(b cipher.Block, size int) (cipher.AEAD, error) {
+	if b.BlockSize() != BlockSize {
+		return nil, errBlockSize
+	}
+	if size <= 0 {
+		return nil, errNonceSize
+	}
+	g := &gcm{b: b, nonceSize: size}
+	b.Encrypt(g.h[:], g.h[:])
+	return g, nil
+}
+
+func (g *gcm) NonceSize() int { return g.nonceSize }
+func (g *gcm) Overhead() int  { return gcmTagSize }
+
+// Returns pre-counter block J0
+func (g *gcm) counter(nonce []byte) (j0 [BlockSize]byte) {
+	if len(nonce) == gcmStandardNonceSize {
+		copy(j0[:], nonce)
+		j0[BlockSize-1] = 1
+		return j0
+	}
+	gh := newGHASH(g.h[:])
+	gh.update(nonce)
+	gh.updateLengths(0, len(nonce))
+	gh.sum(&j0)
+	return j0
+}
+
+// Computes tag of ciphertext ct with associated data ad
+func (g *gcm) tag(out *[gcmTagSize]byte, j0 [BlockSize]byte, ct, ad []byte) {
+	gh := newGHASH(g.h[:])
+	gh.update(ad)
+	gh.update(ct)
+	gh.updateLengths(len(ad), len(ct))
+	gh.sum(out)
+	g.b.Encrypt(j0[:], j0[:])
+	subtle.XORBytes(out[:], out[:], j0[:])
+}
+
+func (g *gcm) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != g.nonceSize {
+		panic("aes: incorrect nonce length given to GCM")
+	}
+	if uint64(len(plaintext)) > gcmMaxPlaintextSize {
+		panic("aes: message too large for GCM")
+	}
+	ret, out := sliceForAppend(dst, len(plaintext)+gcmTagSize)
+	if InexactOverlap(out, plaintext) {
+		panic("aes: invalid buffer overlap")
+	}
+
+	j0 := g.counter(nonce)
+	ctr := j0
+	gcmInc32(&ctr)
+	ctrXOR(g.b, &ctr, gcmInc32, out, plaintext)
+
+	var tag [gcmTagSize]byte
+	g.tag(&tag, j0, out[:len(plaintext)], additionalData)
+	copy(out[len(plaintext):], tag[:])
+	return ret
+}
+
+func (g *gcm) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != g.nonceSize {
+		panic("aes: incorrect nonce length given to GCM")
+	}
+	if len(ciphertext) < gcmTagSize || uint64(len(ciphertext)) > gcmMaxPlaintextSize+gcmTagSize {
+		return nil, errOpen
+	}
+	ct, expected := ciphertext[:len(ciphertext)-gcmTagSize], ciphertext[len(ciphertext)-gcmTagSize:]
+
+	j0 := g.counter(nonce)
+	var tag [gcmTagSize]byte
+	g.tag(&tag, j0, ct, additionalData)
+	if subtle.ConstantTimeCompare(tag[:], expected) != 1 {
+		return nil, errOpen
+	}
+
+	ret, out := sliceForAppend(dst, len(ct))
+	if InexactOverlap(out, ciphertext) {
+		panic("aes: invalid buffer overlap")
+	}
+	ctr := j0
+	gcmInc32(&ctr)
+	ctrXOR(g.b, &ctr, gcmInc32, out, ct)
+	return ret, nil
+}
@@ -0,0 +1,136 @@
+package aes
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+)
+
+const (
+	gcmSIVNonceSize = 12
+	gcmSIVTagSize   = 16
+	// Plaintext and associated data can have at most 2^36 bytes
+	gcmSIVMaxSize = 1 << 36
+)
+
+type gcmSIV struct {
+	// Key-generating key
+	b      cipher.Block
+	keyLen int
+}
+
+// NewGCMSIV returns AEAD_AES_128_GCM_SIV or AEAD_AES_256_GCM_SIV from RFC
+// 8452, depending on the size of the key, which must be 16 or 32 bytes.
+// Reuse of a nonce reveals only whether messages were equal, but the
+// nonce still should be unique.
+func NewGCMSIV(key []byte) (cipher.AEAD, error) {
+	if len(key) != 16 && len(key) != 32 {
+		return nil, KeySizeError(len(key))
+	}
+	b, err := New(key)
+	if err != nil {
+		return nil, err
+	}
+	return &gcmSIV{b: b, keyLen: len(key)}, nil
+}
+
+func (g *gcmSIV) NonceSize() int { return gcmSIVNonceSize }
+func (g *gcmSIV) Overhead() int  { return gcmSIVTagSize }
+
+// Derives message-authentication and message-encryption keys for the
+// nonce
+func (g *gcmSIV) deriveKeys(nonce []byte) (*polyval, cipher.Block) {
+	var in, out [BlockSize]byte
+	var keys [16 + 32]byte
+	copy(in[4:], nonce)
+	n := 16 + g.keyLen
+	for i := 0; i < n/8; i++ {
+		binary.LittleEndian.PutUint32(in[:4], uint32(i))
+		g.b.Encrypt(out[:], in[:])
+		copy(keys[8*i:], out[:8])
+	}
+	enc, err := New(keys[16:n])
+	if err != nil {
+		panic(err)
+	}
+	mac := newPolyval(keys[:16])
+	for i := range keys {
+		keys[i] = 0
+	}
+	return mac, enc
+}
+
+// Computes tag of plaintext pt with associated data ad
+func (g *gcmSIV) tag(tag *[gcmSIVTagSize]byte, mac *polyval, enc cipher.Block, nonce, pt, ad []byte) {
+	mac.update(ad)
+	mac.update(pt)
+	mac.updateLengths(len(ad), len(pt))
+	mac.sum(tag)
+	subtle.XORBytes(tag[:], tag[:], nonce)
+	tag[15] &= 0x7f
+	enc.Encrypt(tag[:], tag[:])
+}
+
+// Increments the first 32 bits of ctr as little-endian integer
+func gcmSIVInc32(ctr *[BlockSize]byte) {
+	binary.LittleEndian.PutUint32(ctr[:4], binary.LittleEndian.Uint32(ctr[:4])+1)
+}
+
+// Encrypts src to dst in counter mode, with initial counter block derived
+// from the tag
+func (g *gcmSIV) xor(enc cipher.Block, tag *[gcmSIVTagSize]byte, dst, src []byte) {
+	ctr := *tag
+	ctr[15] |= 0x80
+	ctrXOR(enc, &ctr, gcmSIVInc32, dst, src)
+}
+
+func (g *gcmSIV) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != gcmSIVNonceSize {
+		panic("aes: incorrect nonce length given to GCM-SIV")
+	}
+	if uint64(len(plaintext)) > gcmSIVMaxSize || uint64(len(additionalData)) > gcmSIVMaxSize {
+		panic("aes: message too large for GCM-SIV")
+	}
+	ret, out := sliceForAppend(dst, len(plaintext)+gcmSIVTagSize)
+	if InexactOverlap(out, plaintext) {
+		panic("aes: invalid buffer overlap")
+	}
+
+	mac, enc := g.deriveKeys(nonce)
+	var tag [gcmSIVTagSize]byte
+	g.tag(&tag, mac, enc, nonce, plaintext, additionalData)
+	g.xor(enc, &tag, out, plaintext)
+	copy(out[len(plaintext):], tag[:])
+	return ret
+}
+
+func (g *gcmSIV) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != gcmSIVNonceSize {
+		panic("aes: incorrect nonce length given to GCM-SIV")
+	}
+	if len(ciphertext) < gcmSIVTagSize ||
+		uint64(len(ciphertext)) > gcmSIVMaxSize+gcmSIVTagSize ||
+		uint64(len(additionalData)) > gcmSIVMaxSize {
+		return nil, errOpen
+	}
+	var tag [gcmSIVTagSize]byte
+	ct := ciphertext[:len(ciphertext)-gcmSIVTagSize]
+	copy(tag[:], ciphertext[len(ct):])
+
+	ret, out := sliceForAppend(dst, len(ct))
+	if InexactOverlap(out, ciphertext) {
+		panic("aes: invalid buffer overlap")
+	}
+	// Plaintext is needed to compute the tag, it's wiped on failure
+	mac, enc := g.deriveKeys(nonce)
+	g.xor(enc, &tag, out, ct)
+	var expected [gcmSIVTagSize]byte
+	g.tag(&expected, mac, enc, nonce, out, additionalData)
+	if subtle.ConstantTimeCompare(expected[:], tag[:]) != 1 {
+		for i := range out {
+			out[i] = 0
+		}
+		return nil, errOpen
+	}
+	return ret, nil
+}
@@ -0,0 +1,376 @@
+package aes
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// Vectors of GCM are NIST vectors (gcmEncryptExtIV) copied from tests of
+// crypto/cipher, with 16 byte tags. Vectors of XTS were generated with
+// OpenSSL 3.0 and include data units which aren't multiple of BlockSize.
+// Vectors of AES-GCM-SIV and POLYVAL are from RFC 8452.
+
+func readJson(t *testing.T, fileName string, v interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("File %v can't be opened: %v", fileName, err)
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		t.Fatalf("File %v can't be parsed: %v", fileName, err)
+	}
+}
+
+func fromHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func randBytes(t testing.TB, n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// Hides multiBlock, so that modes process blocks one by one
+type singleBlock struct {
+	cipher.Block
+}
+
+// Returns all implementations of AES keyed with key
+func blockImpls(t *testing.T, key []byte) map[string]cipher.Block {
+	t.Helper()
+	generic := NewCipher()
+	if err := generic.SetKey(key); err != nil {
+		t.Fatal(err)
+	}
+	b, err := New(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]cipher.Block{
+		"generic": generic,
+		"New":     b,
+		"single":  singleBlock{b},
+	}
+}
+
+// Lengths covering partial blocks and batches
+var testLengths = []int{0, 1, 15, 16, 17, 127, 128, 129, 255, 256, 1000}
+
+func TestCTR(t *testing.T) {
+	// NIST SP 800-38A, F.5
+	iv := fromHex(t, "f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff")
+	pt := fromHex(t, "6bc1bee22e409f96e93d7e117393172aae2d8a571e03ac9c9eb76fac45af8e51"+
+		"30c81c46a35ce411e5fbc1191a0a52eff69f2445df4f9b17ad2b417be66c3710")
+	for _, v := range []struct{ key, ct string }{
+		{"2b7e151628aed2a6abf7158809cf4f3c",
+			"874d6191b620e3261bef6864990db6ce9806f66b7970fdff8617187bb9fffdff" +
+				"5ae4df3edbd5d35e5b4f09020db03eab1e031dda2fbe03d1792170a0f3009cee"},
+		{"8e73b0f7da0e6452c810f32b809079e562f8ead2522c6b7b",
+			"1abc932417521ca24f2b0459fe7e6e0b090339ec0aa6faefd5ccc2c6f4ce8e94" +
+				"1e36b26bd1ebc670d1bd1d665620abf74f78a7f6d29809585a97daec58c6b050"},
+		{"603deb1015ca71be2b73aef0857d77811f352c073b6108d72d9810a30914dff4",
+			"601ec313775789a5b7a7f504bbf3d228f443e3ca4d62b59aca84e990cacaf5c5" +
+				"2b0930daa23de94ce87017ba2d84988ddfc9c58db67aada613c2dd08457941a6"},
+	} {
+		for name, b := range blockImpls(t, fromHex(t, v.key)) {
+			out := make([]byte, len(pt))
+			NewCTR(b, iv).XORKeyStream(out, pt)
+			if hex.EncodeToString(out) != v.ct {
+				t.Errorf("%s: got %x, want %s", name, out, v.ct)
+			}
+		}
+	}
+
+	// Compare with crypto/cipher, also with counter overflowing 64 and
+	// 128 bits and with data split into chunks
+	for _, iv := range [][]byte{
+		randBytes(t, BlockSize),
+		fromHex(t, "0000000000000000fffffffffffffffe"),
+		fromHex(t, "fffffffffffffffffffffffffffffffd"),
+	} {
+		for name, b := range blockImpls(t, randBytes(t, 16)) {
+			pt := randBytes(t, 1000)
+			want := make([]byte, len(pt))
+			cipher.NewCTR(b, iv).XORKeyStream(want, pt)
+			for _, chunk := range []int{1, 15, 16, 100, 1000} {
+				got := make([]byte, len(pt))
+				s := NewCTR(b, iv)
+				for i := 0; i < len(pt); i += chunk {
+					end := i + chunk
+					if end > len(pt) {
+						end = len(pt)
+					}
+					s.XORKeyStream(got[i:end], pt[i:end])
+				}
+				if !bytes.Equal(got, want) {
+					t.Errorf("%s: iv %x, chunk %d: output differs from crypto/cipher", name, iv, chunk)
+				}
+			}
+		}
+	}
+}
+
+type gcmVector struct {
+	Key   string `json:"key"`
+	Nonce string `json:"nonce"`
+	Pt    string `json:"pt"`
+	Aad   string `json:"aad"`
+	Ct    string `json:"ct"`
+}
+
+// Checks that modified ciphertexts, associated data and nonces are
+// rejected
+func testAEADTamper(t *testing.T, name string, a cipher.AEAD, nonce, pt, ad []byte) {
+	t.Helper()
+	ct := a.Seal(nil, nonce, pt, ad)
+	for i := 0; i < len(ct); i += 7 {
+		ct[i] ^= 0x80
+		if _, err := a.Open(nil, nonce, ct, ad); err != errOpen {
+			t.Errorf("%s: modified byte %d accepted", name, i)
+		}
+		ct[i] ^= 0x80
+	}
+	if _, err := a.Open(nil, nonce, ct, append(ad, 0)); err != errOpen {
+		t.Errorf("%s: modified associated data accepted", name)
+	}
+	nonce = append([]byte(nil), nonce...)
+	nonce[0] ^= 1
+	if _, err := a.Open(nil, nonce, ct, ad); err != errOpen {
+		t.Errorf("%s: modified nonce accepted", name)
+	}
+	if _, err := a.Open(nil, nonce, ct[:a.Overhead()-1], ad); err != errOpen {
+		t.Errorf("%s: short ciphertext accepted", name)
+	}
+}
+
+// Checks in-place encryption and decryption
+func testAEADInPlace(t *testing.T, name string, a cipher.AEAD, nonce, pt, ad []byte) {
+	t.Helper()
+	want := a.Seal(nil, nonce, pt, ad)
+	buf := make([]byte, len(pt), len(pt)+a.Overhead())
+	copy(buf, pt)
+	ct := a.Seal(buf[:0], nonce, buf, ad)
+	if !bytes.Equal(ct, want) {
+		t.Errorf("%s: in-place Seal differs", name)
+	}
+	got, err := a.Open(ct[:0], nonce, ct, ad)
+	if err != nil || !bytes.Equal(got, pt) {
+		t.Errorf("%s: in-place Open failed: %v", name, err)
+	}
+}
+
+func TestGCM(t *testing.T) {
+	var vectors []gcmVector
+	readJson(t, "testdata/gcm_nist.json", &vectors)
+	for i, v := range vectors {
+		nonce, pt, ad := fromHex(t, v.Nonce), fromHex(t, v.Pt), fromHex(t, v.Aad)
+		for name, b := range blockImpls(t, fromHex(t, v.Key)) {
+			a, err := NewGCMWithNonceSize(b, len(nonce))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ct := a.Seal(nil, nonce, pt, ad)
+			if hex.EncodeToString(ct) != v.Ct {
+				t.Errorf("#%d %s: got %x, want %s", i, name, ct, v.Ct)
+				continue
+			}
+			got, err := a.Open(nil, nonce, ct, ad)
+			if err != nil || !bytes.Equal(got, pt) {
+				t.Errorf("#%d %s: Open failed: %v", i, name, err)
+			}
+		}
+	}
+
+	// Compare with crypto/cipher
+	for _, keySize := range []int{16, 24, 32} {
+		for name, b := range blockImpls(t, randBytes(t, keySize)) {
+			a, _ := NewGCM(b)
+			ref, _ := cipher.NewGCM(b)
+			for _, n := range testLengths {
+				nonce, pt, ad := randBytes(t, 12), randBytes(t, n), randBytes(t, n/3)
+				if !bytes.Equal(a.Seal(nil, nonce, pt, ad), ref.Seal(nil, nonce, pt, ad)) {
+					t.Errorf("%s: length %d: output differs from crypto/cipher", name, n)
+				}
+			}
+			testAEADTamper(t, name, a, randBytes(t, 12), randBytes(t, 100), randBytes(t, 10))
+			testAEADInPlace(t, name, a, randBytes(t, 12), randBytes(t, 100), randBytes(t, 10))
+		}
+	}
+
+	b, _ := New(make([]byte, 16))
+	if _, err := NewGCMWithNonceSize(b, 0); err != errNonceSize {
+		t.Errorf("expected errNonceSize, got %v", err)
+	}
+}
+
+func TestPolyval(t *testing.T) {
+	// RFC 8452, Appendix A
+	p := newPolyval(fromHex(t, "25629347589242761d31f826ba4b757b"))
+	p.update(fromHex(t, "4f4f95668c83dfb6401762bb2d01a262d1a24ddd2721d006bbe45f20d3c9f362"))
+	var out [BlockSize]byte
+	p.sum(&out)
+	if hex.EncodeToString(out[:]) != "f7a3b47b846119fae5b7866cf5e5b77e" {
+		t.Errorf("unexpected POLYVAL %x", out)
+	}
+}
+
+func TestGCMSIV(t *testing.T) {
+	// RFC 8452, Appendix C.1 and C.2
+	for _, v := range []struct{ key, nonce, ad, pt, ct string }{
+		{"01000000000000000000000000000000", "030000000000000000000000", "", "",
+			"dc20e2d83f25705bb49e439eca56de25"},
+		{"01000000000000000000000000000000", "030000000000000000000000", "", "0100000000000000",
+			"b5d839330ac7b786578782fff6013b815b287c22493a364c"},
+		{"01000000000000000000000000000000", "030000000000000000000000", "", "010000000000000000000000",
+			"7323ea61d05932260047d942a4978db357391a0bc4fdec8b0d106639"},
+		{"01000000000000000000000000000000", "030000000000000000000000", "", "01000000000000000000000000000000",
+			"743f7c8077ab25f8624e2e948579cf77303aaf90f6fe21199c6068577437a0c4"},
+		{"01000000000000000000000000000000", "030000000000000000000000", "01", "0200000000000000",
+			"1e6daba35669f4273b0a1a2560969cdf790d99759abd1508"},
+		{"0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "", "",
+			"07f5f4169bbf55a8400cd47ea6fd400f"},
+		{"0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "", "0100000000000000",
+			"c2ef328e5c71c83b843122130f7364b761e0b97427e3df28"},
+	} {
+		a, err := NewGCMSIV(fromHex(t, v.key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		nonce, pt, ad := fromHex(t, v.nonce), fromHex(t, v.pt), fromHex(t, v.ad)
+		ct := a.Seal(nil, nonce, pt, ad)
+		if hex.EncodeToString(ct) != v.ct {
+			t.Errorf("got %x, want %s", ct, v.ct)
+			continue
+		}
+		got, err := a.Open(nil, nonce, ct, ad)
+		if err != nil || !bytes.Equal(got, pt) {
+			t.Errorf("Open failed: %v", err)
+		}
+	}
+
+	for _, keySize := range []int{16, 32} {
+		a, err := NewGCMSIV(randBytes(t, keySize))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, n := range testLengths {
+			nonce, pt, ad := randBytes(t, 12), randBytes(t, n), randBytes(t, n/3)
+			got, err := a.Open(nil, nonce, a.Seal(nil, nonce, pt, ad), ad)
+			if err != nil || !bytes.Equal(got, pt) {
+				t.Errorf("length %d: round trip failed: %v", n, err)
+			}
+		}
+		testAEADTamper(t, "GCM-SIV", a, randBytes(t, 12), randBytes(t, 100), randBytes(t, 10))
+		testAEADInPlace(t, "GCM-SIV", a, randBytes(t, 12), randBytes(t, 100), randBytes(t, 10))
+	}
+	if _, err := NewGCMSIV(make([]byte, 24)); err == nil {
+		t.Error("expected error for 192-bit key")
+	}
+
+	// Counter wraps around 32 bits without carry
+	ctr := [BlockSize]byte{0xff, 0xff, 0xff, 0xff, 0xaa}
+	gcmSIVInc32(&ctr)
+	if ctr != [BlockSize]byte{4: 0xaa} {
+		t.Errorf("unexpected counter %x", ctr)
+	}
+}
+
+type xtsVector struct {
+	Key    string `json:"key"`
+	Sector uint64 `json:"sector"`
+	Pt     string `json:"pt"`
+	Ct     string `json:"ct"`
+}
+
+func TestXTS(t *testing.T) {
+	var vectors []xtsVector
+	readJson(t, "testdata/xts_openssl.json", &vectors)
+	for i, v := range vectors {
+		c, err := NewXTS(fromHex(t, v.Key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pt := fromHex(t, v.Pt)
+		ct := make([]byte, len(pt))
+		c.Encrypt(ct, pt, v.Sector)
+		if hex.EncodeToString(ct) != v.Ct {
+			t.Errorf("#%d: got %x, want %s", i, ct, v.Ct)
+		}
+		got := make([]byte, len(pt))
+		c.Decrypt(got, ct, v.Sector)
+		if !bytes.Equal(got, pt) {
+			t.Errorf("#%d: Decrypt failed", i)
+		}
+		// In place
+		c.Encrypt(got, got, v.Sector)
+		if !bytes.Equal(got, ct) {
+			t.Errorf("#%d: in-place Encrypt failed", i)
+		}
+		c.Decrypt(got, got, v.Sector)
+		if !bytes.Equal(got, pt) {
+			t.Errorf("#%d: in-place Decrypt failed", i)
+		}
+	}
+
+	for _, key := range [][]byte{make([]byte, 48), make([]byte, 32)} {
+		if _, err := NewXTS(key); err == nil {
+			t.Errorf("expected error for key %x", key)
+		}
+	}
+	c, _ := NewXTS(randBytes(t, 32))
+	mustPanic(t, "aes: XTS input shorter than block", func() { c.Encrypt(make([]byte, 15), make([]byte, 15), 0) })
+}
+
+func benchmarkAEAD(b *testing.B, a cipher.AEAD, size int) {
+	nonce, buf := make([]byte, a.NonceSize()), make([]byte, size, size+a.Overhead())
+	b.SetBytes(int64(size))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Seal(buf[:0], nonce, buf, nil)
+	}
+}
+
+func BenchmarkCTR(b *testing.B) {
+	c, _ := New(make([]byte, 16))
+	buf := make([]byte, 8192)
+	s := NewCTR(c, make([]byte, BlockSize))
+	b.SetBytes(int64(len(buf)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.XORKeyStream(buf, buf)
+	}
+}
+
+func BenchmarkGCM(b *testing.B) {
+	c, _ := New(make([]byte, 16))
+	a, _ := NewGCM(c)
+	benchmarkAEAD(b, a, 8192)
+}
+
+func BenchmarkGCMSIV(b *testing.B) {
+	a, _ := NewGCMSIV(make([]byte, 16))
+	benchmarkAEAD(b, a, 8192)
+}
+
+func BenchmarkXTS(b *testing.B) {
+	c, _ := NewXTS(append(make([]byte, 16), bytes.Repeat([]byte{1}, 16)...))
+	buf := make([]byte, 4096)
+	b.SetBytes(int64(len(buf)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Encrypt(buf, buf, uint64(i))
+	}
+}
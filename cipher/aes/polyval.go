@@ -0,0 +1,144 @@
+package aes
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// Element of GF(2^128) defined by x^128 + x^127 + x^126 + x^121 + 1, in
+// representation used by POLYVAL (RFC 8452). Bit i of lo is coefficient
+// of x^i, bit i of hi is coefficient of x^(64+i).
+//
+// Multiplication uses integer multiplications with holes (as in BearSSL)
+// instead of table lookups, so it runs in constant time. GHASH is
+// computed with POLYVAL, as described in Appendix A of RFC 8452.
+type fieldElement struct {
+	lo, hi uint64
+}
+
+// Multiplies x and y as polynomials over GF(2), keeping only the lower
+// 64 coefficients of the result. Every 4th bit of operands is used in each
+// of partial products, so carries never propagate into the bits kept.
+func bmul64(x, y uint64) uint64 {
+	x0 := x & 0x1111111111111111
+	x1 := x & 0x2222222222222222
+	x2 := x & 0x4444444444444444
+	x3 := x & 0x8888888888888888
+	y0 := y & 0x1111111111111111
+	y1 := y & 0x2222222222222222
+	y2 := y & 0x4444444444444444
+	y3 := y & 0x8888888888888888
+	z0 := (x0 * y0) ^ (x1 * y3) ^ (x2 * y2) ^ (x3 * y1)
+	z1 := (x0 * y1) ^ (x1 * y0) ^ (x2 * y3) ^ (x3 * y2)
+	z2 := (x0 * y2) ^ (x1 * y1) ^ (x2 * y0) ^ (x3 * y3)
+	z3 := (x0 * y3) ^ (x1 * y2) ^ (x2 * y1) ^ (x3 * y0)
+	z0 &= 0x1111111111111111
+	z1 &= 0x2222222222222222
+	z2 &= 0x4444444444444444
+	z3 &= 0x8888888888888888
+	return z0 | z1 | z2 | z3
+}
+
+// Carry-less multiplication of x and y, returns 128-bit product
+func clmul(x, y uint64) (hi, lo uint64) {
+	lo = bmul64(x, y)
+	hi = bits.Reverse64(bmul64(bits.Reverse64(x), bits.Reverse64(y))) >> 1
+	return hi, lo
+}
+
+// Returns x*y*x^-128
+func (x *fieldElement) dot(y *fieldElement) fieldElement {
+	// Karatsuba
+	h1, h0 := clmul(x.hi, y.hi)
+	l1, l0 := clmul(x.lo, y.lo)
+	m1, m0 := clmul(x.hi^x.lo, y.hi^y.lo)
+	m0 ^= l0 ^ h0
+	m1 ^= l1 ^ h1
+	w0, w1, w2, w3 := l0, l1^m0, h0^m1, h1
+
+	// Montgomery reduction. Adds w0*P and w1*P*x^64, which cancels w0 and
+	// w1, then divides by x^128.
+	w1 ^= (w0 << 63) ^ (w0 << 62) ^ (w0 << 57)
+	w2 ^= w0 ^ (w0 >> 1) ^ (w0 >> 2) ^ (w0 >> 7)
+	w2 ^= (w1 << 63) ^ (w1 << 62) ^ (w1 << 57)
+	w3 ^= w1 ^ (w1 >> 1) ^ (w1 >> 2) ^ (w1 >> 7)
+	return fieldElement{lo: w2, hi: w3}
+}
+
+// Returns x multiplied by x
+func (x *fieldElement) mulX() fieldElement {
+	carry := x.hi >> 63
+	return fieldElement{
+		lo: (x.lo << 1) ^ carry,
+		hi: (x.hi << 1) ^ (x.lo >> 63) ^ (-carry & 0xc200000000000000),
+	}
+}
+
+// POLYVAL from RFC 8452 or GHASH from SP 800-38D
+type polyval struct {
+	h   fieldElement
+	acc fieldElement
+	// GHASH uses reversed byte order
+	ghash bool
+}
+
+func newPolyval(h []byte) *polyval {
+	return &polyval{h: fieldElement{
+		lo: binary.LittleEndian.Uint64(h[:8]),
+		hi: binary.LittleEndian.Uint64(h[8:]),
+	}}
+}
+
+// Returns GHASH with key h. ByteReverse(h) multiplied by x is the
+// corresponding POLYVAL key.
+func newGHASH(h []byte) *polyval {
+	k := fieldElement{
+		lo: binary.BigEndian.Uint64(h[8:]),
+		hi: binary.BigEndian.Uint64(h[:8]),
+	}
+	return &polyval{h: k.mulX(), ghash: true}
+}
+
+// Absorbs b, which is padded with zeros to multiple of BlockSize
+func (p *polyval) update(b []byte) {
+	var block [BlockSize]byte
+	for len(b) > 0 {
+		n := copy(block[:], b)
+		for i := n; i < BlockSize; i++ {
+			block[i] = 0
+		}
+		b = b[n:]
+		if p.ghash {
+			p.acc.lo ^= binary.BigEndian.Uint64(block[8:])
+			p.acc.hi ^= binary.BigEndian.Uint64(block[:8])
+		} else {
+			p.acc.lo ^= binary.LittleEndian.Uint64(block[:8])
+			p.acc.hi ^= binary.LittleEndian.Uint64(block[8:])
+		}
+		p.acc = p.acc.dot(&p.h)
+	}
+}
+
+// Absorbs block of bit lengths of a and b, in byte order of the function
+func (p *polyval) updateLengths(a, b int) {
+	var block [BlockSize]byte
+	if p.ghash {
+		binary.BigEndian.PutUint64(block[:8], uint64(a)*8)
+		binary.BigEndian.PutUint64(block[8:], uint64(b)*8)
+	} else {
+		binary.LittleEndian.PutUint64(block[:8], uint64(a)*8)
+		binary.LittleEndian.PutUint64(block[8:], uint64(b)*8)
+	}
+	p.update(block[:])
+}
+
+// Writes the result to out
+func (p *polyval) sum(out *[BlockSize]byte) {
+	if p.ghash {
+		binary.BigEndian.PutUint64(out[:8], p.acc.hi)
+		binary.BigEndian.PutUint64(out[8:], p.acc.lo)
+	} else {
+		binary.LittleEndian.PutUint64(out[:8], p.acc.lo)
+		binary.LittleEndian.PutUint64(out[8:], p.acc.hi)
+	}
+}
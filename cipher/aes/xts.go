@@ -0,0 +1,155 @@
+package aes
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+var errXTSKey = errors.New("aes: halves of XTS key must differ")
+
+// XTS is AES in XTS mode (IEEE 1619, SP 800-38E), used for encryption of
+// storage sectors. It is not authenticated. Data units which aren't a
+// multiple of BlockSize are encrypted with ciphertext stealing.
+type XTS struct {
+	k1, k2 cipher.Block
+}
+
+// NewXTS returns XTS-AES-128 or XTS-AES-256 for key of size 32 or 64
+// bytes respectively. The key is concatenation of two AES keys, which
+// must differ.
+func NewXTS(key []byte) (*XTS, error) {
+	if len(key) != 32 && len(key) != 64 {
+		return nil, KeySizeError(len(key))
+	}
+	half := len(key) / 2
+	if subtle.ConstantTimeCompare(key[:half], key[half:]) == 1 {
+		return nil, errXTSKey
+	}
+	k1, err := New(key[:half])
+	if err != nil {
+		return nil, err
+	}
+	k2, err := New(key[half:])
+	if err != nil {
+		return nil, err
+	}
+	return &XTS{k1: k1, k2: k2}, nil
+}
+
+// Tweak is an element of GF(2^128), as two little-endian halves
+type xtsTweak struct {
+	lo, hi uint64
+}
+
+// Multiplies t by primitive element alpha
+func (t *xtsTweak) mul() {
+	carry := t.hi >> 63
+	t.hi = t.hi<<1 | t.lo>>63
+	t.lo = t.lo<<1 ^ (-carry & 0x87)
+}
+
+func (t *xtsTweak) xor(dst, src []byte) {
+	binary.LittleEndian.PutUint64(dst, binary.LittleEndian.Uint64(src)^t.lo)
+	binary.LittleEndian.PutUint64(dst[8:], binary.LittleEndian.Uint64(src[8:])^t.hi)
+}
+
+// Processes whole blocks of src with tweaks starting at t, which is
+// updated to the tweak following the last block
+func (c *XTS) blocks(crypt func(cipher.Block, []byte, []byte), t *xtsTweak, dst, src []byte) {
+	var buf [ctrBatch * BlockSize]byte
+	var tweaks [ctrBatch]xtsTweak
+	for len(src) > 0 {
+		n := len(buf)
+		if len(src) < n {
+			n = len(src)
+		}
+		for i := 0; i < n; i += BlockSize {
+			tweaks[i/BlockSize] = *t
+			t.xor(buf[i:], src[i:])
+			t.mul()
+		}
+		crypt(c.k1, buf[:n], buf[:n])
+		for i := 0; i < n; i += BlockSize {
+			tweaks[i/BlockSize].xor(dst[i:], buf[i:])
+		}
+		dst, src = dst[n:], src[n:]
+	}
+}
+
+// Returns the initial tweak for sector
+func (c *XTS) tweak(sector uint64) xtsTweak {
+	var b [BlockSize]byte
+	binary.LittleEndian.PutUint64(b[:], sector)
+	c.k2.Encrypt(b[:], b[:])
+	return xtsTweak{
+		lo: binary.LittleEndian.Uint64(b[:8]),
+		hi: binary.LittleEndian.Uint64(b[8:]),
+	}
+}
+
+func checkXTSBuffers(dst, src []byte) {
+	if len(src) < BlockSize {
+		panic("aes: XTS input shorter than block")
+	}
+	if len(dst) < len(src) {
+		panic("aes: output smaller than input")
+	}
+	if InexactOverlap(dst[:len(src)], src) {
+		panic("aes: invalid buffer overlap")
+	}
+}
+
+// Encrypt encrypts data unit src with sector number to dst. Length of src
+// must be at least BlockSize, dst must be at least as long as src.
+func (c *XTS) Encrypt(dst, src []byte, sector uint64) {
+	checkXTSBuffers(dst, src)
+	t := c.tweak(sector)
+	r := len(src) % BlockSize
+	full := len(src) - r
+	if r != 0 {
+		full -= BlockSize
+	}
+	c.blocks(encryptBlocks, &t, dst, src[:full])
+	if r == 0 {
+		return
+	}
+
+	// Ciphertext stealing. The last full block is encrypted, its prefix
+	// becomes the last partial block of ciphertext and the rest pads the
+	// last partial block of plaintext.
+	var cc, pp [BlockSize]byte
+	c.blocks(encryptBlocks, &t, cc[:], src[full:full+BlockSize])
+	copy(pp[:], src[full+BlockSize:])
+	copy(pp[r:], cc[r:])
+	copy(dst[full+BlockSize:len(src)], cc[:r])
+	c.blocks(encryptBlocks, &t, dst[full:], pp[:])
+}
+
+// Decrypt decrypts data unit src with sector number to dst. Length of src
+// must be at least BlockSize, dst must be at least as long as src.
+func (c *XTS) Decrypt(dst, src []byte, sector uint64) {
+	checkXTSBuffers(dst, src)
+	t := c.tweak(sector)
+	r := len(src) % BlockSize
+	full := len(src) - r
+	if r != 0 {
+		full -= BlockSize
+	}
+	c.blocks(decryptBlocks, &t, dst, src[:full])
+	if r == 0 {
+		return
+	}
+
+	// Ciphertext stealing. The last full block was encrypted with the
+	// tweak following the tweak of the preceding block.
+	var cc, pp [BlockSize]byte
+	last := t
+	t.mul()
+	c.blocks(decryptBlocks, &t, pp[:], src[full:full+BlockSize])
+	copy(cc[:], src[full+BlockSize:])
+	copy(cc[r:], pp[r:])
+	copy(dst[full+BlockSize:len(src)], pp[:r])
+	c.blocks(decryptBlocks, &last, dst[full:], cc[:])
+}
@@ -0,0 +1,368 @@
+// Command nobs-hash computes and checks message digests using hash
+// functions implemented in this repository.
+//
+// Output format is compatible with sha256sum(1), so files produced by
+// this tool can be verified with `nobs-hash --check` and vice versa.
+//
+// Usage:
+//
+//	nobs-hash [-a algorithm] [-l length] [-N name] [-S custom] [FILE]...
+//	nobs-hash --check [-a algorithm] [FILE]...
+//
+// With no FILE, or when FILE is -, standard input is read.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/henrydcase/nobs/hash/sha3"
+	"github.com/henrydcase/nobs/hash/sm3"
+)
+
+const progName = "nobs-hash"
+
+// digest is a common interface for fixed and variable output length
+// hash functions.
+type digest interface {
+	io.Writer
+	// Sum returns the digest of the data written so far
+	Sum() []byte
+}
+
+// fixedDigest adapts hash.Hash to the digest interface
+type fixedDigest struct {
+	hash.Hash
+}
+
+func (d fixedDigest) Sum() []byte { return d.Hash.Sum(nil) }
+
+// xofDigest adapts sha3.ShakeHash to the digest interface. Output length
+// is fixed at construction time.
+type xofDigest struct {
+	sha3.ShakeHash
+	outLen int
+}
+
+func (d xofDigest) Sum() []byte {
+	out := make([]byte, d.outLen)
+	d.ShakeHash.Read(out)
+	return out
+}
+
+// algorithm describes a hash function available from the command line
+type algorithm struct {
+	// Default output length in bytes
+	size int
+	// True if function is an XOF and output length can be chosen
+	xof bool
+	// True if function accepts N and S customization strings
+	custom bool
+	// Constructs new digest. Arguments are ignored if not used
+	// by the algorithm.
+	new func(outLen int, N, S []byte) digest
+}
+
+func fixed(f func() hash.Hash) func(int, []byte, []byte) digest {
+	return func(int, []byte, []byte) digest { return fixedDigest{f()} }
+}
+
+var algorithms = map[string]algorithm{
+	"sm3":        {size: sm3.Size, new: fixed(sm3.New)},
+	"sha3-224":   {size: 28, new: fixed(sha3.New224)},
+	"sha3-256":   {size: 32, new: fixed(sha3.New256)},
+	"sha3-384":   {size: 48, new: fixed(sha3.New384)},
+	"sha3-512":   {size: 64, new: fixed(sha3.New512)},
+	"keccak-256": {size: 32, new: fixed(sha3.NewLegacyKeccak256)},
+	"shake128": {size: 32, xof: true,
+		new: func(l int, _, _ []byte) digest { return xofDigest{sha3.NewShake128(), l} }},
+	"shake256": {size: 64, xof: true,
+		new: func(l int, _, _ []byte) digest { return xofDigest{sha3.NewShake256(), l} }},
+	"cshake128": {size: 32, xof: true, custom: true,
+		new: func(l int, N, S []byte) digest { return xofDigest{sha3.NewCShake128(N, S), l} }},
+	"cshake256": {size: 64, xof: true, custom: true,
+		new: func(l int, N, S []byte) digest { return xofDigest{sha3.NewCShake256(N, S), l} }},
+}
+
+func algorithmNames() string {
+	names := make([]string, 0, len(algorithms))
+	for k := range algorithms {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// config keeps settings provided on the command line
+type config struct {
+	alg    algorithm
+	outLen int
+	N, S   []byte
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// hashReader streams content of r through the configured hash function.
+// In case outLen is 0, default length of the algorithm is used.
+func (c *config) hashReader(r io.Reader, outLen int) ([]byte, error) {
+	if outLen == 0 {
+		outLen = c.alg.size
+	}
+	d := c.alg.new(outLen, c.N, c.S)
+	if _, err := io.Copy(d, r); err != nil {
+		return nil, err
+	}
+	return d.Sum(), nil
+}
+
+// hashFile computes digest of a file. "-" stands for standard input.
+func (c *config) hashFile(name string, outLen int) ([]byte, error) {
+	if name == "-" {
+		return c.hashReader(c.stdin, outLen)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return c.hashReader(f, outLen)
+}
+
+// escapeName escapes file name the same way as GNU coreutils do. Returns
+// true if name needed escaping, in which case output line must be
+// prefixed with a backslash.
+func escapeName(name string) (string, bool) {
+	if !strings.ContainsAny(name, "\\\n\r") {
+		return name, false
+	}
+	r := strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\r", "\\r")
+	return r.Replace(name), true
+}
+
+// unescapeName reverts escapeName. Returns error on unknown escape sequence.
+func unescapeName(name string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		if name[i] != '\\' {
+			b.WriteByte(name[i])
+			continue
+		}
+		i++
+		if i == len(name) {
+			return "", errors.New("unterminated escape sequence")
+		}
+		switch name[i] {
+		case '\\':
+			b.WriteByte('\\')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		default:
+			return "", errors.New("unknown escape sequence")
+		}
+	}
+	return b.String(), nil
+}
+
+// sum prints digests of all files. Returns false if any of the files
+// couldn't be read.
+func (c *config) sum(files []string) bool {
+	ok := true
+	for _, name := range files {
+		d, err := c.hashFile(name, c.outLen)
+		if err != nil {
+			fmt.Fprintf(c.stderr, "%s: %s\n", progName, err)
+			ok = false
+			continue
+		}
+		esc, escaped := escapeName(name)
+		if escaped {
+			fmt.Fprint(c.stdout, "\\")
+		}
+		fmt.Fprintf(c.stdout, "%s  %s\n", hex.EncodeToString(d), esc)
+	}
+	return ok
+}
+
+// parseCheckLine splits line of a checksum file into digest and file name.
+// Both "<digest>  <name>" (text) and "<digest> *<name>" (binary) forms are
+// accepted.
+func parseCheckLine(line string) (sum []byte, name string, err error) {
+	escaped := strings.HasPrefix(line, "\\")
+	if escaped {
+		line = line[1:]
+	}
+	i := strings.IndexByte(line, ' ')
+	if i <= 0 || i+2 > len(line) || (line[i+1] != ' ' && line[i+1] != '*') {
+		return nil, "", errors.New("improperly formatted checksum line")
+	}
+	sum, err = hex.DecodeString(line[:i])
+	if err != nil || len(sum) == 0 {
+		return nil, "", errors.New("improperly formatted checksum line")
+	}
+	name = line[i+2:]
+	if escaped {
+		name, err = unescapeName(name)
+	}
+	return sum, name, err
+}
+
+// checkStats counts problems found while checking digests
+type checkStats struct {
+	failed, unreadable, malformed int
+}
+
+// checkList verifies digests of the files listed in r
+func (c *config) checkList(r io.Reader, list string, st *checkStats) {
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSuffix(s.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		exp, name, err := parseCheckLine(line)
+		if err == nil && !c.alg.xof && len(exp) != c.alg.size {
+			err = errors.New("digest length doesn't match the algorithm")
+		}
+		if err != nil {
+			st.malformed++
+			continue
+		}
+
+		// XOF output length is taken from the checksum file unless
+		// it was provided explicitly.
+		outLen := c.outLen
+		if c.alg.xof && outLen == 0 {
+			outLen = len(exp)
+		}
+		got, err := c.hashFile(name, outLen)
+		if err != nil {
+			fmt.Fprintf(c.stderr, "%s: %s\n", progName, err)
+			fmt.Fprintf(c.stdout, "%s: FAILED open or read\n", name)
+			st.unreadable++
+			continue
+		}
+		if bytes.Equal(got, exp) {
+			fmt.Fprintf(c.stdout, "%s: OK\n", name)
+		} else {
+			fmt.Fprintf(c.stdout, "%s: FAILED\n", name)
+			st.failed++
+		}
+	}
+	if err := s.Err(); err != nil {
+		fmt.Fprintf(c.stderr, "%s: %s: %s\n", progName, list, err)
+		st.unreadable++
+	}
+}
+
+// check reads checksum files and verifies digests of the files listed
+// in them. Returns false if any verification failed.
+func (c *config) check(files []string) bool {
+	var st checkStats
+
+	for _, list := range files {
+		if list == "-" {
+			c.checkList(c.stdin, list, &st)
+			continue
+		}
+		f, err := os.Open(list)
+		if err != nil {
+			fmt.Fprintf(c.stderr, "%s: %s\n", progName, err)
+			st.unreadable++
+			continue
+		}
+		c.checkList(f, list, &st)
+		f.Close()
+	}
+
+	if st.malformed > 0 {
+		fmt.Fprintf(c.stderr, "%s: WARNING: %d line(s) improperly formatted\n", progName, st.malformed)
+	}
+	if st.unreadable > 0 {
+		fmt.Fprintf(c.stderr, "%s: WARNING: %d listed file(s) could not be read\n", progName, st.unreadable)
+	}
+	if st.failed > 0 {
+		fmt.Fprintf(c.stderr, "%s: WARNING: %d computed checksum(s) did NOT match\n", progName, st.failed)
+	}
+	return st == checkStats{}
+}
+
+// run executes the command and returns process exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var algName, N, S string
+	var outLen int
+	var check bool
+
+	fs := flag.NewFlagSet(progName, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&algName, "a", "sha3-256", "hash algorithm, one of: "+algorithmNames())
+	fs.StringVar(&algName, "algorithm", "sha3-256", "same as -a")
+	fs.IntVar(&outLen, "l", 0, "output length in bytes (SHAKE and cSHAKE only)")
+	fs.IntVar(&outLen, "length", 0, "same as -l")
+	fs.StringVar(&N, "N", "", "cSHAKE function name string")
+	fs.StringVar(&S, "S", "", "cSHAKE customization string")
+	fs.BoolVar(&check, "c", false, "read checksums from the FILEs and check them")
+	fs.BoolVar(&check, "check", false, "same as -c")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s [OPTION]... [FILE]...\n", progName)
+		fmt.Fprintf(stderr, "Print or check message digests. With no FILE, or when FILE is -, read standard input.\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	alg, ok := algorithms[strings.ToLower(algName)]
+	if !ok {
+		fmt.Fprintf(stderr, "%s: unknown algorithm %q\n", progName, algName)
+		return 2
+	}
+	if outLen < 0 || (outLen != 0 && !alg.xof) {
+		fmt.Fprintf(stderr, "%s: output length can't be used with %s\n", progName, algName)
+		return 2
+	}
+	if (N != "" || S != "") && !alg.custom {
+		fmt.Fprintf(stderr, "%s: customization strings can't be used with %s\n", progName, algName)
+		return 2
+	}
+
+	c := &config{
+		alg:    alg,
+		outLen: outLen,
+		N:      []byte(N),
+		S:      []byte(S),
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	if check {
+		ok = c.check(files)
+	} else {
+		ok = c.sum(files)
+	}
+	if !ok {
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
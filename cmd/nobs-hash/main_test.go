@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Digests of "abc". cSHAKE values come from NIST SP 800-185 examples
+// (sample #2 and #4), where input is 00010203.
+var vectors = []struct {
+	args []string
+	in   string
+	out  string
+}{
+	{[]string{"-a", "sm3"}, "abc",
+		"66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0"},
+	{[]string{"-a", "sha3-224"}, "abc",
+		"e642824c3f8cf24ad09234ee7d3c766fc9a3a5168d0c94ad73b46fdf"},
+	{[]string{}, "abc",
+		"3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532"},
+	{[]string{"-a", "sha3-384"}, "abc",
+		"ec01498288516fc926459f58e2c6ad8df9b473cb0fc08c2596da7cf0e49be4b298d88cea927ac7f539f1edf228376d25"},
+	{[]string{"-a", "SHA3-512"}, "abc",
+		"b751850b1a57168a5693cd924b6b096e08f621827444f70d884f5d0240d2712e10e116e9192af3c91a7ec57647e3934057340b4cf408d5a56592f8274eec53f0"},
+	{[]string{"-a", "keccak-256"}, "abc",
+		"4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+	{[]string{"-a", "shake128", "-l", "16"}, "abc",
+		"5881092dd818bf5cf8a3ddb793fbcba7"},
+	{[]string{"-a", "shake256", "--length", "32"}, "abc",
+		"483366601360a8771c6863080cc4114d8db44530f8f1e1ee4f94ea37e78b5739"},
+	{[]string{"-a", "cshake128", "-S", "Email Signature"}, "\x00\x01\x02\x03",
+		"c1c36925b6409a04f1b504fcbca9d82b4017277cb5ed2b2065fc1d3814d5aaf5"},
+	{[]string{"-a", "cshake256", "-S", "Email Signature"}, "\x00\x01\x02\x03",
+		"d008828e2b80ac9d2218ffee1d070c48b8e4c87bff32c9699d5b6896eee0edd1" +
+			"64020e2be0560858d9c00c037e34a96937c561a74c412bb4c746469527281c8c"},
+}
+
+func runCmd(args []string, stdin string) (code int, stdout, stderr string) {
+	var o, e bytes.Buffer
+	code = run(args, strings.NewReader(stdin), &o, &e)
+	return code, o.String(), e.String()
+}
+
+func TestStdinVectors(t *testing.T) {
+	for i, v := range vectors {
+		code, out, errOut := runCmd(v.args, v.in)
+		if code != 0 {
+			t.Errorf("[%d] exit code %d: %s", i, code, errOut)
+			continue
+		}
+		if exp := v.out + "  -\n"; out != exp {
+			t.Errorf("[%d] %v\n got: %q\n exp: %q", i, v.args, out, exp)
+		}
+	}
+}
+
+func TestStreamingLargeInput(t *testing.T) {
+	// Input longer than internal buffers, must give the same result
+	// as hashing in one go.
+	in := strings.Repeat("nobs", 100000)
+	_, out1, _ := runCmd([]string{"-a", "sm3"}, in)
+	_, out2, _ := runCmd([]string{"-a", "sm3", "-"}, in)
+	if out1 != out2 || len(out1) == 0 {
+		t.Errorf("%q != %q", out1, out2)
+	}
+}
+
+func TestCheckRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nobs-hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	names := []string{
+		filepath.Join(dir, "a.txt"),
+		filepath.Join(dir, "with space"),
+		filepath.Join(dir, "back\\slash"),
+	}
+	for i, n := range names {
+		if err := ioutil.WriteFile(n, bytes.Repeat([]byte{byte(i)}, 1000*i), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, alg := range []string{"sm3", "sha3-256", "shake128", "cshake256"} {
+		args := append([]string{"-a", alg, "-S", ""}, names...)
+		if alg == "shake128" {
+			args = append([]string{"-l", "100"}, args...)
+		}
+		code, sums, errOut := runCmd(args, "")
+		if code != 0 {
+			t.Fatalf("%s: exit code %d: %s", alg, code, errOut)
+		}
+
+		// XOF length is taken from the checksum file
+		code, out, errOut := runCmd([]string{"--check", "-a", alg}, sums)
+		if code != 0 {
+			t.Fatalf("%s: check failed: %s%s", alg, out, errOut)
+		}
+		if strings.Count(out, ": OK\n") != len(names) {
+			t.Errorf("%s: unexpected output %q", alg, out)
+		}
+
+		// Modify one of the digests
+		bad := []byte(sums)
+		idx := strings.IndexByte(sums, '\n') + 1
+		if bad[idx] == '\\' {
+			idx++
+		}
+		if bad[idx] == '0' {
+			bad[idx] = '1'
+		} else {
+			bad[idx] = '0'
+		}
+		code, out, errOut = runCmd([]string{"-c", "-a", alg}, string(bad))
+		if code != 1 {
+			t.Errorf("%s: expected failure, got %d", alg, code)
+		}
+		if strings.Count(out, ": FAILED\n") != 1 || !strings.Contains(errOut, "1 computed checksum(s) did NOT match") {
+			t.Errorf("%s: unexpected output %q %q", alg, out, errOut)
+		}
+	}
+}
+
+func TestCheckCompatibleFormat(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nobs-hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	name := filepath.Join(dir, "abc")
+	if err := ioutil.WriteFile(name, []byte("abc"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// binary mode marker and CRLF line ending as written by other tools
+	list := vectors[0].out + " *" + name + "\r\n" +
+		"not a checksum line\n"
+
+	code, out, errOut := runCmd([]string{"-c", "-a", "sm3"}, list)
+	if code != 1 {
+		t.Errorf("expected failure due to malformed line, got %d", code)
+	}
+	if out != name+": OK\n" || !strings.Contains(errOut, "1 line(s) improperly formatted") {
+		t.Errorf("unexpected output %q %q", out, errOut)
+	}
+}
+
+func TestInvalidArguments(t *testing.T) {
+	for _, args := range [][]string{
+		{"-a", "md5"},
+		{"-a", "sha3-256", "-l", "10"},
+		{"-a", "shake256", "-N", "name"},
+		{"-a", "shake256", "-l", "-1"},
+	} {
+		if code, _, _ := runCmd(args, ""); code != 2 {
+			t.Errorf("%v: expected usage error, got %d", args, code)
+		}
+	}
+	if code, _, _ := runCmd([]string{"/nonexistent/file"}, ""); code != 1 {
+		t.Errorf("expected failure for nonexisting file")
+	}
+}
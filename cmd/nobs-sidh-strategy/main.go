@@ -0,0 +1,262 @@
+// Command nobs-sidh-strategy computes optimal isogeny tree traversal
+// strategies for SIDH and emits them as Go source for a params package
+// (dh/sidh/p503, dh/sidh/p751).
+//
+// Strategies depend on relative cost of point multiplication and of
+// isogeny evaluation. By default both are measured on the current machine,
+// they can also be given explicitly. Before printing, generated strategies
+// are checked by computing SIDH shared secret with them and comparing it
+// to the one computed by dh/sidh with the shipped strategies.
+//
+// Shipped strategies are kept in strategy.go of the params packages,
+// generated with go generate from costs given in its go:generate
+// directive.
+//
+// Usage:
+//
+//	nobs-sidh-strategy [-p p503|p751] [-o FILE] [-mulA N -evalA N -mulB N -evalB N]
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/henrydcase/nobs/dh/sidh"
+	"github.com/henrydcase/nobs/dh/sidh/isogeny"
+	"github.com/henrydcase/nobs/utils/security"
+)
+
+const progName = "nobs-sidh-strategy"
+
+var ids = map[string]uint8{
+	"p503": sidh.FP_503,
+	"p751": sidh.FP_751,
+}
+
+// costs of a single step in the isogeny tree, in nanoseconds or any
+// other unit
+type costs struct {
+	mul, eval float64
+}
+
+// ns returns average time of f in nanoseconds, f is run repeatedly for
+// at least 100ms
+func ns(f func()) float64 {
+	var n int64
+	start := time.Now()
+	for ; time.Since(start) < 100*time.Millisecond; n++ {
+		f()
+	}
+	return float64(time.Since(start).Nanoseconds()) / float64(n)
+}
+
+// measure benchmarks operations used when traversing isogeny tree. For
+// A these are multiplication by 4 and evaluation of 4-isogeny, for B
+// multiplication by 3 and evaluation of 3-isogeny.
+func measure(p *isogeny.Params) (a, b costs) {
+	PA, PB := p.NewPoint(&p.PA), p.NewPoint(&p.PB)
+	KA := p.Pow2k(&p.E0, &PA, uint32(p.E2-2))
+	KB := p.Pow3k(&p.E0, &PB, uint32(p.E3-1))
+	phiA, _ := p.NewIsogeny(&KA, 4)
+	phiB, _ := p.NewIsogeny(&KB, 3)
+
+	a.mul = ns(func() { p.Pow2k(&p.E0, &PA, 2) })
+	a.eval = ns(func() { phiA.Evaluate(&PB) })
+	b.mul = ns(func() { p.Pow3k(&p.E0, &PB, 1) })
+	b.eval = ns(func() { phiB.Evaluate(&PA) })
+	return a, b
+}
+
+// sharedSecret computes SIDH shared secret with given strategy. pub is
+// exported public key of the other party.
+func sharedSecret(p *isogeny.Params, scalar, pub []byte, isA bool, strategy []uint32) ([]byte, error) {
+	var x [3]isogeny.Fp2
+	var err error
+	var degree uint = 4
+	var nbits = p.SecretBitLenA
+
+	if !isA {
+		degree, nbits = 3, p.SecretBitLenB
+	}
+	for i := range x {
+		x[i], err = p.FromBytes(pub[i*p.Size() : (i+1)*p.Size()])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c := p.RecoverCurve(&x[0], &x[1], &x[2])
+	xP, xQ, xR := p.NewPoint(&x[0]), p.NewPoint(&x[1]), p.NewPoint(&x[2])
+	K := p.ScalarMul3Pt(&c, &xP, &xQ, &xR, nbits, scalar)
+	c, err = p.Traverse(&c, &K, degree, strategy, nil)
+	if err != nil {
+		return nil, err
+	}
+	return p.Jinvariant(&c), nil
+}
+
+// verify checks that strategies produce the same shared secret as dh/sidh
+func verify(id uint8, p *isogeny.Params, strategyA, strategyB []uint32) error {
+	// SIDH is broken, but here it only serves as a reference for testing
+	security.AllowInsecure(true)
+	prvA := sidh.NewPrivateKey(id, sidh.KeyVariant_SIDH_A)
+	prvB := sidh.NewPrivateKey(id, sidh.KeyVariant_SIDH_B)
+	if err := prvA.Generate(rand.Reader); err != nil {
+		return err
+	}
+	if err := prvB.Generate(rand.Reader); err != nil {
+		return err
+	}
+	pubA := prvA.GeneratePublicKey()
+	pubB := prvB.GeneratePublicKey()
+
+	exp, err := sidh.DeriveSecret(prvA, pubB)
+	if err != nil {
+		return err
+	}
+	ssA, err := sharedSecret(p, prvA.Export(), pubB.Export(), true, strategyA)
+	if err != nil {
+		return err
+	}
+	ssB, err := sharedSecret(p, prvB.Export(), pubA.Export(), false, strategyB)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(ssA, exp) || !bytes.Equal(ssB, exp) {
+		return errors.New("shared secret doesn't match")
+	}
+	return nil
+}
+
+// writeStrategy prints strategy in the format used by params packages
+func writeStrategy(w io.Writer, comment, name, size string, s []uint32) {
+	fmt.Fprintf(w, "\n// %s\n", comment)
+	fmt.Fprintf(w, "var %s = [%s]uint32{\n\t", name, size)
+	for i, v := range s {
+		if i != 0 && i%16 == 0 {
+			fmt.Fprint(w, "\n\t")
+		} else if i != 0 {
+			fmt.Fprint(w, " ")
+		}
+		fmt.Fprintf(w, "0x%02X", v)
+		if i != len(s)-1 {
+			fmt.Fprint(w, ",")
+		}
+	}
+	fmt.Fprint(w, "}\n")
+}
+
+// run executes the command and returns process exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	var name, out string
+	var a, b costs
+	var noVerify bool
+
+	fs := flag.NewFlagSet(progName, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&name, "p", "p503", "parameter set, p503 or p751")
+	fs.StringVar(&out, "o", "", "output file (default standard output)")
+	fs.Float64Var(&a.mul, "mulA", 0, "cost of multiplication by 4 (0 to measure)")
+	fs.Float64Var(&a.eval, "evalA", 0, "cost of 4-isogeny evaluation (0 to measure)")
+	fs.Float64Var(&b.mul, "mulB", 0, "cost of multiplication by 3 (0 to measure)")
+	fs.Float64Var(&b.eval, "evalB", 0, "cost of 3-isogeny evaluation (0 to measure)")
+	fs.BoolVar(&noVerify, "noverify", false, "don't check generated strategies")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s [OPTION]...\n", progName)
+		fmt.Fprintf(stderr, "Compute optimal SIDH isogeny strategies and print them as Go source.\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	name = strings.ToLower(name)
+	id, ok := ids[name]
+	if !ok {
+		fmt.Fprintf(stderr, "%s: unknown parameter set %q\n", progName, name)
+		return 2
+	}
+	for _, c := range []float64{a.mul, a.eval, b.mul, b.eval} {
+		if c < 0 {
+			fmt.Fprintf(stderr, "%s: cost can't be negative\n", progName)
+			return 2
+		}
+	}
+
+	p := isogeny.P503()
+	if id == sidh.FP_751 {
+		p = isogeny.P751()
+	}
+	if a.mul == 0 || a.eval == 0 || b.mul == 0 || b.eval == 0 {
+		ma, mb := measure(p)
+		if a.mul == 0 {
+			a.mul = ma.mul
+		}
+		if a.eval == 0 {
+			a.eval = ma.eval
+		}
+		if b.mul == 0 {
+			b.mul = mb.mul
+		}
+		if b.eval == 0 {
+			b.eval = mb.eval
+		}
+	}
+
+	strategyA := isogeny.OptimalStrategy(len(p.StrategyA)+1, a.mul, a.eval)
+	strategyB := isogeny.OptimalStrategy(len(p.StrategyB)+1, b.mul, b.eval)
+	if !noVerify {
+		if err := verify(id, p, strategyA, strategyB); err != nil {
+			fmt.Fprintf(stderr, "%s: verification failed: %s\n", progName, err)
+			return 1
+		}
+	}
+
+	// Compare with shipped strategies
+	for _, v := range []struct {
+		side      string
+		c         costs
+		new, curr []uint32
+	}{{"A", a, strategyA, p.StrategyA}, {"B", b, strategyB, p.StrategyB}} {
+		cn, _ := isogeny.StrategyCost(v.new, v.c.mul, v.c.eval)
+		cc, _ := isogeny.StrategyCost(v.curr, v.c.mul, v.c.eval)
+		fmt.Fprintf(stderr, "%s: %s: mul=%.1f eval=%.1f, cost %.0f (shipped %.0f)\n",
+			progName, v.side, v.c.mul, v.c.eval, cn, cc)
+	}
+
+	var buf bytes.Buffer
+	prefix := strings.ToUpper(name)
+	fmt.Fprintf(&buf, "// Code generated by %s. DO NOT EDIT.\n", progName)
+	fmt.Fprintf(&buf, "// Costs: mulA=%.1f evalA=%.1f mulB=%.1f evalB=%.1f\n\n", a.mul, a.eval, b.mul, b.eval)
+	fmt.Fprintf(&buf, "package %s\n", name)
+	writeStrategy(&buf, "2-torsion group computation strategy",
+		prefix+"_AliceIsogenyStrategy", "strategySizeA", strategyA)
+	writeStrategy(&buf, "3-torsion group computation strategy",
+		prefix+"_BobIsogenyStrategy", "strategySizeB", strategyB)
+
+	if out == "" {
+		stdout.Write(buf.Bytes())
+		return 0
+	}
+	if err := ioutil.WriteFile(out, buf.Bytes(), 0644); err != nil {
+		fmt.Fprintf(stderr, "%s: %s\n", progName, err)
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
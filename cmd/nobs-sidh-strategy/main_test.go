@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// Output for arguments from go:generate directive of params package must
+// be the same as its strategy.go
+func TestShipped(t *testing.T) {
+	re := regexp.MustCompile(`//go:generate go run \S+/` + progName + ` (.*) -o strategy.go\n`)
+	for _, pkg := range []string{"p503", "p751"} {
+		dir := filepath.Join("..", "..", "dh", "sidh", pkg)
+		consts, err := ioutil.ReadFile(filepath.Join(dir, "consts.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := re.FindSubmatch(consts)
+		if m == nil {
+			t.Fatalf("%s: go:generate directive not found", pkg)
+		}
+		args := strings.Fields(string(m[1]))
+
+		var stdout, stderr bytes.Buffer
+		if code := run(args, &stdout, &stderr); code != 0 {
+			t.Fatalf("%v: exit code %d: %s", args, code, stderr.String())
+		}
+		exp, err := ioutil.ReadFile(filepath.Join(dir, "strategy.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(stdout.Bytes(), exp) {
+			t.Errorf("%s: strategy.go differs from the output, run go generate", pkg)
+		}
+	}
+}
+
+func TestOutputFile(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	out := filepath.Join(t.TempDir(), "strategy.go")
+	args := []string{"-noverify", "-o", out, "-mulA", "3", "-evalA", "2", "-mulB", "3", "-evalB", "1"}
+	if code := run(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr.String())
+	}
+	b, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(b, []byte("// Code generated by nobs-sidh-strategy. DO NOT EDIT.")) ||
+		!bytes.Contains(b, []byte("\npackage p503\n")) {
+		t.Errorf("unexpected output:\n%s", b)
+	}
+	if stdout.Len() != 0 {
+		t.Error("unexpected output on stdout")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"-p", "p434"},
+		{"-mulA", "-1"},
+		{"-x"},
+		{"extra"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, &stdout, &stderr); code != 2 {
+			t.Errorf("%v: exit code %d", args, code)
+		}
+	}
+}
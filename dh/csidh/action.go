@@ -0,0 +1,250 @@
+package csidh
+
+import (
+	"io"
+)
+
+// Sets v to random element of F_p. Randomness isn't secret, it's used
+// only for sampling points.
+func randFp(v *fp511Element, rand io.Reader) error {
+	var buf [fp511Bytelen]byte
+	var x uint512
+	for {
+		if _, err := io.ReadFull(rand, buf[:]); err != nil {
+			return err
+		}
+		x = uint512{}
+		for i, b := range buf {
+			x[i/8] |= uint64(b) << (8 * uint(i%8))
+		}
+		x[len(x)-1] &= (1 << 63) - 1
+		if x.less((*uint512)(&p511)) {
+			*v = fp511Element(x)
+			return nil
+		}
+	}
+}
+
+// Samples random points P[0] on the curve y^2 = x^3 + Ax^2 + x and P[1]
+// on its quadratic twist.
+func randPoints(P *[2]point, a *fp511Element, rand io.Reader) error {
+	var rhs fp511Element
+	var found [2]bool
+	for !found[0] || !found[1] {
+		var x fp511Element
+		if err := randFp(&x, rand); err != nil {
+			return err
+		}
+		montRhs(&rhs, a, &x)
+		switch legendre(&rhs) {
+		case 1:
+			P[0], found[0] = point{x: x, z: fp511One}, true
+		case -1:
+			P[1], found[1] = point{x: x, z: fp511One}, true
+		}
+	}
+	return nil
+}
+
+// Sets (A:C) to (A/C:1)
+func normalize(A *coeff) {
+	fp511Inv(&A.c, &A.c)
+	fp511MulRdc(&A.a, &A.a, &A.c)
+	A.c = fp511One
+}
+
+// orderCheck implements divide-and-conquer version of the supersingularity
+// test (algorithm 3 from the CSIDH paper). P must be a point with order
+// dividing product of primes[lo:hi]. For each prime l_i it checks whether
+// l_i divides order of P and accumulates such primes in order. First
+// returned value indicates that the result is known, the second one
+// whether the curve is supersingular.
+func orderCheck(P *point, A *coeff, lo, hi int, order *uint512) (bool, bool) {
+	if hi-lo == 1 {
+		if isZero(&P.z) {
+			return false, false
+		}
+		xMul64(P, P, A, primes[lo])
+		if !isZero(&P.z) {
+			// order of P doesn't divide p+1, curve is ordinary
+			return true, false
+		}
+		order.mul64(order, primes[lo])
+		if fourSqrtP.less(order) {
+			// only supersingular curves have points of such order
+			return true, true
+		}
+		return false, false
+	}
+
+	var Q point
+	var mulL, mulR = uint512{1}, uint512{1}
+	mid := lo + (hi-lo+1)/2
+	for i := lo; i < mid; i++ {
+		mulR.mul64(&mulR, primes[i])
+	}
+	for i := mid; i < hi; i++ {
+		mulL.mul64(&mulL, primes[i])
+	}
+	xMul(&Q, P, A, &mulR)
+	xMul(P, P, A, &mulL)
+
+	if done, ok := orderCheck(&Q, A, mid, hi, order); done {
+		return done, ok
+	}
+	return orderCheck(P, A, lo, mid, order)
+}
+
+// Applies group action of the private key to the curve y^2 = x^3 + ax^2 + x.
+// Result is stored in a.
+func (prv *PrivateKey) groupAction(a *fp511Element, rand io.Reader) error {
+	if prv.keyVariant == KeyVariant_ConstantTime {
+		return prv.groupActionCT(a, rand)
+	}
+	return prv.groupActionFast(a, rand)
+}
+
+// Algorithm 2 from the CSIDH paper. In each round a random point is
+// sampled, depending on whether it lies on the curve or on the twist,
+// isogenies in positive or negative direction are computed. Execution
+// time depends on the private key.
+func (prv *PrivateKey) groupActionFast(a *fp511Element, rand io.Reader) error {
+	var e = prv.e
+	var idx [primeCount]int
+	A := coeff{a: *a, c: fp511One}
+
+	for {
+		var P point
+		var rhs fp511Element
+		var k = uint512{4}
+		var n int
+
+		if e == [primeCount]int8{} {
+			break
+		}
+		if err := randFp(&P.x, rand); err != nil {
+			return err
+		}
+		P.z = fp511One
+		montRhs(&rhs, &A.a, &P.x)
+		s := int8(legendre(&rhs))
+		if s == 0 {
+			continue
+		}
+
+		// Primes for which isogeny is computed in this round
+		for i := range e {
+			if e[i] != 0 && (e[i] > 0) == (s > 0) {
+				idx[n] = i
+				n++
+			} else {
+				k.mul64(&k, primes[i])
+			}
+		}
+		if n == 0 {
+			continue
+		}
+
+		xMul(&P, &P, &A, &k)
+		for m := n - 1; m >= 0; m-- {
+			var K point
+			var cof = uint512{1}
+			for j := 0; j < m; j++ {
+				cof.mul64(&cof, primes[idx[j]])
+			}
+			xMul(&K, &P, &A, &cof)
+			if !isZero(&K.z) {
+				pts := [1]point{P}
+				xIso(&A, &K, primes[idx[m]], pts[:])
+				P = pts[0]
+				e[idx[m]] -= s
+			}
+		}
+		normalize(&A)
+	}
+	*a = A.a
+	return nil
+}
+
+// Constant time group action. Each round samples a point on the curve and
+// a point on the twist and for each prime computes either a real isogeny
+// in direction given by the sign of the exponent, or a dummy one, which
+// costs the same. Each prime gets exactly expMax steps, so timing depends
+// only on randomly sampled points.
+//
+// Based on "A Faster Constant-Time Algorithm of CSIDH keeping Two Points"
+// by H. Onuki, Y. Aikawa, T. Yamazaki and T. Takagi (ia.cr/2019/353).
+func (prv *PrivateKey) groupActionCT(a *fp511Element, rand io.Reader) error {
+	// r[i] is number of remaining real isogenies, s[i] is 1 if exponent
+	// is negative. cnt[i] is number of remaining steps, which is public.
+	var r, s [primeCount]uint8
+	var cnt [primeCount]int
+	for i, v := range prv.e {
+		m := uint8(v >> 7)
+		s[i] = m & 1
+		r[i] = (uint8(v) ^ m) - m
+		cnt[i] = expMax
+	}
+	A := coeff{a: *a, c: fp511One}
+
+	for {
+		var P [2]point
+		var k = uint512{4}
+		var active bool
+
+		for i := range cnt {
+			if cnt[i] == 0 {
+				k.mul64(&k, primes[i])
+			} else {
+				active = true
+			}
+		}
+		if !active {
+			break
+		}
+
+		if err := randPoints(&P, &A.a, rand); err != nil {
+			return err
+		}
+		xMul(&P[0], &P[0], &A, &k)
+		xMul(&P[1], &P[1], &A, &k)
+
+		for i := primeCount - 1; i >= 0; i-- {
+			if cnt[i] == 0 {
+				continue
+			}
+			var K point
+			var cof = uint512{1}
+			for j := 0; j < i; j++ {
+				if cnt[j] != 0 {
+					cof.mul64(&cof, primes[j])
+				}
+			}
+
+			// P[0] is the point in the direction of the isogeny
+			cswapPoint(&P[0], &P[1], s[i])
+			xMul(&K, &P[0], &A, &cof)
+			if !isZero(&K.z) {
+				var dummy point
+				real := (r[i] | -r[i]) >> 7
+				B, img := A, P
+
+				// Real step maps both points, dummy step
+				// removes l_i from order of P[0].
+				xIso(&B, &K, primes[i], img[:])
+				xMul64(&dummy, &P[0], &A, primes[i])
+				cswapCoeff(&A, &B, real)
+				cswapPoint(&P[0], &img[0], real)
+				cswapPoint(&P[1], &img[1], real)
+				cswapPoint(&P[0], &dummy, 1^real)
+				r[i] -= real
+				cnt[i]--
+			}
+			xMul64(&P[1], &P[1], &A, primes[i])
+			cswapPoint(&P[0], &P[1], s[i])
+		}
+		normalize(&A)
+	}
+	*a = A.a
+	return nil
+}
@@ -0,0 +1,48 @@
+package csidh
+
+const (
+	// Number of small odd primes l_i, such that p = 4*l_1*...*l_n - 1
+	primeCount = 74
+	// Exponents of the private key are from [-expMax, expMax]
+	expMax = 5
+	// Size of the private key in bytes, two exponents per byte
+	PrivateKeySize = (primeCount + 1) / 2
+	// Size of the public key in bytes
+	PublicKeySize = fp511Bytelen
+	// Size of the shared secret in bytes
+	SharedSecretSize = fp511Bytelen
+)
+
+// Small odd primes l_i, 3..373 and 587
+var primes = [primeCount]uint64{
+	3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67, 71,
+	73, 79, 83, 89, 97, 101, 103, 107, 109, 113, 127, 131, 137, 139, 149, 151,
+	157, 163, 167, 173, 179, 181, 191, 193, 197, 199, 211, 223, 227, 229, 233,
+	239, 241, 251, 257, 263, 269, 271, 277, 281, 283, 293, 307, 311, 313, 317,
+	331, 337, 347, 349, 353, 359, 367, 373, 587,
+}
+
+// 2 in Montgomery domain
+var fp511Two = fp511Element{
+	0x767762E5FD1E1599, 0x33C5743A49A0B6F6, 0x68FC0C0364C77443, 0xB9AA1E24F83F56DB,
+	0x3914101F20520EFB, 0x7B1ED6D95B1542B4, 0x114A8BE928C8828A, 0x03793732BBB24F40,
+}
+
+// -2 in Montgomery domain
+var fp511TwoNeg = fp511Element{
+	0xA50A561F36A8B2E2, 0x8EACA7BA0E0BF13E, 0xE86B24C8BA43DAE2, 0xEE00A8A06FB3FE2B,
+	0x21E7ECA772D0BAD1, 0x390E316192B3498E, 0xEB4024E83575C9C0, 0x623B575CB85D3A7F,
+}
+
+// (p-1)/2, exponent used for computing Legendre symbol
+var p511Minus1Over2 = [fp511NumWords]uint64{
+	0x8DC0DC8299E3643D, 0xE1390DFA2BD6541A, 0xA8B398660F85A792, 0xD3D56362B3F9AA83,
+	0x2D7DFE63499164E6, 0x5A16841D76E44621, 0xFE455868AF1F2625, 0x32DA4747BA07C4DF,
+}
+
+// floor(4*sqrt(p)). Order of a point bigger than that proves that the
+// curve is supersingular.
+var fourSqrtP = uint512{
+	0x17895E71E1A20B3F, 0x38D0CD95F8636A56, 0x142B9541E59682CD, 0x856F1399D91D6592,
+	0x0000000000000002,
+}
@@ -0,0 +1,214 @@
+package csidh
+
+import (
+	"errors"
+	"io"
+
+	"github.com/henrydcase/nobs/utils/security"
+)
+
+// Selects implementation of the group action used by a private key
+type KeyVariant uint
+
+const (
+	// Algorithm 2 from the CSIDH paper. Execution time depends on the
+	// private key, use only with ephemeral keys.
+	KeyVariant_Fast KeyVariant = iota
+	// Computes the same number of isogenies of each degree regardless of
+	// the private key.
+	KeyVariant_ConstantTime
+)
+
+// Meta returns security properties of CSIDH-512. Level is the one claimed
+// in the CSIDH paper, later analyses of quantum attacks estimate it lower.
+func Meta() security.Metadata {
+	return security.Metadata{
+		Name:      "CSIDH-512",
+		Level:     1,
+		Status:    security.Experimental,
+		Reference: "https://ia.cr/2018/383",
+	}
+}
+
+// Defines operations on private key
+type PrivateKey struct {
+	// Exponents e_i from [-expMax, expMax], one for each prime l_i
+	e [primeCount]int8
+	// Implementation of the group action
+	keyVariant KeyVariant
+}
+
+// Defines operations on public key
+type PublicKey struct {
+	// Montgomery coefficient A of the curve y^2 = x^3 + Ax^2 + x, in
+	// Montgomery domain
+	a fp511Element
+}
+
+// NewPrivateKey initializes private key which will use given variant of
+// the group action.
+func NewPrivateKey(v KeyVariant) *PrivateKey {
+	return &PrivateKey{keyVariant: v}
+}
+
+// NewPublicKey initializes public key.
+func NewPublicKey() *PublicKey {
+	return &PublicKey{}
+}
+
+// Accessor to key variant
+func (prv *PrivateKey) Variant() KeyVariant {
+	return prv.keyVariant
+}
+
+// Size returns size of the private key in bytes
+func (prv *PrivateKey) Size() int {
+	return PrivateKeySize
+}
+
+// Size returns size of the public key in bytes
+func (pub *PublicKey) Size() int {
+	return PublicKeySize
+}
+
+// Generates random private key. Each exponent is chosen uniformly from
+// [-5, 5]. Returns error in case user provided RNG fails.
+func (prv *PrivateKey) Generate(rand io.Reader) error {
+	var buf [64]byte
+	for i := 0; i < primeCount; {
+		if _, err := io.ReadFull(rand, buf[:]); err != nil {
+			return err
+		}
+		for _, b := range buf {
+			// rejection sampling from [0, 2*expMax]
+			if v := int8(b & 0xF); v <= 2*expMax && i < primeCount {
+				prv.e[i] = v - expMax
+				i++
+			}
+		}
+	}
+	return nil
+}
+
+// Exports private key. Byte j stores exponent e_2j in upper and e_2j+1 in
+// lower 4 bits, both as two's complement.
+func (prv *PrivateKey) Export() []byte {
+	out := make([]byte, PrivateKeySize)
+	for i, v := range prv.e {
+		out[i/2] |= byte(v&0xF) << (4 * uint(1-i%2))
+	}
+	return out
+}
+
+// Import clears content of the private key and imports key from the octet
+// string. Returns error in case of wrong size or if any exponent is out of
+// range.
+func (prv *PrivateKey) Import(input []byte) error {
+	var e [primeCount]int8
+	if len(input) != PrivateKeySize {
+		return errors.New("csidh: wrong size of the private key")
+	}
+	for i := range e {
+		// arithmetic shift extends the sign
+		e[i] = int8(input[i/2]<<(4*uint(i%2))) >> 4
+		if e[i] > expMax || e[i] < -expMax {
+			return errors.New("csidh: private key out of range")
+		}
+	}
+	prv.e = e
+	return nil
+}
+
+// Exports public key as 64-byte little-endian integer.
+func (pub *PublicKey) Export() []byte {
+	out := make([]byte, PublicKeySize)
+	for i := range out {
+		out[i] = byte(pub.a[i/8] >> (8 * uint(i%8)))
+	}
+	return out
+}
+
+// Import imports public key from the octet string. Returns error in case
+// of wrong size. Doesn't perform any validation, see Validate.
+func (pub *PublicKey) Import(input []byte) error {
+	var a fp511Element
+	if len(input) != PublicKeySize {
+		return errors.New("csidh: wrong size of the public key")
+	}
+	for i, v := range input {
+		a[i/8] |= uint64(v) << (8 * uint(i%8))
+	}
+	pub.a = a
+	return nil
+}
+
+// Generates public key corresponding to the private key, that is
+// applies the group action to the curve y^2 = x^3 + x. Randomness is
+// used for sampling points and doesn't need to be secret. Returns error
+// in case user provided RNG fails.
+func (prv *PrivateKey) GeneratePublicKey(rand io.Reader) (*PublicKey, error) {
+	pub := NewPublicKey()
+	if err := prv.groupAction(&pub.a, rand); err != nil {
+		return nil, err
+	}
+	return pub, nil
+}
+
+// Validate returns nil if the public key represents a supersingular
+// curve, i.e. is a valid CSIDH public key. Randomness is used for
+// sampling points. Not constant time, operates on public data only.
+func (pub *PublicKey) Validate(rand io.Reader) error {
+	var A24 coeff
+	var bits uint512
+
+	// Must be fully reduced
+	copy(bits[:], pub.a[:])
+	if !bits.less((*uint512)(&p511)) {
+		return errors.New("csidh: public key out of range")
+	}
+	// A = 2 and A = -2 give singular curves
+	if pub.a == fp511Two || pub.a == fp511TwoNeg {
+		return errors.New("csidh: singular curve")
+	}
+
+	A := coeff{a: pub.a, c: fp511One}
+	for {
+		var P point
+		var order = uint512{1}
+		if err := randFp(&P.x, rand); err != nil {
+			return err
+		}
+		P.z = fp511One
+
+		// Remove factor 4 from the order
+		calcA24(&A24, &A)
+		xDbl(&P, &P, &A24)
+		xDbl(&P, &P, &A24)
+
+		done, ok := orderCheck(&P, &A, 0, primeCount, &order)
+		if done {
+			if !ok {
+				return errors.New("csidh: curve is not supersingular")
+			}
+			return nil
+		}
+	}
+}
+
+// DeriveSecret computes a shared secret, which is the Montgomery coefficient
+// of the curve obtained by applying the group action of prv to pub. Public
+// key is validated first, error is returned if it's invalid or in case
+// user provided RNG fails.
+func DeriveSecret(prv *PrivateKey, pub *PublicKey, rand io.Reader) ([]byte, error) {
+	if prv == nil || pub == nil {
+		return nil, errors.New("csidh: invalid arguments")
+	}
+	if err := pub.Validate(rand); err != nil {
+		return nil, err
+	}
+	ss := &PublicKey{a: pub.a}
+	if err := prv.groupAction(&ss.a, rand); err != nil {
+		return nil, err
+	}
+	return ss.Export(), nil
+}
@@ -0,0 +1,293 @@
+package csidh
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// Test vectors generated with the reference implementation, taken from
+// github.com/cloudflare/circl.
+const katFile = "testdata/csidh_testvectors.json"
+
+type testVector struct {
+	ID     int    `json:"Id"`
+	Pk1    string `json:"Pk1"`
+	Pr1    string `json:"Pr1"`
+	Pk2    string `json:"Pk2"`
+	Ss     string `json:"Ss"`
+	Status string `json:"status"`
+}
+
+var variants = []KeyVariant{KeyVariant_Fast, KeyVariant_ConstantTime}
+
+func loadVectors(t *testing.T) []testVector {
+	var tests struct {
+		Vectors []testVector `json:"Vectors"`
+	}
+	f, err := os.Open(katFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err = json.NewDecoder(f).Decode(&tests); err != nil {
+		t.Fatal(err)
+	}
+	return tests.Vectors
+}
+
+func mustDecode(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestKAT(t *testing.T) {
+	vectors := loadVectors(t)
+	// Group action is slow, so only some of the vectors are checked
+	step := 8
+	if testing.Short() {
+		step = 32
+	}
+	for n := 0; n < len(vectors); n += step {
+		v := vectors[n]
+		// use both variants alternately
+		prv := NewPrivateKey(variants[(n/step)%2])
+		pub := NewPublicKey()
+		if err := prv.Import(mustDecode(t, v.Pr1)); err != nil {
+			t.Fatalf("[%d] %v", v.ID, err)
+		}
+		// Vectors with invalid public key 1 don't have Pk2
+		if v.Status != "invalid_public_key1" {
+			if err := pub.Import(mustDecode(t, v.Pk2)); err != nil {
+				t.Fatalf("[%d] %v", v.ID, err)
+			}
+		}
+
+		switch v.Status {
+		case "valid", "invalid_shared_secret":
+			ss, err := DeriveSecret(prv, pub, rand.Reader)
+			if err != nil {
+				t.Fatalf("[%d] %v", v.ID, err)
+			}
+			if bytes.Equal(ss, mustDecode(t, v.Ss)) != (v.Status == "valid") {
+				t.Errorf("[%d] unexpected shared secret", v.ID)
+			}
+			if v.Status != "valid" {
+				continue
+			}
+			fallthrough
+		case "invalid_public_key1":
+			pk, err := prv.GeneratePublicKey(rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.Equal(pk.Export(), mustDecode(t, v.Pk1)) != (v.Status == "valid") {
+				t.Errorf("[%d] unexpected public key", v.ID)
+			}
+		case "invalid_public_key2":
+			if pub.Validate(rand.Reader) == nil {
+				t.Errorf("[%d] invalid public key accepted", v.ID)
+			}
+		default:
+			t.Fatalf("[%d] unknown status %s", v.ID, v.Status)
+		}
+	}
+}
+
+func TestKeyExchange(t *testing.T) {
+	for _, v := range variants {
+		prv1, prv2 := NewPrivateKey(v), NewPrivateKey(v)
+		if err := prv1.Generate(rand.Reader); err != nil {
+			t.Fatal(err)
+		}
+		if err := prv2.Generate(rand.Reader); err != nil {
+			t.Fatal(err)
+		}
+		pub1, err := prv1.GeneratePublicKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub2, err := prv2.GeneratePublicKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ss1, err := DeriveSecret(prv1, pub2, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ss2, err := DeriveSecret(prv2, pub1, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(ss1, ss2) || len(ss1) != SharedSecretSize {
+			t.Errorf("variant %d: shared secrets differ", v)
+		}
+	}
+}
+
+// Both variants must compute the same public key
+func TestVariantsAgree(t *testing.T) {
+	prv := NewPrivateKey(KeyVariant_Fast)
+	if err := prv.Generate(rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+	pk1, err := prv.GeneratePublicKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prv.keyVariant = KeyVariant_ConstantTime
+	pk2, err := prv.GeneratePublicKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pk1.a != pk2.a {
+		t.Error("public keys differ")
+	}
+	if err := pk1.Validate(rand.Reader); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPrivateKeyImportExport(t *testing.T) {
+	prv1, prv2 := NewPrivateKey(KeyVariant_Fast), NewPrivateKey(KeyVariant_Fast)
+	for i := 0; i < 100; i++ {
+		if err := prv1.Generate(rand.Reader); err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range prv1.e {
+			if e < -expMax || e > expMax {
+				t.Fatalf("exponent %d out of range", e)
+			}
+		}
+		b := prv1.Export()
+		if len(b) != prv1.Size() {
+			t.Fatal("wrong size")
+		}
+		if err := prv2.Import(b); err != nil {
+			t.Fatal(err)
+		}
+		if prv1.e != prv2.e {
+			t.Fatal("import/export failed")
+		}
+	}
+
+	if err := prv2.Import(make([]byte, PrivateKeySize-1)); err == nil {
+		t.Error("expected error for wrong size")
+	}
+	b := make([]byte, PrivateKeySize)
+	b[3] = 0x60 // 6 is out of range
+	if err := prv2.Import(b); err == nil {
+		t.Error("expected error for exponent out of range")
+	}
+}
+
+func TestPublicKeyValidate(t *testing.T) {
+	var pub PublicKey
+
+	// y^2 = x^3 + x is supersingular
+	if err := pub.Validate(rand.Reader); err != nil {
+		t.Error(err)
+	}
+
+	pPlus1 := p511
+	pPlus1[0]++
+	invalid := []fp511Element{fp511Two, fp511TwoNeg, p511, pPlus1}
+	for _, a := range invalid {
+		pub.a = a
+		if pub.Validate(rand.Reader) == nil {
+			t.Errorf("%x: invalid public key accepted", a)
+		}
+	}
+
+	// A = 1 gives ordinary curve
+	pub.a = fp511One
+	if pub.Validate(rand.Reader) == nil {
+		t.Error("ordinary curve accepted")
+	}
+
+	if err := pub.Import(make([]byte, PublicKeySize+1)); err == nil {
+		t.Error("expected error for wrong size")
+	}
+	b := make([]byte, PublicKeySize)
+	b[0], b[63] = 1, 2
+	if err := pub.Import(b); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pub.Export(), b) {
+		t.Error("import/export failed")
+	}
+}
+
+// Isogeny of degree l maps its kernel to the point at infinity
+func TestIsogenyKernel(t *testing.T) {
+	var P [2]point
+	A := coeff{c: fp511One}
+	if err := randPoints(&P, &A.a, rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+
+	// K = [(p+1)/l]P has order l or 1
+	for _, l := range []uint64{3, 5, 587} {
+		var k = uint512{4}
+		for _, v := range primes {
+			if v != l {
+				k.mul64(&k, v)
+			}
+		}
+		var K point
+		xMul(&K, &P[0], &A, &k)
+		if isZero(&K.z) {
+			continue
+		}
+		B := A
+		img := []point{K}
+		xIso(&B, &K, l, img)
+		if !isZero(&img[0].z) {
+			t.Errorf("l=%d: kernel not mapped to infinity", l)
+		}
+		normalize(&B)
+		pub := PublicKey{a: B.a}
+		if err := pub.Validate(rand.Reader); err != nil {
+			t.Errorf("l=%d: %v", l, err)
+		}
+	}
+}
+
+func BenchmarkGeneratePublicKey(b *testing.B) {
+	for _, v := range variants {
+		prv := NewPrivateKey(v)
+		if err := prv.Generate(rand.Reader); err != nil {
+			b.Fatal(err)
+		}
+		name := map[KeyVariant]string{KeyVariant_Fast: "Fast", KeyVariant_ConstantTime: "ConstantTime"}[v]
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := prv.GeneratePublicKey(rand.Reader); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	prv := NewPrivateKey(KeyVariant_Fast)
+	if err := prv.Generate(rand.Reader); err != nil {
+		b.Fatal(err)
+	}
+	pub, err := prv.GeneratePublicKey(rand.Reader)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		if err := pub.Validate(rand.Reader); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package csidh
+
+import (
+	"math/bits"
+)
+
+// Unsigned 512-bit integer, used for scalars. Little-endian words.
+type uint512 [8]uint64
+
+// Projective x-coordinate (X:Z) of a point on Montgomery curve.
+// Point at infinity has Z = 0.
+type point struct {
+	x, z fp511Element
+}
+
+// Projective coefficients (A:C) of Montgomery curve Cy^2 = Cx^3 + Ax^2 + Cx
+type coeff struct {
+	a, c fp511Element
+}
+
+// Set z = x * y, where y is a small number. Overflow is ignored.
+func (z *uint512) mul64(x *uint512, y uint64) {
+	var carry uint64
+	for i := range x {
+		var c uint64
+		hi, lo := bits.Mul64(x[i], y)
+		z[i], c = bits.Add64(lo, carry, 0)
+		carry = hi + c
+	}
+}
+
+// Returns true if x < y. Not constant time.
+func (x *uint512) less(y *uint512) bool {
+	for i := len(x) - 1; i >= 0; i-- {
+		if x[i] != y[i] {
+			return x[i] < y[i]
+		}
+	}
+	return false
+}
+
+// Returns bit length of x. Not constant time.
+func (x *uint512) bitLen() int {
+	for i := len(x) - 1; i >= 0; i-- {
+		if x[i] != 0 {
+			return 64*i + bits.Len64(x[i])
+		}
+	}
+	return 0
+}
+
+// Returns true if x == 0. Not constant time.
+func isZero(x *fp511Element) bool {
+	return *x == fp511Element{}
+}
+
+// Returns 1 if x is zero and 0 otherwise, in constant time.
+func ctIsZero(x *fp511Element) uint8 {
+	var acc uint64
+	for i := range x {
+		acc |= x[i]
+	}
+	return uint8(1 ^ ((acc | -acc) >> 63))
+}
+
+// Sets z = x^3 + Ax^2 + x, the right hand side of the curve equation
+// for (A:1).
+func montRhs(z, a, x *fp511Element) {
+	var t fp511Element
+	fp511MulRdc(&t, x, x)     // t = x^2
+	fp511MulRdc(z, a, x)      // z = Ax
+	fp511AddReduced(z, z, &t) // z = x^2 + Ax
+	fp511AddReduced(z, z, &fp511One)
+	fp511MulRdc(z, z, x) // z = x^3 + Ax^2 + x
+}
+
+// Returns 1 if x is a non-zero square, 0 if x is zero and -1 otherwise.
+// Computed as x^((p-1)/2), so execution time doesn't depend on x.
+func legendre(x *fp511Element) int {
+	var t fp511Element
+	fp511Exp(&t, x, p511Minus1Over2[:])
+	if t == fp511One {
+		return 1
+	}
+	if isZero(&t) {
+		return 0
+	}
+	return -1
+}
+
+// Conditionally swaps points in constant time. Swap is done if
+// choice = 1, choice must be 0 or 1.
+func cswapPoint(P, Q *point, choice uint8) {
+	fp511ConditionalSwap(&P.x, &Q.x, choice)
+	fp511ConditionalSwap(&P.z, &Q.z, choice)
+}
+
+// Conditionally swaps curve coefficients in constant time.
+func cswapCoeff(A, B *coeff, choice uint8) {
+	fp511ConditionalSwap(&A.a, &B.a, choice)
+	fp511ConditionalSwap(&A.c, &B.c, choice)
+}
+
+// Computes x([2]P) on the curve E_(A:C). A24 = (A+2C:4C).
+func xDbl(Q, P *point, A24 *coeff) {
+	var t0, t1, t2 fp511Element
+
+	fp511AddReduced(&t0, &P.x, &P.z) // t0 = X+Z
+	fp511MulRdc(&t0, &t0, &t0)       // t0 = (X+Z)^2
+	fp511SubReduced(&t1, &P.x, &P.z) // t1 = X-Z
+	fp511MulRdc(&t1, &t1, &t1)       // t1 = (X-Z)^2
+	fp511SubReduced(&t2, &t0, &t1)   // t2 = 4XZ
+	fp511MulRdc(&t1, &t1, &A24.c)    // t1 = 4C(X-Z)^2
+	fp511MulRdc(&Q.x, &t0, &t1)      // XQ = 4C(X+Z)^2(X-Z)^2
+	fp511MulRdc(&t0, &t2, &A24.a)    // t0 = (A+2C)4XZ
+	fp511AddReduced(&t0, &t0, &t1)   // t0 = (A+2C)4XZ + 4C(X-Z)^2
+	fp511MulRdc(&Q.z, &t0, &t2)      // ZQ = t0 * 4XZ
+}
+
+// Computes x(P+Q) given x(P), x(Q) and x(P-Q). Result is correct if
+// P-Q isn't a point at infinity nor a point of order 2. Allowed to
+// overlap R with P or Q.
+func xAdd(R, P, Q, PmQ *point) {
+	var t0, t1, t2, t3 fp511Element
+
+	fp511AddReduced(&t0, &P.x, &P.z) // t0 = XP+ZP
+	fp511SubReduced(&t1, &P.x, &P.z) // t1 = XP-ZP
+	fp511AddReduced(&t2, &Q.x, &Q.z) // t2 = XQ+ZQ
+	fp511SubReduced(&t3, &Q.x, &Q.z) // t3 = XQ-ZQ
+	fp511MulRdc(&t0, &t0, &t3)       // t0 = (XP+ZP)(XQ-ZQ)
+	fp511MulRdc(&t1, &t1, &t2)       // t1 = (XP-ZP)(XQ+ZQ)
+	fp511AddReduced(&t2, &t0, &t1)
+	fp511SubReduced(&t3, &t0, &t1)
+	fp511MulRdc(&t2, &t2, &t2)
+	fp511MulRdc(&t3, &t3, &t3)
+	fp511MulRdc(&R.x, &PmQ.z, &t2)
+	fp511MulRdc(&R.z, &PmQ.x, &t3)
+}
+
+// Computes (A+2C:4C) from (A:C)
+func calcA24(A24, A *coeff) {
+	fp511AddReduced(&A24.c, &A.c, &A.c)
+	fp511AddReduced(&A24.a, &A.a, &A24.c)
+	fp511AddReduced(&A24.c, &A24.c, &A24.c)
+}
+
+// Computes x([k]P) on the curve E_(A:C) with Montgomery ladder. The
+// scalar k is public, execution time depends on its bit length.
+func xMul(kP, P *point, A *coeff, k *uint512) {
+	var A24 coeff
+	var R0, R1 point
+
+	n := k.bitLen()
+	if n == 0 {
+		*kP = point{x: fp511One}
+		return
+	}
+
+	calcA24(&A24, A)
+	R0 = *P
+	xDbl(&R1, P, &A24)
+	for i := n - 2; i >= 0; i-- {
+		// R1 - R0 = P
+		bit := uint8(k[i/64]>>uint(i%64)) & 1
+		cswapPoint(&R0, &R1, bit)
+		xAdd(&R1, &R0, &R1, P)
+		xDbl(&R0, &R0, &A24)
+		cswapPoint(&R0, &R1, bit)
+	}
+	*kP = R0
+}
+
+// Computes x([l]P) for small l
+func xMul64(kP, P *point, A *coeff, l uint64) {
+	var k = uint512{l}
+	xMul(kP, P, A, &k)
+}
+
+// xIso computes isogeny of odd prime degree l with kernel generated by K,
+// a point of order l on the curve E_(A:C). Coefficients of the codomain
+// are stored in A and points are replaced by their images.
+//
+// Uses formulas for Montgomery curves from "A simple and compact
+// algorithm for SIDH with arbitrary degree isogenies" by C. Costello and
+// H. Hisil (ia.cr/2017/504) for images and for codomain the twisted
+// Edwards formulas from "On the Use of Montgomery Curves in
+// Isogeny-Based Cryptography" by M. Meyer and S. Reith (ia.cr/2018/782).
+//
+// Execution time depends only on l.
+func xIso(A *coeff, K *point, l uint64, pts []point) {
+	var A24 coeff
+	var t0, t1, t2 fp511Element
+	var ed coeff
+	var prodM, prodP fp511Element
+	var M [3]point
+	var S, D [2]fp511Element
+	var Q [2]point
+
+	if len(pts) > len(Q) {
+		panic("csidh: too many points")
+	}
+
+	// Twisted Edwards coefficients a = A+2C, d = A-2C
+	fp511AddReduced(&t0, &A.c, &A.c)
+	fp511AddReduced(&ed.a, &A.a, &t0)
+	fp511SubReduced(&ed.c, &A.a, &t0)
+
+	// prodM = prod(Xi-Zi), prodP = prod(Xi+Zi) over multiples [i]K,
+	// i = 1..(l-1)/2
+	fp511SubReduced(&prodM, &K.x, &K.z)
+	fp511AddReduced(&prodP, &K.x, &K.z)
+	for j := range pts {
+		fp511AddReduced(&S[j], &pts[j].x, &pts[j].z)
+		fp511SubReduced(&D[j], &pts[j].x, &pts[j].z)
+		fp511MulRdc(&t0, &prodP, &D[j])
+		fp511MulRdc(&t1, &prodM, &S[j])
+		fp511AddReduced(&Q[j].x, &t0, &t1)
+		fp511SubReduced(&Q[j].z, &t0, &t1)
+	}
+
+	calcA24(&A24, A)
+	M[0] = *K
+	xDbl(&M[1], K, &A24)
+	for i := uint64(1); i < l/2; i++ {
+		if i >= 2 {
+			xAdd(&M[i%3], &M[(i-1)%3], K, &M[(i-2)%3])
+		}
+		fp511SubReduced(&t1, &M[i%3].x, &M[i%3].z)
+		fp511AddReduced(&t0, &M[i%3].x, &M[i%3].z)
+		fp511MulRdc(&prodM, &prodM, &t1)
+		fp511MulRdc(&prodP, &prodP, &t0)
+		for j := range pts {
+			var u, v fp511Element
+			fp511MulRdc(&u, &t1, &S[j])
+			fp511MulRdc(&v, &t0, &D[j])
+			fp511AddReduced(&t2, &v, &u)
+			fp511MulRdc(&Q[j].x, &Q[j].x, &t2)
+			fp511SubReduced(&t2, &v, &u)
+			fp511MulRdc(&Q[j].z, &Q[j].z, &t2)
+		}
+	}
+
+	// Images: (X*Qx^2 : Z*Qz^2)
+	for j := range pts {
+		fp511MulRdc(&Q[j].x, &Q[j].x, &Q[j].x)
+		fp511MulRdc(&Q[j].z, &Q[j].z, &Q[j].z)
+		fp511MulRdc(&pts[j].x, &pts[j].x, &Q[j].x)
+		fp511MulRdc(&pts[j].z, &pts[j].z, &Q[j].z)
+	}
+
+	// a' = a^l * prodP^8, d' = d^l * prodM^8
+	fp511Exp(&ed.a, &ed.a, []uint64{l})
+	fp511Exp(&ed.c, &ed.c, []uint64{l})
+	for i := 0; i < 3; i++ {
+		fp511MulRdc(&prodM, &prodM, &prodM)
+		fp511MulRdc(&prodP, &prodP, &prodP)
+	}
+	fp511MulRdc(&ed.a, &ed.a, &prodP)
+	fp511MulRdc(&ed.c, &ed.c, &prodM)
+
+	// Back to Montgomery: (A':C') = (2(a'+d') : a'-d')
+	fp511AddReduced(&A.a, &ed.a, &ed.c)
+	fp511SubReduced(&A.c, &ed.a, &ed.c)
+	fp511AddReduced(&A.a, &A.a, &A.a)
+}
@@ -0,0 +1,26 @@
+// Package csidh implements CSIDH-512, a non-interactive key exchange based
+// on the action of the class group on supersingular Montgomery curves over
+// F_p, as described in "CSIDH: An Efficient Post-Quantum Commutative Group
+// Action" by W. Castryck, T. Lange, C. Martindale, L. Panny and J. Renes
+// (ia.cr/2018/383).
+//
+// Unlike SIDH, CSIDH public keys can be validated, which makes the scheme
+// usable with static keys. Two implementations of the group action are
+// provided. KeyVariant_Fast is the algorithm from the CSIDH paper, its
+// execution time depends on the private key, so it must only be used with
+// ephemeral keys. KeyVariant_ConstantTime always computes the same number
+// of (possibly dummy) isogenies of each degree, using two torsion points
+// as proposed by Onuki, Aikawa, Yamazaki and Takagi (ia.cr/2019/353); its
+// timing doesn't depend on the private key.
+//
+// Both variants compute the same public keys and shared secrets. Encoding
+// of the keys is compatible with the reference implementation: a public key
+// is the Montgomery coefficient A in Montgomery domain (A*2^512 mod p)
+// stored as 64-byte little-endian integer. Private key stores 74 exponents
+// from [-5, 5] as signed 4-bit values, two per byte.
+//
+// Field arithmetic (files with p511_ prefix) is generated by
+// dh/sidh/internal/fpgen, rerun "go generate" instead of editing it.
+package csidh
+
+//go:generate go run ../sidh/internal/fpgen -name p511 -pkg csidh -fp2=false -prime 4*3*5*7*11*13*17*19*23*29*31*37*41*43*47*53*59*61*67*71*73*79*83*89*97*101*103*107*109*113*127*131*137*139*149*151*157*163*167*173*179*181*191*193*197*199*211*223*227*229*233*239*241*251*257*263*269*271*277*281*283*293*307*311*313*317*331*337*347*349*353*359*367*373*587-1
@@ -0,0 +1,1694 @@
+// Code generated by fpgen. DO NOT EDIT.
+
+//go:build arm64 && !noasm
+// +build arm64,!noasm
+
+#include "textflag.h"
+
+TEXT ·fp511ConditionalSwap(SB), NOSPLIT, $0-17
+	MOVD	x+0(FP), R0
+	MOVD	y+8(FP), R1
+	MOVBU	choice+16(FP), R2
+
+	// mask = 0 - choice
+	NEG	R2, R2
+	MOVD	0(R0), R3
+	MOVD	0(R1), R4
+	EOR	R3, R4, R5
+	AND	R2, R5, R5
+	EOR	R5, R3, R3
+	EOR	R5, R4, R4
+	MOVD	R3, 0(R0)
+	MOVD	R4, 0(R1)
+	MOVD	8(R0), R3
+	MOVD	8(R1), R4
+	EOR	R3, R4, R5
+	AND	R2, R5, R5
+	EOR	R5, R3, R3
+	EOR	R5, R4, R4
+	MOVD	R3, 8(R0)
+	MOVD	R4, 8(R1)
+	MOVD	16(R0), R3
+	MOVD	16(R1), R4
+	EOR	R3, R4, R5
+	AND	R2, R5, R5
+	EOR	R5, R3, R3
+	EOR	R5, R4, R4
+	MOVD	R3, 16(R0)
+	MOVD	R4, 16(R1)
+	MOVD	24(R0), R3
+	MOVD	24(R1), R4
+	EOR	R3, R4, R5
+	AND	R2, R5, R5
+	EOR	R5, R3, R3
+	EOR	R5, R4, R4
+	MOVD	R3, 24(R0)
+	MOVD	R4, 24(R1)
+	MOVD	32(R0), R3
+	MOVD	32(R1), R4
+	EOR	R3, R4, R5
+	AND	R2, R5, R5
+	EOR	R5, R3, R3
+	EOR	R5, R4, R4
+	MOVD	R3, 32(R0)
+	MOVD	R4, 32(R1)
+	MOVD	40(R0), R3
+	MOVD	40(R1), R4
+	EOR	R3, R4, R5
+	AND	R2, R5, R5
+	EOR	R5, R3, R3
+	EOR	R5, R4, R4
+	MOVD	R3, 40(R0)
+	MOVD	R4, 40(R1)
+	MOVD	48(R0), R3
+	MOVD	48(R1), R4
+	EOR	R3, R4, R5
+	AND	R2, R5, R5
+	EOR	R5, R3, R3
+	EOR	R5, R4, R4
+	MOVD	R3, 48(R0)
+	MOVD	R4, 48(R1)
+	MOVD	56(R0), R3
+	MOVD	56(R1), R4
+	EOR	R3, R4, R5
+	AND	R2, R5, R5
+	EOR	R5, R3, R3
+	EOR	R5, R4, R4
+	MOVD	R3, 56(R0)
+	MOVD	R4, 56(R1)
+	RET	
+
+TEXT ·fp511AddReduced(SB), NOSPLIT, $0-24
+	MOVD	z+0(FP), R0
+	MOVD	x+8(FP), R1
+	MOVD	y+16(FP), R2
+
+	// z = x + y
+	MOVD	0(R1), R3
+	MOVD	0(R2), R4
+	ADDS	R4, R3, R3
+	MOVD	R3, 0(R0)
+	MOVD	8(R1), R3
+	MOVD	8(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 8(R0)
+	MOVD	16(R1), R3
+	MOVD	16(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 16(R0)
+	MOVD	24(R1), R3
+	MOVD	24(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 24(R0)
+	MOVD	32(R1), R3
+	MOVD	32(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 32(R0)
+	MOVD	40(R1), R3
+	MOVD	40(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 40(R0)
+	MOVD	48(R1), R3
+	MOVD	48(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 48(R0)
+	MOVD	56(R1), R3
+	MOVD	56(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 56(R0)
+
+	// R0 = R0 - p511
+	MOVD	0(R0), R3
+	MOVD	·p511+0(SB), R4
+	SUBS	R4, R3, R3
+	MOVD	R3, 0(R0)
+	MOVD	8(R0), R3
+	MOVD	·p511+8(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 8(R0)
+	MOVD	16(R0), R3
+	MOVD	·p511+16(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 16(R0)
+	MOVD	24(R0), R3
+	MOVD	·p511+24(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 24(R0)
+	MOVD	32(R0), R3
+	MOVD	·p511+32(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 32(R0)
+	MOVD	40(R0), R3
+	MOVD	·p511+40(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 40(R0)
+	MOVD	48(R0), R3
+	MOVD	·p511+48(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 48(R0)
+	MOVD	56(R0), R3
+	MOVD	·p511+56(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 56(R0)
+
+	// if R0<0 add p511 back
+	SBC	ZR, ZR, R5
+	MOVD	0(R0), R3
+	MOVD	·p511+0(SB), R4
+	AND	R5, R4, R4
+	ADDS	R4, R3, R3
+	MOVD	R3, 0(R0)
+	MOVD	8(R0), R3
+	MOVD	·p511+8(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 8(R0)
+	MOVD	16(R0), R3
+	MOVD	·p511+16(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 16(R0)
+	MOVD	24(R0), R3
+	MOVD	·p511+24(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 24(R0)
+	MOVD	32(R0), R3
+	MOVD	·p511+32(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 32(R0)
+	MOVD	40(R0), R3
+	MOVD	·p511+40(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 40(R0)
+	MOVD	48(R0), R3
+	MOVD	·p511+48(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 48(R0)
+	MOVD	56(R0), R3
+	MOVD	·p511+56(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 56(R0)
+	RET	
+
+TEXT ·fp511SubReduced(SB), NOSPLIT, $0-24
+	MOVD	z+0(FP), R0
+	MOVD	x+8(FP), R1
+	MOVD	y+16(FP), R2
+
+	// z = x - y
+	MOVD	0(R1), R3
+	MOVD	0(R2), R4
+	SUBS	R4, R3, R3
+	MOVD	R3, 0(R0)
+	MOVD	8(R1), R3
+	MOVD	8(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 8(R0)
+	MOVD	16(R1), R3
+	MOVD	16(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 16(R0)
+	MOVD	24(R1), R3
+	MOVD	24(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 24(R0)
+	MOVD	32(R1), R3
+	MOVD	32(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 32(R0)
+	MOVD	40(R1), R3
+	MOVD	40(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 40(R0)
+	MOVD	48(R1), R3
+	MOVD	48(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 48(R0)
+	MOVD	56(R1), R3
+	MOVD	56(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 56(R0)
+
+	// if R0<0 add p511 back
+	SBC	ZR, ZR, R5
+	MOVD	0(R0), R3
+	MOVD	·p511+0(SB), R4
+	AND	R5, R4, R4
+	ADDS	R4, R3, R3
+	MOVD	R3, 0(R0)
+	MOVD	8(R0), R3
+	MOVD	·p511+8(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 8(R0)
+	MOVD	16(R0), R3
+	MOVD	·p511+16(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 16(R0)
+	MOVD	24(R0), R3
+	MOVD	·p511+24(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 24(R0)
+	MOVD	32(R0), R3
+	MOVD	·p511+32(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 32(R0)
+	MOVD	40(R0), R3
+	MOVD	·p511+40(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 40(R0)
+	MOVD	48(R0), R3
+	MOVD	·p511+48(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 48(R0)
+	MOVD	56(R0), R3
+	MOVD	·p511+56(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 56(R0)
+	RET	
+
+TEXT ·fp511AddLazy(SB), NOSPLIT, $0-24
+	MOVD	z+0(FP), R0
+	MOVD	x+8(FP), R1
+	MOVD	y+16(FP), R2
+	MOVD	0(R1), R3
+	MOVD	0(R2), R4
+	ADDS	R4, R3, R3
+	MOVD	R3, 0(R0)
+	MOVD	8(R1), R3
+	MOVD	8(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 8(R0)
+	MOVD	16(R1), R3
+	MOVD	16(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 16(R0)
+	MOVD	24(R1), R3
+	MOVD	24(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 24(R0)
+	MOVD	32(R1), R3
+	MOVD	32(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 32(R0)
+	MOVD	40(R1), R3
+	MOVD	40(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 40(R0)
+	MOVD	48(R1), R3
+	MOVD	48(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 48(R0)
+	MOVD	56(R1), R3
+	MOVD	56(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 56(R0)
+	RET	
+
+TEXT ·fp511X2AddLazy(SB), NOSPLIT, $0-24
+	MOVD	z+0(FP), R0
+	MOVD	x+8(FP), R1
+	MOVD	y+16(FP), R2
+	MOVD	0(R1), R3
+	MOVD	0(R2), R4
+	ADDS	R4, R3, R3
+	MOVD	R3, 0(R0)
+	MOVD	8(R1), R3
+	MOVD	8(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 8(R0)
+	MOVD	16(R1), R3
+	MOVD	16(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 16(R0)
+	MOVD	24(R1), R3
+	MOVD	24(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 24(R0)
+	MOVD	32(R1), R3
+	MOVD	32(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 32(R0)
+	MOVD	40(R1), R3
+	MOVD	40(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 40(R0)
+	MOVD	48(R1), R3
+	MOVD	48(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 48(R0)
+	MOVD	56(R1), R3
+	MOVD	56(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 56(R0)
+	MOVD	64(R1), R3
+	MOVD	64(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 64(R0)
+	MOVD	72(R1), R3
+	MOVD	72(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 72(R0)
+	MOVD	80(R1), R3
+	MOVD	80(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 80(R0)
+	MOVD	88(R1), R3
+	MOVD	88(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 88(R0)
+	MOVD	96(R1), R3
+	MOVD	96(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 96(R0)
+	MOVD	104(R1), R3
+	MOVD	104(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 104(R0)
+	MOVD	112(R1), R3
+	MOVD	112(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 112(R0)
+	MOVD	120(R1), R3
+	MOVD	120(R2), R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 120(R0)
+	RET	
+
+TEXT ·fp511X2SubLazy(SB), NOSPLIT, $0-24
+	MOVD	z+0(FP), R0
+	MOVD	x+8(FP), R1
+	MOVD	y+16(FP), R2
+
+	// z = x - y
+	MOVD	0(R1), R3
+	MOVD	0(R2), R4
+	SUBS	R4, R3, R3
+	MOVD	R3, 0(R0)
+	MOVD	8(R1), R3
+	MOVD	8(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 8(R0)
+	MOVD	16(R1), R3
+	MOVD	16(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 16(R0)
+	MOVD	24(R1), R3
+	MOVD	24(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 24(R0)
+	MOVD	32(R1), R3
+	MOVD	32(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 32(R0)
+	MOVD	40(R1), R3
+	MOVD	40(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 40(R0)
+	MOVD	48(R1), R3
+	MOVD	48(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 48(R0)
+	MOVD	56(R1), R3
+	MOVD	56(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 56(R0)
+	MOVD	64(R1), R3
+	MOVD	64(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 64(R0)
+	MOVD	72(R1), R3
+	MOVD	72(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 72(R0)
+	MOVD	80(R1), R3
+	MOVD	80(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 80(R0)
+	MOVD	88(R1), R3
+	MOVD	88(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 88(R0)
+	MOVD	96(R1), R3
+	MOVD	96(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 96(R0)
+	MOVD	104(R1), R3
+	MOVD	104(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 104(R0)
+	MOVD	112(R1), R3
+	MOVD	112(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 112(R0)
+	MOVD	120(R1), R3
+	MOVD	120(R2), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 120(R0)
+
+	// if R0<0 add p511 back
+	SBC	ZR, ZR, R5
+	MOVD	64(R0), R3
+	MOVD	·p511+0(SB), R4
+	AND	R5, R4, R4
+	ADDS	R4, R3, R3
+	MOVD	R3, 64(R0)
+	MOVD	72(R0), R3
+	MOVD	·p511+8(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 72(R0)
+	MOVD	80(R0), R3
+	MOVD	·p511+16(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 80(R0)
+	MOVD	88(R0), R3
+	MOVD	·p511+24(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 88(R0)
+	MOVD	96(R0), R3
+	MOVD	·p511+32(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 96(R0)
+	MOVD	104(R0), R3
+	MOVD	·p511+40(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 104(R0)
+	MOVD	112(R0), R3
+	MOVD	·p511+48(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 112(R0)
+	MOVD	120(R0), R3
+	MOVD	·p511+56(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 120(R0)
+	RET	
+
+TEXT ·fp511StrongReduce(SB), NOSPLIT, $0-8
+	MOVD	x+0(FP), R0
+
+	// R0 = R0 - p511
+	MOVD	0(R0), R3
+	MOVD	·p511+0(SB), R4
+	SUBS	R4, R3, R3
+	MOVD	R3, 0(R0)
+	MOVD	8(R0), R3
+	MOVD	·p511+8(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 8(R0)
+	MOVD	16(R0), R3
+	MOVD	·p511+16(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 16(R0)
+	MOVD	24(R0), R3
+	MOVD	·p511+24(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 24(R0)
+	MOVD	32(R0), R3
+	MOVD	·p511+32(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 32(R0)
+	MOVD	40(R0), R3
+	MOVD	·p511+40(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 40(R0)
+	MOVD	48(R0), R3
+	MOVD	·p511+48(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 48(R0)
+	MOVD	56(R0), R3
+	MOVD	·p511+56(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 56(R0)
+
+	// if R0<0 add p511 back
+	SBC	ZR, ZR, R5
+	MOVD	0(R0), R3
+	MOVD	·p511+0(SB), R4
+	AND	R5, R4, R4
+	ADDS	R4, R3, R3
+	MOVD	R3, 0(R0)
+	MOVD	8(R0), R3
+	MOVD	·p511+8(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 8(R0)
+	MOVD	16(R0), R3
+	MOVD	·p511+16(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 16(R0)
+	MOVD	24(R0), R3
+	MOVD	·p511+24(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 24(R0)
+	MOVD	32(R0), R3
+	MOVD	·p511+32(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 32(R0)
+	MOVD	40(R0), R3
+	MOVD	·p511+40(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 40(R0)
+	MOVD	48(R0), R3
+	MOVD	·p511+48(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 48(R0)
+	MOVD	56(R0), R3
+	MOVD	·p511+56(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 56(R0)
+	RET	
+
+TEXT ·fp511Mul(SB), NOSPLIT, $0-24
+	MOVD	z+0(FP), R0
+	MOVD	x+8(FP), R1
+	MOVD	y+16(FP), R2
+	MOVD	ZR, R8
+	MOVD	ZR, R9
+	MOVD	ZR, R10
+
+	// z[0]
+	MOVD	0(R1), R3
+	MOVD	0(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	R8, 0(R0)
+	MOVD	ZR, R8
+
+	// z[1]
+	MOVD	0(R1), R3
+	MOVD	8(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	8(R1), R3
+	MOVD	0(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	R9, 8(R0)
+	MOVD	ZR, R9
+
+	// z[2]
+	MOVD	0(R1), R3
+	MOVD	16(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	8(R1), R3
+	MOVD	8(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	16(R1), R3
+	MOVD	0(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	R10, 16(R0)
+	MOVD	ZR, R10
+
+	// z[3]
+	MOVD	0(R1), R3
+	MOVD	24(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	8(R1), R3
+	MOVD	16(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	16(R1), R3
+	MOVD	8(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	24(R1), R3
+	MOVD	0(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	R8, 24(R0)
+	MOVD	ZR, R8
+
+	// z[4]
+	MOVD	0(R1), R3
+	MOVD	32(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	8(R1), R3
+	MOVD	24(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	16(R1), R3
+	MOVD	16(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	24(R1), R3
+	MOVD	8(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	32(R1), R3
+	MOVD	0(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	R9, 32(R0)
+	MOVD	ZR, R9
+
+	// z[5]
+	MOVD	0(R1), R3
+	MOVD	40(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	8(R1), R3
+	MOVD	32(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	16(R1), R3
+	MOVD	24(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	24(R1), R3
+	MOVD	16(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	32(R1), R3
+	MOVD	8(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	40(R1), R3
+	MOVD	0(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	R10, 40(R0)
+	MOVD	ZR, R10
+
+	// z[6]
+	MOVD	0(R1), R3
+	MOVD	48(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	8(R1), R3
+	MOVD	40(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	16(R1), R3
+	MOVD	32(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	24(R1), R3
+	MOVD	24(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	32(R1), R3
+	MOVD	16(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	40(R1), R3
+	MOVD	8(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	48(R1), R3
+	MOVD	0(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	R8, 48(R0)
+	MOVD	ZR, R8
+
+	// z[7]
+	MOVD	0(R1), R3
+	MOVD	56(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	8(R1), R3
+	MOVD	48(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	16(R1), R3
+	MOVD	40(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	24(R1), R3
+	MOVD	32(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	32(R1), R3
+	MOVD	24(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	40(R1), R3
+	MOVD	16(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	48(R1), R3
+	MOVD	8(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	56(R1), R3
+	MOVD	0(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	R9, 56(R0)
+	MOVD	ZR, R9
+
+	// z[8]
+	MOVD	8(R1), R3
+	MOVD	56(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	16(R1), R3
+	MOVD	48(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	24(R1), R3
+	MOVD	40(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	32(R1), R3
+	MOVD	32(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	40(R1), R3
+	MOVD	24(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	48(R1), R3
+	MOVD	16(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	56(R1), R3
+	MOVD	8(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	R10, 64(R0)
+	MOVD	ZR, R10
+
+	// z[9]
+	MOVD	16(R1), R3
+	MOVD	56(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	24(R1), R3
+	MOVD	48(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	32(R1), R3
+	MOVD	40(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	40(R1), R3
+	MOVD	32(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	48(R1), R3
+	MOVD	24(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	56(R1), R3
+	MOVD	16(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	R8, 72(R0)
+	MOVD	ZR, R8
+
+	// z[10]
+	MOVD	24(R1), R3
+	MOVD	56(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	32(R1), R3
+	MOVD	48(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	40(R1), R3
+	MOVD	40(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	48(R1), R3
+	MOVD	32(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	56(R1), R3
+	MOVD	24(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	R9, 80(R0)
+	MOVD	ZR, R9
+
+	// z[11]
+	MOVD	32(R1), R3
+	MOVD	56(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	40(R1), R3
+	MOVD	48(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	48(R1), R3
+	MOVD	40(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	56(R1), R3
+	MOVD	32(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	R10, 88(R0)
+	MOVD	ZR, R10
+
+	// z[12]
+	MOVD	40(R1), R3
+	MOVD	56(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	48(R1), R3
+	MOVD	48(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	56(R1), R3
+	MOVD	40(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	R8, 96(R0)
+	MOVD	ZR, R8
+
+	// z[13]
+	MOVD	48(R1), R3
+	MOVD	56(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	56(R1), R3
+	MOVD	48(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	R9, 104(R0)
+	MOVD	ZR, R9
+
+	// z[14]
+	MOVD	56(R1), R3
+	MOVD	56(R2), R4
+	MUL	R4, R3, R6
+	UMULH	R4, R3, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	R10, 112(R0)
+	MOVD	ZR, R10
+	MOVD	R8, 120(R0)
+	RET	
+
+TEXT ·fp511MontgomeryReduce(SB), NOSPLIT, $0-16
+	MOVD	z+0(FP), R0
+	MOVD	x+8(FP), R1
+	MOVD	$0x66C1301F632E294D, R11
+	MOVD	ZR, R8
+	MOVD	ZR, R9
+	MOVD	ZR, R10
+
+	// m[0]
+	MOVD	0(R1), R3
+	ADDS	R3, R8, R8
+	ADCS	ZR, R9, R9
+	ADC	ZR, R10, R10
+	MUL	R11, R8, R12
+	MOVD	·p511+0(SB), R4
+	MUL	R4, R12, R6
+	UMULH	R4, R12, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	ZR, R8
+
+	// m[1]
+	MOVD	·p511+8(SB), R4
+	MUL	R4, R12, R6
+	UMULH	R4, R12, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	8(R1), R3
+	ADDS	R3, R9, R9
+	ADCS	ZR, R10, R10
+	ADC	ZR, R8, R8
+	MUL	R11, R9, R13
+	MOVD	·p511+0(SB), R4
+	MUL	R4, R13, R6
+	UMULH	R4, R13, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	ZR, R9
+
+	// m[2]
+	MOVD	·p511+16(SB), R4
+	MUL	R4, R12, R6
+	UMULH	R4, R12, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	·p511+8(SB), R4
+	MUL	R4, R13, R6
+	UMULH	R4, R13, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	16(R1), R3
+	ADDS	R3, R10, R10
+	ADCS	ZR, R8, R8
+	ADC	ZR, R9, R9
+	MUL	R11, R10, R14
+	MOVD	·p511+0(SB), R4
+	MUL	R4, R14, R6
+	UMULH	R4, R14, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	ZR, R10
+
+	// m[3]
+	MOVD	·p511+24(SB), R4
+	MUL	R4, R12, R6
+	UMULH	R4, R12, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	·p511+16(SB), R4
+	MUL	R4, R13, R6
+	UMULH	R4, R13, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	·p511+8(SB), R4
+	MUL	R4, R14, R6
+	UMULH	R4, R14, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	24(R1), R3
+	ADDS	R3, R8, R8
+	ADCS	ZR, R9, R9
+	ADC	ZR, R10, R10
+	MUL	R11, R8, R15
+	MOVD	·p511+0(SB), R4
+	MUL	R4, R15, R6
+	UMULH	R4, R15, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	ZR, R8
+
+	// m[4]
+	MOVD	·p511+32(SB), R4
+	MUL	R4, R12, R6
+	UMULH	R4, R12, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	·p511+24(SB), R4
+	MUL	R4, R13, R6
+	UMULH	R4, R13, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	·p511+16(SB), R4
+	MUL	R4, R14, R6
+	UMULH	R4, R14, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	·p511+8(SB), R4
+	MUL	R4, R15, R6
+	UMULH	R4, R15, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	32(R1), R3
+	ADDS	R3, R9, R9
+	ADCS	ZR, R10, R10
+	ADC	ZR, R8, R8
+	MUL	R11, R9, R16
+	MOVD	·p511+0(SB), R4
+	MUL	R4, R16, R6
+	UMULH	R4, R16, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	ZR, R9
+
+	// m[5]
+	MOVD	·p511+40(SB), R4
+	MUL	R4, R12, R6
+	UMULH	R4, R12, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	·p511+32(SB), R4
+	MUL	R4, R13, R6
+	UMULH	R4, R13, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	·p511+24(SB), R4
+	MUL	R4, R14, R6
+	UMULH	R4, R14, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	·p511+16(SB), R4
+	MUL	R4, R15, R6
+	UMULH	R4, R15, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	·p511+8(SB), R4
+	MUL	R4, R16, R6
+	UMULH	R4, R16, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	40(R1), R3
+	ADDS	R3, R10, R10
+	ADCS	ZR, R8, R8
+	ADC	ZR, R9, R9
+	MUL	R11, R10, R17
+	MOVD	·p511+0(SB), R4
+	MUL	R4, R17, R6
+	UMULH	R4, R17, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	ZR, R10
+
+	// m[6]
+	MOVD	·p511+48(SB), R4
+	MUL	R4, R12, R6
+	UMULH	R4, R12, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	·p511+40(SB), R4
+	MUL	R4, R13, R6
+	UMULH	R4, R13, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	·p511+32(SB), R4
+	MUL	R4, R14, R6
+	UMULH	R4, R14, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	·p511+24(SB), R4
+	MUL	R4, R15, R6
+	UMULH	R4, R15, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	·p511+16(SB), R4
+	MUL	R4, R16, R6
+	UMULH	R4, R16, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	·p511+8(SB), R4
+	MUL	R4, R17, R6
+	UMULH	R4, R17, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	48(R1), R3
+	ADDS	R3, R8, R8
+	ADCS	ZR, R9, R9
+	ADC	ZR, R10, R10
+	MUL	R11, R8, R19
+	MOVD	·p511+0(SB), R4
+	MUL	R4, R19, R6
+	UMULH	R4, R19, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	ZR, R8
+
+	// m[7]
+	MOVD	·p511+56(SB), R4
+	MUL	R4, R12, R6
+	UMULH	R4, R12, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	·p511+48(SB), R4
+	MUL	R4, R13, R6
+	UMULH	R4, R13, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	·p511+40(SB), R4
+	MUL	R4, R14, R6
+	UMULH	R4, R14, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	·p511+32(SB), R4
+	MUL	R4, R15, R6
+	UMULH	R4, R15, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	·p511+24(SB), R4
+	MUL	R4, R16, R6
+	UMULH	R4, R16, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	·p511+16(SB), R4
+	MUL	R4, R17, R6
+	UMULH	R4, R17, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	·p511+8(SB), R4
+	MUL	R4, R19, R6
+	UMULH	R4, R19, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	56(R1), R3
+	ADDS	R3, R9, R9
+	ADCS	ZR, R10, R10
+	ADC	ZR, R8, R8
+	MUL	R11, R9, R20
+	MOVD	·p511+0(SB), R4
+	MUL	R4, R20, R6
+	UMULH	R4, R20, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	ZR, R9
+
+	// z[0]
+	MOVD	·p511+56(SB), R4
+	MUL	R4, R13, R6
+	UMULH	R4, R13, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	·p511+48(SB), R4
+	MUL	R4, R14, R6
+	UMULH	R4, R14, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	·p511+40(SB), R4
+	MUL	R4, R15, R6
+	UMULH	R4, R15, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	·p511+32(SB), R4
+	MUL	R4, R16, R6
+	UMULH	R4, R16, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	·p511+24(SB), R4
+	MUL	R4, R17, R6
+	UMULH	R4, R17, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	·p511+16(SB), R4
+	MUL	R4, R19, R6
+	UMULH	R4, R19, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	·p511+8(SB), R4
+	MUL	R4, R20, R6
+	UMULH	R4, R20, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	64(R1), R3
+	ADDS	R3, R10, R10
+	ADCS	ZR, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	R10, 0(R0)
+	MOVD	ZR, R10
+
+	// z[1]
+	MOVD	·p511+56(SB), R4
+	MUL	R4, R14, R6
+	UMULH	R4, R14, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	·p511+48(SB), R4
+	MUL	R4, R15, R6
+	UMULH	R4, R15, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	·p511+40(SB), R4
+	MUL	R4, R16, R6
+	UMULH	R4, R16, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	·p511+32(SB), R4
+	MUL	R4, R17, R6
+	UMULH	R4, R17, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	·p511+24(SB), R4
+	MUL	R4, R19, R6
+	UMULH	R4, R19, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	·p511+16(SB), R4
+	MUL	R4, R20, R6
+	UMULH	R4, R20, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	72(R1), R3
+	ADDS	R3, R8, R8
+	ADCS	ZR, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	R8, 8(R0)
+	MOVD	ZR, R8
+
+	// z[2]
+	MOVD	·p511+56(SB), R4
+	MUL	R4, R15, R6
+	UMULH	R4, R15, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	·p511+48(SB), R4
+	MUL	R4, R16, R6
+	UMULH	R4, R16, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	·p511+40(SB), R4
+	MUL	R4, R17, R6
+	UMULH	R4, R17, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	·p511+32(SB), R4
+	MUL	R4, R19, R6
+	UMULH	R4, R19, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	·p511+24(SB), R4
+	MUL	R4, R20, R6
+	UMULH	R4, R20, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	80(R1), R3
+	ADDS	R3, R9, R9
+	ADCS	ZR, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	R9, 16(R0)
+	MOVD	ZR, R9
+
+	// z[3]
+	MOVD	·p511+56(SB), R4
+	MUL	R4, R16, R6
+	UMULH	R4, R16, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	·p511+48(SB), R4
+	MUL	R4, R17, R6
+	UMULH	R4, R17, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	·p511+40(SB), R4
+	MUL	R4, R19, R6
+	UMULH	R4, R19, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	·p511+32(SB), R4
+	MUL	R4, R20, R6
+	UMULH	R4, R20, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	88(R1), R3
+	ADDS	R3, R10, R10
+	ADCS	ZR, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	R10, 24(R0)
+	MOVD	ZR, R10
+
+	// z[4]
+	MOVD	·p511+56(SB), R4
+	MUL	R4, R17, R6
+	UMULH	R4, R17, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	·p511+48(SB), R4
+	MUL	R4, R19, R6
+	UMULH	R4, R19, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	·p511+40(SB), R4
+	MUL	R4, R20, R6
+	UMULH	R4, R20, R7
+	ADDS	R6, R8, R8
+	ADCS	R7, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	96(R1), R3
+	ADDS	R3, R8, R8
+	ADCS	ZR, R9, R9
+	ADC	ZR, R10, R10
+	MOVD	R8, 32(R0)
+	MOVD	ZR, R8
+
+	// z[5]
+	MOVD	·p511+56(SB), R4
+	MUL	R4, R19, R6
+	UMULH	R4, R19, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	·p511+48(SB), R4
+	MUL	R4, R20, R6
+	UMULH	R4, R20, R7
+	ADDS	R6, R9, R9
+	ADCS	R7, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	104(R1), R3
+	ADDS	R3, R9, R9
+	ADCS	ZR, R10, R10
+	ADC	ZR, R8, R8
+	MOVD	R9, 40(R0)
+	MOVD	ZR, R9
+
+	// z[6]
+	MOVD	·p511+56(SB), R4
+	MUL	R4, R20, R6
+	UMULH	R4, R20, R7
+	ADDS	R6, R10, R10
+	ADCS	R7, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	112(R1), R3
+	ADDS	R3, R10, R10
+	ADCS	ZR, R8, R8
+	ADC	ZR, R9, R9
+	MOVD	R10, 48(R0)
+	MOVD	ZR, R10
+	MOVD	120(R1), R3
+	ADD	R3, R8, R8
+	MOVD	R8, 56(R0)
+
+	// R0 = R0 - p511
+	MOVD	0(R0), R3
+	MOVD	·p511+0(SB), R4
+	SUBS	R4, R3, R3
+	MOVD	R3, 0(R0)
+	MOVD	8(R0), R3
+	MOVD	·p511+8(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 8(R0)
+	MOVD	16(R0), R3
+	MOVD	·p511+16(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 16(R0)
+	MOVD	24(R0), R3
+	MOVD	·p511+24(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 24(R0)
+	MOVD	32(R0), R3
+	MOVD	·p511+32(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 32(R0)
+	MOVD	40(R0), R3
+	MOVD	·p511+40(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 40(R0)
+	MOVD	48(R0), R3
+	MOVD	·p511+48(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 48(R0)
+	MOVD	56(R0), R3
+	MOVD	·p511+56(SB), R4
+	SBCS	R4, R3, R3
+	MOVD	R3, 56(R0)
+
+	// if R0<0 add p511 back
+	SBC	ZR, ZR, R5
+	MOVD	0(R0), R3
+	MOVD	·p511+0(SB), R4
+	AND	R5, R4, R4
+	ADDS	R4, R3, R3
+	MOVD	R3, 0(R0)
+	MOVD	8(R0), R3
+	MOVD	·p511+8(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 8(R0)
+	MOVD	16(R0), R3
+	MOVD	·p511+16(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 16(R0)
+	MOVD	24(R0), R3
+	MOVD	·p511+24(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 24(R0)
+	MOVD	32(R0), R3
+	MOVD	·p511+32(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 32(R0)
+	MOVD	40(R0), R3
+	MOVD	·p511+40(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 40(R0)
+	MOVD	48(R0), R3
+	MOVD	·p511+48(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 48(R0)
+	MOVD	56(R0), R3
+	MOVD	·p511+56(SB), R4
+	AND	R5, R4, R4
+	ADCS	R4, R3, R3
+	MOVD	R3, 56(R0)
+	RET	
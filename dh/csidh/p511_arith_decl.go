@@ -0,0 +1,53 @@
+// Code generated by fpgen. DO NOT EDIT.
+
+//go:build (amd64 && !noasm) || (arm64 && !noasm)
+// +build amd64,!noasm arm64,!noasm
+
+package csidh
+
+// If choice = 0, leave x,y unchanged. If choice = 1, set x,y = y,x.
+// If choice is neither 0 nor 1 then behaviour is undefined.
+// This function executes in constant time.
+//
+//go:noescape
+func fp511ConditionalSwap(x, y *fp511Element, choice uint8)
+
+// Compute z = x + y (mod p).
+//
+//go:noescape
+func fp511AddReduced(z, x, y *fp511Element)
+
+// Compute z = x - y (mod p).
+//
+//go:noescape
+func fp511SubReduced(z, x, y *fp511Element)
+
+// Compute z = x + y, without reducing mod p.
+//
+//go:noescape
+func fp511AddLazy(z, x, y *fp511Element)
+
+// Compute z = x + y, without reducing mod p.
+//
+//go:noescape
+func fp511X2AddLazy(z, x, y *fp511ElementX2)
+
+// Compute z = x - y, without reducing mod p.
+//
+//go:noescape
+func fp511X2SubLazy(z, x, y *fp511ElementX2)
+
+// Reduce a field element in [0, 2*p) to one in [0,p).
+//
+//go:noescape
+func fp511StrongReduce(x *fp511Element)
+
+// Computes z = x * y.
+//
+//go:noescape
+func fp511Mul(z *fp511ElementX2, x, y *fp511Element)
+
+// Computes the Montgomery reduction z = x R^{-1} (mod p).
+//
+//go:noescape
+func fp511MontgomeryReduce(z *fp511Element, x *fp511ElementX2)
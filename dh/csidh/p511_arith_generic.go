@@ -0,0 +1,187 @@
+// Code generated by fpgen. DO NOT EDIT.
+
+//go:build noasm || (!amd64 && !arm64)
+// +build noasm !amd64,!arm64
+
+package csidh
+
+import (
+	"math/bits"
+)
+
+// Compute z = x + y (mod p).
+func fp511AddReduced(z, x, y *fp511Element) {
+	var carry uint64
+
+	// z=x+y % p511
+	for i := 0; i < fp511NumWords; i++ {
+		z[i], carry = bits.Add64(x[i], y[i], carry)
+	}
+
+	// z = z - p511
+	carry = 0
+	for i := 0; i < fp511NumWords; i++ {
+		z[i], carry = bits.Sub64(z[i], p511[i], carry)
+	}
+
+	// if z<0 add p511 back
+	mask := uint64(0 - carry)
+	carry = 0
+	for i := 0; i < fp511NumWords; i++ {
+		z[i], carry = bits.Add64(z[i], p511[i]&mask, carry)
+	}
+}
+
+// Compute z = x - y (mod p).
+func fp511SubReduced(z, x, y *fp511Element) {
+	var borrow uint64
+
+	// z = x - y
+	for i := 0; i < fp511NumWords; i++ {
+		z[i], borrow = bits.Sub64(x[i], y[i], borrow)
+	}
+
+	// if z<0 add p511 back
+	mask := uint64(0 - borrow)
+	borrow = 0
+	for i := 0; i < fp511NumWords; i++ {
+		z[i], borrow = bits.Add64(z[i], p511[i]&mask, borrow)
+	}
+}
+
+// Conditionally swaps bits in x and y in constant time.
+// mask indicates bits to be swapped (set bits are swapped)
+// For details see "Hackers Delight, 2.20"
+func fp511ConditionalSwap(x, y *fp511Element, mask uint8) {
+	var tmp, mask64 uint64
+
+	mask64 = 0 - uint64(mask)
+	for i := 0; i < fp511NumWords; i++ {
+		tmp = mask64 & (x[i] ^ y[i])
+		x[i] = tmp ^ x[i]
+		y[i] = tmp ^ y[i]
+	}
+}
+
+// Perform Montgomery reduction: set z = x R^{-1} (mod p)
+// with R=2^512. Requires x < p*R.
+func fp511MontgomeryReduce(z *fp511Element, x *fp511ElementX2) {
+	var m [fp511NumWords]uint64
+	var t, u, v, hi, lo, carry uint64
+
+	// Product scanning, (t,u,v) is a column accumulator
+	for i := 0; i < fp511NumWords; i++ {
+		for j := 0; j < i; j++ {
+			hi, lo = bits.Mul64(m[j], p511[i-j])
+			v, carry = bits.Add64(v, lo, 0)
+			u, carry = bits.Add64(u, hi, carry)
+			t += carry
+		}
+		v, carry = bits.Add64(v, x[i], 0)
+		u, carry = bits.Add64(u, 0, carry)
+		t += carry
+
+		// m[i] is chosen so that lowest word of accumulator becomes 0
+		m[i] = v * p511MPrime
+		hi, lo = bits.Mul64(m[i], p511[0])
+		v, carry = bits.Add64(v, lo, 0)
+		u, carry = bits.Add64(u, hi, carry)
+		t += carry
+
+		v, u, t = u, t, 0
+	}
+
+	for i := fp511NumWords; i < 2*fp511NumWords-1; i++ {
+		for j := i - fp511NumWords + 1; j < fp511NumWords; j++ {
+			hi, lo = bits.Mul64(m[j], p511[i-j])
+			v, carry = bits.Add64(v, lo, 0)
+			u, carry = bits.Add64(u, hi, carry)
+			t += carry
+		}
+		v, carry = bits.Add64(v, x[i], 0)
+		u, carry = bits.Add64(u, 0, carry)
+		t += carry
+
+		z[i-fp511NumWords] = v
+		v, u, t = u, t, 0
+	}
+	z[fp511NumWords-1] = v + x[2*fp511NumWords-1]
+
+	// z < 2*p, reduce to [0, p)
+	fp511StrongReduce(z)
+}
+
+// Compute z = x + y, without reducing mod p.
+func fp511AddLazy(z, x, y *fp511Element) {
+	var carry uint64
+	for i := 0; i < fp511NumWords; i++ {
+		z[i], carry = bits.Add64(x[i], y[i], carry)
+	}
+}
+
+// Compute z = x + y, without reducing mod p.
+func fp511X2AddLazy(z, x, y *fp511ElementX2) {
+	var carry uint64
+	for i := 0; i < 2*fp511NumWords; i++ {
+		z[i], carry = bits.Add64(x[i], y[i], carry)
+	}
+}
+
+// Reduce a field element in [0, 2*p) to one in [0,p).
+func fp511StrongReduce(x *fp511Element) {
+	var borrow, mask uint64
+	for i := 0; i < fp511NumWords; i++ {
+		x[i], borrow = bits.Sub64(x[i], p511[i], borrow)
+	}
+
+	// Mask is 0 if x>=p, otherwise -1
+	mask = 0 - borrow
+	borrow = 0
+	for i := 0; i < fp511NumWords; i++ {
+		x[i], borrow = bits.Add64(x[i], p511[i]&mask, borrow)
+	}
+}
+
+// Compute z = x - y, without reducing mod p.
+func fp511X2SubLazy(z, x, y *fp511ElementX2) {
+	var borrow, mask uint64
+	for i := 0; i < 2*fp511NumWords; i++ {
+		z[i], borrow = bits.Sub64(x[i], y[i], borrow)
+	}
+
+	// if z<0, add p*R back
+	mask = 0 - borrow
+	borrow = 0
+	for i := fp511NumWords; i < 2*fp511NumWords; i++ {
+		z[i], borrow = bits.Add64(z[i], p511[i-fp511NumWords]&mask, borrow)
+	}
+}
+
+// Compute z = x * y.
+func fp511Mul(z *fp511ElementX2, x, y *fp511Element) {
+	var t, u, v, hi, lo, carry uint64
+
+	// Product scanning, (t,u,v) is a column accumulator
+	for i := 0; i < fp511NumWords; i++ {
+		for j := 0; j <= i; j++ {
+			hi, lo = bits.Mul64(x[j], y[i-j])
+			v, carry = bits.Add64(v, lo, 0)
+			u, carry = bits.Add64(u, hi, carry)
+			t += carry
+		}
+		z[i] = v
+		v, u, t = u, t, 0
+	}
+
+	for i := fp511NumWords; i < 2*fp511NumWords-1; i++ {
+		for j := i - fp511NumWords + 1; j < fp511NumWords; j++ {
+			hi, lo = bits.Mul64(x[j], y[i-j])
+			v, carry = bits.Add64(v, lo, 0)
+			u, carry = bits.Add64(u, hi, carry)
+			t += carry
+		}
+		z[i] = v
+		v, u, t = u, t, 0
+	}
+	z[2*fp511NumWords-1] = v
+}
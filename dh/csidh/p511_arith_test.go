@@ -0,0 +1,284 @@
+// Code generated by fpgen. DO NOT EDIT.
+
+package csidh
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// Number of values used by tests. Each binary operation is checked
+// on all pairs.
+const fp511TestValues = 40
+
+var (
+	fp511BigP     = fp511ToBig(p511[:fp511NumWords])
+	fp511BigR     = new(big.Int).Lsh(big.NewInt(1), 64*fp511NumWords)
+	fp511BigBound = new(big.Int).Mul(big.NewInt(1), fp511BigP)
+	fp511BigPR    = new(big.Int).Mul(fp511BigP, fp511BigR)
+	fp511BigRInv  = new(big.Int).ModInverse(fp511BigR, fp511BigP)
+)
+
+// Converts little-endian words to big.Int
+func fp511ToBig(x []uint64) *big.Int {
+	b := new(big.Int)
+	for i := len(x) - 1; i >= 0; i-- {
+		b.Lsh(b, 64)
+		b.Or(b, new(big.Int).SetUint64(x[i]))
+	}
+	return b
+}
+
+// Converts b to little-endian words. Panics if b doesn't fit in x.
+func fp511FromBig(x []uint64, b *big.Int) {
+	t := new(big.Int).Set(b)
+	mask := new(big.Int).SetUint64(^uint64(0))
+	for i := range x {
+		x[i] = new(big.Int).And(t, mask).Uint64()
+		t.Rsh(t, 64)
+	}
+	if t.Sign() != 0 {
+		panic("value too big")
+	}
+}
+
+// Returns values from [0, max), starting with edge cases
+func fp511Values(rng *rand.Rand, max *big.Int) []*big.Int {
+	one := big.NewInt(1)
+	var vals []*big.Int
+	for _, v := range []*big.Int{
+		big.NewInt(0),
+		one,
+		new(big.Int).Sub(fp511BigP, one),
+		fp511BigP,
+		new(big.Int).Add(fp511BigP, one),
+		new(big.Int).Sub(max, one),
+	} {
+		if v.Cmp(max) < 0 {
+			vals = append(vals, v)
+		}
+	}
+	for len(vals) < fp511TestValues {
+		vals = append(vals, new(big.Int).Rand(rng, max))
+	}
+	return vals
+}
+
+// Checks if got is equal to exp
+func fp511CheckEq(t *testing.T, op string, got []uint64, exp *big.Int, args ...*big.Int) {
+	if g := fp511ToBig(got); g.Cmp(exp) != 0 {
+		t.Errorf("%s%x:\n got: %x\n exp: %x", op, args, g, exp)
+	}
+}
+
+// Checks if got is in [0, bound) and congruent to exp modulo p
+func fp511CheckMod(t *testing.T, op string, got []uint64, exp *big.Int, args ...*big.Int) {
+	g := fp511ToBig(got)
+	d := new(big.Int).Sub(g, exp)
+	if g.Cmp(fp511BigBound) >= 0 || d.Mod(d, fp511BigP).Sign() != 0 {
+		t.Errorf("%s%x:\n got: %x\n exp: %x (mod p)", op, args, g, exp)
+	}
+}
+
+// Runs f on all pairs of values from [0, max)
+func fp511ForPairs(max *big.Int, f func(a, b *big.Int)) {
+	vals := fp511Values(rand.New(rand.NewSource(1)), max)
+	for _, a := range vals {
+		for _, b := range vals {
+			f(a, b)
+		}
+	}
+}
+
+func TestP511Constants(t *testing.T) {
+	if !fp511BigP.ProbablyPrime(20) {
+		t.Error("p is not prime")
+	}
+	m := new(big.Int).SetUint64(p511MPrime)
+	m.Mul(m, fp511BigP).Add(m, big.NewInt(1))
+	if m.Uint64() != 0 {
+		t.Error("p*mprime != -1 mod 2^64")
+	}
+	r2 := new(big.Int).Mul(fp511BigR, fp511BigR)
+	fp511CheckEq(t, "R2", p511R2[:fp511NumWords], r2.Mod(r2, fp511BigP))
+	fp511CheckEq(t, "One", fp511One[:fp511NumWords], new(big.Int).Mod(fp511BigR, fp511BigP))
+}
+
+func TestP511AddReduced(t *testing.T) {
+	var x, y, z fp511Element
+	fp511ForPairs(fp511BigBound, func(a, b *big.Int) {
+		fp511FromBig(x[:fp511NumWords], a)
+		fp511FromBig(y[:fp511NumWords], b)
+		fp511AddReduced(&z, &x, &y)
+		fp511CheckMod(t, "AddReduced", z[:fp511NumWords], new(big.Int).Add(a, b), a, b)
+	})
+}
+
+func TestP511SubReduced(t *testing.T) {
+	var x, y, z fp511Element
+	fp511ForPairs(fp511BigBound, func(a, b *big.Int) {
+		fp511FromBig(x[:fp511NumWords], a)
+		fp511FromBig(y[:fp511NumWords], b)
+		fp511SubReduced(&z, &x, &y)
+		fp511CheckMod(t, "SubReduced", z[:fp511NumWords], new(big.Int).Sub(a, b), a, b)
+	})
+}
+
+func TestP511AddLazy(t *testing.T) {
+	var x, y, z fp511Element
+	fp511ForPairs(fp511BigBound, func(a, b *big.Int) {
+		fp511FromBig(x[:fp511NumWords], a)
+		fp511FromBig(y[:fp511NumWords], b)
+		fp511AddLazy(&z, &x, &y)
+		fp511CheckEq(t, "AddLazy", z[:fp511NumWords], new(big.Int).Add(a, b), a, b)
+	})
+}
+
+func TestP511X2AddLazy(t *testing.T) {
+	var x, y, z fp511ElementX2
+	fp511ForPairs(fp511BigPR, func(a, b *big.Int) {
+		fp511FromBig(x[:2*fp511NumWords], a)
+		fp511FromBig(y[:2*fp511NumWords], b)
+		fp511X2AddLazy(&z, &x, &y)
+		fp511CheckEq(t, "X2AddLazy", z[:2*fp511NumWords], new(big.Int).Add(a, b), a, b)
+	})
+}
+
+func TestP511X2SubLazy(t *testing.T) {
+	var x, y, z fp511ElementX2
+	fp511ForPairs(fp511BigPR, func(a, b *big.Int) {
+		fp511FromBig(x[:2*fp511NumWords], a)
+		fp511FromBig(y[:2*fp511NumWords], b)
+		fp511X2SubLazy(&z, &x, &y)
+		// Negative result is shifted by p*R
+		exp := new(big.Int).Sub(a, b)
+		if exp.Sign() < 0 {
+			exp.Add(exp, fp511BigPR)
+		}
+		fp511CheckEq(t, "X2SubLazy", z[:2*fp511NumWords], exp, a, b)
+	})
+}
+
+func TestP511StrongReduce(t *testing.T) {
+	var x fp511Element
+	max := new(big.Int).Lsh(fp511BigP, 1)
+	for _, a := range fp511Values(rand.New(rand.NewSource(1)), max) {
+		fp511FromBig(x[:fp511NumWords], a)
+		fp511StrongReduce(&x)
+		fp511CheckEq(t, "StrongReduce", x[:fp511NumWords], new(big.Int).Mod(a, fp511BigP), a)
+	}
+}
+
+func TestP511ConditionalSwap(t *testing.T) {
+	var x, y fp511Element
+	fp511ForPairs(fp511BigBound, func(a, b *big.Int) {
+		fp511FromBig(x[:fp511NumWords], a)
+		fp511FromBig(y[:fp511NumWords], b)
+		fp511ConditionalSwap(&x, &y, 0)
+		fp511CheckEq(t, "ConditionalSwap(0)", x[:fp511NumWords], a, a, b)
+		fp511CheckEq(t, "ConditionalSwap(0)", y[:fp511NumWords], b, a, b)
+		fp511ConditionalSwap(&x, &y, 1)
+		fp511CheckEq(t, "ConditionalSwap(1)", x[:fp511NumWords], b, a, b)
+		fp511CheckEq(t, "ConditionalSwap(1)", y[:fp511NumWords], a, a, b)
+	})
+}
+
+func TestP511Mul(t *testing.T) {
+	var x, y fp511Element
+	var z fp511ElementX2
+	fp511ForPairs(fp511BigBound, func(a, b *big.Int) {
+		fp511FromBig(x[:fp511NumWords], a)
+		fp511FromBig(y[:fp511NumWords], b)
+		fp511Mul(&z, &x, &y)
+		fp511CheckEq(t, "Mul", z[:2*fp511NumWords], new(big.Int).Mul(a, b), a, b)
+	})
+}
+
+func TestP511MontgomeryReduce(t *testing.T) {
+	var x fp511ElementX2
+	var z fp511Element
+	for _, a := range fp511Values(rand.New(rand.NewSource(1)), fp511BigPR) {
+		fp511FromBig(x[:2*fp511NumWords], a)
+		fp511MontgomeryReduce(&z, &x)
+		exp := new(big.Int).Mul(a, fp511BigRInv)
+		fp511CheckMod(t, "MontgomeryReduce", z[:fp511NumWords], exp, a)
+	}
+}
+
+func TestP511MulRdc(t *testing.T) {
+	var x, y, z fp511Element
+	fp511ForPairs(fp511BigBound, func(a, b *big.Int) {
+		fp511FromBig(x[:fp511NumWords], a)
+		fp511FromBig(y[:fp511NumWords], b)
+		fp511MulRdc(&z, &x, &y)
+		exp := new(big.Int).Mul(a, b)
+		exp.Mul(exp, fp511BigRInv)
+		fp511CheckMod(t, "MulRdc", z[:fp511NumWords], exp, a, b)
+	})
+}
+
+func TestP511Inv(t *testing.T) {
+	var x, z fp511Element
+	for _, a := range fp511Values(rand.New(rand.NewSource(1)), fp511BigBound) {
+		fp511FromBig(x[:fp511NumWords], a)
+		fp511Inv(&z, &x)
+
+		// x = a*R, so expected result is a^-1*R = R^2/x
+		exp := new(big.Int).Mul(fp511BigR, fp511BigR)
+		if inv := new(big.Int).ModInverse(a, fp511BigP); inv != nil {
+			exp.Mul(exp, inv)
+		} else {
+			exp.SetInt64(0)
+		}
+		fp511CheckMod(t, "Inv", z[:fp511NumWords], exp, a)
+	}
+}
+
+func TestP511MontgomeryRoundTrip(t *testing.T) {
+	var x, z fp511Element
+	for _, a := range fp511Values(rand.New(rand.NewSource(1)), fp511BigP) {
+		fp511FromBig(x[:fp511NumWords], a)
+		fp511ToMontgomery(&z, &x)
+		exp := new(big.Int).Mul(a, fp511BigR)
+		fp511CheckMod(t, "ToMontgomery", z[:fp511NumWords], exp, a)
+		fp511FromMontgomery(&z, &z)
+		fp511CheckEq(t, "FromMontgomery", z[:fp511NumWords], a, a)
+	}
+}
+
+func BenchmarkP511Mul(b *testing.B) {
+	var x, y fp511Element
+	var z fp511ElementX2
+	fp511FromBig(x[:fp511NumWords], new(big.Int).Sub(fp511BigP, big.NewInt(1)))
+	y = x
+	for n := 0; n < b.N; n++ {
+		fp511Mul(&z, &x, &y)
+	}
+}
+
+func BenchmarkP511MontgomeryReduce(b *testing.B) {
+	var x fp511ElementX2
+	var z fp511Element
+	fp511FromBig(x[:2*fp511NumWords], new(big.Int).Sub(fp511BigPR, big.NewInt(1)))
+	for n := 0; n < b.N; n++ {
+		fp511MontgomeryReduce(&z, &x)
+	}
+}
+
+func BenchmarkP511AddReduced(b *testing.B) {
+	var x, y, z fp511Element
+	fp511FromBig(x[:fp511NumWords], new(big.Int).Sub(fp511BigP, big.NewInt(1)))
+	y = x
+	for n := 0; n < b.N; n++ {
+		fp511AddReduced(&z, &x, &y)
+	}
+}
+
+func BenchmarkP511Inv(b *testing.B) {
+	var x fp511Element
+	fp511FromBig(x[:fp511NumWords], new(big.Int).Sub(fp511BigP, big.NewInt(1)))
+	for n := 0; n < b.N; n++ {
+		fp511Inv(&x, &x)
+	}
+}
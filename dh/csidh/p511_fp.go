@@ -0,0 +1,65 @@
+// Code generated by fpgen. DO NOT EDIT.
+
+package csidh
+
+// Set z = x * y * R^{-1} (mod p).
+//
+// Allowed to overlap x or y with z.
+func fp511MulRdc(z, x, y *fp511Element) {
+	var t fp511ElementX2
+	fp511Mul(&t, x, y)
+	fp511MontgomeryReduce(z, &t)
+}
+
+// Set z = x^e, where x is in Montgomery domain and e is little-endian
+// exponent. Uses fixed window of 4 bits. Exponent is assumed to be public,
+// execution time doesn't depend on x.
+//
+// Allowed to overlap x with z.
+func fp511Exp(z, x *fp511Element, e []uint64) {
+	var lookup [16]fp511Element
+	var started bool
+
+	// lookup[i] = x^i
+	lookup[0] = fp511One
+	lookup[1] = *x
+	for i := 2; i < 16; i++ {
+		fp511MulRdc(&lookup[i], &lookup[i-1], x)
+	}
+
+	res := lookup[0]
+	for i := 16*len(e) - 1; i >= 0; i-- {
+		if started {
+			for j := 0; j < 4; j++ {
+				fp511MulRdc(&res, &res, &res)
+			}
+		}
+		w := (e[i/16] >> (4 * uint(i%16))) & 0xF
+		if w != 0 {
+			fp511MulRdc(&res, &res, &lookup[w])
+			started = true
+		}
+	}
+	*z = res
+}
+
+// Set z = 1/x (mod p), computed as x^(p-2). Inverse of 0 is 0.
+//
+// Allowed to overlap x with z.
+func fp511Inv(z, x *fp511Element) {
+	fp511Exp(z, x, p511Minus2[:])
+}
+
+// Converts x to Montgomery domain, z = x*R mod p.
+func fp511ToMontgomery(z, x *fp511Element) {
+	fp511MulRdc(z, x, &p511R2)
+}
+
+// Converts x from Montgomery domain, z = x*R^{-1} mod p. Result is
+// fully reduced to [0, p).
+func fp511FromMontgomery(z, x *fp511Element) {
+	var t fp511ElementX2
+	copy(t[:], x[:fp511NumWords])
+	fp511MontgomeryReduce(z, &t)
+	fp511StrongReduce(z)
+}
@@ -54,8 +54,13 @@ type PrivateKey struct {
 	Scalar []byte
 	// Used only by KEM
 	S []byte
+	// Set by Destroy, key can't be used afterwards
+	destroyed bool
 }
 
+// Panic value when destroyed private key is used
+var errDestroyed = errors.New("sidh: private key destroyed")
+
 // Accessor to the domain parameters
 func (key *key) Params() *SidhParams {
 	return key.params
@@ -120,8 +125,12 @@ func (pub *PublicKey) Size() int {
 }
 
 // Exports currently stored key. In case structure hasn't been filled with key data
-// returned byte string is filled with zeros.
+// returned byte string is filled with zeros. Panics if private key was
+// destroyed.
 func (prv *PrivateKey) Export() []byte {
+	if prv.destroyed {
+		panic(errDestroyed)
+	}
 	ret := make([]byte, len(prv.Scalar)+len(prv.S))
 	copy(ret, prv.S)
 	copy(ret[len(prv.S):], prv.Scalar)
@@ -141,7 +150,11 @@ func (prv *PrivateKey) Size() int {
 // and imports key from octet string. In case of SIKE, the random value 'S'
 // must be prepended to the value of actual private key (see SIKE spec for details).
 // Function doesn't import public key value to PrivateKey object.
+// Panics if private key was destroyed.
 func (prv *PrivateKey) Import(input []byte) error {
+	if prv.destroyed {
+		panic(errDestroyed)
+	}
 	if len(input) != prv.Size() {
 		return errors.New("sidh: input to short")
 	}
@@ -155,11 +168,19 @@ func (prv *PrivateKey) Import(input []byte) error {
 // for KeyVariant_A or <2^(s-1)..2^s - 1>, where s = ceil(log_2(3^e3)),
 // for KeyVariant_B.
 //
-// Returns error in case user provided RNG fails.
+// Returns error in case user provided RNG fails or if use of broken
+// algorithms wasn't enabled (see Params().Meta). Panics if private key
+// was destroyed.
 func (prv *PrivateKey) Generate(rand io.Reader) error {
 	var err error
 	var dp *DomainParams
 
+	if prv.destroyed {
+		panic(errDestroyed)
+	}
+	if err = prv.params.Meta.Check(); err != nil {
+		return err
+	}
 	if (prv.keyVariant & KeyVariant_SIDH_A) == KeyVariant_SIDH_A {
 		dp = &prv.params.A
 	} else {
@@ -190,10 +211,13 @@ func (prv *PrivateKey) Generate(rand io.Reader) error {
 	return err
 }
 
-// Generates public key.
+// Generates public key. Panics if private key was destroyed.
 //
 // Constant time.
 func (prv *PrivateKey) GeneratePublicKey() *PublicKey {
+	if prv.destroyed {
+		panic(errDestroyed)
+	}
 	if (prv.keyVariant & KeyVariant_SIDH_A) == KeyVariant_SIDH_A {
 		return publicKeyGenA(prv)
 	}
@@ -207,7 +231,9 @@ func (prv *PrivateKey) GeneratePublicKey() *PublicKey {
 // It's important to notice that each keypair must not be used more than once
 // to calculate shared secret.
 //
-// Function may return error. This happens only in case provided input is invalid.
+// Function may return error. This happens in case provided input is invalid
+// or if use of broken algorithms wasn't enabled (see Params().Meta).
+// Panics if private key was destroyed.
 // Constant time for properly initialized private and public key.
 func DeriveSecret(prv *PrivateKey, pub *PublicKey) ([]byte, error) {
 
@@ -215,6 +241,14 @@ func DeriveSecret(prv *PrivateKey, pub *PublicKey) ([]byte, error) {
 		return nil, errors.New("sidh: invalid arguments")
 	}
 
+	if prv.destroyed {
+		panic(errDestroyed)
+	}
+
+	if err := prv.params.Meta.Check(); err != nil {
+		return nil, err
+	}
+
 	if (pub.keyVariant == prv.keyVariant) || (pub.params.Id != prv.params.Id) {
 		return nil, errors.New("sidh: public and private are incompatbile")
 	}
@@ -225,3 +259,22 @@ func DeriveSecret(prv *PrivateKey, pub *PublicKey) ([]byte, error) {
 		return deriveSecretB(prv, pub), nil
 	}
 }
+
+// Zeroize overwrites secret values of the private key with zeros. The key
+// can be reused, by calling Generate or Import.
+func (prv *PrivateKey) Zeroize() {
+	for i := range prv.Scalar {
+		prv.Scalar[i] = 0
+	}
+	for i := range prv.S {
+		prv.S[i] = 0
+	}
+}
+
+// Destroy zeroizes the private key and marks it as destroyed. Any later
+// call to Import, Export, Generate, GeneratePublicKey or DeriveSecret
+// panics.
+func (prv *PrivateKey) Destroy() {
+	prv.Zeroize()
+	prv.destroyed = true
+}
@@ -0,0 +1,52 @@
+//go:build ctcheck
+// +build ctcheck
+
+package sidh
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/henrydcase/nobs/utils/ctcheck"
+)
+
+// Number of measurements of SIDH operations
+const ctMeasurements = 1000
+
+// Checks that DeriveSecret runs in the same time for fixed private key
+// (class 0) and random ones (class 1).
+func TestDeriveSecretCT(t *testing.T) {
+	variants := []struct {
+		name     string
+		prv, pub KeyVariant
+	}{
+		{"A", KeyVariant_SIDH_A, KeyVariant_SIDH_B},
+		{"B", KeyVariant_SIDH_B, KeyVariant_SIDH_A},
+	}
+	for _, id := range []uint8{FP_503, FP_751} {
+		for _, v := range variants {
+			t.Run(tdata[id].name+"/"+v.name, func(t *testing.T) {
+				peer := NewPrivateKey(id, v.pub)
+				fixed := NewPrivateKey(id, v.prv)
+				if err := peer.Generate(rand.Reader); err != nil {
+					t.Fatal(err)
+				}
+				if err := fixed.Generate(rand.Reader); err != nil {
+					t.Fatal(err)
+				}
+				pub := peer.GeneratePublicKey()
+
+				ctcheck.Check(t, "DeriveSecret", ctMeasurements, func(class int) func() {
+					prv := NewPrivateKey(id, v.prv)
+					if class == 0 {
+						prv.Import(fixed.Export())
+					} else if err := prv.Generate(rand.Reader); err != nil {
+						t.Fatal(err)
+					}
+					ctcheck.Secret(prv.Scalar)
+					return func() { DeriveSecret(prv, pub) }
+				})
+			})
+		}
+	}
+}
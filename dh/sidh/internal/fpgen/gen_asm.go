@@ -0,0 +1,511 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// asm is a helper for writing assembly source
+type asm struct {
+	bytes.Buffer
+}
+
+// I writes an instruction
+func (a *asm) I(op string, args ...string) {
+	fmt.Fprintf(a, "\t%s\t%s\n", op, strings.Join(args, ", "))
+}
+
+// C writes a comment preceded by an empty line
+func (a *asm) C(format string, args ...interface{}) {
+	fmt.Fprintf(a, "\n\t// "+format+"\n", args...)
+}
+
+// text starts a function
+func (a *asm) text(name string, frame, args int) {
+	fmt.Fprintf(a, "\nTEXT ·%s(SB), NOSPLIT, $%d-%d\n", name, frame, args)
+}
+
+// ret ends a function
+func (a *asm) ret() { a.I("RET") }
+
+func asmHeader(tags string) string {
+	return fmt.Sprintf("// Code generated by fpgen. DO NOT EDIT.\n\n"+
+		"//go:build %s\n// +build %s\n\n#include \"textflag.h\"\n", goBuildExpr(tags), tags)
+}
+
+// off returns memory operand for i-th word of memory pointed by reg
+func off(i int, reg string) string { return fmt.Sprintf("%d(%s)", 8*i, reg) }
+
+// sym returns memory operand for i-th word of global variable
+func sym(name string, i int) string { return fmt.Sprintf("·%s+%d(SB)", name, 8*i) }
+
+// ---------------------------------------------------------------------------
+// amd64
+// ---------------------------------------------------------------------------
+
+// Memory operands are used for all multi-word chains, so that code works
+// for any number of words. MOVQ doesn't modify flags, so carry can be
+// propagated through loads and stores. Multiplication uses MULQ with a
+// 3-word column accumulator (product scanning).
+
+// amd64Local returns name of i-th local variable on the stack
+func amd64Local(name string, i int) string {
+	return fmt.Sprintf("%s%d-%d(SP)", name, i, 8*(i+1))
+}
+
+// amd64Chain emits z[i] = x[i] op y[i] for i in [from, to), propagating carry
+func (a *asm) amd64Chain(op, opc string, z, x, y func(int) string, from, to int) {
+	for i := from; i < to; i++ {
+		a.I("MOVQ", x(i), "AX")
+		if i == from {
+			a.I(op, y(i), "AX")
+		} else {
+			a.I(opc, y(i), "AX")
+		}
+		a.I("MOVQ", "AX", z(i))
+	}
+}
+
+// amd64Masked stores words of a global constant ANDed with CX on the stack
+func (a *asm) amd64Masked(name string, n int) {
+	for i := 0; i < n; i++ {
+		a.I("MOVQ", sym(name, i), "AX")
+		a.I("ANDQ", "CX", "AX")
+		a.I("MOVQ", "AX", amd64Local("t", i))
+	}
+}
+
+// amd64CondSub emits z = z - c; if z < 0 then z = z + c. All n words of c
+// are used, where c is a global constant.
+func (a *asm) amd64CondSub(f *field, c, reg string) {
+	n := f.Words
+	mem := func(i int) string { return off(i, reg) }
+	a.C("%s = %s - %s", reg, reg, c)
+	a.amd64Chain("SUBQ", "SBBQ", mem, mem, func(i int) string { return sym(c, i) }, 0, n)
+	a.amd64AddBack(f, c, reg, 0)
+}
+
+// amd64AddBack emits z = z + (c & mask), where mask is all ones in case
+// of borrow from previous instruction. Words of c are added to words of z
+// starting from first.
+func (a *asm) amd64AddBack(f *field, c, reg string, first int) {
+	n := f.Words
+	a.C("if %s<0 add %s back", reg, c)
+	a.I("SBBQ", "CX", "CX")
+	a.amd64Masked(c, n)
+	a.amd64Chain("ADDQ", "ADCQ",
+		func(i int) string { return off(i, reg) },
+		func(i int) string { return off(i, reg) },
+		func(i int) string { return amd64Local("t", i-first) },
+		first, first+n)
+}
+
+// amd64Acc is a column accumulator (R10:R9:R8) with rotating registers
+type amd64Acc [3]string
+
+func (c *amd64Acc) rotate(a *asm) {
+	c[0], c[1], c[2] = c[1], c[2], c[0]
+	a.I("XORQ", c[2], c[2])
+}
+
+// mulAdd emits acc += AX * src
+func (c *amd64Acc) mulAdd(a *asm, x, y string) {
+	a.I("MOVQ", x, "AX")
+	a.I("MULQ", y)
+	a.I("ADDQ", "AX", c[0])
+	a.I("ADCQ", "DX", c[1])
+	a.I("ADCQ", "$0", c[2])
+}
+
+// add emits acc += src
+func (c *amd64Acc) add(a *asm, src string) {
+	a.I("ADDQ", src, c[0])
+	a.I("ADCQ", "$0", c[1])
+	a.I("ADCQ", "$0", c[2])
+}
+
+func genAmd64(f *field) ([]byte, error) {
+	n := f.Words
+	fn, p, bound := f.Fn(), f.Name, f.Bound()
+	a := &asm{}
+	a.WriteString(asmHeader("amd64,!noasm"))
+
+	// Conditional swap
+	a.text(fn+"ConditionalSwap", 0, 17)
+	a.I("MOVQ", "x+0(FP)", "DI")
+	a.I("MOVQ", "y+8(FP)", "SI")
+	a.I("MOVBQZX", "choice+16(FP)", "AX")
+	a.C("mask = 0 - choice")
+	a.I("NEGQ", "AX")
+	for i := 0; i < n; i++ {
+		a.I("MOVQ", off(i, "DI"), "BX")
+		a.I("MOVQ", off(i, "SI"), "CX")
+		a.I("MOVQ", "BX", "DX")
+		a.I("XORQ", "CX", "DX")
+		a.I("ANDQ", "AX", "DX")
+		a.I("XORQ", "DX", "BX")
+		a.I("XORQ", "DX", "CX")
+		a.I("MOVQ", "BX", off(i, "DI"))
+		a.I("MOVQ", "CX", off(i, "SI"))
+	}
+	a.ret()
+
+	z := func(i int) string { return off(i, "DI") }
+	x := func(i int) string { return off(i, "SI") }
+	y := func(i int) string { return off(i, "BX") }
+	loadArgs := func() {
+		a.I("MOVQ", "z+0(FP)", "DI")
+		a.I("MOVQ", "x+8(FP)", "SI")
+		a.I("MOVQ", "y+16(FP)", "BX")
+	}
+
+	// Addition modulo p
+	a.text(fn+"AddReduced", 8*n, 24)
+	loadArgs()
+	a.C("z = x + y")
+	a.amd64Chain("ADDQ", "ADCQ", z, x, y, 0, n)
+	a.amd64CondSub(f, bound, "DI")
+	a.ret()
+
+	// Subtraction modulo p
+	a.text(fn+"SubReduced", 8*n, 24)
+	loadArgs()
+	a.C("z = x - y")
+	a.amd64Chain("SUBQ", "SBBQ", z, x, y, 0, n)
+	a.amd64AddBack(f, bound, "DI", 0)
+	a.ret()
+
+	// Additions without reduction
+	a.text(fn+"AddLazy", 0, 24)
+	loadArgs()
+	a.amd64Chain("ADDQ", "ADCQ", z, x, y, 0, n)
+	a.ret()
+
+	a.text(fn+"X2AddLazy", 0, 24)
+	loadArgs()
+	a.amd64Chain("ADDQ", "ADCQ", z, x, y, 0, 2*n)
+	a.ret()
+
+	// Subtraction of double-length values, p*R is added on borrow
+	a.text(fn+"X2SubLazy", 8*n, 24)
+	loadArgs()
+	a.C("z = x - y")
+	a.amd64Chain("SUBQ", "SBBQ", z, x, y, 0, 2*n)
+	a.amd64AddBack(f, p, "DI", n)
+	a.ret()
+
+	// Reduction from [0, 2p) to [0, p)
+	a.text(fn+"StrongReduce", 8*n, 8)
+	a.I("MOVQ", "x+0(FP)", "DI")
+	a.amd64CondSub(f, p, "DI")
+	a.ret()
+
+	// Multiplication, product scanning
+	a.text(fn+"Mul", 0, 24)
+	loadArgs()
+	acc := amd64Acc{"R8", "R9", "R10"}
+	for _, r := range acc {
+		a.I("XORQ", r, r)
+	}
+	for k := 0; k < 2*n-1; k++ {
+		a.C("z[%d]", k)
+		for j := maxInt(0, k-n+1); j <= minInt(k, n-1); j++ {
+			acc.mulAdd(a, x(j), y(k-j))
+		}
+		a.I("MOVQ", acc[0], z(k))
+		acc.rotate(a)
+	}
+	a.I("MOVQ", acc[0], z(2*n-1))
+	a.ret()
+
+	// Montgomery reduction, product scanning. Quotients are kept on
+	// the stack.
+	frame := 8 * n
+	if !f.Fp2 {
+		// space for masked p used by final subtraction
+		frame += 8 * n
+	}
+	a.text(fn+"MontgomeryReduce", frame, 16)
+	a.I("MOVQ", "z+0(FP)", "DI")
+	a.I("MOVQ", "x+8(FP)", "SI")
+	if f.MPrime() != 1 {
+		a.I("MOVQ", fmt.Sprintf("$0x%016X", f.MPrime()), "R11")
+	}
+	acc = amd64Acc{"R8", "R9", "R10"}
+	for _, r := range acc {
+		a.I("XORQ", r, r)
+	}
+	m := func(i int) string { return fmt.Sprintf("m%d-%d(SP)", i, 8*(i+1)+8*n*b2i(!f.Fp2)) }
+	for k := 0; k < n; k++ {
+		a.C("m[%d]", k)
+		for j := 0; j < k; j++ {
+			acc.mulAdd(a, m(j), sym(p, k-j))
+		}
+		acc.add(a, x(k))
+		a.I("MOVQ", acc[0], "AX")
+		if f.MPrime() != 1 {
+			a.I("IMULQ", "R11", "AX")
+		}
+		a.I("MOVQ", "AX", m(k))
+		a.I("MULQ", sym(p, 0))
+		a.I("ADDQ", "AX", acc[0])
+		a.I("ADCQ", "DX", acc[1])
+		a.I("ADCQ", "$0", acc[2])
+		acc.rotate(a)
+	}
+	for k := n; k < 2*n-1; k++ {
+		a.C("z[%d]", k-n)
+		for j := k - n + 1; j < n; j++ {
+			acc.mulAdd(a, m(j), sym(p, k-j))
+		}
+		acc.add(a, x(k))
+		a.I("MOVQ", acc[0], z(k-n))
+		acc.rotate(a)
+	}
+	a.I("ADDQ", x(2*n-1), acc[0])
+	a.I("MOVQ", acc[0], z(n-1))
+	if !f.Fp2 {
+		a.amd64CondSub(f, p, "DI")
+	}
+	a.ret()
+
+	return a.Bytes(), nil
+}
+
+// ---------------------------------------------------------------------------
+// arm64
+// ---------------------------------------------------------------------------
+
+// Multi-word chains use ADDS/ADCS and SUBS/SBCS. Loads, stores and AND
+// don't modify flags, so masks can be applied inside of the chain. Montgomery
+// quotients are kept in registers.
+
+// Registers available for Montgomery quotients
+var arm64MRegs = []string{"R12", "R13", "R14", "R15", "R16", "R17",
+	"R19", "R20", "R21", "R22", "R23", "R24", "R25"}
+
+// arm64Chain emits z[i] = x[i] op y[i] for i in [from, to), propagating carry.
+// If mask is not empty, y[i] is ANDed with mask first.
+func (a *asm) arm64Chain(op, opc string, z, x, y func(int) string, from, to int, mask string) {
+	for i := from; i < to; i++ {
+		a.I("MOVD", x(i), "R3")
+		a.I("MOVD", y(i), "R4")
+		if mask != "" {
+			a.I("AND", mask, "R4", "R4")
+		}
+		if i == from {
+			a.I(op, "R4", "R3", "R3")
+		} else {
+			a.I(opc, "R4", "R3", "R3")
+		}
+		a.I("MOVD", "R3", z(i))
+	}
+}
+
+// arm64AddBack emits z = z + (c & mask), where mask is all ones in case
+// of borrow from previous instruction.
+func (a *asm) arm64AddBack(f *field, c, reg string, first int) {
+	mem := func(i int) string { return off(i, reg) }
+	a.C("if %s<0 add %s back", reg, c)
+	a.I("SBC", "ZR", "ZR", "R5")
+	a.arm64Chain("ADDS", "ADCS", mem, mem,
+		func(i int) string { return sym(c, i-first) }, first, first+f.Words, "R5")
+}
+
+// arm64CondSub emits z = z - c; if z < 0 then z = z + c
+func (a *asm) arm64CondSub(f *field, c, reg string) {
+	mem := func(i int) string { return off(i, reg) }
+	a.C("%s = %s - %s", reg, reg, c)
+	a.arm64Chain("SUBS", "SBCS", mem, mem, func(i int) string { return sym(c, i) }, 0, f.Words, "")
+	a.arm64AddBack(f, c, reg, 0)
+}
+
+// arm64Acc is a column accumulator (R10:R9:R8) with rotating registers
+type arm64Acc [3]string
+
+func (c *arm64Acc) rotate(a *asm) {
+	c[0], c[1], c[2] = c[1], c[2], c[0]
+	a.I("MOVD", "ZR", c[2])
+}
+
+// mulAdd emits acc += x * y, where x and y are registers
+func (c *arm64Acc) mulAdd(a *asm, x, y string) {
+	a.I("MUL", y, x, "R6")
+	a.I("UMULH", y, x, "R7")
+	a.I("ADDS", "R6", c[0], c[0])
+	a.I("ADCS", "R7", c[1], c[1])
+	a.I("ADC", "ZR", c[2], c[2])
+}
+
+// add emits acc += x, where x is a register
+func (c *arm64Acc) add(a *asm, x string) {
+	a.I("ADDS", x, c[0], c[0])
+	a.I("ADCS", "ZR", c[1], c[1])
+	a.I("ADC", "ZR", c[2], c[2])
+}
+
+func genArm64(f *field) ([]byte, error) {
+	n := f.Words
+	if n > len(arm64MRegs) {
+		return nil, fmt.Errorf("at most %d words supported", len(arm64MRegs))
+	}
+	fn, p, bound := f.Fn(), f.Name, f.Bound()
+	a := &asm{}
+	a.WriteString(asmHeader("arm64,!noasm"))
+
+	// Conditional swap
+	a.text(fn+"ConditionalSwap", 0, 17)
+	a.I("MOVD", "x+0(FP)", "R0")
+	a.I("MOVD", "y+8(FP)", "R1")
+	a.I("MOVBU", "choice+16(FP)", "R2")
+	a.C("mask = 0 - choice")
+	a.I("NEG", "R2", "R2")
+	for i := 0; i < n; i++ {
+		a.I("MOVD", off(i, "R0"), "R3")
+		a.I("MOVD", off(i, "R1"), "R4")
+		a.I("EOR", "R3", "R4", "R5")
+		a.I("AND", "R2", "R5", "R5")
+		a.I("EOR", "R5", "R3", "R3")
+		a.I("EOR", "R5", "R4", "R4")
+		a.I("MOVD", "R3", off(i, "R0"))
+		a.I("MOVD", "R4", off(i, "R1"))
+	}
+	a.ret()
+
+	z := func(i int) string { return off(i, "R0") }
+	x := func(i int) string { return off(i, "R1") }
+	y := func(i int) string { return off(i, "R2") }
+	loadArgs := func() {
+		a.I("MOVD", "z+0(FP)", "R0")
+		a.I("MOVD", "x+8(FP)", "R1")
+		a.I("MOVD", "y+16(FP)", "R2")
+	}
+
+	a.text(fn+"AddReduced", 0, 24)
+	loadArgs()
+	a.C("z = x + y")
+	a.arm64Chain("ADDS", "ADCS", z, x, y, 0, n, "")
+	a.arm64CondSub(f, bound, "R0")
+	a.ret()
+
+	a.text(fn+"SubReduced", 0, 24)
+	loadArgs()
+	a.C("z = x - y")
+	a.arm64Chain("SUBS", "SBCS", z, x, y, 0, n, "")
+	a.arm64AddBack(f, bound, "R0", 0)
+	a.ret()
+
+	a.text(fn+"AddLazy", 0, 24)
+	loadArgs()
+	a.arm64Chain("ADDS", "ADCS", z, x, y, 0, n, "")
+	a.ret()
+
+	a.text(fn+"X2AddLazy", 0, 24)
+	loadArgs()
+	a.arm64Chain("ADDS", "ADCS", z, x, y, 0, 2*n, "")
+	a.ret()
+
+	a.text(fn+"X2SubLazy", 0, 24)
+	loadArgs()
+	a.C("z = x - y")
+	a.arm64Chain("SUBS", "SBCS", z, x, y, 0, 2*n, "")
+	a.arm64AddBack(f, p, "R0", n)
+	a.ret()
+
+	a.text(fn+"StrongReduce", 0, 8)
+	a.I("MOVD", "x+0(FP)", "R0")
+	a.arm64CondSub(f, p, "R0")
+	a.ret()
+
+	// Multiplication, product scanning
+	a.text(fn+"Mul", 0, 24)
+	loadArgs()
+	acc := arm64Acc{"R8", "R9", "R10"}
+	for _, r := range acc {
+		a.I("MOVD", "ZR", r)
+	}
+	for k := 0; k < 2*n-1; k++ {
+		a.C("z[%d]", k)
+		for j := maxInt(0, k-n+1); j <= minInt(k, n-1); j++ {
+			a.I("MOVD", x(j), "R3")
+			a.I("MOVD", y(k-j), "R4")
+			acc.mulAdd(a, "R3", "R4")
+		}
+		a.I("MOVD", acc[0], z(k))
+		acc.rotate(a)
+	}
+	a.I("MOVD", acc[0], z(2*n-1))
+	a.ret()
+
+	// Montgomery reduction, product scanning
+	a.text(fn+"MontgomeryReduce", 0, 16)
+	a.I("MOVD", "z+0(FP)", "R0")
+	a.I("MOVD", "x+8(FP)", "R1")
+	if f.MPrime() != 1 {
+		a.I("MOVD", fmt.Sprintf("$0x%016X", f.MPrime()), "R11")
+	}
+	acc = arm64Acc{"R8", "R9", "R10"}
+	for _, r := range acc {
+		a.I("MOVD", "ZR", r)
+	}
+	m := arm64MRegs
+	for k := 0; k < n; k++ {
+		a.C("m[%d]", k)
+		for j := 0; j < k; j++ {
+			a.I("MOVD", sym(p, k-j), "R4")
+			acc.mulAdd(a, m[j], "R4")
+		}
+		a.I("MOVD", x(k), "R3")
+		acc.add(a, "R3")
+		if f.MPrime() != 1 {
+			a.I("MUL", "R11", acc[0], m[k])
+		} else {
+			a.I("MOVD", acc[0], m[k])
+		}
+		a.I("MOVD", sym(p, 0), "R4")
+		acc.mulAdd(a, m[k], "R4")
+		acc.rotate(a)
+	}
+	for k := n; k < 2*n-1; k++ {
+		a.C("z[%d]", k-n)
+		for j := k - n + 1; j < n; j++ {
+			a.I("MOVD", sym(p, k-j), "R4")
+			acc.mulAdd(a, m[j], "R4")
+		}
+		a.I("MOVD", x(k), "R3")
+		acc.add(a, "R3")
+		a.I("MOVD", acc[0], z(k-n))
+		acc.rotate(a)
+	}
+	a.I("MOVD", x(2*n-1), "R3")
+	a.I("ADD", "R3", acc[0], acc[0])
+	a.I("MOVD", acc[0], z(n-1))
+	if !f.Fp2 {
+		a.arm64CondSub(f, p, "R0")
+	}
+	a.ret()
+
+	return a.Bytes(), nil
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
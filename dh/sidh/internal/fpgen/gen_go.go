@@ -0,0 +1,622 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+)
+
+// Go source of generated files. Each template is executed with *field
+// as data and the result is gofmt'ed.
+
+const genHeader = `// Code generated by fpgen. DO NOT EDIT.
+{{- if .Tags}}
+
+//go:build {{goBuild .Tags}}
+// +build {{.Tags}}
+{{- end}}
+
+package {{.F.Pkg}}
+{{- if .F.Fp2}}
+
+import (
+	. "github.com/henrydcase/nobs/dh/sidh/internal/isogeny"
+)
+{{- end}}
+`
+
+const constsTmpl = `
+const (
+	// Number of limbs for a field element
+	{{.NumWords}} = {{.Words}}
+	// ceil({{.Bits}}/8)
+	{{.Bytelen}} = {{bytelen .Bits}}
+	// -p^(-1) mod 2^64, used by Montgomery reduction
+	{{.Name}}MPrime = {{printf "0x%016X" .MPrime}}
+)
+{{if not .Fp2}}
+// Element of the prime field. Values are in Montgomery domain and
+// fully reduced to [0, p).
+type {{.Fp}} [{{.NumWords}}]uint64
+
+// Represents an intermediate product of two field elements.
+type {{.FpX2}} [2 * {{.NumWords}}]uint64
+{{end}}
+// {{.Name}} = {{.Spec}}
+var {{.Name}} = {{.Fp}}{
+{{words .PWords}}}
+{{if .Fp2}}
+// 2*{{.Name}}
+var {{.Name}}x2 = {{.Fp}}{
+{{words .P2Words}}}
+{{end}}
+// R^2=(2^{{mul 64 .Words}})^2 mod p
+var {{.Name}}R2 = {{.Fp}}{
+{{words .R2Words}}}
+
+// p-2, exponent used for inversion
+var {{.Name}}Minus2 = [{{.NumWords}}]uint64{
+{{words .PMinus2Words}}}
+{{if .Fp2}}
+// 1*R mod p
+var {{.Export}}OneFp2 = Fp2Element{
+	A: FpElement{
+{{words .OneWords}}},
+}
+
+// 1/2 * R mod p
+var {{.Export}}HalfFp2 = Fp2Element{
+	A: FpElement{
+{{words .HalfWords}}},
+}
+{{else}}
+// 1*R mod p
+var {{.Fn}}One = {{.Fp}}{
+{{words .OneWords}}}
+{{end}}`
+
+const declTmpl = `
+// If choice = 0, leave x,y unchanged. If choice = 1, set x,y = y,x.
+// If choice is neither 0 nor 1 then behaviour is undefined.
+// This function executes in constant time.
+//go:noescape
+func {{.Fn}}ConditionalSwap(x, y *{{.Fp}}, choice uint8)
+
+// Compute z = x + y (mod p).
+//go:noescape
+func {{.Fn}}AddReduced(z, x, y *{{.Fp}})
+
+// Compute z = x - y (mod p).
+//go:noescape
+func {{.Fn}}SubReduced(z, x, y *{{.Fp}})
+
+// Compute z = x + y, without reducing mod p.
+//go:noescape
+func {{.Fn}}AddLazy(z, x, y *{{.Fp}})
+
+// Compute z = x + y, without reducing mod p.
+//go:noescape
+func {{.Fn}}X2AddLazy(z, x, y *{{.FpX2}})
+
+// Compute z = x - y, without reducing mod p.
+//go:noescape
+func {{.Fn}}X2SubLazy(z, x, y *{{.FpX2}})
+
+// Reduce a field element in [0, 2*p) to one in [0,p).
+//go:noescape
+func {{.Fn}}StrongReduce(x *{{.Fp}})
+
+// Computes z = x * y.
+//go:noescape
+func {{.Fn}}Mul(z *{{.FpX2}}, x, y *{{.Fp}})
+
+// Computes the Montgomery reduction z = x R^{-1} (mod {{.BoundDesc}}).
+//go:noescape
+func {{.Fn}}MontgomeryReduce(z *{{.Fp}}, x *{{.FpX2}})
+`
+
+const genericTmpl = `
+import (
+	"math/bits"
+)
+
+// Compute z = x + y (mod p).
+func {{.Fn}}AddReduced(z, x, y *{{.Fp}}) {
+	var carry uint64
+
+	// z=x+y % {{.Name}}
+	for i := 0; i < {{.NumWords}}; i++ {
+		z[i], carry = bits.Add64(x[i], y[i], carry)
+	}
+
+	// z = z - {{.Bound}}
+	carry = 0
+	for i := 0; i < {{.NumWords}}; i++ {
+		z[i], carry = bits.Sub64(z[i], {{.Bound}}[i], carry)
+	}
+
+	// if z<0 add {{.Bound}} back
+	mask := uint64(0 - carry)
+	carry = 0
+	for i := 0; i < {{.NumWords}}; i++ {
+		z[i], carry = bits.Add64(z[i], {{.Bound}}[i]&mask, carry)
+	}
+}
+
+// Compute z = x - y (mod p).
+func {{.Fn}}SubReduced(z, x, y *{{.Fp}}) {
+	var borrow uint64
+
+	// z = x - y
+	for i := 0; i < {{.NumWords}}; i++ {
+		z[i], borrow = bits.Sub64(x[i], y[i], borrow)
+	}
+
+	// if z<0 add {{.Bound}} back
+	mask := uint64(0 - borrow)
+	borrow = 0
+	for i := 0; i < {{.NumWords}}; i++ {
+		z[i], borrow = bits.Add64(z[i], {{.Bound}}[i]&mask, borrow)
+	}
+}
+
+// Conditionally swaps bits in x and y in constant time.
+// mask indicates bits to be swapped (set bits are swapped)
+// For details see "Hackers Delight, 2.20"
+func {{.Fn}}ConditionalSwap(x, y *{{.Fp}}, mask uint8) {
+	var tmp, mask64 uint64
+
+	mask64 = 0 - uint64(mask)
+	for i := 0; i < {{.NumWords}}; i++ {
+		tmp = mask64 & (x[i] ^ y[i])
+		x[i] = tmp ^ x[i]
+		y[i] = tmp ^ y[i]
+	}
+}
+
+// Perform Montgomery reduction: set z = x R^{-1} (mod {{.BoundDesc}})
+// with R=2^{{mul 64 .Words}}. Requires x < p*R.
+func {{.Fn}}MontgomeryReduce(z *{{.Fp}}, x *{{.FpX2}}) {
+	var m [{{.NumWords}}]uint64
+	var t, u, v, hi, lo, carry uint64
+
+	// Product scanning, (t,u,v) is a column accumulator
+	for i := 0; i < {{.NumWords}}; i++ {
+		for j := 0; j < i; j++ {
+			hi, lo = bits.Mul64(m[j], {{.Name}}[i-j])
+			v, carry = bits.Add64(v, lo, 0)
+			u, carry = bits.Add64(u, hi, carry)
+			t += carry
+		}
+		v, carry = bits.Add64(v, x[i], 0)
+		u, carry = bits.Add64(u, 0, carry)
+		t += carry
+
+		// m[i] is chosen so that lowest word of accumulator becomes 0
+		m[i] = v * {{.Name}}MPrime
+		hi, lo = bits.Mul64(m[i], {{.Name}}[0])
+		v, carry = bits.Add64(v, lo, 0)
+		u, carry = bits.Add64(u, hi, carry)
+		t += carry
+
+		v, u, t = u, t, 0
+	}
+
+	for i := {{.NumWords}}; i < 2*{{.NumWords}}-1; i++ {
+		for j := i - {{.NumWords}} + 1; j < {{.NumWords}}; j++ {
+			hi, lo = bits.Mul64(m[j], {{.Name}}[i-j])
+			v, carry = bits.Add64(v, lo, 0)
+			u, carry = bits.Add64(u, hi, carry)
+			t += carry
+		}
+		v, carry = bits.Add64(v, x[i], 0)
+		u, carry = bits.Add64(u, 0, carry)
+		t += carry
+
+		z[i-{{.NumWords}}] = v
+		v, u, t = u, t, 0
+	}
+	z[{{.NumWords}}-1] = v + x[2*{{.NumWords}}-1]
+{{- if not .Fp2}}
+
+	// z < 2*p, reduce to [0, p)
+	{{.Fn}}StrongReduce(z)
+{{- end}}
+}
+
+// Compute z = x + y, without reducing mod p.
+func {{.Fn}}AddLazy(z, x, y *{{.Fp}}) {
+	var carry uint64
+	for i := 0; i < {{.NumWords}}; i++ {
+		z[i], carry = bits.Add64(x[i], y[i], carry)
+	}
+}
+
+// Compute z = x + y, without reducing mod p.
+func {{.Fn}}X2AddLazy(z, x, y *{{.FpX2}}) {
+	var carry uint64
+	for i := 0; i < 2*{{.NumWords}}; i++ {
+		z[i], carry = bits.Add64(x[i], y[i], carry)
+	}
+}
+
+// Reduce a field element in [0, 2*p) to one in [0,p).
+func {{.Fn}}StrongReduce(x *{{.Fp}}) {
+	var borrow, mask uint64
+	for i := 0; i < {{.NumWords}}; i++ {
+		x[i], borrow = bits.Sub64(x[i], {{.Name}}[i], borrow)
+	}
+
+	// Mask is 0 if x>=p, otherwise -1
+	mask = 0 - borrow
+	borrow = 0
+	for i := 0; i < {{.NumWords}}; i++ {
+		x[i], borrow = bits.Add64(x[i], {{.Name}}[i]&mask, borrow)
+	}
+}
+
+// Compute z = x - y, without reducing mod p.
+func {{.Fn}}X2SubLazy(z, x, y *{{.FpX2}}) {
+	var borrow, mask uint64
+	for i := 0; i < 2*{{.NumWords}}; i++ {
+		z[i], borrow = bits.Sub64(x[i], y[i], borrow)
+	}
+
+	// if z<0, add p*R back
+	mask = 0 - borrow
+	borrow = 0
+	for i := {{.NumWords}}; i < 2*{{.NumWords}}; i++ {
+		z[i], borrow = bits.Add64(z[i], {{.Name}}[i-{{.NumWords}}]&mask, borrow)
+	}
+}
+
+// Compute z = x * y.
+func {{.Fn}}Mul(z *{{.FpX2}}, x, y *{{.Fp}}) {
+	var t, u, v, hi, lo, carry uint64
+
+	// Product scanning, (t,u,v) is a column accumulator
+	for i := 0; i < {{.NumWords}}; i++ {
+		for j := 0; j <= i; j++ {
+			hi, lo = bits.Mul64(x[j], y[i-j])
+			v, carry = bits.Add64(v, lo, 0)
+			u, carry = bits.Add64(u, hi, carry)
+			t += carry
+		}
+		z[i] = v
+		v, u, t = u, t, 0
+	}
+
+	for i := {{.NumWords}}; i < 2*{{.NumWords}}-1; i++ {
+		for j := i - {{.NumWords}} + 1; j < {{.NumWords}}; j++ {
+			hi, lo = bits.Mul64(x[j], y[i-j])
+			v, carry = bits.Add64(v, lo, 0)
+			u, carry = bits.Add64(u, hi, carry)
+			t += carry
+		}
+		z[i] = v
+		v, u, t = u, t, 0
+	}
+	z[2*{{.NumWords}}-1] = v
+}
+`
+
+const fpTmpl = `
+// Set z = x * y * R^{-1} (mod {{.BoundDesc}}).
+//
+// Allowed to overlap x or y with z.
+func {{.Fn}}MulRdc(z, x, y *{{.Fp}}) {
+	var t {{.FpX2}}
+	{{.Fn}}Mul(&t, x, y)
+	{{.Fn}}MontgomeryReduce(z, &t)
+}
+
+// Set z = x^e, where x is in Montgomery domain and e is little-endian
+// exponent. Uses fixed window of 4 bits. Exponent is assumed to be public,
+// execution time doesn't depend on x.
+//
+// Allowed to overlap x with z.
+func {{.Fn}}Exp(z, x *{{.Fp}}, e []uint64) {
+	var lookup [16]{{.Fp}}
+	var started bool
+
+	// lookup[i] = x^i
+	lookup[0] = {{.OneVar}}
+	lookup[1] = *x
+	for i := 2; i < 16; i++ {
+		{{.Fn}}MulRdc(&lookup[i], &lookup[i-1], x)
+	}
+
+	res := lookup[0]
+	for i := 16*len(e) - 1; i >= 0; i-- {
+		if started {
+			for j := 0; j < 4; j++ {
+				{{.Fn}}MulRdc(&res, &res, &res)
+			}
+		}
+		w := (e[i/16] >> (4 * uint(i%16))) & 0xF
+		if w != 0 {
+			{{.Fn}}MulRdc(&res, &res, &lookup[w])
+			started = true
+		}
+	}
+	*z = res
+}
+
+// Set z = 1/x (mod p), computed as x^(p-2). Inverse of 0 is 0.
+//
+// Allowed to overlap x with z.
+func {{.Fn}}Inv(z, x *{{.Fp}}) {
+	{{.Fn}}Exp(z, x, {{.Name}}Minus2[:])
+}
+
+// Converts x to Montgomery domain, z = x*R mod p.
+func {{.Fn}}ToMontgomery(z, x *{{.Fp}}) {
+	{{.Fn}}MulRdc(z, x, &{{.Name}}R2)
+}
+
+// Converts x from Montgomery domain, z = x*R^{-1} mod p. Result is
+// fully reduced to [0, p).
+func {{.Fn}}FromMontgomery(z, x *{{.Fp}}) {
+	var t {{.FpX2}}
+	copy(t[:], x[:{{.NumWords}}])
+	{{.Fn}}MontgomeryReduce(z, &t)
+	{{.Fn}}StrongReduce(z)
+}
+`
+
+const fieldOpsTmpl = `
+type {{.Fn}}Ops struct{}
+
+func FieldOperations() FieldOps {
+	return &{{.Fn}}Ops{}
+}
+
+func ({{.Fn}}Ops) Add(dest, lhs, rhs *Fp2Element) {
+	{{.Fn}}AddReduced(&dest.A, &lhs.A, &rhs.A)
+	{{.Fn}}AddReduced(&dest.B, &lhs.B, &rhs.B)
+}
+
+func ({{.Fn}}Ops) Sub(dest, lhs, rhs *Fp2Element) {
+	{{.Fn}}SubReduced(&dest.A, &lhs.A, &rhs.A)
+	{{.Fn}}SubReduced(&dest.B, &lhs.B, &rhs.B)
+}
+
+func ({{.Fn}}Ops) Mul(dest, lhs, rhs *Fp2Element) {
+	// Let (a,b,c,d) = (lhs.a,lhs.b,rhs.a,rhs.b).
+	a := &lhs.A
+	b := &lhs.B
+	c := &rhs.A
+	d := &rhs.B
+
+	// We want to compute
+	//
+	// (a + bi)*(c + di) = (a*c - b*d) + (a*d + b*c)i
+	//
+	// Use Karatsuba's trick: note that
+	//
+	// (b - a)*(c - d) = (b*c + a*d) - a*c - b*d
+	//
+	// so (a*d + b*c) = (b-a)*(c-d) + a*c + b*d.
+
+	var ac, bd FpElementX2
+	{{.Fn}}Mul(&ac, a, c) // = a*c*R*R
+	{{.Fn}}Mul(&bd, b, d) // = b*d*R*R
+
+	var b_minus_a, c_minus_d FpElement
+	{{.Fn}}SubReduced(&b_minus_a, b, a) // = (b-a)*R
+	{{.Fn}}SubReduced(&c_minus_d, c, d) // = (c-d)*R
+
+	var ad_plus_bc FpElementX2
+	{{.Fn}}Mul(&ad_plus_bc, &b_minus_a, &c_minus_d) // = (b-a)*(c-d)*R*R
+	{{.Fn}}X2AddLazy(&ad_plus_bc, &ad_plus_bc, &ac) // = ((b-a)*(c-d) + a*c)*R*R
+	{{.Fn}}X2AddLazy(&ad_plus_bc, &ad_plus_bc, &bd) // = ((b-a)*(c-d) + a*c + b*d)*R*R
+
+	{{.Fn}}MontgomeryReduce(&dest.B, &ad_plus_bc) // = (a*d + b*c)*R mod p
+
+	var ac_minus_bd FpElementX2
+	{{.Fn}}X2SubLazy(&ac_minus_bd, &ac, &bd)       // = (a*c - b*d)*R*R
+	{{.Fn}}MontgomeryReduce(&dest.A, &ac_minus_bd) // = (a*c - b*d)*R mod p
+}
+
+// Set dest = 1/x
+//
+// Allowed to overlap dest with x.
+func ({{.Fn}}Ops) Inv(dest, x *Fp2Element) {
+	a := &x.A
+	b := &x.B
+
+	// We want to compute
+	//
+	//    1          1     (a - bi)	    (a - bi)
+	// -------- = -------- -------- = -----------
+	// (a + bi)   (a + bi) (a - bi)   (a^2 + b^2)
+	//
+	// Letting c = 1/(a^2 + b^2), this is
+	//
+	// 1/(a+bi) = a*c - b*ci.
+
+	var inv FpElement
+	var asq, bsq FpElementX2
+	{{.Fn}}Mul(&asq, a, a)               // = a*a*R*R
+	{{.Fn}}Mul(&bsq, b, b)               // = b*b*R*R
+	{{.Fn}}X2AddLazy(&asq, &asq, &bsq)   // = (a^2 + b^2)*R*R
+	{{.Fn}}MontgomeryReduce(&inv, &asq) // = (a^2 + b^2)*R mod p
+	{{.Fn}}Inv(&inv, &inv)              // = (a^2 + b^2)^-1*R mod p
+
+	var ac FpElementX2
+	{{.Fn}}Mul(&ac, a, &inv)
+	{{.Fn}}MontgomeryReduce(&dest.A, &ac)
+
+	var minus_b FpElement
+	{{.Fn}}SubReduced(&minus_b, &minus_b, b)
+	var minus_bc FpElementX2
+	{{.Fn}}Mul(&minus_bc, &minus_b, &inv)
+	{{.Fn}}MontgomeryReduce(&dest.B, &minus_bc)
+}
+
+func ({{.Fn}}Ops) Square(dest, x *Fp2Element) {
+	a := &x.A
+	b := &x.B
+
+	// We want to compute
+	//
+	// (a + bi)*(a + bi) = (a^2 - b^2) + 2abi.
+
+	var a2, a_plus_b, a_minus_b FpElement
+	{{.Fn}}AddReduced(&a2, a, a)        // = a*R + a*R = 2*a*R
+	{{.Fn}}AddReduced(&a_plus_b, a, b)  // = a*R + b*R = (a+b)*R
+	{{.Fn}}SubReduced(&a_minus_b, a, b) // = a*R - b*R = (a-b)*R
+
+	var asq_minus_bsq, ab2 FpElementX2
+	{{.Fn}}Mul(&asq_minus_bsq, &a_plus_b, &a_minus_b) // = (a+b)*(a-b)*R*R = (a^2 - b^2)*R*R
+	{{.Fn}}Mul(&ab2, &a2, b)                          // = 2*a*b*R*R
+
+	{{.Fn}}MontgomeryReduce(&dest.A, &asq_minus_bsq) // = (a^2 - b^2)*R mod p
+	{{.Fn}}MontgomeryReduce(&dest.B, &ab2)           // = 2*a*b*R mod p
+}
+
+// In case choice == 1, performs following swap in constant time:
+// 	xPx <-> xQx
+//	xPz <-> xQz
+// Otherwise returns xPx, xPz, xQx, xQz unchanged
+func ({{.Fn}}Ops) CondSwap(xPx, xPz, xQx, xQz *Fp2Element, choice uint8) {
+	{{.Fn}}ConditionalSwap(&xPx.A, &xQx.A, choice)
+	{{.Fn}}ConditionalSwap(&xPx.B, &xQx.B, choice)
+	{{.Fn}}ConditionalSwap(&xPz.A, &xQz.A, choice)
+	{{.Fn}}ConditionalSwap(&xPz.B, &xQz.B, choice)
+}
+
+// Converts values in x.A and x.B to Montgomery domain
+// x.A = x.A * R mod p
+// x.B = x.B * R mod p
+func ({{.Fn}}Ops) ToMontgomery(x *Fp2Element) {
+	{{.Fn}}ToMontgomery(&x.A, &x.A)
+	{{.Fn}}ToMontgomery(&x.B, &x.B)
+}
+
+// Converts values in x.A and x.B from Montgomery domain
+// a = x.A mod p
+// b = x.B mod p
+//
+// After returning from the call x is not modified.
+func ({{.Fn}}Ops) FromMontgomery(x *Fp2Element, out *Fp2Element) {
+	{{.Fn}}FromMontgomery(&out.A, &x.A)
+	{{.Fn}}FromMontgomery(&out.B, &x.B)
+}
+`
+
+// Export returns exported form of the name, e.g. P434
+func (f *field) Export() string { return strings.ToUpper(f.Name[:1]) + f.Name[1:] }
+
+// Bytelen returns name of the constant keeping size of element in bytes
+func (f *field) Bytelen() string {
+	if f.Fp2 {
+		return f.Export() + "_Bytelen"
+	}
+	return f.Fn() + "Bytelen"
+}
+
+// OneVar returns expression evaluating to 1 in Montgomery domain
+func (f *field) OneVar() string {
+	if f.Fp2 {
+		return f.Export() + "OneFp2.A"
+	}
+	return f.Fn() + "One"
+}
+
+var funcs = template.FuncMap{
+	"mul":     func(a, b int) int { return a * b },
+	"bytelen": func(bits int) int { return (bits + 7) / 8 },
+	"goBuild": goBuildExpr,
+	"words": func(w []uint64) string {
+		var b strings.Builder
+		for i, v := range w {
+			if i%4 == 0 {
+				b.WriteString("\t")
+			} else {
+				b.WriteString(" ")
+			}
+			fmt.Fprintf(&b, "0x%016X,", v)
+			if i%4 == 3 || i == len(w)-1 {
+				b.WriteString("\n")
+			}
+		}
+		return b.String()
+	},
+}
+
+// goBuildExpr converts "+build" constraint to "go:build" expression
+func goBuildExpr(tags string) string {
+	var or []string
+	for _, opt := range strings.Fields(tags) {
+		and := strings.Split(opt, ",")
+		for i, t := range and {
+			if strings.HasPrefix(t, "!") {
+				and[i] = "!" + t[1:]
+			}
+		}
+		e := strings.Join(and, " && ")
+		if len(and) > 1 && len(strings.Fields(tags)) > 1 {
+			e = "(" + e + ")"
+		}
+		or = append(or, e)
+	}
+	return strings.Join(or, " || ")
+}
+
+// execGo renders Go file from the header and body template and formats it
+func execGo(f *field, tags, body string, imports bool) ([]byte, error) {
+	var buf bytes.Buffer
+	t := template.Must(template.New("").Funcs(funcs).Parse(genHeader))
+	hdr := struct {
+		F    *field
+		Tags string
+	}{f, tags}
+	// Dot-import of isogeny package is only used by files which refer to it
+	if !imports {
+		g := *f
+		g.Fp2 = false
+		hdr.F = &g
+	}
+	if err := t.Execute(&buf, hdr); err != nil {
+		return nil, err
+	}
+	t = template.Must(template.New("").Funcs(funcs).Parse(body))
+	if err := t.Execute(&buf, f); err != nil {
+		return nil, err
+	}
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%v\n%s", err, buf.Bytes())
+	}
+	return out, nil
+}
+
+const (
+	asmTags     = "amd64,!noasm arm64,!noasm"
+	genericTags = "noasm !amd64,!arm64"
+)
+
+func genConsts(f *field) ([]byte, error) { return execGo(f, "", constsTmpl, true) }
+func genDecl(f *field) ([]byte, error)   { return execGo(f, asmTags, declTmpl, true) }
+func genFp(f *field) ([]byte, error)     { return execGo(f, "", fpTmpl, true) }
+
+func genGeneric(f *field) ([]byte, error) {
+	src, err := execGo(f, genericTags, genericTmpl, false)
+	if err != nil || !f.Fp2 {
+		return src, err
+	}
+	return addIsogenyImport(src, "\"math/bits\"")
+}
+
+// addIsogenyImport adds dot-import of isogeny package to the import block
+// which ends with last.
+func addIsogenyImport(src []byte, last string) ([]byte, error) {
+	s := strings.Replace(string(src), last+"\n",
+		last+"\n\n\t. \"github.com/henrydcase/nobs/dh/sidh/internal/isogeny\"\n", 1)
+	return format.Source([]byte(s))
+}
+
+func genFieldOps(f *field) ([]byte, error) { return execGo(f, "", fieldOpsTmpl, true) }
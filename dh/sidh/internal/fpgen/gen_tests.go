@@ -0,0 +1,371 @@
+package main
+
+// Template of differential tests of generated arithmetic. Results are
+// compared against math/big.
+const testTmpl = `
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// Number of values used by tests. Each binary operation is checked
+// on all pairs.
+const {{.Fn}}TestValues = 40
+
+var (
+	{{.Fn}}BigP     = {{.Fn}}ToBig({{.Name}}[:{{.NumWords}}])
+	{{.Fn}}BigR     = new(big.Int).Lsh(big.NewInt(1), 64*{{.NumWords}})
+	{{.Fn}}BigBound = new(big.Int).Mul(big.NewInt({{if .Fp2}}2{{else}}1{{end}}), {{.Fn}}BigP)
+	{{.Fn}}BigPR    = new(big.Int).Mul({{.Fn}}BigP, {{.Fn}}BigR)
+	{{.Fn}}BigRInv  = new(big.Int).ModInverse({{.Fn}}BigR, {{.Fn}}BigP)
+)
+
+// Converts little-endian words to big.Int
+func {{.Fn}}ToBig(x []uint64) *big.Int {
+	b := new(big.Int)
+	for i := len(x) - 1; i >= 0; i-- {
+		b.Lsh(b, 64)
+		b.Or(b, new(big.Int).SetUint64(x[i]))
+	}
+	return b
+}
+
+// Converts b to little-endian words. Panics if b doesn't fit in x.
+func {{.Fn}}FromBig(x []uint64, b *big.Int) {
+	t := new(big.Int).Set(b)
+	mask := new(big.Int).SetUint64(^uint64(0))
+	for i := range x {
+		x[i] = new(big.Int).And(t, mask).Uint64()
+		t.Rsh(t, 64)
+	}
+	if t.Sign() != 0 {
+		panic("value too big")
+	}
+}
+
+// Returns values from [0, max), starting with edge cases
+func {{.Fn}}Values(rng *rand.Rand, max *big.Int) []*big.Int {
+	one := big.NewInt(1)
+	var vals []*big.Int
+	for _, v := range []*big.Int{
+		big.NewInt(0),
+		one,
+		new(big.Int).Sub({{.Fn}}BigP, one),
+		{{.Fn}}BigP,
+		new(big.Int).Add({{.Fn}}BigP, one),
+		new(big.Int).Sub(max, one),
+	} {
+		if v.Cmp(max) < 0 {
+			vals = append(vals, v)
+		}
+	}
+	for len(vals) < {{.Fn}}TestValues {
+		vals = append(vals, new(big.Int).Rand(rng, max))
+	}
+	return vals
+}
+
+// Checks if got is equal to exp
+func {{.Fn}}CheckEq(t *testing.T, op string, got []uint64, exp *big.Int, args ...*big.Int) {
+	if g := {{.Fn}}ToBig(got); g.Cmp(exp) != 0 {
+		t.Errorf("%s%x:\n got: %x\n exp: %x", op, args, g, exp)
+	}
+}
+
+// Checks if got is in [0, bound) and congruent to exp modulo p
+func {{.Fn}}CheckMod(t *testing.T, op string, got []uint64, exp *big.Int, args ...*big.Int) {
+	g := {{.Fn}}ToBig(got)
+	d := new(big.Int).Sub(g, exp)
+	if g.Cmp({{.Fn}}BigBound) >= 0 || d.Mod(d, {{.Fn}}BigP).Sign() != 0 {
+		t.Errorf("%s%x:\n got: %x\n exp: %x (mod p)", op, args, g, exp)
+	}
+}
+
+// Runs f on all pairs of values from [0, max)
+func {{.Fn}}ForPairs(max *big.Int, f func(a, b *big.Int)) {
+	vals := {{.Fn}}Values(rand.New(rand.NewSource(1)), max)
+	for _, a := range vals {
+		for _, b := range vals {
+			f(a, b)
+		}
+	}
+}
+
+func Test{{.Export}}Constants(t *testing.T) {
+	if !{{.Fn}}BigP.ProbablyPrime(20) {
+		t.Error("p is not prime")
+	}
+	m := new(big.Int).SetUint64({{.Name}}MPrime)
+	m.Mul(m, {{.Fn}}BigP).Add(m, big.NewInt(1))
+	if m.Uint64() != 0 {
+		t.Error("p*mprime != -1 mod 2^64")
+	}
+	r2 := new(big.Int).Mul({{.Fn}}BigR, {{.Fn}}BigR)
+	{{.Fn}}CheckEq(t, "R2", {{.Name}}R2[:{{.NumWords}}], r2.Mod(r2, {{.Fn}}BigP))
+	{{.Fn}}CheckEq(t, "One", {{.OneVar}}[:{{.NumWords}}], new(big.Int).Mod({{.Fn}}BigR, {{.Fn}}BigP))
+{{- if .Fp2}}
+	{{.Fn}}CheckEq(t, "2p", {{.Name}}x2[:{{.NumWords}}], new(big.Int).Lsh({{.Fn}}BigP, 1))
+	half := new(big.Int).ModInverse(big.NewInt(2), {{.Fn}}BigP)
+	half.Mul(half, {{.Fn}}BigR).Mod(half, {{.Fn}}BigP)
+	{{.Fn}}CheckEq(t, "Half", {{.Export}}HalfFp2.A[:{{.NumWords}}], half)
+{{- end}}
+}
+
+func Test{{.Export}}AddReduced(t *testing.T) {
+	var x, y, z {{.Fp}}
+	{{.Fn}}ForPairs({{.Fn}}BigBound, func(a, b *big.Int) {
+		{{.Fn}}FromBig(x[:{{.NumWords}}], a)
+		{{.Fn}}FromBig(y[:{{.NumWords}}], b)
+		{{.Fn}}AddReduced(&z, &x, &y)
+		{{.Fn}}CheckMod(t, "AddReduced", z[:{{.NumWords}}], new(big.Int).Add(a, b), a, b)
+	})
+}
+
+func Test{{.Export}}SubReduced(t *testing.T) {
+	var x, y, z {{.Fp}}
+	{{.Fn}}ForPairs({{.Fn}}BigBound, func(a, b *big.Int) {
+		{{.Fn}}FromBig(x[:{{.NumWords}}], a)
+		{{.Fn}}FromBig(y[:{{.NumWords}}], b)
+		{{.Fn}}SubReduced(&z, &x, &y)
+		{{.Fn}}CheckMod(t, "SubReduced", z[:{{.NumWords}}], new(big.Int).Sub(a, b), a, b)
+	})
+}
+
+func Test{{.Export}}AddLazy(t *testing.T) {
+	var x, y, z {{.Fp}}
+	{{.Fn}}ForPairs({{.Fn}}BigBound, func(a, b *big.Int) {
+		{{.Fn}}FromBig(x[:{{.NumWords}}], a)
+		{{.Fn}}FromBig(y[:{{.NumWords}}], b)
+		{{.Fn}}AddLazy(&z, &x, &y)
+		{{.Fn}}CheckEq(t, "AddLazy", z[:{{.NumWords}}], new(big.Int).Add(a, b), a, b)
+	})
+}
+
+func Test{{.Export}}X2AddLazy(t *testing.T) {
+	var x, y, z {{.FpX2}}
+	{{.Fn}}ForPairs({{.Fn}}BigPR, func(a, b *big.Int) {
+		{{.Fn}}FromBig(x[:2*{{.NumWords}}], a)
+		{{.Fn}}FromBig(y[:2*{{.NumWords}}], b)
+		{{.Fn}}X2AddLazy(&z, &x, &y)
+		{{.Fn}}CheckEq(t, "X2AddLazy", z[:2*{{.NumWords}}], new(big.Int).Add(a, b), a, b)
+	})
+}
+
+func Test{{.Export}}X2SubLazy(t *testing.T) {
+	var x, y, z {{.FpX2}}
+	{{.Fn}}ForPairs({{.Fn}}BigPR, func(a, b *big.Int) {
+		{{.Fn}}FromBig(x[:2*{{.NumWords}}], a)
+		{{.Fn}}FromBig(y[:2*{{.NumWords}}], b)
+		{{.Fn}}X2SubLazy(&z, &x, &y)
+		// Negative result is shifted by p*R
+		exp := new(big.Int).Sub(a, b)
+		if exp.Sign() < 0 {
+			exp.Add(exp, {{.Fn}}BigPR)
+		}
+		{{.Fn}}CheckEq(t, "X2SubLazy", z[:2*{{.NumWords}}], exp, a, b)
+	})
+}
+
+func Test{{.Export}}StrongReduce(t *testing.T) {
+	var x {{.Fp}}
+	max := new(big.Int).Lsh({{.Fn}}BigP, 1)
+	for _, a := range {{.Fn}}Values(rand.New(rand.NewSource(1)), max) {
+		{{.Fn}}FromBig(x[:{{.NumWords}}], a)
+		{{.Fn}}StrongReduce(&x)
+		{{.Fn}}CheckEq(t, "StrongReduce", x[:{{.NumWords}}], new(big.Int).Mod(a, {{.Fn}}BigP), a)
+	}
+}
+
+func Test{{.Export}}ConditionalSwap(t *testing.T) {
+	var x, y {{.Fp}}
+	{{.Fn}}ForPairs({{.Fn}}BigBound, func(a, b *big.Int) {
+		{{.Fn}}FromBig(x[:{{.NumWords}}], a)
+		{{.Fn}}FromBig(y[:{{.NumWords}}], b)
+		{{.Fn}}ConditionalSwap(&x, &y, 0)
+		{{.Fn}}CheckEq(t, "ConditionalSwap(0)", x[:{{.NumWords}}], a, a, b)
+		{{.Fn}}CheckEq(t, "ConditionalSwap(0)", y[:{{.NumWords}}], b, a, b)
+		{{.Fn}}ConditionalSwap(&x, &y, 1)
+		{{.Fn}}CheckEq(t, "ConditionalSwap(1)", x[:{{.NumWords}}], b, a, b)
+		{{.Fn}}CheckEq(t, "ConditionalSwap(1)", y[:{{.NumWords}}], a, a, b)
+	})
+}
+
+func Test{{.Export}}Mul(t *testing.T) {
+	var x, y {{.Fp}}
+	var z {{.FpX2}}
+	{{.Fn}}ForPairs({{.Fn}}BigBound, func(a, b *big.Int) {
+		{{.Fn}}FromBig(x[:{{.NumWords}}], a)
+		{{.Fn}}FromBig(y[:{{.NumWords}}], b)
+		{{.Fn}}Mul(&z, &x, &y)
+		{{.Fn}}CheckEq(t, "Mul", z[:2*{{.NumWords}}], new(big.Int).Mul(a, b), a, b)
+	})
+}
+
+func Test{{.Export}}MontgomeryReduce(t *testing.T) {
+	var x {{.FpX2}}
+	var z {{.Fp}}
+	for _, a := range {{.Fn}}Values(rand.New(rand.NewSource(1)), {{.Fn}}BigPR) {
+		{{.Fn}}FromBig(x[:2*{{.NumWords}}], a)
+		{{.Fn}}MontgomeryReduce(&z, &x)
+		exp := new(big.Int).Mul(a, {{.Fn}}BigRInv)
+		{{.Fn}}CheckMod(t, "MontgomeryReduce", z[:{{.NumWords}}], exp, a)
+	}
+}
+
+func Test{{.Export}}MulRdc(t *testing.T) {
+	var x, y, z {{.Fp}}
+	{{.Fn}}ForPairs({{.Fn}}BigBound, func(a, b *big.Int) {
+		{{.Fn}}FromBig(x[:{{.NumWords}}], a)
+		{{.Fn}}FromBig(y[:{{.NumWords}}], b)
+		{{.Fn}}MulRdc(&z, &x, &y)
+		exp := new(big.Int).Mul(a, b)
+		exp.Mul(exp, {{.Fn}}BigRInv)
+		{{.Fn}}CheckMod(t, "MulRdc", z[:{{.NumWords}}], exp, a, b)
+	})
+}
+
+func Test{{.Export}}Inv(t *testing.T) {
+	var x, z {{.Fp}}
+	for _, a := range {{.Fn}}Values(rand.New(rand.NewSource(1)), {{.Fn}}BigBound) {
+		{{.Fn}}FromBig(x[:{{.NumWords}}], a)
+		{{.Fn}}Inv(&z, &x)
+
+		// x = a*R, so expected result is a^-1*R = R^2/x
+		exp := new(big.Int).Mul({{.Fn}}BigR, {{.Fn}}BigR)
+		if inv := new(big.Int).ModInverse(a, {{.Fn}}BigP); inv != nil {
+			exp.Mul(exp, inv)
+		} else {
+			exp.SetInt64(0)
+		}
+		{{.Fn}}CheckMod(t, "Inv", z[:{{.NumWords}}], exp, a)
+	}
+}
+
+func Test{{.Export}}MontgomeryRoundTrip(t *testing.T) {
+	var x, z {{.Fp}}
+	for _, a := range {{.Fn}}Values(rand.New(rand.NewSource(1)), {{.Fn}}BigP) {
+		{{.Fn}}FromBig(x[:{{.NumWords}}], a)
+		{{.Fn}}ToMontgomery(&z, &x)
+		exp := new(big.Int).Mul(a, {{.Fn}}BigR)
+		{{.Fn}}CheckMod(t, "ToMontgomery", z[:{{.NumWords}}], exp, a)
+		{{.Fn}}FromMontgomery(&z, &z)
+		{{.Fn}}CheckEq(t, "FromMontgomery", z[:{{.NumWords}}], a, a)
+	}
+}
+{{if .Fp2}}
+// Sets x = a + bi, converted to Montgomery domain
+func {{.Fn}}Fp2FromBig(x *Fp2Element, a, b *big.Int) {
+	*x = Fp2Element{}
+	{{.Fn}}FromBig(x.A[:{{.NumWords}}], a)
+	{{.Fn}}FromBig(x.B[:{{.NumWords}}], b)
+	FieldOperations().ToMontgomery(x)
+}
+
+// Checks if x = a + bi
+func {{.Fn}}CheckFp2(t *testing.T, op string, x *Fp2Element, a, b *big.Int, args ...*big.Int) {
+	var out Fp2Element
+	FieldOperations().FromMontgomery(x, &out)
+	{{.Fn}}CheckEq(t, op+".A", out.A[:{{.NumWords}}], a.Mod(a, {{.Fn}}BigP), args...)
+	{{.Fn}}CheckEq(t, op+".B", out.B[:{{.NumWords}}], b.Mod(b, {{.Fn}}BigP), args...)
+}
+
+func Test{{.Export}}FieldOps(t *testing.T) {
+	var x, y, z Fp2Element
+	op := FieldOperations()
+	rng := rand.New(rand.NewSource(1))
+	vals := {{.Fn}}Values(rng, {{.Fn}}BigP)
+
+	for i := range vals {
+		a, b := vals[i], vals[(i+1)%len(vals)]
+		c, d := vals[(i+7)%len(vals)], vals[(i+13)%len(vals)]
+		{{.Fn}}Fp2FromBig(&x, a, b)
+		{{.Fn}}Fp2FromBig(&y, c, d)
+
+		op.Add(&z, &x, &y)
+		{{.Fn}}CheckFp2(t, "Add", &z, new(big.Int).Add(a, c), new(big.Int).Add(b, d), a, b, c, d)
+
+		op.Sub(&z, &x, &y)
+		{{.Fn}}CheckFp2(t, "Sub", &z, new(big.Int).Sub(a, c), new(big.Int).Sub(b, d), a, b, c, d)
+
+		// (a + bi)*(c + di) = (a*c - b*d) + (a*d + b*c)i
+		ac, bd := new(big.Int).Mul(a, c), new(big.Int).Mul(b, d)
+		ad, bc := new(big.Int).Mul(a, d), new(big.Int).Mul(b, c)
+		op.Mul(&z, &x, &y)
+		{{.Fn}}CheckFp2(t, "Mul", &z, ac.Sub(ac, bd), ad.Add(ad, bc), a, b, c, d)
+
+		// (a + bi)^2 = (a^2 - b^2) + 2abi
+		aa, bb := new(big.Int).Mul(a, a), new(big.Int).Mul(b, b)
+		ab2 := new(big.Int).Mul(a, b)
+		op.Square(&z, &x)
+		{{.Fn}}CheckFp2(t, "Square", &z, aa.Sub(aa, bb), ab2.Lsh(ab2, 1), a, b)
+
+		// 1/(a + bi) = (a - bi)/(a^2 + b^2)
+		n := new(big.Int).Mul(a, a)
+		n.Add(n, new(big.Int).Mul(b, b))
+		if n.ModInverse(n, {{.Fn}}BigP) == nil {
+			n.SetInt64(0)
+		}
+		op.Inv(&z, &x)
+		{{.Fn}}CheckFp2(t, "Inv", &z, new(big.Int).Mul(a, n), new(big.Int).Neg(new(big.Int).Mul(b, n)), a, b)
+
+		// Overlapping arguments
+		z = x
+		op.Mul(&z, &z, &z)
+		aa, bb = new(big.Int).Mul(a, a), new(big.Int).Mul(b, b)
+		ab2 = new(big.Int).Mul(a, b)
+		{{.Fn}}CheckFp2(t, "Mul(x,x)", &z, aa.Sub(aa, bb), ab2.Lsh(ab2, 1), a, b)
+
+		xPx, xPz, xQx, xQz := x, y, y, x
+		op.CondSwap(&xPx, &xPz, &xQx, &xQz, 0)
+		{{.Fn}}CheckFp2(t, "CondSwap(0)", &xPx, new(big.Int).Set(a), new(big.Int).Set(b), a, b, c, d)
+		op.CondSwap(&xPx, &xPz, &xQx, &xQz, 1)
+		{{.Fn}}CheckFp2(t, "CondSwap(1)", &xPx, new(big.Int).Set(c), new(big.Int).Set(d), a, b, c, d)
+		{{.Fn}}CheckFp2(t, "CondSwap(1)", &xPz, new(big.Int).Set(a), new(big.Int).Set(b), a, b, c, d)
+	}
+}
+{{end}}
+func Benchmark{{.Export}}Mul(b *testing.B) {
+	var x, y {{.Fp}}
+	var z {{.FpX2}}
+	{{.Fn}}FromBig(x[:{{.NumWords}}], new(big.Int).Sub({{.Fn}}BigP, big.NewInt(1)))
+	y = x
+	for n := 0; n < b.N; n++ {
+		{{.Fn}}Mul(&z, &x, &y)
+	}
+}
+
+func Benchmark{{.Export}}MontgomeryReduce(b *testing.B) {
+	var x {{.FpX2}}
+	var z {{.Fp}}
+	{{.Fn}}FromBig(x[:2*{{.NumWords}}], new(big.Int).Sub({{.Fn}}BigPR, big.NewInt(1)))
+	for n := 0; n < b.N; n++ {
+		{{.Fn}}MontgomeryReduce(&z, &x)
+	}
+}
+
+func Benchmark{{.Export}}AddReduced(b *testing.B) {
+	var x, y, z {{.Fp}}
+	{{.Fn}}FromBig(x[:{{.NumWords}}], new(big.Int).Sub({{.Fn}}BigP, big.NewInt(1)))
+	y = x
+	for n := 0; n < b.N; n++ {
+		{{.Fn}}AddReduced(&z, &x, &y)
+	}
+}
+
+func Benchmark{{.Export}}Inv(b *testing.B) {
+	var x {{.Fp}}
+	{{.Fn}}FromBig(x[:{{.NumWords}}], new(big.Int).Sub({{.Fn}}BigP, big.NewInt(1)))
+	for n := 0; n < b.N; n++ {
+		{{.Fn}}Inv(&x, &x)
+	}
+}
+`
+
+func genTest(f *field) ([]byte, error) {
+	src, err := execGo(f, "", testTmpl, false)
+	if err != nil || !f.Fp2 {
+		return src, err
+	}
+	return addIsogenyImport(src, "\"testing\"")
+}
@@ -0,0 +1,392 @@
+// Command fpgen generates constant-time arithmetic for a prime field F_p
+// in Montgomery representation.
+//
+// For a given prime it emits Montgomery constants, portable Go
+// implementation of the field primitives, amd64 and arm64 assembly and
+// differential tests against math/big. By default the generated package
+// also implements isogeny.FieldOps for the quadratic extension F_p^2 = F_p(i),
+// so that it can be plugged into SIDH/SIKE.
+//
+// Usage:
+//
+//	go run github.com/henrydcase/nobs/dh/sidh/internal/fpgen -name p434 -prime "2^216*3^137-1"
+//
+// Flags:
+//
+//	-name   name of the prime, used as a prefix of generated identifiers (e.g. p434)
+//	-prime  the prime: decimal, hexadecimal (0x...) or an expression using
+//	        + - * ^ and parentheses (e.g. "2^216*3^137-1")
+//	-pkg    package name of generated files (defaults to -name)
+//	-fp2    generate implementation of isogeny.FieldOps (default true). Otherwise
+//	        only arithmetic in F_p is generated, with elements fully reduced
+//	        to [0,p) and types local to the package.
+//	-out    output directory (defaults to current directory)
+//
+// Generated primitives follow the same contract as the hand-written ones in
+// dh/sidh/p503 and dh/sidh/p751. When -fp2 is set, field elements are kept
+// in [0,2p) (lazy reduction), which requires 16*p < 2^(64*words). Without
+// it, elements are kept in [0,p) and only 2*p < 2^(64*words) is required.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Maximal number of words in FpElement, must match isogeny.FP_MAX_WORDS
+const maxWordsFp2 = 12
+
+// Maximal number of words supported by arm64 backend, which keeps all
+// Montgomery quotients in registers.
+const maxWords = 13
+
+// field describes prime field for which code is generated
+type field struct {
+	// Name of the prime, e.g. "p434"
+	Name string
+	// Package name of generated files
+	Pkg string
+	// Prime as provided on the command line
+	Spec string
+	// The prime
+	P *big.Int
+	// Number of 64-bit words needed to store an element
+	Words int
+	// Bit length of the prime
+	Bits int
+	// If true, isogeny.FieldOps is generated and lazy reduction is used
+	Fp2 bool
+}
+
+var identRe = regexp.MustCompile(`^p[0-9A-Za-z_]+$`)
+
+// newField validates parameters and returns field description
+func newField(name, pkg, spec string, fp2 bool) (*field, error) {
+	if !identRe.MatchString(name) {
+		return nil, fmt.Errorf("invalid name %q, must start with 'p' and be a valid identifier", name)
+	}
+	p, err := parsePrime(spec)
+	if err != nil {
+		return nil, err
+	}
+	if p.Sign() <= 0 || p.Bit(0) == 0 || !p.ProbablyPrime(32) {
+		return nil, errors.New("modulus must be an odd prime")
+	}
+	if pkg == "" {
+		pkg = name
+	}
+
+	f := &field{
+		Name:  name,
+		Pkg:   pkg,
+		Spec:  spec,
+		P:     p,
+		Bits:  p.BitLen(),
+		Words: (p.BitLen() + 63) / 64,
+		Fp2:   fp2,
+	}
+
+	// Check if there is enough headroom for lazy reduction
+	bound := new(big.Int).Lsh(p, 1)
+	if fp2 {
+		bound.Lsh(p, 4)
+		if f.Words > maxWordsFp2 {
+			return nil, fmt.Errorf("prime too big, FpElement holds at most %d words", maxWordsFp2)
+		}
+	}
+	if bound.BitLen() > 64*f.Words {
+		if fp2 {
+			return nil, errors.New("16*p must be smaller than R, prime not suitable for lazy reduction")
+		}
+		return nil, errors.New("2*p must be smaller than R")
+	}
+	if f.Words > maxWords {
+		return nil, fmt.Errorf("prime too big, at most %d words supported", maxWords)
+	}
+	return f, nil
+}
+
+// Identifiers and names used in generated code
+
+// Fn returns prefix of generated functions, e.g. "fp434"
+func (f *field) Fn() string { return "fp" + f.Name[1:] }
+
+// Fp returns name of the type storing field element
+func (f *field) Fp() string {
+	if f.Fp2 {
+		return "FpElement"
+	}
+	return f.Fn() + "Element"
+}
+
+// FpX2 returns name of the type storing product of two field elements
+func (f *field) FpX2() string { return f.Fp() + "X2" }
+
+// NumWords returns name of the constant holding number of words
+func (f *field) NumWords() string {
+	if f.Fp2 {
+		return "NumWords"
+	}
+	return f.Fn() + "NumWords"
+}
+
+// Bound returns name of a variable keeping upper bound of field element
+// representation. That is 2*p in case of lazy reduction and p otherwise.
+func (f *field) Bound() string {
+	if f.Fp2 {
+		return f.Name + "x2"
+	}
+	return f.Name
+}
+
+// BoundDesc returns description of the bound used in comments
+func (f *field) BoundDesc() string {
+	if f.Fp2 {
+		return "2*p"
+	}
+	return "p"
+}
+
+// R returns 2^(64*words)
+func (f *field) R() *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(64*f.Words))
+}
+
+// MPrime returns -p^-1 mod 2^64
+func (f *field) MPrime() uint64 {
+	b := new(big.Int).Lsh(big.NewInt(1), 64)
+	m := new(big.Int).ModInverse(f.P, b)
+	m.Sub(b, m)
+	return m.Uint64()
+}
+
+// words returns x as little-endian slice of 64-bit words of field size
+func (f *field) words(x *big.Int) []uint64 {
+	w := make([]uint64, f.Words)
+	t := new(big.Int).Set(x)
+	mask := new(big.Int).SetUint64(^uint64(0))
+	for i := range w {
+		w[i] = new(big.Int).And(t, mask).Uint64()
+		t.Rsh(t, 64)
+	}
+	return w
+}
+
+// Values of constants used by generated code
+func (f *field) PWords() []uint64  { return f.words(f.P) }
+func (f *field) P2Words() []uint64 { return f.words(new(big.Int).Lsh(f.P, 1)) }
+func (f *field) R2Words() []uint64 {
+	r := f.R()
+	return f.words(r.Mul(r, r).Mod(r, f.P))
+}
+func (f *field) OneWords() []uint64 {
+	r := f.R()
+	return f.words(r.Mod(r, f.P))
+}
+func (f *field) HalfWords() []uint64 {
+	h := new(big.Int).ModInverse(big.NewInt(2), f.P)
+	return f.words(h.Mul(h, f.R()).Mod(h, f.P))
+}
+func (f *field) PMinus2Words() []uint64 {
+	return f.words(new(big.Int).Sub(f.P, big.NewInt(2)))
+}
+
+// fileName returns name of generated file. If package is dedicated to the
+// prime, names are the same as in hand-written packages, otherwise they
+// are prefixed with name of the prime.
+func (f *field) fileName(base string) string {
+	if f.Pkg == f.Name {
+		return base
+	}
+	return f.Name + "_" + base
+}
+
+// generate returns content of all generated files, keyed by file name
+func generate(f *field) (map[string][]byte, error) {
+	out := make(map[string][]byte)
+	gens := []struct {
+		name string
+		gen  func(*field) ([]byte, error)
+	}{
+		{"consts.go", genConsts},
+		{"arith_decl.go", genDecl},
+		{"arith_generic.go", genGeneric},
+		{"arith_amd64.s", genAmd64},
+		{"arith_arm64.s", genArm64},
+		{"fp.go", genFp},
+		{"arith_test.go", genTest},
+	}
+	if f.Fp2 {
+		gens = append(gens, struct {
+			name string
+			gen  func(*field) ([]byte, error)
+		}{"field_ops.go", genFieldOps})
+	}
+	for _, g := range gens {
+		b, err := g.gen(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", g.name, err)
+		}
+		out[f.fileName(g.name)] = b
+	}
+	return out, nil
+}
+
+func main() {
+	var name, spec, pkg, dir string
+	var fp2 bool
+
+	flag.StringVar(&name, "name", "", "name of the prime (e.g. p434)")
+	flag.StringVar(&spec, "prime", "", "the prime, either a number or an expression like 2^216*3^137-1")
+	flag.StringVar(&pkg, "pkg", "", "package name (defaults to -name)")
+	flag.BoolVar(&fp2, "fp2", true, "generate isogeny.FieldOps implementation")
+	flag.StringVar(&dir, "out", ".", "output directory")
+	flag.Parse()
+
+	if name == "" || spec == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	f, err := newField(name, pkg, spec, fp2)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "fpgen:", err)
+		os.Exit(1)
+	}
+	files, err := generate(f)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "fpgen:", err)
+		os.Exit(1)
+	}
+	for n, b := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, n), b, 0644); err != nil {
+			fmt.Fprintln(os.Stderr, "fpgen:", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Parsing of the prime
+// ---------------------------------------------------------------------------
+
+// parsePrime parses number or simple arithmetic expression. Following grammar
+// is supported:
+//
+//	expr   = term { ("+" | "-") term }
+//	term   = factor { "*" factor }
+//	factor = atom [ "^" factor ]
+//	atom   = number | "(" expr ")"
+func parsePrime(s string) (*big.Int, error) {
+	p := &parser{s: strings.Replace(s, " ", "", -1)}
+	v, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.s) {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.s[p.pos:], p.pos)
+	}
+	return v, nil
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func (p *parser) expr() (*big.Int, error) {
+	v, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for c := p.peek(); c == '+' || c == '-'; c = p.peek() {
+		p.pos++
+		t, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		if c == '+' {
+			v.Add(v, t)
+		} else {
+			v.Sub(v, t)
+		}
+	}
+	return v, nil
+}
+
+func (p *parser) term() (*big.Int, error) {
+	v, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == '*' {
+		p.pos++
+		t, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		v.Mul(v, t)
+	}
+	return v, nil
+}
+
+func (p *parser) factor() (*big.Int, error) {
+	v, err := p.atom()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() == '^' {
+		p.pos++
+		e, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		if e.Sign() < 0 || e.BitLen() > 16 {
+			return nil, errors.New("exponent out of range")
+		}
+		v.Exp(v, e, nil)
+	}
+	return v, nil
+}
+
+func (p *parser) atom() (*big.Int, error) {
+	if p.peek() == '(' {
+		p.pos++
+		v, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("missing ')' at position %d", p.pos)
+		}
+		p.pos++
+		return v, nil
+	}
+
+	pos, base, digits := p.pos, 10, "0123456789"
+	if strings.HasPrefix(p.s[p.pos:], "0x") || strings.HasPrefix(p.s[p.pos:], "0X") {
+		p.pos += 2
+		base, digits = 16, "0123456789abcdefABCDEF"
+	}
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte(digits, p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	if v, ok := new(big.Int).SetString(p.s[start:p.pos], base); ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("expected number at position %d", pos)
+}
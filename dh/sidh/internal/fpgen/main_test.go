@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/henrydcase/nobs/dh/sidh/p503"
+	"github.com/henrydcase/nobs/dh/sidh/p751"
+)
+
+func TestParsePrime(t *testing.T) {
+	var vectors = []struct {
+		in  string
+		out string
+	}{
+		{"13", "13"},
+		{"0x1F", "31"},
+		{"2^3*3^2-1", "71"},
+		{"2^2^3", "256"},
+		{"(1+2)*(3 - 1)", "6"},
+		{"2^216*3^137-1", "24439423661345221551909145011457493619085780243761596511325807336205221239331976725970216671828618445898719026692884939342314733567"},
+	}
+	for _, v := range vectors {
+		got, err := parsePrime(v.in)
+		if err != nil {
+			t.Errorf("%s: %v", v.in, err)
+			continue
+		}
+		if got.String() != v.out {
+			t.Errorf("%s: got %s, expected %s", v.in, got, v.out)
+		}
+	}
+
+	for _, in := range []string{"", "2^", "(2", "2)", "x", "0x", "2^100000"} {
+		if _, err := parsePrime(in); err == nil {
+			t.Errorf("%q: expected error", in)
+		}
+	}
+}
+
+func TestNewFieldErrors(t *testing.T) {
+	var vectors = []struct {
+		name, prime string
+		fp2         bool
+	}{
+		{"p1", "2^127-1", true},    // no space for lazy reduction
+		{"p2", "2^128-159", false}, // 2p doesn't fit
+		{"p3", "2^216*3^137", true},
+		{"p4", "2^216*3^137+1", true},
+		{"x434", "2^216*3^137-1", true},
+		{"p5", "2^800-2^32-1", false},
+	}
+	for _, v := range vectors {
+		if _, err := newField(v.name, "", v.prime, v.fp2); err == nil {
+			t.Errorf("%s: expected error", v.prime)
+		}
+	}
+	if _, err := newField("p127", "", "2^127-1", false); err != nil {
+		t.Error(err)
+	}
+}
+
+// Compares constants with those in hand-written packages
+func TestConstants(t *testing.T) {
+	check := func(name string, got []uint64, exp []uint64) {
+		for i := range got {
+			if got[i] != exp[i] {
+				t.Errorf("%s: got %x, expected %x", name, got, exp[:len(got)])
+				return
+			}
+		}
+	}
+
+	f, err := newField("p503", "", "2^250*3^159-1", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("p503 one", f.OneWords(), p503.P503OneFp2.A[:])
+	check("p503 half", f.HalfWords(), p503.P503HalfFp2.A[:])
+	if f.Words != p503.NumWords || (f.Bits+7)/8 != p503.P503_Bytelen {
+		t.Error("p503: wrong size")
+	}
+
+	f, err = newField("p751", "", "2^372*3^239-1", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("p751 one", f.OneWords(), p751.P751OneFp2.A[:])
+	check("p751 half", f.HalfWords(), p751.P751HalfFp2.A[:])
+
+	// -p^-1 * p = 1 mod 2^64
+	m := new(big.Int).SetUint64(f.MPrime())
+	m.Mul(m, f.P).Add(m, big.NewInt(1))
+	if m.Uint64() != 0 {
+		t.Error("wrong mprime")
+	}
+}
+
+// Checks that generated files in dh/sidh/p434 and dh/csidh are up to date
+func TestGeneratedUpToDate(t *testing.T) {
+	var csidhPrime = "4*3*5*7*11*13*17*19*23*29*31*37*41*43*47*53*59*61*67*71*73*79*83*89*97*" +
+		"101*103*107*109*113*127*131*137*139*149*151*157*163*167*173*179*181*191*193*197*199*" +
+		"211*223*227*229*233*239*241*251*257*263*269*271*277*281*283*293*307*311*313*317*331*" +
+		"337*347*349*353*359*367*373*587-1"
+	var vectors = []struct {
+		name, pkg, prime string
+		fp2              bool
+		dir              string
+	}{
+		{"p434", "", "2^216*3^137-1", true, filepath.Join("..", "..", "p434")},
+		{"p511", "csidh", csidhPrime, false, filepath.Join("..", "..", "..", "csidh")},
+	}
+	for _, v := range vectors {
+		f, err := newField(v.name, v.pkg, v.prime, v.fp2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files, err := generate(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, content := range files {
+			disk, err := ioutil.ReadFile(filepath.Join(v.dir, name))
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+			if !bytes.Equal(disk, content) {
+				t.Errorf("%s/%s is out of date, run go generate", v.dir, name)
+			}
+		}
+	}
+}
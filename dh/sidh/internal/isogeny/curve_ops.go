@@ -250,6 +250,9 @@ func (c *CurveOperations) ScalarMul3Pt(cparams *ProjectiveCurveParameters, P, Q,
 		R0, R2 = c.xDblAdd(&R0, &R2, &R1, &aPlus2Over4)
 	}
 	op.CondSwap(&R1.X, &R1.Z, &R2.X, &R2.Z, prevBit)
+	// Remaining ladder state depends on bits of the scalar
+	R0.Zeroize()
+	R2.Zeroize()
 	return R1
 }
 
@@ -438,3 +441,33 @@ func (fp *Fp2Element) Zeroize() {
 		fp.B[i] = 0
 	}
 }
+
+// Cleans coordinates of the point
+func (point *ProjectivePoint) Zeroize() {
+	point.X.Zeroize()
+	point.Z.Zeroize()
+}
+
+// Cleans curve coefficients
+func (cparams *ProjectiveCurveParameters) Zeroize() {
+	cparams.A.Zeroize()
+	cparams.C.Zeroize()
+}
+
+// Cleans equivalent curve coefficients
+func (coefEq *CurveCoefficientsEquiv) Zeroize() {
+	coefEq.A.Zeroize()
+	coefEq.C.Zeroize()
+}
+
+// Cleans isogeny constants
+func (phi *isogeny3) Zeroize() {
+	phi.K1.Zeroize()
+	phi.K2.Zeroize()
+}
+
+// Cleans isogeny constants
+func (phi *isogeny4) Zeroize() {
+	phi.isogeny3.Zeroize()
+	phi.K3.Zeroize()
+}
@@ -1,5 +1,9 @@
 package internal
 
+import (
+	"github.com/henrydcase/nobs/utils/security"
+)
+
 const (
 	FP_MAX_WORDS = 12 // Currently p751.NumWords
 )
@@ -54,6 +58,8 @@ type SidhParams struct {
 	KemSize uint
 	// Access to field arithmetic
 	Op FieldOps
+	// Security properties of SIDH/SIKE with this prime
+	Meta security.Metadata
 }
 
 // Interface for working with isogenies.
@@ -66,6 +72,8 @@ type Isogeny interface {
 	// Evaluates isogeny at caller provided point. Requires isogeny curve constants
 	// to be earlier computed by GenerateCurve.
 	EvaluatePoint(*ProjectivePoint) ProjectivePoint
+	// Cleans isogeny constants, which depend on the kernel point.
+	Zeroize()
 }
 
 // Stores curve projective parameters equivalent to A/C. Meaning of the
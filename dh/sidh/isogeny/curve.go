@@ -0,0 +1,290 @@
+package isogeny
+
+import (
+	"errors"
+
+	internal "github.com/henrydcase/nobs/dh/sidh/internal/isogeny"
+)
+
+func (p *Params) ops() *internal.CurveOperations {
+	return &internal.CurveOperations{Params: p.p}
+}
+
+// NewPoint returns projective point (x:1).
+func (p *Params) NewPoint(x *Fp2) Point {
+	return Point{X: *x, Z: Fp2{p.p.OneFp2}}
+}
+
+// ToAffine returns affine x-coordinate X/Z of a point.
+func (p *Params) ToAffine(P *Point) Fp2 {
+	R := P.in()
+	return Fp2{*R.ToAffine(p.ops())}
+}
+
+// Pow2k returns x([2^k]P), computed on curve c.
+func (p *Params) Pow2k(c *Curve, P *Point, k uint32) Point {
+	ops := p.ops()
+	cc, R := c.in(), P.in()
+	eq := ops.CalcCurveParamsEquiv4(&cc)
+	ops.Pow2k(&R, &eq, k)
+	return toPoint(&R)
+}
+
+// Pow3k returns x([3^k]P), computed on curve c.
+func (p *Params) Pow3k(c *Curve, P *Point, k uint32) Point {
+	ops := p.ops()
+	cc, R := c.in(), P.in()
+	eq := ops.CalcCurveParamsEquiv3(&cc)
+	ops.Pow3k(&R, &eq, k)
+	return toPoint(&R)
+}
+
+// DiffAdd returns x(P+Q), given x(P), x(Q) and x(P-Q). Differential addition
+// doesn't depend on the curve.
+func (p *Params) DiffAdd(P, Q, PmQ *Point) Point {
+	var t0, t1 internal.Fp2Element
+	var R internal.ProjectivePoint
+	op := p.p.Op
+
+	op.Add(&t0, &P.X.v, &P.Z.v)  // t0 = XP + ZP
+	op.Sub(&t1, &P.X.v, &P.Z.v)  // t1 = XP - ZP
+	op.Sub(&R.X, &Q.X.v, &Q.Z.v) // XR = XQ - ZQ
+	op.Add(&R.Z, &Q.X.v, &Q.Z.v) // ZR = XQ + ZQ
+	op.Mul(&t0, &R.X, &t0)       // t0 = XR * t0
+	op.Mul(&t1, &R.Z, &t1)       // t1 = ZR * t1
+	op.Sub(&R.Z, &t0, &t1)       // ZR = t0 - t1
+	op.Add(&R.X, &t0, &t1)       // XR = t0 + t1
+	op.Square(&R.Z, &R.Z)        // ZR = ZR^2
+	op.Square(&R.X, &R.X)        // XR = XR^2
+	op.Mul(&R.X, &PmQ.Z.v, &R.X)
+	op.Mul(&R.Z, &PmQ.X.v, &R.Z)
+	return toPoint(&R)
+}
+
+// ScalarMul3Pt returns x(Q + [k]P), given x(P), x(Q) and x(P-Q), where k is
+// little-endian scalar of nbits bits. Computed on curve c in constant time.
+func (p *Params) ScalarMul3Pt(c *Curve, P, Q, PmQ *Point, nbits uint, k []byte) Point {
+	cc, iP, iQ, iPmQ := c.in(), P.in(), Q.in(), PmQ.in()
+	R := p.ops().ScalarMul3Pt(&cc, &iP, &iQ, &iPmQ, nbits, k)
+	return toPoint(&R)
+}
+
+// Jinvariant returns encoded j-invariant of the curve c. Encoding is the
+// same as in Field.ToBytes.
+func (p *Params) Jinvariant(c *Curve) []byte {
+	j := make([]byte, p.Size())
+	cc := c.in()
+	p.ops().Jinvariant(&cc, j)
+	return j
+}
+
+// RecoverCurve returns curve (A:1), given affine x-coordinates of points
+// P, Q and Q-P on it.
+func (p *Params) RecoverCurve(xP, xQ, xQmP *Fp2) Curve {
+	c := internal.ProjectiveCurveParameters{C: p.p.OneFp2}
+	p.ops().RecoverCoordinateA(&c, &xP.v, &xQ.v, &xQmP.v)
+	return toCurve(&c)
+}
+
+// Isogeny represents isogeny of small degree between Montgomery curves.
+type Isogeny interface {
+	// Degree of the isogeny
+	Degree() uint
+	// Codomain returns curve isogenous to the domain.
+	Codomain() Curve
+	// Evaluate returns image of a point.
+	Evaluate(P *Point) Point
+}
+
+type smallIsogeny struct {
+	degree   uint
+	phi      internal.Isogeny
+	codomain Curve
+}
+
+func (s *smallIsogeny) Degree() uint            { return s.degree }
+func (s *smallIsogeny) Codomain() Curve         { return s.codomain }
+func (s *smallIsogeny) Evaluate(P *Point) Point { return evaluate(s.phi, P) }
+
+// evaluate returns image of P under phi
+func evaluate(phi internal.Isogeny, P *Point) Point {
+	R := P.in()
+	R = phi.EvaluatePoint(&R)
+	return toPoint(&R)
+}
+
+// NewIsogeny constructs isogeny of degree 2, 3 or 4 with kernel generated by
+// K. Order of K must be equal to degree. In case of degree 2, K must not
+// be (0:1). In case of degree 4, [2]K must not be (0:1).
+func (p *Params) NewIsogeny(K *Point, degree uint) (Isogeny, error) {
+	s := &smallIsogeny{degree: degree}
+	phi, err := p.isogeny(degree)
+	if err != nil {
+		return nil, err
+	}
+	s.phi = phi
+	R := K.in()
+	eq := phi.GenerateCurve(&R)
+	s.codomain = p.recover(&eq, degree)
+	return s, nil
+}
+
+// isogeny returns internal implementation of isogeny of given degree
+func (p *Params) isogeny(degree uint) (internal.Isogeny, error) {
+	switch degree {
+	case 2:
+		return &isogeny2{op: p.p.Op}, nil
+	case 3:
+		return internal.Newisogeny3(p.p.Op), nil
+	case 4:
+		return internal.Newisogeny4(p.p.Op), nil
+	}
+	return nil, errors.New("isogeny: unsupported degree")
+}
+
+// recover converts curve coefficients returned by isogeny of given
+// degree to (A:C)
+func (p *Params) recover(eq *internal.CurveCoefficientsEquiv, degree uint) Curve {
+	var c internal.ProjectiveCurveParameters
+	if degree == 3 {
+		p.ops().RecoverCurveCoefficients3(&c, eq)
+	} else {
+		p.ops().RecoverCurveCoefficients4(&c, eq)
+	}
+	return toCurve(&c)
+}
+
+// Traverse computes isogeny of degree l^n, where l is a degree of isogeny
+// (2, 3 or 4) and n = len(strategy)+1. The kernel is generated by K, which
+// must have order l^n on curve c. Points are evaluated in place. Returns
+// the codomain.
+//
+// The isogeny is computed as composition of n isogenies of degree l, in
+// order given by the strategy. The strategy encodes a traversal of
+// a binary tree with n leaves; i-th number says by how many multiplications
+// by l the currently visited point is moved towards the leaves. Same format
+// is used by SIKE, for example Params.StrategyA is a strategy for Traverse
+// with l=4 and n=E2/2.
+//
+// A strategy consisting of n-1 ones is always valid, but is the slowest one.
+func (p *Params) Traverse(c *Curve, K *Point, degree uint, strategy []uint32, points []Point) (Curve, error) {
+	var out Curve
+	var eq internal.CurveCoefficientsEquiv
+	var mul func(*internal.ProjectivePoint, *internal.CurveCoefficientsEquiv, uint32)
+	var ops = p.ops()
+	var cc = c.in()
+
+	phi, err := p.isogeny(degree)
+	if err != nil {
+		return out, err
+	}
+	switch degree {
+	case 2:
+		eq = ops.CalcCurveParamsEquiv4(&cc)
+		mul = ops.Pow2k
+	case 3:
+		eq = ops.CalcCurveParamsEquiv3(&cc)
+		mul = ops.Pow3k
+	case 4:
+		eq = ops.CalcCurveParamsEquiv4(&cc)
+		mul = func(P *internal.ProjectivePoint, eq *internal.CurveCoefficientsEquiv, k uint32) {
+			ops.Pow2k(P, eq, 2*k)
+		}
+	}
+
+	var stack = make([]internal.ProjectivePoint, 0, 8)
+	var indices = make([]int, 0, 8)
+	var i, sidx int
+	var R = K.in()
+	var n = len(strategy)
+
+	for j := 1; j <= n; j++ {
+		for i <= n-j {
+			if sidx == n {
+				return out, errors.New("isogeny: invalid strategy")
+			}
+			stack = append(stack, R)
+			indices = append(indices, i)
+
+			k := strategy[sidx]
+			sidx++
+			mul(&R, &eq, k)
+			i += int(k)
+		}
+		// R must be a leaf
+		if i != n-j+1 {
+			return out, errors.New("isogeny: invalid strategy")
+		}
+
+		eq = phi.GenerateCurve(&R)
+		for k := range stack {
+			stack[k] = phi.EvaluatePoint(&stack[k])
+		}
+		for k := range points {
+			points[k] = evaluate(phi, &points[k])
+		}
+
+		// pop R from the stack
+		if len(stack) == 0 {
+			return out, errors.New("isogeny: invalid strategy")
+		}
+		R, stack = stack[len(stack)-1], stack[:len(stack)-1]
+		i, indices = indices[len(indices)-1], indices[:len(indices)-1]
+	}
+	if sidx != n {
+		return out, errors.New("isogeny: invalid strategy")
+	}
+
+	// Last isogeny
+	eq = phi.GenerateCurve(&R)
+	for k := range points {
+		points[k] = evaluate(phi, &points[k])
+	}
+	return p.recover(&eq, degree), nil
+}
+
+// Stores 2-isogeny kernel point
+type isogeny2 struct {
+	op     internal.FieldOps
+	X2, Z2 internal.Fp2Element
+}
+
+// Given a two-torsion point p = x(P2) != (0:1) on the curve E_(A:C),
+// construct the two-isogeny phi : E_(A:C) -> E_(A:C)/<P_2> = E_(A':C').
+// Returns (A'+2C':4C').
+func (phi *isogeny2) GenerateCurve(p *internal.ProjectivePoint) internal.CurveCoefficientsEquiv {
+	var coefEq internal.CurveCoefficientsEquiv
+	op := phi.op
+
+	phi.X2, phi.Z2 = p.X, p.Z
+	op.Square(&coefEq.A, &p.X)              // A24p = XP2^2
+	op.Square(&coefEq.C, &p.Z)              // C24  = ZP2^2
+	op.Sub(&coefEq.A, &coefEq.C, &coefEq.A) // A24p = C24 - A24p
+	return coefEq
+}
+
+// Given a 2-isogeny phi and a point p = x(P), compute x(Q), the
+// x-coordinate of the image Q = phi(P).
+func (phi *isogeny2) EvaluatePoint(p *internal.ProjectivePoint) internal.ProjectivePoint {
+	var t0, t1, t2, t3 internal.Fp2Element
+	var q internal.ProjectivePoint
+	op := phi.op
+
+	op.Add(&t0, &phi.X2, &phi.Z2) // t0 = X2 + Z2
+	op.Sub(&t1, &phi.X2, &phi.Z2) // t1 = X2 - Z2
+	op.Add(&t2, &p.X, &p.Z)       // t2 = XP + ZP
+	op.Sub(&t3, &p.X, &p.Z)       // t3 = XP - ZP
+	op.Mul(&t0, &t0, &t3)         // t0 = t0 * t3
+	op.Mul(&t1, &t1, &t2)         // t1 = t1 * t2
+	op.Add(&t2, &t0, &t1)         // t2 = t0 + t1
+	op.Sub(&t3, &t0, &t1)         // t3 = t0 - t1
+	op.Mul(&q.X, &p.X, &t2)       // XQ = XP * t2
+	op.Mul(&q.Z, &p.Z, &t3)       // ZQ = ZP * t3
+	return q
+}
+
+// Cleans the kernel point
+func (phi *isogeny2) Zeroize() {
+	phi.X2.Zeroize()
+	phi.Z2.Zeroize()
+}
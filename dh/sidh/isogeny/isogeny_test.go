@@ -0,0 +1,274 @@
+package isogeny
+
+import (
+	"bytes"
+	"crypto/rand"
+	"os"
+	"testing"
+
+	"github.com/henrydcase/nobs/dh/sidh"
+	"github.com/henrydcase/nobs/utils/security"
+)
+
+// Results are compared with dh/sidh, which must be explicitly enabled
+func TestMain(m *testing.M) {
+	security.AllowInsecure(true)
+	os.Exit(m.Run())
+}
+
+var params = []*Params{P503(), P751()}
+
+// Computes public key of one party using only functions from this package.
+// Returns the encoding used by sidh.PublicKey.Export.
+func publicKey(t *testing.T, p *Params, scalar []byte, isA bool) []byte {
+	var P, Q, R, S [3]Fp2
+	var degree uint = 4
+	var nbits = p.SecretBitLenA
+	var strategy = p.StrategyA
+
+	P[0], Q[0], R[0] = p.PA, p.QA, p.RA
+	S[0], S[1], S[2] = p.PB, p.QB, p.RB
+	if !isA {
+		P[0], Q[0], R[0] = p.PB, p.QB, p.RB
+		S[0], S[1], S[2] = p.PA, p.QA, p.RA
+		degree, nbits, strategy = 3, p.SecretBitLenB, p.StrategyB
+	}
+
+	xP, xQ, xR := p.NewPoint(&P[0]), p.NewPoint(&Q[0]), p.NewPoint(&R[0])
+	K := p.ScalarMul3Pt(&p.E0, &xP, &xQ, &xR, nbits, scalar)
+	points := []Point{p.NewPoint(&S[0]), p.NewPoint(&S[1]), p.NewPoint(&S[2])}
+	if _, err := p.Traverse(&p.E0, &K, degree, strategy, points); err != nil {
+		t.Fatal(err)
+	}
+
+	var out []byte
+	for i := range points {
+		x := p.ToAffine(&points[i])
+		out = append(out, p.ToBytes(&x)...)
+	}
+	return out
+}
+
+// Computes shared secret from the public key of the other party
+func sharedSecret(t *testing.T, p *Params, scalar, pub []byte, isA bool) []byte {
+	var x [3]Fp2
+	var err error
+	var degree uint = 4
+	var nbits = p.SecretBitLenA
+	var strategy = p.StrategyA
+
+	if !isA {
+		degree, nbits, strategy = 3, p.SecretBitLenB, p.StrategyB
+	}
+	for i := range x {
+		x[i], err = p.FromBytes(pub[i*p.Size() : (i+1)*p.Size()])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := p.RecoverCurve(&x[0], &x[1], &x[2])
+	xP, xQ, xR := p.NewPoint(&x[0]), p.NewPoint(&x[1]), p.NewPoint(&x[2])
+	K := p.ScalarMul3Pt(&c, &xP, &xQ, &xR, nbits, scalar)
+	c, err = p.Traverse(&c, &K, degree, strategy, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p.Jinvariant(&c)
+}
+
+func TestSIDHCompatibility(t *testing.T) {
+	ids := map[string]uint8{"P503": sidh.FP_503, "P751": sidh.FP_751}
+	for _, p := range params {
+		prvA := sidh.NewPrivateKey(ids[p.Name], sidh.KeyVariant_SIDH_A)
+		prvB := sidh.NewPrivateKey(ids[p.Name], sidh.KeyVariant_SIDH_B)
+		if err := prvA.Generate(rand.Reader); err != nil {
+			t.Fatal(err)
+		}
+		if err := prvB.Generate(rand.Reader); err != nil {
+			t.Fatal(err)
+		}
+		pubA := prvA.GeneratePublicKey()
+		pubB := prvB.GeneratePublicKey()
+
+		gotA := publicKey(t, p, prvA.Export(), true)
+		gotB := publicKey(t, p, prvB.Export(), false)
+		if !bytes.Equal(gotA, pubA.Export()) {
+			t.Errorf("%s: public key A doesn't match", p.Name)
+		}
+		if !bytes.Equal(gotB, pubB.Export()) {
+			t.Errorf("%s: public key B doesn't match", p.Name)
+		}
+
+		exp, err := sidh.DeriveSecret(prvA, pubB)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ss := sharedSecret(t, p, prvA.Export(), gotB, true); !bytes.Equal(ss, exp) {
+			t.Errorf("%s: shared secret A doesn't match", p.Name)
+		}
+		if ss := sharedSecret(t, p, prvB.Export(), gotA, false); !bytes.Equal(ss, exp) {
+			t.Errorf("%s: shared secret B doesn't match", p.Name)
+		}
+	}
+}
+
+// Chain of 2-isogenies must end on the same curve as chain of 4-isogenies
+// with the same kernel.
+func TestTwoIsogenyChain(t *testing.T) {
+	for _, p := range params {
+		scalar := make([]byte, (p.SecretBitLenA+7)/8)
+		if _, err := rand.Read(scalar); err != nil {
+			t.Fatal(err)
+		}
+		xP, xQ, xR := p.NewPoint(&p.PA), p.NewPoint(&p.QA), p.NewPoint(&p.RA)
+		K := p.ScalarMul3Pt(&p.E0, &xP, &xQ, &xR, p.SecretBitLenA, scalar)
+
+		// Simplest strategy and degree 3^E3 isogeny for comparison
+		ones := make([]uint32, p.E2-1)
+		for i := range ones {
+			ones[i] = 1
+		}
+		pts2 := []Point{p.NewPoint(&p.PB)}
+		c2, err := p.Traverse(&p.E0, &K, 2, ones, pts2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pts4 := []Point{p.NewPoint(&p.PB)}
+		c4, err := p.Traverse(&p.E0, &K, 4, p.StrategyA, pts4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(p.Jinvariant(&c2), p.Jinvariant(&c4)) {
+			t.Errorf("%s: j-invariants differ", p.Name)
+		}
+
+		// Image of a point must be on the codomain, check that [3^E3]P = 0
+		R := p.Pow3k(&c2, &pts2[0], uint32(p.E3))
+		if !p.Equal(&R.Z, &Fp2{}) {
+			t.Errorf("%s: image has wrong order", p.Name)
+		}
+		R = p.Pow3k(&c2, &pts2[0], uint32(p.E3-1))
+		if p.Equal(&R.Z, &Fp2{}) {
+			t.Errorf("%s: image has wrong order", p.Name)
+		}
+	}
+}
+
+func TestNewIsogeny(t *testing.T) {
+	for _, p := range params {
+		// [2^(E2-2)]PA is a point of order 4 with [2]K != (0:1)
+		P := p.NewPoint(&p.PA)
+		K := p.Pow2k(&p.E0, &P, uint32(p.E2-2))
+		K2 := p.Pow2k(&p.E0, &K, 1)
+
+		phi4, err := p.NewIsogeny(&K, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		phi2, err := p.NewIsogeny(&K2, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if phi4.Degree() != 4 || phi2.Degree() != 2 {
+			t.Error("wrong degree")
+		}
+
+		// phi4 = psi o phi2, where kernel of psi is phi2(K)
+		K = phi2.Evaluate(&K)
+		c := phi2.Codomain()
+		psi, err := p.NewIsogeny(&K, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c4, cc := phi4.Codomain(), psi.Codomain()
+		if !bytes.Equal(p.Jinvariant(&c4), p.Jinvariant(&cc)) {
+			t.Errorf("%s: j-invariants differ", p.Name)
+		}
+		// phi2 maps K2 to zero
+		R := phi2.Evaluate(&K2)
+		if !p.Equal(&R.Z, &Fp2{}) {
+			t.Errorf("%s: kernel not mapped to zero", p.Name)
+		}
+		// and the curve must have point of order 2, namely the image of K
+		R = p.Pow2k(&c, &K, 1)
+		if !p.Equal(&R.Z, &Fp2{}) {
+			t.Errorf("%s: wrong order of the image", p.Name)
+		}
+
+		if _, err := p.NewIsogeny(&K, 5); err == nil {
+			t.Error("expected error for degree 5")
+		}
+	}
+}
+
+func TestTraverseErrors(t *testing.T) {
+	p := P503()
+	K := p.NewPoint(&p.PA)
+	if _, err := p.Traverse(&p.E0, &K, 6, []uint32{1}, nil); err == nil {
+		t.Error("expected error for degree 6")
+	}
+	// Strategies for 3 leaves
+	for _, s := range [][]uint32{{2, 1}, {1, 1}} {
+		if _, err := p.Traverse(&p.E0, &K, 2, s, nil); err != nil {
+			t.Errorf("%v: %v", s, err)
+		}
+	}
+	for _, s := range [][]uint32{{1, 2}, {3, 1}, {0, 1}, {0, 0}} {
+		if _, err := p.Traverse(&p.E0, &K, 2, s, nil); err == nil {
+			t.Errorf("%v: expected error", s)
+		}
+	}
+}
+
+func TestFieldBytes(t *testing.T) {
+	for _, p := range params {
+		var x, y, z Fp2
+		b := make([]byte, p.Size())
+		if _, err := rand.Read(b); err != nil {
+			t.Fatal(err)
+		}
+		// make coefficients smaller than p
+		b[p.Size()/2-1], b[p.Size()-1] = 0, 0
+		x, err := p.FromBytes(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(p.ToBytes(&x), b) {
+			t.Errorf("%s: round trip failed", p.Name)
+		}
+		if _, err := p.FromBytes(b[1:]); err == nil {
+			t.Error("expected error")
+		}
+
+		one := p.One()
+		p.Inv(&y, &x)
+		p.Mul(&z, &x, &y)
+		if !p.Equal(&z, &one) {
+			t.Errorf("%s: x * 1/x != 1", p.Name)
+		}
+		p.Square(&y, &x)
+		p.Mul(&z, &x, &x)
+		if !p.Equal(&z, &y) {
+			t.Errorf("%s: x^2 != x*x", p.Name)
+		}
+		p.Add(&y, &x, &one)
+		p.Sub(&y, &y, &one)
+		if !p.Equal(&x, &y) {
+			t.Errorf("%s: x + 1 - 1 != x", p.Name)
+		}
+	}
+}
+
+// Modification of returned parameters doesn't affect other callers
+func TestParamsCopy(t *testing.T) {
+	p := P503()
+	p.StrategyA[0]++
+	p.PA = Fp2{}
+	if q := P503(); q.StrategyA[0] == p.StrategyA[0] || q.Equal(&q.PA, &p.PA) {
+		t.Error("parameters are shared")
+	}
+	if sidh.Params(sidh.FP_503).A.IsogenyStrategy[0] == p.StrategyA[0] {
+		t.Error("strategy of dh/sidh modified")
+	}
+}
@@ -0,0 +1,165 @@
+// Package isogeny provides building blocks for isogeny-based cryptography
+// over the SIDH primes. It exposes arithmetic in F_p^2, x-only arithmetic
+// on Montgomery curves, isogenies of degree 2, 3 and 4, and strategy-based
+// computation of isogenies of large smooth degree.
+//
+// Curves are Montgomery curves E_(A:C): Cy^2 = Cx^3 + Ax^2 + Cx given by
+// projective coefficients (A:C). Points are represented by projective
+// x-coordinates (X:Z). Field elements are kept in Montgomery domain, use
+// Field.FromBytes and Field.ToBytes to convert them from/to little-endian
+// encoding used by SIDH.
+//
+// Package reuses implementation used by dh/sidh, so SIDH key exchange can be
+// expressed with it (see tests). Functions which take secret scalars
+// (ScalarMul3Pt) are constant time, the others don't branch on secret data
+// but are meant for experimentation rather than production use.
+package isogeny
+
+import (
+	"errors"
+
+	"github.com/henrydcase/nobs/dh/sidh"
+	internal "github.com/henrydcase/nobs/dh/sidh/internal/isogeny"
+)
+
+// Element of the field F_p^2 = F_p(i), stored in Montgomery domain. Zero
+// value is the zero element.
+type Fp2 struct {
+	v internal.Fp2Element
+}
+
+// Point on the Kummer line of a Montgomery curve, represented by projective
+// x-coordinate (X:Z).
+type Point struct {
+	X, Z Fp2
+}
+
+// Montgomery curve Cy^2 = Cx^3 + Ax^2 + Cx, given by projective
+// coefficients (A:C).
+type Curve struct {
+	A, C Fp2
+}
+
+// Conversions to and from types used by the implementation of dh/sidh
+func (P *Point) in() internal.ProjectivePoint {
+	return internal.ProjectivePoint{X: P.X.v, Z: P.Z.v}
+}
+
+func toPoint(P *internal.ProjectivePoint) Point {
+	return Point{X: Fp2{P.X}, Z: Fp2{P.Z}}
+}
+
+func (c *Curve) in() internal.ProjectiveCurveParameters {
+	return internal.ProjectiveCurveParameters{A: c.A.v, C: c.C.v}
+}
+
+func toCurve(c *internal.ProjectiveCurveParameters) Curve {
+	return Curve{A: Fp2{c.A}, C: Fp2{c.C}}
+}
+
+// Field implements arithmetic in F_p^2.
+type Field struct {
+	p *internal.SidhParams
+}
+
+// Params keeps SIDH domain parameters. Field operations and curve
+// operations are available as methods.
+type Params struct {
+	Field
+
+	// Name of the parameter set, e.g. "P503"
+	Name string
+	// Exponents of the prime p = 2^E2*3^E3 - 1
+	E2, E3 uint
+	// Starting curve y^2 = x^3 + x
+	E0 Curve
+	// Affine x-coordinates of the basis P, Q of E0[2^E2] and of P-Q
+	PA, QA, RA Fp2
+	// Affine x-coordinates of the basis P, Q of E0[3^E3] and of P-Q
+	PB, QB, RB Fp2
+	// Bit length of secret scalars used by SIDH
+	SecretBitLenA, SecretBitLenB uint
+	// Strategies for computing isogeny of degree 2^E2 with 4-isogenies
+	// (StrategyA) and of degree 3^E3 with 3-isogenies (StrategyB). See
+	// Traverse.
+	StrategyA, StrategyB []uint32
+}
+
+func newParams(name string, id uint8) *Params {
+	sp := sidh.Params(id)
+	p := &Params{
+		Field:         Field{p: sp},
+		Name:          name,
+		E2:            2 * uint(len(sp.A.IsogenyStrategy)+1),
+		E3:            uint(len(sp.B.IsogenyStrategy) + 1),
+		E0:            Curve{C: Fp2{sp.OneFp2}},
+		PA:            Fp2{sp.A.Affine_P},
+		QA:            Fp2{sp.A.Affine_Q},
+		RA:            Fp2{sp.A.Affine_R},
+		PB:            Fp2{sp.B.Affine_P},
+		QB:            Fp2{sp.B.Affine_Q},
+		RB:            Fp2{sp.B.Affine_R},
+		SecretBitLenA: sp.A.SecretBitLen,
+		SecretBitLenB: sp.B.SecretBitLen,
+		StrategyA:     append([]uint32(nil), sp.A.IsogenyStrategy...),
+		StrategyB:     append([]uint32(nil), sp.B.IsogenyStrategy...),
+	}
+	return p
+}
+
+// P503 returns parameters of SIDH/SIKE over p503 = 2^250*3^159 - 1. Each
+// call returns a new copy, which can be modified by the caller.
+func P503() *Params { return newParams("P503", sidh.FP_503) }
+
+// P751 returns parameters of SIDH/SIKE over p751 = 2^372*3^239 - 1. Each
+// call returns a new copy, which can be modified by the caller.
+func P751() *Params { return newParams("P751", sidh.FP_751) }
+
+// Set z = x + y.
+func (f *Field) Add(z, x, y *Fp2) { f.p.Op.Add(&z.v, &x.v, &y.v) }
+
+// Set z = x - y.
+func (f *Field) Sub(z, x, y *Fp2) { f.p.Op.Sub(&z.v, &x.v, &y.v) }
+
+// Set z = x * y.
+func (f *Field) Mul(z, x, y *Fp2) { f.p.Op.Mul(&z.v, &x.v, &y.v) }
+
+// Set z = x^2.
+func (f *Field) Square(z, x *Fp2) { f.p.Op.Square(&z.v, &x.v) }
+
+// Set z = 1/x. Inverse of 0 is 0.
+func (f *Field) Inv(z, x *Fp2) { f.p.Op.Inv(&z.v, &x.v) }
+
+// One returns multiplicative identity.
+func (f *Field) One() Fp2 { return Fp2{f.p.OneFp2} }
+
+// Size returns size of encoded element of F_p^2 in bytes.
+func (f *Field) Size() int { return 2 * f.p.Bytelen }
+
+// ToBytes encodes x as two little-endian integers a, b, where x = a + bi.
+func (f *Field) ToBytes(x *Fp2) []byte {
+	out := make([]byte, f.Size())
+	op := internal.CurveOperations{Params: f.p}
+	op.Fp2ToBytes(out, &x.v)
+	return out
+}
+
+// FromBytes decodes element encoded by ToBytes. Coefficients are
+// reduced modulo p.
+func (f *Field) FromBytes(in []byte) (Fp2, error) {
+	var x Fp2
+	if len(in) != f.Size() {
+		return x, errors.New("isogeny: wrong size of encoded element")
+	}
+	op := internal.CurveOperations{Params: f.p}
+	op.Fp2FromBytes(&x.v, in)
+	return x, nil
+}
+
+// Equal returns true if x == y. Not constant time.
+func (f *Field) Equal(x, y *Fp2) bool {
+	var a, b internal.Fp2Element
+	f.p.Op.FromMontgomery(&x.v, &a)
+	f.p.Op.FromMontgomery(&y.v, &b)
+	return a == b
+}
@@ -0,0 +1,81 @@
+package isogeny
+
+import "errors"
+
+// OptimalStrategy returns strategy for Traverse which minimizes cost of
+// computing isogeny with n leaves (n isogenies of small degree). mulCost is
+// cost of moving point one level towards leaves (i.e. multiplication by
+// degree of small isogeny) and evalCost is cost of evaluating small isogeny
+// at a point. Cost of constructing small isogenies doesn't depend on the
+// strategy.
+//
+// Implements dynamic programming algorithm from [DJP14], section 4.2, in
+// the form used by SIKE specification. Returned strategy has n-1 entries.
+// In case of ties the strategy with smaller first step is chosen, which
+// makes results reproducible.
+//
+// [DJP14] L. De Feo, D. Jao, J. Plut: Towards quantum-resistant
+//         cryptosystems from supersingular elliptic curve isogenies.
+func OptimalStrategy(n int, mulCost, evalCost float64) []uint32 {
+	if n < 1 {
+		return nil
+	}
+
+	// cost[i] and split[i] describe optimal strategy for i leaves
+	var cost = make([]float64, n+1)
+	var split = make([]int, n+1)
+	for i := 2; i <= n; i++ {
+		for b := 1; b < i; b++ {
+			c := cost[i-b] + cost[b] + float64(b)*mulCost + float64(i-b)*evalCost
+			if split[i] == 0 || c < cost[i] {
+				cost[i], split[i] = c, b
+			}
+		}
+	}
+
+	// S[i] = [b] || S[i-b] || S[b], where b = split[i]
+	var strategy = make([]uint32, 0, n-1)
+	var expand func(i int)
+	expand = func(i int) {
+		if i < 2 {
+			return
+		}
+		b := split[i]
+		strategy = append(strategy, uint32(b))
+		expand(i - b)
+		expand(b)
+	}
+	expand(n)
+	return strategy
+}
+
+// StrategyCost returns cost of computing isogeny with given strategy, using
+// the same cost model as OptimalStrategy. Returns error if strategy is
+// malformed.
+func StrategyCost(strategy []uint32, mulCost, evalCost float64) (float64, error) {
+	var cost float64
+	var sidx int
+
+	// Cost of a subtree with i leaves
+	var subtree func(i int) bool
+	subtree = func(i int) bool {
+		if i == 1 {
+			return true
+		}
+		if sidx == len(strategy) {
+			return false
+		}
+		b := int(strategy[sidx])
+		sidx++
+		if b < 1 || b >= i {
+			return false
+		}
+		cost += float64(b)*mulCost + float64(i-b)*evalCost
+		return subtree(i-b) && subtree(b)
+	}
+
+	if !subtree(len(strategy)+1) || sidx != len(strategy) {
+		return 0, errors.New("isogeny: invalid strategy")
+	}
+	return cost, nil
+}
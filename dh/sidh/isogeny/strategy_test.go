@@ -0,0 +1,106 @@
+package isogeny
+
+import (
+	"bytes"
+	"crypto/rand"
+	"reflect"
+	"testing"
+)
+
+// Costs for which the optimal strategy is the same as the shipped one
+var shippedCosts = []struct {
+	p           *Params
+	mulA, evalA float64
+	mulB, evalB float64
+}{
+	{P503(), 1, 1, 5, 4},
+	{P751(), 5, 4, 7, 6},
+}
+
+func TestOptimalStrategyShipped(t *testing.T) {
+	for _, v := range shippedCosts {
+		s := OptimalStrategy(len(v.p.StrategyA)+1, v.mulA, v.evalA)
+		if !reflect.DeepEqual(s, v.p.StrategyA) {
+			t.Errorf("%s: strategy A differs", v.p.Name)
+		}
+		s = OptimalStrategy(len(v.p.StrategyB)+1, v.mulB, v.evalB)
+		if !reflect.DeepEqual(s, v.p.StrategyB) {
+			t.Errorf("%s: strategy B differs", v.p.Name)
+		}
+	}
+}
+
+func TestOptimalStrategyCost(t *testing.T) {
+	for _, c := range [][2]float64{{1, 1}, {3449, 2511}, {5186, 1893}, {1, 100}, {100, 1}} {
+		for _, n := range []int{1, 2, 3, 17, 125, 239} {
+			s := OptimalStrategy(n, c[0], c[1])
+			if len(s) != n-1 {
+				t.Fatalf("n=%d: wrong length %d", n, len(s))
+			}
+			opt, err := StrategyCost(s, c[0], c[1])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Must not be worse than simple strategies
+			ones := make([]uint32, n-1)
+			for i := range ones {
+				ones[i] = 1
+			}
+			linear := make([]uint32, n-1)
+			for i := range linear {
+				linear[i] = uint32(n - 1 - i)
+			}
+			for _, other := range [][]uint32{ones, linear} {
+				cost, err := StrategyCost(other, c[0], c[1])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if opt > cost {
+					t.Errorf("n=%d %v: %f > %f", n, c, opt, cost)
+				}
+			}
+		}
+	}
+
+	for _, s := range [][]uint32{{0, 1}, {1, 2}, {3, 1}, {2, 1, 1, 2}, {2}} {
+		if _, err := StrategyCost(s, 1, 1); err == nil {
+			t.Errorf("%v: expected error", s)
+		}
+	}
+}
+
+// Strategy which differs from the shipped one must give the same
+// shared secret
+func TestOptimalStrategyTraverse(t *testing.T) {
+	for _, p := range params {
+		s := OptimalStrategy(len(p.StrategyA)+1, 10, 1)
+		if reflect.DeepEqual(s, p.StrategyA) {
+			t.Fatal("expected different strategy")
+		}
+
+		scalar := make([]byte, (p.SecretBitLenA+7)/8)
+		if _, err := rand.Read(scalar); err != nil {
+			t.Fatal(err)
+		}
+		xP, xQ, xR := p.NewPoint(&p.PA), p.NewPoint(&p.QA), p.NewPoint(&p.RA)
+		K := p.ScalarMul3Pt(&p.E0, &xP, &xQ, &xR, p.SecretBitLenA, scalar)
+		c1, err := p.Traverse(&p.E0, &K, 4, s, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c2, err := p.Traverse(&p.E0, &K, 4, p.StrategyA, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(p.Jinvariant(&c1), p.Jinvariant(&c2)) {
+			t.Errorf("%s: j-invariants differ", p.Name)
+		}
+	}
+}
+
+func BenchmarkOptimalStrategy(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		OptimalStrategy(239, 7, 6)
+	}
+}
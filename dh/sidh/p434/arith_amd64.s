@@ -0,0 +1,1144 @@
+// Code generated by fpgen. DO NOT EDIT.
+
+//go:build amd64 && !noasm
+// +build amd64,!noasm
+
+#include "textflag.h"
+
+TEXT ·fp434ConditionalSwap(SB), NOSPLIT, $0-17
+	MOVQ	x+0(FP), DI
+	MOVQ	y+8(FP), SI
+	MOVBQZX	choice+16(FP), AX
+
+	// mask = 0 - choice
+	NEGQ	AX
+	MOVQ	0(DI), BX
+	MOVQ	0(SI), CX
+	MOVQ	BX, DX
+	XORQ	CX, DX
+	ANDQ	AX, DX
+	XORQ	DX, BX
+	XORQ	DX, CX
+	MOVQ	BX, 0(DI)
+	MOVQ	CX, 0(SI)
+	MOVQ	8(DI), BX
+	MOVQ	8(SI), CX
+	MOVQ	BX, DX
+	XORQ	CX, DX
+	ANDQ	AX, DX
+	XORQ	DX, BX
+	XORQ	DX, CX
+	MOVQ	BX, 8(DI)
+	MOVQ	CX, 8(SI)
+	MOVQ	16(DI), BX
+	MOVQ	16(SI), CX
+	MOVQ	BX, DX
+	XORQ	CX, DX
+	ANDQ	AX, DX
+	XORQ	DX, BX
+	XORQ	DX, CX
+	MOVQ	BX, 16(DI)
+	MOVQ	CX, 16(SI)
+	MOVQ	24(DI), BX
+	MOVQ	24(SI), CX
+	MOVQ	BX, DX
+	XORQ	CX, DX
+	ANDQ	AX, DX
+	XORQ	DX, BX
+	XORQ	DX, CX
+	MOVQ	BX, 24(DI)
+	MOVQ	CX, 24(SI)
+	MOVQ	32(DI), BX
+	MOVQ	32(SI), CX
+	MOVQ	BX, DX
+	XORQ	CX, DX
+	ANDQ	AX, DX
+	XORQ	DX, BX
+	XORQ	DX, CX
+	MOVQ	BX, 32(DI)
+	MOVQ	CX, 32(SI)
+	MOVQ	40(DI), BX
+	MOVQ	40(SI), CX
+	MOVQ	BX, DX
+	XORQ	CX, DX
+	ANDQ	AX, DX
+	XORQ	DX, BX
+	XORQ	DX, CX
+	MOVQ	BX, 40(DI)
+	MOVQ	CX, 40(SI)
+	MOVQ	48(DI), BX
+	MOVQ	48(SI), CX
+	MOVQ	BX, DX
+	XORQ	CX, DX
+	ANDQ	AX, DX
+	XORQ	DX, BX
+	XORQ	DX, CX
+	MOVQ	BX, 48(DI)
+	MOVQ	CX, 48(SI)
+	RET	
+
+TEXT ·fp434AddReduced(SB), NOSPLIT, $56-24
+	MOVQ	z+0(FP), DI
+	MOVQ	x+8(FP), SI
+	MOVQ	y+16(FP), BX
+
+	// z = x + y
+	MOVQ	0(SI), AX
+	ADDQ	0(BX), AX
+	MOVQ	AX, 0(DI)
+	MOVQ	8(SI), AX
+	ADCQ	8(BX), AX
+	MOVQ	AX, 8(DI)
+	MOVQ	16(SI), AX
+	ADCQ	16(BX), AX
+	MOVQ	AX, 16(DI)
+	MOVQ	24(SI), AX
+	ADCQ	24(BX), AX
+	MOVQ	AX, 24(DI)
+	MOVQ	32(SI), AX
+	ADCQ	32(BX), AX
+	MOVQ	AX, 32(DI)
+	MOVQ	40(SI), AX
+	ADCQ	40(BX), AX
+	MOVQ	AX, 40(DI)
+	MOVQ	48(SI), AX
+	ADCQ	48(BX), AX
+	MOVQ	AX, 48(DI)
+
+	// DI = DI - p434x2
+	MOVQ	0(DI), AX
+	SUBQ	·p434x2+0(SB), AX
+	MOVQ	AX, 0(DI)
+	MOVQ	8(DI), AX
+	SBBQ	·p434x2+8(SB), AX
+	MOVQ	AX, 8(DI)
+	MOVQ	16(DI), AX
+	SBBQ	·p434x2+16(SB), AX
+	MOVQ	AX, 16(DI)
+	MOVQ	24(DI), AX
+	SBBQ	·p434x2+24(SB), AX
+	MOVQ	AX, 24(DI)
+	MOVQ	32(DI), AX
+	SBBQ	·p434x2+32(SB), AX
+	MOVQ	AX, 32(DI)
+	MOVQ	40(DI), AX
+	SBBQ	·p434x2+40(SB), AX
+	MOVQ	AX, 40(DI)
+	MOVQ	48(DI), AX
+	SBBQ	·p434x2+48(SB), AX
+	MOVQ	AX, 48(DI)
+
+	// if DI<0 add p434x2 back
+	SBBQ	CX, CX
+	MOVQ	·p434x2+0(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t0-8(SP)
+	MOVQ	·p434x2+8(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t1-16(SP)
+	MOVQ	·p434x2+16(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t2-24(SP)
+	MOVQ	·p434x2+24(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t3-32(SP)
+	MOVQ	·p434x2+32(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t4-40(SP)
+	MOVQ	·p434x2+40(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t5-48(SP)
+	MOVQ	·p434x2+48(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t6-56(SP)
+	MOVQ	0(DI), AX
+	ADDQ	t0-8(SP), AX
+	MOVQ	AX, 0(DI)
+	MOVQ	8(DI), AX
+	ADCQ	t1-16(SP), AX
+	MOVQ	AX, 8(DI)
+	MOVQ	16(DI), AX
+	ADCQ	t2-24(SP), AX
+	MOVQ	AX, 16(DI)
+	MOVQ	24(DI), AX
+	ADCQ	t3-32(SP), AX
+	MOVQ	AX, 24(DI)
+	MOVQ	32(DI), AX
+	ADCQ	t4-40(SP), AX
+	MOVQ	AX, 32(DI)
+	MOVQ	40(DI), AX
+	ADCQ	t5-48(SP), AX
+	MOVQ	AX, 40(DI)
+	MOVQ	48(DI), AX
+	ADCQ	t6-56(SP), AX
+	MOVQ	AX, 48(DI)
+	RET	
+
+TEXT ·fp434SubReduced(SB), NOSPLIT, $56-24
+	MOVQ	z+0(FP), DI
+	MOVQ	x+8(FP), SI
+	MOVQ	y+16(FP), BX
+
+	// z = x - y
+	MOVQ	0(SI), AX
+	SUBQ	0(BX), AX
+	MOVQ	AX, 0(DI)
+	MOVQ	8(SI), AX
+	SBBQ	8(BX), AX
+	MOVQ	AX, 8(DI)
+	MOVQ	16(SI), AX
+	SBBQ	16(BX), AX
+	MOVQ	AX, 16(DI)
+	MOVQ	24(SI), AX
+	SBBQ	24(BX), AX
+	MOVQ	AX, 24(DI)
+	MOVQ	32(SI), AX
+	SBBQ	32(BX), AX
+	MOVQ	AX, 32(DI)
+	MOVQ	40(SI), AX
+	SBBQ	40(BX), AX
+	MOVQ	AX, 40(DI)
+	MOVQ	48(SI), AX
+	SBBQ	48(BX), AX
+	MOVQ	AX, 48(DI)
+
+	// if DI<0 add p434x2 back
+	SBBQ	CX, CX
+	MOVQ	·p434x2+0(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t0-8(SP)
+	MOVQ	·p434x2+8(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t1-16(SP)
+	MOVQ	·p434x2+16(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t2-24(SP)
+	MOVQ	·p434x2+24(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t3-32(SP)
+	MOVQ	·p434x2+32(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t4-40(SP)
+	MOVQ	·p434x2+40(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t5-48(SP)
+	MOVQ	·p434x2+48(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t6-56(SP)
+	MOVQ	0(DI), AX
+	ADDQ	t0-8(SP), AX
+	MOVQ	AX, 0(DI)
+	MOVQ	8(DI), AX
+	ADCQ	t1-16(SP), AX
+	MOVQ	AX, 8(DI)
+	MOVQ	16(DI), AX
+	ADCQ	t2-24(SP), AX
+	MOVQ	AX, 16(DI)
+	MOVQ	24(DI), AX
+	ADCQ	t3-32(SP), AX
+	MOVQ	AX, 24(DI)
+	MOVQ	32(DI), AX
+	ADCQ	t4-40(SP), AX
+	MOVQ	AX, 32(DI)
+	MOVQ	40(DI), AX
+	ADCQ	t5-48(SP), AX
+	MOVQ	AX, 40(DI)
+	MOVQ	48(DI), AX
+	ADCQ	t6-56(SP), AX
+	MOVQ	AX, 48(DI)
+	RET	
+
+TEXT ·fp434AddLazy(SB), NOSPLIT, $0-24
+	MOVQ	z+0(FP), DI
+	MOVQ	x+8(FP), SI
+	MOVQ	y+16(FP), BX
+	MOVQ	0(SI), AX
+	ADDQ	0(BX), AX
+	MOVQ	AX, 0(DI)
+	MOVQ	8(SI), AX
+	ADCQ	8(BX), AX
+	MOVQ	AX, 8(DI)
+	MOVQ	16(SI), AX
+	ADCQ	16(BX), AX
+	MOVQ	AX, 16(DI)
+	MOVQ	24(SI), AX
+	ADCQ	24(BX), AX
+	MOVQ	AX, 24(DI)
+	MOVQ	32(SI), AX
+	ADCQ	32(BX), AX
+	MOVQ	AX, 32(DI)
+	MOVQ	40(SI), AX
+	ADCQ	40(BX), AX
+	MOVQ	AX, 40(DI)
+	MOVQ	48(SI), AX
+	ADCQ	48(BX), AX
+	MOVQ	AX, 48(DI)
+	RET	
+
+TEXT ·fp434X2AddLazy(SB), NOSPLIT, $0-24
+	MOVQ	z+0(FP), DI
+	MOVQ	x+8(FP), SI
+	MOVQ	y+16(FP), BX
+	MOVQ	0(SI), AX
+	ADDQ	0(BX), AX
+	MOVQ	AX, 0(DI)
+	MOVQ	8(SI), AX
+	ADCQ	8(BX), AX
+	MOVQ	AX, 8(DI)
+	MOVQ	16(SI), AX
+	ADCQ	16(BX), AX
+	MOVQ	AX, 16(DI)
+	MOVQ	24(SI), AX
+	ADCQ	24(BX), AX
+	MOVQ	AX, 24(DI)
+	MOVQ	32(SI), AX
+	ADCQ	32(BX), AX
+	MOVQ	AX, 32(DI)
+	MOVQ	40(SI), AX
+	ADCQ	40(BX), AX
+	MOVQ	AX, 40(DI)
+	MOVQ	48(SI), AX
+	ADCQ	48(BX), AX
+	MOVQ	AX, 48(DI)
+	MOVQ	56(SI), AX
+	ADCQ	56(BX), AX
+	MOVQ	AX, 56(DI)
+	MOVQ	64(SI), AX
+	ADCQ	64(BX), AX
+	MOVQ	AX, 64(DI)
+	MOVQ	72(SI), AX
+	ADCQ	72(BX), AX
+	MOVQ	AX, 72(DI)
+	MOVQ	80(SI), AX
+	ADCQ	80(BX), AX
+	MOVQ	AX, 80(DI)
+	MOVQ	88(SI), AX
+	ADCQ	88(BX), AX
+	MOVQ	AX, 88(DI)
+	MOVQ	96(SI), AX
+	ADCQ	96(BX), AX
+	MOVQ	AX, 96(DI)
+	MOVQ	104(SI), AX
+	ADCQ	104(BX), AX
+	MOVQ	AX, 104(DI)
+	RET	
+
+TEXT ·fp434X2SubLazy(SB), NOSPLIT, $56-24
+	MOVQ	z+0(FP), DI
+	MOVQ	x+8(FP), SI
+	MOVQ	y+16(FP), BX
+
+	// z = x - y
+	MOVQ	0(SI), AX
+	SUBQ	0(BX), AX
+	MOVQ	AX, 0(DI)
+	MOVQ	8(SI), AX
+	SBBQ	8(BX), AX
+	MOVQ	AX, 8(DI)
+	MOVQ	16(SI), AX
+	SBBQ	16(BX), AX
+	MOVQ	AX, 16(DI)
+	MOVQ	24(SI), AX
+	SBBQ	24(BX), AX
+	MOVQ	AX, 24(DI)
+	MOVQ	32(SI), AX
+	SBBQ	32(BX), AX
+	MOVQ	AX, 32(DI)
+	MOVQ	40(SI), AX
+	SBBQ	40(BX), AX
+	MOVQ	AX, 40(DI)
+	MOVQ	48(SI), AX
+	SBBQ	48(BX), AX
+	MOVQ	AX, 48(DI)
+	MOVQ	56(SI), AX
+	SBBQ	56(BX), AX
+	MOVQ	AX, 56(DI)
+	MOVQ	64(SI), AX
+	SBBQ	64(BX), AX
+	MOVQ	AX, 64(DI)
+	MOVQ	72(SI), AX
+	SBBQ	72(BX), AX
+	MOVQ	AX, 72(DI)
+	MOVQ	80(SI), AX
+	SBBQ	80(BX), AX
+	MOVQ	AX, 80(DI)
+	MOVQ	88(SI), AX
+	SBBQ	88(BX), AX
+	MOVQ	AX, 88(DI)
+	MOVQ	96(SI), AX
+	SBBQ	96(BX), AX
+	MOVQ	AX, 96(DI)
+	MOVQ	104(SI), AX
+	SBBQ	104(BX), AX
+	MOVQ	AX, 104(DI)
+
+	// if DI<0 add p434 back
+	SBBQ	CX, CX
+	MOVQ	·p434+0(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t0-8(SP)
+	MOVQ	·p434+8(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t1-16(SP)
+	MOVQ	·p434+16(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t2-24(SP)
+	MOVQ	·p434+24(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t3-32(SP)
+	MOVQ	·p434+32(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t4-40(SP)
+	MOVQ	·p434+40(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t5-48(SP)
+	MOVQ	·p434+48(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t6-56(SP)
+	MOVQ	56(DI), AX
+	ADDQ	t0-8(SP), AX
+	MOVQ	AX, 56(DI)
+	MOVQ	64(DI), AX
+	ADCQ	t1-16(SP), AX
+	MOVQ	AX, 64(DI)
+	MOVQ	72(DI), AX
+	ADCQ	t2-24(SP), AX
+	MOVQ	AX, 72(DI)
+	MOVQ	80(DI), AX
+	ADCQ	t3-32(SP), AX
+	MOVQ	AX, 80(DI)
+	MOVQ	88(DI), AX
+	ADCQ	t4-40(SP), AX
+	MOVQ	AX, 88(DI)
+	MOVQ	96(DI), AX
+	ADCQ	t5-48(SP), AX
+	MOVQ	AX, 96(DI)
+	MOVQ	104(DI), AX
+	ADCQ	t6-56(SP), AX
+	MOVQ	AX, 104(DI)
+	RET	
+
+TEXT ·fp434StrongReduce(SB), NOSPLIT, $56-8
+	MOVQ	x+0(FP), DI
+
+	// DI = DI - p434
+	MOVQ	0(DI), AX
+	SUBQ	·p434+0(SB), AX
+	MOVQ	AX, 0(DI)
+	MOVQ	8(DI), AX
+	SBBQ	·p434+8(SB), AX
+	MOVQ	AX, 8(DI)
+	MOVQ	16(DI), AX
+	SBBQ	·p434+16(SB), AX
+	MOVQ	AX, 16(DI)
+	MOVQ	24(DI), AX
+	SBBQ	·p434+24(SB), AX
+	MOVQ	AX, 24(DI)
+	MOVQ	32(DI), AX
+	SBBQ	·p434+32(SB), AX
+	MOVQ	AX, 32(DI)
+	MOVQ	40(DI), AX
+	SBBQ	·p434+40(SB), AX
+	MOVQ	AX, 40(DI)
+	MOVQ	48(DI), AX
+	SBBQ	·p434+48(SB), AX
+	MOVQ	AX, 48(DI)
+
+	// if DI<0 add p434 back
+	SBBQ	CX, CX
+	MOVQ	·p434+0(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t0-8(SP)
+	MOVQ	·p434+8(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t1-16(SP)
+	MOVQ	·p434+16(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t2-24(SP)
+	MOVQ	·p434+24(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t3-32(SP)
+	MOVQ	·p434+32(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t4-40(SP)
+	MOVQ	·p434+40(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t5-48(SP)
+	MOVQ	·p434+48(SB), AX
+	ANDQ	CX, AX
+	MOVQ	AX, t6-56(SP)
+	MOVQ	0(DI), AX
+	ADDQ	t0-8(SP), AX
+	MOVQ	AX, 0(DI)
+	MOVQ	8(DI), AX
+	ADCQ	t1-16(SP), AX
+	MOVQ	AX, 8(DI)
+	MOVQ	16(DI), AX
+	ADCQ	t2-24(SP), AX
+	MOVQ	AX, 16(DI)
+	MOVQ	24(DI), AX
+	ADCQ	t3-32(SP), AX
+	MOVQ	AX, 24(DI)
+	MOVQ	32(DI), AX
+	ADCQ	t4-40(SP), AX
+	MOVQ	AX, 32(DI)
+	MOVQ	40(DI), AX
+	ADCQ	t5-48(SP), AX
+	MOVQ	AX, 40(DI)
+	MOVQ	48(DI), AX
+	ADCQ	t6-56(SP), AX
+	MOVQ	AX, 48(DI)
+	RET	
+
+TEXT ·fp434Mul(SB), NOSPLIT, $0-24
+	MOVQ	z+0(FP), DI
+	MOVQ	x+8(FP), SI
+	MOVQ	y+16(FP), BX
+	XORQ	R8, R8
+	XORQ	R9, R9
+	XORQ	R10, R10
+
+	// z[0]
+	MOVQ	0(SI), AX
+	MULQ	0(BX)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	R8, 0(DI)
+	XORQ	R8, R8
+
+	// z[1]
+	MOVQ	0(SI), AX
+	MULQ	8(BX)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	8(SI), AX
+	MULQ	0(BX)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	R9, 8(DI)
+	XORQ	R9, R9
+
+	// z[2]
+	MOVQ	0(SI), AX
+	MULQ	16(BX)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	8(SI), AX
+	MULQ	8(BX)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	16(SI), AX
+	MULQ	0(BX)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	R10, 16(DI)
+	XORQ	R10, R10
+
+	// z[3]
+	MOVQ	0(SI), AX
+	MULQ	24(BX)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	8(SI), AX
+	MULQ	16(BX)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	16(SI), AX
+	MULQ	8(BX)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	24(SI), AX
+	MULQ	0(BX)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	R8, 24(DI)
+	XORQ	R8, R8
+
+	// z[4]
+	MOVQ	0(SI), AX
+	MULQ	32(BX)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	8(SI), AX
+	MULQ	24(BX)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	16(SI), AX
+	MULQ	16(BX)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	24(SI), AX
+	MULQ	8(BX)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	32(SI), AX
+	MULQ	0(BX)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	R9, 32(DI)
+	XORQ	R9, R9
+
+	// z[5]
+	MOVQ	0(SI), AX
+	MULQ	40(BX)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	8(SI), AX
+	MULQ	32(BX)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	16(SI), AX
+	MULQ	24(BX)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	24(SI), AX
+	MULQ	16(BX)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	32(SI), AX
+	MULQ	8(BX)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	40(SI), AX
+	MULQ	0(BX)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	R10, 40(DI)
+	XORQ	R10, R10
+
+	// z[6]
+	MOVQ	0(SI), AX
+	MULQ	48(BX)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	8(SI), AX
+	MULQ	40(BX)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	16(SI), AX
+	MULQ	32(BX)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	24(SI), AX
+	MULQ	24(BX)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	32(SI), AX
+	MULQ	16(BX)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	40(SI), AX
+	MULQ	8(BX)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	48(SI), AX
+	MULQ	0(BX)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	R8, 48(DI)
+	XORQ	R8, R8
+
+	// z[7]
+	MOVQ	8(SI), AX
+	MULQ	48(BX)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	16(SI), AX
+	MULQ	40(BX)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	24(SI), AX
+	MULQ	32(BX)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	32(SI), AX
+	MULQ	24(BX)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	40(SI), AX
+	MULQ	16(BX)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	48(SI), AX
+	MULQ	8(BX)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	R9, 56(DI)
+	XORQ	R9, R9
+
+	// z[8]
+	MOVQ	16(SI), AX
+	MULQ	48(BX)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	24(SI), AX
+	MULQ	40(BX)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	32(SI), AX
+	MULQ	32(BX)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	40(SI), AX
+	MULQ	24(BX)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	48(SI), AX
+	MULQ	16(BX)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	R10, 64(DI)
+	XORQ	R10, R10
+
+	// z[9]
+	MOVQ	24(SI), AX
+	MULQ	48(BX)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	32(SI), AX
+	MULQ	40(BX)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	40(SI), AX
+	MULQ	32(BX)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	48(SI), AX
+	MULQ	24(BX)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	R8, 72(DI)
+	XORQ	R8, R8
+
+	// z[10]
+	MOVQ	32(SI), AX
+	MULQ	48(BX)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	40(SI), AX
+	MULQ	40(BX)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	48(SI), AX
+	MULQ	32(BX)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	R9, 80(DI)
+	XORQ	R9, R9
+
+	// z[11]
+	MOVQ	40(SI), AX
+	MULQ	48(BX)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	48(SI), AX
+	MULQ	40(BX)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	R10, 88(DI)
+	XORQ	R10, R10
+
+	// z[12]
+	MOVQ	48(SI), AX
+	MULQ	48(BX)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	R8, 96(DI)
+	XORQ	R8, R8
+	MOVQ	R9, 104(DI)
+	RET	
+
+TEXT ·fp434MontgomeryReduce(SB), NOSPLIT, $56-16
+	MOVQ	z+0(FP), DI
+	MOVQ	x+8(FP), SI
+	XORQ	R8, R8
+	XORQ	R9, R9
+	XORQ	R10, R10
+
+	// m[0]
+	ADDQ	0(SI), R8
+	ADCQ	$0, R9
+	ADCQ	$0, R10
+	MOVQ	R8, AX
+	MOVQ	AX, m0-8(SP)
+	MULQ	·p434+0(SB)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	XORQ	R8, R8
+
+	// m[1]
+	MOVQ	m0-8(SP), AX
+	MULQ	·p434+8(SB)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	ADDQ	8(SI), R9
+	ADCQ	$0, R10
+	ADCQ	$0, R8
+	MOVQ	R9, AX
+	MOVQ	AX, m1-16(SP)
+	MULQ	·p434+0(SB)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	XORQ	R9, R9
+
+	// m[2]
+	MOVQ	m0-8(SP), AX
+	MULQ	·p434+16(SB)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	m1-16(SP), AX
+	MULQ	·p434+8(SB)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	ADDQ	16(SI), R10
+	ADCQ	$0, R8
+	ADCQ	$0, R9
+	MOVQ	R10, AX
+	MOVQ	AX, m2-24(SP)
+	MULQ	·p434+0(SB)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	XORQ	R10, R10
+
+	// m[3]
+	MOVQ	m0-8(SP), AX
+	MULQ	·p434+24(SB)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	m1-16(SP), AX
+	MULQ	·p434+16(SB)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	m2-24(SP), AX
+	MULQ	·p434+8(SB)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	ADDQ	24(SI), R8
+	ADCQ	$0, R9
+	ADCQ	$0, R10
+	MOVQ	R8, AX
+	MOVQ	AX, m3-32(SP)
+	MULQ	·p434+0(SB)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	XORQ	R8, R8
+
+	// m[4]
+	MOVQ	m0-8(SP), AX
+	MULQ	·p434+32(SB)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	m1-16(SP), AX
+	MULQ	·p434+24(SB)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	m2-24(SP), AX
+	MULQ	·p434+16(SB)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	m3-32(SP), AX
+	MULQ	·p434+8(SB)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	ADDQ	32(SI), R9
+	ADCQ	$0, R10
+	ADCQ	$0, R8
+	MOVQ	R9, AX
+	MOVQ	AX, m4-40(SP)
+	MULQ	·p434+0(SB)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	XORQ	R9, R9
+
+	// m[5]
+	MOVQ	m0-8(SP), AX
+	MULQ	·p434+40(SB)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	m1-16(SP), AX
+	MULQ	·p434+32(SB)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	m2-24(SP), AX
+	MULQ	·p434+24(SB)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	m3-32(SP), AX
+	MULQ	·p434+16(SB)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	m4-40(SP), AX
+	MULQ	·p434+8(SB)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	ADDQ	40(SI), R10
+	ADCQ	$0, R8
+	ADCQ	$0, R9
+	MOVQ	R10, AX
+	MOVQ	AX, m5-48(SP)
+	MULQ	·p434+0(SB)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	XORQ	R10, R10
+
+	// m[6]
+	MOVQ	m0-8(SP), AX
+	MULQ	·p434+48(SB)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	m1-16(SP), AX
+	MULQ	·p434+40(SB)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	m2-24(SP), AX
+	MULQ	·p434+32(SB)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	m3-32(SP), AX
+	MULQ	·p434+24(SB)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	m4-40(SP), AX
+	MULQ	·p434+16(SB)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	m5-48(SP), AX
+	MULQ	·p434+8(SB)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	ADDQ	48(SI), R8
+	ADCQ	$0, R9
+	ADCQ	$0, R10
+	MOVQ	R8, AX
+	MOVQ	AX, m6-56(SP)
+	MULQ	·p434+0(SB)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	XORQ	R8, R8
+
+	// z[0]
+	MOVQ	m1-16(SP), AX
+	MULQ	·p434+48(SB)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	m2-24(SP), AX
+	MULQ	·p434+40(SB)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	m3-32(SP), AX
+	MULQ	·p434+32(SB)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	m4-40(SP), AX
+	MULQ	·p434+24(SB)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	m5-48(SP), AX
+	MULQ	·p434+16(SB)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	m6-56(SP), AX
+	MULQ	·p434+8(SB)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	ADDQ	56(SI), R9
+	ADCQ	$0, R10
+	ADCQ	$0, R8
+	MOVQ	R9, 0(DI)
+	XORQ	R9, R9
+
+	// z[1]
+	MOVQ	m2-24(SP), AX
+	MULQ	·p434+48(SB)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	m3-32(SP), AX
+	MULQ	·p434+40(SB)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	m4-40(SP), AX
+	MULQ	·p434+32(SB)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	m5-48(SP), AX
+	MULQ	·p434+24(SB)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	m6-56(SP), AX
+	MULQ	·p434+16(SB)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	ADDQ	64(SI), R10
+	ADCQ	$0, R8
+	ADCQ	$0, R9
+	MOVQ	R10, 8(DI)
+	XORQ	R10, R10
+
+	// z[2]
+	MOVQ	m3-32(SP), AX
+	MULQ	·p434+48(SB)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	m4-40(SP), AX
+	MULQ	·p434+40(SB)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	m5-48(SP), AX
+	MULQ	·p434+32(SB)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	MOVQ	m6-56(SP), AX
+	MULQ	·p434+24(SB)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	ADDQ	72(SI), R8
+	ADCQ	$0, R9
+	ADCQ	$0, R10
+	MOVQ	R8, 16(DI)
+	XORQ	R8, R8
+
+	// z[3]
+	MOVQ	m4-40(SP), AX
+	MULQ	·p434+48(SB)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	m5-48(SP), AX
+	MULQ	·p434+40(SB)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	MOVQ	m6-56(SP), AX
+	MULQ	·p434+32(SB)
+	ADDQ	AX, R9
+	ADCQ	DX, R10
+	ADCQ	$0, R8
+	ADDQ	80(SI), R9
+	ADCQ	$0, R10
+	ADCQ	$0, R8
+	MOVQ	R9, 24(DI)
+	XORQ	R9, R9
+
+	// z[4]
+	MOVQ	m5-48(SP), AX
+	MULQ	·p434+48(SB)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	MOVQ	m6-56(SP), AX
+	MULQ	·p434+40(SB)
+	ADDQ	AX, R10
+	ADCQ	DX, R8
+	ADCQ	$0, R9
+	ADDQ	88(SI), R10
+	ADCQ	$0, R8
+	ADCQ	$0, R9
+	MOVQ	R10, 32(DI)
+	XORQ	R10, R10
+
+	// z[5]
+	MOVQ	m6-56(SP), AX
+	MULQ	·p434+48(SB)
+	ADDQ	AX, R8
+	ADCQ	DX, R9
+	ADCQ	$0, R10
+	ADDQ	96(SI), R8
+	ADCQ	$0, R9
+	ADCQ	$0, R10
+	MOVQ	R8, 40(DI)
+	XORQ	R8, R8
+	ADDQ	104(SI), R9
+	MOVQ	R9, 48(DI)
+	RET	
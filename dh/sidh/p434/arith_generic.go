@@ -0,0 +1,186 @@
+// Code generated by fpgen. DO NOT EDIT.
+
+//go:build noasm || (!amd64 && !arm64)
+// +build noasm !amd64,!arm64
+
+package p434
+
+import (
+	"math/bits"
+
+	. "github.com/henrydcase/nobs/dh/sidh/internal/isogeny"
+)
+
+// Compute z = x + y (mod p).
+func fp434AddReduced(z, x, y *FpElement) {
+	var carry uint64
+
+	// z=x+y % p434
+	for i := 0; i < NumWords; i++ {
+		z[i], carry = bits.Add64(x[i], y[i], carry)
+	}
+
+	// z = z - p434x2
+	carry = 0
+	for i := 0; i < NumWords; i++ {
+		z[i], carry = bits.Sub64(z[i], p434x2[i], carry)
+	}
+
+	// if z<0 add p434x2 back
+	mask := uint64(0 - carry)
+	carry = 0
+	for i := 0; i < NumWords; i++ {
+		z[i], carry = bits.Add64(z[i], p434x2[i]&mask, carry)
+	}
+}
+
+// Compute z = x - y (mod p).
+func fp434SubReduced(z, x, y *FpElement) {
+	var borrow uint64
+
+	// z = x - y
+	for i := 0; i < NumWords; i++ {
+		z[i], borrow = bits.Sub64(x[i], y[i], borrow)
+	}
+
+	// if z<0 add p434x2 back
+	mask := uint64(0 - borrow)
+	borrow = 0
+	for i := 0; i < NumWords; i++ {
+		z[i], borrow = bits.Add64(z[i], p434x2[i]&mask, borrow)
+	}
+}
+
+// Conditionally swaps bits in x and y in constant time.
+// mask indicates bits to be swapped (set bits are swapped)
+// For details see "Hackers Delight, 2.20"
+func fp434ConditionalSwap(x, y *FpElement, mask uint8) {
+	var tmp, mask64 uint64
+
+	mask64 = 0 - uint64(mask)
+	for i := 0; i < NumWords; i++ {
+		tmp = mask64 & (x[i] ^ y[i])
+		x[i] = tmp ^ x[i]
+		y[i] = tmp ^ y[i]
+	}
+}
+
+// Perform Montgomery reduction: set z = x R^{-1} (mod 2*p)
+// with R=2^448. Requires x < p*R.
+func fp434MontgomeryReduce(z *FpElement, x *FpElementX2) {
+	var m [NumWords]uint64
+	var t, u, v, hi, lo, carry uint64
+
+	// Product scanning, (t,u,v) is a column accumulator
+	for i := 0; i < NumWords; i++ {
+		for j := 0; j < i; j++ {
+			hi, lo = bits.Mul64(m[j], p434[i-j])
+			v, carry = bits.Add64(v, lo, 0)
+			u, carry = bits.Add64(u, hi, carry)
+			t += carry
+		}
+		v, carry = bits.Add64(v, x[i], 0)
+		u, carry = bits.Add64(u, 0, carry)
+		t += carry
+
+		// m[i] is chosen so that lowest word of accumulator becomes 0
+		m[i] = v * p434MPrime
+		hi, lo = bits.Mul64(m[i], p434[0])
+		v, carry = bits.Add64(v, lo, 0)
+		u, carry = bits.Add64(u, hi, carry)
+		t += carry
+
+		v, u, t = u, t, 0
+	}
+
+	for i := NumWords; i < 2*NumWords-1; i++ {
+		for j := i - NumWords + 1; j < NumWords; j++ {
+			hi, lo = bits.Mul64(m[j], p434[i-j])
+			v, carry = bits.Add64(v, lo, 0)
+			u, carry = bits.Add64(u, hi, carry)
+			t += carry
+		}
+		v, carry = bits.Add64(v, x[i], 0)
+		u, carry = bits.Add64(u, 0, carry)
+		t += carry
+
+		z[i-NumWords] = v
+		v, u, t = u, t, 0
+	}
+	z[NumWords-1] = v + x[2*NumWords-1]
+}
+
+// Compute z = x + y, without reducing mod p.
+func fp434AddLazy(z, x, y *FpElement) {
+	var carry uint64
+	for i := 0; i < NumWords; i++ {
+		z[i], carry = bits.Add64(x[i], y[i], carry)
+	}
+}
+
+// Compute z = x + y, without reducing mod p.
+func fp434X2AddLazy(z, x, y *FpElementX2) {
+	var carry uint64
+	for i := 0; i < 2*NumWords; i++ {
+		z[i], carry = bits.Add64(x[i], y[i], carry)
+	}
+}
+
+// Reduce a field element in [0, 2*p) to one in [0,p).
+func fp434StrongReduce(x *FpElement) {
+	var borrow, mask uint64
+	for i := 0; i < NumWords; i++ {
+		x[i], borrow = bits.Sub64(x[i], p434[i], borrow)
+	}
+
+	// Mask is 0 if x>=p, otherwise -1
+	mask = 0 - borrow
+	borrow = 0
+	for i := 0; i < NumWords; i++ {
+		x[i], borrow = bits.Add64(x[i], p434[i]&mask, borrow)
+	}
+}
+
+// Compute z = x - y, without reducing mod p.
+func fp434X2SubLazy(z, x, y *FpElementX2) {
+	var borrow, mask uint64
+	for i := 0; i < 2*NumWords; i++ {
+		z[i], borrow = bits.Sub64(x[i], y[i], borrow)
+	}
+
+	// if z<0, add p*R back
+	mask = 0 - borrow
+	borrow = 0
+	for i := NumWords; i < 2*NumWords; i++ {
+		z[i], borrow = bits.Add64(z[i], p434[i-NumWords]&mask, borrow)
+	}
+}
+
+// Compute z = x * y.
+func fp434Mul(z *FpElementX2, x, y *FpElement) {
+	var t, u, v, hi, lo, carry uint64
+
+	// Product scanning, (t,u,v) is a column accumulator
+	for i := 0; i < NumWords; i++ {
+		for j := 0; j <= i; j++ {
+			hi, lo = bits.Mul64(x[j], y[i-j])
+			v, carry = bits.Add64(v, lo, 0)
+			u, carry = bits.Add64(u, hi, carry)
+			t += carry
+		}
+		z[i] = v
+		v, u, t = u, t, 0
+	}
+
+	for i := NumWords; i < 2*NumWords-1; i++ {
+		for j := i - NumWords + 1; j < NumWords; j++ {
+			hi, lo = bits.Mul64(x[j], y[i-j])
+			v, carry = bits.Add64(v, lo, 0)
+			u, carry = bits.Add64(u, hi, carry)
+			t += carry
+		}
+		z[i] = v
+		v, u, t = u, t, 0
+	}
+	z[2*NumWords-1] = v
+}
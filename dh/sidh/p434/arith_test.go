@@ -0,0 +1,361 @@
+// Code generated by fpgen. DO NOT EDIT.
+
+package p434
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	. "github.com/henrydcase/nobs/dh/sidh/internal/isogeny"
+)
+
+// Number of values used by tests. Each binary operation is checked
+// on all pairs.
+const fp434TestValues = 40
+
+var (
+	fp434BigP     = fp434ToBig(p434[:NumWords])
+	fp434BigR     = new(big.Int).Lsh(big.NewInt(1), 64*NumWords)
+	fp434BigBound = new(big.Int).Mul(big.NewInt(2), fp434BigP)
+	fp434BigPR    = new(big.Int).Mul(fp434BigP, fp434BigR)
+	fp434BigRInv  = new(big.Int).ModInverse(fp434BigR, fp434BigP)
+)
+
+// Converts little-endian words to big.Int
+func fp434ToBig(x []uint64) *big.Int {
+	b := new(big.Int)
+	for i := len(x) - 1; i >= 0; i-- {
+		b.Lsh(b, 64)
+		b.Or(b, new(big.Int).SetUint64(x[i]))
+	}
+	return b
+}
+
+// Converts b to little-endian words. Panics if b doesn't fit in x.
+func fp434FromBig(x []uint64, b *big.Int) {
+	t := new(big.Int).Set(b)
+	mask := new(big.Int).SetUint64(^uint64(0))
+	for i := range x {
+		x[i] = new(big.Int).And(t, mask).Uint64()
+		t.Rsh(t, 64)
+	}
+	if t.Sign() != 0 {
+		panic("value too big")
+	}
+}
+
+// Returns values from [0, max), starting with edge cases
+func fp434Values(rng *rand.Rand, max *big.Int) []*big.Int {
+	one := big.NewInt(1)
+	var vals []*big.Int
+	for _, v := range []*big.Int{
+		big.NewInt(0),
+		one,
+		new(big.Int).Sub(fp434BigP, one),
+		fp434BigP,
+		new(big.Int).Add(fp434BigP, one),
+		new(big.Int).Sub(max, one),
+	} {
+		if v.Cmp(max) < 0 {
+			vals = append(vals, v)
+		}
+	}
+	for len(vals) < fp434TestValues {
+		vals = append(vals, new(big.Int).Rand(rng, max))
+	}
+	return vals
+}
+
+// Checks if got is equal to exp
+func fp434CheckEq(t *testing.T, op string, got []uint64, exp *big.Int, args ...*big.Int) {
+	if g := fp434ToBig(got); g.Cmp(exp) != 0 {
+		t.Errorf("%s%x:\n got: %x\n exp: %x", op, args, g, exp)
+	}
+}
+
+// Checks if got is in [0, bound) and congruent to exp modulo p
+func fp434CheckMod(t *testing.T, op string, got []uint64, exp *big.Int, args ...*big.Int) {
+	g := fp434ToBig(got)
+	d := new(big.Int).Sub(g, exp)
+	if g.Cmp(fp434BigBound) >= 0 || d.Mod(d, fp434BigP).Sign() != 0 {
+		t.Errorf("%s%x:\n got: %x\n exp: %x (mod p)", op, args, g, exp)
+	}
+}
+
+// Runs f on all pairs of values from [0, max)
+func fp434ForPairs(max *big.Int, f func(a, b *big.Int)) {
+	vals := fp434Values(rand.New(rand.NewSource(1)), max)
+	for _, a := range vals {
+		for _, b := range vals {
+			f(a, b)
+		}
+	}
+}
+
+func TestP434Constants(t *testing.T) {
+	if !fp434BigP.ProbablyPrime(20) {
+		t.Error("p is not prime")
+	}
+	m := new(big.Int).SetUint64(p434MPrime)
+	m.Mul(m, fp434BigP).Add(m, big.NewInt(1))
+	if m.Uint64() != 0 {
+		t.Error("p*mprime != -1 mod 2^64")
+	}
+	r2 := new(big.Int).Mul(fp434BigR, fp434BigR)
+	fp434CheckEq(t, "R2", p434R2[:NumWords], r2.Mod(r2, fp434BigP))
+	fp434CheckEq(t, "One", P434OneFp2.A[:NumWords], new(big.Int).Mod(fp434BigR, fp434BigP))
+	fp434CheckEq(t, "2p", p434x2[:NumWords], new(big.Int).Lsh(fp434BigP, 1))
+	half := new(big.Int).ModInverse(big.NewInt(2), fp434BigP)
+	half.Mul(half, fp434BigR).Mod(half, fp434BigP)
+	fp434CheckEq(t, "Half", P434HalfFp2.A[:NumWords], half)
+}
+
+func TestP434AddReduced(t *testing.T) {
+	var x, y, z FpElement
+	fp434ForPairs(fp434BigBound, func(a, b *big.Int) {
+		fp434FromBig(x[:NumWords], a)
+		fp434FromBig(y[:NumWords], b)
+		fp434AddReduced(&z, &x, &y)
+		fp434CheckMod(t, "AddReduced", z[:NumWords], new(big.Int).Add(a, b), a, b)
+	})
+}
+
+func TestP434SubReduced(t *testing.T) {
+	var x, y, z FpElement
+	fp434ForPairs(fp434BigBound, func(a, b *big.Int) {
+		fp434FromBig(x[:NumWords], a)
+		fp434FromBig(y[:NumWords], b)
+		fp434SubReduced(&z, &x, &y)
+		fp434CheckMod(t, "SubReduced", z[:NumWords], new(big.Int).Sub(a, b), a, b)
+	})
+}
+
+func TestP434AddLazy(t *testing.T) {
+	var x, y, z FpElement
+	fp434ForPairs(fp434BigBound, func(a, b *big.Int) {
+		fp434FromBig(x[:NumWords], a)
+		fp434FromBig(y[:NumWords], b)
+		fp434AddLazy(&z, &x, &y)
+		fp434CheckEq(t, "AddLazy", z[:NumWords], new(big.Int).Add(a, b), a, b)
+	})
+}
+
+func TestP434X2AddLazy(t *testing.T) {
+	var x, y, z FpElementX2
+	fp434ForPairs(fp434BigPR, func(a, b *big.Int) {
+		fp434FromBig(x[:2*NumWords], a)
+		fp434FromBig(y[:2*NumWords], b)
+		fp434X2AddLazy(&z, &x, &y)
+		fp434CheckEq(t, "X2AddLazy", z[:2*NumWords], new(big.Int).Add(a, b), a, b)
+	})
+}
+
+func TestP434X2SubLazy(t *testing.T) {
+	var x, y, z FpElementX2
+	fp434ForPairs(fp434BigPR, func(a, b *big.Int) {
+		fp434FromBig(x[:2*NumWords], a)
+		fp434FromBig(y[:2*NumWords], b)
+		fp434X2SubLazy(&z, &x, &y)
+		// Negative result is shifted by p*R
+		exp := new(big.Int).Sub(a, b)
+		if exp.Sign() < 0 {
+			exp.Add(exp, fp434BigPR)
+		}
+		fp434CheckEq(t, "X2SubLazy", z[:2*NumWords], exp, a, b)
+	})
+}
+
+func TestP434StrongReduce(t *testing.T) {
+	var x FpElement
+	max := new(big.Int).Lsh(fp434BigP, 1)
+	for _, a := range fp434Values(rand.New(rand.NewSource(1)), max) {
+		fp434FromBig(x[:NumWords], a)
+		fp434StrongReduce(&x)
+		fp434CheckEq(t, "StrongReduce", x[:NumWords], new(big.Int).Mod(a, fp434BigP), a)
+	}
+}
+
+func TestP434ConditionalSwap(t *testing.T) {
+	var x, y FpElement
+	fp434ForPairs(fp434BigBound, func(a, b *big.Int) {
+		fp434FromBig(x[:NumWords], a)
+		fp434FromBig(y[:NumWords], b)
+		fp434ConditionalSwap(&x, &y, 0)
+		fp434CheckEq(t, "ConditionalSwap(0)", x[:NumWords], a, a, b)
+		fp434CheckEq(t, "ConditionalSwap(0)", y[:NumWords], b, a, b)
+		fp434ConditionalSwap(&x, &y, 1)
+		fp434CheckEq(t, "ConditionalSwap(1)", x[:NumWords], b, a, b)
+		fp434CheckEq(t, "ConditionalSwap(1)", y[:NumWords], a, a, b)
+	})
+}
+
+func TestP434Mul(t *testing.T) {
+	var x, y FpElement
+	var z FpElementX2
+	fp434ForPairs(fp434BigBound, func(a, b *big.Int) {
+		fp434FromBig(x[:NumWords], a)
+		fp434FromBig(y[:NumWords], b)
+		fp434Mul(&z, &x, &y)
+		fp434CheckEq(t, "Mul", z[:2*NumWords], new(big.Int).Mul(a, b), a, b)
+	})
+}
+
+func TestP434MontgomeryReduce(t *testing.T) {
+	var x FpElementX2
+	var z FpElement
+	for _, a := range fp434Values(rand.New(rand.NewSource(1)), fp434BigPR) {
+		fp434FromBig(x[:2*NumWords], a)
+		fp434MontgomeryReduce(&z, &x)
+		exp := new(big.Int).Mul(a, fp434BigRInv)
+		fp434CheckMod(t, "MontgomeryReduce", z[:NumWords], exp, a)
+	}
+}
+
+func TestP434MulRdc(t *testing.T) {
+	var x, y, z FpElement
+	fp434ForPairs(fp434BigBound, func(a, b *big.Int) {
+		fp434FromBig(x[:NumWords], a)
+		fp434FromBig(y[:NumWords], b)
+		fp434MulRdc(&z, &x, &y)
+		exp := new(big.Int).Mul(a, b)
+		exp.Mul(exp, fp434BigRInv)
+		fp434CheckMod(t, "MulRdc", z[:NumWords], exp, a, b)
+	})
+}
+
+func TestP434Inv(t *testing.T) {
+	var x, z FpElement
+	for _, a := range fp434Values(rand.New(rand.NewSource(1)), fp434BigBound) {
+		fp434FromBig(x[:NumWords], a)
+		fp434Inv(&z, &x)
+
+		// x = a*R, so expected result is a^-1*R = R^2/x
+		exp := new(big.Int).Mul(fp434BigR, fp434BigR)
+		if inv := new(big.Int).ModInverse(a, fp434BigP); inv != nil {
+			exp.Mul(exp, inv)
+		} else {
+			exp.SetInt64(0)
+		}
+		fp434CheckMod(t, "Inv", z[:NumWords], exp, a)
+	}
+}
+
+func TestP434MontgomeryRoundTrip(t *testing.T) {
+	var x, z FpElement
+	for _, a := range fp434Values(rand.New(rand.NewSource(1)), fp434BigP) {
+		fp434FromBig(x[:NumWords], a)
+		fp434ToMontgomery(&z, &x)
+		exp := new(big.Int).Mul(a, fp434BigR)
+		fp434CheckMod(t, "ToMontgomery", z[:NumWords], exp, a)
+		fp434FromMontgomery(&z, &z)
+		fp434CheckEq(t, "FromMontgomery", z[:NumWords], a, a)
+	}
+}
+
+// Sets x = a + bi, converted to Montgomery domain
+func fp434Fp2FromBig(x *Fp2Element, a, b *big.Int) {
+	*x = Fp2Element{}
+	fp434FromBig(x.A[:NumWords], a)
+	fp434FromBig(x.B[:NumWords], b)
+	FieldOperations().ToMontgomery(x)
+}
+
+// Checks if x = a + bi
+func fp434CheckFp2(t *testing.T, op string, x *Fp2Element, a, b *big.Int, args ...*big.Int) {
+	var out Fp2Element
+	FieldOperations().FromMontgomery(x, &out)
+	fp434CheckEq(t, op+".A", out.A[:NumWords], a.Mod(a, fp434BigP), args...)
+	fp434CheckEq(t, op+".B", out.B[:NumWords], b.Mod(b, fp434BigP), args...)
+}
+
+func TestP434FieldOps(t *testing.T) {
+	var x, y, z Fp2Element
+	op := FieldOperations()
+	rng := rand.New(rand.NewSource(1))
+	vals := fp434Values(rng, fp434BigP)
+
+	for i := range vals {
+		a, b := vals[i], vals[(i+1)%len(vals)]
+		c, d := vals[(i+7)%len(vals)], vals[(i+13)%len(vals)]
+		fp434Fp2FromBig(&x, a, b)
+		fp434Fp2FromBig(&y, c, d)
+
+		op.Add(&z, &x, &y)
+		fp434CheckFp2(t, "Add", &z, new(big.Int).Add(a, c), new(big.Int).Add(b, d), a, b, c, d)
+
+		op.Sub(&z, &x, &y)
+		fp434CheckFp2(t, "Sub", &z, new(big.Int).Sub(a, c), new(big.Int).Sub(b, d), a, b, c, d)
+
+		// (a + bi)*(c + di) = (a*c - b*d) + (a*d + b*c)i
+		ac, bd := new(big.Int).Mul(a, c), new(big.Int).Mul(b, d)
+		ad, bc := new(big.Int).Mul(a, d), new(big.Int).Mul(b, c)
+		op.Mul(&z, &x, &y)
+		fp434CheckFp2(t, "Mul", &z, ac.Sub(ac, bd), ad.Add(ad, bc), a, b, c, d)
+
+		// (a + bi)^2 = (a^2 - b^2) + 2abi
+		aa, bb := new(big.Int).Mul(a, a), new(big.Int).Mul(b, b)
+		ab2 := new(big.Int).Mul(a, b)
+		op.Square(&z, &x)
+		fp434CheckFp2(t, "Square", &z, aa.Sub(aa, bb), ab2.Lsh(ab2, 1), a, b)
+
+		// 1/(a + bi) = (a - bi)/(a^2 + b^2)
+		n := new(big.Int).Mul(a, a)
+		n.Add(n, new(big.Int).Mul(b, b))
+		if n.ModInverse(n, fp434BigP) == nil {
+			n.SetInt64(0)
+		}
+		op.Inv(&z, &x)
+		fp434CheckFp2(t, "Inv", &z, new(big.Int).Mul(a, n), new(big.Int).Neg(new(big.Int).Mul(b, n)), a, b)
+
+		// Overlapping arguments
+		z = x
+		op.Mul(&z, &z, &z)
+		aa, bb = new(big.Int).Mul(a, a), new(big.Int).Mul(b, b)
+		ab2 = new(big.Int).Mul(a, b)
+		fp434CheckFp2(t, "Mul(x,x)", &z, aa.Sub(aa, bb), ab2.Lsh(ab2, 1), a, b)
+
+		xPx, xPz, xQx, xQz := x, y, y, x
+		op.CondSwap(&xPx, &xPz, &xQx, &xQz, 0)
+		fp434CheckFp2(t, "CondSwap(0)", &xPx, new(big.Int).Set(a), new(big.Int).Set(b), a, b, c, d)
+		op.CondSwap(&xPx, &xPz, &xQx, &xQz, 1)
+		fp434CheckFp2(t, "CondSwap(1)", &xPx, new(big.Int).Set(c), new(big.Int).Set(d), a, b, c, d)
+		fp434CheckFp2(t, "CondSwap(1)", &xPz, new(big.Int).Set(a), new(big.Int).Set(b), a, b, c, d)
+	}
+}
+
+func BenchmarkP434Mul(b *testing.B) {
+	var x, y FpElement
+	var z FpElementX2
+	fp434FromBig(x[:NumWords], new(big.Int).Sub(fp434BigP, big.NewInt(1)))
+	y = x
+	for n := 0; n < b.N; n++ {
+		fp434Mul(&z, &x, &y)
+	}
+}
+
+func BenchmarkP434MontgomeryReduce(b *testing.B) {
+	var x FpElementX2
+	var z FpElement
+	fp434FromBig(x[:2*NumWords], new(big.Int).Sub(fp434BigPR, big.NewInt(1)))
+	for n := 0; n < b.N; n++ {
+		fp434MontgomeryReduce(&z, &x)
+	}
+}
+
+func BenchmarkP434AddReduced(b *testing.B) {
+	var x, y, z FpElement
+	fp434FromBig(x[:NumWords], new(big.Int).Sub(fp434BigP, big.NewInt(1)))
+	y = x
+	for n := 0; n < b.N; n++ {
+		fp434AddReduced(&z, &x, &y)
+	}
+}
+
+func BenchmarkP434Inv(b *testing.B) {
+	var x FpElement
+	fp434FromBig(x[:NumWords], new(big.Int).Sub(fp434BigP, big.NewInt(1)))
+	for n := 0; n < b.N; n++ {
+		fp434Inv(&x, &x)
+	}
+}
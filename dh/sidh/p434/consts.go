@@ -0,0 +1,56 @@
+// Code generated by fpgen. DO NOT EDIT.
+
+package p434
+
+import (
+	. "github.com/henrydcase/nobs/dh/sidh/internal/isogeny"
+)
+
+const (
+	// Number of limbs for a field element
+	NumWords = 7
+	// ceil(434/8)
+	P434_Bytelen = 55
+	// -p^(-1) mod 2^64, used by Montgomery reduction
+	p434MPrime = 0x0000000000000001
+)
+
+// p434 = 2^216*3^137-1
+var p434 = FpElement{
+	0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF, 0xFDC1767AE2FFFFFF,
+	0x7BC65C783158AEA3, 0x6CFC5FD681C52056, 0x0002341F27177344,
+}
+
+// 2*p434
+var p434x2 = FpElement{
+	0xFFFFFFFFFFFFFFFE, 0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF, 0xFB82ECF5C5FFFFFF,
+	0xF78CB8F062B15D47, 0xD9F8BFAD038A40AC, 0x0004683E4E2EE688,
+}
+
+// R^2=(2^448)^2 mod p
+var p434R2 = FpElement{
+	0x28E55B65DCD69B30, 0xACEC7367768798C2, 0xAB27973F8311688D, 0x175CC6AF8D6C7C0B,
+	0xABCD92BF2DDE347E, 0x69E16A61C7686D9A, 0x000025A89BCDD12A,
+}
+
+// p-2, exponent used for inversion
+var p434Minus2 = [NumWords]uint64{
+	0xFFFFFFFFFFFFFFFD, 0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF, 0xFDC1767AE2FFFFFF,
+	0x7BC65C783158AEA3, 0x6CFC5FD681C52056, 0x0002341F27177344,
+}
+
+// 1*R mod p
+var P434OneFp2 = Fp2Element{
+	A: FpElement{
+		0x000000000000742C, 0x0000000000000000, 0x0000000000000000, 0xB90FF404FC000000,
+		0xD801A4FB559FACD4, 0xE93254545F77410C, 0x0000ECEEA7BD2EDA,
+	},
+}
+
+// 1/2 * R mod p
+var P434HalfFp2 = Fp2Element{
+	A: FpElement{
+		0x0000000000003A16, 0x0000000000000000, 0x0000000000000000, 0x5C87FA027E000000,
+		0x6C00D27DAACFD66A, 0x74992A2A2FBBA086, 0x0000767753DE976D,
+	},
+}
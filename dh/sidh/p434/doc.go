@@ -0,0 +1,8 @@
+// Package p434 implements arithmetic in the field F_p^2 = F_p(i) for the
+// SIKEp434 prime p434 = 2^216*3^137 - 1.
+//
+// All the other files in this package are generated by
+// dh/sidh/internal/fpgen. Don't edit them, rerun "go generate" instead.
+package p434
+
+//go:generate go run ../internal/fpgen -name p434 -prime 2^216*3^137-1
@@ -0,0 +1,148 @@
+// Code generated by fpgen. DO NOT EDIT.
+
+package p434
+
+import (
+	. "github.com/henrydcase/nobs/dh/sidh/internal/isogeny"
+)
+
+type fp434Ops struct{}
+
+func FieldOperations() FieldOps {
+	return &fp434Ops{}
+}
+
+func (fp434Ops) Add(dest, lhs, rhs *Fp2Element) {
+	fp434AddReduced(&dest.A, &lhs.A, &rhs.A)
+	fp434AddReduced(&dest.B, &lhs.B, &rhs.B)
+}
+
+func (fp434Ops) Sub(dest, lhs, rhs *Fp2Element) {
+	fp434SubReduced(&dest.A, &lhs.A, &rhs.A)
+	fp434SubReduced(&dest.B, &lhs.B, &rhs.B)
+}
+
+func (fp434Ops) Mul(dest, lhs, rhs *Fp2Element) {
+	// Let (a,b,c,d) = (lhs.a,lhs.b,rhs.a,rhs.b).
+	a := &lhs.A
+	b := &lhs.B
+	c := &rhs.A
+	d := &rhs.B
+
+	// We want to compute
+	//
+	// (a + bi)*(c + di) = (a*c - b*d) + (a*d + b*c)i
+	//
+	// Use Karatsuba's trick: note that
+	//
+	// (b - a)*(c - d) = (b*c + a*d) - a*c - b*d
+	//
+	// so (a*d + b*c) = (b-a)*(c-d) + a*c + b*d.
+
+	var ac, bd FpElementX2
+	fp434Mul(&ac, a, c) // = a*c*R*R
+	fp434Mul(&bd, b, d) // = b*d*R*R
+
+	var b_minus_a, c_minus_d FpElement
+	fp434SubReduced(&b_minus_a, b, a) // = (b-a)*R
+	fp434SubReduced(&c_minus_d, c, d) // = (c-d)*R
+
+	var ad_plus_bc FpElementX2
+	fp434Mul(&ad_plus_bc, &b_minus_a, &c_minus_d) // = (b-a)*(c-d)*R*R
+	fp434X2AddLazy(&ad_plus_bc, &ad_plus_bc, &ac) // = ((b-a)*(c-d) + a*c)*R*R
+	fp434X2AddLazy(&ad_plus_bc, &ad_plus_bc, &bd) // = ((b-a)*(c-d) + a*c + b*d)*R*R
+
+	fp434MontgomeryReduce(&dest.B, &ad_plus_bc) // = (a*d + b*c)*R mod p
+
+	var ac_minus_bd FpElementX2
+	fp434X2SubLazy(&ac_minus_bd, &ac, &bd)       // = (a*c - b*d)*R*R
+	fp434MontgomeryReduce(&dest.A, &ac_minus_bd) // = (a*c - b*d)*R mod p
+}
+
+// Set dest = 1/x
+//
+// Allowed to overlap dest with x.
+func (fp434Ops) Inv(dest, x *Fp2Element) {
+	a := &x.A
+	b := &x.B
+
+	// We want to compute
+	//
+	//    1          1     (a - bi)	    (a - bi)
+	// -------- = -------- -------- = -----------
+	// (a + bi)   (a + bi) (a - bi)   (a^2 + b^2)
+	//
+	// Letting c = 1/(a^2 + b^2), this is
+	//
+	// 1/(a+bi) = a*c - b*ci.
+
+	var inv FpElement
+	var asq, bsq FpElementX2
+	fp434Mul(&asq, a, a)              // = a*a*R*R
+	fp434Mul(&bsq, b, b)              // = b*b*R*R
+	fp434X2AddLazy(&asq, &asq, &bsq)  // = (a^2 + b^2)*R*R
+	fp434MontgomeryReduce(&inv, &asq) // = (a^2 + b^2)*R mod p
+	fp434Inv(&inv, &inv)              // = (a^2 + b^2)^-1*R mod p
+
+	var ac FpElementX2
+	fp434Mul(&ac, a, &inv)
+	fp434MontgomeryReduce(&dest.A, &ac)
+
+	var minus_b FpElement
+	fp434SubReduced(&minus_b, &minus_b, b)
+	var minus_bc FpElementX2
+	fp434Mul(&minus_bc, &minus_b, &inv)
+	fp434MontgomeryReduce(&dest.B, &minus_bc)
+}
+
+func (fp434Ops) Square(dest, x *Fp2Element) {
+	a := &x.A
+	b := &x.B
+
+	// We want to compute
+	//
+	// (a + bi)*(a + bi) = (a^2 - b^2) + 2abi.
+
+	var a2, a_plus_b, a_minus_b FpElement
+	fp434AddReduced(&a2, a, a)        // = a*R + a*R = 2*a*R
+	fp434AddReduced(&a_plus_b, a, b)  // = a*R + b*R = (a+b)*R
+	fp434SubReduced(&a_minus_b, a, b) // = a*R - b*R = (a-b)*R
+
+	var asq_minus_bsq, ab2 FpElementX2
+	fp434Mul(&asq_minus_bsq, &a_plus_b, &a_minus_b) // = (a+b)*(a-b)*R*R = (a^2 - b^2)*R*R
+	fp434Mul(&ab2, &a2, b)                          // = 2*a*b*R*R
+
+	fp434MontgomeryReduce(&dest.A, &asq_minus_bsq) // = (a^2 - b^2)*R mod p
+	fp434MontgomeryReduce(&dest.B, &ab2)           // = 2*a*b*R mod p
+}
+
+// In case choice == 1, performs following swap in constant time:
+//
+//	xPx <-> xQx
+//	xPz <-> xQz
+//
+// Otherwise returns xPx, xPz, xQx, xQz unchanged
+func (fp434Ops) CondSwap(xPx, xPz, xQx, xQz *Fp2Element, choice uint8) {
+	fp434ConditionalSwap(&xPx.A, &xQx.A, choice)
+	fp434ConditionalSwap(&xPx.B, &xQx.B, choice)
+	fp434ConditionalSwap(&xPz.A, &xQz.A, choice)
+	fp434ConditionalSwap(&xPz.B, &xQz.B, choice)
+}
+
+// Converts values in x.A and x.B to Montgomery domain
+// x.A = x.A * R mod p
+// x.B = x.B * R mod p
+func (fp434Ops) ToMontgomery(x *Fp2Element) {
+	fp434ToMontgomery(&x.A, &x.A)
+	fp434ToMontgomery(&x.B, &x.B)
+}
+
+// Converts values in x.A and x.B from Montgomery domain
+// a = x.A mod p
+// b = x.B mod p
+//
+// After returning from the call x is not modified.
+func (fp434Ops) FromMontgomery(x *Fp2Element, out *Fp2Element) {
+	fp434FromMontgomery(&out.A, &x.A)
+	fp434FromMontgomery(&out.B, &x.B)
+}
@@ -0,0 +1,69 @@
+// Code generated by fpgen. DO NOT EDIT.
+
+package p434
+
+import (
+	. "github.com/henrydcase/nobs/dh/sidh/internal/isogeny"
+)
+
+// Set z = x * y * R^{-1} (mod 2*p).
+//
+// Allowed to overlap x or y with z.
+func fp434MulRdc(z, x, y *FpElement) {
+	var t FpElementX2
+	fp434Mul(&t, x, y)
+	fp434MontgomeryReduce(z, &t)
+}
+
+// Set z = x^e, where x is in Montgomery domain and e is little-endian
+// exponent. Uses fixed window of 4 bits. Exponent is assumed to be public,
+// execution time doesn't depend on x.
+//
+// Allowed to overlap x with z.
+func fp434Exp(z, x *FpElement, e []uint64) {
+	var lookup [16]FpElement
+	var started bool
+
+	// lookup[i] = x^i
+	lookup[0] = P434OneFp2.A
+	lookup[1] = *x
+	for i := 2; i < 16; i++ {
+		fp434MulRdc(&lookup[i], &lookup[i-1], x)
+	}
+
+	res := lookup[0]
+	for i := 16*len(e) - 1; i >= 0; i-- {
+		if started {
+			for j := 0; j < 4; j++ {
+				fp434MulRdc(&res, &res, &res)
+			}
+		}
+		w := (e[i/16] >> (4 * uint(i%16))) & 0xF
+		if w != 0 {
+			fp434MulRdc(&res, &res, &lookup[w])
+			started = true
+		}
+	}
+	*z = res
+}
+
+// Set z = 1/x (mod p), computed as x^(p-2). Inverse of 0 is 0.
+//
+// Allowed to overlap x with z.
+func fp434Inv(z, x *FpElement) {
+	fp434Exp(z, x, p434Minus2[:])
+}
+
+// Converts x to Montgomery domain, z = x*R mod p.
+func fp434ToMontgomery(z, x *FpElement) {
+	fp434MulRdc(z, x, &p434R2)
+}
+
+// Converts x from Montgomery domain, z = x*R^{-1} mod p. Result is
+// fully reduced to [0, p).
+func fp434FromMontgomery(z, x *FpElement) {
+	var t FpElementX2
+	copy(t[:], x[:NumWords])
+	fp434MontgomeryReduce(z, &t)
+	fp434StrongReduce(z)
+}
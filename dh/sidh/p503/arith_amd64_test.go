@@ -4,7 +4,7 @@ package p503
 
 import (
 	. "github.com/henrydcase/nobs/dh/sidh/internal/isogeny"
-	cpu "github.com/henrydcase/nobs/utils"
+	cpu "github.com/henrydcase/nobs/utils/cpu"
 	"reflect"
 	"testing"
 	"testing/quick"
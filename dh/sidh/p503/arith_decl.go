@@ -3,9 +3,14 @@
 package p503
 
 import (
+	"runtime"
+
 	. "github.com/henrydcase/nobs/dh/sidh/internal/isogeny"
 )
 
+// Implementation of field arithmetic, assembly for GOARCH
+const arithImpl = runtime.GOARCH
+
 // If choice = 0, leave x,y unchanged. If choice = 1, set x,y = y,x.
 // If choice is neither 0 nor 1 then behaviour is undefined.
 // This function executes in constant time.
@@ -0,0 +1,469 @@
+package p503
+
+// Differential fuzzing of field arithmetic against math/big. Each target
+// runs with all backends supported by the CPU. Seed corpus contains edge
+// cases, run it with "go test", fuzz with "go test -fuzz=FuzzFp503Mul".
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	. "github.com/henrydcase/nobs/dh/sidh/internal/isogeny"
+	cpu "github.com/henrydcase/nobs/utils/cpu"
+)
+
+var (
+	fuzzP   = radix64ToBigInt(p503[:NumWords])
+	fuzzP2  = new(big.Int).Lsh(fuzzP, 1)
+	fuzzR   = new(big.Int).Lsh(big.NewInt(1), 64*NumWords)
+	fuzzRR  = new(big.Int).Lsh(big.NewInt(1), 2*64*NumWords)
+	fuzzPR  = new(big.Int).Mul(fuzzP, fuzzR)
+	fuzzRi  = new(big.Int).ModInverse(fuzzR, fuzzP)
+	fuzzNeg = new(big.Int).Sub(fuzzR, new(big.Int).ModInverse(fuzzP, fuzzR)) // -1/p mod R
+)
+
+// Implementation of multiplication and Montgomery reduction selected
+// by HasBMI2 and HasADXandBMI2.
+type fuzzBackend struct {
+	name      string
+	bmi2, adx bool
+}
+
+// Returns backends supported by the CPU. Only amd64 assembly has MULX
+// and ADX variants, otherwise the single implementation is returned.
+func fuzzBackends() []fuzzBackend {
+	if arithImpl != "amd64" {
+		return []fuzzBackend{{name: arithImpl}}
+	}
+	b := []fuzzBackend{{name: "amd64-noMULX"}}
+	if cpu.X86.HasBMI2 {
+		b = append(b, fuzzBackend{name: "MULX", bmi2: true})
+		if cpu.X86.HasADX {
+			b = append(b, fuzzBackend{name: "MULX+ADX", bmi2: true, adx: true})
+		}
+	}
+	return b
+}
+
+// Calls f for each backend
+func forEachBackend(f func(name string)) {
+	bmi2, adx := HasBMI2, HasADXandBMI2
+	defer func() { HasBMI2, HasADXandBMI2 = bmi2, adx }()
+	for _, b := range fuzzBackends() {
+		HasBMI2, HasADXandBMI2 = b.bmi2, b.adx
+		f(b.name)
+	}
+}
+
+// Returns value from [0, bound) decoded from fuzzer input. First byte
+// selects a base, remaining bytes an offset from it, so that values near
+// 0, p, bound (and p-1) are easily reachable.
+func fuzzValue(data []byte, bound *big.Int) *big.Int {
+	if len(data) == 0 {
+		return new(big.Int)
+	}
+	v := new(big.Int).SetBytes(data[1:])
+	switch data[0] & 3 {
+	case 1:
+		v.Sub(fuzzP, v).Sub(v, big.NewInt(1))
+	case 2:
+		v.Add(fuzzP, v)
+	case 3:
+		v.Sub(bound, v).Sub(v, big.NewInt(1))
+	}
+	return v.Mod(v, bound)
+}
+
+// Seeds with edge cases for targets with n []byte arguments
+func fuzzSeeds(n int) [][]interface{} {
+	edges := [][]byte{
+		{0}, {0, 1}, {1}, {1, 1}, {2}, {2, 1}, {3}, {3, 1},
+		{0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		{3, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+	}
+	var seeds [][]interface{}
+	for _, e1 := range edges {
+		for _, e2 := range edges {
+			s := []interface{}{e1}
+			for i := 1; i < n; i++ {
+				s = append(s, e2)
+			}
+			seeds = append(seeds, s)
+		}
+	}
+	return seeds
+}
+
+func addSeeds(f *testing.F, n int, extra ...interface{}) {
+	for _, s := range fuzzSeeds(n) {
+		f.Add(append(s, extra...)...)
+	}
+}
+
+func toFp(x *big.Int) (z FpElement) {
+	for i, w := range new(big.Int).Set(x).Bits() {
+		z[i] = uint64(w)
+	}
+	return
+}
+
+func toFpX2(x *big.Int) (z FpElementX2) {
+	for i, w := range new(big.Int).Set(x).Bits() {
+		z[i] = uint64(w)
+	}
+	return
+}
+
+// Fails if got != want. Values beyond NumWords are included in got, so
+// writes out of bounds are detected too.
+func fuzzCheck(t *testing.T, op, backend string, got []uint64, want *big.Int, in ...*big.Int) {
+	if g := radix64ToBigInt(got); g.Cmp(want) != 0 {
+		t.Fatalf("%s (%s)\ninput: %#x\ngot:   %#x\nwant:  %#x", op, backend, in, g, want)
+	}
+}
+
+// Reference Montgomery reduction, returns (x + m*p)/R for m = -x/p mod R
+func refRedc(x *big.Int) *big.Int {
+	m := new(big.Int).Mul(x, fuzzNeg)
+	m.Mod(m, fuzzR).Mul(m, fuzzP).Add(m, x)
+	return m.Rsh(m, 64*NumWords)
+}
+
+/* -------------------------------------------------------------------------
+   Assembly (or generic) primitives
+   -------------------------------------------------------------------------*/
+
+func FuzzFp503AddReduced(f *testing.F) {
+	addSeeds(f, 2)
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		x, y := fuzzValue(a, fuzzP2), fuzzValue(b, fuzzP2)
+		want := new(big.Int).Add(x, y)
+		if want.Cmp(fuzzP2) >= 0 {
+			want.Sub(want, fuzzP2)
+		}
+		forEachBackend(func(name string) {
+			var z FpElement
+			fx, fy := toFp(x), toFp(y)
+			fp503AddReduced(&z, &fx, &fy)
+			fuzzCheck(t, "fp503AddReduced", name, z[:], want, x, y)
+		})
+	})
+}
+
+func FuzzFp503SubReduced(f *testing.F) {
+	addSeeds(f, 2)
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		x, y := fuzzValue(a, fuzzP2), fuzzValue(b, fuzzP2)
+		want := new(big.Int).Sub(x, y)
+		if want.Sign() < 0 {
+			want.Add(want, fuzzP2)
+		}
+		forEachBackend(func(name string) {
+			var z FpElement
+			fx, fy := toFp(x), toFp(y)
+			fp503SubReduced(&z, &fx, &fy)
+			fuzzCheck(t, "fp503SubReduced", name, z[:], want, x, y)
+		})
+	})
+}
+
+func FuzzFp503AddLazy(f *testing.F) {
+	addSeeds(f, 2)
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		x, y := fuzzValue(a, fuzzR), fuzzValue(b, fuzzR)
+		want := new(big.Int).Add(x, y)
+		want.Mod(want, fuzzR)
+		forEachBackend(func(name string) {
+			var z FpElement
+			fx, fy := toFp(x), toFp(y)
+			fp503AddLazy(&z, &fx, &fy)
+			fuzzCheck(t, "fp503AddLazy", name, z[:], want, x, y)
+		})
+	})
+}
+
+func FuzzFp503X2AddLazy(f *testing.F) {
+	addSeeds(f, 2)
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		x, y := fuzzValue(a, fuzzRR), fuzzValue(b, fuzzRR)
+		want := new(big.Int).Add(x, y)
+		want.Mod(want, fuzzRR)
+		forEachBackend(func(name string) {
+			var z FpElementX2
+			fx, fy := toFpX2(x), toFpX2(y)
+			fp503X2AddLazy(&z, &fx, &fy)
+			fuzzCheck(t, "fp503X2AddLazy", name, z[:], want, x, y)
+		})
+	})
+}
+
+func FuzzFp503X2SubLazy(f *testing.F) {
+	addSeeds(f, 2)
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		x, y := fuzzValue(a, fuzzRR), fuzzValue(b, fuzzRR)
+		// p*R is added in case of borrow
+		want := new(big.Int).Sub(x, y)
+		if want.Sign() < 0 {
+			want.Add(want, fuzzPR)
+		}
+		want.Mod(want, fuzzRR)
+		forEachBackend(func(name string) {
+			var z FpElementX2
+			fx, fy := toFpX2(x), toFpX2(y)
+			fp503X2SubLazy(&z, &fx, &fy)
+			fuzzCheck(t, "fp503X2SubLazy", name, z[:], want, x, y)
+		})
+	})
+}
+
+func FuzzFp503StrongReduce(f *testing.F) {
+	addSeeds(f, 1)
+	f.Fuzz(func(t *testing.T, a []byte) {
+		x := fuzzValue(a, fuzzP2)
+		want := new(big.Int).Mod(x, fuzzP)
+		forEachBackend(func(name string) {
+			z := toFp(x)
+			fp503StrongReduce(&z)
+			fuzzCheck(t, "fp503StrongReduce", name, z[:], want, x)
+		})
+	})
+}
+
+func FuzzFp503Mul(f *testing.F) {
+	addSeeds(f, 2)
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		x, y := fuzzValue(a, fuzzP2), fuzzValue(b, fuzzP2)
+		want := new(big.Int).Mul(x, y)
+		forEachBackend(func(name string) {
+			var z FpElementX2
+			fx, fy := toFp(x), toFp(y)
+			fp503Mul(&z, &fx, &fy)
+			fuzzCheck(t, "fp503Mul", name, z[:], want, x, y)
+		})
+	})
+}
+
+func FuzzFp503MontgomeryReduce(f *testing.F) {
+	addSeeds(f, 1)
+	f.Fuzz(func(t *testing.T, a []byte) {
+		// Input is a product of elements from [0, 2p) or difference
+		// computed by fp503X2SubLazy, so it is in [0, p*R)
+		x := fuzzValue(a, fuzzPR)
+		want := refRedc(x)
+		if want.Cmp(fuzzP2) >= 0 {
+			t.Fatalf("reference: result %#x not in [0, 2p)", want)
+		}
+		forEachBackend(func(name string) {
+			var z FpElement
+			fx := toFpX2(x)
+			fp503MontgomeryReduce(&z, &fx)
+			fuzzCheck(t, "fp503MontgomeryReduce", name, z[:], want, x)
+		})
+	})
+}
+
+func FuzzFp503ConditionalSwap(f *testing.F) {
+	addSeeds(f, 2, uint8(0))
+	addSeeds(f, 2, uint8(1))
+	f.Fuzz(func(t *testing.T, a, b []byte, choice uint8) {
+		choice &= 1
+		x, y := fuzzValue(a, fuzzR), fuzzValue(b, fuzzR)
+		wantX, wantY := x, y
+		if choice == 1 {
+			wantX, wantY = y, x
+		}
+		forEachBackend(func(name string) {
+			fx, fy := toFp(x), toFp(y)
+			fp503ConditionalSwap(&fx, &fy, choice)
+			fuzzCheck(t, fmt.Sprintf("fp503ConditionalSwap(%d)", choice), name, fx[:], wantX, x, y)
+			fuzzCheck(t, fmt.Sprintf("fp503ConditionalSwap(%d)", choice), name, fy[:], wantY, x, y)
+		})
+	})
+}
+
+/* -------------------------------------------------------------------------
+   FieldOps. Elements of Fp2 are in Montgomery domain, results are
+   compared modulo p and must be in [0, 2p).
+   -------------------------------------------------------------------------*/
+
+// Element of Fp2 decoded from fuzzer input, coordinates in [0, 2p)
+func fuzzFp2(a, b []byte) (x Fp2Element, ref [2]*big.Int) {
+	ref[0], ref[1] = fuzzValue(a, fuzzP2), fuzzValue(b, fuzzP2)
+	x.A, x.B = toFp(ref[0]), toFp(ref[1])
+	return
+}
+
+// Returns x/R mod p, value represented by x in Montgomery domain
+func fromMont(x *big.Int) *big.Int {
+	v := new(big.Int).Mul(x, fuzzRi)
+	return v.Mod(v, fuzzP)
+}
+
+// Fails if got doesn't represent want, values of want are modulo p
+func fuzzCheckFp2(t *testing.T, op, backend string, got *Fp2Element, want [2]*big.Int, in ...*big.Int) {
+	for i, c := range []*FpElement{&got.A, &got.B} {
+		g := radix64ToBigInt(c[:])
+		w := new(big.Int).Mod(want[i], fuzzP)
+		if g.Cmp(fuzzP2) >= 0 || fromMont(g).Cmp(w) != 0 {
+			t.Fatalf("%s (%s)\ninput: %#x\ngot:   %#x (%#x)\nwant:  %#x", op, backend, in, g, fromMont(g), w)
+		}
+	}
+}
+
+// Reference multiplication in Fp2, i^2 = -1
+func refMulFp2(x, y [2]*big.Int) (z [2]*big.Int) {
+	ac := new(big.Int).Mul(x[0], y[0])
+	bd := new(big.Int).Mul(x[1], y[1])
+	ad := new(big.Int).Mul(x[0], y[1])
+	bc := new(big.Int).Mul(x[1], y[0])
+	z[0] = ac.Sub(ac, bd).Mod(ac, fuzzP)
+	z[1] = ad.Add(ad, bc).Mod(ad, fuzzP)
+	return
+}
+
+func FuzzFieldOpsAdd(f *testing.F) {
+	addSeeds(f, 4)
+	f.Fuzz(func(t *testing.T, a0, a1, b0, b1 []byte) {
+		x, xr := fuzzFp2(a0, a1)
+		y, yr := fuzzFp2(b0, b1)
+		var want [2]*big.Int
+		for i := range want {
+			want[i] = fromMont(new(big.Int).Add(xr[i], yr[i]))
+		}
+		forEachBackend(func(name string) {
+			var z Fp2Element
+			kFieldOps.Add(&z, &x, &y)
+			fuzzCheckFp2(t, "Add", name, &z, want, xr[0], xr[1], yr[0], yr[1])
+		})
+	})
+}
+
+func FuzzFieldOpsSub(f *testing.F) {
+	addSeeds(f, 4)
+	f.Fuzz(func(t *testing.T, a0, a1, b0, b1 []byte) {
+		x, xr := fuzzFp2(a0, a1)
+		y, yr := fuzzFp2(b0, b1)
+		var want [2]*big.Int
+		for i := range want {
+			want[i] = fromMont(new(big.Int).Sub(xr[i], yr[i]))
+		}
+		forEachBackend(func(name string) {
+			var z Fp2Element
+			kFieldOps.Sub(&z, &x, &y)
+			fuzzCheckFp2(t, "Sub", name, &z, want, xr[0], xr[1], yr[0], yr[1])
+		})
+	})
+}
+
+func FuzzFieldOpsMul(f *testing.F) {
+	addSeeds(f, 4)
+	f.Fuzz(func(t *testing.T, a0, a1, b0, b1 []byte) {
+		x, xr := fuzzFp2(a0, a1)
+		y, yr := fuzzFp2(b0, b1)
+		want := refMulFp2(
+			[2]*big.Int{fromMont(xr[0]), fromMont(xr[1])},
+			[2]*big.Int{fromMont(yr[0]), fromMont(yr[1])})
+		forEachBackend(func(name string) {
+			var z Fp2Element
+			kFieldOps.Mul(&z, &x, &y)
+			fuzzCheckFp2(t, "Mul", name, &z, want, xr[0], xr[1], yr[0], yr[1])
+		})
+	})
+}
+
+func FuzzFieldOpsSquare(f *testing.F) {
+	addSeeds(f, 2)
+	f.Fuzz(func(t *testing.T, a0, a1 []byte) {
+		x, xr := fuzzFp2(a0, a1)
+		v := [2]*big.Int{fromMont(xr[0]), fromMont(xr[1])}
+		want := refMulFp2(v, v)
+		forEachBackend(func(name string) {
+			var z Fp2Element
+			kFieldOps.Square(&z, &x)
+			fuzzCheckFp2(t, "Square", name, &z, want, xr[0], xr[1])
+		})
+	})
+}
+
+func FuzzFieldOpsInv(f *testing.F) {
+	addSeeds(f, 2)
+	f.Fuzz(func(t *testing.T, a0, a1 []byte) {
+		x, xr := fuzzFp2(a0, a1)
+		v := [2]*big.Int{fromMont(xr[0]), fromMont(xr[1])}
+		// 1/(a+bi) = (a-bi)/(a^2+b^2), zero has no inverse
+		n := new(big.Int).Mul(v[0], v[0])
+		n.Add(n, new(big.Int).Mul(v[1], v[1])).Mod(n, fuzzP)
+		if n.Sign() == 0 {
+			return
+		}
+		n.ModInverse(n, fuzzP)
+		want := [2]*big.Int{
+			new(big.Int).Mul(v[0], n),
+			new(big.Int).Neg(new(big.Int).Mul(v[1], n)),
+		}
+		forEachBackend(func(name string) {
+			var z Fp2Element
+			kFieldOps.Inv(&z, &x)
+			fuzzCheckFp2(t, "Inv", name, &z, want, xr[0], xr[1])
+		})
+	})
+}
+
+func FuzzFieldOpsCondSwap(f *testing.F) {
+	addSeeds(f, 2, uint8(0))
+	addSeeds(f, 2, uint8(1))
+	f.Fuzz(func(t *testing.T, a, b []byte, choice uint8) {
+		choice &= 1
+		// Different elements in each of the 4 arguments
+		x, xr := fuzzFp2(a, b)
+		y, yr := fuzzFp2(b, a)
+		p, q := x, y
+		kFieldOps.Add(&p, &p, &x)
+		kFieldOps.Add(&q, &q, &y)
+		in := []Fp2Element{x, p, y, q}
+		want := in
+		if choice == 1 {
+			want = []Fp2Element{y, q, x, p}
+		}
+		forEachBackend(func(name string) {
+			got := append([]Fp2Element{}, in...)
+			kFieldOps.CondSwap(&got[0], &got[1], &got[2], &got[3], choice)
+			for i := range got {
+				if got[i] != want[i] {
+					t.Fatalf("CondSwap(%d) (%s): argument %d differs\ninput: %#x", choice, name, i, [][2]*big.Int{xr, yr})
+				}
+			}
+		})
+	})
+}
+
+func FuzzFieldOpsToMontgomery(f *testing.F) {
+	addSeeds(f, 2)
+	f.Fuzz(func(t *testing.T, a0, a1 []byte) {
+		x, xr := fuzzFp2(a0, a1)
+		forEachBackend(func(name string) {
+			z := x
+			kFieldOps.ToMontgomery(&z)
+			fuzzCheckFp2(t, "ToMontgomery", name, &z, xr, xr[0], xr[1])
+		})
+	})
+}
+
+func FuzzFieldOpsFromMontgomery(f *testing.F) {
+	addSeeds(f, 2)
+	f.Fuzz(func(t *testing.T, a0, a1 []byte) {
+		x, xr := fuzzFp2(a0, a1)
+		forEachBackend(func(name string) {
+			var z Fp2Element
+			in := x
+			kFieldOps.FromMontgomery(&in, &z)
+			// Result is fully reduced
+			fuzzCheck(t, "FromMontgomery", name, z.A[:], fromMont(xr[0]), xr[0], xr[1])
+			fuzzCheck(t, "FromMontgomery", name, z.B[:], fromMont(xr[1]), xr[0], xr[1])
+			if in != x {
+				t.Fatalf("FromMontgomery (%s): input modified", name)
+			}
+		})
+	})
+}
@@ -3,30 +3,34 @@
 package p751
 
 import (
-	. "github.com/henrydcase/nobs/dh/sidh/internal/arith"
+	"math/bits"
+
 	. "github.com/henrydcase/nobs/dh/sidh/internal/isogeny"
 )
 
+// Implementation of field arithmetic, portable Go code
+const arithImpl = "go"
+
 // Compute z = x + y (mod p).
 func fp751AddReduced(z, x, y *FpElement) {
 	var carry uint64
 
 	// z=x+y % p751
 	for i := 0; i < NumWords; i++ {
-		z[i], carry = Addc64(carry, x[i], y[i])
+		z[i], carry = bits.Add64(x[i], y[i], carry)
 	}
 
 	// z = z - p751x2
 	carry = 0
 	for i := 0; i < NumWords; i++ {
-		z[i], carry = Subc64(carry, z[i], p751x2[i])
+		z[i], carry = bits.Sub64(z[i], p751x2[i], carry)
 	}
 
 	// z = z + p751x2
 	mask := uint64(0 - carry)
 	carry = 0
 	for i := 0; i < NumWords; i++ {
-		z[i], carry = Addc64(carry, z[i], p751x2[i]&mask)
+		z[i], carry = bits.Add64(z[i], p751x2[i]&mask, carry)
 	}
 }
 
@@ -35,14 +39,14 @@ func fp751SubReduced(z, x, y *FpElement) {
 	var borrow uint64
 
 	for i := 0; i < NumWords; i++ {
-		z[i], borrow = Subc64(borrow, x[i], y[i])
+		z[i], borrow = bits.Sub64(x[i], y[i], borrow)
 	}
 
 	mask := uint64(0 - borrow)
 	borrow = 0
 
 	for i := 0; i < NumWords; i++ {
-		z[i], borrow = Addc64(borrow, z[i], p751x2[i]&mask)
+		z[i], borrow = bits.Add64(z[i], p751x2[i]&mask, borrow)
 	}
 }
 
@@ -66,7 +70,7 @@ func fp751ConditionalSwap(x, y *FpElement, mask uint8) {
 // with R=2^768. Destroys the input value.
 func fp751MontgomeryReduce(z *FpElement, x *FpElementX2) {
 	var carry, t, u, v uint64
-	var uv Uint128
+	var hi, lo uint64
 	var count int
 
 	count = 5 // number of 0 digits in the least significat part of p751 + 1
@@ -74,14 +78,14 @@ func fp751MontgomeryReduce(z *FpElement, x *FpElementX2) {
 	for i := 0; i < NumWords; i++ {
 		for j := 0; j < i; j++ {
 			if j < (i - count + 1) {
-				uv = Mul64(z[j], p751p1[i-j])
-				v, carry = Addc64(0, uv.L, v)
-				u, carry = Addc64(carry, uv.H, u)
+				hi, lo = bits.Mul64(z[j], p751p1[i-j])
+				v, carry = bits.Add64(v, lo, 0)
+				u, carry = bits.Add64(u, hi, carry)
 				t += carry
 			}
 		}
-		v, carry = Addc64(0, v, x[i])
-		u, carry = Addc64(carry, u, 0)
+		v, carry = bits.Add64(v, x[i], 0)
+		u, carry = bits.Add64(u, 0, carry)
 		t += carry
 
 		z[i] = v
@@ -96,14 +100,14 @@ func fp751MontgomeryReduce(z *FpElement, x *FpElementX2) {
 		}
 		for j := i - NumWords + 1; j < NumWords; j++ {
 			if j < (NumWords - count) {
-				uv = Mul64(z[j], p751p1[i-j])
-				v, carry = Addc64(0, uv.L, v)
-				u, carry = Addc64(carry, uv.H, u)
+				hi, lo = bits.Mul64(z[j], p751p1[i-j])
+				v, carry = bits.Add64(v, lo, 0)
+				u, carry = bits.Add64(u, hi, carry)
 				t += carry
 			}
 		}
-		v, carry = Addc64(0, v, x[i])
-		u, carry = Addc64(carry, u, 0)
+		v, carry = bits.Add64(v, x[i], 0)
+		u, carry = bits.Add64(u, 0, carry)
 
 		t += carry
 		z[i-NumWords] = v
@@ -111,7 +115,7 @@ func fp751MontgomeryReduce(z *FpElement, x *FpElementX2) {
 		u = t
 		t = 0
 	}
-	v, carry = Addc64(0, v, x[2*NumWords-1])
+	v, carry = bits.Add64(v, x[2*NumWords-1], 0)
 	z[NumWords-1] = v
 }
 
@@ -119,13 +123,13 @@ func fp751MontgomeryReduce(z *FpElement, x *FpElementX2) {
 func fp751Mul(z *FpElementX2, x, y *FpElement) {
 	var u, v, t uint64
 	var carry uint64
-	var uv Uint128
+	var hi, lo uint64
 
 	for i := uint64(0); i < NumWords; i++ {
 		for j := uint64(0); j <= i; j++ {
-			uv = Mul64(x[j], y[i-j])
-			v, carry = Addc64(0, uv.L, v)
-			u, carry = Addc64(carry, uv.H, u)
+			hi, lo = bits.Mul64(x[j], y[i-j])
+			v, carry = bits.Add64(v, lo, 0)
+			u, carry = bits.Add64(u, hi, carry)
 			t += carry
 		}
 		z[i] = v
@@ -136,9 +140,9 @@ func fp751Mul(z *FpElementX2, x, y *FpElement) {
 
 	for i := NumWords; i < (2*NumWords)-1; i++ {
 		for j := i - NumWords + 1; j < NumWords; j++ {
-			uv = Mul64(x[j], y[i-j])
-			v, carry = Addc64(0, uv.L, v)
-			u, carry = Addc64(carry, uv.H, u)
+			hi, lo = bits.Mul64(x[j], y[i-j])
+			v, carry = bits.Add64(v, lo, 0)
+			u, carry = bits.Add64(u, hi, carry)
 			t += carry
 		}
 		z[i] = v
@@ -153,7 +157,7 @@ func fp751Mul(z *FpElementX2, x, y *FpElement) {
 func fp751AddLazy(z, x, y *FpElement) {
 	var carry uint64
 	for i := 0; i < NumWords; i++ {
-		z[i], carry = Addc64(carry, x[i], y[i])
+		z[i], carry = bits.Add64(x[i], y[i], carry)
 	}
 }
 
@@ -161,7 +165,7 @@ func fp751AddLazy(z, x, y *FpElement) {
 func fp751X2AddLazy(z, x, y *FpElementX2) {
 	var carry uint64
 	for i := 0; i < 2*NumWords; i++ {
-		z[i], carry = Addc64(carry, x[i], y[i])
+		z[i], carry = bits.Add64(x[i], y[i], carry)
 	}
 }
 
@@ -169,14 +173,14 @@ func fp751X2AddLazy(z, x, y *FpElementX2) {
 func fp751StrongReduce(x *FpElement) {
 	var borrow, mask uint64
 	for i := 0; i < NumWords; i++ {
-		x[i], borrow = Subc64(borrow, x[i], p751[i])
+		x[i], borrow = bits.Sub64(x[i], p751[i], borrow)
 	}
 
 	// Sets all bits if borrow = 1
 	mask = 0 - borrow
 	borrow = 0
 	for i := 0; i < NumWords; i++ {
-		x[i], borrow = Addc64(borrow, x[i], p751[i]&mask)
+		x[i], borrow = bits.Add64(x[i], p751[i]&mask, borrow)
 	}
 }
 
@@ -184,13 +188,13 @@ func fp751StrongReduce(x *FpElement) {
 func fp751X2SubLazy(z, x, y *FpElementX2) {
 	var borrow, mask uint64
 	for i := 0; i < len(z); i++ {
-		z[i], borrow = Subc64(borrow, x[i], y[i])
+		z[i], borrow = bits.Sub64(x[i], y[i], borrow)
 	}
 
 	// Sets all bits if borrow = 1
 	mask = 0 - borrow
 	borrow = 0
 	for i := NumWords; i < len(z); i++ {
-		z[i], borrow = Addc64(borrow, z[i], p751[i-NumWords]&mask)
+		z[i], borrow = bits.Add64(z[i], p751[i-NumWords]&mask, borrow)
 	}
 }
@@ -4,8 +4,13 @@ import (
 	. "github.com/henrydcase/nobs/dh/sidh/internal/isogeny"
 	p503 "github.com/henrydcase/nobs/dh/sidh/p503"
 	p751 "github.com/henrydcase/nobs/dh/sidh/p751"
+	"github.com/henrydcase/nobs/utils/security"
 )
 
+// SIDH and SIKE are broken by the key recovery attack of Castryck and
+// Decru, which runs in hours on a single core for all parameter sets.
+const sidhAttack = "broken by Castryck-Decru key recovery attack, https://eprint.iacr.org/2022/975"
+
 // Keeps mapping: SIDH prime field ID to domain parameters
 var sidhParams = make(map[uint8]SidhParams)
 
@@ -46,6 +51,12 @@ func init() {
 		KemSize: 16,
 		Bytelen: p503.P503_Bytelen,
 		Op:      p503.FieldOperations(),
+		Meta: security.Metadata{
+			Name:      "SIKEp503",
+			Level:     2,
+			Status:    security.Broken,
+			Reference: sidhAttack,
+		},
 	}
 
 	p751 := SidhParams{
@@ -75,6 +86,12 @@ func init() {
 		KemSize: 24,
 		Bytelen: p751.P751_Bytelen,
 		Op:      p751.FieldOperations(),
+		Meta: security.Metadata{
+			Name:      "SIKEp751",
+			Level:     5,
+			Status:    security.Broken,
+			Reference: sidhAttack,
+		},
 	}
 
 	sidhParams[FP_503] = p503
@@ -8,6 +8,15 @@ import (
 // Functions for traversing isogeny trees acoording to strategy. Key type 'A' is
 //
 
+// Cleans all points in the backing array of the slice, including those
+// which were popped from it.
+func zeroizePoints(points []ProjectivePoint) {
+	points = points[:cap(points)]
+	for i := range points {
+		points[i].Zeroize()
+	}
+}
+
 // Traverses isogeny tree in order to compute xR, xP, xQ and xQmP needed
 // for public key generation.
 func traverseTreePublicKeyA(curve *ProjectiveCurveParameters, xR, phiP, phiQ, phiR *ProjectivePoint, pub *PublicKey) {
@@ -45,6 +54,11 @@ func traverseTreePublicKeyA(curve *ProjectiveCurveParameters, xR, phiP, phiQ, ph
 		*xR, points = points[len(points)-1], points[:len(points)-1]
 		i, indices = int(indices[len(indices)-1]), indices[:len(indices)-1]
 	}
+
+	// Intermediate points and curves depend on the private key
+	zeroizePoints(points)
+	cparam.Zeroize()
+	phi.Zeroize()
 }
 
 // Traverses isogeny tree in order to compute xR needed
@@ -80,6 +94,11 @@ func traverseTreeSharedKeyA(curve *ProjectiveCurveParameters, xR *ProjectivePoin
 		*xR, points = points[len(points)-1], points[:len(points)-1]
 		i, indices = int(indices[len(indices)-1]), indices[:len(indices)-1]
 	}
+
+	// Intermediate points and curves depend on the private key
+	zeroizePoints(points)
+	cparam.Zeroize()
+	phi.Zeroize()
 }
 
 // Traverses isogeny tree in order to compute xR, xP, xQ and xQmP needed
@@ -119,6 +138,11 @@ func traverseTreePublicKeyB(curve *ProjectiveCurveParameters, xR, phiP, phiQ, ph
 		*xR, points = points[len(points)-1], points[:len(points)-1]
 		i, indices = int(indices[len(indices)-1]), indices[:len(indices)-1]
 	}
+
+	// Intermediate points and curves depend on the private key
+	zeroizePoints(points)
+	cparam.Zeroize()
+	phi.Zeroize()
 }
 
 // Traverses isogeny tree in order to compute xR, xP, xQ and xQmP needed
@@ -154,6 +178,11 @@ func traverseTreeSharedKeyB(curve *ProjectiveCurveParameters, xR *ProjectivePoin
 		*xR, points = points[len(points)-1], points[:len(points)-1]
 		i, indices = int(indices[len(indices)-1]), indices[:len(indices)-1]
 	}
+
+	// Intermediate points and curves depend on the private key
+	zeroizePoints(points)
+	cparam.Zeroize()
+	phi.Zeroize()
 }
 
 // Generate a public key in the 2-torsion group
@@ -196,6 +225,11 @@ func publicKeyGenA(prv *PrivateKey) (pub *PublicKey) {
 	op.Params.Op.Mul(&pub.affine_xP, &xPA.X, &invZP)
 	op.Params.Op.Mul(&pub.affine_xQ, &xQA.X, &invZQ)
 	op.Params.Op.Mul(&pub.affine_xQmP, &xRA.X, &invZR)
+
+	// Kernel of the secret isogeny
+	xR.Zeroize()
+	tmp.Zeroize()
+	phi.Zeroize()
 	return
 }
 
@@ -236,6 +270,11 @@ func publicKeyGenB(prv *PrivateKey) (pub *PublicKey) {
 	op.Params.Op.Mul(&pub.affine_xP, &xPB.X, &invZP)
 	op.Params.Op.Mul(&pub.affine_xQ, &xQB.X, &invZQ)
 	op.Params.Op.Mul(&pub.affine_xQmP, &xRB.X, &invZR)
+
+	// Kernel of the secret isogeny
+	xR.Zeroize()
+	tmp.Zeroize()
+	phi.Zeroize()
 	return
 }
 
@@ -269,6 +308,12 @@ func deriveSecretA(prv *PrivateKey, pub *PublicKey) []byte {
 	c := phi.GenerateCurve(&xR)
 	op.RecoverCurveCoefficients4(&cparam, &c)
 	op.Jinvariant(&cparam, sharedSecret)
+
+	// Kernel and isogenous curve, from which the secret can be computed
+	xR.Zeroize()
+	c.Zeroize()
+	cparam.Zeroize()
+	phi.Zeroize()
 	return sharedSecret
 }
 
@@ -298,5 +343,11 @@ func deriveSecretB(prv *PrivateKey, pub *PublicKey) []byte {
 	c := phi.GenerateCurve(&xR)
 	op.RecoverCurveCoefficients3(&cparam, &c)
 	op.Jinvariant(&cparam, sharedSecret)
+
+	// Kernel and isogenous curve, from which the secret can be computed
+	xR.Zeroize()
+	c.Zeroize()
+	cparam.Zeroize()
+	phi.Zeroize()
 	return sharedSecret
 }
@@ -4,13 +4,22 @@ import (
 	"bytes"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
+	"os"
 	"testing"
 
 	. "github.com/henrydcase/nobs/dh/sidh/internal/isogeny"
+	"github.com/henrydcase/nobs/utils/security"
 )
 
+// SIDH is broken, it must be explicitly enabled for testing
+func TestMain(m *testing.M) {
+	security.AllowInsecure(true)
+	os.Exit(m.Run())
+}
+
 /* -------------------------------------------------------------------------
    Test data
    -------------------------------------------------------------------------*/
@@ -190,6 +199,86 @@ func testRoundtrip(t testing.TB, id uint8) {
 	}
 }
 
+// Fail unless f panics with errDestroyed
+func checkPanic(t testing.TB, msg string, f func()) {
+	defer func() {
+		if recover() != errDestroyed {
+			t.Errorf("%s: expected panic with errDestroyed", msg)
+		}
+	}()
+	f()
+}
+
+// Fail if any byte of b is not zero
+func checkZero(t testing.TB, b []byte, msg string) {
+	for _, v := range b {
+		if v != 0 {
+			t.Errorf("%s not zeroized: %X", msg, b)
+			return
+		}
+	}
+}
+
+func testZeroize(t testing.TB, id uint8) {
+	prv := NewPrivateKey(id, KeyVariant_SIKE)
+	checkErr(t, prv.Generate(rand.Reader), "key generation failed")
+	scalar, s := prv.Scalar, prv.S
+	prv.Zeroize()
+	checkZero(t, scalar, "scalar")
+	checkZero(t, s, "S")
+
+	// Key can be reused after zeroization
+	checkErr(t, prv.Generate(rand.Reader), "key generation after Zeroize failed")
+	prvA := NewPrivateKey(id, KeyVariant_SIDH_A)
+	checkErr(t, prvA.Generate(rand.Reader), "key generation failed")
+	s1, err := DeriveSecret(prv, prvA.GeneratePublicKey())
+	checkErr(t, err, "derivation after Zeroize failed")
+	s2, err := DeriveSecret(prvA, prv.GeneratePublicKey())
+	checkErr(t, err, "derivation after Zeroize failed")
+	if !bytes.Equal(s1, s2) {
+		t.Error("shared keys after Zeroize do not match")
+	}
+}
+
+func testDestroy(t testing.TB, id uint8) {
+	prvA := NewPrivateKey(id, KeyVariant_SIDH_A)
+	prvB := NewPrivateKey(id, KeyVariant_SIKE)
+	checkErr(t, prvA.Generate(rand.Reader), "key generation failed")
+	checkErr(t, prvB.Generate(rand.Reader), "key generation failed")
+	pubA := prvA.GeneratePublicKey()
+	exported := prvB.Export()
+
+	scalar, s := prvB.Scalar, prvB.S
+	prvB.Destroy()
+	checkZero(t, scalar, "scalar")
+	checkZero(t, s, "S")
+
+	checkPanic(t, "DeriveSecret", func() { DeriveSecret(prvB, pubA) })
+	checkPanic(t, "Generate", func() { prvB.Generate(rand.Reader) })
+	checkPanic(t, "Import", func() { prvB.Import(exported) })
+	checkPanic(t, "Export", func() { prvB.Export() })
+	checkPanic(t, "GeneratePublicKey", func() { prvB.GeneratePublicKey() })
+}
+
+// Checks that also points popped from the slice are zeroized
+func TestZeroizePoints(t *testing.T) {
+	points := make([]ProjectivePoint, 0, 4)
+	for i := 0; i < 3; i++ {
+		var p ProjectivePoint
+		p.X.A[0], p.Z.B[FP_MAX_WORDS-1] = 1, 1
+		points = append(points, p)
+	}
+	points = points[:1]
+	zeroizePoints(points)
+
+	var zero ProjectivePoint
+	for i, p := range points[:cap(points)] {
+		if p != zero {
+			t.Errorf("point %d not zeroized", i)
+		}
+	}
+}
+
 func testKeyAgreement(t testing.TB, id uint8, pkA, prA, pkB, prB string) {
 	var e error
 
@@ -312,10 +401,32 @@ func TestKeyAgreementP751_AliceEvenNumber(t *testing.T) {
 /* -------------------------------------------------------------------------
    Wrappers for 'testing' module
    -------------------------------------------------------------------------*/
+func TestInsecureRefused(t *testing.T) {
+	security.AllowInsecure(false)
+	defer security.AllowInsecure(true)
+
+	for id := range tdata {
+		if Params(id).Meta.Status != security.Broken {
+			t.Errorf("%s not marked as broken", Params(id).Meta.Name)
+		}
+		prvA := NewPrivateKey(id, KeyVariant_SIDH_A)
+		if err := prvA.Generate(rand.Reader); !errors.Is(err, security.ErrInsecure) {
+			t.Errorf("%s: expected ErrInsecure, got %v", Params(id).Meta.Name, err)
+		}
+		prvB := NewPrivateKey(id, KeyVariant_SIDH_B)
+		pubA := prvA.GeneratePublicKey()
+		if _, err := DeriveSecret(prvB, pubA); !errors.Is(err, security.ErrInsecure) {
+			t.Errorf("%s: expected ErrInsecure, got %v", Params(id).Meta.Name, err)
+		}
+	}
+}
+
 func TestKeygen(t *testing.T)             { Do(testKeygen, t) }
 func TestRoundtrip(t *testing.T)          { Do(testRoundtrip, t) }
 func TestImportExport(t *testing.T)       { Do(testImportExport, t) }
 func TestPrivateKeyBelowMax(t *testing.T) { Do(testPrivateKeyBelowMax, t) }
+func TestZeroize(t *testing.T)            { Do(testZeroize, t) }
+func TestDestroy(t *testing.T)            { Do(testDestroy, t) }
 
 /* -------------------------------------------------------------------------
    Benchmarking
@@ -0,0 +1,31 @@
+//go:build ctcheck
+// +build ctcheck
+
+package drbg
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/henrydcase/nobs/utils/ctcheck"
+)
+
+// Checks that generation of random bytes runs in the same time for fixed
+// entropy input (class 0) and random one (class 1).
+func TestReadCT(t *testing.T) {
+	var fixed [SeedLen]byte
+	rand.Read(fixed[:])
+	ctcheck.Check(t, "Read", 50000, func(class int) func() {
+		var out [4 * BlockLen]byte
+		entropy := fixed
+		if class == 1 {
+			rand.Read(entropy[:])
+		}
+		ctcheck.Secret(entropy[:])
+		c := NewCtrDrbg()
+		if !c.Init(entropy[:], nil) {
+			t.Fatal("Init failed")
+		}
+		return func() { c.Read(out[:]) }
+	})
+}
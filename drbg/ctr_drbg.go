@@ -13,7 +13,9 @@
 package drbg
 
 import (
-	"github.com/henrydcase/nobs/drbg/internal/aes"
+	"errors"
+
+	"github.com/henrydcase/nobs/cipher/aes"
 )
 
 // Constants below correspond to AES-256, which is currently
@@ -24,6 +26,9 @@ const (
 	SeedLen  = BlockLen + KeyLen
 )
 
+// Panic value when destroyed DRBG is used
+var errDestroyed = errors.New("drbg: destroyed")
+
 type CtrDrbg struct {
 	v          [BlockLen]byte
 	key        [KeyLen]byte
@@ -32,6 +37,7 @@ type CtrDrbg struct {
 	resistance bool
 	blockEnc   aes.AES
 	tmpBlk     [3 * BlockLen]byte
+	destroyed  bool
 }
 
 func NewCtrDrbg() *CtrDrbg {
@@ -49,10 +55,16 @@ func (c *CtrDrbg) inc() {
 	}
 }
 
+// Init instantiates DRBG with entropy and personalization string.
+// Returns false if entropy is too short. Panics if DRBG was destroyed.
 func (c *CtrDrbg) Init(entropy, personalization []byte) bool {
 	var lsz int
 	var seedBuf [SeedLen]byte
 
+	if c.destroyed {
+		panic(errDestroyed)
+	}
+
 	// Minimum entropy input (SP800-90A, 10.2.1)
 	if len(entropy) < int(c.strength/8) {
 		return false
@@ -79,6 +91,7 @@ func (c *CtrDrbg) Init(entropy, personalization []byte) bool {
 	c.blockEnc.SetKey(c.key[:])
 	c.update(seedBuf[:])
 	c.counter = 1
+	seedBuf = [SeedLen]byte{}
 	return true
 }
 
@@ -102,10 +115,15 @@ func (c *CtrDrbg) update(data []byte) {
 	copy(c.v[:], c.tmpBlk[KeyLen:])
 }
 
+// Reseed panics if DRBG was destroyed.
 func (c *CtrDrbg) Reseed(entropy, data []byte) {
 	var seedBuf [SeedLen]byte
 	var lsz int
 
+	if c.destroyed {
+		panic(errDestroyed)
+	}
+
 	lsz = len(entropy)
 	if lsz > SeedLen {
 		lsz = SeedLen
@@ -123,12 +141,17 @@ func (c *CtrDrbg) Reseed(entropy, data []byte) {
 
 	c.update(seedBuf[:])
 	c.counter = 1
+	seedBuf = [SeedLen]byte{}
 }
 
 func (c *CtrDrbg) ReadWithAdditionalData(out, ad []byte) (n int, err error) {
 	var seedBuf [SeedLen]byte
 	// TODO: check reseed_counter > reseed_interval
 
+	if c.destroyed {
+		panic(errDestroyed)
+	}
+
 	if len(ad) > 0 {
 		// pad additional data with zeros if needed
 		copy(seedBuf[:], ad)
@@ -153,7 +176,30 @@ func (c *CtrDrbg) ReadWithAdditionalData(out, ad []byte) (n int, err error) {
 }
 
 // Read reads data from DRBG. Size of data is determined by
-// out buffer.
+// out buffer. Panics if DRBG was destroyed.
 func (c *CtrDrbg) Read(out []byte) (n int, err error) {
 	return c.ReadWithAdditionalData(out, nil)
 }
+
+// Zeroize overwrites internal state of DRBG with zeros. DRBG must be
+// initialized with Init before next use.
+func (c *CtrDrbg) Zeroize() {
+	for i := range c.v {
+		c.v[i] = 0
+	}
+	for i := range c.key {
+		c.key[i] = 0
+	}
+	for i := range c.tmpBlk {
+		c.tmpBlk[i] = 0
+	}
+	c.blockEnc.Zeroize()
+	c.counter = 0
+}
+
+// Destroy zeroizes DRBG and marks it as destroyed. Any later call to
+// Init, Reseed or Read panics.
+func (c *CtrDrbg) Destroy() {
+	c.Zeroize()
+	c.destroyed = true
+}
@@ -100,3 +100,59 @@ func BenchmarkRead(b *testing.B) {
 		c.ReadWithAdditionalData(result[:], vectors[0].AdditionalInput1)
 	}
 }
+
+func TestZeroize(t *testing.T) {
+	var out1, out2 [40]byte
+	entropy := vectors[0].EntropyInput
+
+	c := NewCtrDrbg()
+	if !c.Init(entropy, nil) {
+		t.FailNow()
+	}
+	c.Read(out1[:])
+	c.Zeroize()
+	if c.v != [BlockLen]byte{} || c.key != [KeyLen]byte{} || c.tmpBlk != [3 * BlockLen]byte{} {
+		t.Error("state not zeroized")
+	}
+
+	// Zeroized DRBG can be initialized again
+	if !c.Init(entropy, nil) {
+		t.FailNow()
+	}
+	c.Read(out2[:])
+	if out1 != out2 {
+		t.Error("output differs after Zeroize")
+	}
+}
+
+func TestDestroy(t *testing.T) {
+	var out [16]byte
+	entropy := vectors[0].EntropyInput
+
+	c := NewCtrDrbg()
+	if !c.Init(entropy, nil) {
+		t.FailNow()
+	}
+	c.Destroy()
+	if c.v != [BlockLen]byte{} || c.key != [KeyLen]byte{} || c.tmpBlk != [3 * BlockLen]byte{} {
+		t.Error("state not zeroized")
+	}
+	ops := map[string]func(){
+		"Init":   func() { c.Init(entropy, nil) },
+		"Reseed": func() { c.Reseed(entropy, nil) },
+		"Read":   func() { c.Read(out[:]) },
+	}
+	for name, f := range ops {
+		func() {
+			defer func() {
+				if recover() != errDestroyed {
+					t.Errorf("%s: expected panic with errDestroyed", name)
+				}
+			}()
+			f()
+		}()
+	}
+	if out != [16]byte{} {
+		t.Error("Read wrote output after Destroy")
+	}
+}
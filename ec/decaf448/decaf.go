@@ -0,0 +1,342 @@
+// Package decaf448 implements Decaf448, a prime-order group built on top
+// of edwards448, as specified in RFC 9496. It's meant for protocols that
+// need a prime-order group, like OPRFs and PAKEs.
+//
+// Elements are encoded as 56-byte strings. Decoding accepts only the
+// canonical encoding of an element, so two elements are equal if and
+// only if their encodings are equal. Scalars are integers modulo the
+// group order l, see Scalar.
+//
+// All operations on secret data run in constant time.
+package decaf448
+
+import (
+	"errors"
+
+	"github.com/henrydcase/nobs/ec/internal/fp448"
+	"github.com/henrydcase/nobs/hash/sha3"
+)
+
+const (
+	// ElementSize is the size of an encoded element in bytes
+	ElementSize = 56
+	// UniformSize is the number of bytes taken by DeriveElement
+	UniformSize = 112
+)
+
+var (
+	errEncoding = errors.New("decaf448: invalid element encoding")
+
+	// SQRT_MINUS_D and INVSQRT_MINUS_D from RFC 9496, set by init
+	sqrtMinusD, invSqrtMinusD fp448.Elt
+
+	// Encoding of the generator
+	generatorEnc = [ElementSize]byte{
+		0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66,
+		0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66,
+		0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66,
+		0x66, 0x66, 0x66, 0x66, 0x33, 0x33, 0x33, 0x33,
+		0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33,
+		0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33,
+		0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33,
+	}
+	generator point
+)
+
+// Element is an element of the Decaf448 group. Its zero value is not
+// valid, use Identity, Generator or SetBytes to initialize it.
+type Element struct {
+	p point
+}
+
+// Identity sets e to the identity element and returns e
+func (e *Element) Identity() *Element {
+	e.p.setIdentity()
+	return e
+}
+
+// Generator sets e to the generator of the group and returns e
+func (e *Element) Generator() *Element {
+	e.p = generator
+	return e
+}
+
+// Set sets e = x and returns e
+func (e *Element) Set(x *Element) *Element {
+	*e = *x
+	return e
+}
+
+// Add sets e = x + y and returns e
+func (e *Element) Add(x, y *Element) *Element {
+	e.p.add(&x.p, &y.p)
+	return e
+}
+
+// Sub sets e = x - y and returns e
+func (e *Element) Sub(x, y *Element) *Element {
+	var n point
+	n.neg(&y.p)
+	e.p.add(&x.p, &n)
+	return e
+}
+
+// Neg sets e = -x and returns e
+func (e *Element) Neg(x *Element) *Element {
+	e.p.neg(&x.p)
+	return e
+}
+
+// Double sets e = 2*x and returns e
+func (e *Element) Double(x *Element) *Element {
+	e.p.double(&x.p)
+	return e
+}
+
+// ScalarMult sets e = k*x and returns e
+func (e *Element) ScalarMult(k *Scalar, x *Element) *Element {
+	e.p.scalarMult(k, &x.p)
+	return e
+}
+
+// ScalarBaseMult sets e = k*G, where G is the generator, and returns e
+func (e *Element) ScalarBaseMult(k *Scalar) *Element {
+	e.p.scalarMult(k, &generator)
+	return e
+}
+
+// Equal returns 1 if e and x represent the same element and 0 otherwise.
+// Constant time.
+func (e *Element) Equal(x *Element) int {
+	// Points differing by a 4-torsion point are equal: x1*y2 == x2*y1
+	var a, b fp448.Elt
+	a.Mul(&e.p.x, &x.p.y)
+	b.Mul(&x.p.x, &e.p.y)
+	return a.Equal(&b)
+}
+
+// Bytes returns the canonical encoding of e (RFC 9496, 5.3.2)
+func (e *Element) Bytes() []byte {
+	var u1, u2, t, invSqrt, ratio, s fp448.Elt
+	p := &e.p
+
+	// u1 = (x0 + t0) * (x0 - t0)
+	u1.Add(&p.x, &p.t)
+	t.Sub(&p.x, &p.t)
+	u1.Mul(&u1, &t)
+
+	// invsqrt = 1/sqrt(u1 * (1-d) * x0^2)
+	t.Sqr(&p.x)
+	t.Mul(&t, &u1)
+	t.Mlw(&t, 1+minusD)
+	invSqrt.SqrtRatio(&fp448.One, &t)
+
+	// ratio = abs(invsqrt * u1 * sqrt(-d))
+	ratio.Mul(&invSqrt, &u1)
+	ratio.Mul(&ratio, &sqrtMinusD)
+	ratio.Abs(&ratio)
+
+	// u2 = invsqrt(-d) * ratio * z0 - t0
+	u2.Mul(&invSqrtMinusD, &ratio)
+	u2.Mul(&u2, &p.z)
+	u2.Sub(&u2, &p.t)
+
+	// s = abs((1-d) * invsqrt * x0 * u2)
+	s.Mul(&invSqrt, &p.x)
+	s.Mul(&s, &u2)
+	s.Mlw(&s, 1+minusD)
+	s.Abs(&s)
+
+	var out [ElementSize]byte
+	s.Ser(&out)
+	return out[:]
+}
+
+// SetBytes sets e to the element encoded in b (RFC 9496, 5.3.1). Returns
+// an error if b is not a canonical encoding of an element, in which case
+// e is not modified.
+func (e *Element) SetBytes(b []byte) error {
+	if len(b) != ElementSize {
+		return errEncoding
+	}
+	var in [ElementSize]byte
+	var s, ss, u1, u2, t, invSqrt, u3 fp448.Elt
+	var p point
+	copy(in[:], b)
+
+	// s must be canonical and non-negative
+	ok := s.SetBytes(&in)
+	ok &= 1 ^ s.IsNegative()
+
+	// u1 = 1 + s^2, u2 = u1^2 - 4*d*s^2
+	ss.Sqr(&s)
+	u1.Add(&fp448.One, &ss)
+	u2.Sqr(&u1)
+	t.Mlw(&ss, 4*minusD)
+	u2.Add(&u2, &t)
+
+	// invsqrt = 1/sqrt(u2 * u1^2)
+	t.Sqr(&u1)
+	t.Mul(&t, &u2)
+	ok &= invSqrt.SqrtRatio(&fp448.One, &t)
+
+	// u3 = abs(2 * s * invsqrt * u1 * sqrt(-d))
+	u3.Add(&s, &s)
+	u3.Mul(&u3, &invSqrt)
+	u3.Mul(&u3, &u1)
+	u3.Mul(&u3, &sqrtMinusD)
+	u3.Abs(&u3)
+
+	// x = u3 * invsqrt * u2 * invsqrt(-d)
+	p.x.Mul(&u3, &invSqrt)
+	p.x.Mul(&p.x, &u2)
+	p.x.Mul(&p.x, &invSqrtMinusD)
+
+	// y = (1 - s^2) * invsqrt * u1
+	p.y.Sub(&fp448.One, &ss)
+	p.y.Mul(&p.y, &invSqrt)
+	p.y.Mul(&p.y, &u1)
+
+	p.z.Cpy(&fp448.One)
+	p.t.Mul(&p.x, &p.y)
+
+	if ok != 1 {
+		return errEncoding
+	}
+	e.p = p
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (e *Element) MarshalBinary() ([]byte, error) {
+	return e.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (e *Element) UnmarshalBinary(data []byte) error {
+	return e.SetBytes(data)
+}
+
+// Maps a field element to an element of the group (RFC 9496, 5.3.4)
+func (e *Element) mapToElement(in *[ElementSize]byte) {
+	var t, r, u0, u1, v, vPrime, sgn, s, w0, w1, w2, w3, x fp448.Elt
+	var negOne, tv fp448.Elt
+
+	t.Deser(in)
+
+	// r = -t^2
+	r.Sqr(&t)
+	r.Neg(&r)
+
+	// u0 = d * (r-1), u1 = (u0 + 1) * (u0 - r)
+	u0.Sub(&r, &fp448.One)
+	u0.Mlw(&u0, minusD)
+	u0.Neg(&u0)
+	u1.Add(&u0, &fp448.One)
+	x.Sub(&u0, &r)
+	u1.Mul(&u1, &x)
+
+	// was_square, v = sqrt_ratio_m1(1 - 2*d, (r + 1) * u1)
+	var oneMinusTwoD, rPlusOne fp448.Elt
+	oneMinusTwoD.Mlw(&fp448.One, 1+2*minusD)
+	rPlusOne.Add(&r, &fp448.One)
+	x.Mul(&rPlusOne, &u1)
+	wasSquare := v.SqrtRatio(&oneMinusTwoD, &x)
+	mask := -uint32(1 ^ wasSquare)
+
+	// v' = v if was_square else t*v, sgn = 1 if was_square else -1
+	tv.Mul(&t, &v)
+	vPrime.Cpy(&v)
+	vPrime.Cmov(&tv, mask)
+	negOne.Neg(&fp448.One)
+	sgn.Cpy(&fp448.One)
+	sgn.Cmov(&negOne, mask)
+
+	// s = v' * (r + 1)
+	s.Mul(&vPrime, &rPlusOne)
+
+	// w0 = 2*abs(s), w1 = s^2 + 1, w2 = s^2 - 1,
+	// w3 = v' * s * (r - 1) * (1 - 2*d) + sgn
+	w0.Abs(&s)
+	w0.Add(&w0, &w0)
+	x.Sqr(&s)
+	w1.Add(&x, &fp448.One)
+	w2.Sub(&x, &fp448.One)
+	w3.Mul(&vPrime, &s)
+	x.Sub(&r, &fp448.One)
+	w3.Mul(&w3, &x)
+	w3.Mul(&w3, &oneMinusTwoD)
+	w3.Add(&w3, &sgn)
+
+	e.p.x.Mul(&w0, &w3)
+	e.p.y.Mul(&w2, &w1)
+	e.p.z.Mul(&w1, &w3)
+	e.p.t.Mul(&w0, &w2)
+}
+
+// DeriveElement sets e to the element derived from 112 uniformly random
+// bytes (RFC 9496, 5.3.4) and returns e. The result is indistinguishable
+// from a uniformly random element. Panics if len(b) != UniformSize.
+func (e *Element) DeriveElement(b []byte) *Element {
+	if len(b) != UniformSize {
+		panic("decaf448: wrong size of input")
+	}
+	var in [ElementSize]byte
+	var p2 Element
+	copy(in[:], b[:ElementSize])
+	e.mapToElement(&in)
+	copy(in[:], b[ElementSize:])
+	p2.mapToElement(&in)
+	return e.Add(e, &p2)
+}
+
+// Implements expand_message_xof from RFC 9380 with SHAKE256
+func expandMessage(out, msg, dst []byte) {
+	if len(dst) > 255 {
+		// Hash oversized DST to ceil(2*224/8) bytes
+		h := sha3.NewShake256()
+		h.Write([]byte("H2C-OVERSIZE-DST-"))
+		h.Write(dst)
+		dst = make([]byte, 56)
+		h.Read(dst)
+	}
+	h := sha3.NewShake256()
+	h.Write(msg)
+	h.Write([]byte{byte(len(out) >> 8), byte(len(out))})
+	h.Write(dst)
+	h.Write([]byte{byte(len(dst))})
+	h.Read(out)
+}
+
+// HashToElement sets e to the hash of msg and returns e. Uses
+// expand_message_xof with SHAKE256 (RFC 9380) to produce 112 bytes, which
+// are passed to DeriveElement. The dst is a domain separation tag.
+func (e *Element) HashToElement(msg, dst []byte) *Element {
+	var b [UniformSize]byte
+	expandMessage(b[:], msg, dst)
+	return e.DeriveElement(b[:])
+}
+
+// HashToScalar sets s to the hash of msg and returns s. Uses
+// expand_message_xof with SHAKE256 (RFC 9380) to produce 84 bytes, which
+// are reduced modulo the group order, as in RFC 9497. The dst is a domain
+// separation tag.
+func (s *Scalar) HashToScalar(msg, dst []byte) *Scalar {
+	var b [84]byte
+	expandMessage(b[:], msg, dst)
+	return s.SetUniformBytes(b[:])
+}
+
+func init() {
+	// sqrt(-d) is the non-negative square root of 39081
+	var t fp448.Elt
+	t.Mlw(&fp448.One, minusD)
+	sqrtMinusD.SqrtRatio(&t, &fp448.One)
+	invSqrtMinusD.Inv(&sqrtMinusD)
+
+	var g Element
+	if g.SetBytes(generatorEnc[:]) != nil {
+		panic("decaf448: invalid generator")
+	}
+	generator = g.p
+}
@@ -0,0 +1,114 @@
+package decaf448
+
+import "github.com/henrydcase/nobs/ec/internal/fp448"
+
+// Arithmetic on the Edwards curve edwards448
+//
+//	x^2 + y^2 = 1 + d*x^2*y^2, d = -39081
+//
+// The d is not a square, so the addition law is complete.
+
+// -d
+const minusD = 39081
+
+// Point in extended coordinates: x = X/Z, y = Y/Z and x*y = T/Z
+type point struct {
+	x, y, z, t fp448.Elt
+}
+
+// Sets p to the identity (0, 1)
+func (p *point) setIdentity() {
+	p.x.Cpy(&fp448.Zero)
+	p.y.Cpy(&fp448.One)
+	p.z.Cpy(&fp448.One)
+	p.t.Cpy(&fp448.Zero)
+}
+
+// Computes r = p + q
+func (r *point) add(p, q *point) {
+	var a, b, c, d, e, f, g, h fp448.Elt
+	a.Mul(&p.x, &q.x)
+	b.Mul(&p.y, &q.y)
+	c.Mul(&p.t, &q.t)
+	c.Mlw(&c, minusD)
+	c.Neg(&c) // C = d*t1*t2
+	d.Mul(&p.z, &q.z)
+	e.Add(&p.x, &p.y)
+	f.Add(&q.x, &q.y)
+	e.Mul(&e, &f)
+	e.Sub(&e, &a)
+	e.Sub(&e, &b) // E = x1*y2 + y1*x2
+	f.Sub(&d, &c)
+	g.Add(&d, &c)
+	h.Sub(&b, &a)
+	r.x.Mul(&e, &f)
+	r.y.Mul(&g, &h)
+	r.t.Mul(&e, &h)
+	r.z.Mul(&f, &g)
+}
+
+// Computes r = 2*p
+func (r *point) double(p *point) {
+	var a, b, c, e, f, g, h fp448.Elt
+	a.Sqr(&p.x)
+	b.Sqr(&p.y)
+	c.Sqr(&p.z)
+	c.Add(&c, &c)
+	e.Add(&p.x, &p.y)
+	e.Sqr(&e)
+	e.Sub(&e, &a)
+	e.Sub(&e, &b) // E = 2*x*y
+	g.Add(&a, &b)
+	f.Sub(&g, &c)
+	h.Sub(&a, &b)
+	r.x.Mul(&e, &f)
+	r.y.Mul(&g, &h)
+	r.t.Mul(&e, &h)
+	r.z.Mul(&f, &g)
+}
+
+// Computes r = -p
+func (r *point) neg(p *point) {
+	r.x.Neg(&p.x)
+	r.y.Cpy(&p.y)
+	r.z.Cpy(&p.z)
+	r.t.Neg(&p.t)
+}
+
+// Sets r = p if mask is all 1s, does nothing if mask is 0. Constant time.
+func (r *point) cmov(p *point, mask uint32) {
+	r.x.Cmov(&p.x, mask)
+	r.y.Cmov(&p.y, mask)
+	r.z.Cmov(&p.z, mask)
+	r.t.Cmov(&p.t, mask)
+}
+
+// Computes r = k*p, where k is a scalar reduced modulo the group order,
+// with fixed 4-bit windows. Constant time.
+func (r *point) scalarMult(k *Scalar, p *point) {
+	// tbl[i] = i*p
+	var tbl [16]point
+	tbl[0].setIdentity()
+	tbl[1] = *p
+	for i := 2; i < len(tbl); i++ {
+		tbl[i].add(&tbl[i-1], p)
+	}
+
+	var q, t point
+	q.setIdentity()
+	for i := 16*len(k.l) - 1; i >= 0; i-- {
+		q.double(&q)
+		q.double(&q)
+		q.double(&q)
+		q.double(&q)
+		w := uint32(k.l[i/16]>>(4*uint(i%16))) & 0xF
+		t.setIdentity()
+		for j := range tbl {
+			// All 1s if w == j
+			mask := -uint32((uint64(w^uint32(j)) - 1) >> 63)
+			t.cmov(&tbl[j], mask)
+		}
+		q.add(&q, &t)
+	}
+	*r = q
+}
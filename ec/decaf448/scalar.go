@@ -0,0 +1,226 @@
+package decaf448
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+// ScalarSize is the size of an encoded scalar in bytes
+const ScalarSize = 56
+
+// Scalar is an integer modulo the group order
+//
+//	l = 2^446 - 13818066809895115352007386748515426880336692474882178609894547503885
+//
+// stored as little-endian 64-bit limbs, always fully reduced. All
+// operations run in constant time.
+type Scalar struct {
+	l [7]uint64
+}
+
+var (
+	// Group order
+	order = [7]uint64{
+		0x2378c292ab5844f3, 0x216cc2728dc58f55, 0xc44edb49aed63690, 0xffffffff7cca23e9,
+		0xffffffffffffffff, 0xffffffffffffffff, 0x3fffffffffffffff,
+	}
+	// R^2 mod l, where R = 2^448
+	orderR2 = [7]uint64{
+		0xe3539257049b9b60, 0x7af32c4bc1b195d9, 0x0d66de2388ea1859, 0xae17cf725ee4d838,
+		0x1a9cc14ba3c47c44, 0x2052bcb7e4d070af, 0x3402a939f823b729,
+	}
+	// -l^-1 mod 2^64
+	orderInv uint64 = 0x03bd440fae918bc5
+
+	errScalarEncoding = errors.New("decaf448: non-canonical scalar encoding")
+)
+
+// Sets z = x - l if x >= l, z = x otherwise. hi is an extra top limb of x.
+func condSubOrder(z *[7]uint64, x *[7]uint64, hi uint64) {
+	var s [7]uint64
+	var b uint64
+	for i := range s {
+		s[i], b = bits.Sub64(x[i], order[i], b)
+	}
+	_, b = bits.Sub64(hi, 0, b)
+	mask := b - 1 // all 1s if x >= l
+	for i := range z {
+		z[i] = x[i] ^ ((x[i] ^ s[i]) & mask)
+	}
+}
+
+// Montgomery multiplication z = x*y/R mod l. Requires x*y < l*R.
+func montMul(z, x, y *[7]uint64) {
+	var t [9]uint64
+	for i := 0; i < 7; i++ {
+		// t += x*y[i]
+		var c, cc uint64
+		for j := 0; j < 7; j++ {
+			hi, lo := bits.Mul64(x[j], y[i])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j], c = lo, hi
+		}
+		t[7], cc = bits.Add64(t[7], c, 0)
+		t[8] = cc
+
+		// t = (t + m*l) / 2^64
+		m := t[0] * orderInv
+		hi, lo := bits.Mul64(m, order[0])
+		_, cc = bits.Add64(lo, t[0], 0)
+		c = hi + cc
+		for j := 1; j < 7; j++ {
+			hi, lo = bits.Mul64(m, order[j])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j-1], c = lo, hi
+		}
+		t[6], cc = bits.Add64(t[7], c, 0)
+		t[7] = t[8] + cc
+	}
+	var r [7]uint64
+	copy(r[:], t[:7])
+	condSubOrder(z, &r, t[7])
+}
+
+// SetUint64 sets s = v and returns s
+func (s *Scalar) SetUint64(v uint64) *Scalar {
+	s.l = [7]uint64{v}
+	return s
+}
+
+// SetBytes sets s to the value of the little-endian, canonical encoding
+// in b. Returns an error if b has wrong size or encodes a value not
+// smaller than l, in which case s is not modified.
+func (s *Scalar) SetBytes(b []byte) error {
+	if len(b) != ScalarSize {
+		return errScalarEncoding
+	}
+	var x, r [7]uint64
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint64(b[8*i:])
+	}
+	condSubOrder(&r, &x, 0)
+	if r != x {
+		return errScalarEncoding
+	}
+	s.l = x
+	return nil
+}
+
+// SetUniformBytes sets s to the little-endian integer b reduced modulo l.
+// The b can't be longer than 112 bytes. In order to get a scalar
+// indistinguishable from uniform, b must be uniformly random and at least
+// 84 bytes long.
+func (s *Scalar) SetUniformBytes(b []byte) *Scalar {
+	if len(b) > 2*ScalarSize {
+		panic("decaf448: input too long")
+	}
+	var buf [2 * ScalarSize]byte
+	var lo, hi [7]uint64
+	copy(buf[:], b)
+	for i := range lo {
+		lo[i] = binary.LittleEndian.Uint64(buf[8*i:])
+		hi[i] = binary.LittleEndian.Uint64(buf[ScalarSize+8*i:])
+	}
+	// lo + hi*R = (lo*R)/R + hi*R^2/R
+	var one = [7]uint64{1}
+	montMul(&lo, &lo, &orderR2)
+	montMul(&lo, &lo, &one)
+	montMul(&hi, &hi, &orderR2)
+	s.l = lo
+	return s.Add(s, &Scalar{hi})
+}
+
+// Bytes returns the little-endian, canonical encoding of s
+func (s *Scalar) Bytes() []byte {
+	b := make([]byte, ScalarSize)
+	for i, v := range s.l {
+		binary.LittleEndian.PutUint64(b[8*i:], v)
+	}
+	return b
+}
+
+// Add sets s = x + y and returns s
+func (s *Scalar) Add(x, y *Scalar) *Scalar {
+	var r [7]uint64
+	var c uint64
+	for i := range r {
+		r[i], c = bits.Add64(x.l[i], y.l[i], c)
+	}
+	// x + y < 2l < 2^448, no carry out of the top limb
+	condSubOrder(&s.l, &r, 0)
+	return s
+}
+
+// Sub sets s = x - y and returns s
+func (s *Scalar) Sub(x, y *Scalar) *Scalar {
+	var r [7]uint64
+	var b, c uint64
+	for i := range r {
+		r[i], b = bits.Sub64(x.l[i], y.l[i], b)
+	}
+	// Add l back if the result is negative
+	mask := -b
+	for i := range r {
+		s.l[i], c = bits.Add64(r[i], order[i]&mask, c)
+	}
+	return s
+}
+
+// Neg sets s = -x and returns s
+func (s *Scalar) Neg(x *Scalar) *Scalar {
+	return s.Sub(&Scalar{}, x)
+}
+
+// Mul sets s = x * y and returns s
+func (s *Scalar) Mul(x, y *Scalar) *Scalar {
+	var r [7]uint64
+	montMul(&r, &x.l, &y.l)
+	montMul(&s.l, &r, &orderR2)
+	return s
+}
+
+// Inv sets s = 1/x and returns s. The inverse of 0 is 0.
+func (s *Scalar) Inv(x *Scalar) *Scalar {
+	// x^(l-2) computed in Montgomery domain. The exponent is public.
+	var e [7]uint64
+	var b uint64
+	e[0], b = bits.Sub64(order[0], 2, 0)
+	for i := 1; i < 7; i++ {
+		e[i], b = bits.Sub64(order[i], 0, b)
+	}
+
+	var xm, r [7]uint64
+	one := [7]uint64{1}
+	montMul(&xm, &x.l, &orderR2)
+	montMul(&r, &one, &orderR2)
+	for i := 446 - 1; i >= 0; i-- {
+		montMul(&r, &r, &r)
+		if (e[i/64]>>uint(i%64))&1 == 1 {
+			montMul(&r, &r, &xm)
+		}
+	}
+	montMul(&s.l, &r, &one)
+	return s
+}
+
+// Equal returns 1 if s == x and 0 otherwise
+func (s *Scalar) Equal(x *Scalar) int {
+	var d uint64
+	for i := range s.l {
+		d |= s.l[i] ^ x.l[i]
+	}
+	// 1 if d == 0
+	return int(1 ^ ((d | -d) >> 63))
+}
+
+// IsZero returns 1 if s == 0 and 0 otherwise
+func (s *Scalar) IsZero() int {
+	return s.Equal(&Scalar{})
+}
@@ -0,0 +1,88 @@
+package decaf448
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+var orderBig, _ = new(big.Int).SetString("181709681073901722637330951972001133588410340171829515070372549795146003961539585716195755291692375963310293709091662304773755859649779", 10)
+
+// Converts little-endian bytes to big.Int
+func leToBig(b []byte) *big.Int {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return new(big.Int).SetBytes(r)
+}
+
+func TestScalarOrder(t *testing.T) {
+	var l [56]byte
+	for i, v := range order {
+		for j := 0; j < 8; j++ {
+			l[8*i+j] = byte(v >> (8 * uint(j)))
+		}
+	}
+	if leToBig(l[:]).Cmp(orderBig) != 0 {
+		t.Fatal("wrong order")
+	}
+	// l*G is the identity
+	var s Scalar
+	var e Element
+	s.Sub(&s, new(Scalar).SetUint64(1)) // l-1
+	e.ScalarBaseMult(&s)
+	e.Add(&e, new(Element).Generator())
+	if e.Equal(new(Element).Identity()) != 1 {
+		t.Error("l*G is not the identity")
+	}
+}
+
+func TestScalarArith(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		x, y := randomScalar(t), randomScalar(t)
+		xb, yb := leToBig(x.Bytes()), leToBig(y.Bytes())
+
+		check := func(name string, got *Scalar, want *big.Int) {
+			want.Mod(want, orderBig)
+			if leToBig(got.Bytes()).Cmp(want) != 0 {
+				t.Fatalf("%s: mismatch", name)
+			}
+		}
+		check("add", new(Scalar).Add(x, y), new(big.Int).Add(xb, yb))
+		check("sub", new(Scalar).Sub(x, y), new(big.Int).Sub(xb, yb))
+		check("neg", new(Scalar).Neg(x), new(big.Int).Neg(xb))
+		check("mul", new(Scalar).Mul(x, y), new(big.Int).Mul(xb, yb))
+		check("inv", new(Scalar).Inv(x), new(big.Int).ModInverse(xb, orderBig))
+
+		var b [112]byte
+		rand.Read(b[:])
+		check("uniform", new(Scalar).SetUniformBytes(b[:]), leToBig(b[:]))
+	}
+	if new(Scalar).Inv(new(Scalar)).IsZero() != 1 {
+		t.Error("inverse of 0 is not 0")
+	}
+}
+
+func TestScalarEncoding(t *testing.T) {
+	var s Scalar
+	x := randomScalar(t)
+	if err := s.SetBytes(x.Bytes()); err != nil || s.Equal(x) != 1 {
+		t.Fatal("round trip failed")
+	}
+	// l is not canonical, l-1 is
+	var l [ScalarSize]byte
+	lm1 := new(Scalar).Sub(&Scalar{}, new(Scalar).SetUint64(1)).Bytes()
+	copy(l[:], lm1)
+	l[0]++
+	if s.SetBytes(l[:]) == nil {
+		t.Error("expected error for l")
+	}
+	if s.SetBytes(lm1) != nil || !bytes.Equal(s.Bytes(), lm1) {
+		t.Error("unexpected error for l-1")
+	}
+	if s.SetBytes(lm1[1:]) == nil {
+		t.Error("expected error for wrong size")
+	}
+}
@@ -0,0 +1,286 @@
+// Package ecdh provides a common API for Diffie-Hellman key agreement
+// over Montgomery curves, as described in RFC 7748. It supports X25519
+// (package ec/x25519) and X448 (package ec/x448).
+//
+// Keys are encoded as little-endian byte strings defined by RFC 7748.
+// Key agreement fails with ErrLowOrder when the peer's public key is a
+// point of small order, in which case the shared secret would be the
+// all-zero value.
+package ecdh
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+
+	"github.com/henrydcase/nobs/ec/x25519"
+	"github.com/henrydcase/nobs/ec/x448"
+	"github.com/henrydcase/nobs/utils/security"
+)
+
+var (
+	// Returned by ECDH when the peer's public key has small order
+	ErrLowOrder  = errors.New("ecdh: low order point")
+	errKeySize   = errors.New("ecdh: wrong size of the key")
+	errCurve     = errors.New("ecdh: keys belong to different curves")
+	errDestroyed = errors.New("ecdh: private key destroyed")
+)
+
+// Curve is a Montgomery curve used for key agreement. Only curves
+// returned by X25519 and X448 implement it.
+type Curve interface {
+	// Name of the function, same as Meta().Name
+	Name() string
+	// Security properties of the function
+	Meta() security.Metadata
+	// Size of private key, public key and shared secret in bytes
+	Size() int
+	// Computes out = scalar * point. Returns false if the result is
+	// the all-zero value.
+	scalarMult(out, scalar, point []byte) bool
+	// Computes out = scalar * G
+	scalarBaseMult(out, scalar []byte)
+}
+
+type curve25519 struct{}
+
+func (curve25519) Name() string            { return "X25519" }
+func (curve25519) Meta() security.Metadata { return x25519.Meta() }
+func (curve25519) Size() int               { return x25519.SharedSecretSize }
+
+func (curve25519) scalarMult(out, scalar, point []byte) bool {
+	var o, s, p [x25519.SharedSecretSize]byte
+	copy(s[:], scalar)
+	copy(p[:], point)
+	err := x25519.ScalarMult(&o, &s, &p)
+	copy(out, o[:])
+	s, o = [x25519.SharedSecretSize]byte{}, [x25519.SharedSecretSize]byte{}
+	return err == nil
+}
+
+func (curve25519) scalarBaseMult(out, scalar []byte) {
+	var o, s [x25519.SharedSecretSize]byte
+	copy(s[:], scalar)
+	x25519.ScalarBaseMult(&o, &s)
+	copy(out, o[:])
+	s = [x25519.SharedSecretSize]byte{}
+}
+
+type curve448 struct{}
+
+func (curve448) Name() string            { return "X448" }
+func (curve448) Meta() security.Metadata { return x448.Meta() }
+func (curve448) Size() int               { return x448.SharedSecretSize }
+
+func (curve448) scalarMult(out, scalar, point []byte) bool {
+	var o, s, p [x448.SharedSecretSize]byte
+	copy(s[:], scalar)
+	copy(p[:], point)
+	err := x448.ScalarMult(&o, &s, &p)
+	copy(out, o[:])
+	s, o = [x448.SharedSecretSize]byte{}, [x448.SharedSecretSize]byte{}
+	return err == nil
+}
+
+func (curve448) scalarBaseMult(out, scalar []byte) {
+	var o, s [x448.SharedSecretSize]byte
+	copy(s[:], scalar)
+	x448.ScalarBaseMult(&o, &s)
+	copy(out, o[:])
+	s = [x448.SharedSecretSize]byte{}
+}
+
+// X25519 returns the curve used by the X25519 function
+func X25519() Curve { return curve25519{} }
+
+// X448 returns the curve used by the X448 function
+func X448() Curve { return curve448{} }
+
+// Base type for public and private key
+type key struct {
+	curve Curve
+}
+
+// Accessor to the curve
+func (k *key) Curve() Curve {
+	return k.curve
+}
+
+// Size returns size of the key in bytes
+func (k *key) Size() int {
+	return k.curve.Size()
+}
+
+// Defines operations on public key
+type PublicKey struct {
+	key
+	point []byte
+}
+
+// Defines operations on private key
+type PrivateKey struct {
+	key
+	scalar    []byte
+	pub       *PublicKey
+	destroyed bool
+}
+
+// NewPublicKey initializes public key.
+// Usage of this function guarantees that the object is correctly initialized.
+func NewPublicKey(c Curve) *PublicKey {
+	return &PublicKey{key: key{c}, point: make([]byte, c.Size())}
+}
+
+// NewPrivateKey initializes private key.
+// Usage of this function guarantees that the object is correctly initialized.
+func NewPrivateKey(c Curve) *PrivateKey {
+	return &PrivateKey{key: key{c}, scalar: make([]byte, c.Size())}
+}
+
+// Import initializes public key with the u-coordinate of a point. Points
+// of small order are accepted here and rejected by ECDH.
+func (pub *PublicKey) Import(input []byte) error {
+	if len(input) != pub.Size() {
+		return errKeySize
+	}
+	copy(pub.point, input)
+	return nil
+}
+
+// Export returns encoding of the public key
+func (pub *PublicKey) Export() []byte {
+	return append([]byte(nil), pub.point...)
+}
+
+// Equal returns true if both keys are on the same curve and have the
+// same encoding
+func (pub *PublicKey) Equal(x *PublicKey) bool {
+	return pub.curve == x.curve && subtle.ConstantTimeCompare(pub.point, x.point) == 1
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (pub *PublicKey) MarshalBinary() ([]byte, error) {
+	return pub.Export(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The key must be
+// initialized with NewPublicKey first, so that the curve is known.
+func (pub *PublicKey) UnmarshalBinary(data []byte) error {
+	if pub.curve == nil {
+		return errCurve
+	}
+	return pub.Import(data)
+}
+
+// Generate generates private key using the rng, which must be a
+// cryptographically secure PRNG. Panics if the key was destroyed.
+func (prv *PrivateKey) Generate(rng io.Reader) error {
+	if prv.destroyed {
+		panic(errDestroyed)
+	}
+	if _, err := io.ReadFull(rng, prv.scalar); err != nil {
+		return err
+	}
+	prv.pub = nil
+	return nil
+}
+
+// Import initializes private key with a scalar. The scalar is clamped
+// during scalar multiplication, so any value is accepted. Panics if the
+// key was destroyed.
+func (prv *PrivateKey) Import(input []byte) error {
+	if prv.destroyed {
+		panic(errDestroyed)
+	}
+	if len(input) != prv.Size() {
+		return errKeySize
+	}
+	copy(prv.scalar, input)
+	prv.pub = nil
+	return nil
+}
+
+// Export returns encoding of the private key. Panics if the key was
+// destroyed.
+func (prv *PrivateKey) Export() []byte {
+	if prv.destroyed {
+		panic(errDestroyed)
+	}
+	return append([]byte(nil), prv.scalar...)
+}
+
+// Public returns public key corresponding to the private key. It is
+// computed on first use. Panics if the key was destroyed.
+func (prv *PrivateKey) Public() *PublicKey {
+	if prv.destroyed {
+		panic(errDestroyed)
+	}
+	if prv.pub == nil {
+		pub := NewPublicKey(prv.curve)
+		prv.curve.scalarBaseMult(pub.point, prv.scalar)
+		prv.pub = pub
+	}
+	return prv.pub
+}
+
+// Equal returns true if both keys are on the same curve and have the
+// same encoding
+func (prv *PrivateKey) Equal(x *PrivateKey) bool {
+	return prv.curve == x.curve && subtle.ConstantTimeCompare(prv.scalar, x.scalar) == 1
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (prv *PrivateKey) MarshalBinary() ([]byte, error) {
+	return prv.Export(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The key must be
+// initialized with NewPrivateKey first, so that the curve is known.
+func (prv *PrivateKey) UnmarshalBinary(data []byte) error {
+	if prv.curve == nil {
+		return errCurve
+	}
+	return prv.Import(data)
+}
+
+// ECDH computes shared secret with the peer's public key. Returns
+// ErrLowOrder if the result is the all-zero value. Panics if the key was
+// destroyed.
+func (prv *PrivateKey) ECDH(peer *PublicKey) ([]byte, error) {
+	if prv.destroyed {
+		panic(errDestroyed)
+	}
+	if prv.curve != peer.curve {
+		return nil, errCurve
+	}
+	ss := make([]byte, prv.Size())
+	if !prv.curve.scalarMult(ss, prv.scalar, peer.point) {
+		return nil, ErrLowOrder
+	}
+	return ss, nil
+}
+
+// Zeroize overwrites the private key with zeros. The key can be reused
+// by calling Generate or Import.
+func (prv *PrivateKey) Zeroize() {
+	for i := range prv.scalar {
+		prv.scalar[i] = 0
+	}
+	prv.pub = nil
+}
+
+// Destroy zeroizes the private key and marks it as destroyed. Any later
+// call to Generate, Import, Export, Public or ECDH panics.
+func (prv *PrivateKey) Destroy() {
+	prv.Zeroize()
+	prv.destroyed = true
+}
+
+// GenerateKey returns new private key on curve c, generated with rng
+func GenerateKey(c Curve, rng io.Reader) (*PrivateKey, error) {
+	prv := NewPrivateKey(c)
+	if err := prv.Generate(rng); err != nil {
+		return nil, err
+	}
+	return prv, nil
+}
@@ -0,0 +1,263 @@
+package ecdh
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+)
+
+// The 1,000,000 iterations of X448 take far longer than the default
+// 10 min test timeout. Set it to true and run with `go test -timeout 60m`
+// in order to run it.
+const reallyRunSlowTest = false
+
+var curves = []struct {
+	name  string
+	curve Curve
+}{
+	{"x25519", X25519()},
+	{"x448", X448()},
+}
+
+type katVector struct {
+	Input  string `json:"input"`
+	Output string `json:"output"`
+	Scalar string `json:"scalar"`
+}
+
+type timesVector struct {
+	Times uint32 `json:"times"`
+	Key   string `json:"key"`
+}
+
+func readJson(t *testing.T, fileName string, v interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("File %v can't be opened: %v", fileName, err)
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		t.Fatalf("File %v can't be parsed: %v", fileName, err)
+	}
+}
+
+func fromHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// Test vectors from sections 5.2 and 6 of RFC 7748
+func TestRFC7748Kat(t *testing.T) {
+	for _, c := range curves {
+		var kat []katVector
+		readJson(t, "testdata/"+c.name+"_kat_test.json", &kat)
+		for i, v := range kat {
+			prv, pub := NewPrivateKey(c.curve), NewPublicKey(c.curve)
+			if err := prv.Import(fromHex(t, v.Scalar)); err != nil {
+				t.Fatal(err)
+			}
+			if err := pub.Import(fromHex(t, v.Input)); err != nil {
+				t.Fatal(err)
+			}
+			ss, err := prv.ECDH(pub)
+			if err != nil {
+				t.Fatalf("%s[%d]: %v", c.name, i, err)
+			}
+			if !bytes.Equal(ss, fromHex(t, v.Output)) {
+				t.Errorf("%s[%d]: mismatch", c.name, i)
+			}
+		}
+	}
+}
+
+// Iterated test from section 5.2 of RFC 7748
+func TestRFC7748Times(t *testing.T) {
+	for _, c := range curves {
+		var vectors []timesVector
+		readJson(t, "testdata/"+c.name+"_times_test.json", &vectors)
+
+		// k and u start as the base point
+		k := make([]byte, c.curve.Size())
+		k[0] = 9
+		if c.curve == X448() {
+			k[0] = 5
+		}
+		u := append([]byte(nil), k...)
+		out := make([]byte, c.curve.Size())
+
+		var done uint32
+		for _, v := range vectors {
+			if v.Times > 1000 && (testing.Short() || (c.curve == X448() && !reallyRunSlowTest)) {
+				t.Logf("%s: skipping %d iterations", c.name, v.Times)
+				continue
+			}
+			for ; done < v.Times; done++ {
+				if !c.curve.scalarMult(out, k, u) {
+					t.Fatalf("%s[%d]: unexpected failure", c.name, done)
+				}
+				copy(u, k)
+				copy(k, out)
+			}
+			if !bytes.Equal(k, fromHex(t, v.Key)) {
+				t.Errorf("%s: mismatch after %d iterations", c.name, v.Times)
+			}
+		}
+	}
+}
+
+func TestKeyAgreement(t *testing.T) {
+	for _, c := range curves {
+		alice, err := GenerateKey(c.curve, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bob, err := GenerateKey(c.curve, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ss1, err := alice.ECDH(bob.Public())
+		if err != nil {
+			t.Fatal(err)
+		}
+		ss2, err := bob.ECDH(alice.Public())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(ss1, ss2) {
+			t.Errorf("%s: shared secrets differ", c.name)
+		}
+		if len(ss1) != c.curve.Size() {
+			t.Errorf("%s: wrong size of the shared secret", c.name)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	for _, c := range curves {
+		prv, err := GenerateKey(c.curve, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := prv.MarshalBinary()
+		prv2 := NewPrivateKey(c.curve)
+		if err = prv2.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+		if !prv.Equal(prv2) || !prv.Public().Equal(prv2.Public()) {
+			t.Errorf("%s: private key round trip failed", c.name)
+		}
+
+		b, _ = prv.Public().MarshalBinary()
+		pub := NewPublicKey(c.curve)
+		if err = pub.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+		if !pub.Equal(prv.Public()) {
+			t.Errorf("%s: public key round trip failed", c.name)
+		}
+
+		if pub.Import(b[1:]) == nil || prv2.Import(b[1:]) == nil {
+			t.Errorf("%s: expected error for wrong key size", c.name)
+		}
+		if new(PublicKey).UnmarshalBinary(b) == nil {
+			t.Errorf("%s: expected error for uninitialized key", c.name)
+		}
+	}
+}
+
+func TestLowOrder(t *testing.T) {
+	for _, c := range curves {
+		prv, err := GenerateKey(c.curve, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// u = 0 and u = 1 have small order on both curves
+		for _, u := range []byte{0, 1} {
+			pub := NewPublicKey(c.curve)
+			b := make([]byte, c.curve.Size())
+			b[0] = u
+			if err = pub.Import(b); err != nil {
+				t.Fatal(err)
+			}
+			if _, err = prv.ECDH(pub); !errors.Is(err, ErrLowOrder) {
+				t.Errorf("%s: expected ErrLowOrder for u=%d, got %v", c.name, u, err)
+			}
+		}
+	}
+}
+
+func TestCurveMismatch(t *testing.T) {
+	prv, err := GenerateKey(X25519(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = prv.ECDH(NewPublicKey(X448())); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestMeta(t *testing.T) {
+	for _, c := range curves {
+		if c.curve.Meta().Name != c.curve.Name() {
+			t.Errorf("%s: wrong name in metadata", c.name)
+		}
+	}
+}
+
+func TestDestroy(t *testing.T) {
+	for _, c := range curves {
+		prv, err := GenerateKey(c.curve, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		peer, err := GenerateKey(c.curve, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := prv.Export()
+		pub := prv.Public()
+
+		// Zeroized key can be imported again
+		scalar := prv.scalar
+		prv.Zeroize()
+		if !bytes.Equal(scalar, make([]byte, len(scalar))) || prv.pub != nil {
+			t.Errorf("%s: key not zeroized", c.name)
+		}
+		if err = prv.Import(exp); err != nil {
+			t.Fatal(err)
+		}
+		if !prv.Public().Equal(pub) {
+			t.Errorf("%s: wrong public key after Zeroize", c.name)
+		}
+
+		prv.Destroy()
+		if !bytes.Equal(scalar, make([]byte, len(scalar))) {
+			t.Errorf("%s: key not zeroized", c.name)
+		}
+		ops := map[string]func(){
+			"ECDH":     func() { prv.ECDH(peer.Public()) },
+			"Import":   func() { prv.Import(exp) },
+			"Export":   func() { prv.Export() },
+			"Generate": func() { prv.Generate(rand.Reader) },
+			"Public":   func() { prv.Public() },
+		}
+		for name, f := range ops {
+			func() {
+				defer func() {
+					if recover() != errDestroyed {
+						t.Errorf("%s: %s: expected panic with errDestroyed", c.name, name)
+					}
+				}()
+				f()
+			}()
+		}
+	}
+}
@@ -21,13 +21,18 @@
 // OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
 // THE SOFTWARE.
 
-package x448
+// Package fp448 provides arithmetic in the prime field GF(2^448-2^224-1)
+// used by X448 and Decaf448.
+package fp448
 
 // This should really use 64 bit limbs, but Go is fucking retarded and doesn't
 // have __(u)int128_t, so the 32 bit code it is, at a hefty performance
 // penalty.  Fuck my life, I'm going to have to bust out PeachPy to get this
 // to go fast aren't I.
 
+// Size of an encoded element in bytes
+const Size = 56
+
 const (
 	wBits     = 32
 	lBits     = (wBits * 7 / 8)
@@ -35,22 +40,19 @@ const (
 	lMask     = (1 << lBits) - 1
 )
 
-type limbUint uint32
-type limbSint int32
-
-type gf struct {
+type Elt struct {
 	limb [x448Limbs]uint32
 }
 
-var zero = gf{[x448Limbs]uint32{0}}
-var one = gf{[x448Limbs]uint32{1}}
-var p = gf{[x448Limbs]uint32{
+var Zero = Elt{[x448Limbs]uint32{0}}
+var One = Elt{[x448Limbs]uint32{1}}
+var p = Elt{[x448Limbs]uint32{
 	lMask, lMask, lMask, lMask, lMask, lMask, lMask, lMask,
 	lMask - 1, lMask, lMask, lMask, lMask, lMask, lMask, lMask,
 }}
 
-// cpy copies x = y.
-func (x *gf) cpy(y *gf) {
+// Cpy copies x = y.
+func (x *Elt) Cpy(y *Elt) {
 	// for i, v := range y.limb {
 	//	x.limb[i] = v
 	// }
@@ -58,10 +60,10 @@ func (x *gf) cpy(y *gf) {
 	copy(x.limb[:], y.limb[:])
 }
 
-// mul multiplies c = a * b. (PERF)
-func (c *gf) mul(a, b *gf) {
-	var aa gf
-	aa.cpy(a)
+// Mul multiplies c = a * b. (PERF)
+func (c *Elt) Mul(a, b *Elt) {
+	var aa Elt
+	aa.Cpy(a)
 
 	//
 	// This is *by far* the most CPU intesive routine in the code.
@@ -448,110 +450,110 @@ func (c *gf) mul(a, b *gf) {
 	c.limb[15] = (uint32)(accum15)
 }
 
-// sqr squares (c = x * x).  Just calls multiply. (PERF)
-func (c *gf) sqr(x *gf) {
-	c.mul(x, x)
+// Sqr squares (c = x * x).  Just calls multiply. (PERF)
+func (c *Elt) Sqr(x *Elt) {
+	c.Mul(x, x)
 }
 
-// isqrt inverse square roots (y = 1/sqrt(x)), using an addition chain.
-func (y *gf) isqrt(x *gf) {
-	var a, b, c gf
-	c.sqr(x)
+// Isqrt inverse square roots (y = 1/sqrt(x)), using an addition chain.
+func (y *Elt) Isqrt(x *Elt) {
+	var a, b, c Elt
+	c.Sqr(x)
 
 	// XXX/Yawning, could unroll, but this is called only once.
 
 	// STEP(b,x,1);
-	b.mul(x, &c)
-	c.cpy(&b)
+	b.Mul(x, &c)
+	c.Cpy(&b)
 	for i := 0; i < 1; i++ {
-		c.sqr(&c)
+		c.Sqr(&c)
 	}
 
 	// STEP(b,x,3);
-	b.mul(x, &c)
-	c.cpy(&b)
+	b.Mul(x, &c)
+	c.Cpy(&b)
 	for i := 0; i < 3; i++ {
-		c.sqr(&c)
+		c.Sqr(&c)
 	}
 
 	//STEP(a,b,3);
-	a.mul(&b, &c)
-	c.cpy(&a)
+	a.Mul(&b, &c)
+	c.Cpy(&a)
 	for i := 0; i < 3; i++ {
-		c.sqr(&c)
+		c.Sqr(&c)
 	}
 
 	// STEP(a,b,9);
-	a.mul(&b, &c)
-	c.cpy(&a)
+	a.Mul(&b, &c)
+	c.Cpy(&a)
 	for i := 0; i < 9; i++ {
-		c.sqr(&c)
+		c.Sqr(&c)
 	}
 
 	// STEP(b,a,1);
-	b.mul(&a, &c)
-	c.cpy(&b)
+	b.Mul(&a, &c)
+	c.Cpy(&b)
 	for i := 0; i < 1; i++ {
-		c.sqr(&c)
+		c.Sqr(&c)
 	}
 
 	// STEP(a,x,18);
-	a.mul(x, &c)
-	c.cpy(&a)
+	a.Mul(x, &c)
+	c.Cpy(&a)
 	for i := 0; i < 18; i++ {
-		c.sqr(&c)
+		c.Sqr(&c)
 	}
 
 	// STEP(a,b,37);
-	a.mul(&b, &c)
-	c.cpy(&a)
+	a.Mul(&b, &c)
+	c.Cpy(&a)
 	for i := 0; i < 37; i++ {
-		c.sqr(&c)
+		c.Sqr(&c)
 	}
 
 	// STEP(b,a,37);
-	b.mul(&a, &c)
-	c.cpy(&b)
+	b.Mul(&a, &c)
+	c.Cpy(&b)
 	for i := 0; i < 37; i++ {
-		c.sqr(&c)
+		c.Sqr(&c)
 	}
 
 	// STEP(b,a,111);
-	b.mul(&a, &c)
-	c.cpy(&b)
+	b.Mul(&a, &c)
+	c.Cpy(&b)
 	for i := 0; i < 111; i++ {
-		c.sqr(&c)
+		c.Sqr(&c)
 	}
 
 	// STEP(a,b,1);
-	a.mul(&b, &c)
-	c.cpy(&a)
+	a.Mul(&b, &c)
+	c.Cpy(&a)
 	for i := 0; i < 1; i++ {
-		c.sqr(&c)
+		c.Sqr(&c)
 	}
 
 	// STEP(b,x,223);
-	b.mul(x, &c)
-	c.cpy(&b)
+	b.Mul(x, &c)
+	c.Cpy(&b)
 	for i := 0; i < 223; i++ {
-		c.sqr(&c)
+		c.Sqr(&c)
 	}
 
-	y.mul(&a, &c)
+	y.Mul(&a, &c)
 }
 
-// inv inverses (y = 1/x).
-func (y *gf) inv(x *gf) {
-	var z, w gf
-	z.sqr(x)     // x^2
-	w.isqrt(&z)  // +- 1/sqrt(x^2) = +- 1/x
-	z.sqr(&w)    // 1/x^2
-	w.mul(x, &z) // 1/x
-	y.cpy(&w)
+// Inv inverses (y = 1/x).
+func (y *Elt) Inv(x *Elt) {
+	var z, w Elt
+	z.Sqr(x)     // x^2
+	w.Isqrt(&z)  // +- 1/sqrt(x^2) = +- 1/x
+	z.Sqr(&w)    // 1/x^2
+	w.Mul(x, &z) // 1/x
+	y.Cpy(&w)
 }
 
 // reduce weakly reduces mod p
-func (x *gf) reduce() {
+func (x *Elt) reduce() {
 	x.limb[x448Limbs/2] += x.limb[x448Limbs-1] >> lBits
 
 	// for j := uint(0); j < x448Limbs; j++ {
@@ -592,8 +594,8 @@ func (x *gf) reduce() {
 	x.limb[14] &= lMask
 }
 
-// add adds mod p. Conservatively always weak-reduces. (PERF)
-func (x *gf) add(y, z *gf) {
+// Add adds mod p. Conservatively always weak-reduces. (PERF)
+func (x *Elt) Add(y, z *Elt) {
 	// for i, yv := range y.limb {
 	//	x.limb[i] = yv + z.limb[i]
 	// }
@@ -617,8 +619,8 @@ func (x *gf) add(y, z *gf) {
 	x.reduce()
 }
 
-// sub subtracts mod p.  Conservatively always weak-reduces. (PERF)
-func (x *gf) sub(y, z *gf) {
+// Sub subtracts mod p.  Conservatively always weak-reduces. (PERF)
+func (x *Elt) Sub(y, z *Elt) {
 	// for i, yv := range y.limb {
 	//	x.limb[i] = yv - z.limb[i] + 2*p.limb[i]
 	// }
@@ -642,8 +644,8 @@ func (x *gf) sub(y, z *gf) {
 	x.reduce()
 }
 
-// condSwap swaps x and y in constant time.
-func (x *gf) condSwap(y *gf, swap limbUint) {
+// CondSwap swaps x and y in constant time.
+func (x *Elt) CondSwap(y *Elt, swap uint32) {
 	// for i, xv := range x.limb {
 	//	s := (xv ^ y.limb[i]) & (uint32)(swap) // Sort of dumb, oh well.
 	//	x.limb[i] ^= s
@@ -702,24 +704,32 @@ func (x *gf) condSwap(y *gf, swap limbUint) {
 	y.limb[15] ^= s
 }
 
-// mlw multiplies by a signed int.  NOT CONSTANT TIME wrt the sign of the int,
-// but that's ok because it's only ever called with w = -edwardsD.  Just uses
+// Cmov sets x = y if mask is all 1s, does nothing if mask is 0. Constant
+// time.
+func (x *Elt) Cmov(y *Elt, mask uint32) {
+	for i := range x.limb {
+		x.limb[i] ^= (x.limb[i] ^ y.limb[i]) & mask
+	}
+}
+
+// Mlw multiplies by a signed int.  NOT CONSTANT TIME wrt the sign of the int,
+// but that's ok because it's only ever called with public constants.  Just uses
 // a full multiply. (PERF)
-func (a *gf) mlw(b *gf, w int) {
+func (a *Elt) Mlw(b *Elt, w int) {
 	if w > 0 {
-		ww := gf{[x448Limbs]uint32{(uint32)(w)}}
-		a.mul(b, &ww)
+		ww := Elt{[x448Limbs]uint32{(uint32)(w)}}
+		a.Mul(b, &ww)
 	} else {
 		// This branch is *NEVER* taken with the current code.
 		panic("mul called with negative w")
-		ww := gf{[x448Limbs]uint32{(uint32)(-w)}}
-		a.mul(b, &ww)
-		a.sub(&zero, a)
+		ww := Elt{[x448Limbs]uint32{(uint32)(-w)}}
+		a.Mul(b, &ww)
+		a.Sub(&Zero, a)
 	}
 }
 
-// canon canonicalizes.
-func (a *gf) canon() {
+// Canon canonicalizes.
+func (a *Elt) Canon() {
 	a.reduce()
 
 	// Subtract p with borrow.
@@ -741,30 +751,30 @@ func (a *gf) canon() {
 	}
 }
 
-// deser deserializes into the limb representation.
-func (s *gf) deser(ser *[SharedSecretSize]byte) {
+// Deser deserializes into the limb representation.
+func (s *Elt) Deser(ser *[Size]byte) {
 	var buf uint64
 	bits := uint(0)
 	k := 0
 
 	for i, v := range ser {
 		buf |= (uint64)(v) << bits
-		for bits += 8; (bits >= lBits || i == SharedSecretSize-1) && k < x448Limbs; bits, buf = bits-lBits, buf>>lBits {
+		for bits += 8; (bits >= lBits || i == Size-1) && k < x448Limbs; bits, buf = bits-lBits, buf>>lBits {
 			s.limb[k] = (uint32)(buf & lMask)
 			k++
 		}
 	}
 }
 
-// ser serializes into byte representation.
-func (a *gf) ser(ser *[SharedSecretSize]byte) {
-	a.canon()
+// Ser serializes into byte representation.
+func (a *Elt) Ser(ser *[Size]byte) {
+	a.Canon()
 	k := 0
 	bits := uint(0)
 	var buf uint64
 	for i, v := range a.limb {
 		buf |= (uint64)(v) << bits
-		for bits += lBits; (bits >= 8 || i == x448Limbs-1) && k < SharedSecretSize; bits, buf = bits-8, buf>>8 {
+		for bits += lBits; (bits >= 8 || i == x448Limbs-1) && k < Size; bits, buf = bits-8, buf>>8 {
 			ser[k] = (byte)(buf)
 			k++
 		}
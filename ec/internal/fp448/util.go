@@ -0,0 +1,65 @@
+package fp448
+
+import "crypto/subtle"
+
+// Neg sets x = -y
+func (x *Elt) Neg(y *Elt) {
+	x.Sub(&Zero, y)
+}
+
+// Equal returns 1 if x == y and 0 otherwise. Constant time.
+func (x *Elt) Equal(y *Elt) int {
+	var a, b [Size]byte
+	t := *x
+	t.Ser(&a)
+	t = *y
+	t.Ser(&b)
+	return subtle.ConstantTimeCompare(a[:], b[:])
+}
+
+// IsNegative returns 1 if canonical representation of x is odd and 0
+// otherwise. Constant time.
+func (x *Elt) IsNegative() int {
+	var a [Size]byte
+	t := *x
+	t.Ser(&a)
+	return int(a[0] & 1)
+}
+
+// Abs sets x = -y if y is negative and x = y otherwise. Constant time.
+func (x *Elt) Abs(y *Elt) {
+	var n Elt
+	n.Neg(y)
+	x.Cpy(y)
+	x.Cmov(&n, -uint32(y.IsNegative()))
+}
+
+// SetBytes decodes little-endian, canonical encoding of an element.
+// Returns 1 if the encoding is canonical (smaller than p) and 0
+// otherwise, in which case x is still set to the reduced value.
+// Constant time.
+func (x *Elt) SetBytes(in *[Size]byte) int {
+	var out [Size]byte
+	x.Deser(in)
+	t := *x
+	t.Ser(&out)
+	return subtle.ConstantTimeCompare(in[:], out[:])
+}
+
+// SqrtRatio sets x = sqrt(u/v), the non-negative root, if u/v is a
+// square and returns 1. Otherwise it returns 0 and x is set to
+// sqrt(-u/v). In both cases the result is 0 if u or v is 0.
+// Constant time.
+func (x *Elt) SqrtRatio(u, v *Elt) int {
+	var r, c Elt
+	// r = u*(u*v)^((p-3)/4)
+	r.Mul(u, v)
+	r.Isqrt(&r)
+	r.Mul(&r, u)
+	// c = v*r^2 is u if u/v is a square and -u otherwise
+	c.Sqr(&r)
+	c.Mul(&c, v)
+	ok := c.Equal(u)
+	x.Abs(&r)
+	return ok
+}
@@ -0,0 +1,239 @@
+package x25519
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// Element of GF(2^255-19) in radix 2^51. Limbs are kept below 2^52
+// between operations, which leaves enough headroom for the 128-bit
+// accumulators used by mul.
+type gf [5]uint64
+
+const maskLow51Bits = (1 << 51) - 1
+
+var (
+	zero = gf{0, 0, 0, 0, 0}
+	one  = gf{1, 0, 0, 0, 0}
+)
+
+// 128-bit accumulator
+type uint128 struct {
+	lo, hi uint64
+}
+
+// Returns a*b
+func mul64(a, b uint64) uint128 {
+	hi, lo := bits.Mul64(a, b)
+	return uint128{lo, hi}
+}
+
+// Returns v + a*b
+func addMul64(v uint128, a, b uint64) uint128 {
+	hi, lo := bits.Mul64(a, b)
+	lo, c := bits.Add64(lo, v.lo, 0)
+	hi, _ = bits.Add64(hi, v.hi, c)
+	return uint128{lo, hi}
+}
+
+// Returns v >> 51
+func shiftRightBy51(v uint128) uint64 {
+	return (v.hi << (64 - 51)) | (v.lo >> 51)
+}
+
+// Brings all limbs below 2^51 + 2^13*19
+func (x *gf) carry() {
+	c0 := x[0] >> 51
+	c1 := x[1] >> 51
+	c2 := x[2] >> 51
+	c3 := x[3] >> 51
+	c4 := x[4] >> 51
+	x[0] = x[0]&maskLow51Bits + c4*19
+	x[1] = x[1]&maskLow51Bits + c0
+	x[2] = x[2]&maskLow51Bits + c1
+	x[3] = x[3]&maskLow51Bits + c2
+	x[4] = x[4]&maskLow51Bits + c3
+}
+
+// x = y + z
+func (x *gf) add(y, z *gf) {
+	for i := range x {
+		x[i] = y[i] + z[i]
+	}
+	x.carry()
+}
+
+// x = y - z. Adds 2*p first in order to avoid underflow.
+func (x *gf) sub(y, z *gf) {
+	x[0] = (y[0] + 0xFFFFFFFFFFFDA) - z[0]
+	x[1] = (y[1] + 0xFFFFFFFFFFFFE) - z[1]
+	x[2] = (y[2] + 0xFFFFFFFFFFFFE) - z[2]
+	x[3] = (y[3] + 0xFFFFFFFFFFFFE) - z[3]
+	x[4] = (y[4] + 0xFFFFFFFFFFFFE) - z[4]
+	x.carry()
+}
+
+// c = a * b
+func (c *gf) mul(a, b *gf) {
+	a0, a1, a2, a3, a4 := a[0], a[1], a[2], a[3], a[4]
+	b0, b1, b2, b3, b4 := b[0], b[1], b[2], b[3], b[4]
+
+	// Reduction by 2^255 = 19 is folded into the schoolbook multiplication
+	b1_19, b2_19, b3_19, b4_19 := b1*19, b2*19, b3*19, b4*19
+
+	r0 := mul64(a0, b0)
+	r0 = addMul64(r0, a1, b4_19)
+	r0 = addMul64(r0, a2, b3_19)
+	r0 = addMul64(r0, a3, b2_19)
+	r0 = addMul64(r0, a4, b1_19)
+
+	r1 := mul64(a0, b1)
+	r1 = addMul64(r1, a1, b0)
+	r1 = addMul64(r1, a2, b4_19)
+	r1 = addMul64(r1, a3, b3_19)
+	r1 = addMul64(r1, a4, b2_19)
+
+	r2 := mul64(a0, b2)
+	r2 = addMul64(r2, a1, b1)
+	r2 = addMul64(r2, a2, b0)
+	r2 = addMul64(r2, a3, b4_19)
+	r2 = addMul64(r2, a4, b3_19)
+
+	r3 := mul64(a0, b3)
+	r3 = addMul64(r3, a1, b2)
+	r3 = addMul64(r3, a2, b1)
+	r3 = addMul64(r3, a3, b0)
+	r3 = addMul64(r3, a4, b4_19)
+
+	r4 := mul64(a0, b4)
+	r4 = addMul64(r4, a1, b3)
+	r4 = addMul64(r4, a2, b2)
+	r4 = addMul64(r4, a3, b1)
+	r4 = addMul64(r4, a4, b0)
+
+	c0 := shiftRightBy51(r0)
+	c1 := shiftRightBy51(r1)
+	c2 := shiftRightBy51(r2)
+	c3 := shiftRightBy51(r3)
+	c4 := shiftRightBy51(r4)
+
+	c[0] = r0.lo&maskLow51Bits + c4*19
+	c[1] = r1.lo&maskLow51Bits + c0
+	c[2] = r2.lo&maskLow51Bits + c1
+	c[3] = r3.lo&maskLow51Bits + c2
+	c[4] = r4.lo&maskLow51Bits + c3
+	c.carry()
+}
+
+// c = a^2
+func (c *gf) sqr(a *gf) {
+	c.mul(a, a)
+}
+
+// c = a^(2^n)
+func (c *gf) sqrn(a *gf, n int) {
+	c.sqr(a)
+	for i := 1; i < n; i++ {
+		c.sqr(c)
+	}
+}
+
+// a = b * w, where w is a small constant
+func (a *gf) mlw(b *gf, w uint32) {
+	var lo, hi [5]uint64
+	for i := range b {
+		h, l := bits.Mul64(b[i], uint64(w))
+		lo[i] = l & maskLow51Bits
+		hi[i] = (h << (64 - 51)) | (l >> 51)
+	}
+	a[0] = lo[0] + 19*hi[4]
+	a[1] = lo[1] + hi[0]
+	a[2] = lo[2] + hi[1]
+	a[3] = lo[3] + hi[2]
+	a[4] = lo[4] + hi[3]
+	a.carry()
+}
+
+// y = x^(p-2) = x^-1. Uses the addition chain from curve25519-donna.
+func (y *gf) inv(x *gf) {
+	var z2, z9, z11, z2_5_0, z2_10_0, z2_20_0, z2_50_0, z2_100_0, t gf
+
+	z2.sqr(x)           // 2
+	t.sqrn(&z2, 2)      // 8
+	z9.mul(&t, x)       // 9
+	z11.mul(&z9, &z2)   // 11
+	t.sqr(&z11)         // 22
+	z2_5_0.mul(&t, &z9) // 2^5 - 1
+
+	t.sqrn(&z2_5_0, 5)
+	z2_10_0.mul(&t, &z2_5_0) // 2^10 - 1
+	t.sqrn(&z2_10_0, 10)
+	z2_20_0.mul(&t, &z2_10_0) // 2^20 - 1
+	t.sqrn(&z2_20_0, 20)
+	t.mul(&t, &z2_20_0) // 2^40 - 1
+	t.sqrn(&t, 10)
+	z2_50_0.mul(&t, &z2_10_0) // 2^50 - 1
+	t.sqrn(&z2_50_0, 50)
+	z2_100_0.mul(&t, &z2_50_0) // 2^100 - 1
+	t.sqrn(&z2_100_0, 100)
+	t.mul(&t, &z2_100_0) // 2^200 - 1
+	t.sqrn(&t, 50)
+	t.mul(&t, &z2_50_0) // 2^250 - 1
+	t.sqrn(&t, 5)
+	y.mul(&t, &z11) // 2^255 - 21
+}
+
+// Swaps x and y if swap is all 1s, does nothing if swap is 0. Constant time.
+func (x *gf) condSwap(y *gf, swap uint64) {
+	for i := range x {
+		t := swap & (x[i] ^ y[i])
+		x[i] ^= t
+		y[i] ^= t
+	}
+}
+
+// Decodes little-endian u-coordinate. As required by RFC 7748, the most
+// significant bit is ignored and non-canonical values are accepted.
+func (x *gf) deser(in *[SharedSecretSize]byte) {
+	x[0] = binary.LittleEndian.Uint64(in[0:8]) & maskLow51Bits
+	x[1] = (binary.LittleEndian.Uint64(in[6:14]) >> 3) & maskLow51Bits
+	x[2] = (binary.LittleEndian.Uint64(in[12:20]) >> 6) & maskLow51Bits
+	x[3] = (binary.LittleEndian.Uint64(in[19:27]) >> 1) & maskLow51Bits
+	x[4] = (binary.LittleEndian.Uint64(in[24:32]) >> 12) & maskLow51Bits
+}
+
+// Encodes x in its canonical, little-endian form
+func (x *gf) ser(out *[SharedSecretSize]byte) {
+	t := *x
+	t.carry()
+
+	// q is 1 if t >= p, 0 otherwise
+	q := (t[0] + 19) >> 51
+	q = (t[1] + q) >> 51
+	q = (t[2] + q) >> 51
+	q = (t[3] + q) >> 51
+	q = (t[4] + q) >> 51
+
+	// t - q*p = t + 19*q - q*2^255
+	t[0] += 19 * q
+	t[1] += t[0] >> 51
+	t[0] &= maskLow51Bits
+	t[2] += t[1] >> 51
+	t[1] &= maskLow51Bits
+	t[3] += t[2] >> 51
+	t[2] &= maskLow51Bits
+	t[4] += t[3] >> 51
+	t[3] &= maskLow51Bits
+	t[4] &= maskLow51Bits
+
+	for i := range out {
+		out[i] = 0
+	}
+	for i, l := range t {
+		off := i * 51
+		l <<= uint(off % 8)
+		for j := 0; j < 8 && off/8+j < len(out); j++ {
+			out[off/8+j] |= byte(l >> (8 * uint(j)))
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Package x25519 provides an implementation of scalar multiplication on
+// the elliptic curve known as curve25519.
+//
+// See https://tools.ietf.org/html/rfc7748
+package x25519
+
+import (
+	"errors"
+
+	"github.com/henrydcase/nobs/utils/security"
+)
+
+// ErrLowOrder is returned when the result of scalar multiplication is the
+// all-zero value. This happens when the input point has small order, in
+// which case the shared secret doesn't depend on the private key.
+var ErrLowOrder = errors.New("x25519: low order point")
+
+const (
+	SharedSecretSize = 32
+	// (A-2)/4, where A = 486662 is a coefficient of curve25519
+	a24 = 121665
+)
+
+var basePoint = [SharedSecretSize]byte{9}
+
+// Meta returns security properties of X25519. NIST security categories
+// apply to post-quantum algorithms only, no category is claimed.
+func Meta() security.Metadata {
+	return security.Metadata{
+		Name:      "X25519",
+		Status:    security.Standardized,
+		Reference: "RFC 7748",
+	}
+}
+
+// ScalarMult computes out = scalar * base with the Montgomery ladder. The
+// scalar is clamped as described in RFC 7748. Returns ErrLowOrder if the
+// result is the all-zero value, as required by section 6.2 of RFC 7748.
+// The out is written in both cases.
+func ScalarMult(out, scalar, base *[SharedSecretSize]byte) error {
+	var x1, x2, z2, x3, z3, t1, t2 gf
+	x1.deser(base)
+	x2 = one
+	z2 = zero
+	x3 = x1
+	z3 = one
+
+	var swap uint64
+
+	for t := 255 - 1; t >= 0; t-- {
+		sb := scalar[t/8]
+
+		// Scalar conditioning.
+		if t/8 == 0 {
+			sb &= 0xF8
+		} else if t/8 == SharedSecretSize-1 {
+			sb = (sb & 0x7F) | 0x40
+		}
+
+		kT := uint64((sb >> (uint(t) % 8)) & 1)
+		kT = -kT // Set to all 0s or all 1s
+
+		swap ^= kT
+		x2.condSwap(&x3, swap)
+		z2.condSwap(&z3, swap)
+		swap = kT
+
+		t1.add(&x2, &z2) // A = x2 + z2
+		t2.sub(&x2, &z2) // B = x2 - z2
+		z2.sub(&x3, &z3) // D = x3 - z3
+		x2.mul(&t1, &z2) // DA
+		z2.add(&z3, &x3) // C = x3 + z3
+		x3.mul(&t2, &z2) // CB
+		z3.sub(&x2, &x3) // DA-CB
+		z2.sqr(&z3)      // (DA-CB)^2
+		z3.mul(&x1, &z2) // z3 = x1(DA-CB)^2
+		z2.add(&x2, &x3) // (DA+CB)
+		x3.sqr(&z2)      // x3 = (DA+CB)^2
+
+		z2.sqr(&t1)      // AA = A^2
+		t1.sqr(&t2)      // BB = B^2
+		x2.mul(&z2, &t1) // x2 = AA*BB
+		t2.sub(&z2, &t1) // E = AA-BB
+
+		t1.mlw(&t2, a24) // a24*E
+		t1.add(&t1, &z2) // AA + a24*E
+		z2.mul(&t2, &t1) // z2 = E(AA+a24*E)
+	}
+
+	// Finish
+	x2.condSwap(&x3, swap)
+	z2.condSwap(&z3, swap)
+	z2.inv(&z2)
+	x1.mul(&x2, &z2)
+	x1.ser(out)
+
+	// Ladder state depends on bits of the scalar
+	x1, x2, z2, x3, z3, t1, t2 = zero, zero, zero, zero, zero, zero, zero
+
+	// Check, without leaking extra information about the value of the
+	// result, whether it is the all-zero value.
+	var nz int32
+	for _, v := range out {
+		nz |= int32(v)
+	}
+	nz = (nz - 1) >> 8 // 0 = succ, -1 = fail
+
+	if nz != 0 {
+		return ErrLowOrder
+	}
+	return nil
+}
+
+// ScalarBaseMult computes out = scalar * G, where G is the standard base
+// point with u = 9. Clamped scalars are never multiples of the order of G,
+// so it never fails.
+func ScalarBaseMult(out, scalar *[SharedSecretSize]byte) error {
+	return ScalarMult(out, scalar, &basePoint)
+}
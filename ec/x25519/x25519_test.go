@@ -0,0 +1,141 @@
+package x25519
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+)
+
+func fromHex(t testing.TB, s string) (out [SharedSecretSize]byte) {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != SharedSecretSize {
+		t.Fatalf("malformed test vector %q", s)
+	}
+	copy(out[:], b)
+	return
+}
+
+func TestX25519(t *testing.T) {
+	// Test vectors from section 5.2 of RFC 7748. The second one has
+	// the most significant bit of u-coordinate set, which must be ignored.
+	vectors := []struct {
+		scalar, base, answer string
+	}{
+		{
+			"a546e36bf0527c9d3b16154b82465edd62144c0ac1fc5a18506a2244ba449ac4",
+			"e6db6867583030db3594c1a424b15f7c726624ec26b3353b10a903a6d0ab1c4c",
+			"c3da55379de9c6908e94ea4df28d084f32eccf03491c71f754b4075577a28552",
+		},
+		{
+			"4b66e9d4d1b4673c5ad22691957d6af5c11b6421e0ea01d42ca4169e7918ba0d",
+			"e5210f12786811d3f4b7959d0538ae2c31dbe7106fc03c3efc4cd549c715a493",
+			"95cbde9476e8907d7aade45cb4b873f88b595a68799fa152e6f8f7647aac7957",
+		},
+	}
+
+	var out [SharedSecretSize]byte
+	for i, vec := range vectors {
+		scalar, base := fromHex(t, vec.scalar), fromHex(t, vec.base)
+		answer := fromHex(t, vec.answer)
+		if err := ScalarMult(&out, &scalar, &base); err != nil {
+			t.Errorf("KAT[%d]: ScalarMult failed", i)
+		}
+		if !bytes.Equal(out[:], answer[:]) {
+			t.Errorf("KAT[%d]: Mismatch", i)
+		}
+	}
+}
+
+func TestCurve25519(t *testing.T) {
+	// Diffie-Hellman test vector from section 6.1 of RFC 7748
+	alicePriv := fromHex(t, "77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a")
+	alicePub := fromHex(t, "8520f0098930a754748b7ddcb43ef75a0dbf3a0d26381af4eba4a98eaa9b4e6a")
+	bobPriv := fromHex(t, "5dab087e624a8a4b79e17f8b83800ee66f3bb1292618b6fd1c2f8b27ff88e0eb")
+	bobPub := fromHex(t, "de9edb7d7b7dc1b4d35b61c2ece435373f8343c85b78674dadfc7e146f882b4f")
+	aliceBob := fromHex(t, "4a5d9d5ba4ce2de1728e3bf480350f25e07e21c947d19e3376f09b3c1e161742")
+
+	var out [SharedSecretSize]byte
+	if ScalarBaseMult(&out, &alicePriv) != nil || !bytes.Equal(out[:], alicePub[:]) {
+		t.Error("Alice: ScalarBaseMult Mismatch")
+	}
+	if ScalarBaseMult(&out, &bobPriv) != nil || !bytes.Equal(out[:], bobPub[:]) {
+		t.Error("Bob: ScalarBaseMult Mismatch")
+	}
+	if ScalarMult(&out, &bobPriv, &alicePub) != nil || !bytes.Equal(out[:], aliceBob[:]) {
+		t.Error("Bob: ScalarMult Mismatch")
+	}
+	if ScalarMult(&out, &alicePriv, &bobPub) != nil || !bytes.Equal(out[:], aliceBob[:]) {
+		t.Error("Alice: ScalarMult Mismatch")
+	}
+}
+
+func TestLowOrder(t *testing.T) {
+	var scalar, out [SharedSecretSize]byte
+	if _, err := rand.Read(scalar[:]); err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range []string{
+		// 0, 1 and p-1
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+		// Points of order 8
+		"e0eb7a7c3b41b8ae1656e3faf19fc46ada098deb9c32b1fd866205165f49b800",
+		"5f9c95bca3508c24b1d0b1559c83ef5b04445cc4581c8e86d8224eddd09f1157",
+		// p and p+1, non-canonical encodings of 0 and 1
+		"edffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+		"eeffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+	} {
+		base := fromHex(t, u)
+		if ScalarMult(&out, &scalar, &base) != ErrLowOrder {
+			t.Errorf("%s: expected failure", u)
+		}
+	}
+}
+
+// Compares results with the implementation from standard library
+func TestCompareStdlib(t *testing.T) {
+	var scalar, base, out [SharedSecretSize]byte
+	for i := 0; i < 100; i++ {
+		if _, err := rand.Read(scalar[:]); err != nil {
+			t.Fatal(err)
+		}
+		// Use public key as a base, so that stdlib accepts it
+		ScalarBaseMult(&base, &scalar)
+		if _, err := rand.Read(scalar[:]); err != nil {
+			t.Fatal(err)
+		}
+		ScalarMult(&out, &scalar, &base)
+
+		prv, err := ecdh.X25519().NewPrivateKey(scalar[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := ecdh.X25519().NewPublicKey(base[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		ss, err := prv.ECDH(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(ss, out[:]) {
+			t.Fatalf("mismatch for scalar %x and base %x", scalar, base)
+		}
+	}
+}
+
+func BenchmarkECDH(b *testing.B) {
+	var sa, sb, pa, pb, ab, ba [SharedSecretSize]byte
+	rand.Read(sa[:])
+	rand.Read(sb[:])
+	ScalarBaseMult(&pa, &sa)
+	ScalarBaseMult(&pb, &sb)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ScalarMult(&ab, &sa, &pb)
+		ScalarMult(&ba, &sb, &pa)
+	}
+}
@@ -0,0 +1,51 @@
+//go:build ctcheck
+// +build ctcheck
+
+package x448
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/henrydcase/nobs/utils/ctcheck"
+)
+
+// Number of measurements of scalar multiplication
+const ctMeasurements = 20000
+
+// Returns fixed scalar for class 0 and random one for class 1
+func ctScalar(t *testing.T, fixed *[56]byte, class int) *[56]byte {
+	k := *fixed
+	if class == 1 {
+		if _, err := rand.Read(k[:]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctcheck.Secret(k[:])
+	return &k
+}
+
+func TestScalarMultCT(t *testing.T) {
+	var fixed, base [56]byte
+	rand.Read(fixed[:])
+	rand.Read(base[:])
+	ctcheck.Check(t, "ScalarMult", ctMeasurements, func(class int) func() {
+		k := ctScalar(t, &fixed, class)
+		return func() {
+			var out [56]byte
+			ScalarMult(&out, k, &base)
+		}
+	})
+}
+
+func TestScalarBaseMultCT(t *testing.T) {
+	var fixed [56]byte
+	rand.Read(fixed[:])
+	ctcheck.Check(t, "ScalarBaseMult", ctMeasurements, func(class int) func() {
+		k := ctScalar(t, &fixed, class)
+		return func() {
+			var out [56]byte
+			ScalarBaseMult(&out, k)
+		}
+	})
+}
@@ -0,0 +1,265 @@
+package x448
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"sync"
+
+	"github.com/henrydcase/nobs/ec/internal/fp448"
+)
+
+// Fixed-base scalar multiplication. Curve448 is birationally equivalent to
+// the twisted Edwards curve
+//
+//	edA*x^2 + y^2 = 1 + edD*x^2*y^2, where edA = A-2 and edD = A+2
+//
+// by (x, y) = (u/v, (u+1)/(u-1)) and u = (y+1)/(y-1). The edA is a square
+// and edD is not, hence the addition law below is complete: it works for
+// any pair of points, including doubling and the identity. This lets
+// ScalarBaseMult use precomputed multiples of the base point instead of
+// the ladder.
+
+const (
+	edA = 156324
+	edD = 156328
+
+	// Number of 4-bit windows of a scalar reduced modulo q
+	baseWindows = 112
+	// Number of multiples of the base point stored per window
+	baseWindowSize = 8
+)
+
+// Edwards coordinates of the base point u = 5
+var (
+	baseX = [SharedSecretSize]byte{
+		0xfe, 0xd4, 0x4c, 0x61, 0xf8, 0x67, 0x09, 0x24,
+		0xb5, 0x03, 0x44, 0x4d, 0xa8, 0x34, 0x54, 0xf9,
+		0xe9, 0x5e, 0x22, 0x0b, 0xe6, 0x28, 0x12, 0xa2,
+		0x83, 0xc3, 0x4c, 0x74, 0xd3, 0x9e, 0x42, 0xe3,
+		0x5c, 0x91, 0xd7, 0xfa, 0x1d, 0x86, 0xc2, 0x69,
+		0x0b, 0x0a, 0x00, 0x72, 0xd4, 0x8d, 0xeb, 0x09,
+		0xe5, 0x73, 0x68, 0xd9, 0xb4, 0xdc, 0xed, 0x30,
+	}
+	baseY = [SharedSecretSize]byte{
+		0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x80, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f,
+	}
+)
+
+// Order of the base point, q, and its multiples 2q and 4q as 64-bit
+// little-endian limbs
+var orderMultiples = [3][7]uint64{
+	{
+		0x8de30a4aad6113cc, 0x85b309ca37163d54, 0x113b6d26bb58da40, 0xfffffffdf3288fa7,
+		0xffffffffffffffff, 0xffffffffffffffff, 0xffffffffffffffff,
+	},
+	{
+		0x46f1852556b089e6, 0x42d984e51b8b1eaa, 0x889db6935dac6d20, 0xfffffffef99447d3,
+		0xffffffffffffffff, 0xffffffffffffffff, 0x7fffffffffffffff,
+	},
+	{
+		0x2378c292ab5844f3, 0x216cc2728dc58f55, 0xc44edb49aed63690, 0xffffffff7cca23e9,
+		0xffffffffffffffff, 0xffffffffffffffff, 0x3fffffffffffffff,
+	},
+}
+
+// Point in extended coordinates: x = X/Z, y = Y/Z and x*y = T/Z
+type edPoint struct {
+	x, y, z, t fp448.Elt
+}
+
+// Affine point with precomputed edD*x*y, as stored in the table
+type edAffine struct {
+	x, y, dt fp448.Elt
+}
+
+// baseTable[i][j] = (j+1) * 16^i * G
+var baseTable [baseWindows][baseWindowSize]edAffine
+var baseTableOnce sync.Once
+
+// Sets p to the identity (0, 1)
+func (p *edPoint) setIdentity() {
+	p.x.Cpy(&fp448.Zero)
+	p.y.Cpy(&fp448.One)
+	p.z.Cpy(&fp448.One)
+	p.t.Cpy(&fp448.Zero)
+}
+
+// Computes r = p + q. Complete, works also for p == q.
+func (r *edPoint) add(p, q *edPoint) {
+	var a, b, c, d, e, f, g, h fp448.Elt
+	a.Mul(&p.x, &q.x)
+	b.Mul(&p.y, &q.y)
+	c.Mul(&p.t, &q.t)
+	c.Mlw(&c, edD)
+	d.Mul(&p.z, &q.z)
+	e.Add(&p.x, &p.y)
+	f.Add(&q.x, &q.y)
+	e.Mul(&e, &f)
+	e.Sub(&e, &a)
+	e.Sub(&e, &b) // E = x1*y2 + y1*x2
+	f.Sub(&d, &c)
+	g.Add(&d, &c)
+	a.Mlw(&a, edA)
+	h.Sub(&b, &a) // H = y1*y2 - edA*x1*x2
+	r.x.Mul(&e, &f)
+	r.y.Mul(&g, &h)
+	r.t.Mul(&e, &h)
+	r.z.Mul(&f, &g)
+}
+
+// Computes r = p + q, where q is affine. Complete.
+func (r *edPoint) addAffine(p *edPoint, q *edAffine) {
+	var a, b, c, e, f, g, h fp448.Elt
+	a.Mul(&p.x, &q.x)
+	b.Mul(&p.y, &q.y)
+	c.Mul(&p.t, &q.dt)
+	e.Add(&p.x, &p.y)
+	f.Add(&q.x, &q.y)
+	e.Mul(&e, &f)
+	e.Sub(&e, &a)
+	e.Sub(&e, &b)
+	f.Sub(&p.z, &c)
+	g.Add(&p.z, &c)
+	a.Mlw(&a, edA)
+	h.Sub(&b, &a)
+	r.x.Mul(&e, &f)
+	r.y.Mul(&g, &h)
+	r.t.Mul(&e, &h)
+	r.z.Mul(&f, &g)
+}
+
+// Computes baseTable. Points are converted to affine coordinates with
+// a single inversion (Montgomery's trick).
+func initBaseTable() {
+	var g, p edPoint
+	var pts [baseWindows * baseWindowSize]edPoint
+
+	g.x.Deser(&baseX)
+	g.y.Deser(&baseY)
+	g.z.Cpy(&fp448.One)
+	g.t.Mul(&g.x, &g.y)
+
+	for i := 0; i < baseWindows; i++ {
+		p = g
+		for j := 0; j < baseWindowSize; j++ {
+			pts[i*baseWindowSize+j] = p
+			p.add(&p, &g)
+		}
+		// p = 9*g here, next window starts from 16*g
+		g.add(&pts[i*baseWindowSize+baseWindowSize-1], &pts[i*baseWindowSize+baseWindowSize-1])
+	}
+
+	// prod[k] = z_0 * ... * z_(k-1)
+	var prod [len(pts) + 1]fp448.Elt
+	var inv fp448.Elt
+	prod[0].Cpy(&fp448.One)
+	for k := range pts {
+		prod[k+1].Mul(&prod[k], &pts[k].z)
+	}
+	inv.Inv(&prod[len(pts)])
+	for k := len(pts) - 1; k >= 0; k-- {
+		var zInv fp448.Elt
+		zInv.Mul(&inv, &prod[k])
+		inv.Mul(&inv, &pts[k].z)
+
+		e := &baseTable[k/baseWindowSize][k%baseWindowSize]
+		e.x.Mul(&pts[k].x, &zInv)
+		e.y.Mul(&pts[k].y, &zInv)
+		e.dt.Mul(&e.x, &e.y)
+		e.dt.Mlw(&e.dt, edD)
+	}
+}
+
+// Reduces clamped scalar modulo q and returns it as 64-bit limbs.
+// Constant time.
+func reduceScalar(scalar *[SharedSecretSize]byte) (k [7]uint64) {
+	for i := range k {
+		k[i] = binary.LittleEndian.Uint64(scalar[8*i:])
+	}
+	// Clamping
+	k[0] &^= 3
+	k[6] |= 1 << 63
+
+	// k < 2^448 < 8q. Subtract 4q, 2q and q, each time only if the
+	// result is not negative.
+	for _, m := range orderMultiples {
+		var t [7]uint64
+		var borrow uint64
+		for i := range k {
+			t[i], borrow = bits.Sub64(k[i], m[i], borrow)
+		}
+		mask := borrow - 1 // all 1s if k >= m
+		for i := range k {
+			k[i] ^= (k[i] ^ t[i]) & mask
+		}
+	}
+	return
+}
+
+// Computes out = scalar * G using the precomputed table. The scalar is
+// reduced modulo q and recoded into signed 4-bit digits in [-8, 8), so
+// that the table holds only 8 multiples per window. Constant time.
+func scalarBaseMultEdwards(out, scalar *[SharedSecretSize]byte) {
+	baseTableOnce.Do(initBaseTable)
+
+	k := reduceScalar(scalar)
+
+	var digits [baseWindows]int32
+	var carry int32
+	for i := range digits {
+		v := int32(k[i/16]>>(4*uint(i%16))&0xF) + carry
+		carry = (v + 8) >> 4
+		digits[i] = v - carry<<4
+	}
+
+	var r edPoint
+	var q edAffine
+	var negX, negDt fp448.Elt
+	r.setIdentity()
+	for i, d := range digits {
+		sign := d >> 31
+		mag := uint32((d ^ sign) - sign)
+
+		// Constant time lookup, identity if mag is 0
+		q.x.Cpy(&fp448.Zero)
+		q.y.Cpy(&fp448.One)
+		q.dt.Cpy(&fp448.Zero)
+		for j := range baseTable[i] {
+			// All 1s if mag == j+1
+			mask := uint32((uint64(mag^uint32(j+1)) - 1) >> 63)
+			mask = -mask
+			q.x.Cmov(&baseTable[i][j].x, mask)
+			q.y.Cmov(&baseTable[i][j].y, mask)
+			q.dt.Cmov(&baseTable[i][j].dt, mask)
+		}
+
+		// -(x, y) = (-x, y)
+		negX.Sub(&fp448.Zero, &q.x)
+		negDt.Sub(&fp448.Zero, &q.dt)
+		q.x.Cmov(&negX, uint32(sign))
+		q.dt.Cmov(&negDt, uint32(sign))
+
+		r.addAffine(&r, &q)
+	}
+
+	// u = (y+1)/(y-1) = (Y+Z)/(Y-Z). Identity maps to u = 0.
+	var n, d fp448.Elt
+	n.Add(&r.y, &r.z)
+	d.Sub(&r.y, &r.z)
+	d.Inv(&d)
+	n.Mul(&n, &d)
+	n.Ser(out)
+
+	// Scalar, its digits and the selected multiples are secret
+	k, digits = [7]uint64{}, [baseWindows]int32{}
+	r, q = edPoint{}, edAffine{}
+	for _, e := range []*fp448.Elt{&negX, &negDt, &n, &d} {
+		e.Cpy(&fp448.Zero)
+	}
+}
@@ -27,6 +27,19 @@
 // See https://tools.ietf.org/html/draft-irtf-cfrg-curves-11
 package x448
 
+import (
+	"errors"
+
+	"github.com/henrydcase/nobs/ec/internal/fp448"
+	"github.com/henrydcase/nobs/utils/security"
+)
+
+// ErrLowOrder is returned when the result of scalar multiplication is the
+// all-zero value. This happens when the input point has small order
+// (u = 0, 1 or p-1, or one of their non-canonical encodings p and p+1),
+// in which case the shared secret doesn't depend on the private key.
+var ErrLowOrder = errors.New("x448: low order point")
+
 const (
 	SharedSecretSize = 56
 	edwardsD         = -39081
@@ -39,15 +52,30 @@ var basePoint = [56]byte{
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 }
 
-func ScalarMult(out, scalar, base *[56]byte) int {
-	var x1, x2, z2, x3, z3, t1, t2 gf
-	x1.deser(base)
-	x2.cpy(&one)
-	z2.cpy(&zero)
-	x3.cpy(&x1)
-	z3.cpy(&one)
+// Meta returns security properties of X448. NIST security categories
+// apply to post-quantum algorithms only, no category is claimed.
+func Meta() security.Metadata {
+	return security.Metadata{
+		Name:      "X448",
+		Status:    security.Standardized,
+		Reference: "RFC 7748",
+	}
+}
 
-	var swap limbUint
+// ScalarMult computes out = scalar * base with the Montgomery ladder. The
+// scalar is clamped as described in RFC 7748. Returns ErrLowOrder if the
+// result is the all-zero value, as required by section 6.2 of RFC 7748.
+// The check doesn't leak anything about the result other than the error.
+// The out is written in both cases.
+func ScalarMult(out, scalar, base *[56]byte) error {
+	var x1, x2, z2, x3, z3, t1, t2 fp448.Elt
+	x1.Deser(base)
+	x2.Cpy(&fp448.One)
+	z2.Cpy(&fp448.Zero)
+	x3.Cpy(&x1)
+	z3.Cpy(&fp448.One)
+
+	var swap uint32
 
 	for t := int(448 - 1); t >= 0; t-- {
 		sb := scalar[t/8]
@@ -59,56 +87,73 @@ func ScalarMult(out, scalar, base *[56]byte) int {
 			sb |= 0x80
 		}
 
-		kT := (limbUint)((sb >> ((uint)(t) % 8)) & 1)
+		kT := (uint32)((sb >> ((uint)(t) % 8)) & 1)
 		kT = -kT // Set to all 0s or all 1s
 
 		swap ^= kT
-		x2.condSwap(&x3, swap)
-		z2.condSwap(&z3, swap)
+		x2.CondSwap(&x3, swap)
+		z2.CondSwap(&z3, swap)
 		swap = kT
 
-		t1.add(&x2, &z2) // A = x2 + z2
-		t2.sub(&x2, &z2) // B = x2 - z2
-		z2.sub(&x3, &z3) // D = x3 - z3
-		x2.mul(&t1, &z2) // DA
-		z2.add(&z3, &x3) // C = x3 + z3
-		x3.mul(&t2, &z2) // CB
-		z3.sub(&x2, &x3) // DA-CB
-		z2.sqr(&z3)      // (DA-CB)^2
-		z3.mul(&x1, &z2) // z3 = x1(DA-CB)^2
-		z2.add(&x2, &x3) // (DA+CB)
-		x3.sqr(&z2)      // x3 = (DA+CB)^2
-
-		z2.sqr(&t1)      // AA = A^2
-		t1.sqr(&t2)      // BB = B^2
-		x2.mul(&z2, &t1) // x2 = AA*BB
-		t2.sub(&z2, &t1) // E = AA-BB
-
-		t1.mlw(&t2, -edwardsD) // E*-d = a24*E
-		t1.add(&t1, &z2)       // AA + a24*E
-		z2.mul(&t2, &t1)       // z2 = E(AA+a24*E)
+		t1.Add(&x2, &z2) // A = x2 + z2
+		t2.Sub(&x2, &z2) // B = x2 - z2
+		z2.Sub(&x3, &z3) // D = x3 - z3
+		x2.Mul(&t1, &z2) // DA
+		z2.Add(&z3, &x3) // C = x3 + z3
+		x3.Mul(&t2, &z2) // CB
+		z3.Sub(&x2, &x3) // DA-CB
+		z2.Sqr(&z3)      // (DA-CB)^2
+		z3.Mul(&x1, &z2) // z3 = x1(DA-CB)^2
+		z2.Add(&x2, &x3) // (DA+CB)
+		x3.Sqr(&z2)      // x3 = (DA+CB)^2
+
+		z2.Sqr(&t1)      // AA = A^2
+		t1.Sqr(&t2)      // BB = B^2
+		x2.Mul(&z2, &t1) // x2 = AA*BB
+		t2.Sub(&z2, &t1) // E = AA-BB
+
+		t1.Mlw(&t2, -edwardsD) // E*-d = a24*E
+		t1.Add(&t1, &z2)       // AA + a24*E
+		z2.Mul(&t2, &t1)       // z2 = E(AA+a24*E)
 	}
 
 	// Finish
-	x2.condSwap(&x3, swap)
-	z2.condSwap(&x3, swap)
-	z2.inv(&z2)
-	x1.mul(&x2, &z2)
-	x1.ser(out)
-
-	// As with X25519, both sides MUST check, without leaking extra
-	// information about the value of K, whether the resulting shared K is
-	// the all-zero value and abort if so.
-	var nz limbSint
+	x2.CondSwap(&x3, swap)
+	z2.CondSwap(&x3, swap)
+	z2.Inv(&z2)
+	x1.Mul(&x2, &z2)
+	x1.Ser(out)
+
+	// Ladder state depends on bits of the scalar
+	for _, e := range []*fp448.Elt{&x1, &x2, &z2, &x3, &z3, &t1, &t2} {
+		e.Cpy(&fp448.Zero)
+	}
+	return checkNonZero(out)
+}
+
+// As with X25519, both sides MUST check, without leaking extra
+// information about the value of K, whether the resulting shared K is
+// the all-zero value and abort if so.
+func checkNonZero(out *[56]byte) error {
+	var nz int32
 	for _, v := range out {
-		nz |= (limbSint)(v)
+		nz |= (int32)(v)
 	}
 	nz = (nz - 1) >> 8 // 0 = succ, -1 = fail
 
-	// return value: 0 = succ, -1 = fail
-	return (int)(nz)
+	if nz != 0 {
+		return ErrLowOrder
+	}
+	return nil
 }
 
-func ScalarBaseMult(out, scalar *[56]byte) int {
-	return ScalarMult(out, scalar, &basePoint)
+// ScalarBaseMult computes out = scalar * G, where G is the standard base
+// point with u = 5. The result is the same as of ScalarMult with G, but
+// it is computed on the birationally equivalent Edwards curve with a
+// precomputed table of multiples of G, which makes it more than twice
+// faster. The table is computed on the first call. The only clamped
+// scalar for which it fails is 4*q, where q is the order of G.
+func ScalarBaseMult(out, scalar *[56]byte) error {
+	scalarBaseMultEdwards(out, scalar)
+	return checkNonZero(out)
 }
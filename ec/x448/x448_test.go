@@ -106,8 +106,7 @@ func TestX448(t *testing.T) {
 
 	var out [SharedSecretSize]byte
 	for i, vec := range vectors {
-		ret := ScalarMult(&out, &vec.scalar, &vec.base)
-		if ret != 0 {
+		if err := ScalarMult(&out, &vec.scalar, &vec.base); err != nil {
 			t.Errorf("KAT[%d]: ScalarMultiply failed", i)
 		}
 		if !bytes.Equal(out[:], vec.answer[:]) {
@@ -131,8 +130,7 @@ func TestX448IETFDraft(t *testing.T) {
 	copy(u[:], basePoint[:])
 
 	for i := 0; i < 1000000; i++ {
-		ret := ScalarMult(&out, &k, &u)
-		if ret != 0 {
+		if err := ScalarMult(&out, &k, &u); err != nil {
 			t.Fatalf("Iterated[%d]: ScalarMultiply failed", i)
 		}
 		switch i + 1 {
@@ -211,29 +209,25 @@ func TestCurve448(t *testing.T) {
 	}
 
 	var out [SharedSecretSize]byte
-	ret := ScalarBaseMult(&out, &alicePriv)
-	if ret != 0 {
+	if err := ScalarBaseMult(&out, &alicePriv); err != nil {
 		t.Error("Alice: ScalarBaseMult failed")
 	}
 	if !bytes.Equal(out[:], alicePub[:]) {
 		t.Error("Alice: ScalarBaseMult Mismatch")
 	}
-	ret = ScalarBaseMult(&out, &bobPriv)
-	if ret != 0 {
+	if err := ScalarBaseMult(&out, &bobPriv); err != nil {
 		t.Error("Bob: ScalarBaseMult failed")
 	}
 	if !bytes.Equal(out[:], bobPub[:]) {
 		t.Error("Bob: ScalarBaseMult Mismatch")
 	}
-	ret = ScalarMult(&out, &bobPriv, &alicePub)
-	if ret != 0 {
+	if err := ScalarMult(&out, &bobPriv, &alicePub); err != nil {
 		t.Error("Bob: ScalarMult failed")
 	}
 	if !bytes.Equal(out[:], aliceBob[:]) {
 		t.Error("Bob: ScalarMult Mismatch")
 	}
-	ret = ScalarMult(&out, &alicePriv, &bobPub)
-	if ret != 0 {
+	if err := ScalarMult(&out, &alicePriv, &bobPub); err != nil {
 		t.Error("Alice: ScalarMult failed")
 	}
 	if !bytes.Equal(out[:], aliceBob[:]) {
@@ -243,19 +237,17 @@ func TestCurve448(t *testing.T) {
 
 func BenchmarkECDH(b *testing.B) {
 	var sa, sb, pa, pb, ab, ba [SharedSecretSize]byte
-	ret := 0
-
 	rand.Read(sa[:])
 	rand.Read(sb[:])
 	b.ResetTimer()
 	b.StopTimer()
 	for i := 0; i < b.N; i++ {
-		ret |= ScalarBaseMult(&pa, &sa)
-		ret |= ScalarBaseMult(&pb, &sb)
+		ScalarBaseMult(&pa, &sa)
+		ScalarBaseMult(&pb, &sb)
 		b.StartTimer()
-		ret |= ScalarMult(&ab, &sa, &pb)
+		ScalarMult(&ab, &sa, &pb)
 		b.StopTimer()
-		ret |= ScalarMult(&ba, &sb, &pa)
+		ScalarMult(&ba, &sb, &pa)
 		if !bytes.Equal(ab[:], ba[:]) {
 			b.Fatal("Alice/Bob: Mismatch")
 		}
@@ -263,3 +255,99 @@ func BenchmarkECDH(b *testing.B) {
 		copy(sb[:], pb[:])
 	}
 }
+
+// All u-coordinates of points of small order on curve448 and its twist,
+// including non-canonical encodings of values above p. Each of them must
+// make ScalarMult fail, whatever the scalar.
+var lowOrderPoints = []string{
+	// 0, order 2 (and the point at infinity)
+	"0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+	// 1, order 4
+	"0100000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+	// p-1, order 4 on the twist
+	"fefffffffffffffffffffffffffffffffffffffffffffffffffffffffeffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+	// p, non-canonical 0
+	"fffffffffffffffffffffffffffffffffffffffffffffffffffffffffeffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+	// p+1, non-canonical 1
+	"00000000000000000000000000000000000000000000000000000000ffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+}
+
+func TestLowOrder(t *testing.T) {
+	var scalar, base, out [SharedSecretSize]byte
+	for i := 0; i < 8; i++ {
+		if _, err := rand.Read(scalar[:]); err != nil {
+			t.Fatal(err)
+		}
+		for _, u := range lowOrderPoints {
+			b, err := hex.DecodeString(u)
+			if err != nil || len(b) != SharedSecretSize {
+				t.Fatalf("malformed point %s", u)
+			}
+			copy(base[:], b)
+			if err = ScalarMult(&out, &scalar, &base); err != ErrLowOrder {
+				t.Errorf("%s: expected ErrLowOrder, got %v", u, err)
+			}
+			if out != [SharedSecretSize]byte{} {
+				t.Errorf("%s: expected all-zero output", u)
+			}
+		}
+	}
+
+	// p-1 and p-2 are canonical values, of which only the first has
+	// small order. The second must be processed normally.
+	b, _ := hex.DecodeString(lowOrderPoints[2])
+	copy(base[:], b)
+	base[0]--
+	if err := ScalarMult(&out, &scalar, &base); err != nil {
+		t.Errorf("p-2: unexpected error %v", err)
+	}
+}
+
+func TestScalarBaseMultOrder(t *testing.T) {
+	// 4*q, where q is the order of the base point, is the only clamped
+	// scalar for which ScalarBaseMult produces the all-zero value.
+	var scalar, out [SharedSecretSize]byte
+	b, _ := hex.DecodeString("cc1361ad4a0ae38d543d1637ca09b38540da58bb266d3b11a78f28f3fdffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	copy(scalar[:], b)
+	if err := ScalarBaseMult(&out, &scalar); err != ErrLowOrder {
+		t.Errorf("expected ErrLowOrder, got %v", err)
+	}
+	scalar[0] += 4
+	if err := ScalarBaseMult(&out, &scalar); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+// Compares fixed-base multiplication with the ladder
+func TestScalarBaseMultLadder(t *testing.T) {
+	var scalar, out1, out2 [SharedSecretSize]byte
+	for i := 0; i < 200; i++ {
+		if _, err := rand.Read(scalar[:]); err != nil {
+			t.Fatal(err)
+		}
+		// Exercise extreme values as well
+		switch i {
+		case 0:
+			scalar = [SharedSecretSize]byte{}
+		case 1:
+			for j := range scalar {
+				scalar[j] = 0xFF
+			}
+		}
+		err1 := ScalarBaseMult(&out1, &scalar)
+		err2 := ScalarMult(&out2, &scalar, &basePoint)
+		if out1 != out2 || err1 != err2 {
+			t.Fatalf("mismatch for scalar %x", scalar)
+		}
+	}
+}
+
+func BenchmarkScalarBaseMult(b *testing.B) {
+	var scalar, out [SharedSecretSize]byte
+	rand.Read(scalar[:])
+	ScalarBaseMult(&out, &scalar)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ScalarBaseMult(&out, &scalar)
+	}
+}
@@ -0,0 +1,236 @@
+#!/usr/bin/env python3
+# Independent Noise implementation (Noise spec rev 34) used to check
+# nobs/noise. Pure Python: X25519/X448 from RFC 7748, AES-256-GCM from
+# FIPS 197 / SP 800-38D, SHA-2 and HMAC from the standard library.
+#
+# usage: noise_vectors.py verify vectors.txt   - recompute every ciphertext
+#        noise_vectors.py gen448 vectors.txt   - print 448 variants of vectors
+import hashlib, hmac, sys
+
+# ---------------------------------------------------------------- RFC 7748
+def _ladder(k, u, p, a24, bits):
+    x1, x2, z2, x3, z3, swap = u, 1, 0, u, 1, 0
+    for t in reversed(range(bits)):
+        kt = (k >> t) & 1
+        swap ^= kt
+        if swap:
+            x2, x3, z2, z3 = x3, x2, z3, z2
+        swap = kt
+        A = (x2 + z2) % p; AA = A * A % p
+        B = (x2 - z2) % p; BB = B * B % p
+        E = (AA - BB) % p
+        C = (x3 + z3) % p; D = (x3 - z3) % p
+        DA = D * A % p; CB = C * B % p
+        x3 = (DA + CB) ** 2 % p
+        z3 = x1 * (DA - CB) ** 2 % p
+        x2 = AA * BB % p
+        z2 = E * (AA + a24 * E) % p
+    if swap:
+        x2, z2 = x3, z3
+    return x2 * pow(z2, p - 2, p) % p
+
+def x25519(k, u):
+    k = bytearray(k); k[0] &= 248; k[31] &= 127; k[31] |= 64
+    p = 2**255 - 19
+    u = int.from_bytes(u, 'little') & ((1 << 255) - 1)
+    return _ladder(int.from_bytes(k, 'little'), u, p, 121665, 255).to_bytes(32, 'little')
+
+def x448(k, u):
+    k = bytearray(k); k[0] &= 252; k[55] |= 128
+    p = 2**448 - 2**224 - 1
+    u = int.from_bytes(u, 'little')
+    return _ladder(int.from_bytes(k, 'little'), u, p, 39081, 448).to_bytes(56, 'little')
+
+DHS = {
+    '25519': (32, x25519, (9).to_bytes(32, 'little')),
+    '448': (56, x448, (5).to_bytes(56, 'little')),
+}
+
+# ---------------------------------------------------------------- AES-256
+def _xt(a):
+    return ((a << 1) ^ 0x1b) & 0xff if a & 0x80 else a << 1
+
+def _sbox():
+    s = [0] * 256
+    p = q = 1
+    while True:
+        p = p ^ ((p << 1) & 0xff) ^ (0x1b if p & 0x80 else 0)
+        q ^= q << 1; q ^= q << 2; q ^= q << 4; q &= 0xff
+        if q & 0x80: q ^= 0x09
+        r = lambda x, n: ((x << n) | (x >> (8 - n))) & 0xff
+        s[p] = q ^ r(q, 1) ^ r(q, 2) ^ r(q, 3) ^ r(q, 4) ^ 0x63
+        if p == 1: break
+    s[0] = 0x63
+    return s
+SBOX = _sbox()
+
+def _expand(key):
+    nk, nr = len(key) // 4, len(key) // 4 + 6
+    w = [list(key[4*i:4*i+4]) for i in range(nk)]
+    rc = 1
+    for i in range(nk, 4 * (nr + 1)):
+        t = list(w[i-1])
+        if i % nk == 0:
+            t = [SBOX[b] for b in t[1:] + t[:1]]
+            t[0] ^= rc; rc = _xt(rc)
+        elif nk > 6 and i % nk == 4:
+            t = [SBOX[b] for b in t]
+        w.append([a ^ b for a, b in zip(w[i-nk], t)])
+    return [sum(w[4*r:4*r+4], []) for r in range(nr + 1)]
+
+def aes_enc(rk, blk):
+    s = [a ^ b for a, b in zip(blk, rk[0])]
+    for r in range(1, len(rk)):
+        s = [SBOX[b] for b in s]
+        s = [s[(i + 4 * (i % 4)) % 16] for i in range(16)]  # ShiftRows
+        if r != len(rk) - 1:
+            m = []
+            for c in range(4):
+                a = s[4*c:4*c+4]; t = a[0] ^ a[1] ^ a[2] ^ a[3]
+                m += [a[i] ^ t ^ _xt(a[i] ^ a[(i+1) % 4]) for i in range(4)]
+            s = m
+        s = [a ^ b for a, b in zip(s, rk[r])]
+    return bytes(s)
+
+def _gmul(x, y):
+    R, z = 0xe1 << 120, 0
+    for i in range(127, -1, -1):
+        if (y >> i) & 1: z ^= x
+        x = (x >> 1) ^ R if x & 1 else x >> 1
+    return z
+
+def _ghash(h, ad, c):
+    def blocks(b):
+        b = b + bytes(-len(b) % 16)
+        return [b[i:i+16] for i in range(0, len(b), 16)]
+    y = 0
+    for blk in blocks(ad) + blocks(c) + [(8*len(ad)).to_bytes(8, 'big') + (8*len(c)).to_bytes(8, 'big')]:
+        y = _gmul(y ^ int.from_bytes(blk, 'big'), h)
+    return y
+
+def gcm_seal(key, nonce, ad, pt):
+    rk = _expand(key)
+    h = int.from_bytes(aes_enc(rk, bytes(16)), 'big')
+    ct = bytearray()
+    for i in range(0, len(pt), 16):
+        ks = aes_enc(rk, nonce + (i // 16 + 2).to_bytes(4, 'big'))
+        ct += bytes(a ^ b for a, b in zip(pt[i:i+16], ks))
+    s = _ghash(h, ad, bytes(ct)) ^ int.from_bytes(aes_enc(rk, nonce + (1).to_bytes(4, 'big')), 'big')
+    return bytes(ct) + s.to_bytes(16, 'big')
+
+# ---------------------------------------------------------------- Noise
+class Cipher:
+    def __init__(self, k=None): self.k, self.n = k, 0
+    def enc(self, ad, pt):
+        if self.k is None: return pt
+        out = gcm_seal(self.k, bytes(4) + self.n.to_bytes(8, 'big'), ad, pt)
+        self.n += 1
+        return out
+
+class Symmetric:
+    def __init__(self, name, hname):
+        self.H = getattr(hashlib, hname.lower())
+        self.hl = self.H().digest_size
+        nb = name.encode()
+        self.h = nb + bytes(self.hl - len(nb)) if len(nb) <= self.hl else self.H(nb).digest()
+        self.ck, self.c = self.h, Cipher()
+    def hkdf(self, ikm, n):
+        tk = hmac.new(self.ck, ikm, self.H).digest()
+        out, prev = [], b''
+        for i in range(1, n + 1):
+            prev = hmac.new(tk, prev + bytes([i]), self.H).digest(); out.append(prev)
+        return out
+    def mix_hash(self, d): self.h = self.H(self.h + d).digest()
+    def mix_key(self, ikm):
+        self.ck, k = self.hkdf(ikm, 2); self.c = Cipher(k[:32])
+    def enc_hash(self, pt):
+        ct = self.c.enc(self.h, pt); self.mix_hash(ct); return ct
+    def split(self):
+        a, b = self.hkdf(b'', 2); return Cipher(a[:32]), Cipher(b[:32])
+
+PATTERNS = {
+    'NN': ([], [], [['e'], ['e', 'ee']]),
+    'NK': ([], ['s'], [['e', 'es'], ['e', 'ee']]),
+    'XX': ([], [], [['e'], ['e', 'ee', 's', 'es'], ['s', 'se']]),
+    'IK': ([], ['s'], [['e', 'es', 's', 'ss'], ['e', 'ee', 'se']]),
+}
+
+def run(v):
+    _, pat, dhn, cn, hn = v['handshake'].split('_')
+    dlen, dh, base = DHS[dhn]
+    assert cn == 'AESGCM'
+    keys = lambda s: (bytes.fromhex(s), dh(bytes.fromhex(s), base)) if s else None
+    pre_i, pre_r, msgs = PATTERNS[pat]
+    st = [Symmetric(v['handshake'], hn) for _ in range(2)]
+    s = [keys(v.get('init_static', '')), keys(v.get('resp_static', ''))]
+    eph = [keys(v['gen_init_ephemeral']), keys(v['gen_resp_ephemeral'])]
+    for x in st:
+        x.mix_hash(bytes.fromhex(v.get('prologue', '')))
+        for tok in pre_i: x.mix_hash(s[0][1])
+        for tok in pre_r: x.mix_hash(s[1][1])
+    out = {}
+    i = 0
+    # The writer computes the message, the state of the reader is kept
+    # in sync by running the same symmetric operations.
+    for i, toks in enumerate(msgs):
+        w = i % 2
+        msg = b''
+        for tok in toks:
+            if tok == 'e':
+                msg += eph[w][1]
+                for x in st: x.mix_hash(eph[w][1])
+            elif tok == 's':
+                ct = st[w].enc_hash(s[w][1]); st[1-w].enc_hash(s[w][1]); msg += ct
+            else:
+                a = {'e': eph, 's': s}[tok[0]][0]; b = {'e': eph, 's': s}[tok[1]][1]
+                secret = dh(a[0], b[1])
+                for x in st: x.mix_key(secret)
+        pt = bytes.fromhex(v['msg_%d_payload' % i])
+        ct = st[w].enc_hash(pt); st[1-w].enc_hash(pt)
+        out[i] = msg + ct
+    assert st[0].h == st[1].h
+    c1, c2 = st[0].split()
+    j = len(msgs)
+    while 'msg_%d_payload' % j in v:
+        c = (c1, c2)[(j - len(msgs)) % 2]
+        out[j] = c.enc(b'', bytes.fromhex(v['msg_%d_payload' % j]))
+        j += 1
+    return out
+
+def read(fn):
+    blocks = []
+    for b in open(fn).read().split('\n\n'):
+        lines = [l for l in b.strip().split('\n') if l]
+        if lines: blocks.append([l.split('=', 1) for l in lines])
+    return blocks
+
+# Extends key by continuing its sequence of bytes, e.g. 00 01 02 ...
+def extend(s, n):
+    b = bytes.fromhex(s)
+    return bytes((b[0] + i) & 0xff for i in range(n)).hex()
+
+def main():
+    cmd, fn = sys.argv[1:]
+    bad = 0
+    for blk in read(fn):
+        v = dict(blk)
+        if cmd == 'gen448':
+            if '_25519_' not in v['handshake']: continue
+            blk = [[k, v['handshake'].replace('_25519_', '_448_') if k == 'handshake'
+                    else extend(x, 56) if k.endswith(('static', 'ephemeral')) else x] for k, x in blk]
+            v = dict(blk)
+        out = run(v)
+        for k, x in blk:
+            if k.endswith('_ciphertext'):
+                got = out[int(k.split('_')[1])].hex()
+                if cmd == 'verify' and got != x:
+                    bad += 1; print('MISMATCH', v['handshake'], k)
+        if cmd == 'gen448':
+            for kv in blk:
+                if kv[0].endswith('_ciphertext'): kv[1] = out[int(kv[0].split('_')[1])].hex()
+            print('\n'.join('%s=%s' % tuple(kv) for kv in blk) + '\n')
+    if cmd == 'verify':
+        print('verified' if not bad else '%d mismatches' % bad, len(read(fn)), 'vectors')
+
+if __name__ == '__main__':
+    main()
@@ -63,6 +63,15 @@ func (d *state) Reset() {
 	d.buf = d.storage[:0]
 }
 
+// Zeroize overwrites the sponge state and the byte buffer, including
+// data which was already absorbed or squeezed, and resets the hash.
+func (d *state) Zeroize() {
+	for i := range d.storage {
+		d.storage[i] = 0
+	}
+	d.Reset()
+}
+
 func (d *state) clone() *state {
 	ret := *d
 	if ret.state == spongeAbsorbing {
@@ -216,6 +216,14 @@ func (s *asmState) Reset() {
 	s.state = spongeAbsorbing
 }
 
+// Zeroize overwrites the state and the buffer and resets the Hash.
+func (s *asmState) Zeroize() {
+	for i := range s.storage {
+		s.storage[i] = 0
+	}
+	s.Reset()
+}
+
 // Size returns the number of bytes Sum will return.
 func (s *asmState) Size() int {
 	return s.outputLen
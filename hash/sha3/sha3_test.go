@@ -329,6 +329,58 @@ func TestReset(t *testing.T) {
 	}
 }
 
+// Returns generic state of the hash, or nil for assembly implementations
+func genericState(h interface{}) *state {
+	switch v := h.(type) {
+	case *state:
+		return v
+	case *cshakeState:
+		return &v.state
+	}
+	return nil
+}
+
+func TestZeroize(t *testing.T) {
+	secret := sequentialBytes(0x100)
+	out1 := make([]byte, 32)
+	out2 := make([]byte, 32)
+
+	for name, v := range testShakes {
+		c := v.constructor([]byte(v.defAlgoName), []byte(v.defCustomStr))
+		c.Write(secret[:0x85])
+		c.Read(out1)
+		c.(Zeroizer).Zeroize()
+		if d := genericState(c); d != nil {
+			if d.storage != [maxRate]byte{} || len(d.buf) != 0 {
+				t.Errorf("%s: buffer not zeroized", name)
+			}
+		}
+
+		// Zeroized state is equal to the initial one
+		c.Write(secret[:0x85])
+		c.Read(out2)
+		if !bytes.Equal(out1, out2) {
+			t.Errorf("%s: hash differs after Zeroize", name)
+		}
+	}
+
+	for name, f := range testDigests {
+		h := f()
+		h.Write(secret[:0x85])
+		out1 = h.Sum(out1[:0])
+		h.(Zeroizer).Zeroize()
+		if d := genericState(h); d != nil {
+			if d.storage != [maxRate]byte{} || d.a != [25]uint64{} {
+				t.Errorf("%s: state not zeroized", name)
+			}
+		}
+		h.Write(secret[:0x85])
+		if !bytes.Equal(out1, h.Sum(nil)) {
+			t.Errorf("%s: hash differs after Zeroize", name)
+		}
+	}
+}
+
 func TestClone(t *testing.T) {
 	out1 := make([]byte, 16)
 	out2 := make([]byte, 16)
@@ -439,7 +491,7 @@ func Example_mac() {
 	// Output: 78de2974bd2711d5549ffd32b753ef0f5fa80a0db2556db60f0987eb8a9218ff
 }
 
-func ExampleCShake256() {
+func ExampleNewCShake256() {
 	out := make([]byte, 32)
 	msg := []byte("The quick brown fox jumps over the lazy dog")
 
@@ -39,6 +39,14 @@ type ShakeHash interface {
 	Reset()
 }
 
+// Zeroizer is implemented by all hashes returned by this package, both
+// ShakeHash and hash.Hash. Zeroize overwrites all data held by the hash,
+// so that secret input and output don't stay in memory, and resets it
+// to its initial state.
+type Zeroizer interface {
+	Zeroize()
+}
+
 // cSHAKE specific context
 type cshakeState struct {
 	state // SHA-3 state context and Read/Write operations
@@ -100,6 +108,12 @@ func (c *cshakeState) Reset() {
 	c.Write(bytepad(c.initBlock, c.rate))
 }
 
+// Zeroize wipes the cSHAKE context and resets it to its initial state.
+func (c *cshakeState) Zeroize() {
+	c.state.Zeroize()
+	c.Write(bytepad(c.initBlock, c.rate))
+}
+
 // Clone returns copy of a cSHAKE context within its current state.
 func (c *cshakeState) Clone() ShakeHash {
 	b := make([]byte, len(c.initBlock))
@@ -55,7 +55,16 @@ func (d *digest) Reset() {
 	d.len = 0
 }
 
+// Zeroize overwrites buffered input and resets the digest.
+func (d *digest) Zeroize() {
+	for i := range d.b {
+		d.b[i] = 0
+	}
+	d.Reset()
+}
+
 func (d *digest) Write(input []byte) (nn int, err error) {
+	nn = len(input)
 
 	// current possition in the buffer
 	idx := int(d.len & uint64((d.BlockSize() - 1)))
@@ -1,6 +1,7 @@
 package sm3
 
 import (
+	"bytes"
 	"encoding/hex"
 	"testing"
 )
@@ -521,6 +522,22 @@ func TestReset(t *testing.T) {
 	}
 }
 
+func TestZeroize(t *testing.T) {
+	secret := []byte("secret input, which isn't a multiple of block size")
+	d := New()
+	d.Write(secret)
+	exp := d.Sum(nil)
+
+	d.(interface{ Zeroize() }).Zeroize()
+	if d.(*digest).b != [BlockSize]byte{} {
+		t.Errorf("buffer not zeroized: %X", d.(*digest).b)
+	}
+	d.Write(secret)
+	if !bytes.Equal(d.Sum(nil), exp) {
+		t.Errorf("hash differs after Zeroize")
+	}
+}
+
 func TestSplit(t *testing.T) {
 
 	var d digest
@@ -573,6 +590,17 @@ func TestCallSumMultipleTimes(t *testing.T) {
 	}
 }
 
+// Write must report number of bytes consumed, otherwise io.Copy fails
+func TestWriteReturnsLength(t *testing.T) {
+	d := New()
+	for _, l := range []int{0, 1, BlockSize - 1, BlockSize, 3*BlockSize + 5} {
+		n, err := d.Write(make([]byte, l))
+		if n != l || err != nil {
+			t.Errorf("Write(%d) returned (%d, %v)", l, n, err)
+		}
+	}
+}
+
 /* ------------------ Benchmarks ------------------- */
 var bench = New()
 var buf = make([]byte, 8192)
@@ -0,0 +1,60 @@
+package hpke
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+)
+
+// AEAD is an authenticated encryption scheme. Implementations of other
+// AEADs (e.g. ChaCha20Poly1305 with identifier 0x0003) can be used with a
+// Suite by implementing this interface.
+type AEAD interface {
+	// Identifier from the HPKE AEAD registry
+	ID() uint16
+	// Size of the key in bytes (Nk)
+	KeySize() int
+	// Size of the nonce in bytes (Nn)
+	NonceSize() int
+	// New returns an instance of the AEAD keyed with key
+	New(key []byte) (cipher.AEAD, error)
+}
+
+type aesGCM struct {
+	id      uint16
+	keySize int
+}
+
+// AES128GCM returns AES-128-GCM
+func AES128GCM() AEAD { return aesGCM{0x0001, 16} }
+
+// AES256GCM returns AES-256-GCM
+func AES256GCM() AEAD { return aesGCM{0x0002, 32} }
+
+func (a aesGCM) ID() uint16     { return a.id }
+func (a aesGCM) KeySize() int   { return a.keySize }
+func (a aesGCM) NonceSize() int { return 12 }
+
+func (a aesGCM) New(key []byte) (cipher.AEAD, error) {
+	if len(key) != a.keySize {
+		return nil, errKeySize
+	}
+	b, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(b)
+}
+
+type exportOnly struct{}
+
+// ExportOnly returns the export-only AEAD. Contexts set up with it can
+// only be used to export secrets.
+func ExportOnly() AEAD { return exportOnly{} }
+
+func (exportOnly) ID() uint16     { return 0xFFFF }
+func (exportOnly) KeySize() int   { return 0 }
+func (exportOnly) NonceSize() int { return 0 }
+
+func (exportOnly) New(key []byte) (cipher.AEAD, error) {
+	return nil, errExportOnly
+}
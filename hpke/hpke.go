@@ -0,0 +1,315 @@
+// Package hpke implements Hybrid Public Key Encryption as specified in
+// RFC 9180, in the Base, PSK, Auth and AuthPSK modes.
+//
+// A Suite combines a KEM, a KDF and an AEAD. Supported are:
+//   - KEMs: DHKEM(X25519, HKDF-SHA256), DHKEM(X448, HKDF-SHA512) and,
+//     experimentally, any KEM from the kem package (see PQKEM)
+//   - KDFs: HKDF-SHA256, HKDF-SHA384, HKDF-SHA512 and KMAC256 (not
+//     standardized)
+//   - AEADs: AES-128-GCM, AES-256-GCM and export-only. Other AEADs can be
+//     plugged in by implementing the AEAD interface.
+package hpke
+
+import (
+	"crypto/cipher"
+	"errors"
+	"io"
+)
+
+// Mode of operation
+type Mode uint8
+
+const (
+	ModeBase    Mode = 0x00
+	ModePSK     Mode = 0x01
+	ModeAuth    Mode = 0x02
+	ModeAuthPSK Mode = 0x03
+)
+
+var (
+	errKeySize      = errors.New("hpke: wrong size of the key")
+	errOutputLength = errors.New("hpke: requested output too long")
+	errExportOnly   = errors.New("hpke: export-only AEAD can't encrypt")
+	errPSK          = errors.New("hpke: inconsistent PSK inputs")
+	errAuth         = errors.New("hpke: KEM doesn't support authentication")
+	errRole         = errors.New("hpke: operation not allowed for this role")
+	errSeqOverflow  = errors.New("hpke: message limit reached")
+	errOpen         = errors.New("hpke: message authentication failed")
+)
+
+// versionLabel is prepended to all labeled inputs
+const versionLabel = "HPKE-v1"
+
+// Computes Extract(salt, "HPKE-v1" || suite || label || ikm)
+func labeledExtract(f KDF, suite, salt []byte, label string, ikm []byte) []byte {
+	in := make([]byte, 0, len(versionLabel)+len(suite)+len(label)+len(ikm))
+	in = append(in, versionLabel...)
+	in = append(in, suite...)
+	in = append(in, label...)
+	in = append(in, ikm...)
+	return f.Extract(salt, in)
+}
+
+// Computes Expand(prk, I2OSP(l, 2) || "HPKE-v1" || suite || label || info, l)
+func labeledExpand(f KDF, suite, prk []byte, label string, info []byte, l int) ([]byte, error) {
+	if l > 0xFFFF {
+		return nil, errOutputLength
+	}
+	in := make([]byte, 0, 2+len(versionLabel)+len(suite)+len(label)+len(info))
+	in = append(in, byte(l>>8), byte(l))
+	in = append(in, versionLabel...)
+	in = append(in, suite...)
+	in = append(in, label...)
+	in = append(in, info...)
+	return f.Expand(prk, in, l)
+}
+
+// Suite is a combination of KEM, KDF and AEAD
+type Suite struct {
+	kem  KEM
+	kdf  KDF
+	aead AEAD
+}
+
+// NewSuite returns a suite consisting of given algorithms
+func NewSuite(k KEM, f KDF, a AEAD) *Suite {
+	return &Suite{kem: k, kdf: f, aead: a}
+}
+
+// KEM returns KEM used by the suite
+func (s *Suite) KEM() KEM { return s.kem }
+
+// Returns "HPKE" || I2OSP(kem_id, 2) || I2OSP(kdf_id, 2) || I2OSP(aead_id, 2)
+func (s *Suite) id() []byte {
+	k, f, a := s.kem.ID(), s.kdf.ID(), s.aead.ID()
+	return append([]byte("HPKE"), byte(k>>8), byte(k), byte(f>>8), byte(f), byte(a>>8), byte(a))
+}
+
+// Context is an encryption context established by one of the Setup
+// functions. Contexts of the sender can only Seal, contexts of the
+// receiver can only Open. Both can Export. Context is not safe for
+// concurrent use.
+type Context struct {
+	suite          *Suite
+	sender         bool
+	aead           cipher.AEAD
+	baseNonce      []byte
+	seq            uint64
+	exporterSecret []byte
+}
+
+// Implements KeySchedule from RFC 9180, 5.1
+func (s *Suite) keySchedule(mode Mode, ss, info, psk, pskID []byte, sender bool) (*Context, error) {
+	if (len(psk) == 0) != (len(pskID) == 0) {
+		return nil, errPSK
+	}
+	if (len(psk) != 0) != (mode == ModePSK || mode == ModeAuthPSK) {
+		return nil, errPSK
+	}
+
+	suite := s.id()
+	pskIDHash := labeledExtract(s.kdf, suite, nil, "psk_id_hash", pskID)
+	infoHash := labeledExtract(s.kdf, suite, nil, "info_hash", info)
+	ksc := append(append([]byte{byte(mode)}, pskIDHash...), infoHash...)
+	secret := labeledExtract(s.kdf, suite, ss, "secret", psk)
+
+	c := &Context{suite: s, sender: sender}
+	var err error
+	c.exporterSecret, err = labeledExpand(s.kdf, suite, secret, "exp", ksc, s.kdf.Size())
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := s.aead.(exportOnly); ok {
+		return c, nil
+	}
+
+	key, err := labeledExpand(s.kdf, suite, secret, "key", ksc, s.aead.KeySize())
+	if err != nil {
+		return nil, err
+	}
+	c.baseNonce, err = labeledExpand(s.kdf, suite, secret, "base_nonce", ksc, s.aead.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+	if c.aead, err = s.aead.New(key); err != nil {
+		return nil, err
+	}
+	if c.aead.NonceSize() != len(c.baseNonce) || len(c.baseNonce) < 8 {
+		return nil, errKeySize
+	}
+	return c, nil
+}
+
+// Sender side of all modes. skS is nil in modes without authentication.
+func (s *Suite) setupS(mode Mode, rng io.Reader, pkR, info, psk, pskID, skS []byte) ([]byte, *Context, error) {
+	var ss, enc []byte
+	var err error
+	if skS != nil {
+		ak, ok := s.kem.(AuthKEM)
+		if !ok {
+			return nil, nil, errAuth
+		}
+		ss, enc, err = ak.AuthEncap(rng, pkR, skS)
+	} else {
+		ss, enc, err = s.kem.Encap(rng, pkR)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := s.keySchedule(mode, ss, info, psk, pskID, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enc, c, nil
+}
+
+// Receiver side of all modes. pkS is nil in modes without authentication.
+func (s *Suite) setupR(mode Mode, enc, skR, info, psk, pskID, pkS []byte) (*Context, error) {
+	var ss []byte
+	var err error
+	if pkS != nil {
+		ak, ok := s.kem.(AuthKEM)
+		if !ok {
+			return nil, errAuth
+		}
+		ss, err = ak.AuthDecap(enc, skR, pkS)
+	} else {
+		ss, err = s.kem.Decap(enc, skR)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.keySchedule(mode, ss, info, psk, pskID, false)
+}
+
+// SetupBaseS establishes a context of the sender for the recipient's
+// public key pkR. Returns the encapsulated key to be sent to the
+// recipient. The rng must be cryptographically secure PRNG.
+func (s *Suite) SetupBaseS(rng io.Reader, pkR, info []byte) ([]byte, *Context, error) {
+	return s.setupS(ModeBase, rng, pkR, info, nil, nil, nil)
+}
+
+// SetupBaseR establishes a context of the recipient from the
+// encapsulated key enc
+func (s *Suite) SetupBaseR(enc, skR, info []byte) (*Context, error) {
+	return s.setupR(ModeBase, enc, skR, info, nil, nil, nil)
+}
+
+// SetupPSKS is SetupBaseS authenticated with a pre-shared key psk,
+// identified by pskID
+func (s *Suite) SetupPSKS(rng io.Reader, pkR, info, psk, pskID []byte) ([]byte, *Context, error) {
+	return s.setupS(ModePSK, rng, pkR, info, psk, pskID, nil)
+}
+
+// SetupPSKR is SetupBaseR authenticated with a pre-shared key psk,
+// identified by pskID
+func (s *Suite) SetupPSKR(enc, skR, info, psk, pskID []byte) (*Context, error) {
+	return s.setupR(ModePSK, enc, skR, info, psk, pskID, nil)
+}
+
+// SetupAuthS is SetupBaseS authenticated with the sender's private key
+// skS. Requires AuthKEM.
+func (s *Suite) SetupAuthS(rng io.Reader, pkR, info, skS []byte) ([]byte, *Context, error) {
+	if skS == nil {
+		return nil, nil, errKeySize
+	}
+	return s.setupS(ModeAuth, rng, pkR, info, nil, nil, skS)
+}
+
+// SetupAuthR is SetupBaseR authenticated with the sender's public key
+// pkS. Requires AuthKEM.
+func (s *Suite) SetupAuthR(enc, skR, info, pkS []byte) (*Context, error) {
+	if pkS == nil {
+		return nil, errKeySize
+	}
+	return s.setupR(ModeAuth, enc, skR, info, nil, nil, pkS)
+}
+
+// SetupAuthPSKS combines SetupAuthS and SetupPSKS
+func (s *Suite) SetupAuthPSKS(rng io.Reader, pkR, info, psk, pskID, skS []byte) ([]byte, *Context, error) {
+	if skS == nil {
+		return nil, nil, errKeySize
+	}
+	return s.setupS(ModeAuthPSK, rng, pkR, info, psk, pskID, skS)
+}
+
+// SetupAuthPSKR combines SetupAuthR and SetupPSKR
+func (s *Suite) SetupAuthPSKR(enc, skR, info, psk, pskID, pkS []byte) (*Context, error) {
+	if pkS == nil {
+		return nil, errKeySize
+	}
+	return s.setupR(ModeAuthPSK, enc, skR, info, psk, pskID, pkS)
+}
+
+// Seal encrypts a single message pt to the recipient's public key pkR in
+// the Base mode. Returns encapsulated key and ciphertext.
+func (s *Suite) Seal(rng io.Reader, pkR, info, aad, pt []byte) (enc, ct []byte, err error) {
+	enc, c, err := s.SetupBaseS(rng, pkR, info)
+	if err != nil {
+		return nil, nil, err
+	}
+	ct, err = c.Seal(aad, pt)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enc, ct, nil
+}
+
+// Open decrypts a single message created by Seal
+func (s *Suite) Open(enc, skR, info, aad, ct []byte) ([]byte, error) {
+	c, err := s.SetupBaseR(enc, skR, info)
+	if err != nil {
+		return nil, err
+	}
+	return c.Open(aad, ct)
+}
+
+// Returns base_nonce XOR I2OSP(seq, Nn)
+func (c *Context) nonce() []byte {
+	n := append([]byte(nil), c.baseNonce...)
+	for i := 0; i < 8; i++ {
+		n[len(n)-1-i] ^= byte(c.seq >> (8 * uint(i)))
+	}
+	return n
+}
+
+// Seal encrypts pt with associated data aad. Only for the sender.
+func (c *Context) Seal(aad, pt []byte) ([]byte, error) {
+	if !c.sender {
+		return nil, errRole
+	}
+	if c.aead == nil {
+		return nil, errExportOnly
+	}
+	if c.seq == ^uint64(0) {
+		return nil, errSeqOverflow
+	}
+	ct := c.aead.Seal(nil, c.nonce(), pt, aad)
+	c.seq++
+	return ct, nil
+}
+
+// Open decrypts ct with associated data aad. Only for the recipient.
+func (c *Context) Open(aad, ct []byte) ([]byte, error) {
+	if c.sender {
+		return nil, errRole
+	}
+	if c.aead == nil {
+		return nil, errExportOnly
+	}
+	if c.seq == ^uint64(0) {
+		return nil, errSeqOverflow
+	}
+	pt, err := c.aead.Open(nil, c.nonce(), ct, aad)
+	if err != nil {
+		return nil, errOpen
+	}
+	c.seq++
+	return pt, nil
+}
+
+// Export returns l bytes of secret derived from the context and
+// exporterContext
+func (c *Context) Export(exporterContext []byte, l int) ([]byte, error) {
+	return labeledExpand(c.suite.kdf, c.suite.id(), c.exporterSecret, "sec", exporterContext, l)
+}
@@ -0,0 +1,272 @@
+package hpke
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/henrydcase/nobs/kem"
+	"github.com/henrydcase/nobs/utils/security"
+)
+
+type encryptionVector struct {
+	Aad   string `json:"aad"`
+	Ct    string `json:"ct"`
+	Nonce string `json:"nonce"`
+	Pt    string `json:"pt"`
+}
+
+type exportVector struct {
+	Context string `json:"exporter_context"`
+	L       int    `json:"L"`
+	Value   string `json:"exported_value"`
+}
+
+type vector struct {
+	Mode           Mode               `json:"mode"`
+	KemID          uint16             `json:"kem_id"`
+	KdfID          uint16             `json:"kdf_id"`
+	AeadID         uint16             `json:"aead_id"`
+	Info           string             `json:"info"`
+	IkmR           string             `json:"ikmR"`
+	IkmE           string             `json:"ikmE"`
+	IkmS           string             `json:"ikmS"`
+	SkRm           string             `json:"skRm"`
+	SkSm           string             `json:"skSm"`
+	PkRm           string             `json:"pkRm"`
+	PkSm           string             `json:"pkSm"`
+	Enc            string             `json:"enc"`
+	Psk            string             `json:"psk"`
+	PskID          string             `json:"psk_id"`
+	SharedSecret   string             `json:"shared_secret"`
+	Key            string             `json:"key"`
+	BaseNonce      string             `json:"base_nonce"`
+	ExporterSecret string             `json:"exporter_secret"`
+	Encryptions    []encryptionVector `json:"encryptions"`
+	Exports        []exportVector     `json:"exports"`
+}
+
+var (
+	kems  = map[uint16]AuthKEM{0x0020: DHKEMX25519(), 0x0021: DHKEMX448()}
+	kdfs  = map[uint16]KDF{0x0001: HKDFSHA256(), 0x0002: HKDFSHA384(), 0x0003: HKDFSHA512()}
+	aeads = map[uint16]AEAD{0x0001: AES128GCM(), 0x0002: AES256GCM(), 0xFFFF: ExportOnly()}
+)
+
+func readJson(t *testing.T, fileName string, v interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("File %v can't be opened: %v", fileName, err)
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		t.Fatalf("File %v can't be parsed: %v", fileName, err)
+	}
+}
+
+func fromHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func checkEqual(t *testing.T, name string, got []byte, want string) {
+	t.Helper()
+	if hex.EncodeToString(got) != want {
+		t.Errorf("%s: got %x, want %s", name, got, want)
+	}
+}
+
+// Test vectors from RFC 9180 for suites with DHKEM(X25519) and
+// DHKEM(X448), with the first few encryptions of each
+func TestRFC9180Vectors(t *testing.T) {
+	var vectors []vector
+	readJson(t, "testdata/rfc9180_vectors.json", &vectors)
+	for _, v := range vectors {
+		k, f, a := kems[v.KemID], kdfs[v.KdfID], aeads[v.AeadID]
+		s := NewSuite(k, f, a)
+
+		pkR, skR, err := k.DeriveKeyPair(fromHex(t, v.IkmR))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkEqual(t, "skR", skR, v.SkRm)
+		checkEqual(t, "pkR", pkR, v.PkRm)
+		var pkS, skS []byte
+		if v.Mode == ModeAuth || v.Mode == ModeAuthPSK {
+			if pkS, skS, err = k.DeriveKeyPair(fromHex(t, v.IkmS)); err != nil {
+				t.Fatal(err)
+			}
+			checkEqual(t, "pkS", pkS, v.PkSm)
+		}
+		info, psk, pskID := fromHex(t, v.Info), fromHex(t, v.Psk), fromHex(t, v.PskID)
+		rng := bytes.NewReader(fromHex(t, v.IkmE))
+
+		var enc []byte
+		var sender, receiver *Context
+		switch v.Mode {
+		case ModeBase:
+			if enc, sender, err = s.SetupBaseS(rng, pkR, info); err == nil {
+				receiver, err = s.SetupBaseR(enc, skR, info)
+			}
+		case ModePSK:
+			if enc, sender, err = s.SetupPSKS(rng, pkR, info, psk, pskID); err == nil {
+				receiver, err = s.SetupPSKR(enc, skR, info, psk, pskID)
+			}
+		case ModeAuth:
+			if enc, sender, err = s.SetupAuthS(rng, pkR, info, skS); err == nil {
+				receiver, err = s.SetupAuthR(enc, skR, info, pkS)
+			}
+		case ModeAuthPSK:
+			if enc, sender, err = s.SetupAuthPSKS(rng, pkR, info, psk, pskID, skS); err == nil {
+				receiver, err = s.SetupAuthPSKR(enc, skR, info, psk, pskID, pkS)
+			}
+		}
+		if err != nil {
+			t.Fatalf("mode %d, suite %x: %v", v.Mode, s.id(), err)
+		}
+		checkEqual(t, "enc", enc, v.Enc)
+		checkEqual(t, "exporter_secret", sender.exporterSecret, v.ExporterSecret)
+		checkEqual(t, "base_nonce", sender.baseNonce, v.BaseNonce)
+
+		for i, e := range v.Encryptions {
+			checkEqual(t, "nonce", sender.nonce(), e.Nonce)
+			aad, pt := fromHex(t, e.Aad), fromHex(t, e.Pt)
+			ct, err := sender.Seal(aad, pt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkEqual(t, "ct", ct, e.Ct)
+			got, err := receiver.Open(aad, ct)
+			if err != nil || !bytes.Equal(got, pt) {
+				t.Fatalf("encryption %d: open failed", i)
+			}
+		}
+		for _, e := range v.Exports {
+			out, err := receiver.Export(fromHex(t, e.Context), e.L)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkEqual(t, "exported_value", out, e.Value)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	info, aad, pt := []byte("info"), []byte("aad"), []byte("plaintext")
+	for _, k := range []KEM{DHKEMX448(), PQKEM(0xFF10, kem.ByName("ML-KEM-768"))} {
+		for _, f := range []KDF{HKDFSHA512(), KMAC256()} {
+			s := NewSuite(k, f, AES256GCM())
+			pkR, skR, err := k.GenerateKeyPair(rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			enc, ct, err := s.Seal(rand.Reader, pkR, info, aad, pt)
+			if err != nil {
+				t.Fatalf("%s: %v", k.Name(), err)
+			}
+			if len(enc) != k.EncapsulationSize() {
+				t.Errorf("%s: wrong size of encapsulation", k.Name())
+			}
+			got, err := s.Open(enc, skR, info, aad, ct)
+			if err != nil || !bytes.Equal(got, pt) {
+				t.Errorf("%s: open failed", k.Name())
+			}
+			ct[0] ^= 1
+			if _, err = s.Open(enc, skR, info, aad, ct); err == nil {
+				t.Errorf("%s: modified ciphertext accepted", k.Name())
+			}
+		}
+	}
+}
+
+func TestDeriveKeyPairPQ(t *testing.T) {
+	k := PQKEM(0xFF10, kem.ByName("ML-KEM-768"))
+	pk1, sk1, err := k.DeriveKeyPair([]byte("ikm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk2, sk2, _ := k.DeriveKeyPair([]byte("ikm"))
+	pk3, _, _ := k.DeriveKeyPair([]byte("ikm2"))
+	if !bytes.Equal(pk1, pk2) || !bytes.Equal(sk1, sk2) || bytes.Equal(pk1, pk3) {
+		t.Error("key derivation is not deterministic")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	k := DHKEMX448()
+	s := NewSuite(k, HKDFSHA512(), AES128GCM())
+	pkR, skR, _ := k.GenerateKeyPair(rand.Reader)
+
+	// PSK and its identifier must be given together, only in PSK modes
+	if _, _, err := s.SetupPSKS(rand.Reader, pkR, nil, []byte("psk"), nil); err != errPSK {
+		t.Errorf("expected errPSK, got %v", err)
+	}
+	if _, _, err := s.SetupPSKS(rand.Reader, pkR, nil, nil, nil); err != errPSK {
+		t.Errorf("expected errPSK, got %v", err)
+	}
+	// Low order point
+	if _, err := s.SetupBaseR(make([]byte, k.EncapsulationSize()), skR, nil); err == nil {
+		t.Error("expected error for low order point")
+	}
+	// Roles
+	enc, sender, _ := s.SetupBaseS(rand.Reader, pkR, nil)
+	receiver, _ := s.SetupBaseR(enc, skR, nil)
+	if _, err := sender.Open(nil, nil); err != errRole {
+		t.Errorf("expected errRole, got %v", err)
+	}
+	if _, err := receiver.Seal(nil, nil); err != errRole {
+		t.Errorf("expected errRole, got %v", err)
+	}
+	// Export-only
+	s = NewSuite(k, HKDFSHA512(), ExportOnly())
+	if _, _, err := s.Seal(rand.Reader, pkR, nil, nil, nil); err != errExportOnly {
+		t.Errorf("expected errExportOnly, got %v", err)
+	}
+	// Auth modes need AuthKEM
+	pq := PQKEM(0xFF10, kem.ByName("ML-KEM-768"))
+	pkR, _, _ = pq.GenerateKeyPair(rand.Reader)
+	s = NewSuite(pq, HKDFSHA512(), AES128GCM())
+	if _, _, err := s.SetupAuthS(rand.Reader, pkR, nil, skR); err != errAuth {
+		t.Errorf("expected errAuth, got %v", err)
+	}
+	// Broken schemes are refused
+	security.AllowInsecure(false)
+	pq = PQKEM(0xFF11, kem.ByName("SIKEp503"))
+	if _, _, err := pq.GenerateKeyPair(rand.Reader); !errors.Is(err, security.ErrInsecure) {
+		t.Errorf("expected ErrInsecure, got %v", err)
+	}
+}
+
+// Sample #4 and #6 from NIST examples for KMAC256
+func TestKMAC256(t *testing.T) {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(0x40 + i)
+	}
+	data := make([]byte, 200)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	out := make([]byte, 64)
+	kmac256(out, key, data[:4], "My Tagged Application")
+	checkEqual(t, "sample 4", out, "20c570c31346f703c9ac36c61c03cb64c3970d0cfc787e9b79599d273a68d2f7f69d4cc3de9d104a351689f27cf6f5951f0103f33f4f24871024d9c27773a8dd")
+	kmac256(out, key, data, "My Tagged Application")
+	checkEqual(t, "sample 6", out, "b58618f71f92e1d56c1b8c55ddd7cd188b97b4ca4d99831eb2699a837da2e4d970fbacfde50033aea585f1a2708510c32d07880801bd182898fe476876fc8965")
+}
+
+func BenchmarkSealX448(b *testing.B) {
+	k := DHKEMX448()
+	s := NewSuite(k, HKDFSHA512(), AES128GCM())
+	pkR, _, _ := k.GenerateKeyPair(rand.Reader)
+	msg := make([]byte, 1024)
+	for i := 0; i < b.N; i++ {
+		s.Seal(rand.Reader, pkR, nil, nil, msg)
+	}
+}
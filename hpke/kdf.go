@@ -0,0 +1,133 @@
+package hpke
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"hash"
+
+	"github.com/henrydcase/nobs/hash/sha3"
+)
+
+// KDF is a two-stage key derivation function
+type KDF interface {
+	// Identifier from the HPKE KDF registry
+	ID() uint16
+	// Size of the output of Extract in bytes (Nh)
+	Size() int
+	// Extract returns a pseudorandom key of Size() bytes
+	Extract(salt, ikm []byte) []byte
+	// Expand returns l bytes of output keying material
+	Expand(prk, info []byte, l int) ([]byte, error)
+}
+
+// -----------------------------------------------------------------------------
+// HKDF (RFC 5869)
+//
+
+type hkdf struct {
+	id   uint16
+	hash func() hash.Hash
+	size int
+}
+
+// HKDFSHA256 returns HKDF with SHA-256
+func HKDFSHA256() KDF { return hkdf{0x0001, sha256.New, sha256.Size} }
+
+// HKDFSHA384 returns HKDF with SHA-384
+func HKDFSHA384() KDF { return hkdf{0x0002, sha512.New384, sha512.Size384} }
+
+// HKDFSHA512 returns HKDF with SHA-512
+func HKDFSHA512() KDF { return hkdf{0x0003, sha512.New, sha512.Size} }
+
+func (f hkdf) ID() uint16 { return f.id }
+func (f hkdf) Size() int  { return f.size }
+
+func (f hkdf) Extract(salt, ikm []byte) []byte {
+	if len(salt) == 0 {
+		salt = make([]byte, f.size)
+	}
+	m := hmac.New(f.hash, salt)
+	m.Write(ikm)
+	return m.Sum(nil)
+}
+
+func (f hkdf) Expand(prk, info []byte, l int) ([]byte, error) {
+	if l < 0 || l > 255*f.size {
+		return nil, errOutputLength
+	}
+	m := hmac.New(f.hash, prk)
+	out := make([]byte, 0, l+f.size)
+	var t []byte
+	for i := byte(1); len(out) < l; i++ {
+		m.Reset()
+		m.Write(t)
+		m.Write(info)
+		m.Write([]byte{i})
+		t = m.Sum(t[:0])
+		out = append(out, t...)
+	}
+	return out[:l], nil
+}
+
+// -----------------------------------------------------------------------------
+// KMAC256 (NIST SP 800-185)
+//
+
+// KMAC256 returns a KDF based on KMAC256. Extract computes
+// KMAC256(salt, ikm, 512, "HPKE-Extract") and Expand computes
+// KMAC256(prk, info, 8*l, "HPKE-Expand"). The KDF is not part of RFC 9180
+// and its identifier 0xFF01 is not assigned by IANA, hence it
+// interoperates only with other users of this package.
+func KMAC256() KDF { return kmacKDF{} }
+
+type kmacKDF struct{}
+
+func (kmacKDF) ID() uint16 { return 0xFF01 }
+func (kmacKDF) Size() int  { return 64 }
+
+func (f kmacKDF) Extract(salt, ikm []byte) []byte {
+	out := make([]byte, f.Size())
+	kmac256(out, salt, ikm, "HPKE-Extract")
+	return out
+}
+
+func (kmacKDF) Expand(prk, info []byte, l int) ([]byte, error) {
+	if l < 0 || l > 0xFFFF {
+		return nil, errOutputLength
+	}
+	out := make([]byte, l)
+	kmac256(out, prk, info, "HPKE-Expand")
+	return out, nil
+}
+
+// left_encode and right_encode from SP 800-185, 2.3.1
+func encodeLen(v uint64, left bool) []byte {
+	var b [9]byte
+	binary.BigEndian.PutUint64(b[1:], v)
+	i := 1
+	for i < 8 && b[i] == 0 {
+		i++
+	}
+	if left {
+		b[i-1] = byte(9 - i)
+		return append([]byte(nil), b[i-1:]...)
+	}
+	return append(append([]byte(nil), b[i:]...), byte(9-i))
+}
+
+// Writes KMAC256(key, x, 8*len(out), s) to out
+func kmac256(out, key, x []byte, s string) {
+	const rate = 136
+	h := sha3.NewCShake256([]byte("KMAC"), []byte(s))
+	// bytepad(encode_string(key), rate)
+	pad := encodeLen(rate, true)
+	pad = append(pad, encodeLen(uint64(len(key))*8, true)...)
+	pad = append(pad, key...)
+	pad = append(pad, make([]byte, (rate-len(pad)%rate)%rate)...)
+	h.Write(pad)
+	h.Write(x)
+	h.Write(encodeLen(uint64(len(out))*8, false))
+	h.Read(out)
+}
@@ -0,0 +1,272 @@
+package hpke
+
+import (
+	"io"
+
+	"github.com/henrydcase/nobs/ec/ecdh"
+	"github.com/henrydcase/nobs/hash/sha3"
+	"github.com/henrydcase/nobs/kem"
+)
+
+// KEM is a key encapsulation mechanism. Keys and encapsulations are
+// passed as byte strings in their serialized form.
+type KEM interface {
+	// Identifier from the HPKE KEM registry
+	ID() uint16
+	// Name of the KEM
+	Name() string
+	// Sizes in bytes (Npk, Nsk, Nenc and Nsecret)
+	PublicKeySize() int
+	PrivateKeySize() int
+	EncapsulationSize() int
+	SharedSecretSize() int
+	// GenerateKeyPair returns serialized public and private key. The rng
+	// must be cryptographically secure PRNG.
+	GenerateKeyPair(rng io.Reader) (pk, sk []byte, err error)
+	// DeriveKeyPair deterministically derives a key pair from ikm
+	DeriveKeyPair(ikm []byte) (pk, sk []byte, err error)
+	// Encap returns shared secret and its encapsulation to pkR. The
+	// rng must be cryptographically secure PRNG.
+	Encap(rng io.Reader, pkR []byte) (ss, enc []byte, err error)
+	// Decap returns shared secret encapsulated in enc
+	Decap(enc, skR []byte) (ss []byte, err error)
+}
+
+// AuthKEM is a KEM which additionally authenticates the sender with its
+// private key. Required by the Auth and AuthPSK modes.
+type AuthKEM interface {
+	KEM
+	// AuthEncap is Encap authenticated with sender's private key skS
+	AuthEncap(rng io.Reader, pkR, skS []byte) (ss, enc []byte, err error)
+	// AuthDecap is Decap authenticated with sender's public key pkS
+	AuthDecap(enc, skR, pkS []byte) (ss []byte, err error)
+}
+
+// Returns "KEM" || I2OSP(id, 2)
+func kemSuiteID(id uint16) []byte {
+	return append([]byte("KEM"), byte(id>>8), byte(id))
+}
+
+// -----------------------------------------------------------------------------
+// DHKEM (RFC 9180, 4.1)
+//
+
+type dhkem struct {
+	id    uint16
+	name  string
+	curve ecdh.Curve
+	kdf   KDF
+}
+
+// DHKEMX25519 returns DHKEM(X25519, HKDF-SHA256)
+func DHKEMX25519() AuthKEM {
+	return dhkem{0x0020, "DHKEM(X25519, HKDF-SHA256)", ecdh.X25519(), HKDFSHA256()}
+}
+
+// DHKEMX448 returns DHKEM(X448, HKDF-SHA512)
+func DHKEMX448() AuthKEM {
+	return dhkem{0x0021, "DHKEM(X448, HKDF-SHA512)", ecdh.X448(), HKDFSHA512()}
+}
+
+func (k dhkem) ID() uint16             { return k.id }
+func (k dhkem) Name() string           { return k.name }
+func (k dhkem) PublicKeySize() int     { return k.curve.Size() }
+func (k dhkem) PrivateKeySize() int    { return k.curve.Size() }
+func (k dhkem) EncapsulationSize() int { return k.curve.Size() }
+func (k dhkem) SharedSecretSize() int  { return k.kdf.Size() }
+
+// Key pair is derived from random bytes, so that Encap is deterministic
+// for a given rng.
+func (k dhkem) GenerateKeyPair(rng io.Reader) ([]byte, []byte, error) {
+	ikm := make([]byte, k.PrivateKeySize())
+	if _, err := io.ReadFull(rng, ikm); err != nil {
+		return nil, nil, err
+	}
+	return k.DeriveKeyPair(ikm)
+}
+
+func (k dhkem) DeriveKeyPair(ikm []byte) ([]byte, []byte, error) {
+	suite := kemSuiteID(k.id)
+	prk := labeledExtract(k.kdf, suite, nil, "dkp_prk", ikm)
+	sk, err := labeledExpand(k.kdf, suite, prk, "sk", nil, k.PrivateKeySize())
+	if err != nil {
+		return nil, nil, err
+	}
+	prv := ecdh.NewPrivateKey(k.curve)
+	if err = prv.Import(sk); err != nil {
+		return nil, nil, err
+	}
+	return prv.Public().Export(), sk, nil
+}
+
+// Returns private key and its serialized public key
+func (k dhkem) privateKey(sk []byte) (*ecdh.PrivateKey, []byte, error) {
+	prv := ecdh.NewPrivateKey(k.curve)
+	if err := prv.Import(sk); err != nil {
+		return nil, nil, err
+	}
+	return prv, prv.Public().Export(), nil
+}
+
+// Returns DH(sk, pk)
+func (k dhkem) dh(prv *ecdh.PrivateKey, pk []byte) ([]byte, error) {
+	pub := ecdh.NewPublicKey(k.curve)
+	if err := pub.Import(pk); err != nil {
+		return nil, err
+	}
+	return prv.ECDH(pub)
+}
+
+func (k dhkem) extractAndExpand(dh, kemContext []byte) ([]byte, error) {
+	suite := kemSuiteID(k.id)
+	prk := labeledExtract(k.kdf, suite, nil, "eae_prk", dh)
+	return labeledExpand(k.kdf, suite, prk, "shared_secret", kemContext, k.SharedSecretSize())
+}
+
+func (k dhkem) Encap(rng io.Reader, pkR []byte) ([]byte, []byte, error) {
+	return k.encap(rng, pkR, nil)
+}
+
+func (k dhkem) Decap(enc, skR []byte) ([]byte, error) {
+	return k.decap(enc, skR, nil)
+}
+
+func (k dhkem) AuthEncap(rng io.Reader, pkR, skS []byte) ([]byte, []byte, error) {
+	if len(skS) != k.PrivateKeySize() {
+		return nil, nil, errKeySize
+	}
+	return k.encap(rng, pkR, skS)
+}
+
+func (k dhkem) AuthDecap(enc, skR, pkS []byte) ([]byte, error) {
+	if len(pkS) != k.PublicKeySize() {
+		return nil, errKeySize
+	}
+	return k.decap(enc, skR, pkS)
+}
+
+// Implements both Encap and AuthEncap, the latter if skS is not nil
+func (k dhkem) encap(rng io.Reader, pkR, skS []byte) ([]byte, []byte, error) {
+	_, skE, err := k.GenerateKeyPair(rng)
+	if err != nil {
+		return nil, nil, err
+	}
+	prvE, enc, err := k.privateKey(skE)
+	if err != nil {
+		return nil, nil, err
+	}
+	dh, err := k.dh(prvE, pkR)
+	if err != nil {
+		return nil, nil, err
+	}
+	kemContext := append(append([]byte(nil), enc...), pkR...)
+	if skS != nil {
+		prvS, pkS, err := k.privateKey(skS)
+		if err != nil {
+			return nil, nil, err
+		}
+		dhS, err := k.dh(prvS, pkR)
+		if err != nil {
+			return nil, nil, err
+		}
+		dh = append(dh, dhS...)
+		kemContext = append(kemContext, pkS...)
+	}
+	ss, err := k.extractAndExpand(dh, kemContext)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ss, enc, nil
+}
+
+// Implements both Decap and AuthDecap, the latter if pkS is not nil
+func (k dhkem) decap(enc, skR, pkS []byte) ([]byte, error) {
+	prvR, pkR, err := k.privateKey(skR)
+	if err != nil {
+		return nil, err
+	}
+	dh, err := k.dh(prvR, enc)
+	if err != nil {
+		return nil, err
+	}
+	kemContext := append(append([]byte(nil), enc...), pkR...)
+	if pkS != nil {
+		dhS, err := k.dh(prvR, pkS)
+		if err != nil {
+			return nil, err
+		}
+		dh = append(dh, dhS...)
+		kemContext = append(kemContext, pkS...)
+	}
+	return k.extractAndExpand(dh, kemContext)
+}
+
+// -----------------------------------------------------------------------------
+// Post-quantum KEMs
+//
+
+// pqkem adapts kem.Scheme to KEM
+type pqkem struct {
+	id     uint16
+	scheme kem.Scheme
+	kdf    KDF
+}
+
+// PQKEM returns KEM built on the scheme s from the kem package, with
+// identifier id. It is experimental: there are no standardized
+// identifiers for post-quantum KEMs in HPKE, so id must be agreed on by
+// both parties. Shared secret of s is passed through
+// LabeledExtract/LabeledExpand with HKDF-SHA512 and bound to the
+// ciphertext. Key pairs are derived by seeding key generation of s with
+// cSHAKE256. Auth modes are not supported.
+//
+// Errors returned by s are passed through unchanged, in particular
+// broken schemes return an error matching security.ErrInsecure.
+func PQKEM(id uint16, s kem.Scheme) KEM {
+	return pqkem{id, s, HKDFSHA512()}
+}
+
+func (k pqkem) ID() uint16             { return k.id }
+func (k pqkem) Name() string           { return k.scheme.Name() }
+func (k pqkem) PublicKeySize() int     { return k.scheme.PublicKeySize() }
+func (k pqkem) PrivateKeySize() int    { return k.scheme.PrivateKeySize() }
+func (k pqkem) EncapsulationSize() int { return k.scheme.CiphertextSize() }
+func (k pqkem) SharedSecretSize() int  { return k.kdf.Size() }
+
+func (k pqkem) GenerateKeyPair(rng io.Reader) ([]byte, []byte, error) {
+	return k.scheme.GenerateKeyPair(rng)
+}
+
+func (k pqkem) DeriveKeyPair(ikm []byte) ([]byte, []byte, error) {
+	suite := kemSuiteID(k.id)
+	prk := labeledExtract(k.kdf, suite, nil, "dkp_prk", ikm)
+	rng := sha3.NewCShake256(nil, append(suite, "dkp"...))
+	rng.Write(prk)
+	return k.scheme.GenerateKeyPair(rng)
+}
+
+func (k pqkem) extractAndExpand(ss, enc []byte) ([]byte, error) {
+	suite := kemSuiteID(k.id)
+	prk := labeledExtract(k.kdf, suite, nil, "eae_prk", ss)
+	return labeledExpand(k.kdf, suite, prk, "shared_secret", enc, k.SharedSecretSize())
+}
+
+func (k pqkem) Encap(rng io.Reader, pkR []byte) ([]byte, []byte, error) {
+	enc, ss, err := k.scheme.Encapsulate(rng, pkR)
+	if err != nil {
+		return nil, nil, err
+	}
+	ss, err = k.extractAndExpand(ss, enc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ss, enc, nil
+}
+
+func (k pqkem) Decap(enc, skR []byte) ([]byte, error) {
+	ss, err := k.scheme.Decapsulate(skR, enc)
+	if err != nil {
+		return nil, err
+	}
+	return k.extractAndExpand(ss, enc)
+}
@@ -0,0 +1,326 @@
+// Package frodo implements FrodoKEM, the key encapsulation mechanism
+// based on the learning with errors problem over unstructured lattices,
+// with parameter sets FrodoKEM-640, FrodoKEM-976 and FrodoKEM-1344. Each
+// parameter set comes in two variants, which differ in the way the public
+// matrix A is generated: with AES-128 or with SHAKE128. The AES variant
+// uses AES instructions of the CPU when available.
+//
+// Implementation is compatible with the version submitted to the round 3
+// of the NIST PQC competition.
+//
+// [FrodoKEM] https://frodokem.org/files/FrodoKEM-specification-20210604.pdf
+package frodo
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/henrydcase/nobs/hash/sha3"
+)
+
+// Domain separators used when sampling the error matrices
+const (
+	dsKeyGen = 0x5F
+	dsEncaps = 0x96
+)
+
+// Defines operations on public key
+type PublicKey struct {
+	params *FrodoParams
+	seedA  [seedASize]byte
+	// n x nbar matrix B, reduced mod q
+	b []uint16
+	// pkh = SHAKE(pk), cached
+	pkh []byte
+}
+
+// Defines operations on private key
+type PrivateKey struct {
+	params *FrodoParams
+	// Value returned by Decapsulate in case of invalid ciphertext
+	s []byte
+	// Transposition of the n x nbar matrix S
+	sT  []uint16
+	pub PublicKey
+}
+
+// NewPrivateKey initializes private key.
+// Usage of this function guarantees that the object is correctly initialized.
+func NewPrivateKey(id uint8) *PrivateKey {
+	params := Params(id)
+	return &PrivateKey{
+		params: params,
+		s:      make([]byte, params.secSize),
+		sT:     make([]uint16, params.N*nbar),
+		pub:    *NewPublicKey(id),
+	}
+}
+
+// NewPublicKey initializes public key.
+// Usage of this function guarantees that the object is correctly initialized.
+func NewPublicKey(id uint8) *PublicKey {
+	params := Params(id)
+	return &PublicKey{
+		params: params,
+		b:      make([]uint16, params.N*nbar),
+		pkh:    make([]byte, params.secSize),
+	}
+}
+
+// Accessor to the domain parameters
+func (pub *PublicKey) Params() *FrodoParams {
+	return pub.params
+}
+
+// Accessor to the domain parameters
+func (prv *PrivateKey) Params() *FrodoParams {
+	return prv.params
+}
+
+// Public returns public key corresponding to the private key
+func (prv *PrivateKey) Public() *PublicKey {
+	return &prv.pub
+}
+
+// Size returns size of the public key in bytes
+func (pub *PublicKey) Size() int {
+	return pub.params.PublicKeySize
+}
+
+// Size returns size of the private key in bytes
+func (prv *PrivateKey) Size() int {
+	return prv.params.PrivateKeySize
+}
+
+// Exports public key as seedA || Pack(B)
+func (pub *PublicKey) Export() []byte {
+	out := make([]byte, pub.params.PublicKeySize)
+	copy(out, pub.seedA[:])
+	pub.params.pack(out[seedASize:], pub.b)
+	return out
+}
+
+// Import clears content of the public key and imports key stored in the
+// byte string. Returns error in case of wrong size.
+func (pub *PublicKey) Import(input []byte) error {
+	if len(input) != pub.params.PublicKeySize {
+		return errors.New("frodo: wrong size of the public key")
+	}
+	copy(pub.seedA[:], input)
+	pub.params.unpack(pub.b, input[seedASize:])
+	pub.params.hash(pub.pkh, input)
+	return nil
+}
+
+// Exports private key as s || pk || S^T || pkh, where entries of S^T are
+// encoded as 16-bit little-endian values.
+func (prv *PrivateKey) Export() []byte {
+	params := prv.params
+	out := make([]byte, 0, params.PrivateKeySize)
+	out = append(out, prv.s...)
+	out = append(out, prv.pub.Export()...)
+	for _, v := range prv.sT {
+		out = append(out, byte(v), byte(v>>8))
+	}
+	return append(out, prv.pub.pkh...)
+}
+
+// Import clears content of the private key and imports key stored in the
+// byte string. Returns error in case of wrong size or if pkh doesn't match
+// the public key.
+func (prv *PrivateKey) Import(input []byte) error {
+	params := prv.params
+	if len(input) != params.PrivateKeySize {
+		return errors.New("frodo: wrong size of the private key")
+	}
+	sec := params.secSize
+	pk := input[sec : sec+params.PublicKeySize]
+	sT := input[sec+params.PublicKeySize : len(input)-sec]
+	if err := prv.pub.Import(pk); err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(input[len(input)-sec:], prv.pub.pkh) != 1 {
+		return errors.New("frodo: private key hash check failed")
+	}
+	copy(prv.s, input[:sec])
+	for i := range prv.sT {
+		prv.sT[i] = binary.LittleEndian.Uint16(sT[2*i:])
+	}
+	return nil
+}
+
+// Returns SHAKE128 for FrodoKEM-640 and SHAKE256 otherwise
+func (params *FrodoParams) newShake() sha3.ShakeHash {
+	if params.N == 640 {
+		return sha3.NewShake128()
+	}
+	return sha3.NewShake256()
+}
+
+// Computes SHAKE(in) of len(out) bytes
+func (params *FrodoParams) hash(out []byte, in ...[]byte) {
+	h := params.newShake()
+	for _, v := range in {
+		h.Write(v)
+	}
+	h.Read(out)
+}
+
+// Generates key pair from seed s || seedSE || z
+func (prv *PrivateKey) generate(seed []byte) {
+	params := prv.params
+	pub := &prv.pub
+	sec, n := params.secSize, params.N
+
+	copy(prv.s, seed[:sec])
+	params.hash(pub.seedA[:], seed[2*sec:])
+
+	// (S^T || E) = SHAKE(0x5F || seedSE)
+	e := make([]uint16, n*nbar)
+	h := params.newShake()
+	h.Write([]byte{dsKeyGen})
+	h.Write(seed[sec : 2*sec])
+	readU16(h, prv.sT)
+	readU16(h, e)
+	params.sample(prv.sT)
+	params.sample(e)
+
+	// B = A*S + E
+	newMatrixA(params, pub.seedA[:]).mulAddAS(pub.b, prv.sT, e)
+	for i := range pub.b {
+		pub.b[i] &= 1<<params.D - 1
+	}
+	params.hash(pub.pkh, pub.Export())
+}
+
+// Computes ciphertext for message mu, using seedSE to sample the error
+// matrices.
+func (pub *PublicKey) encrypt(ct, mu, seedSE []byte) {
+	params := pub.params
+	n := params.N
+
+	// (S' || E' || E'') = SHAKE(0x96 || seedSE)
+	r := make([]uint16, 2*n*nbar+nbar*nbar)
+	h := params.newShake()
+	h.Write([]byte{dsEncaps})
+	h.Write(seedSE)
+	readU16(h, r)
+	params.sample(r)
+	sp, ep, epp := r[:n*nbar], r[n*nbar:2*n*nbar], r[2*n*nbar:]
+
+	// B' = S'*A + E'
+	bp := make([]uint16, n*nbar)
+	newMatrixA(params, pub.seedA[:]).mulAddSA(bp, sp, ep)
+
+	// C = S'*B + E'' + Encode(mu)
+	var c, m [nbar * nbar]uint16
+	mulAddSB(c[:], sp, pub.b, epp, n)
+	params.encode(m[:], mu)
+	for i := range c {
+		c[i] += m[i]
+	}
+
+	sz := int(params.D) * n * nbar / 8
+	params.pack(ct[:sz], bp)
+	params.pack(ct[sz:], c[:])
+}
+
+// KeyGen derives key pair deterministically from seed s || seedSE || z
+// of KeySeedSize bytes. Intended for testing, use GenerateKeyPair otherwise.
+func KeyGen(id uint8, seed []byte) (*PublicKey, *PrivateKey, error) {
+	prv := NewPrivateKey(id)
+	if len(seed) != prv.params.KeySeedSize {
+		return nil, nil, errors.New("frodo: wrong size of the seed")
+	}
+	prv.generate(seed)
+	return prv.Public(), prv, nil
+}
+
+// GenerateKeyPair generates random key pair for parameter set given by id.
+// The rng must be cryptographically secure PRNG. Error is returned in
+// case PRNG fails.
+func GenerateKeyPair(rng io.Reader, id uint8) (*PublicKey, *PrivateKey, error) {
+	seed := make([]byte, Params(id).KeySeedSize)
+	if _, err := io.ReadFull(rng, seed); err != nil {
+		return nil, nil, err
+	}
+	return KeyGen(id, seed)
+}
+
+// Encaps computes ciphertext and shared secret deterministically from the
+// message mu of MessageSize bytes. Intended for testing, use Encapsulate
+// otherwise.
+func Encaps(pub *PublicKey, mu []byte) (ctext []byte, secret []byte, err error) {
+	params := pub.params
+	sec := params.secSize
+	if len(mu) != params.MessageSize {
+		return nil, nil, errors.New("frodo: wrong size of the message")
+	}
+
+	// (seedSE || k) = SHAKE(pkh || mu)
+	g := make([]byte, 2*sec)
+	params.hash(g, pub.pkh, mu)
+
+	ctext = make([]byte, params.CiphertextSize)
+	pub.encrypt(ctext, mu, g[:sec])
+
+	// ss = SHAKE(ct || k)
+	secret = make([]byte, params.SharedSecretSize)
+	params.hash(secret, ctext, g[sec:])
+	return ctext, secret, nil
+}
+
+// Encapsulate receives the public key and generates ciphertext and shared
+// secret. The rng must be cryptographically secure PRNG. Error is returned
+// in case PRNG fails.
+func Encapsulate(rng io.Reader, pub *PublicKey) (ctext []byte, secret []byte, err error) {
+	mu := make([]byte, pub.params.MessageSize)
+	if _, err = io.ReadFull(rng, mu); err != nil {
+		return nil, nil, err
+	}
+	return Encaps(pub, mu)
+}
+
+// Decapsulate given the private key and ciphertext outputs a shared secret.
+// In case ciphertext is invalid, pseudorandom value derived from s and
+// ciphertext is returned (implicit rejection). Error is returned only if
+// ciphertext has wrong size. Constant time.
+func Decapsulate(prv *PrivateKey, ctext []byte) ([]byte, error) {
+	params := prv.params
+	pub := &prv.pub
+	n, sec := params.N, params.secSize
+
+	if len(ctext) != params.CiphertextSize {
+		return nil, errors.New("frodo: wrong size of the ciphertext")
+	}
+
+	// mu' = Decode(C - B'*S)
+	var c, w [nbar * nbar]uint16
+	bp := make([]uint16, n*nbar)
+	sz := int(params.D) * n * nbar / 8
+	params.unpack(bp, ctext[:sz])
+	params.unpack(c[:], ctext[sz:])
+	mulBS(w[:], bp, prv.sT, n)
+	for i := range w {
+		w[i] = c[i] - w[i]
+	}
+	mu := make([]byte, params.MessageSize)
+	params.decode(mu, w[:])
+
+	// (seedSE' || k') = SHAKE(pkh || mu')
+	g := make([]byte, 2*sec)
+	params.hash(g, pub.pkh, mu)
+
+	// Re-encrypt and use s instead of k' if ciphertexts differ
+	ct := make([]byte, params.CiphertextSize)
+	pub.encrypt(ct, mu, g[:sec])
+	eq := subtle.ConstantTimeCompare(ct, ctext)
+	subtle.ConstantTimeCopy(1-eq, g[sec:], prv.s)
+
+	// ss = SHAKE(ct || k')
+	secret := make([]byte, params.SharedSecretSize)
+	params.hash(secret, ctext, g[sec:])
+	return secret, nil
+}
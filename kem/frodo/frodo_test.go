@@ -0,0 +1,276 @@
+package frodo
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/henrydcase/nobs/drbg"
+)
+
+var ids = []uint8{
+	FRODO640AES, FRODO640SHAKE,
+	FRODO976AES, FRODO976SHAKE,
+	FRODO1344AES, FRODO1344SHAKE,
+}
+
+// SHA-256 of the PQCkemKAT_*.rsp files of the FrodoKEM submission. Sets
+// without recorded hash are regenerated, but not compared.
+var katHashes = map[uint8]string{
+	// https://github.com/microsoft/PQCrypto-LWEKE/blob/66fc7744c3aae6acfc5fcc587ec7f2cdec48d216/KAT/PQCkemKAT_19888_shake.rsp
+	FRODO640SHAKE: "604a10cfc871dfaed9cb5b057c644ab03b16852cea7f39bc7f9831513b5b1cfa",
+}
+
+// Regenerates the PQCkemKAT_*.rsp file of the FrodoKEM submission (100
+// test cases generated by NIST's CTR_DRBG based randombytes) and compares
+// its SHA-256 with the hash of the original file.
+func TestPQCgenKATKem(t *testing.T) {
+	for _, id := range ids {
+		id := id
+		t.Run(Params(id).Name, func(t *testing.T) {
+			want, ok := katHashes[id]
+			got := genKATKem(t, id)
+			if !ok {
+				t.Skipf("KAT hash not recorded, got %s", got)
+			}
+			if got != want {
+				t.Errorf("KAT hash %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+// Returns hex encoded SHA-256 of the regenerated KAT file
+func genKATKem(t *testing.T, id uint8) string {
+	params := Params(id)
+	var seed [48]byte
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	g := drbg.NewCtrDrbg()
+	g.Init(seed[:], nil)
+
+	f := sha256.New()
+	fmt.Fprintf(f, "# %s\n\n", params.Name)
+	for i := 0; i < 100; i++ {
+		g.Read(seed[:])
+		fmt.Fprintf(f, "count = %d\n", i)
+		fmt.Fprintf(f, "seed = %X\n", seed)
+
+		g2 := drbg.NewCtrDrbg()
+		g2.Init(seed[:], nil)
+		kseed := make([]byte, params.KeySeedSize)
+		mu := make([]byte, params.MessageSize)
+		g2.Read(kseed)
+		g2.Read(mu)
+
+		pub, prv, err := KeyGen(id, kseed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ct, ss, err := Encaps(pub, mu)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ss2, err := Decapsulate(prv, ct)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(ss, ss2) {
+			t.Fatalf("%s: shared secrets differ", params.Name)
+		}
+		fmt.Fprintf(f, "pk = %X\n", pub.Export())
+		fmt.Fprintf(f, "sk = %X\n", prv.Export())
+		fmt.Fprintf(f, "ct = %X\n", ct)
+		fmt.Fprintf(f, "ss = %X\n\n", ss)
+	}
+	return hex.EncodeToString(f.Sum(nil))
+}
+
+func TestParams(t *testing.T) {
+	// Sizes from table 3 of the specification
+	for _, v := range []struct {
+		id             uint8
+		pk, sk, ct, ss int
+	}{
+		{FRODO640AES, 9616, 19888, 9720, 16},
+		{FRODO976SHAKE, 15632, 31296, 15744, 24},
+		{FRODO1344AES, 21520, 43088, 21632, 32},
+	} {
+		p := Params(v.id)
+		if p.PublicKeySize != v.pk || p.PrivateKeySize != v.sk ||
+			p.CiphertextSize != v.ct || p.SharedSecretSize != v.ss {
+			t.Errorf("%s: unexpected parameters %+v", p.Name, p)
+		}
+	}
+}
+
+func TestKEMRoundTrip(t *testing.T) {
+	for _, id := range ids {
+		params := Params(id)
+		pub, prv, err := GenerateKeyPair(rand.Reader, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ct, ss1, err := Encapsulate(rand.Reader, pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ct) != params.CiphertextSize || len(ss1) != params.SharedSecretSize {
+			t.Fatalf("%s: wrong output size", params.Name)
+		}
+		ss2, err := Decapsulate(prv, ct)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(ss1, ss2) {
+			t.Errorf("%s: shared secrets differ", params.Name)
+		}
+
+		// Modified ciphertext gives implicitly rejected, different secret
+		for _, pos := range []int{0, len(ct) - 1} {
+			ct[pos] ^= 1
+			ss3, err := Decapsulate(prv, ct)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.Equal(ss1, ss3) {
+				t.Errorf("%s: modified ciphertext accepted", params.Name)
+			}
+			ct[pos] ^= 1
+		}
+		if _, err = Decapsulate(prv, ct[1:]); err == nil {
+			t.Errorf("%s: expected error for wrong ciphertext size", params.Name)
+		}
+	}
+}
+
+func TestImportExport(t *testing.T) {
+	for _, id := range ids {
+		params := Params(id)
+		pub1, prv1, err := GenerateKeyPair(rand.Reader, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub2, prv2 := NewPublicKey(id), NewPrivateKey(id)
+
+		pk, sk := pub1.Export(), prv1.Export()
+		if len(pk) != pub1.Size() || len(sk) != prv1.Size() {
+			t.Fatalf("%s: wrong key size", params.Name)
+		}
+		if err = pub2.Import(pk); err != nil {
+			t.Fatal(err)
+		}
+		if err = prv2.Import(sk); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(pub2.Export(), pk) || !bytes.Equal(prv2.Export(), sk) {
+			t.Errorf("%s: import/export failed", params.Name)
+		}
+
+		// Imported keys interoperate with the original ones
+		ct, ss1, err := Encapsulate(rand.Reader, pub2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ss2, err := Decapsulate(prv2, ct)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(ss1, ss2) {
+			t.Errorf("%s: shared secrets differ", params.Name)
+		}
+
+		if pub2.Import(pk[1:]) == nil || prv2.Import(sk[1:]) == nil {
+			t.Errorf("%s: expected error for wrong key size", params.Name)
+		}
+		// Corrupted pkh fails hash check
+		sk[len(sk)-1] ^= 1
+		if prv2.Import(sk) == nil {
+			t.Errorf("%s: private key with wrong hash accepted", params.Name)
+		}
+	}
+}
+
+func TestDeterministicInputSize(t *testing.T) {
+	params := Params(FRODO640SHAKE)
+	seed := make([]byte, params.KeySeedSize)
+	if _, _, err := KeyGen(params.Id, seed[1:]); err == nil {
+		t.Error("expected error for wrong seed size")
+	}
+	pub, _, err := KeyGen(params.Id, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = Encaps(pub, seed[:params.MessageSize-1]); err == nil {
+		t.Error("expected error for wrong message size")
+	}
+}
+
+func TestPackEncode(t *testing.T) {
+	for _, id := range []uint8{FRODO640AES, FRODO976AES, FRODO1344AES} {
+		params := Params(id)
+		in := make([]uint16, nbar*nbar)
+		for i := range in {
+			in[i] = uint16(i*0x1357) & (1<<params.D - 1)
+		}
+		buf := make([]byte, int(params.D)*len(in)/8)
+		out := make([]uint16, len(in))
+		params.pack(buf, in)
+		params.unpack(out, buf)
+		for i := range in {
+			if in[i] != out[i] {
+				t.Fatalf("%s: unpack(pack(x)) differs at %d", params.Name, i)
+			}
+		}
+
+		msg := make([]byte, params.MessageSize)
+		dec := make([]byte, params.MessageSize)
+		rand.Read(msg)
+		params.encode(out, msg)
+		// Add small error to each coefficient
+		for i := range out {
+			out[i] += uint16(i%7) - 3
+		}
+		params.decode(dec, out)
+		if !bytes.Equal(msg, dec) {
+			t.Errorf("%s: decode(encode(x)) differs", params.Name)
+		}
+	}
+}
+
+func BenchmarkKeyGen(b *testing.B) {
+	for _, id := range ids {
+		b.Run(Params(id).Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _, _ = GenerateKeyPair(rand.Reader, id)
+			}
+		})
+	}
+}
+
+func BenchmarkEncapsulate(b *testing.B) {
+	for _, id := range ids {
+		pub, _, _ := GenerateKeyPair(rand.Reader, id)
+		b.Run(Params(id).Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _, _ = Encapsulate(rand.Reader, pub)
+			}
+		})
+	}
+}
+
+func BenchmarkDecapsulate(b *testing.B) {
+	for _, id := range ids {
+		pub, prv, _ := GenerateKeyPair(rand.Reader, id)
+		ct, _, _ := Encapsulate(rand.Reader, pub)
+		b.Run(Params(id).Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = Decapsulate(prv, ct)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package frodo
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+
+	"github.com/henrydcase/nobs/cipher/aes"
+	"github.com/henrydcase/nobs/hash/sha3"
+)
+
+// Generates matrix A from seedA one row at a time, so that the whole
+// n x n matrix never needs to be kept in memory.
+type matrixA struct {
+	n     int
+	block cipher.Block
+	xof   sha3.ShakeHash
+	seed  [2 + seedASize]byte
+	buf   []byte
+}
+
+func newMatrixA(params *FrodoParams, seedA []byte) *matrixA {
+	a := &matrixA{n: params.N}
+	if params.Aes {
+		// Key has correct size, error not possible
+		a.block, _ = aes.New(seedA)
+		a.buf = make([]byte, 16)
+	} else {
+		a.xof = sha3.NewShake128()
+		copy(a.seed[2:], seedA)
+		a.buf = make([]byte, 2*params.N)
+	}
+	return a
+}
+
+// Computes i-th row of A. Entries are not reduced mod q.
+func (a *matrixA) row(out []uint16, i int) {
+	if a.block != nil {
+		// A[i][j..j+7] = AES128(seedA, i || j || 0...)
+		var in [16]byte
+		binary.LittleEndian.PutUint16(in[0:], uint16(i))
+		for j := 0; j < a.n; j += 8 {
+			binary.LittleEndian.PutUint16(in[2:], uint16(j))
+			a.block.Encrypt(a.buf, in[:])
+			for k := 0; k < 8; k++ {
+				out[j+k] = binary.LittleEndian.Uint16(a.buf[2*k:])
+			}
+		}
+		return
+	}
+	// A[i] = SHAKE128(i || seedA, 16n)
+	binary.LittleEndian.PutUint16(a.seed[:], uint16(i))
+	a.xof.Reset()
+	a.xof.Write(a.seed[:])
+	a.xof.Read(a.buf)
+	for j := range out[:a.n] {
+		out[j] = binary.LittleEndian.Uint16(a.buf[2*j:])
+	}
+}
+
+// Computes B = A*S + E, where sT stores transposition of S. B and E are
+// n x nbar matrices. Result is not reduced mod q.
+func (a *matrixA) mulAddAS(b, sT, e []uint16) {
+	n := a.n
+	row := make([]uint16, n)
+	for i := 0; i < n; i++ {
+		a.row(row, i)
+		for k := 0; k < nbar; k++ {
+			sum := e[i*nbar+k]
+			s := sT[k*n : (k+1)*n]
+			for j := range row {
+				sum += row[j] * s[j]
+			}
+			b[i*nbar+k] = sum
+		}
+	}
+}
+
+// Computes B' = S'*A + E', where B', S' and E' are nbar x n matrices.
+// Result is not reduced mod q.
+func (a *matrixA) mulAddSA(b, s, e []uint16) {
+	n := a.n
+	row := make([]uint16, n)
+	copy(b, e[:nbar*n])
+	for j := 0; j < n; j++ {
+		a.row(row, j)
+		for k := 0; k < nbar; k++ {
+			sk := s[k*n+j]
+			bk := b[k*n : (k+1)*n]
+			for i := range row {
+				bk[i] += sk * row[i]
+			}
+		}
+	}
+}
+
+// Computes V = S'*B + E, where S' is nbar x n, B is n x nbar and
+// V, E are nbar x nbar matrices. Result is not reduced mod q.
+func mulAddSB(v, s, b, e []uint16, n int) {
+	for k := 0; k < nbar; k++ {
+		for i := 0; i < nbar; i++ {
+			sum := e[k*nbar+i]
+			for j := 0; j < n; j++ {
+				sum += s[k*n+j] * b[j*nbar+i]
+			}
+			v[k*nbar+i] = sum
+		}
+	}
+}
+
+// Computes W = B'*S, where B' is nbar x n and sT stores transposition
+// of S. Result is not reduced mod q.
+func mulBS(w, b, sT []uint16, n int) {
+	for i := 0; i < nbar; i++ {
+		for j := 0; j < nbar; j++ {
+			var sum uint16
+			for k := 0; k < n; k++ {
+				sum += b[i*n+k] * sT[j*n+k]
+			}
+			w[i*nbar+j] = sum
+		}
+	}
+}
+
+// Turns uniformly random 16-bit values into samples from the error
+// distribution by inversion sampling. Constant time.
+func (params *FrodoParams) sample(r []uint16) {
+	for i := range r {
+		var e uint16
+		sign := r[i] & 1
+		u := r[i] >> 1
+		for _, c := range params.cdf[:len(params.cdf)-1] {
+			e += (c - u) >> 15
+		}
+		// Negate e if sign is set
+		r[i] = (-sign ^ e) + sign
+	}
+}
+
+// Reads 16-bit little-endian values from xof into out
+func readU16(xof sha3.ShakeHash, out []uint16) {
+	buf := make([]byte, 2*len(out))
+	xof.Read(buf)
+	for i := range out {
+		out[i] = binary.LittleEndian.Uint16(buf[2*i:])
+	}
+}
+
+// Packs coefficients as D-bit values, most significant bit first.
+// Coefficients are reduced mod q.
+func (params *FrodoParams) pack(out []byte, in []uint16) {
+	var acc uint32
+	var bits uint
+	d := params.D
+	j := 0
+	for _, v := range in {
+		acc = acc<<d | uint32(v)&(1<<d-1)
+		bits += d
+		for bits >= 8 {
+			bits -= 8
+			out[j] = byte(acc >> bits)
+			j++
+		}
+	}
+}
+
+// Inverse of pack
+func (params *FrodoParams) unpack(out []uint16, in []byte) {
+	var acc uint32
+	var bits uint
+	d := params.D
+	j := 0
+	for _, b := range in {
+		acc = acc<<8 | uint32(b)
+		bits += 8
+		if bits >= d {
+			bits -= d
+			out[j] = uint16((acc >> bits) & (1<<d - 1))
+			j++
+		}
+	}
+}
+
+// Encodes message as nbar x nbar matrix. Each coefficient holds B bits
+// of the message (read least significant bit first) in its most
+// significant bits.
+func (params *FrodoParams) encode(out []uint16, msg []byte) {
+	var acc uint32
+	var bits uint
+	b, d := params.B, params.D
+	j := 0
+	for _, m := range msg {
+		acc |= uint32(m) << bits
+		bits += 8
+		for bits >= b {
+			out[j] = uint16(acc&(1<<b-1)) << (d - b)
+			acc >>= b
+			bits -= b
+			j++
+		}
+	}
+}
+
+// Inverse of encode. Rounds each coefficient to the nearest multiple
+// of q/2^B. Constant time.
+func (params *FrodoParams) decode(out []byte, in []uint16) {
+	var acc uint32
+	var bits uint
+	b, d := params.B, params.D
+	j := 0
+	for _, c := range in {
+		v := (c + 1<<(d-b-1)) >> (d - b)
+		acc |= uint32(v&(1<<b-1)) << bits
+		bits += b
+		for bits >= 8 {
+			out[j] = byte(acc)
+			acc >>= 8
+			bits -= 8
+			j++
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package frodo
+
+import "github.com/henrydcase/nobs/utils/security"
+
+// Id's of the FrodoKEM parameter sets
+const (
+	FRODO640AES uint8 = iota
+	FRODO640SHAKE
+	FRODO976AES
+	FRODO976SHAKE
+	FRODO1344AES
+	FRODO1344SHAKE
+	maxParamsId
+)
+
+const (
+	// Dimension nbar of the matrices S, E and of the encoded message
+	nbar = 8
+	// Size of seedA and z
+	seedASize = 16
+)
+
+// Domain parameters of FrodoKEM
+type FrodoParams struct {
+	Id   uint8
+	Name string
+	// Dimension of the matrix A
+	N int
+	// Modulus is q = 2^D
+	D uint
+	// Number of bits encoded in each coefficient of the message matrix
+	B uint
+	// Matrix A is generated with AES-128 if true, otherwise with SHAKE128
+	Aes bool
+	// Cumulative distribution table of the error distribution
+	cdf []uint16
+	// Size of s, seedSE, mu, pkh, k and the shared secret. Called
+	// len_sec in the specification.
+	secSize int
+	// Sizes in bytes
+	KeySeedSize      int
+	MessageSize      int
+	SharedSecretSize int
+	PublicKeySize    int
+	PrivateKeySize   int
+	CiphertextSize   int
+	// Security properties
+	Meta security.Metadata
+}
+
+// Keeps mapping: parameter set ID to domain parameters
+var frodoParams = make(map[uint8]FrodoParams)
+
+// Params returns domain parameters identified by `id`. Function panics
+// in case `id` wasn't registered earlier.
+func Params(id uint8) *FrodoParams {
+	if val, ok := frodoParams[id]; ok {
+		return &val
+	}
+	panic("frodo: FrodoKEM Params ID unregistered")
+}
+
+func newParams(id uint8, name string, level, n int, d, b uint, aes bool, cdf []uint16) FrodoParams {
+	sec := int(b) * nbar * nbar / 8
+	packedB := int(d) * n * nbar / 8
+	return FrodoParams{
+		Id:               id,
+		Name:             name,
+		N:                n,
+		D:                d,
+		B:                b,
+		Aes:              aes,
+		cdf:              cdf,
+		secSize:          sec,
+		KeySeedSize:      2*sec + seedASize,
+		MessageSize:      sec,
+		SharedSecretSize: sec,
+		PublicKeySize:    seedASize + packedB,
+		PrivateKeySize:   sec + seedASize + packedB + 2*n*nbar + sec,
+		CiphertextSize:   packedB + int(d)*nbar*nbar/8,
+		Meta: security.Metadata{
+			Name:      name,
+			Level:     level,
+			Status:    security.Candidate,
+			Reference: "https://frodokem.org/files/FrodoKEM-specification-20210604.pdf",
+		},
+	}
+}
+
+func init() {
+	cdf640 := []uint16{4643, 13363, 20579, 25843, 29227, 31145, 32103, 32525, 32689, 32745, 32762, 32766, 32767}
+	cdf976 := []uint16{5638, 15915, 23689, 28571, 31116, 32217, 32613, 32731, 32760, 32766, 32767}
+	cdf1344 := []uint16{9142, 23462, 30338, 32361, 32725, 32765, 32767}
+
+	frodoParams[FRODO640AES] = newParams(FRODO640AES, "FrodoKEM-640-AES", 1, 640, 15, 2, true, cdf640)
+	frodoParams[FRODO640SHAKE] = newParams(FRODO640SHAKE, "FrodoKEM-640-SHAKE", 1, 640, 15, 2, false, cdf640)
+	frodoParams[FRODO976AES] = newParams(FRODO976AES, "FrodoKEM-976-AES", 3, 976, 16, 3, true, cdf976)
+	frodoParams[FRODO976SHAKE] = newParams(FRODO976SHAKE, "FrodoKEM-976-SHAKE", 3, 976, 16, 3, false, cdf976)
+	frodoParams[FRODO1344AES] = newParams(FRODO1344AES, "FrodoKEM-1344-AES", 5, 1344, 16, 4, true, cdf1344)
+	frodoParams[FRODO1344SHAKE] = newParams(FRODO1344SHAKE, "FrodoKEM-1344-SHAKE", 5, 1344, 16, 4, false, cdf1344)
+}
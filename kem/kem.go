@@ -0,0 +1,61 @@
+// Package kem provides a common interface to the key encapsulation
+// mechanisms implemented in subpackages and a registry of all of them,
+// together with their security properties.
+//
+// Keys and ciphertexts are passed as byte strings in the format of the
+// underlying implementation. Private key of SIKE is s || sk || pk, as in
+// the NIST submission.
+//
+// SIKE is broken and its Scheme returns an error matching
+// security.ErrInsecure, unless use of broken algorithms was enabled (see
+// package utils/security).
+package kem
+
+import (
+	"io"
+
+	"github.com/henrydcase/nobs/utils/security"
+)
+
+// Scheme is a key encapsulation mechanism with fixed parameters
+type Scheme interface {
+	// Name of the scheme, same as Meta().Name
+	Name() string
+	// Security properties of the scheme
+	Meta() security.Metadata
+	// Sizes in bytes
+	PublicKeySize() int
+	PrivateKeySize() int
+	CiphertextSize() int
+	SharedSecretSize() int
+	// GenerateKeyPair returns exported public and private key. The rng
+	// must be cryptographically secure PRNG.
+	GenerateKeyPair(rng io.Reader) (pk, sk []byte, err error)
+	// Encapsulate generates ciphertext and shared secret for the public
+	// key pk. The rng must be cryptographically secure PRNG.
+	Encapsulate(rng io.Reader, pk []byte) (ct, ss []byte, err error)
+	// Decapsulate returns shared secret encapsulated in ct
+	Decapsulate(sk, ct []byte) (ss []byte, err error)
+}
+
+// Registered schemes, in order of registration
+var schemes []Scheme
+
+func register(s Scheme) {
+	schemes = append(schemes, s)
+}
+
+// All returns all registered schemes, including the broken ones
+func All() []Scheme {
+	return append([]Scheme(nil), schemes...)
+}
+
+// ByName returns scheme with given name or nil if there is no such scheme
+func ByName(name string) Scheme {
+	for _, s := range schemes {
+		if s.Name() == name {
+			return s
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package kem
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/henrydcase/nobs/utils/security"
+)
+
+func TestRoundTrip(t *testing.T) {
+	security.AllowInsecure(true)
+	defer security.AllowInsecure(false)
+
+	for _, s := range All() {
+		if testing.Short() && s.Meta().Status == security.Broken {
+			continue
+		}
+		pk, sk, err := s.GenerateKeyPair(rand.Reader)
+		if err != nil {
+			t.Fatalf("%s: %v", s.Name(), err)
+		}
+		if len(pk) != s.PublicKeySize() || len(sk) != s.PrivateKeySize() {
+			t.Fatalf("%s: wrong key size", s.Name())
+		}
+		ct, ss1, err := s.Encapsulate(rand.Reader, pk)
+		if err != nil {
+			t.Fatalf("%s: %v", s.Name(), err)
+		}
+		if len(ct) != s.CiphertextSize() || len(ss1) != s.SharedSecretSize() {
+			t.Fatalf("%s: wrong output size", s.Name())
+		}
+		ss2, err := s.Decapsulate(sk, ct)
+		if err != nil {
+			t.Fatalf("%s: %v", s.Name(), err)
+		}
+		if !bytes.Equal(ss1, ss2) {
+			t.Errorf("%s: shared secrets differ", s.Name())
+		}
+		if _, err = s.Decapsulate(sk[1:], ct); err == nil {
+			t.Errorf("%s: expected error for wrong private key size", s.Name())
+		}
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	for _, v := range []struct {
+		name   string
+		level  int
+		status security.Status
+	}{
+		{"ML-KEM-768", 3, security.Standardized},
+		{"FrodoKEM-1344-AES", 5, security.Candidate},
+		{"SIKEp503", 2, security.Broken},
+		{"SIKEp751", 5, security.Broken},
+	} {
+		s := ByName(v.name)
+		if s == nil {
+			t.Fatalf("%s not registered", v.name)
+		}
+		m := s.Meta()
+		if m.Name != v.name || m.Level != v.level || m.Status != v.status || m.Reference == "" {
+			t.Errorf("%s: unexpected metadata %+v", v.name, m)
+		}
+	}
+	if ByName("SIKEp434") != nil {
+		t.Error("unexpected scheme")
+	}
+}
+
+func TestInsecureRefused(t *testing.T) {
+	security.AllowInsecure(false)
+	for _, s := range All() {
+		if s.Meta().Status != security.Broken {
+			continue
+		}
+		if _, _, err := s.GenerateKeyPair(rand.Reader); !errors.Is(err, security.ErrInsecure) {
+			t.Errorf("%s: expected ErrInsecure, got %v", s.Name(), err)
+		}
+		pk := make([]byte, s.PublicKeySize())
+		if _, _, err := s.Encapsulate(rand.Reader, pk); !errors.Is(err, security.ErrInsecure) {
+			t.Errorf("%s: expected ErrInsecure, got %v", s.Name(), err)
+		}
+	}
+}
+
+// Private key of SIKE contains the public key, which must match
+func TestSikeKeyMismatch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("SIKE is slow")
+	}
+	security.AllowInsecure(true)
+	defer security.AllowInsecure(false)
+
+	s := ByName("SIKEp503")
+	pk1, sk, err := s.GenerateKeyPair(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk2, _, err := s.GenerateKeyPair(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct, _, err := s.Encapsulate(rand.Reader, pk1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copy(sk[len(sk)-len(pk2):], pk2)
+	if _, err = s.Decapsulate(sk, ct); err == nil {
+		t.Error("expected error for mismatched public key")
+	}
+}
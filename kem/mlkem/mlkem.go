@@ -0,0 +1,344 @@
+// Package mlkem implements ML-KEM, the module-lattice-based key
+// encapsulation mechanism standardized in FIPS 203, with parameter sets
+// ML-KEM-512, ML-KEM-768 and ML-KEM-1024.
+//
+// [FIPS203] https://doi.org/10.6028/NIST.FIPS.203
+package mlkem
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+
+	"github.com/henrydcase/nobs/hash/sha3"
+)
+
+// Defines operations on public (encapsulation) key
+type PublicKey struct {
+	params *MlkemParams
+	// t in NTT domain
+	t   []poly
+	rho [32]byte
+	// H(ek), cached
+	h [32]byte
+	// Transposed matrix A in NTT domain, expanded from rho
+	aT []poly
+}
+
+// Defines operations on private (decapsulation) key
+type PrivateKey struct {
+	params *MlkemParams
+	// s in NTT domain
+	s   []poly
+	pub PublicKey
+	// Implicit rejection value
+	z [32]byte
+}
+
+// NewPrivateKey initializes private key.
+// Usage of this function guarantees that the object is correctly initialized.
+func NewPrivateKey(id uint8) *PrivateKey {
+	params := Params(id)
+	return &PrivateKey{
+		params: params,
+		s:      make([]poly, params.K),
+		pub:    *NewPublicKey(id),
+	}
+}
+
+// NewPublicKey initializes public key.
+// Usage of this function guarantees that the object is correctly initialized.
+func NewPublicKey(id uint8) *PublicKey {
+	params := Params(id)
+	return &PublicKey{
+		params: params,
+		t:      make([]poly, params.K),
+		aT:     make([]poly, params.K*params.K),
+	}
+}
+
+// Accessor to the domain parameters
+func (pub *PublicKey) Params() *MlkemParams {
+	return pub.params
+}
+
+// Accessor to the domain parameters
+func (prv *PrivateKey) Params() *MlkemParams {
+	return prv.params
+}
+
+// Public returns public key corresponding to the private key
+func (prv *PrivateKey) Public() *PublicKey {
+	return &prv.pub
+}
+
+// Size returns size of the public key in bytes
+func (pub *PublicKey) Size() int {
+	return pub.params.PublicKeySize
+}
+
+// Size returns size of the private key in bytes
+func (prv *PrivateKey) Size() int {
+	return prv.params.PrivateKeySize
+}
+
+// Exports public key as ByteEncode12(t) || rho
+func (pub *PublicKey) Export() []byte {
+	out := make([]byte, pub.params.PublicKeySize)
+	for i := range pub.t {
+		pub.t[i].encode(out[i*encodedPolySize:], 12)
+	}
+	copy(out[len(pub.t)*encodedPolySize:], pub.rho[:])
+	return out
+}
+
+// Import clears content of the public key and imports key stored in the
+// byte string. Returns error in case of wrong size or if the key fails
+// modulus check from section 7.2 of FIPS 203, in which case the key is
+// not modified.
+func (pub *PublicKey) Import(input []byte) error {
+	if len(input) != pub.params.PublicKeySize {
+		return errors.New("mlkem: wrong size of the public key")
+	}
+	t := make([]poly, len(pub.t))
+	for i := range t {
+		if !t[i].decode(input[i*encodedPolySize:], 12) {
+			return errors.New("mlkem: public key not reduced")
+		}
+	}
+	copy(pub.t, t)
+	copy(pub.rho[:], input[len(pub.t)*encodedPolySize:])
+	pub.h = sha3.Sum256(input)
+	pub.expandA()
+	return nil
+}
+
+// Exports private key as ByteEncode12(s) || ek || H(ek) || z
+func (prv *PrivateKey) Export() []byte {
+	out := make([]byte, 0, prv.params.PrivateKeySize)
+	sz := len(prv.s) * encodedPolySize
+	out = out[:sz]
+	for i := range prv.s {
+		prv.s[i].encode(out[i*encodedPolySize:], 12)
+	}
+	out = append(out, prv.pub.Export()...)
+	out = append(out, prv.pub.h[:]...)
+	return append(out, prv.z[:]...)
+}
+
+// Import clears content of the private key and imports key stored in the
+// byte string. Returns error in case of wrong size or if the key fails
+// hash check from section 7.3 of FIPS 203, in which case the key is not
+// modified.
+func (prv *PrivateKey) Import(input []byte) error {
+	if len(input) != prv.params.PrivateKeySize {
+		return errors.New("mlkem: wrong size of the private key")
+	}
+	sz := len(prv.s) * encodedPolySize
+	ek := input[sz : sz+prv.params.PublicKeySize]
+	h := input[sz+prv.params.PublicKeySize : len(input)-32]
+	pub := NewPublicKey(prv.params.Id)
+	if err := pub.Import(ek); err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(h, pub.h[:]) != 1 {
+		return errors.New("mlkem: private key hash check failed")
+	}
+	prv.pub = *pub
+	for i := range prv.s {
+		prv.s[i].decode(input[i*encodedPolySize:], 12)
+	}
+	copy(prv.z[:], input[len(input)-32:])
+	return nil
+}
+
+// Expands matrix A from rho. Stores transposition of A, as only A^T is
+// needed for encryption. Element aT[i*k+j] = A[j][i] = SampleNTT(rho||i||j).
+func (pub *PublicKey) expandA() {
+	k := pub.params.K
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			pub.aT[i*k+j].sampleNtt(pub.rho[:], byte(i), byte(j))
+		}
+	}
+}
+
+// K-PKE.KeyGen and ML-KEM.KeyGen_internal (algorithms 13 and 16 of FIPS 203)
+func (prv *PrivateKey) generate(d, z []byte) {
+	var e poly
+	var g [64]byte
+	pub := &prv.pub
+	k := prv.params.K
+
+	h := sha3.New512()
+	h.Write(d)
+	h.Write([]byte{byte(k)})
+	h.Sum(g[:0])
+	rho, sigma := g[:32], g[32:]
+	copy(pub.rho[:], rho)
+	copy(prv.z[:], z)
+	pub.expandA()
+
+	for i := range prv.s {
+		prv.s[i].sampleCbd(sigma, byte(i), prv.params.Eta1)
+		prv.s[i].ntt()
+	}
+	// t = A*s + e. A[i][j] = aT[j*k+i]
+	for i := range pub.t {
+		pub.t[i] = poly{}
+		for j := range prv.s {
+			pub.t[i].mulAcc(&pub.aT[j*k+i], &prv.s[j])
+		}
+		e.sampleCbd(sigma, byte(k+i), prv.params.Eta1)
+		e.ntt()
+		pub.t[i].add(&pub.t[i], &e)
+	}
+	pub.h = sha3.Sum256(pub.Export())
+}
+
+// K-PKE.Encrypt (algorithm 14 of FIPS 203). Writes ciphertext to out.
+func (pub *PublicKey) encrypt(out, m, r []byte) {
+	var e, v, mu poly
+	params := pub.params
+	k := params.K
+	y := make([]poly, k)
+
+	for i := range y {
+		y[i].sampleCbd(r, byte(i), params.Eta1)
+		y[i].ntt()
+	}
+
+	// u = InvNTT(A^T * y) + e1
+	polySize := 32 * int(params.Du)
+	for i := 0; i < k; i++ {
+		var u poly
+		for j := 0; j < k; j++ {
+			u.mulAcc(&pub.aT[i*k+j], &y[j])
+		}
+		u.invNtt()
+		e.sampleCbd(r, byte(k+i), params.Eta2)
+		u.add(&u, &e)
+		u.encode(out[i*polySize:], params.Du)
+	}
+
+	// v = InvNTT(t^T * y) + e2 + Decompress1(m)
+	for i := 0; i < k; i++ {
+		v.mulAcc(&pub.t[i], &y[i])
+	}
+	v.invNtt()
+	e.sampleCbd(r, byte(2*k), params.Eta2)
+	v.add(&v, &e)
+	mu.decode(m, 1)
+	v.add(&v, &mu)
+	v.encode(out[k*polySize:], params.Dv)
+}
+
+// K-PKE.Decrypt (algorithm 15 of FIPS 203). Writes message to m.
+func (prv *PrivateKey) decrypt(m, ct []byte) {
+	var u, v, w poly
+	params := prv.params
+	polySize := 32 * int(params.Du)
+
+	// w = v - InvNTT(s^T * NTT(u))
+	for i := range prv.s {
+		u.decode(ct[i*polySize:], params.Du)
+		u.ntt()
+		w.mulAcc(&prv.s[i], &u)
+	}
+	w.invNtt()
+	v.decode(ct[params.K*polySize:], params.Dv)
+	w.sub(&v, &w)
+	w.encode(m, 1)
+}
+
+// KeyGen derives key pair deterministically from seeds d and z
+// (ML-KEM.KeyGen_internal, algorithm 16 of FIPS 203). Both seeds must
+// have SeedSize bytes. Intended for testing, use GenerateKeyPair otherwise.
+func KeyGen(id uint8, d, z []byte) (*PublicKey, *PrivateKey, error) {
+	if len(d) != SeedSize || len(z) != SeedSize {
+		return nil, nil, errors.New("mlkem: wrong size of the seed")
+	}
+	prv := NewPrivateKey(id)
+	prv.generate(d, z)
+	return prv.Public(), prv, nil
+}
+
+// GenerateKeyPair generates random key pair for parameter set given by id.
+// The rng must be cryptographically secure PRNG. Error is returned in
+// case PRNG fails.
+func GenerateKeyPair(rng io.Reader, id uint8) (*PublicKey, *PrivateKey, error) {
+	var seed [2 * SeedSize]byte
+	if _, err := io.ReadFull(rng, seed[:]); err != nil {
+		return nil, nil, err
+	}
+	return KeyGen(id, seed[:SeedSize], seed[SeedSize:])
+}
+
+// Encaps computes ciphertext and shared secret deterministically from the
+// message m of MessageSize bytes (ML-KEM.Encaps_internal, algorithm 17 of
+// FIPS 203). Intended for testing, use Encapsulate otherwise.
+func Encaps(pub *PublicKey, m []byte) (ctext []byte, secret []byte, err error) {
+	var g [64]byte
+	if len(m) != MessageSize {
+		return nil, nil, errors.New("mlkem: wrong size of the message")
+	}
+
+	// (K, r) = G(m || H(ek))
+	h := sha3.New512()
+	h.Write(m)
+	h.Write(pub.h[:])
+	h.Sum(g[:0])
+
+	ctext = make([]byte, pub.params.CiphertextSize)
+	pub.encrypt(ctext, m, g[32:])
+	return ctext, g[:32], nil
+}
+
+// Encapsulate receives the public key and generates ciphertext and shared
+// secret. The rng must be cryptographically secure PRNG. Error is returned
+// in case PRNG fails.
+func Encapsulate(rng io.Reader, pub *PublicKey) (ctext []byte, secret []byte, err error) {
+	var m [MessageSize]byte
+	if _, err = io.ReadFull(rng, m[:]); err != nil {
+		return nil, nil, err
+	}
+	return Encaps(pub, m[:])
+}
+
+// Decapsulate given the private key and ciphertext outputs a shared secret
+// (ML-KEM.Decaps_internal, algorithm 18 of FIPS 203). In case ciphertext
+// is invalid, pseudorandom value derived from z and ciphertext is returned
+// (implicit rejection). Error is returned only if ciphertext has wrong size.
+// Constant time.
+func Decapsulate(prv *PrivateKey, ctext []byte) ([]byte, error) {
+	var m [MessageSize]byte
+	var g [64]byte
+	var kBar [SharedSecretSize]byte
+	params := prv.params
+
+	if len(ctext) != params.CiphertextSize {
+		return nil, errors.New("mlkem: wrong size of the ciphertext")
+	}
+
+	prv.decrypt(m[:], ctext)
+
+	// (K', r') = G(m' || h)
+	h := sha3.New512()
+	h.Write(m[:])
+	h.Write(prv.pub.h[:])
+	h.Sum(g[:0])
+
+	// K_bar = J(z || c)
+	j := sha3.NewShake256()
+	j.Write(prv.z[:])
+	j.Write(ctext)
+	j.Read(kBar[:])
+
+	ct := make([]byte, params.CiphertextSize)
+	prv.pub.encrypt(ct, m[:], g[32:])
+
+	secret := g[:32]
+	eq := subtle.ConstantTimeCompare(ct, ctext)
+	subtle.ConstantTimeCopy(1-eq, secret, kBar[:])
+	return secret, nil
+}
@@ -0,0 +1,319 @@
+package mlkem
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+var ids = []uint8{MLKEM512, MLKEM768, MLKEM1024}
+
+// []byte encoded as hex string in JSON
+type hexBytes []byte
+
+func (b *hexBytes) UnmarshalJSON(data []byte) (err error) {
+	var s string
+	if err = json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*b, err = hex.DecodeString(s)
+	return err
+}
+
+// ACVP test vector files have a common structure, test groups with test
+// cases identified by tcId. Expected results are kept in a separate file.
+type acvpGroup struct {
+	TestType     string          `json:"testType"`
+	ParameterSet string          `json:"parameterSet"`
+	Dk           hexBytes        `json:"dk"`
+	Tests        json.RawMessage `json:"tests"`
+}
+
+type acvpTest struct {
+	TcID int      `json:"tcId"`
+	Z    hexBytes `json:"z"`
+	D    hexBytes `json:"d"`
+	Ek   hexBytes `json:"ek"`
+	Dk   hexBytes `json:"dk"`
+	M    hexBytes `json:"m"`
+	C    hexBytes `json:"c"`
+	K    hexBytes `json:"k"`
+}
+
+func readGzipJSON(t *testing.T, path string, v interface{}) {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = json.NewDecoder(r).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Returns test groups from prompt file and expected results indexed by tcId
+func loadACVP(t *testing.T, name string) ([]acvpGroup, map[int]acvpTest) {
+	var prompt, results struct {
+		TestGroups []acvpGroup `json:"testGroups"`
+	}
+	readGzipJSON(t, "testdata/"+name+"/prompt.json.gz", &prompt)
+	readGzipJSON(t, "testdata/"+name+"/expectedResults.json.gz", &results)
+
+	expected := make(map[int]acvpTest)
+	for _, g := range results.TestGroups {
+		var tests []acvpTest
+		if err := json.Unmarshal(g.Tests, &tests); err != nil {
+			t.Fatal(err)
+		}
+		for _, tc := range tests {
+			expected[tc.TcID] = tc
+		}
+	}
+	return prompt.TestGroups, expected
+}
+
+func idByName(t *testing.T, name string) uint8 {
+	for _, id := range ids {
+		if Params(id).Name == name {
+			return id
+		}
+	}
+	t.Fatalf("unknown parameter set %s", name)
+	return 0
+}
+
+func TestACVPKeyGen(t *testing.T) {
+	groups, expected := loadACVP(t, "ML-KEM-keyGen-FIPS203")
+	for _, g := range groups {
+		var tests []acvpTest
+		if err := json.Unmarshal(g.Tests, &tests); err != nil {
+			t.Fatal(err)
+		}
+		id := idByName(t, g.ParameterSet)
+		for _, tc := range tests {
+			pub, prv, err := KeyGen(id, tc.D, tc.Z)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(pub.Export(), expected[tc.TcID].Ek) {
+				t.Errorf("tc=%d: ek doesn't match", tc.TcID)
+			}
+			if !bytes.Equal(prv.Export(), expected[tc.TcID].Dk) {
+				t.Errorf("tc=%d: dk doesn't match", tc.TcID)
+			}
+		}
+	}
+}
+
+func TestACVPEncapDecap(t *testing.T) {
+	groups, expected := loadACVP(t, "ML-KEM-encapDecap-FIPS203")
+	for _, g := range groups {
+		var tests []acvpTest
+		if err := json.Unmarshal(g.Tests, &tests); err != nil {
+			t.Fatal(err)
+		}
+		id := idByName(t, g.ParameterSet)
+
+		switch g.TestType {
+		case "AFT":
+			for _, tc := range tests {
+				pub := NewPublicKey(id)
+				if err := pub.Import(tc.Ek); err != nil {
+					t.Fatalf("tc=%d: %v", tc.TcID, err)
+				}
+				ct, ss, err := Encaps(pub, tc.M)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(ct, expected[tc.TcID].C) {
+					t.Errorf("tc=%d: ciphertext doesn't match", tc.TcID)
+				}
+				if !bytes.Equal(ss, expected[tc.TcID].K) {
+					t.Errorf("tc=%d: shared secret doesn't match", tc.TcID)
+				}
+			}
+		case "VAL":
+			prv := NewPrivateKey(id)
+			if err := prv.Import(g.Dk); err != nil {
+				t.Fatal(err)
+			}
+			for _, tc := range tests {
+				ss, err := Decapsulate(prv, tc.C)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(ss, expected[tc.TcID].K) {
+					t.Errorf("tc=%d: shared secret doesn't match", tc.TcID)
+				}
+			}
+		default:
+			t.Fatalf("unknown test type %s", g.TestType)
+		}
+	}
+}
+
+func TestKEMRoundTrip(t *testing.T) {
+	for _, id := range ids {
+		params := Params(id)
+		pub, prv, err := GenerateKeyPair(rand.Reader, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ct, ss1, err := Encapsulate(rand.Reader, pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ct) != params.CiphertextSize || len(ss1) != SharedSecretSize {
+			t.Fatalf("%s: wrong output size", params.Name)
+		}
+		ss2, err := Decapsulate(prv, ct)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(ss1, ss2) {
+			t.Errorf("%s: shared secrets differ", params.Name)
+		}
+
+		// Modified ciphertext gives implicitly rejected, different secret
+		ct[0] ^= 1
+		ss3, err := Decapsulate(prv, ct)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(ss1, ss3) {
+			t.Errorf("%s: modified ciphertext accepted", params.Name)
+		}
+		if _, err = Decapsulate(prv, ct[1:]); err == nil {
+			t.Errorf("%s: expected error for wrong ciphertext size", params.Name)
+		}
+	}
+}
+
+func TestImportExport(t *testing.T) {
+	for _, id := range ids {
+		params := Params(id)
+		pub1, prv1, err := GenerateKeyPair(rand.Reader, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub2, prv2 := NewPublicKey(id), NewPrivateKey(id)
+
+		ek, dk := pub1.Export(), prv1.Export()
+		if len(ek) != pub1.Size() || len(dk) != prv1.Size() {
+			t.Fatalf("%s: wrong key size", params.Name)
+		}
+		if err = pub2.Import(ek); err != nil {
+			t.Fatal(err)
+		}
+		if err = prv2.Import(dk); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(pub2.Export(), ek) || !bytes.Equal(prv2.Export(), dk) {
+			t.Errorf("%s: import/export failed", params.Name)
+		}
+
+		// Coefficient equal to q fails modulus check
+		bad := append([]byte{}, ek...)
+		bad[0], bad[1] = byte(q&0xFF), bad[1]&0xF0|byte(q>>8)
+		if pub2.Import(bad) == nil {
+			t.Errorf("%s: unreduced public key accepted", params.Name)
+		}
+		if pub2.Import(ek[1:]) == nil {
+			t.Errorf("%s: expected error for wrong public key size", params.Name)
+		}
+
+		// Corrupted H(ek) fails hash check
+		bad = append([]byte{}, dk...)
+		bad[len(bad)-33] ^= 1
+		if prv2.Import(bad) == nil {
+			t.Errorf("%s: private key with wrong hash accepted", params.Name)
+		}
+		if prv2.Import(dk[1:]) == nil {
+			t.Errorf("%s: expected error for wrong private key size", params.Name)
+		}
+		// Unreduced ek in the private key
+		bad = append([]byte{}, dk...)
+		sz := params.K * encodedPolySize
+		bad[sz], bad[sz+1] = byte(q&0xFF), bad[sz+1]&0xF0|byte(q>>8)
+		if prv2.Import(bad) == nil {
+			t.Errorf("%s: private key with unreduced ek accepted", params.Name)
+		}
+
+		// Failed imports don't modify keys
+		if !bytes.Equal(pub2.Export(), ek) || !bytes.Equal(prv2.Export(), dk) {
+			t.Errorf("%s: failed import modified the key", params.Name)
+		}
+	}
+}
+
+func TestDeterministicInputSize(t *testing.T) {
+	seed := make([]byte, SeedSize)
+	if _, _, err := KeyGen(MLKEM768, seed[1:], seed); err == nil {
+		t.Error("expected error for wrong seed size")
+	}
+	pub, _, err := KeyGen(MLKEM768, seed, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = Encaps(pub, seed[1:]); err == nil {
+		t.Error("expected error for wrong message size")
+	}
+}
+
+func TestNTT(t *testing.T) {
+	var p, r poly
+	var buf [n]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		t.Fatal(err)
+	}
+	for i := range p {
+		p[i] = barrettReduce(uint32(buf[i]) * 13)
+	}
+	r = p
+	r.ntt()
+	r.invNtt()
+	if r != p {
+		t.Error("InvNTT(NTT(p)) != p")
+	}
+}
+
+func BenchmarkKeyGen(b *testing.B) {
+	for _, id := range ids {
+		b.Run(Params(id).Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				GenerateKeyPair(rand.Reader, id)
+			}
+		})
+	}
+}
+
+func BenchmarkEncapsulate(b *testing.B) {
+	for _, id := range ids {
+		pub, _, _ := GenerateKeyPair(rand.Reader, id)
+		b.Run(Params(id).Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Encapsulate(rand.Reader, pub)
+			}
+		})
+	}
+}
+
+func BenchmarkDecapsulate(b *testing.B) {
+	for _, id := range ids {
+		pub, prv, _ := GenerateKeyPair(rand.Reader, id)
+		ct, _, _ := Encapsulate(rand.Reader, pub)
+		b.Run(Params(id).Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Decapsulate(prv, ct)
+			}
+		})
+	}
+}
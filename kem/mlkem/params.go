@@ -0,0 +1,77 @@
+package mlkem
+
+import "github.com/henrydcase/nobs/utils/security"
+
+// Id's of the parameter sets defined in FIPS 203
+const (
+	MLKEM512 uint8 = iota
+	MLKEM768
+	MLKEM1024
+	maxParamsId
+)
+
+const (
+	// Size of seeds d and z used by KeyGen
+	SeedSize = 32
+	// Size of the message m used by Encaps
+	MessageSize = 32
+	// Size of the shared secret
+	SharedSecretSize = 32
+)
+
+// Domain parameters of ML-KEM
+type MlkemParams struct {
+	Id   uint8
+	Name string
+	// Rank of the module
+	K int
+	// Parameters of the centered binomial distribution
+	Eta1, Eta2 int
+	// Number of bits of compressed ciphertext coefficients
+	Du, Dv uint
+	// Sizes in bytes
+	PublicKeySize  int
+	PrivateKeySize int
+	CiphertextSize int
+	// Security properties
+	Meta security.Metadata
+}
+
+// Keeps mapping: parameter set ID to domain parameters
+var mlkemParams = make(map[uint8]MlkemParams)
+
+// Params returns domain parameters identified by `id`. Function panics
+// in case `id` wasn't registered earlier.
+func Params(id uint8) *MlkemParams {
+	if val, ok := mlkemParams[id]; ok {
+		return &val
+	}
+	panic("mlkem: ML-KEM Params ID unregistered")
+}
+
+func newParams(id uint8, name string, level, k, eta1 int, du, dv uint) MlkemParams {
+	return MlkemParams{
+		Id:             id,
+		Name:           name,
+		K:              k,
+		Eta1:           eta1,
+		Eta2:           2,
+		Du:             du,
+		Dv:             dv,
+		PublicKeySize:  k*encodedPolySize + 32,
+		PrivateKeySize: 2*k*encodedPolySize + 96,
+		CiphertextSize: 32 * (int(du)*k + int(dv)),
+		Meta: security.Metadata{
+			Name:      name,
+			Level:     level,
+			Status:    security.Standardized,
+			Reference: "FIPS 203, https://doi.org/10.6028/NIST.FIPS.203",
+		},
+	}
+}
+
+func init() {
+	mlkemParams[MLKEM512] = newParams(MLKEM512, "ML-KEM-512", 1, 2, 3, 10, 4)
+	mlkemParams[MLKEM768] = newParams(MLKEM768, "ML-KEM-768", 3, 3, 2, 10, 4)
+	mlkemParams[MLKEM1024] = newParams(MLKEM1024, "ML-KEM-1024", 5, 4, 2, 11, 5)
+}
@@ -0,0 +1,264 @@
+package mlkem
+
+import (
+	"github.com/henrydcase/nobs/hash/sha3"
+)
+
+const (
+	// Modulus of the field Z_q
+	q = 3329
+	// Number of coefficients of a polynomial
+	n = 256
+	// Size of a polynomial encoded with 12 bits per coefficient
+	encodedPolySize = 12 * n / 8
+
+	// floor(2^24 / q), used by Barrett reduction
+	barrettMul   = 5039
+	barrettShift = 24
+	// 128^-1 mod q, scales the result of the inverse NTT
+	invN = 3303
+)
+
+// Element of Z_q, always kept in [0, q)
+type fieldElement uint16
+
+// Polynomial from R_q = Z_q[X]/(X^256 + 1), either in normal or in NTT
+// domain
+type poly [n]fieldElement
+
+// zetas[i] = 17^BitRev7(i) mod q
+var zetas = [128]fieldElement{
+	1, 1729, 2580, 3289, 2642, 630, 1897, 848, 1062, 1919, 193, 797, 2786, 3260, 569, 1746,
+	296, 2447, 1339, 1476, 3046, 56, 2240, 1333, 1426, 2094, 535, 2882, 2393, 2879, 1974, 821,
+	289, 331, 3253, 1756, 1197, 2304, 2277, 2055, 650, 1977, 2513, 632, 2865, 33, 1320, 1915,
+	2319, 1435, 807, 452, 1438, 2868, 1534, 2402, 2647, 2617, 1481, 648, 2474, 3110, 1227, 910,
+	17, 2761, 583, 2649, 1637, 723, 2288, 1100, 1409, 2662, 3281, 233, 756, 2156, 3015, 3050,
+	1703, 1651, 2789, 1789, 1847, 952, 1461, 2687, 939, 2308, 2437, 2388, 733, 2337, 268, 641,
+	1584, 2298, 2037, 3220, 375, 2549, 2090, 1645, 1063, 319, 2773, 757, 2099, 561, 2466, 2594,
+	2804, 1092, 403, 1026, 1143, 2150, 2775, 886, 1722, 1212, 1874, 1029, 2110, 2935, 885, 2154,
+}
+
+// gammas[i] = 17^(2*BitRev7(i)+1) mod q, used by base case multiplication
+var gammas = [128]fieldElement{
+	17, 3312, 2761, 568, 583, 2746, 2649, 680, 1637, 1692, 723, 2606, 2288, 1041, 1100, 2229,
+	1409, 1920, 2662, 667, 3281, 48, 233, 3096, 756, 2573, 2156, 1173, 3015, 314, 3050, 279,
+	1703, 1626, 1651, 1678, 2789, 540, 1789, 1540, 1847, 1482, 952, 2377, 1461, 1868, 2687, 642,
+	939, 2390, 2308, 1021, 2437, 892, 2388, 941, 733, 2596, 2337, 992, 268, 3061, 641, 2688,
+	1584, 1745, 2298, 1031, 2037, 1292, 3220, 109, 375, 2954, 2549, 780, 2090, 1239, 1645, 1684,
+	1063, 2266, 319, 3010, 2773, 556, 757, 2572, 2099, 1230, 561, 2768, 2466, 863, 2594, 735,
+	2804, 525, 1092, 2237, 403, 2926, 1026, 2303, 1143, 2186, 2150, 1179, 2775, 554, 886, 2443,
+	1722, 1607, 1212, 2117, 1874, 1455, 1029, 2300, 2110, 1219, 2935, 394, 885, 2444, 2154, 1175,
+}
+
+// Returns x mod q for x < 2q. Constant time.
+func reduceOnce(x uint16) fieldElement {
+	x -= q
+	// if x underflowed, top bit is set
+	x += uint16(int16(x)>>15) & q
+	return fieldElement(x)
+}
+
+// Returns x mod q for x < q^2. Constant time.
+func barrettReduce(x uint32) fieldElement {
+	quo := uint32((uint64(x) * barrettMul) >> barrettShift)
+	return reduceOnce(uint16(x - quo*q))
+}
+
+func fieldAdd(a, b fieldElement) fieldElement {
+	return reduceOnce(uint16(a + b))
+}
+
+func fieldSub(a, b fieldElement) fieldElement {
+	return reduceOnce(uint16(a - b + q))
+}
+
+func fieldMul(a, b fieldElement) fieldElement {
+	return barrettReduce(uint32(a) * uint32(b))
+}
+
+// Sets p = a + b
+func (p *poly) add(a, b *poly) {
+	for i := range p {
+		p[i] = fieldAdd(a[i], b[i])
+	}
+}
+
+// Sets p = a - b
+func (p *poly) sub(a, b *poly) {
+	for i := range p {
+		p[i] = fieldSub(a[i], b[i])
+	}
+}
+
+// Computes NTT of p in place (algorithm 9 of FIPS 203)
+func (p *poly) ntt() {
+	k := 1
+	for l := n / 2; l >= 2; l /= 2 {
+		for start := 0; start < n; start += 2 * l {
+			zeta := zetas[k]
+			k++
+			for j := start; j < start+l; j++ {
+				t := fieldMul(zeta, p[j+l])
+				p[j+l] = fieldSub(p[j], t)
+				p[j] = fieldAdd(p[j], t)
+			}
+		}
+	}
+}
+
+// Computes inverse NTT of p in place (algorithm 10 of FIPS 203)
+func (p *poly) invNtt() {
+	k := 127
+	for l := 2; l <= n/2; l *= 2 {
+		for start := 0; start < n; start += 2 * l {
+			zeta := zetas[k]
+			k--
+			for j := start; j < start+l; j++ {
+				t := p[j]
+				p[j] = fieldAdd(t, p[j+l])
+				p[j+l] = fieldMul(zeta, fieldSub(p[j+l], t))
+			}
+		}
+	}
+	for i := range p {
+		p[i] = fieldMul(p[i], invN)
+	}
+}
+
+// Sets p = p + a*b, where a and b are in NTT domain (algorithms 11
+// and 12 of FIPS 203)
+func (p *poly) mulAcc(a, b *poly) {
+	for i := 0; i < n/2; i++ {
+		a0, a1 := a[2*i], a[2*i+1]
+		b0, b1 := b[2*i], b[2*i+1]
+		c0 := fieldAdd(fieldMul(a0, b0), fieldMul(fieldMul(a1, b1), gammas[i]))
+		c1 := fieldAdd(fieldMul(a0, b1), fieldMul(a1, b0))
+		p[2*i] = fieldAdd(p[2*i], c0)
+		p[2*i+1] = fieldAdd(p[2*i+1], c1)
+	}
+}
+
+// Samples polynomial in NTT domain from the output of SHAKE128(rho||j||i)
+// with rejection sampling (algorithm 7 of FIPS 203). Not constant time,
+// rho is public.
+func (p *poly) sampleNtt(rho []byte, j, i byte) {
+	var buf [168]byte
+	h := sha3.NewShake128()
+	h.Write(rho)
+	h.Write([]byte{j, i})
+
+	for c := 0; c < n; {
+		h.Read(buf[:])
+		for k := 0; k < len(buf) && c < n; k += 3 {
+			d1 := uint16(buf[k]) | uint16(buf[k+1]&0xF)<<8
+			d2 := uint16(buf[k+1]>>4) | uint16(buf[k+2])<<4
+			if d1 < q {
+				p[c] = fieldElement(d1)
+				c++
+			}
+			if d2 < q && c < n {
+				p[c] = fieldElement(d2)
+				c++
+			}
+		}
+	}
+}
+
+// Samples polynomial from the centered binomial distribution with
+// parameter eta, using SHAKE256(s||b) as a source of randomness
+// (algorithms 8 of FIPS 203 combined with PRF).
+func (p *poly) sampleCbd(s []byte, b byte, eta int) {
+	var buf [64 * 3]byte
+	h := sha3.NewShake256()
+	h.Write(s)
+	h.Write([]byte{b})
+	h.Read(buf[:64*eta])
+
+	var bits uint32
+	var nbits uint
+	var pos int
+	next := func() uint32 {
+		if nbits == 0 {
+			bits, nbits = uint32(buf[pos]), 8
+			pos++
+		}
+		bit := bits & 1
+		bits >>= 1
+		nbits--
+		return bit
+	}
+	for i := range p {
+		var x, y uint32
+		for j := 0; j < eta; j++ {
+			x += next()
+		}
+		for j := 0; j < eta; j++ {
+			y += next()
+		}
+		p[i] = fieldSub(fieldElement(x), fieldElement(y))
+	}
+}
+
+// Compresses x to d bits: round(2^d/q * x) mod 2^d
+func compress(x fieldElement, d uint) uint16 {
+	// Division by constant is compiled to multiplication, so it's
+	// constant time.
+	return uint16(((uint32(x)<<d)+q/2)/q) & (1<<d - 1)
+}
+
+// Decompresses y from d bits: round(q/2^d * y)
+func decompress(y uint16, d uint) fieldElement {
+	return fieldElement((uint32(y)*q + 1<<(d-1)) >> d)
+}
+
+// Encodes coefficients of p, each on d bits, little-endian. For d < 12
+// coefficients are compressed first. out must have 32*d bytes.
+func (p *poly) encode(out []byte, d uint) {
+	var acc uint32
+	var accBits uint
+	var pos int
+	for i := range out {
+		out[i] = 0
+	}
+	for _, c := range p {
+		v := uint16(c)
+		if d < 12 {
+			v = compress(c, d)
+		}
+		acc |= uint32(v) << accBits
+		accBits += d
+		for accBits >= 8 {
+			out[pos] = byte(acc)
+			pos++
+			acc >>= 8
+			accBits -= 8
+		}
+	}
+}
+
+// Decodes p from the d-bit encoding. For d < 12 coefficients are
+// decompressed. For d = 12 returns false if any coefficient isn't reduced,
+// in such case coefficients are reduced mod q.
+func (p *poly) decode(in []byte, d uint) bool {
+	var acc uint32
+	var accBits uint
+	var pos int
+	ok := true
+	for i := range p {
+		for accBits < d {
+			acc |= uint32(in[pos]) << accBits
+			pos++
+			accBits += 8
+		}
+		v := uint16(acc & (1<<d - 1))
+		acc >>= d
+		accBits -= d
+		if d < 12 {
+			p[i] = decompress(v, d)
+			continue
+		}
+		ok = ok && v < q
+		p[i] = barrettReduce(uint32(v))
+	}
+	return ok
+}
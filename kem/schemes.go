@@ -0,0 +1,158 @@
+package kem
+
+import (
+	"io"
+
+	"github.com/henrydcase/nobs/dh/sidh"
+	"github.com/henrydcase/nobs/kem/frodo"
+	"github.com/henrydcase/nobs/kem/mlkem"
+	"github.com/henrydcase/nobs/kem/sike"
+	"github.com/henrydcase/nobs/utils/security"
+)
+
+// -----------------------------------------------------------------------------
+// ML-KEM
+//
+
+type mlkemScheme struct {
+	params *mlkem.MlkemParams
+}
+
+func (s mlkemScheme) Name() string            { return s.params.Name }
+func (s mlkemScheme) Meta() security.Metadata { return s.params.Meta }
+func (s mlkemScheme) PublicKeySize() int      { return s.params.PublicKeySize }
+func (s mlkemScheme) PrivateKeySize() int     { return s.params.PrivateKeySize }
+func (s mlkemScheme) CiphertextSize() int     { return s.params.CiphertextSize }
+func (s mlkemScheme) SharedSecretSize() int   { return mlkem.SharedSecretSize }
+
+func (s mlkemScheme) GenerateKeyPair(rng io.Reader) ([]byte, []byte, error) {
+	pub, prv, err := mlkem.GenerateKeyPair(rng, s.params.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pub.Export(), prv.Export(), nil
+}
+
+func (s mlkemScheme) Encapsulate(rng io.Reader, pk []byte) ([]byte, []byte, error) {
+	pub := mlkem.NewPublicKey(s.params.Id)
+	if err := pub.Import(pk); err != nil {
+		return nil, nil, err
+	}
+	return mlkem.Encapsulate(rng, pub)
+}
+
+func (s mlkemScheme) Decapsulate(sk, ct []byte) ([]byte, error) {
+	prv := mlkem.NewPrivateKey(s.params.Id)
+	if err := prv.Import(sk); err != nil {
+		return nil, err
+	}
+	return mlkem.Decapsulate(prv, ct)
+}
+
+// -----------------------------------------------------------------------------
+// FrodoKEM
+//
+
+type frodoScheme struct {
+	params *frodo.FrodoParams
+}
+
+func (s frodoScheme) Name() string            { return s.params.Name }
+func (s frodoScheme) Meta() security.Metadata { return s.params.Meta }
+func (s frodoScheme) PublicKeySize() int      { return s.params.PublicKeySize }
+func (s frodoScheme) PrivateKeySize() int     { return s.params.PrivateKeySize }
+func (s frodoScheme) CiphertextSize() int     { return s.params.CiphertextSize }
+func (s frodoScheme) SharedSecretSize() int   { return s.params.SharedSecretSize }
+
+func (s frodoScheme) GenerateKeyPair(rng io.Reader) ([]byte, []byte, error) {
+	pub, prv, err := frodo.GenerateKeyPair(rng, s.params.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pub.Export(), prv.Export(), nil
+}
+
+func (s frodoScheme) Encapsulate(rng io.Reader, pk []byte) ([]byte, []byte, error) {
+	pub := frodo.NewPublicKey(s.params.Id)
+	if err := pub.Import(pk); err != nil {
+		return nil, nil, err
+	}
+	return frodo.Encapsulate(rng, pub)
+}
+
+func (s frodoScheme) Decapsulate(sk, ct []byte) ([]byte, error) {
+	prv := frodo.NewPrivateKey(s.params.Id)
+	if err := prv.Import(sk); err != nil {
+		return nil, err
+	}
+	return frodo.Decapsulate(prv, ct)
+}
+
+// -----------------------------------------------------------------------------
+// SIKE
+//
+
+// Params of SIDH are internal to dh/sidh and can't be stored here
+type sikeScheme struct {
+	id uint8
+}
+
+func (s sikeScheme) Name() string            { return sidh.Params(s.id).Meta.Name }
+func (s sikeScheme) Meta() security.Metadata { return sidh.Params(s.id).Meta }
+func (s sikeScheme) PublicKeySize() int      { return sidh.Params(s.id).PublicKeySize }
+func (s sikeScheme) SharedSecretSize() int   { return int(sidh.Params(s.id).KemSize) }
+
+// Size of c0 || c1
+func (s sikeScheme) CiphertextSize() int {
+	p := sidh.Params(s.id)
+	return p.PublicKeySize + int(p.MsgLen)
+}
+
+// Size of s || sk || pk
+func (s sikeScheme) PrivateKeySize() int {
+	p := sidh.Params(s.id)
+	return int(p.MsgLen) + int(p.B.SecretByteLen) + p.PublicKeySize
+}
+
+func (s sikeScheme) GenerateKeyPair(rng io.Reader) ([]byte, []byte, error) {
+	kp := sike.NewKeyPair(s.id)
+	if err := kp.Generate(rng); err != nil {
+		return nil, nil, err
+	}
+	return kp.Public().Export(), kp.Export(), nil
+}
+
+func (s sikeScheme) Encapsulate(rng io.Reader, pk []byte) ([]byte, []byte, error) {
+	pub := sidh.NewPublicKey(s.id, sidh.KeyVariant_SIKE)
+	if err := pub.Import(pk); err != nil {
+		return nil, nil, err
+	}
+	return sike.Encapsulate(rng, pub)
+}
+
+// Imports s || sk || pk with sike.KeyPair, which checks that pk is the
+// public key of sk.
+func (s sikeScheme) Decapsulate(sk, ct []byte) ([]byte, error) {
+	kp := sike.NewKeyPair(s.id)
+	defer kp.Destroy()
+	if err := kp.Import(sk); err != nil {
+		return nil, err
+	}
+	return kp.Decapsulate(ct)
+}
+
+func init() {
+	for _, id := range []uint8{mlkem.MLKEM512, mlkem.MLKEM768, mlkem.MLKEM1024} {
+		register(mlkemScheme{mlkem.Params(id)})
+	}
+	for _, id := range []uint8{
+		frodo.FRODO640AES, frodo.FRODO640SHAKE,
+		frodo.FRODO976AES, frodo.FRODO976SHAKE,
+		frodo.FRODO1344AES, frodo.FRODO1344SHAKE,
+	} {
+		register(frodoScheme{frodo.Params(id)})
+	}
+	for _, id := range []uint8{sidh.FP_503, sidh.FP_751} {
+		register(sikeScheme{id})
+	}
+}
@@ -0,0 +1,56 @@
+//go:build ctcheck
+// +build ctcheck
+
+package sike
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/henrydcase/nobs/utils/ctcheck"
+)
+
+// Number of measurements of SIKE operations
+const ctMeasurements = 1000
+
+// Checks that Decapsulate runs in the same time for valid ciphertexts and
+// for invalid ones, which are rejected implicitly, and that it doesn't
+// depend on the ciphertext.
+func TestDecapsulateCT(t *testing.T) {
+	for id, val := range tdata {
+		t.Run(val.name, func(t *testing.T) {
+			kp := NewKeyPair(id)
+			if err := kp.Generate(rand.Reader); err != nil {
+				t.Fatal(err)
+			}
+			ctcheck.Secret(kp.Private().S)
+			ctcheck.Secret(kp.Private().Scalar)
+
+			encapsulate := func() []byte {
+				ct, _, err := Encapsulate(rand.Reader, kp.Public())
+				if err != nil {
+					t.Fatal(err)
+				}
+				return ct
+			}
+
+			// Class 0: valid ciphertexts, class 1: ciphertexts with
+			// modified encrypted message, for which c0 doesn't match
+			ctcheck.Check(t, "Decapsulate/valid-invalid", ctMeasurements, func(class int) func() {
+				ct := encapsulate()
+				ct[len(ct)-1] ^= byte(class)
+				return func() { kp.Decapsulate(ct) }
+			})
+
+			// Class 0: fixed valid ciphertext, class 1: random valid ones
+			fixed := encapsulate()
+			ctcheck.Check(t, "Decapsulate/fixed-random", ctMeasurements, func(class int) func() {
+				ct := fixed
+				if class == 1 {
+					ct = encapsulate()
+				}
+				return func() { kp.Decapsulate(ct) }
+			})
+		})
+	}
+}
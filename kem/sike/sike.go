@@ -19,11 +19,20 @@ var G = []byte{0x00, 0x00}
 var H = []byte{0x01, 0x00}
 var F = []byte{0x02, 0x00}
 
+// Overwrites intermediate secret values. It's a variable, so that tests
+// can check which buffers have been wiped.
+var scrub = func(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
 // Generates cShake-256 sum
 func cshakeSum(out, in, S []byte) {
 	h := cshake.NewCShake256(nil, S)
 	h.Write(in)
 	h.Read(out)
+	h.(cshake.Zeroizer).Zeroize()
 }
 
 func encrypt(skA *PrivateKey, pkA, pkB *PublicKey, ptext []byte) ([]byte, error) {
@@ -40,6 +49,7 @@ func encrypt(skA *PrivateKey, pkA, pkB *PublicKey, ptext []byte) ([]byte, error)
 	}
 
 	cshakeSum(n[:ptextLen], j, F)
+	scrub(j)
 	for i, _ := range ptext {
 		n[i] ^= ptext[i]
 	}
@@ -66,6 +76,7 @@ func Encrypt(rng io.Reader, pub *PublicKey, ptext []byte) ([]byte, error) {
 	}
 
 	skA := NewPrivateKey(params.Id, KeyVariant_SIDH_A)
+	defer skA.Destroy()
 	err := skA.Generate(rng)
 	if err != nil {
 		return nil, err
@@ -106,6 +117,7 @@ func Decrypt(prv *PrivateKey, ctext []byte) ([]byte, error) {
 	}
 
 	cshakeSum(n[:c1_len], j, F)
+	scrub(j)
 	for i, _ := range n[:c1_len] {
 		n[i] ^= ctext[pk_len+i]
 	}
@@ -121,14 +133,23 @@ func Decrypt(prv *PrivateKey, ctext []byte) ([]byte, error) {
 // The generated ciphertext is used for authentication.
 // The rng must be cryptographically secure PRNG.
 // Error is returned in case PRNG fails or wrongly formated input was provided.
+// SIKE is broken, error is also returned unless use of broken algorithms was
+// enabled with security.AllowInsecure or the nobs_insecure build tag.
 func Encapsulate(rng io.Reader, pub *PublicKey) (ctext []byte, secret []byte, err error) {
 	var params = pub.Params()
+	if err = params.Meta.Check(); err != nil {
+		return nil, nil, err
+	}
 	// Buffer for random, secret message
 	var ptext = make([]byte, params.MsgLen)
 	// r = G(ptext||pub)
 	var r = make([]byte, params.A.SecretByteLen)
 	// Resulting shared secret
 	secret = make([]byte, params.KemSize)
+	// Ephemeral key and the message are secret, as both allow to compute
+	// shared secret
+	defer scrub(ptext)
+	defer scrub(r)
 
 	// Generate ephemeral value
 	_, err = io.ReadFull(rng, ptext)
@@ -140,12 +161,14 @@ func Encapsulate(rng io.Reader, pub *PublicKey) (ctext []byte, secret []byte, er
 	h.Write(ptext)
 	h.Write(pub.Export())
 	h.Read(r)
+	h.(cshake.Zeroizer).Zeroize()
 
 	// cSHAKE256 implementation is byte oriented. Ensure bitlength is not bigger then to 2^e2-1
 	r[len(r)-1] &= (1 << (params.A.SecretBitLen % 8)) - 1
 
 	// (c0 || c1) = Enc(pkA, ptext; r)
 	skA := NewPrivateKey(params.Id, KeyVariant_SIDH_A)
+	defer skA.Destroy()
 	err = skA.Import(r)
 	if err != nil {
 		return nil, nil, err
@@ -162,31 +185,42 @@ func Encapsulate(rng io.Reader, pub *PublicKey) (ctext []byte, secret []byte, er
 	h.Write(ptext)
 	h.Write(ctext)
 	h.Read(secret)
+	h.(cshake.Zeroizer).Zeroize()
 
 	return ctext, secret, nil
 }
 
 // Decapsulate given the keypair and ciphertext as inputs, Decapsulate outputs a shared
 // secret if plaintext verifies correctly, otherwise function outputs random value.
-// Decapsulation may fail in case input is wrongly formated.
+// Decapsulation may fail in case input is wrongly formated or if use of broken
+// algorithms wasn't enabled (see Encapsulate).
+// The pub must be the public key of prv, which is not checked and a wrong
+// one yields wrong shared secret. KeyPair.Decapsulate guarantees that.
 // Constant time for properly initialized input.
 func Decapsulate(prv *PrivateKey, pub *PublicKey, ctext []byte) ([]byte, error) {
 	var params = pub.Params()
+	if err := params.Meta.Check(); err != nil {
+		return nil, err
+	}
 	var r = make([]byte, params.A.SecretByteLen)
 	// Resulting shared secret
 	var secret = make([]byte, params.KemSize)
 	var skA = NewPrivateKey(params.Id, KeyVariant_SIDH_A)
+	defer skA.Destroy()
+	defer scrub(r)
 
 	m, err := Decrypt(prv, ctext)
 	if err != nil {
 		return nil, err
 	}
+	defer scrub(m)
 
 	// r' = G(m'||pub)
 	h := cshake.NewCShake256(nil, G)
 	h.Write(m)
 	h.Write(pub.Export())
 	h.Read(r)
+	h.(cshake.Zeroizer).Zeroize()
 
 	// cSHAKE256 implementation is byte oriented: Ensure bitlength is not bigger than 2^e2-1
 	r[len(r)-1] &= (1 << (params.A.SecretBitLen % 8)) - 1
@@ -198,20 +232,129 @@ func Decapsulate(prv *PrivateKey, pub *PublicKey, ctext []byte) ([]byte, error)
 	pkA := skA.GeneratePublicKey()
 	c0 := pkA.Export()
 
+	// In case ciphertext is invalid, S is used instead of m. S is chosen at
+	// random when generating a key and unknown to other party. It may seem
+	// weird, but it's correct. It is important that S is unpredictable to
+	// other party. Without this check, it is possible to recover a secret,
+	// by providing series of invalid ciphertexts. Selection is done without
+	// branching, so that validity of the ciphertext isn't leaked either.
+	//
+	// See more details in "On the security of supersingular isogeny cryptosystems"
+	// (S. Galbraith, et al., 2016, ePrint #859).
+	var mS = make([]byte, len(m))
+	defer scrub(mS)
+	copy(mS, prv.S)
+	subtle.ConstantTimeCopy(subtle.ConstantTimeCompare(c0, ctext[:len(c0)]), mS, m)
+
 	h = cshake.NewCShake256(nil, H)
-	if subtle.ConstantTimeCompare(c0, ctext[:len(c0)]) == 1 {
-		h.Write(m)
-	} else {
-		// S is chosen at random when generating a key and unknown to other party. It
-		// may seem weird, but it's correct. It is important that S is unpredictable
-		// to other party. Without this check, it is possible to recover a secret, by
-		// providing series of invalid ciphertexts. It is also important that in case
-		//
-		// See more details in "On the security of supersingular isogeny cryptosystems"
-		// (S. Galbraith, et al., 2016, ePrint #859).
-		h.Write(prv.S)
-	}
+	h.Write(mS)
 	h.Write(ctext)
 	h.Read(secret)
+	h.(cshake.Zeroizer).Zeroize()
 	return secret, nil
 }
+
+// -----------------------------------------------------------------------------
+// Key pair
+//
+
+var (
+	errKeySize     = errors.New("sike: wrong size of the key")
+	errKeyMismatch = errors.New("sike: public key doesn't match private key")
+)
+
+// KeyPair binds SIKE private key with its public key, so that
+// decapsulation needs only the key pair and can't be called with a public
+// key which doesn't belong to the private key.
+type KeyPair struct {
+	prv *PrivateKey
+	pub *PublicKey
+}
+
+// NewKeyPair initializes key pair for parameters id.
+// Usage of this function guarantees that the object is correctly initialized.
+func NewKeyPair(id uint8) *KeyPair {
+	return &KeyPair{
+		prv: NewPrivateKey(id, KeyVariant_SIKE),
+		pub: NewPublicKey(id, KeyVariant_SIKE),
+	}
+}
+
+// Generate generates new key pair. The rng must be cryptographically
+// secure PRNG. Returns error if use of broken algorithms wasn't enabled
+// (see Encapsulate).
+func (kp *KeyPair) Generate(rng io.Reader) error {
+	if err := kp.prv.Generate(rng); err != nil {
+		return err
+	}
+	kp.pub = kp.prv.GeneratePublicKey()
+	return nil
+}
+
+// Size returns size of the exported key pair in bytes
+func (kp *KeyPair) Size() int {
+	return kp.prv.Size() + kp.pub.Size()
+}
+
+// Import imports key pair in the layout of the NIST submission, which is
+// s || sk || pk. Returns error if pk isn't the public key of sk, in which
+// case kp is not modified. The check computes the public key, so it is
+// as slow as key generation.
+func (kp *KeyPair) Import(input []byte) error {
+	if len(input) != kp.Size() {
+		return errKeySize
+	}
+	id := kp.prv.Params().Id
+	prv := NewPrivateKey(id, KeyVariant_SIKE)
+	defer prv.Destroy()
+	pub := NewPublicKey(id, KeyVariant_SIKE)
+	n := prv.Size()
+	if err := prv.Import(input[:n]); err != nil {
+		return err
+	}
+	if err := pub.Import(input[n:]); err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(prv.GeneratePublicKey().Export(), input[n:]) != 1 {
+		return errKeyMismatch
+	}
+	// Overwrites the current private key, panics if it was destroyed
+	if err := kp.prv.Import(input[:n]); err != nil {
+		return err
+	}
+	kp.pub = pub
+	return nil
+}
+
+// Export returns key pair in the layout of the NIST submission, which is
+// s || sk || pk.
+func (kp *KeyPair) Export() []byte {
+	return append(kp.prv.Export(), kp.pub.Export()...)
+}
+
+// Public returns the public key
+func (kp *KeyPair) Public() *PublicKey {
+	return kp.pub
+}
+
+// Private returns the private key
+func (kp *KeyPair) Private() *PrivateKey {
+	return kp.prv
+}
+
+// Zeroize overwrites the private key with zeros. The key pair can be
+// reused by calling Generate or Import.
+func (kp *KeyPair) Zeroize() {
+	kp.prv.Zeroize()
+}
+
+// Destroy zeroizes the private key and marks it as destroyed. Any later
+// use of the key pair, other than Zeroize and Destroy, panics.
+func (kp *KeyPair) Destroy() {
+	kp.prv.Destroy()
+}
+
+// Decapsulate is the same as Decapsulate(kp.Private(), kp.Public(), ctext)
+func (kp *KeyPair) Decapsulate(ctext []byte) ([]byte, error) {
+	return Decapsulate(kp.prv, kp.pub, ctext)
+}
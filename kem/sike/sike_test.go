@@ -10,10 +10,12 @@ import (
 	"os"
 	"strings"
 
+	"errors"
 	"fmt"
 
 	rand "crypto/rand"
 	. "github.com/henrydcase/nobs/dh/sidh"
+	"github.com/henrydcase/nobs/utils/security"
 )
 
 const (
@@ -23,6 +25,12 @@ const (
 
 var params = Params(FP_751)
 
+// SIKE is broken, it must be explicitly enabled for testing
+func TestMain(m *testing.M) {
+	security.AllowInsecure(true)
+	os.Exit(m.Run())
+}
+
 type MultiIdTestingFunc func(*testing.T, uint8)
 
 func Do(f MultiIdTestingFunc, t *testing.T) {
@@ -348,13 +356,23 @@ func testSIKE_KAT(t *testing.T, id uint8) {
 		pk := readAndCheckLine(r)
 		// sk (secret key in test vector is concatenation of
 		// MSG + SECRET_BOB_KEY + PUBLIC_BOB_KEY. We use only MSG+SECRET_BOB_KEY
-		sk := readAndCheckLine(r)
-		sk = sk[:params.MsgLen+uint(params.B.SecretByteLen)]
+		skFull := readAndCheckLine(r)
+		sk := skFull[:params.MsgLen+uint(params.B.SecretByteLen)]
 		// ct
 		ct := readAndCheckLine(r)
 		// ss
 		ss := readAndCheckLine(r)
 
+		// Key pair uses the layout of test vectors
+		kp := NewKeyPair(id)
+		checkErr(t, kp.Import(skFull), "key pair import failed")
+		if ssGot, err := kp.Decapsulate(ct); err != nil || !bytes.Equal(ssGot, ss) {
+			t.Fatalf("KAT key pair decapsulation failed at %s\n", count)
+		}
+		if !bytes.Equal(kp.Export(), skFull) {
+			t.Fatalf("KAT key pair export failed at %s\n", count)
+		}
+
 		if !testKeygen(pk, sk) {
 			t.Fatalf("KAT keygen form private failed at %s\n", count)
 		}
@@ -368,7 +386,140 @@ func testSIKE_KAT(t *testing.T, id uint8) {
 	}
 }
 
+func testKeyPair(t *testing.T, id uint8) {
+	kp := NewKeyPair(id)
+	checkErr(t, kp.Generate(rand.Reader), "error: key generation")
+	ct, ssE, err := Encapsulate(rand.Reader, kp.Public())
+	checkErr(t, err, "encapsulation failed")
+	ssD, err := kp.Decapsulate(ct)
+	checkErr(t, err, "decapsulation failed")
+	if !bytes.Equal(ssE, ssD) {
+		t.Error("Shared secrets from decapsulation and encapsulation differ")
+	}
+
+	// Public key of other key pair
+	other := NewKeyPair(id)
+	checkErr(t, other.Generate(rand.Reader), "error: key generation")
+	exp := kp.Export()
+	mixed := append(append([]byte(nil), exp[:kp.Private().Size()]...), other.Public().Export()...)
+	if err = kp.Import(mixed); err != errKeyMismatch {
+		t.Errorf("expected errKeyMismatch, got %v", err)
+	}
+	if err = kp.Import(exp[1:]); err != errKeySize {
+		t.Errorf("expected errKeySize, got %v", err)
+	}
+	// Failed import doesn't modify the key pair
+	if !bytes.Equal(kp.Export(), exp) {
+		t.Error("key pair modified by failed import")
+	}
+}
+
+// Replaces scrub with function which records wiped buffers. Returned
+// function restores scrub and checks that recorded buffers are still zero
+// and that there are n of them.
+func recordScrub(t *testing.T, name string, n int) func() {
+	var wiped [][]byte
+	orig := scrub
+	scrub = func(b []byte) {
+		orig(b)
+		wiped = append(wiped, b)
+	}
+	return func() {
+		scrub = orig
+		if len(wiped) != n {
+			t.Errorf("%s: %d buffers scrubbed, expected %d", name, len(wiped), n)
+		}
+		for _, b := range wiped {
+			if !bytes.Equal(b, make([]byte, len(b))) {
+				t.Errorf("%s: buffer not zeroized: %X", name, b)
+			}
+		}
+	}
+}
+
+func testScrub(t *testing.T, id uint8) {
+	kp := NewKeyPair(id)
+	checkErr(t, kp.Generate(rand.Reader), "error: key generation")
+
+	// j-invariant, r and ptext
+	check := recordScrub(t, "Encapsulate", 3)
+	ct, _, err := Encapsulate(rand.Reader, kp.Public())
+	checkErr(t, err, "encapsulation failed")
+	check()
+
+	// j-invariant, mS, m and r, also for invalid ciphertext
+	for _, mod := range []byte{0, 1} {
+		ct[len(ct)-1] ^= mod
+		check = recordScrub(t, "Decapsulate", 4)
+		_, err = kp.Decapsulate(ct)
+		checkErr(t, err, "decapsulation failed")
+		check()
+	}
+}
+
+func testKeyPairDestroy(t *testing.T, id uint8) {
+	kp := NewKeyPair(id)
+	checkErr(t, kp.Generate(rand.Reader), "error: key generation")
+	exp := kp.Export()
+	ct, _, err := Encapsulate(rand.Reader, kp.Public())
+	checkErr(t, err, "encapsulation failed")
+
+	// Zeroized key pair can be imported again
+	scalar, s := kp.Private().Scalar, kp.Private().S
+	kp.Zeroize()
+	if !bytes.Equal(scalar, make([]byte, len(scalar))) || !bytes.Equal(s, make([]byte, len(s))) {
+		t.Error("private key not zeroized")
+	}
+	checkErr(t, kp.Import(exp), "import after Zeroize failed")
+	if !bytes.Equal(kp.Export(), exp) {
+		t.Error("wrong key pair imported after Zeroize")
+	}
+
+	kp.Destroy()
+	if !bytes.Equal(scalar, make([]byte, len(scalar))) || !bytes.Equal(s, make([]byte, len(s))) {
+		t.Error("private key not zeroized")
+	}
+	ops := map[string]func(){
+		"Decapsulate": func() { kp.Decapsulate(ct) },
+		"Import":      func() { kp.Import(exp) },
+		"Export":      func() { kp.Export() },
+		"Generate":    func() { kp.Generate(rand.Reader) },
+	}
+	for name, f := range ops {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected panic with destroyed key pair", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
 // Interface to "testing"
+func TestInsecureRefused(t *testing.T) {
+	for id := range tdata {
+		pk, _ := hex.DecodeString(tdata[id].PkB)
+		sk, _ := hex.DecodeString(tdata[id].PrB)
+		prv := NewPrivateKey(id, KeyVariant_SIKE)
+		pub := NewPublicKey(id, KeyVariant_SIKE)
+		checkErr(t, prv.Import(sk), "private key import failed")
+		checkErr(t, pub.Import(pk), "public key import failed")
+		ct, _, err := Encapsulate(rand.Reader, pub)
+		checkErr(t, err, "encapsulation failed")
+
+		security.AllowInsecure(false)
+		if _, _, err = Encapsulate(rand.Reader, pub); !errors.Is(err, security.ErrInsecure) {
+			t.Errorf("Encapsulate: expected ErrInsecure, got %v", err)
+		}
+		if _, err = Decapsulate(prv, pub, ct); !errors.Is(err, security.ErrInsecure) {
+			t.Errorf("Decapsulate: expected ErrInsecure, got %v", err)
+		}
+		security.AllowInsecure(true)
+	}
+}
+
 func TestPKEKeyGeneration(t *testing.T)           { Do(testPKEKeyGeneration, t) }
 func TestPKERoundTrip(t *testing.T)               { Do(testPKERoundTrip, t) }
 func TestNegativePKE(t *testing.T)                { Do(testNegativePKE, t) }
@@ -376,3 +527,6 @@ func TestKEMKeyGeneration(t *testing.T)           { Do(testKEMKeyGeneration, t)
 func TestNegativeKEM(t *testing.T)                { Do(testNegativeKEM, t) }
 func TestSIKE_KAT(t *testing.T)                   { Do(testSIKE_KAT, t) }
 func TestNegativeKEMSameWrongResult(t *testing.T) { Do(testNegativeKEMSameWrongResult, t) }
+func TestKeyPair(t *testing.T)                    { Do(testKeyPair, t) }
+func TestScrub(t *testing.T)                      { Do(testScrub, t) }
+func TestKeyPairDestroy(t *testing.T)             { Do(testKeyPairDestroy, t) }
@@ -0,0 +1,270 @@
+package noise
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/henrydcase/nobs/ec/x25519"
+	"github.com/henrydcase/nobs/ec/x448"
+	"github.com/henrydcase/nobs/hash/sha3"
+	"github.com/henrydcase/nobs/kem"
+)
+
+// Keypair is a private key and corresponding public key, either of DH or
+// of KEM
+type Keypair struct {
+	Private []byte
+	Public  []byte
+}
+
+// -----------------------------------------------------------------------------
+// DH functions
+//
+
+// DH is a Diffie-Hellman function
+type DH interface {
+	// Name used in the protocol name
+	Name() string
+	// Size of public keys and DH outputs in bytes (DHLEN)
+	Size() int
+	// GenerateKeypair generates key pair using private key read from rng
+	GenerateKeypair(rng io.Reader) (Keypair, error)
+	// DH computes shared secret. Returns error for low order points.
+	DH(private, public []byte) ([]byte, error)
+}
+
+type dh448 struct{}
+
+// DH448 is the X448 function from ec/x448
+var DH448 DH = dh448{}
+
+func (dh448) Name() string { return "448" }
+func (dh448) Size() int    { return x448.SharedSecretSize }
+
+func (dh448) GenerateKeypair(rng io.Reader) (Keypair, error) {
+	var prv, pub [x448.SharedSecretSize]byte
+	if _, err := io.ReadFull(rng, prv[:]); err != nil {
+		return Keypair{}, err
+	}
+	if err := x448.ScalarBaseMult(&pub, &prv); err != nil {
+		return Keypair{}, err
+	}
+	return Keypair{Private: prv[:], Public: pub[:]}, nil
+}
+
+func (dh448) DH(private, public []byte) ([]byte, error) {
+	var prv, pub, out [x448.SharedSecretSize]byte
+	if len(private) != len(prv) || len(public) != len(pub) {
+		return nil, errKeySize
+	}
+	copy(prv[:], private)
+	copy(pub[:], public)
+	if err := x448.ScalarMult(&out, &prv, &pub); err != nil {
+		return nil, err
+	}
+	return out[:], nil
+}
+
+type dh25519 struct{}
+
+// DH25519 is the X25519 function from ec/x25519
+var DH25519 DH = dh25519{}
+
+func (dh25519) Name() string { return "25519" }
+func (dh25519) Size() int    { return x25519.SharedSecretSize }
+
+func (dh25519) GenerateKeypair(rng io.Reader) (Keypair, error) {
+	var prv, pub [x25519.SharedSecretSize]byte
+	if _, err := io.ReadFull(rng, prv[:]); err != nil {
+		return Keypair{}, err
+	}
+	if err := x25519.ScalarBaseMult(&pub, &prv); err != nil {
+		return Keypair{}, err
+	}
+	return Keypair{Private: prv[:], Public: pub[:]}, nil
+}
+
+func (dh25519) DH(private, public []byte) ([]byte, error) {
+	var prv, pub, out [x25519.SharedSecretSize]byte
+	if len(private) != len(prv) || len(public) != len(pub) {
+		return nil, errKeySize
+	}
+	copy(prv[:], private)
+	copy(pub[:], public)
+	if err := x25519.ScalarMult(&out, &prv, &pub); err != nil {
+		return nil, err
+	}
+	return out[:], nil
+}
+
+// -----------------------------------------------------------------------------
+// Cipher functions
+//
+
+// Cipher is an AEAD keyed with 32 byte key, which uses 64-bit nonces
+type Cipher interface {
+	// Encrypt appends ciphertext of pt to out and returns the result
+	Encrypt(out []byte, n uint64, ad, pt []byte) []byte
+	// Decrypt appends plaintext of ct to out and returns the result
+	Decrypt(out []byte, n uint64, ad, ct []byte) ([]byte, error)
+}
+
+// CipherFunc creates Cipher instances. Other ciphers than AESGCM (e.g.
+// ChaChaPoly) can be used by implementing this interface.
+type CipherFunc interface {
+	// Name used in the protocol name
+	Name() string
+	// Cipher returns instance keyed with k
+	Cipher(k [32]byte) Cipher
+}
+
+type cipherAESGCM struct{}
+
+// CipherAESGCM is AES-256-GCM with nonce encoded as 4 zero bytes followed
+// by big-endian encoding of n
+var CipherAESGCM CipherFunc = cipherAESGCM{}
+
+func (cipherAESGCM) Name() string { return "AESGCM" }
+
+func (cipherAESGCM) Cipher(k [32]byte) Cipher {
+	b, err := aes.NewCipher(k[:])
+	if err != nil {
+		panic(err)
+	}
+	gcm, err := cipher.NewGCM(b)
+	if err != nil {
+		panic(err)
+	}
+	return aeadGCM{gcm}
+}
+
+type aeadGCM struct {
+	cipher.AEAD
+}
+
+func (c aeadGCM) nonce(n uint64) []byte {
+	var nonce [12]byte
+	binary.BigEndian.PutUint64(nonce[4:], n)
+	return nonce[:]
+}
+
+func (c aeadGCM) Encrypt(out []byte, n uint64, ad, pt []byte) []byte {
+	return c.Seal(out, c.nonce(n), pt, ad)
+}
+
+func (c aeadGCM) Decrypt(out []byte, n uint64, ad, ct []byte) ([]byte, error) {
+	return c.Open(out, c.nonce(n), ct, ad)
+}
+
+// -----------------------------------------------------------------------------
+// Hash functions
+//
+
+// Hash is a hash function used by the symmetric state. Its Size() is
+// HASHLEN and must be 32 or 64. BlockSize() is BLOCKLEN used by HMAC.
+type Hash interface {
+	// Name used in the protocol name
+	Name() string
+	// New returns new instance of the hash function
+	New() hash.Hash
+}
+
+type namedHash struct {
+	name string
+	new  func() hash.Hash
+}
+
+func (h namedHash) Name() string   { return h.name }
+func (h namedHash) New() hash.Hash { return h.new() }
+
+var (
+	// HashSHA256 is SHA-256
+	HashSHA256 Hash = namedHash{"SHA256", sha256.New}
+	// HashSHA512 is SHA-512
+	HashSHA512 Hash = namedHash{"SHA512", sha512.New}
+	// HashCSHAKE256 is cSHAKE256 from hash/sha3 with 64 bytes of output
+	// and customization string "Noise". It is not one of the hash
+	// functions defined by the Noise specification.
+	HashCSHAKE256 Hash = namedHash{"cSHAKE256", newCShakeHash}
+)
+
+// Adapts cSHAKE256 to hash.Hash with fixed output size
+type cshakeHash struct {
+	sha3.ShakeHash
+}
+
+func newCShakeHash() hash.Hash {
+	return cshakeHash{sha3.NewCShake256(nil, []byte("Noise"))}
+}
+
+func (h cshakeHash) Size() int      { return 64 }
+func (h cshakeHash) BlockSize() int { return 136 }
+
+func (h cshakeHash) Sum(b []byte) []byte {
+	out := make([]byte, h.Size())
+	h.Clone().Read(out)
+	return append(b, out...)
+}
+
+// -----------------------------------------------------------------------------
+// Cipher suite
+//
+
+// CipherSuite combines either DH function or KEM with cipher and hash
+// functions
+type CipherSuite struct {
+	dh     DH
+	kem    kem.Scheme
+	cipher CipherFunc
+	hash   Hash
+}
+
+// NewCipherSuite returns cipher suite for the DH based patterns
+func NewCipherSuite(dh DH, c CipherFunc, h Hash) CipherSuite {
+	return CipherSuite{dh: dh, cipher: c, hash: h}
+}
+
+// NewKEMCipherSuite returns cipher suite for the KEM based patterns of
+// PQNoise, with KEM k from the kem package.
+func NewKEMCipherSuite(k kem.Scheme, c CipherFunc, h Hash) CipherSuite {
+	return CipherSuite{kem: k, cipher: c, hash: h}
+}
+
+// Name returns name of the suite, as used in the protocol name. Names of
+// KEMs are stripped of characters not allowed in the protocol name.
+func (cs CipherSuite) Name() string {
+	var name string
+	if cs.dh != nil {
+		name = cs.dh.Name()
+	} else {
+		name = strings.Replace(cs.kem.Name(), "-", "", -1)
+	}
+	return name + "_" + cs.cipher.Name() + "_" + cs.hash.Name()
+}
+
+// GenerateKeypair generates DH or KEM key pair, depending on the suite.
+// The rng must be cryptographically secure PRNG.
+func (cs CipherSuite) GenerateKeypair(rng io.Reader) (Keypair, error) {
+	if cs.dh != nil {
+		return cs.dh.GenerateKeypair(rng)
+	}
+	pk, sk, err := cs.kem.GenerateKeyPair(rng)
+	if err != nil {
+		return Keypair{}, err
+	}
+	return Keypair{Private: sk, Public: pk}, nil
+}
+
+// Size of public keys
+func (cs CipherSuite) publicKeySize() int {
+	if cs.dh != nil {
+		return cs.dh.Size()
+	}
+	return cs.kem.PublicKeySize()
+}
@@ -0,0 +1,384 @@
+package noise
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/henrydcase/nobs/kem"
+	"github.com/henrydcase/nobs/utils/security"
+)
+
+// Vectors of NN, NK, XX and IK patterns for 25519_AESGCM_SHA256 and
+// 25519_AESGCM_SHA512 from github.com/flynn/noise (v1.1.0), followed by
+// the same handshakes for 448_AESGCM_SHA256 and 448_AESGCM_SHA512. The
+// 448 vectors were generated by etc/noise_vectors.py, an independent
+// implementation, which reproduces the 25519 vectors byte for byte. Keys
+// of 448 vectors continue the byte sequences of 25519 keys to 56 bytes.
+// No vectors exist for PQNoise patterns.
+const vectorsFile = "testdata/vectors.txt"
+
+var patterns = map[string]HandshakePattern{
+	"NN": HandshakeNN, "NK": HandshakeNK, "XX": HandshakeXX, "IK": HandshakeIK,
+	"pqNN": HandshakePQNN, "pqNK": HandshakePQNK, "pqXX": HandshakePQXX, "pqIK": HandshakePQIK,
+}
+
+var dhs = map[string]DH{"25519": DH25519, "448": DH448}
+
+var hashes = map[string]Hash{"SHA256": HashSHA256, "SHA512": HashSHA512}
+
+// Reads blocks of "key=value" lines separated by empty lines
+func readVectors(t *testing.T, fileName string) []map[string]string {
+	f, err := os.Open(fileName)
+	if err != nil {
+		t.Fatalf("File %v can't be opened: %v", fileName, err)
+	}
+	defer f.Close()
+
+	var vectors []map[string]string
+	v := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			if len(v) != 0 {
+				vectors = append(vectors, v)
+				v = map[string]string{}
+			}
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			t.Fatalf("Malformed line %q", line)
+		}
+		v[kv[0]] = kv[1]
+	}
+	if len(v) != 0 {
+		vectors = append(vectors, v)
+	}
+	return vectors
+}
+
+func fromHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// Returns key pair generated from private key given in hex
+func keypair(t *testing.T, dh DH, s string) Keypair {
+	t.Helper()
+	if s == "" {
+		return Keypair{}
+	}
+	kp, err := dh.GenerateKeypair(bytes.NewReader(fromHex(t, s)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return kp
+}
+
+func TestVectors(t *testing.T) {
+	vectors := readVectors(t, vectorsFile)
+	if len(vectors) != 64 {
+		t.Fatalf("Expected 64 vectors, got %d", len(vectors))
+	}
+	for _, v := range vectors {
+		// Noise_<pattern>_<dh>_AESGCM_<hash>
+		name := strings.Split(v["handshake"], "_")
+		dh := dhs[name[2]]
+		suite := NewCipherSuite(dh, CipherAESGCM, hashes[name[4]])
+		if suite.Name() != strings.Join(name[2:], "_") {
+			t.Fatalf("Unexpected suite name %s", suite.Name())
+		}
+		initStatic := keypair(t, dh, v["init_static"])
+		respStatic := keypair(t, dh, v["resp_static"])
+		prologue := fromHex(t, v["prologue"])
+
+		init, err := NewHandshakeState(Config{
+			CipherSuite:   suite,
+			Pattern:       patterns[name[1]],
+			Random:        bytes.NewReader(fromHex(t, v["gen_init_ephemeral"])),
+			Initiator:     true,
+			Prologue:      prologue,
+			StaticKeypair: initStatic,
+			PeerStatic:    respStatic.Public,
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", v["handshake"], err)
+		}
+		resp, err := NewHandshakeState(Config{
+			CipherSuite:   suite,
+			Pattern:       patterns[name[1]],
+			Random:        bytes.NewReader(fromHex(t, v["gen_resp_ephemeral"])),
+			Prologue:      prologue,
+			StaticKeypair: respStatic,
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", v["handshake"], err)
+		}
+
+		// Cipher states returned to the writer and the reader of the last
+		// handshake message. Transport messages are encrypted alternately
+		// with the first and the second one, as in vectors generator.
+		var csW, csR [2]*CipherState
+		handshakeLen := len(patterns[name[1]].Messages)
+		for i := 0; ; i++ {
+			payload, ok := v[fmt.Sprintf("msg_%d_payload", i)]
+			if !ok {
+				break
+			}
+			want := fromHex(t, v[fmt.Sprintf("msg_%d_ciphertext", i)])
+			pt := fromHex(t, payload)
+
+			var ct, got []byte
+			if i < handshakeLen {
+				writer, reader := init, resp
+				if i%2 == 1 {
+					writer, reader = resp, init
+				}
+				ct, csW[0], csW[1], err = writer.WriteMessage(nil, pt)
+				if err != nil {
+					t.Fatalf("%s: msg %d: %v", v["handshake"], i, err)
+				}
+				got, csR[0], csR[1], err = reader.ReadMessage(nil, ct)
+			} else {
+				j := (i - handshakeLen) % 2
+				ct, _ = csW[j].Encrypt(nil, nil, pt)
+				got, err = csR[j].Decrypt(nil, nil, ct)
+			}
+			if err != nil {
+				t.Fatalf("%s: msg %d: %v", v["handshake"], i, err)
+			}
+			if !bytes.Equal(ct, want) {
+				t.Errorf("%s: msg %d: ciphertext differs\n got %x\nwant %x", v["handshake"], i, ct, want)
+			}
+			if !bytes.Equal(got, pt) {
+				t.Errorf("%s: msg %d: payload differs", v["handshake"], i)
+			}
+		}
+		if !bytes.Equal(init.ChannelBinding(), resp.ChannelBinding()) {
+			t.Errorf("%s: handshake hashes differ", v["handshake"])
+		}
+	}
+}
+
+// Runs handshake and checks that transport messages are exchanged
+// correctly in both directions
+func testHandshake(t *testing.T, suite CipherSuite, p HandshakePattern) {
+	t.Helper()
+	initStatic, err := suite.GenerateKeypair(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	respStatic, err := suite.GenerateKeypair(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	init, err := NewHandshakeState(Config{
+		CipherSuite:   suite,
+		Pattern:       p,
+		Random:        rand.Reader,
+		Initiator:     true,
+		Prologue:      []byte("prologue"),
+		StaticKeypair: initStatic,
+		PeerStatic:    respStatic.Public,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := NewHandshakeState(Config{
+		CipherSuite:   suite,
+		Pattern:       p,
+		Random:        rand.Reader,
+		Prologue:      []byte("prologue"),
+		StaticKeypair: respStatic,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cs [2][2]*CipherState
+	for i := range p.Messages {
+		writer, reader := init, resp
+		if i%2 == 1 {
+			writer, reader = resp, init
+		}
+		payload := []byte{byte(i), 1, 2, 3}
+		msg, c0, c1, err := writer.WriteMessage(nil, payload)
+		if err != nil {
+			t.Fatalf("%s: msg %d: %v", p.Name, i, err)
+		}
+		cs[i%2] = [2]*CipherState{c0, c1}
+		got, c0, c1, err := reader.ReadMessage(nil, msg)
+		if err != nil {
+			t.Fatalf("%s: msg %d: %v", p.Name, i, err)
+		}
+		cs[(i+1)%2] = [2]*CipherState{c0, c1}
+		if !bytes.Equal(got, payload) {
+			t.Fatalf("%s: msg %d: payload differs", p.Name, i)
+		}
+	}
+	if cs[0][0] == nil || cs[1][0] == nil {
+		t.Fatalf("%s: handshake not finished", p.Name)
+	}
+	if !bytes.Equal(init.ChannelBinding(), resp.ChannelBinding()) {
+		t.Errorf("%s: handshake hashes differ", p.Name)
+	}
+	if p.Name != "NN" && p.Name != "pqNN" && !bytes.Equal(init.PeerStatic(), respStatic.Public) {
+		t.Errorf("%s: wrong static key of responder", p.Name)
+	}
+	if (p.Name == "XX" || p.Name == "IK" || p.Name == "pqXX" || p.Name == "pqIK") &&
+		!bytes.Equal(resp.PeerStatic(), initStatic.Public) {
+		t.Errorf("%s: wrong static key of initiator", p.Name)
+	}
+	if _, _, _, err = init.WriteMessage(nil, nil); err != errDone {
+		t.Errorf("%s: expected errDone, got %v", p.Name, err)
+	}
+
+	// Transport messages in both directions. cs[0] are states of initiator,
+	// cs[x][0] protects messages sent by initiator.
+	for dir := 0; dir < 2; dir++ {
+		for j := 0; j < 3; j++ {
+			ct, err := cs[dir][dir].Encrypt(nil, nil, []byte("transport"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			pt, err := cs[1-dir][dir].Decrypt(nil, nil, ct)
+			if err != nil || string(pt) != "transport" {
+				t.Fatalf("%s: transport message %d failed: %v", p.Name, j, err)
+			}
+		}
+	}
+}
+
+func TestDH448(t *testing.T) {
+	// RFC 7748, section 6.2
+	alice := keypair(t, DH448, "9a8f4925d1519f5775cf46b04b5800d4ee9ee8bae8bc5565d498c28dd9c9baf5"+
+		"74a9419744897391006382a6f127ab1d9ac2d8c0a598726b")
+	bob := keypair(t, DH448, "1c306a7ac2a0e2e0990b294470cba339e6453772b075811d8fad0d1d6927c120"+
+		"bb5ee8972b0d3e21374c9c921b09d1b0366f10b65173992d")
+	if !bytes.Equal(alice.Public, fromHex(t, "9b08f7cc31b7e3e67d22d5aea121074a273bd2b83de09c63faa73d2c"+
+		"22c5d9bbc836647241d953d40c5b12da88120d53177f80e532c41fa0")) {
+		t.Error("wrong public key of Alice")
+	}
+	if !bytes.Equal(bob.Public, fromHex(t, "3eb7a829b0cd20f5bcfc0b599b6feccf6da4627107bdb0d4f345b430"+
+		"27d8b972fc3e34fb4232a13ca706dcb57aec3dae07bdc1c67bf33609")) {
+		t.Error("wrong public key of Bob")
+	}
+	shared := fromHex(t, "07fff4181ac6cc95ec1c16a94a0f74d12da232ce40a77552281d282bb60c0b56"+
+		"fd2464c335543936521c24403085d59a449a5037514a879d")
+	for _, kp := range [][2]Keypair{{alice, bob}, {bob, alice}} {
+		ss, err := DH448.DH(kp[0].Private, kp[1].Public)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(ss, shared) {
+			t.Error("wrong shared secret")
+		}
+	}
+
+	for _, h := range []Hash{HashSHA256, HashSHA512, HashCSHAKE256} {
+		suite := NewCipherSuite(DH448, CipherAESGCM, h)
+		for _, p := range []HandshakePattern{HandshakeNN, HandshakeNK, HandshakeXX, HandshakeIK} {
+			testHandshake(t, suite, p)
+		}
+	}
+	if n := NewCipherSuite(DH448, CipherAESGCM, HashCSHAKE256).Name(); n != "448_AESGCM_cSHAKE256" {
+		t.Errorf("Unexpected suite name %s", n)
+	}
+}
+
+func TestKEM(t *testing.T) {
+	pq := []HandshakePattern{HandshakePQNN, HandshakePQNK, HandshakePQXX, HandshakePQIK}
+	for _, h := range []Hash{HashSHA512, HashCSHAKE256} {
+		suite := NewKEMCipherSuite(kem.ByName("ML-KEM-768"), CipherAESGCM, h)
+		for _, p := range pq {
+			testHandshake(t, suite, p)
+		}
+	}
+
+	// SIKE is broken and requires explicit opt-in
+	suite := NewKEMCipherSuite(kem.ByName("SIKEp503"), CipherAESGCM, HashSHA512)
+	if _, err := suite.GenerateKeypair(rand.Reader); !errors.Is(err, security.ErrInsecure) {
+		t.Errorf("expected ErrInsecure, got %v", err)
+	}
+	security.AllowInsecure(true)
+	defer security.AllowInsecure(false)
+	testHandshake(t, suite, HandshakePQXX)
+}
+
+func TestErrors(t *testing.T) {
+	dhSuite := NewCipherSuite(DH448, CipherAESGCM, HashSHA512)
+	kemSuite := NewKEMCipherSuite(kem.ByName("ML-KEM-768"), CipherAESGCM, HashSHA512)
+
+	// Pattern and suite mismatch
+	if _, err := NewHandshakeState(Config{CipherSuite: kemSuite, Pattern: HandshakeNN}); err != errSuite {
+		t.Errorf("expected errSuite, got %v", err)
+	}
+	if _, err := NewHandshakeState(Config{CipherSuite: dhSuite, Pattern: HandshakePQNN}); err != errSuite {
+		t.Errorf("expected errSuite, got %v", err)
+	}
+	// Missing static key of responder
+	_, err := NewHandshakeState(Config{CipherSuite: dhSuite, Pattern: HandshakeNK, Initiator: true})
+	if err != errMissingKey {
+		t.Errorf("expected errMissingKey, got %v", err)
+	}
+
+	static, _ := dhSuite.GenerateKeypair(rand.Reader)
+	init, _ := NewHandshakeState(Config{CipherSuite: dhSuite, Pattern: HandshakeXX, Random: rand.Reader, Initiator: true, StaticKeypair: static})
+	resp, _ := NewHandshakeState(Config{CipherSuite: dhSuite, Pattern: HandshakeXX, Random: rand.Reader, StaticKeypair: static})
+	if _, _, _, err = resp.WriteMessage(nil, nil); err != errTurn {
+		t.Errorf("expected errTurn, got %v", err)
+	}
+	msg, _, _, _ := init.WriteMessage(nil, nil)
+	if _, _, _, err = resp.ReadMessage(nil, msg[:10]); err != errShortMsg {
+		t.Errorf("expected errShortMsg, got %v", err)
+	}
+	if _, _, _, err = resp.ReadMessage(nil, msg); err != nil {
+		t.Fatal(err)
+	}
+	msg, _, _, _ = resp.WriteMessage(nil, []byte("payload"))
+	// Tampered message is rejected
+	msg[len(msg)-1] ^= 1
+	if _, _, _, err = init.ReadMessage(nil, msg); err == nil {
+		t.Error("expected authentication failure")
+	}
+	if _, _, _, err = init.WriteMessage(nil, make([]byte, MaxMsgLen)); err != errTurn {
+		t.Errorf("expected errTurn, got %v", err)
+	}
+
+	// Exhausted nonce
+	cs := &CipherState{cf: CipherAESGCM}
+	cs.initializeKey(make([]byte, 32))
+	cs.SetNonce(^uint64(0))
+	if _, err = cs.Encrypt(nil, nil, nil); err != errNonce {
+		t.Errorf("expected errNonce, got %v", err)
+	}
+}
+
+func TestRekey(t *testing.T) {
+	var c1, c2 CipherState
+	for _, cs := range []*CipherState{&c1, &c2} {
+		cs.cf = CipherAESGCM
+		cs.initializeKey(bytes.Repeat([]byte{1}, 32))
+	}
+	c1.Rekey()
+	ct, _ := c1.Encrypt(nil, nil, []byte("msg"))
+	if _, err := c2.Decrypt(nil, nil, ct); err == nil {
+		t.Error("expected failure with old key")
+	}
+	c2.Rekey()
+	if pt, err := c2.Decrypt(nil, nil, ct); err != nil || string(pt) != "msg" {
+		t.Errorf("decryption after rekey failed: %v", err)
+	}
+}
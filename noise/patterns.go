@@ -0,0 +1,117 @@
+package noise
+
+// Token of a message pattern
+type Token uint8
+
+const (
+	TokenE Token = iota
+	TokenS
+	TokenEE
+	TokenES
+	TokenSE
+	TokenSS
+	// Encapsulation to the remote ephemeral KEM key (PQNoise)
+	TokenEKEM
+	// Encapsulation to the remote static KEM key (PQNoise)
+	TokenSKEM
+)
+
+// HandshakePattern describes a handshake. Messages are sent alternately,
+// starting with the initiator.
+type HandshakePattern struct {
+	Name string
+	// Pre-messages known to the other party before the handshake
+	InitiatorPreMessages []Token
+	ResponderPreMessages []Token
+	Messages             [][]Token
+	// KEM patterns require a suite created by NewKEMCipherSuite
+	KEM bool
+}
+
+var (
+	// HandshakeNN has no static keys
+	HandshakeNN = HandshakePattern{
+		Name: "NN",
+		Messages: [][]Token{
+			{TokenE},
+			{TokenE, TokenEE},
+		},
+	}
+
+	// HandshakeNK authenticates the responder, whose static key is
+	// known to the initiator
+	HandshakeNK = HandshakePattern{
+		Name:                 "NK",
+		ResponderPreMessages: []Token{TokenS},
+		Messages: [][]Token{
+			{TokenE, TokenES},
+			{TokenE, TokenEE},
+		},
+	}
+
+	// HandshakeXX mutually authenticates parties, which transmit their
+	// static keys
+	HandshakeXX = HandshakePattern{
+		Name: "XX",
+		Messages: [][]Token{
+			{TokenE},
+			{TokenE, TokenEE, TokenS, TokenES},
+			{TokenS, TokenSE},
+		},
+	}
+
+	// HandshakeIK mutually authenticates parties. Initiator knows the
+	// static key of the responder and sends its own immediately.
+	HandshakeIK = HandshakePattern{
+		Name:                 "IK",
+		ResponderPreMessages: []Token{TokenS},
+		Messages: [][]Token{
+			{TokenE, TokenES, TokenS, TokenSS},
+			{TokenE, TokenEE, TokenSE},
+		},
+	}
+
+	// HandshakePQNN is the KEM counterpart of NN from PQNoise
+	HandshakePQNN = HandshakePattern{
+		Name: "pqNN",
+		Messages: [][]Token{
+			{TokenE},
+			{TokenEKEM},
+		},
+		KEM: true,
+	}
+
+	// HandshakePQNK is the KEM counterpart of NK from PQNoise
+	HandshakePQNK = HandshakePattern{
+		Name:                 "pqNK",
+		ResponderPreMessages: []Token{TokenS},
+		Messages: [][]Token{
+			{TokenSKEM, TokenE},
+			{TokenEKEM},
+		},
+		KEM: true,
+	}
+
+	// HandshakePQXX is the KEM counterpart of XX from PQNoise
+	HandshakePQXX = HandshakePattern{
+		Name: "pqXX",
+		Messages: [][]Token{
+			{TokenE},
+			{TokenEKEM, TokenS},
+			{TokenSKEM, TokenS},
+			{TokenSKEM},
+		},
+		KEM: true,
+	}
+
+	// HandshakePQIK is the KEM counterpart of IK from PQNoise
+	HandshakePQIK = HandshakePattern{
+		Name:                 "pqIK",
+		ResponderPreMessages: []Token{TokenS},
+		Messages: [][]Token{
+			{TokenSKEM, TokenE, TokenS},
+			{TokenEKEM, TokenSKEM},
+		},
+		KEM: true,
+	}
+)
@@ -0,0 +1,506 @@
+// Package noise implements handshakes of the Noise Protocol Framework
+// (revision 34) and the KEM based patterns of PQNoise (Angel et al.,
+// "Post-Quantum Noise", CCS 2022).
+//
+// DH based patterns (NN, NK, XX and IK) use X448 or X25519. KEM based
+// patterns (pqNN, pqNK, pqXX and pqIK) use any KEM from the kem package.
+// In those, the "e" and "s" tokens transmit KEM public keys, the "ekem"
+// token sends a ciphertext encapsulated to the remote ephemeral key in
+// clear and the "skem" token sends a ciphertext encapsulated to the
+// remote static key with EncryptAndHash. Both mix the hash of the
+// ciphertext and the encapsulated key into the state.
+//
+// The symmetric state uses HMAC based HKDF with SHA256, SHA512 or
+// cSHAKE256 from hash/sha3. Pre-shared keys are not supported.
+package noise
+
+import (
+	"crypto/hmac"
+	"errors"
+	"hash"
+	"io"
+)
+
+// MaxMsgLen is the maximal size of a Noise message
+const MaxMsgLen = 65535
+
+var (
+	errKeySize      = errors.New("noise: wrong size of the key")
+	errMsgSize      = errors.New("noise: message too long")
+	errShortMsg     = errors.New("noise: message too short")
+	errNonce        = errors.New("noise: nonce exhausted")
+	errTurn         = errors.New("noise: not this party's turn")
+	errDone         = errors.New("noise: handshake already finished")
+	errMissingKey   = errors.New("noise: missing key required by the pattern")
+	errSuite        = errors.New("noise: cipher suite doesn't match the pattern")
+	errUnknownToken = errors.New("noise: unknown token")
+)
+
+// -----------------------------------------------------------------------------
+// CipherState
+//
+
+// CipherState encrypts and decrypts transport messages after the
+// handshake. It is not safe for concurrent use.
+type CipherState struct {
+	cf     CipherFunc
+	c      Cipher
+	k      [32]byte
+	n      uint64
+	hasKey bool
+}
+
+func (cs *CipherState) initializeKey(k []byte) {
+	copy(cs.k[:], k)
+	cs.c = cs.cf.Cipher(cs.k)
+	cs.n = 0
+	cs.hasKey = true
+}
+
+// Encrypt appends ciphertext of pt with associated data ad to out. If
+// the key is not set, pt is appended unchanged.
+func (cs *CipherState) Encrypt(out, ad, pt []byte) ([]byte, error) {
+	if !cs.hasKey {
+		return append(out, pt...), nil
+	}
+	// 2^64-1 is reserved for Rekey
+	if cs.n == ^uint64(0) {
+		return nil, errNonce
+	}
+	out = cs.c.Encrypt(out, cs.n, ad, pt)
+	cs.n++
+	return out, nil
+}
+
+// Decrypt appends plaintext of ct with associated data ad to out. The
+// nonce is incremented only if authentication succeeds.
+func (cs *CipherState) Decrypt(out, ad, ct []byte) ([]byte, error) {
+	if !cs.hasKey {
+		return append(out, ct...), nil
+	}
+	if cs.n == ^uint64(0) {
+		return nil, errNonce
+	}
+	out, err := cs.c.Decrypt(out, cs.n, ad, ct)
+	if err != nil {
+		return nil, err
+	}
+	cs.n++
+	return out, nil
+}
+
+// Nonce returns the nonce used by the next Encrypt or Decrypt
+func (cs *CipherState) Nonce() uint64 {
+	return cs.n
+}
+
+// SetNonce sets the nonce. Needed if messages may be lost or reordered.
+func (cs *CipherState) SetNonce(n uint64) {
+	cs.n = n
+}
+
+// Rekey replaces the key with a key derived from it
+func (cs *CipherState) Rekey() {
+	var zeros [32]byte
+	k := cs.c.Encrypt(nil, ^uint64(0), nil, zeros[:])
+	copy(cs.k[:], k)
+	cs.c = cs.cf.Cipher(cs.k)
+}
+
+// -----------------------------------------------------------------------------
+// SymmetricState
+//
+
+type symmetricState struct {
+	cs   CipherState
+	hash Hash
+	ck   []byte
+	h    []byte
+}
+
+func (s *symmetricState) initialize(cf CipherFunc, h Hash, protocolName string) {
+	s.cs = CipherState{cf: cf}
+	s.hash = h
+	size := h.New().Size()
+	if len(protocolName) <= size {
+		s.h = make([]byte, size)
+		copy(s.h, protocolName)
+	} else {
+		s.h = s.sum([]byte(protocolName))
+	}
+	s.ck = append([]byte(nil), s.h...)
+}
+
+// Returns HASH(data...)
+func (s *symmetricState) sum(data ...[]byte) []byte {
+	h := s.hash.New()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// HKDF from section 4.3 of the specification, returns 2 outputs
+func (s *symmetricState) hkdf(ikm []byte) ([]byte, []byte) {
+	newHash := func() hash.Hash { return s.hash.New() }
+	m := hmac.New(newHash, s.ck)
+	m.Write(ikm)
+	tempKey := m.Sum(nil)
+
+	m = hmac.New(newHash, tempKey)
+	m.Write([]byte{0x01})
+	out1 := m.Sum(nil)
+
+	m.Reset()
+	m.Write(out1)
+	m.Write([]byte{0x02})
+	out2 := m.Sum(nil)
+	return out1, out2
+}
+
+func (s *symmetricState) mixKey(ikm []byte) {
+	ck, k := s.hkdf(ikm)
+	s.ck = ck
+	s.cs.initializeKey(k[:32])
+}
+
+func (s *symmetricState) mixHash(data []byte) {
+	s.h = s.sum(s.h, data)
+}
+
+func (s *symmetricState) encryptAndHash(out, pt []byte) ([]byte, error) {
+	n := len(out)
+	out, err := s.cs.Encrypt(out, s.h, pt)
+	if err != nil {
+		return nil, err
+	}
+	s.mixHash(out[n:])
+	return out, nil
+}
+
+func (s *symmetricState) decryptAndHash(out, ct []byte) ([]byte, error) {
+	out, err := s.cs.Decrypt(out, s.h, ct)
+	if err != nil {
+		return nil, err
+	}
+	s.mixHash(ct)
+	return out, nil
+}
+
+func (s *symmetricState) split() (*CipherState, *CipherState) {
+	k1, k2 := s.hkdf(nil)
+	c1 := &CipherState{cf: s.cs.cf}
+	c2 := &CipherState{cf: s.cs.cf}
+	c1.initializeKey(k1[:32])
+	c2.initializeKey(k2[:32])
+	return c1, c2
+}
+
+// Overhead of the cipher, if the key is set
+func (s *symmetricState) overhead() int {
+	if s.cs.hasKey {
+		return 16
+	}
+	return 0
+}
+
+// -----------------------------------------------------------------------------
+// HandshakeState
+//
+
+// Config is a configuration of the handshake
+type Config struct {
+	CipherSuite CipherSuite
+	Pattern     HandshakePattern
+	// Random is cryptographically secure PRNG used for ephemeral keys
+	Random    io.Reader
+	Initiator bool
+	Prologue  []byte
+	// Local static key pair, required if the pattern uses it
+	StaticKeypair Keypair
+	// Remote static public key, required if known before the handshake
+	PeerStatic []byte
+}
+
+// HandshakeState runs a handshake. It is not safe for concurrent use.
+type HandshakeState struct {
+	ss        symmetricState
+	suite     CipherSuite
+	rng       io.Reader
+	s, e      Keypair
+	rs, re    []byte
+	initiator bool
+	messages  [][]Token
+	msgIdx    int
+}
+
+// Returns true if the pattern requires local static key and true if it
+// requires remote static key before the handshake
+func requiredKeys(p *HandshakePattern, initiator bool) (local, remote bool) {
+	for _, t := range p.InitiatorPreMessages {
+		local = local || (t == TokenS && initiator)
+		remote = remote || (t == TokenS && !initiator)
+	}
+	for _, t := range p.ResponderPreMessages {
+		local = local || (t == TokenS && !initiator)
+		remote = remote || (t == TokenS && initiator)
+	}
+	for i, m := range p.Messages {
+		for _, t := range m {
+			local = local || (t == TokenS && (i%2 == 0) == initiator)
+		}
+	}
+	return local, remote
+}
+
+// NewHandshakeState initializes handshake defined by c
+func NewHandshakeState(c Config) (*HandshakeState, error) {
+	suite := c.CipherSuite
+	if (suite.dh == nil) == (suite.kem == nil) || suite.cipher == nil || suite.hash == nil ||
+		c.Pattern.KEM != (suite.kem != nil) {
+		return nil, errSuite
+	}
+	if size := suite.hash.New().Size(); size != 32 && size != 64 {
+		return nil, errSuite
+	}
+	local, remote := requiredKeys(&c.Pattern, c.Initiator)
+	if local && len(c.StaticKeypair.Public) != suite.publicKeySize() {
+		return nil, errMissingKey
+	}
+	if remote && len(c.PeerStatic) != suite.publicKeySize() {
+		return nil, errMissingKey
+	}
+
+	hs := &HandshakeState{
+		suite:     suite,
+		rng:       c.Random,
+		s:         c.StaticKeypair,
+		rs:        c.PeerStatic,
+		initiator: c.Initiator,
+		messages:  c.Pattern.Messages,
+	}
+	hs.ss.initialize(suite.cipher, suite.hash, "Noise_"+c.Pattern.Name+"_"+suite.Name())
+	hs.ss.mixHash(c.Prologue)
+
+	// Pre-messages
+	for _, v := range []struct {
+		tokens []Token
+		local  bool
+	}{
+		{c.Pattern.InitiatorPreMessages, c.Initiator},
+		{c.Pattern.ResponderPreMessages, !c.Initiator},
+	} {
+		for _, t := range v.tokens {
+			if t != TokenS {
+				return nil, errUnknownToken
+			}
+			if v.local {
+				hs.ss.mixHash(hs.s.Public)
+			} else {
+				hs.ss.mixHash(hs.rs)
+			}
+		}
+	}
+	return hs, nil
+}
+
+// Returns true if it's this party's turn to write
+func (hs *HandshakeState) isWriter() bool {
+	return (hs.msgIdx%2 == 0) == hs.initiator
+}
+
+// Computes DH and mixes the result into the key
+func (hs *HandshakeState) mixDH(private, public []byte) error {
+	k, err := hs.suite.dh.DH(private, public)
+	if err != nil {
+		return err
+	}
+	hs.ss.mixKey(k)
+	return nil
+}
+
+// Private key and remote public key used for DH token t
+func (hs *HandshakeState) dhKeys(t Token) ([]byte, []byte) {
+	// First letter refers to initiator's key, second to responder's one
+	var local, remote byte
+	switch t {
+	case TokenEE:
+		local, remote = 'e', 'e'
+	case TokenSS:
+		local, remote = 's', 's'
+	case TokenES:
+		local, remote = 'e', 's'
+		if !hs.initiator {
+			local, remote = 's', 'e'
+		}
+	case TokenSE:
+		local, remote = 's', 'e'
+		if !hs.initiator {
+			local, remote = 'e', 's'
+		}
+	}
+	private, public := hs.e.Private, hs.re
+	if local == 's' {
+		private = hs.s.Private
+	}
+	if remote == 's' {
+		public = hs.rs
+	}
+	return private, public
+}
+
+// Returns transport cipher states if the handshake is finished
+func (hs *HandshakeState) finish() (*CipherState, *CipherState) {
+	hs.msgIdx++
+	if hs.msgIdx < len(hs.messages) {
+		return nil, nil
+	}
+	return hs.ss.split()
+}
+
+// WriteMessage appends the next handshake message with payload to out.
+// After the last message of the pattern, it also returns cipher states
+// for transport messages sent by initiator and by responder.
+func (hs *HandshakeState) WriteMessage(out, payload []byte) ([]byte, *CipherState, *CipherState, error) {
+	if hs.msgIdx >= len(hs.messages) {
+		return nil, nil, nil, errDone
+	}
+	if !hs.isWriter() {
+		return nil, nil, nil, errTurn
+	}
+	start := len(out)
+	var err error
+	for _, t := range hs.messages[hs.msgIdx] {
+		switch t {
+		case TokenE:
+			if hs.e, err = hs.suite.GenerateKeypair(hs.rng); err != nil {
+				return nil, nil, nil, err
+			}
+			out = append(out, hs.e.Public...)
+			hs.ss.mixHash(hs.e.Public)
+		case TokenS:
+			out, err = hs.ss.encryptAndHash(out, hs.s.Public)
+		case TokenEE, TokenES, TokenSE, TokenSS:
+			err = hs.mixDH(hs.dhKeys(t))
+		case TokenEKEM, TokenSKEM:
+			pk := hs.re
+			if t == TokenSKEM {
+				pk = hs.rs
+			}
+			ct, k, e := hs.suite.kem.Encapsulate(hs.rng, pk)
+			if e != nil {
+				return nil, nil, nil, e
+			}
+			if t == TokenEKEM {
+				out = append(out, ct...)
+				hs.ss.mixHash(ct)
+			} else {
+				out, err = hs.ss.encryptAndHash(out, ct)
+			}
+			hs.ss.mixKey(k)
+		default:
+			err = errUnknownToken
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	out, err = hs.ss.encryptAndHash(out, payload)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(out)-start > MaxMsgLen {
+		return nil, nil, nil, errMsgSize
+	}
+	c1, c2 := hs.finish()
+	return out, c1, c2, nil
+}
+
+// ReadMessage processes the next handshake message and appends its
+// payload to out. After the last message of the pattern, it also returns
+// cipher states for transport messages sent by initiator and by
+// responder. On error, the handshake must be aborted.
+func (hs *HandshakeState) ReadMessage(out, message []byte) ([]byte, *CipherState, *CipherState, error) {
+	if hs.msgIdx >= len(hs.messages) {
+		return nil, nil, nil, errDone
+	}
+	if hs.isWriter() {
+		return nil, nil, nil, errTurn
+	}
+	if len(message) > MaxMsgLen {
+		return nil, nil, nil, errMsgSize
+	}
+
+	// Returns next n bytes of the message
+	next := func(n int) ([]byte, error) {
+		if len(message) < n {
+			return nil, errShortMsg
+		}
+		b := message[:n]
+		message = message[n:]
+		return b, nil
+	}
+
+	var err error
+	var b []byte
+	for _, t := range hs.messages[hs.msgIdx] {
+		switch t {
+		case TokenE:
+			if b, err = next(hs.suite.publicKeySize()); err == nil {
+				hs.re = append([]byte(nil), b...)
+				hs.ss.mixHash(hs.re)
+			}
+		case TokenS:
+			if b, err = next(hs.suite.publicKeySize() + hs.ss.overhead()); err == nil {
+				hs.rs, err = hs.ss.decryptAndHash(nil, b)
+			}
+		case TokenEE, TokenES, TokenSE, TokenSS:
+			err = hs.mixDH(hs.dhKeys(t))
+		case TokenEKEM, TokenSKEM:
+			sk, n := hs.e.Private, hs.suite.kem.CiphertextSize()
+			if t == TokenSKEM {
+				sk, n = hs.s.Private, n+hs.ss.overhead()
+			}
+			if b, err = next(n); err != nil {
+				break
+			}
+			ct := b
+			if t == TokenEKEM {
+				hs.ss.mixHash(ct)
+			} else if ct, err = hs.ss.decryptAndHash(nil, b); err != nil {
+				break
+			}
+			var k []byte
+			if k, err = hs.suite.kem.Decapsulate(sk, ct); err == nil {
+				hs.ss.mixKey(k)
+			}
+		default:
+			err = errUnknownToken
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	out, err = hs.ss.decryptAndHash(out, message)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	c1, c2 := hs.finish()
+	return out, c1, c2, nil
+}
+
+// ChannelBinding returns the handshake hash, which identifies the
+// session. Should be called after the handshake is finished.
+func (hs *HandshakeState) ChannelBinding() []byte {
+	return append([]byte(nil), hs.ss.h...)
+}
+
+// PeerStatic returns the static public key of the remote party, if known
+func (hs *HandshakeState) PeerStatic() []byte {
+	return hs.rs
+}
+
+// MessageIndex returns index of the next handshake message
+func (hs *HandshakeState) MessageIndex() int {
+	return hs.msgIdx
+}
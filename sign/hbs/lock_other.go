@@ -0,0 +1,10 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package hbs
+
+// Advisory locks are not supported, only access from single process is
+// serialized.
+func lockFile(path string) (func(), error) {
+	return func() {}, nil
+}
@@ -0,0 +1,226 @@
+// Package hbs provides state management for stateful hash-based signature
+// schemes, XMSS (RFC 8391) and LMS/HSS (RFC 8554).
+//
+// Private keys of these schemes consist of many one-time keys, and signing
+// two messages with the same one-time key breaks security of the scheme.
+// Index of the next unused one-time key is kept by a StateStore. Signer
+// reserves a range of indices from the store, and the reservation is made
+// durable before any index from the range is used. A crash may therefore
+// waste some one-time keys, but never causes reuse of one.
+package hbs
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var (
+	// ErrExhausted is returned when all one-time keys have been used
+	ErrExhausted = errors.New("hbs: all one-time keys used")
+)
+
+// StateStore keeps index of the first one-time key which was never
+// reserved.
+type StateStore interface {
+	// Reserve atomically reserves count consecutive indices, but no more
+	// than up to max, and persists the reservation before returning.
+	// Returns first reserved index or ErrExhausted if no index smaller
+	// than max is left.
+	Reserve(count, max uint64) (uint64, error)
+}
+
+// Computes value of the next unreserved index after reserving count
+// indices starting at next
+func advance(next, count, max uint64) (uint64, error) {
+	if count == 0 {
+		return 0, errors.New("hbs: reservation of zero indices")
+	}
+	if next >= max {
+		return 0, ErrExhausted
+	}
+	if max-next < count {
+		return max, nil
+	}
+	return next + count, nil
+}
+
+// MemoryStateStore keeps the state in memory only. It can be used with
+// ephemeral keys, which are never used again after the process exits.
+type MemoryStateStore struct {
+	mu   sync.Mutex
+	next uint64
+}
+
+// Reserve implements StateStore
+func (s *MemoryStateStore) Reserve(count, max uint64) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next, err := advance(s.next, count, max)
+	if err != nil {
+		return 0, err
+	}
+	first := s.next
+	s.next = next
+	return first, nil
+}
+
+// FileStateStore keeps the state in a file. The file is updated by writing
+// new state to a temporary file, which is synced and renamed over the
+// original one, so a crash leaves either old or new state. Access from
+// multiple processes is serialized with an advisory lock on a file with
+// ".lock" suffix, where supported by the platform.
+type FileStateStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// CreateFileStateStore creates new state file, with no index reserved.
+// Returns error if the file already exists.
+func CreateFileStateStore(path string) (*FileStateStore, error) {
+	var buf [8]byte
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+	_, err = f.Write(buf[:])
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return &FileStateStore{path: path}, syncDir(path)
+}
+
+// OpenFileStateStore opens existing state file. Missing state file is an
+// error, it is never recreated, as that would allow reuse of one-time keys.
+func OpenFileStateStore(path string) (*FileStateStore, error) {
+	s := &FileStateStore{path: path}
+	if _, err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Returns first unreserved index stored in the file
+func (s *FileStateStore) load() (uint64, error) {
+	buf, err := os.ReadFile(s.path)
+	if err != nil {
+		return 0, err
+	}
+	if len(buf) != 8 {
+		return 0, errors.New("hbs: malformed state file")
+	}
+	return binary.BigEndian.Uint64(buf), nil
+}
+
+// Stores first unreserved index in the file
+func (s *FileStateStore) store(next uint64) error {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], next)
+
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(buf[:])
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, s.path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return syncDir(s.path)
+}
+
+// Reserve implements StateStore
+func (s *FileStateStore) Reserve(count, max uint64) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := lockFile(s.path + ".lock")
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	first, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+	next, err := advance(first, count, max)
+	if err != nil {
+		return 0, err
+	}
+	if err = s.store(next); err != nil {
+		return 0, err
+	}
+	return first, nil
+}
+
+// Makes rename of the file durable by syncing its directory
+func syncDir(path string) error {
+	d, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	// Some platforms don't support syncing directories, error is ignored
+	d.Sync()
+	return nil
+}
+
+// Counter hands out indices of one-time keys. Indices are reserved from
+// StateStore in batches of given size, so that state is written once per
+// batch. Indices reserved but not handed out before the process exits are
+// never used. Counter is safe for concurrent use.
+type Counter struct {
+	mu         sync.Mutex
+	store      StateStore
+	batch, max uint64
+	next, end  uint64
+}
+
+// NewCounter returns Counter which hands out indices smaller than max,
+// reserving batch indices at once. Batch of size 0 is treated as 1.
+func NewCounter(store StateStore, batch, max uint64) *Counter {
+	if batch == 0 {
+		batch = 1
+	}
+	return &Counter{store: store, batch: batch, max: max}
+}
+
+// Next returns index of the next one-time key
+func (c *Counter) Next() (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.next == c.end {
+		first, err := c.store.Reserve(c.batch, c.max)
+		if err != nil {
+			return 0, err
+		}
+		end, err := advance(first, c.batch, c.max)
+		if err != nil {
+			return 0, err
+		}
+		c.next, c.end = first, end
+	}
+	i := c.next
+	c.next++
+	return i, nil
+}
@@ -0,0 +1,92 @@
+package hbs
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestFileStateStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	if _, err := OpenFileStateStore(path); err == nil {
+		t.Fatal("missing state file accepted")
+	}
+	s, err := CreateFileStateStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = CreateFileStateStore(path); err == nil {
+		t.Fatal("existing state file overwritten")
+	}
+
+	for i, v := range []struct{ count, first uint64 }{{3, 0}, {5, 3}, {10, 8}} {
+		first, err := s.Reserve(v.count, 12)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first != v.first {
+			t.Errorf("#%d: expected %d, got %d", i, v.first, first)
+		}
+	}
+	if _, err = s.Reserve(1, 12); err != ErrExhausted {
+		t.Errorf("expected ErrExhausted, got %v", err)
+	}
+
+	// State survives reopening
+	s, err = OpenFileStateStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first, err := s.Reserve(1, 20); err != nil || first != 12 {
+		t.Errorf("expected 12, got %d (%v)", first, err)
+	}
+
+	if err = os.WriteFile(path, []byte{1, 2, 3}, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Reserve(1, 20); err == nil {
+		t.Error("malformed state file accepted")
+	}
+}
+
+func TestCounter(t *testing.T) {
+	const max = 100
+	var store MemoryStateStore
+	var wg sync.WaitGroup
+	c1 := NewCounter(&store, 7, max)
+	c2 := NewCounter(&store, 3, max)
+
+	// Indices handed out by counters sharing a store are unique
+	ch := make(chan uint64, max)
+	for _, c := range []*Counter{c1, c2, c1, c2} {
+		wg.Add(1)
+		go func(c *Counter) {
+			defer wg.Done()
+			for {
+				i, err := c.Next()
+				if err == ErrExhausted {
+					return
+				}
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				ch <- i
+			}
+		}(c)
+	}
+	wg.Wait()
+	close(ch)
+
+	seen := make(map[uint64]bool)
+	for i := range ch {
+		if seen[i] || i >= max {
+			t.Fatalf("index %d handed out twice or out of range", i)
+		}
+		seen[i] = true
+	}
+	if len(seen) != max {
+		t.Errorf("expected %d indices, got %d", max, len(seen))
+	}
+}
@@ -0,0 +1,136 @@
+// Package merkle computes roots and authentication paths of Merkle trees
+// used by hash-based signature schemes.
+package merkle
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Hasher computes nodes of the tree. Implementations don't need to be
+// safe for concurrent use, each goroutine uses own instance.
+type Hasher interface {
+	// Computes i-th leaf into out
+	Leaf(out []byte, i uint32)
+	// Computes i-th node at height z into out, from its children l and r
+	Node(out []byte, z int, i uint32, l, r []byte)
+}
+
+// Tree keeps nodes at the upper levels of a Merkle tree. Authentication
+// path of a leaf is taken from the stored nodes and from a small subtree,
+// recomputed on each call, below them.
+type Tree struct {
+	n, height int
+	// Height of the lowest stored level
+	cut int
+	// nodes[z-cut] keeps 2^(height-z) nodes at height z
+	nodes [][]byte
+	h     Hasher
+}
+
+// Number of levels of the tree, which are not stored, for given height.
+// Authentication path needs recomputing 2^cut leaves, and 2^(height-cut+1)
+// nodes are stored.
+func cutHeight(height int) int {
+	cut := 4
+	if height-16 > cut {
+		cut = height - 16
+	}
+	if cut > height {
+		cut = height
+	}
+	return cut
+}
+
+// Computes root of the subtree of given height, which starts at leaf
+// off. If auth isn't nil, authentication path of the leaf off+idx within
+// the subtree is written to it.
+func treeHash(h Hasher, n, height int, off uint32, auth []byte, idx uint32) []byte {
+	stack := make([]byte, (height+1)*n)
+	heights := make([]int, height+1)
+	sp := 0
+
+	for i := uint32(0); i < 1<<uint(height); i++ {
+		cur := stack[sp*n : (sp+1)*n]
+		h.Leaf(cur, off+i)
+		z, index := 0, i
+		for {
+			if auth != nil && z < height && index == (idx>>uint(z))^1 {
+				copy(auth[z*n:], cur)
+			}
+			if sp == 0 || heights[sp-1] != z {
+				break
+			}
+			z++
+			index >>= 1
+			left := stack[(sp-1)*n : sp*n]
+			h.Node(left, z, off>>uint(z)+index, left, cur)
+			cur = left
+			sp--
+		}
+		heights[sp] = z
+		sp++
+	}
+	return stack[:n]
+}
+
+// New computes the tree of given height with node size n. Subtrees below
+// the stored levels are computed concurrently, newHasher is called once
+// for each goroutine.
+func New(n, height int, newHasher func() Hasher) *Tree {
+	t := &Tree{n: n, height: height, cut: cutHeight(height), h: newHasher()}
+	t.nodes = make([][]byte, height-t.cut+1)
+	for z := range t.nodes {
+		t.nodes[z] = make([]byte, n<<uint(height-t.cut-z))
+	}
+
+	// Roots of subtrees at height cut
+	var wg sync.WaitGroup
+	count := 1 << uint(height-t.cut)
+	ch := make(chan int, count)
+	for i := 0; i < count; i++ {
+		ch <- i
+	}
+	close(ch)
+	workers := runtime.GOMAXPROCS(0)
+	if workers > count {
+		workers = count
+	}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func(h Hasher) {
+			defer wg.Done()
+			for i := range ch {
+				root := treeHash(h, n, t.cut, uint32(i)<<uint(t.cut), nil, 0)
+				copy(t.nodes[0][i*n:], root)
+			}
+		}(newHasher())
+	}
+	wg.Wait()
+
+	// Upper levels
+	for z := 1; z < len(t.nodes); z++ {
+		for i := 0; i < len(t.nodes[z])/n; i++ {
+			t.h.Node(t.nodes[z][i*n:(i+1)*n], t.cut+z, uint32(i),
+				t.nodes[z-1][2*i*n:(2*i+1)*n], t.nodes[z-1][(2*i+1)*n:(2*i+2)*n])
+		}
+	}
+	return t
+}
+
+// Root returns root of the tree
+func (t *Tree) Root() []byte {
+	return t.nodes[len(t.nodes)-1]
+}
+
+// AuthPath writes authentication path of the leaf idx, height*n bytes,
+// into out. Not safe for concurrent use.
+func (t *Tree) AuthPath(out []byte, idx uint32) {
+	n := t.n
+	off := idx >> uint(t.cut) << uint(t.cut)
+	treeHash(t.h, n, t.cut, off, out, idx-off)
+	for z := t.cut; z < t.height; z++ {
+		i := (idx >> uint(z)) ^ 1
+		copy(out[z*n:(z+1)*n], t.nodes[z-t.cut][int(i)*n:])
+	}
+}
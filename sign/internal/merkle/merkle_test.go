@@ -0,0 +1,64 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+)
+
+const n = sha256.Size
+
+type testHasher struct{}
+
+func (testHasher) Leaf(out []byte, i uint32) {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], i)
+	s := sha256.Sum256(buf[:])
+	copy(out, s[:])
+}
+
+func (testHasher) Node(out []byte, z int, i uint32, l, r []byte) {
+	var buf [8]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(z))
+	binary.BigEndian.PutUint32(buf[4:], i)
+	s := sha256.Sum256(append(append(buf[:], l...), r...))
+	copy(out, s[:])
+}
+
+// Computes all levels of the tree
+func naiveTree(height int) [][]byte {
+	var h testHasher
+	levels := make([][]byte, height+1)
+	levels[0] = make([]byte, n<<uint(height))
+	for i := uint32(0); i < 1<<uint(height); i++ {
+		h.Leaf(levels[0][i*n:], i)
+	}
+	for z := 1; z <= height; z++ {
+		levels[z] = make([]byte, n<<uint(height-z))
+		for i := 0; i < len(levels[z])/n; i++ {
+			h.Node(levels[z][i*n:], z, uint32(i), levels[z-1][2*i*n:(2*i+1)*n], levels[z-1][(2*i+1)*n:(2*i+2)*n])
+		}
+	}
+	return levels
+}
+
+func TestTree(t *testing.T) {
+	for height := 0; height <= 8; height++ {
+		levels := naiveTree(height)
+		tree := New(n, height, func() Hasher { return testHasher{} })
+		if !bytes.Equal(tree.Root(), levels[height]) {
+			t.Fatalf("height=%d: root doesn't match", height)
+		}
+		auth := make([]byte, height*n)
+		for idx := uint32(0); idx < 1<<uint(height); idx++ {
+			tree.AuthPath(auth, idx)
+			for z := 0; z < height; z++ {
+				i := (idx >> uint(z)) ^ 1
+				if !bytes.Equal(auth[z*n:(z+1)*n], levels[z][i*n:(i+1)*n]) {
+					t.Fatalf("height=%d idx=%d: auth path differs at %d", height, idx, z)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package lms
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+
+	"github.com/henrydcase/nobs/hash/sha3"
+)
+
+// Domain separators (RFC 8554, section 3.1.3) and indices used for
+// pseudorandom key generation (RFC 8554, appendix A)
+const (
+	dPblc = 0x8080
+	dMesg = 0x8181
+	dLeaf = 0x8282
+	dIntr = 0x8383
+	// Randomizer C of LM-OTS signature
+	dRandC = 0xfffd
+	// SEED and I of the child key of HSS
+	dChildSeed = 0xfffe
+	dChildId   = 0xffff
+)
+
+// Hash function SHA-256 or SHAKE256, with output truncated to n bytes.
+// Not safe for concurrent use.
+type hasher struct {
+	n     int
+	sha   hash.Hash
+	shake sha3.ShakeHash
+	sum   [sha256.Size]byte
+	// Scratch buffer for integer encodings
+	buf [4]byte
+}
+
+func newHasher(shake bool, n int) *hasher {
+	h := &hasher{n: n}
+	if shake {
+		h.shake = sha3.NewShake256()
+	} else {
+		h.sha = sha256.New()
+	}
+	return h
+}
+
+func (h *hasher) reset() {
+	if h.shake != nil {
+		h.shake.Reset()
+	} else {
+		h.sha.Reset()
+	}
+}
+
+func (h *hasher) write(in []byte) {
+	if h.shake != nil {
+		h.shake.Write(in)
+	} else {
+		h.sha.Write(in)
+	}
+}
+
+func (h *hasher) writeU32(v uint32) {
+	binary.BigEndian.PutUint32(h.buf[:], v)
+	h.write(h.buf[:4])
+}
+
+func (h *hasher) writeU16(v uint16) {
+	binary.BigEndian.PutUint16(h.buf[:], v)
+	h.write(h.buf[:2])
+}
+
+// Finishes hashing, writes n bytes of the output into out
+func (h *hasher) sumTo(out []byte) {
+	if h.shake != nil {
+		h.shake.Read(out[:h.n])
+		return
+	}
+	copy(out[:h.n], h.sha.Sum(h.sum[:0]))
+}
+
+// Computes H(I || u32str(q) || u16str(i) || in_1 || ... || in_l)
+func (h *hasher) hash(out []byte, id []byte, q uint32, i uint16, in ...[]byte) {
+	h.reset()
+	h.write(id)
+	h.writeU32(q)
+	h.writeU16(i)
+	for _, v := range in {
+		h.write(v)
+	}
+	h.sumTo(out)
+}
+
+// Derives pseudorandom value with index i of the key pair q from SEED
+// (RFC 8554, appendix A)
+func (h *hasher) prf(out []byte, id []byte, q uint32, i uint16, seed []byte) {
+	h.hash(out, id, q, i, []byte{0xff}, seed)
+}
@@ -0,0 +1,98 @@
+package lms
+
+import (
+	"encoding/binary"
+)
+
+// Returns i-th w-bit digit of s (Coef, RFC 8554 section 3.1.3)
+func coef(s []byte, i, w int) uint32 {
+	mask := byte(1)<<uint(w) - 1
+	shift := uint(8 - w*(i%(8/w)) - w)
+	return uint32(s[i*w/8]>>shift) & uint32(mask)
+}
+
+// Returns digits signed by LM-OTS chains, message digest Q followed by
+// checksum (RFC 8554, section 4.4)
+func (p *LmotsParams) digits(q []byte) []uint32 {
+	var sum uint32
+	var cksm [2]byte
+	u := 8 * p.N / p.W
+	digits := make([]uint32, p.P)
+	for i := 0; i < u; i++ {
+		digits[i] = coef(q, i, p.W)
+		sum += 1<<uint(p.W) - 1 - digits[i]
+	}
+	binary.BigEndian.PutUint16(cksm[:], uint16(sum<<uint(p.Ls)))
+	for i := u; i < p.P; i++ {
+		digits[i] = coef(cksm[:], i-u, p.W)
+	}
+	return digits
+}
+
+// Computes chain i of the key pair q from tmp, steps from start to end-1
+func (h *hasher) chain(tmp, id []byte, q uint32, i uint16, start, end uint32) {
+	var j [1]byte
+	for k := start; k < end; k++ {
+		j[0] = byte(k)
+		h.hash(tmp, id, q, i, j[:], tmp[:h.n])
+	}
+}
+
+// Computes LM-OTS public key K of the key pair q, from chain ends y kept
+// in p*n bytes
+func (h *hasher) otsCompress(out, id []byte, q uint32, y []byte) {
+	h.hash(out, id, q, dPblc, y)
+}
+
+// Computes LM-OTS public key K of the key pair q, derived from SEED
+// (RFC 8554, section 4.3 and appendix A)
+func (h *hasher) otsPublic(out []byte, p *LmotsParams, id []byte, q uint32, seed []byte) {
+	n := p.N
+	y := make([]byte, p.P*n)
+	for i := 0; i < p.P; i++ {
+		tmp := y[i*n : (i+1)*n]
+		h.prf(tmp, id, q, uint16(i), seed)
+		h.chain(tmp, id, q, uint16(i), 0, 1<<uint(p.W)-1)
+	}
+	h.otsCompress(out, id, q, y)
+}
+
+// Computes message digest Q of the key pair q with randomizer c
+func (h *hasher) otsDigest(p *LmotsParams, id []byte, q uint32, c, msg []byte) []byte {
+	out := make([]byte, p.N)
+	h.hash(out, id, q, dMesg, c, msg)
+	return out
+}
+
+// Computes LM-OTS signature of msg with key pair q, derived from SEED
+// (RFC 8554, section 4.5). sig must have p.sigSize() bytes.
+func (h *hasher) otsSign(sig []byte, p *LmotsParams, id []byte, q uint32, seed, msg []byte) {
+	n := p.N
+	binary.BigEndian.PutUint32(sig, p.Type)
+	c := sig[4 : 4+n]
+	h.prf(c, id, q, dRandC, seed)
+	digits := p.digits(h.otsDigest(p, id, q, c, msg))
+
+	y := sig[4+n:]
+	for i, a := range digits {
+		tmp := y[i*n : (i+1)*n]
+		h.prf(tmp, id, q, uint16(i), seed)
+		h.chain(tmp, id, q, uint16(i), 0, a)
+	}
+}
+
+// Computes candidate LM-OTS public key from the signature of msg by key
+// pair q (RFC 8554, algorithm 4b). Signature must have correct size and
+// type.
+func (h *hasher) otsPublicFromSig(out []byte, p *LmotsParams, id []byte, q uint32, sig, msg []byte) {
+	n := p.N
+	c := sig[4 : 4+n]
+	digits := p.digits(h.otsDigest(p, id, q, c, msg))
+
+	z := make([]byte, p.P*n)
+	copy(z, sig[4+n:])
+	for i, a := range digits {
+		h.chain(z[i*n:(i+1)*n], id, q, uint16(i), a, 1<<uint(p.W)-1)
+	}
+	h.otsCompress(out, id, q, z)
+}
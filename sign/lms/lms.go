@@ -0,0 +1,474 @@
+// Package lms implements stateful hash-based signature scheme HSS with
+// LMS trees, specified in RFC 8554, together with parameter sets based on
+// SHA-256/192 and SHAKE256 from NIST SP 800-208.
+//
+// HSS is a hierarchy of 1 to 8 levels of LMS trees, where each tree signs
+// public key of the tree below it. Single-level HSS is plain LMS, with
+// number of levels prepended to keys and signatures.
+//
+// Each signature uses a one-time key, which must never be used again.
+// Private key hands out one-time keys from hbs.StateStore, which must be
+// attached with SetStateStore before signing. Format of the private key
+// is specific to this implementation. LM-OTS private keys, keys of lower
+// levels and randomizers of signatures are derived from the seed, as
+// suggested by RFC 8554, appendix A.
+//
+// [RFC8554] https://www.rfc-editor.org/rfc/rfc8554
+// [SP800-208] https://doi.org/10.6028/NIST.SP.800-208
+package lms
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/henrydcase/nobs/sign/hbs"
+	"github.com/henrydcase/nobs/sign/internal/merkle"
+)
+
+// Level describes parameters of a single level of HSS
+type Level struct {
+	Lms   uint32
+	Lmots uint32
+}
+
+// Parameters of a single level, resolved from types
+type level struct {
+	lms *LmsParams
+	ots *LmotsParams
+}
+
+// Defines operations on public key
+type PublicKey struct {
+	// Number of levels
+	levels int
+	// LMS public key of the top level tree
+	top level
+	id  []byte
+	// T[1], root of the tree
+	root []byte
+}
+
+// Defines operations on private key
+type PrivateKey struct {
+	levels []level
+	id     []byte
+	seed   []byte
+	pub    PublicKey
+	// Hands out indices of one-time keys, nil until state store is set
+	counter *hbs.Counter
+
+	// Protects cached trees and signatures
+	mu    sync.Mutex
+	cache []levelCache
+}
+
+// Cached data of one level of HSS
+type levelCache struct {
+	// Index of the tree on this level, its I, SEED and the tree. tree
+	// is nil if not computed yet.
+	idx      uint64
+	id, seed []byte
+	tree     *merkle.Tree
+	// Index of the tree on the level below and signature of its
+	// public key, nil if not computed yet. Not used on the bottom level.
+	sigIdx uint64
+	sig    []byte
+}
+
+// Resolves types of the levels. Returns error for unknown types, LMS
+// and LM-OTS types with different hash function or output size, or
+// for wrong number of levels.
+func resolveLevels(levels []Level) ([]level, error) {
+	if len(levels) < 1 || len(levels) > MaxLevels {
+		return nil, errors.New("lms: wrong number of levels")
+	}
+	out := make([]level, len(levels))
+	height := 0
+	for i, v := range levels {
+		lms, err := getLmsParams(v.Lms)
+		if err != nil {
+			return nil, err
+		}
+		ots, err := getLmotsParams(v.Lmots)
+		if err != nil {
+			return nil, err
+		}
+		if lms.M != ots.N || lms.Shake != ots.Shake {
+			return nil, errors.New("lms: LMS and LM-OTS types don't match")
+		}
+		height += lms.H
+		out[i] = level{lms, ots}
+	}
+	// Index of one-time key must fit in uint64
+	if height > 63 {
+		return nil, errors.New("lms: total height of HSS too large")
+	}
+	return out, nil
+}
+
+// Returns hasher of the level
+func (l *level) hasher() *hasher {
+	return newHasher(l.lms.Shake, l.lms.M)
+}
+
+// Size returns size of the public key in bytes
+func (pub *PublicKey) Size() int {
+	return 4 + pub.top.lms.pubSize()
+}
+
+// Size returns size of the private key in bytes
+func (prv *PrivateKey) Size() int {
+	return 4 + 8*len(prv.levels) + idSize + len(prv.seed)
+}
+
+// Levels returns number of levels of HSS
+func (pub *PublicKey) Levels() int {
+	return pub.levels
+}
+
+// Public returns public key corresponding to the private key
+func (prv *PrivateKey) Public() *PublicKey {
+	return &prv.pub
+}
+
+// Encodes LMS public key u32str(type) || u32str(otstype) || I || T[1]
+func appendLmsPublic(out []byte, l level, id, root []byte) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint32(buf[:], l.lms.Type)
+	binary.BigEndian.PutUint32(buf[4:], l.ots.Type)
+	return append(append(append(out, buf[:]...), id...), root...)
+}
+
+// Decodes LMS public key, returns number of bytes read. Input may be
+// followed by other data.
+func parseLmsPublic(input []byte) (l level, id, root []byte, size int, err error) {
+	if len(input) < 8 {
+		return l, nil, nil, 0, errors.New("lms: public key too short")
+	}
+	if l.lms, err = getLmsParams(binary.BigEndian.Uint32(input)); err != nil {
+		return
+	}
+	if l.ots, err = getLmotsParams(binary.BigEndian.Uint32(input[4:])); err != nil {
+		return
+	}
+	size = l.lms.pubSize()
+	if len(input) < size {
+		return l, nil, nil, 0, errors.New("lms: public key too short")
+	}
+	return l, input[8 : 8+idSize], input[8+idSize : size], size, nil
+}
+
+// Exports public key as u32str(L) || LMS public key of the top level
+func (pub *PublicKey) Export() []byte {
+	out := make([]byte, 4, pub.Size())
+	binary.BigEndian.PutUint32(out, uint32(pub.levels))
+	return appendLmsPublic(out, pub.top, pub.id, pub.root)
+}
+
+// Import clears content of the public key and imports key stored in the
+// byte string. Returns error if the key is malformed.
+func (pub *PublicKey) Import(input []byte) error {
+	if len(input) < 4 {
+		return errors.New("lms: public key too short")
+	}
+	levels := binary.BigEndian.Uint32(input)
+	if levels < 1 || levels > MaxLevels {
+		return errors.New("lms: wrong number of levels")
+	}
+	l, id, root, size, err := parseLmsPublic(input[4:])
+	if err != nil {
+		return err
+	}
+	if 4+size != len(input) {
+		return errors.New("lms: wrong size of the public key")
+	}
+	pub.levels = int(levels)
+	pub.top = l
+	pub.id = append([]byte{}, id...)
+	pub.root = append([]byte{}, root...)
+	return nil
+}
+
+// Exports private key as u32str(L) || (u32str(type) || u32str(otstype))
+// for each level || I || SEED of the top level. Index of the next one-time
+// key is not part of the export.
+func (prv *PrivateKey) Export() []byte {
+	out := make([]byte, 4, prv.Size())
+	binary.BigEndian.PutUint32(out, uint32(len(prv.levels)))
+	for _, l := range prv.levels {
+		var buf [8]byte
+		binary.BigEndian.PutUint32(buf[:], l.lms.Type)
+		binary.BigEndian.PutUint32(buf[4:], l.ots.Type)
+		out = append(out, buf[:]...)
+	}
+	return append(append(out, prv.id...), prv.seed...)
+}
+
+// Import clears content of the private key and imports key stored in the
+// byte string. Public key is recomputed, which requires computing the top
+// level tree. Returns error if the key is malformed.
+func (prv *PrivateKey) Import(input []byte) error {
+	if len(input) < 4 {
+		return errors.New("lms: private key too short")
+	}
+	count := int(binary.BigEndian.Uint32(input))
+	if count < 1 || count > MaxLevels || len(input) < 4+8*count {
+		return errors.New("lms: malformed private key")
+	}
+	levels := make([]Level, count)
+	for i := range levels {
+		levels[i].Lms = binary.BigEndian.Uint32(input[4+8*i:])
+		levels[i].Lmots = binary.BigEndian.Uint32(input[8+8*i:])
+	}
+	resolved, err := resolveLevels(levels)
+	if err != nil {
+		return err
+	}
+	input = input[4+8*count:]
+	if len(input) != idSize+resolved[0].lms.M {
+		return errors.New("lms: wrong size of the private key")
+	}
+	prv.init(resolved, input[:idSize], input[idSize:])
+	return nil
+}
+
+// SetStateStore attaches the store of the index of the next one-time key.
+// The store reserves batch indices at once. Larger batches make signing
+// faster, at the cost of wasting unused reserved indices, when the key
+// is not used anymore.
+func (prv *PrivateKey) SetStateStore(store hbs.StateStore, batch uint64) {
+	height := 0
+	for _, l := range prv.levels {
+		height += l.lms.H
+	}
+	prv.counter = hbs.NewCounter(store, batch, uint64(1)<<uint(height))
+}
+
+// Adapts hasher to merkle.Hasher, computing nodes of LMS tree
+// (RFC 8554, section 5.3)
+type treeHasher struct {
+	*hasher
+	l        level
+	id, seed []byte
+}
+
+// Computes leaf i, node 2^h+i
+func (t *treeHasher) Leaf(out []byte, i uint32) {
+	t.otsPublic(out, t.l.ots, t.id, i, t.seed)
+	t.hash(out, t.id, 1<<uint(t.l.lms.H)+i, dLeaf, out[:t.n])
+}
+
+// Computes node i at height z, node 2^(h-z)+i
+func (t *treeHasher) Node(out []byte, z int, i uint32, l, r []byte) {
+	t.hash(out, t.id, 1<<uint(t.l.lms.H-z)+i, dIntr, l, r)
+}
+
+// Computes LMS tree of the level with given I and SEED
+func computeTree(l level, id, seed []byte) *merkle.Tree {
+	return merkle.New(l.lms.M, l.lms.H, func() merkle.Hasher {
+		return &treeHasher{hasher: l.hasher(), l: l, id: id, seed: seed}
+	})
+}
+
+// Initializes private key and computes the top level tree
+func (prv *PrivateKey) init(levels []level, id, seed []byte) {
+	prv.mu.Lock()
+	defer prv.mu.Unlock()
+	prv.levels = levels
+	prv.id = append([]byte{}, id...)
+	prv.seed = append([]byte{}, seed...)
+	prv.cache = make([]levelCache, len(levels))
+	prv.cache[0] = levelCache{
+		id:   prv.id,
+		seed: prv.seed,
+		tree: computeTree(levels[0], prv.id, prv.seed),
+	}
+	prv.pub = PublicKey{
+		levels: len(levels),
+		top:    levels[0],
+		id:     prv.id,
+		root:   append([]byte{}, prv.cache[0].tree.Root()...),
+	}
+}
+
+// KeyGen derives key pair with given levels from I of 16 bytes and SEED
+// of the size of hash output of the top level. Intended for testing, use
+// GenerateKey otherwise.
+func KeyGen(levels []Level, id, seed []byte) (*PublicKey, *PrivateKey, error) {
+	resolved, err := resolveLevels(levels)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(id) != idSize || len(seed) != resolved[0].lms.M {
+		return nil, nil, errors.New("lms: wrong size of the seed")
+	}
+	prv := new(PrivateKey)
+	prv.init(resolved, id, seed)
+	return prv.Public(), prv, nil
+}
+
+// GenerateKey generates random key pair with given levels, from the top
+// to the bottom one. The rng must be cryptographically secure PRNG. Error
+// is returned in case PRNG fails or levels are invalid.
+func GenerateKey(rng io.Reader, levels []Level) (*PublicKey, *PrivateKey, error) {
+	resolved, err := resolveLevels(levels)
+	if err != nil {
+		return nil, nil, err
+	}
+	buf := make([]byte, idSize+resolved[0].lms.M)
+	if _, err := io.ReadFull(rng, buf); err != nil {
+		return nil, nil, err
+	}
+	return KeyGen(levels, buf[:idSize], buf[idSize:])
+}
+
+// Returns cache of level i with tree idx, whose parent has I and SEED
+// given and signs it with leaf q. Tree is computed if it isn't cached.
+func (prv *PrivateKey) levelTree(i int, idx uint64, parent *levelCache, q uint32) *levelCache {
+	c := &prv.cache[i]
+	if c.tree != nil && c.idx == idx {
+		return c
+	}
+	l := prv.levels[i]
+	h := prv.levels[i].hasher()
+	c.id = make([]byte, l.lms.M)
+	c.seed = make([]byte, l.lms.M)
+	h.prf(c.id, parent.id, q, dChildId, parent.seed)
+	h.prf(c.seed, parent.id, q, dChildSeed, parent.seed)
+	c.id = c.id[:idSize]
+	c.idx = idx
+	c.tree = computeTree(l, c.id, c.seed)
+	c.sig = nil
+	return c
+}
+
+// Computes LMS signature of msg by leaf q (RFC 8554, section 5.4.1)
+func (prv *PrivateKey) lmsSign(l level, c *levelCache, q uint32, msg []byte) []byte {
+	h := l.hasher()
+	sig := make([]byte, l.lms.sigSize(l.ots))
+	binary.BigEndian.PutUint32(sig, q)
+	pos := 4 + l.ots.sigSize()
+	h.otsSign(sig[4:pos], l.ots, c.id, q, c.seed, msg)
+	binary.BigEndian.PutUint32(sig[pos:], l.lms.Type)
+	c.tree.AuthPath(sig[pos+4:], q)
+	return sig
+}
+
+// Computes HSS signature of msg with one-time key idx (RFC 8554, section
+// 6.2). Must be called with prv.mu held.
+func (prv *PrivateKey) signAt(idx uint64, msg []byte) []byte {
+	count := len(prv.levels)
+	// Leaf used on each level and index of the tree it belongs to
+	leaves := make([]uint32, count)
+	trees := make([]uint64, count)
+	for i, t := count-1, idx; i >= 0; i-- {
+		h := uint(prv.levels[i].lms.H)
+		leaves[i] = uint32(t & (1<<h - 1))
+		t >>= h
+		trees[i] = t
+	}
+
+	sig := make([]byte, 4)
+	binary.BigEndian.PutUint32(sig, uint32(count-1))
+	c := &prv.cache[0]
+	for i := 0; i < count-1; i++ {
+		child := prv.levelTree(i+1, trees[i+1], c, leaves[i])
+		// Signatures of lower public keys change only when the lower
+		// tree changes, they are cached.
+		if c.sig == nil || c.sigIdx != trees[i+1] {
+			pub := appendLmsPublic(nil, prv.levels[i+1], child.id, child.tree.Root())
+			c.sig = append(prv.lmsSign(prv.levels[i], c, leaves[i], pub), pub...)
+			c.sigIdx = trees[i+1]
+		}
+		sig = append(sig, c.sig...)
+		c = child
+	}
+	return append(sig, prv.lmsSign(prv.levels[count-1], c, leaves[count-1], msg)...)
+}
+
+// Sign computes signature of msg, using next one-time key from the state
+// store. Error is returned if state store is not set, all one-time keys
+// are used or store fails.
+func Sign(prv *PrivateKey, msg []byte) ([]byte, error) {
+	if prv.counter == nil {
+		return nil, errors.New("lms: state store not set")
+	}
+	idx, err := prv.counter.Next()
+	if err != nil {
+		return nil, err
+	}
+	prv.mu.Lock()
+	defer prv.mu.Unlock()
+	return prv.signAt(idx, msg), nil
+}
+
+// Verifies LMS signature of msg, which starts sig. Returns false if
+// signature is invalid, number of bytes of the signature otherwise
+// (RFC 8554, algorithms 6a and 6b).
+func lmsVerify(l level, id, root, msg, sig []byte) (int, bool) {
+	if len(sig) < 8 {
+		return 0, false
+	}
+	q := binary.BigEndian.Uint32(sig)
+	if binary.BigEndian.Uint32(sig[4:]) != l.ots.Type {
+		return 0, false
+	}
+	otsEnd := 4 + l.ots.sigSize()
+	size := l.lms.sigSize(l.ots)
+	if len(sig) < size || binary.BigEndian.Uint32(sig[otsEnd:]) != l.lms.Type {
+		return 0, false
+	}
+	if q >= 1<<uint(l.lms.H) {
+		return 0, false
+	}
+
+	m := l.lms.M
+	h := l.hasher()
+	node := make([]byte, m)
+	h.otsPublicFromSig(node, l.ots, id, q, sig[4:otsEnd], msg)
+	r := 1<<uint(l.lms.H) + q
+	h.hash(node, id, r, dLeaf, node)
+	path := sig[otsEnd+4 : size]
+	for i := 0; r > 1; i++ {
+		if r&1 == 1 {
+			h.hash(node, id, r/2, dIntr, path[i*m:(i+1)*m], node)
+		} else {
+			h.hash(node, id, r/2, dIntr, node, path[i*m:(i+1)*m])
+		}
+		r /= 2
+	}
+	return size, subtle.ConstantTimeCompare(node, root) == 1
+}
+
+// Verify returns true if sig is a valid HSS signature of msg (RFC 8554,
+// section 6.3).
+func Verify(pub *PublicKey, msg, sig []byte) bool {
+	if len(sig) < 4 || binary.BigEndian.Uint32(sig) != uint32(pub.levels-1) {
+		return false
+	}
+	sig = sig[4:]
+	l, id, root := pub.top, pub.id, pub.root
+	for i := 0; i < pub.levels-1; i++ {
+		size := l.lms.sigSize(l.ots)
+		if len(sig) < size {
+			return false
+		}
+		child, childId, childRoot, pubSize, err := parseLmsPublic(sig[size:])
+		if err != nil {
+			return false
+		}
+		if _, ok := lmsVerify(l, id, root, sig[size:size+pubSize], sig[:size]); !ok {
+			return false
+		}
+		if child.lms.M != child.ots.N || child.lms.Shake != child.ots.Shake {
+			return false
+		}
+		l, id, root = child, childId, childRoot
+		sig = sig[size+pubSize:]
+	}
+	size, ok := lmsVerify(l, id, root, msg, sig)
+	return ok && size == len(sig)
+}
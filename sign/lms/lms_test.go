@@ -0,0 +1,255 @@
+package lms
+
+import (
+	"bytes"
+	"crypto/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/henrydcase/nobs/sign/hbs"
+	"github.com/henrydcase/nobs/utils/security"
+)
+
+// HSS configurations with small trees, fast enough for testing
+var testLevels = [][]Level{
+	{{LMS_SHA256_M32_H5, LMOTS_SHA256_N32_W8}},
+	{{LMS_SHAKE_M24_H5, LMOTS_SHAKE_N24_W4}},
+	{{LMS_SHA256_M32_H10, LMOTS_SHA256_N32_W4}, {LMS_SHA256_M32_H5, LMOTS_SHA256_N32_W2}},
+	{
+		{LMS_SHAKE_M32_H5, LMOTS_SHAKE_N32_W4},
+		{LMS_SHA256_M24_H5, LMOTS_SHA256_N24_W1},
+		{LMS_SHA256_M32_H5, LMOTS_SHA256_N32_W8},
+	},
+}
+
+func TestParams(t *testing.T) {
+	// Values from RFC 8554, table 1 and NIST SP 800-208, table 4
+	for _, v := range []struct {
+		t     uint32
+		name  string
+		p, ls int
+	}{
+		{LMOTS_SHA256_N32_W1, "LMOTS_SHA256_N32_W1", 265, 7},
+		{LMOTS_SHA256_N32_W2, "LMOTS_SHA256_N32_W2", 133, 6},
+		{LMOTS_SHA256_N32_W4, "LMOTS_SHA256_N32_W4", 67, 4},
+		{LMOTS_SHA256_N32_W8, "LMOTS_SHA256_N32_W8", 34, 0},
+		{LMOTS_SHA256_N24_W1, "LMOTS_SHA256_N24_W1", 200, 8},
+		{LMOTS_SHA256_N24_W2, "LMOTS_SHA256_N24_W2", 101, 6},
+		{LMOTS_SHA256_N24_W4, "LMOTS_SHA256_N24_W4", 51, 4},
+		{LMOTS_SHA256_N24_W8, "LMOTS_SHA256_N24_W8", 26, 0},
+		{LMOTS_SHAKE_N32_W4, "LMOTS_SHAKE_N32_W4", 67, 4},
+		{LMOTS_SHAKE_N24_W8, "LMOTS_SHAKE_N24_W8", 26, 0},
+	} {
+		p, err := getLmotsParams(v.t)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Name != v.name || p.P != v.p || p.Ls != v.ls {
+			t.Errorf("%s: unexpected parameters %+v", v.name, p)
+		}
+	}
+	for _, v := range []struct {
+		t    uint32
+		name string
+	}{
+		{LMS_SHA256_M32_H5, "LMS_SHA256_M32_H5"},
+		{LMS_SHA256_M32_H25, "LMS_SHA256_M32_H25"},
+		{LMS_SHA256_M24_H5, "LMS_SHA256_M24_H5"},
+		{LMS_SHAKE_M32_H15, "LMS_SHAKE_M32_H15"},
+		{LMS_SHAKE_M24_H25, "LMS_SHAKE_M24_H25"},
+	} {
+		p, err := getLmsParams(v.t)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Name != v.name || p.Meta.Name != v.name || p.Meta.Status != security.Standardized {
+			t.Errorf("%s: unexpected parameters %+v", v.name, p)
+		}
+		if *Params(v.t) != *p {
+			t.Errorf("%s: Params differs", v.name)
+		}
+	}
+}
+
+func TestInvalidLevels(t *testing.T) {
+	for _, levels := range [][]Level{
+		nil,
+		{{LMS_SHA256_M32_H5, LMOTS_SHA256_N24_W8}},
+		{{LMS_SHAKE_M32_H5, LMOTS_SHA256_N32_W8}},
+		{{LMS_SHA256_M32_H5, 0}},
+		{{0, LMOTS_SHA256_N32_W8}},
+		make([]Level, MaxLevels+1),
+		{
+			{LMS_SHA256_M32_H25, LMOTS_SHA256_N32_W8},
+			{LMS_SHA256_M32_H25, LMOTS_SHA256_N32_W8},
+			{LMS_SHA256_M32_H15, LMOTS_SHA256_N32_W8},
+		},
+	} {
+		if _, _, err := GenerateKey(rand.Reader, levels); err == nil {
+			t.Errorf("invalid levels %v accepted", levels)
+		}
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	msg := []byte("message")
+	for _, levels := range testLevels {
+		pub, prv, err := GenerateKey(rand.Reader, levels)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = Sign(prv, msg); err == nil {
+			t.Fatalf("%v: signing without state store", levels)
+		}
+		prv.SetStateStore(&hbs.MemoryStateStore{}, 4)
+
+		var prev []byte
+		for i := 0; i < 3; i++ {
+			sig, err := Sign(prv, msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !Verify(pub, msg, sig) {
+				t.Errorf("%v: valid signature rejected", levels)
+			}
+			if bytes.Equal(sig, prev) {
+				t.Errorf("%v: same signature twice", levels)
+			}
+			prev = sig
+		}
+
+		if Verify(pub, msg[1:], prev) {
+			t.Errorf("%v: signature accepted for wrong message", levels)
+		}
+		if Verify(pub, msg, prev[1:]) || Verify(pub, msg, append(prev, 0)) {
+			t.Errorf("%v: signature of wrong size accepted", levels)
+		}
+		for pos := 0; pos < len(prev); pos += 7 {
+			prev[pos] ^= 1
+			if Verify(pub, msg, prev) {
+				t.Errorf("%v: signature modified at %d accepted", levels, pos)
+			}
+			prev[pos] ^= 1
+		}
+	}
+}
+
+// Signs with indices crossing boundaries of trees on all levels
+func TestTreeBoundaries(t *testing.T) {
+	msg := []byte("message")
+	levels := []Level{
+		{LMS_SHA256_M32_H5, LMOTS_SHA256_N32_W4},
+		{LMS_SHA256_M32_H5, LMOTS_SHA256_N32_W4},
+		{LMS_SHA256_M32_H5, LMOTS_SHA256_N32_W4},
+	}
+	pub, prv, err := GenerateKey(rand.Reader, levels)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prv.mu.Lock()
+	defer prv.mu.Unlock()
+	for _, idx := range []uint64{31, 32, 33, 1023, 1024, 1<<15 - 1, 5} {
+		if !Verify(pub, msg, prv.signAt(idx, msg)) {
+			t.Errorf("valid signature with index %d rejected", idx)
+		}
+	}
+}
+
+func TestExhausted(t *testing.T) {
+	msg := []byte("message")
+	levels := []Level{{LMS_SHA256_M32_H5, LMOTS_SHA256_N32_W8}}
+	_, prv, err := GenerateKey(rand.Reader, levels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &hbs.MemoryStateStore{}
+	if _, err = store.Reserve(31, 32); err != nil {
+		t.Fatal(err)
+	}
+	prv.SetStateStore(store, 16)
+	if _, err = Sign(prv, msg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Sign(prv, msg); err != hbs.ErrExhausted {
+		t.Errorf("expected ErrExhausted, got %v", err)
+	}
+}
+
+func TestImportExport(t *testing.T) {
+	msg := []byte("message")
+	for _, levels := range testLevels {
+		pub, prv, err := GenerateKey(rand.Reader, levels)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk, sk := pub.Export(), prv.Export()
+		if len(pk) != pub.Size() || len(sk) != prv.Size() {
+			t.Fatalf("%v: wrong size of exported keys", levels)
+		}
+
+		pub2, prv2 := new(PublicKey), new(PrivateKey)
+		if err = pub2.Import(pk); err != nil {
+			t.Fatal(err)
+		}
+		if err = prv2.Import(sk); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(pub2.Export(), pk) || !bytes.Equal(prv2.Export(), sk) {
+			t.Errorf("%v: keys differ after import", levels)
+		}
+		if !bytes.Equal(prv2.Public().Export(), pk) {
+			t.Errorf("%v: recomputed public key differs", levels)
+		}
+
+		// Continue with state stored in a file
+		path := filepath.Join(t.TempDir(), "state")
+		store, err := hbs.CreateFileStateStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prv2.SetStateStore(store, 1)
+		sig, err := Sign(prv2, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !Verify(pub2, msg, sig) {
+			t.Errorf("%v: signature by imported key rejected", levels)
+		}
+
+		if pub2.Import(pk[1:]) == nil || prv2.Import(sk[1:]) == nil {
+			t.Errorf("%v: wrong size accepted", levels)
+		}
+		pk[7] ^= 0xff
+		if pub2.Import(pk) == nil {
+			t.Errorf("%v: unknown type accepted", levels)
+		}
+	}
+}
+
+func BenchmarkSign(b *testing.B) {
+	msg := []byte("message")
+	for _, levels := range testLevels {
+		_, prv, _ := GenerateKey(rand.Reader, levels)
+		prv.SetStateStore(&hbs.MemoryStateStore{}, 1024)
+		b.Run("", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = Sign(prv, msg)
+			}
+		})
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	msg := []byte("message")
+	for _, levels := range testLevels {
+		pub, prv, _ := GenerateKey(rand.Reader, levels)
+		prv.SetStateStore(&hbs.MemoryStateStore{}, 1)
+		sig, _ := Sign(prv, msg)
+		b.Run("", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = Verify(pub, msg, sig)
+			}
+		})
+	}
+}
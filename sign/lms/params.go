@@ -0,0 +1,186 @@
+package lms
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/henrydcase/nobs/utils/security"
+)
+
+// Types of LMS parameter sets, as registered by IANA (RFC 8554, section
+// 5.1 and NIST SP 800-208, section 4)
+const (
+	LMS_SHA256_M32_H5  uint32 = 0x05
+	LMS_SHA256_M32_H10 uint32 = 0x06
+	LMS_SHA256_M32_H15 uint32 = 0x07
+	LMS_SHA256_M32_H20 uint32 = 0x08
+	LMS_SHA256_M32_H25 uint32 = 0x09
+	LMS_SHA256_M24_H5  uint32 = 0x0a
+	LMS_SHA256_M24_H10 uint32 = 0x0b
+	LMS_SHA256_M24_H15 uint32 = 0x0c
+	LMS_SHA256_M24_H20 uint32 = 0x0d
+	LMS_SHA256_M24_H25 uint32 = 0x0e
+	LMS_SHAKE_M32_H5   uint32 = 0x0f
+	LMS_SHAKE_M32_H10  uint32 = 0x10
+	LMS_SHAKE_M32_H15  uint32 = 0x11
+	LMS_SHAKE_M32_H20  uint32 = 0x12
+	LMS_SHAKE_M32_H25  uint32 = 0x13
+	LMS_SHAKE_M24_H5   uint32 = 0x14
+	LMS_SHAKE_M24_H10  uint32 = 0x15
+	LMS_SHAKE_M24_H15  uint32 = 0x16
+	LMS_SHAKE_M24_H20  uint32 = 0x17
+	LMS_SHAKE_M24_H25  uint32 = 0x18
+)
+
+// Types of LM-OTS parameter sets, as registered by IANA (RFC 8554,
+// section 4.1 and NIST SP 800-208, section 4)
+const (
+	LMOTS_SHA256_N32_W1 uint32 = 0x01
+	LMOTS_SHA256_N32_W2 uint32 = 0x02
+	LMOTS_SHA256_N32_W4 uint32 = 0x03
+	LMOTS_SHA256_N32_W8 uint32 = 0x04
+	LMOTS_SHA256_N24_W1 uint32 = 0x05
+	LMOTS_SHA256_N24_W2 uint32 = 0x06
+	LMOTS_SHA256_N24_W4 uint32 = 0x07
+	LMOTS_SHA256_N24_W8 uint32 = 0x08
+	LMOTS_SHAKE_N32_W1  uint32 = 0x09
+	LMOTS_SHAKE_N32_W2  uint32 = 0x0a
+	LMOTS_SHAKE_N32_W4  uint32 = 0x0b
+	LMOTS_SHAKE_N32_W8  uint32 = 0x0c
+	LMOTS_SHAKE_N24_W1  uint32 = 0x0d
+	LMOTS_SHAKE_N24_W2  uint32 = 0x0e
+	LMOTS_SHAKE_N24_W4  uint32 = 0x0f
+	LMOTS_SHAKE_N24_W8  uint32 = 0x10
+)
+
+const (
+	// Size of the key pair identifier I
+	idSize = 16
+	// Maximal number of levels of HSS
+	MaxLevels = 8
+)
+
+// Domain parameters of LMS
+type LmsParams struct {
+	Type uint32
+	Name string
+	// Size of hash outputs in bytes
+	M int
+	// Height of the tree
+	H int
+	// SHAKE256 if true, SHA-256 otherwise
+	Shake bool
+	// Security properties of the parameter set
+	Meta security.Metadata
+}
+
+// Domain parameters of LM-OTS
+type LmotsParams struct {
+	Type uint32
+	Name string
+	// Size of hash outputs in bytes
+	N int
+	// Winternitz parameter, number of bits encoded by a chain
+	W int
+	// Number of chains
+	P int
+	// Left shift of the checksum
+	Ls int
+	// SHAKE256 if true, SHA-256 otherwise
+	Shake bool
+}
+
+// Keeps mapping: type to domain parameters
+var (
+	lmsParams   = make(map[uint32]LmsParams)
+	lmotsParams = make(map[uint32]LmotsParams)
+)
+
+// Params returns domain parameters of LMS type t. Function panics in
+// case t wasn't registered earlier.
+func Params(t uint32) *LmsParams {
+	p, err := getLmsParams(t)
+	if err != nil {
+		panic("lms: LMS type unregistered")
+	}
+	return p
+}
+
+// Returns LMS parameters of given type, error if type is unknown
+func getLmsParams(t uint32) (*LmsParams, error) {
+	if val, ok := lmsParams[t]; ok {
+		return &val, nil
+	}
+	return nil, errors.New("lms: unknown LMS type")
+}
+
+// Returns LM-OTS parameters of given type, error if type is unknown
+func getLmotsParams(t uint32) (*LmotsParams, error) {
+	if val, ok := lmotsParams[t]; ok {
+		return &val, nil
+	}
+	return nil, errors.New("lms: unknown LM-OTS type")
+}
+
+// Size of LM-OTS signature
+func (p *LmotsParams) sigSize() int {
+	return 4 + p.N*(p.P+1)
+}
+
+// Size of LMS signature with LM-OTS parameters ots
+func (p *LmsParams) sigSize(ots *LmotsParams) int {
+	return 4 + ots.sigSize() + 4 + p.H*p.M
+}
+
+// Size of LMS public key
+func (p *LmsParams) pubSize() int {
+	return 8 + idSize + p.M
+}
+
+func hashName(shake bool) string {
+	if shake {
+		return "SHAKE"
+	}
+	return "SHA256"
+}
+
+func init() {
+	var t uint32
+	// LMS types are ordered by hash, m and h
+	t = LMS_SHA256_M32_H5
+	for _, shake := range []bool{false, true} {
+		for _, m := range []int{32, 24} {
+			for h := 5; h <= 25; h += 5 {
+				name := fmt.Sprintf("LMS_%s_M%d_H%d", hashName(shake), m, h)
+				lmsParams[t] = LmsParams{Type: t, Name: name, M: m, H: h, Shake: shake,
+					Meta: security.Metadata{
+						Name:      name,
+						Status:    security.Standardized,
+						Reference: "RFC 8554, NIST SP 800-208",
+					}}
+				t++
+			}
+		}
+	}
+
+	// LM-OTS types are ordered by hash, n and w. Number of chains
+	// and checksum shift are computed as in RFC 8554, appendix B.
+	t = LMOTS_SHA256_N32_W1
+	for _, shake := range []bool{false, true} {
+		for _, n := range []int{32, 24} {
+			for _, w := range []int{1, 2, 4, 8} {
+				u := (8*n + w - 1) / w
+				maxCksm := ((1 << uint(w)) - 1) * u
+				bits := 0
+				for ; maxCksm > 0; maxCksm >>= 1 {
+					bits++
+				}
+				v := (bits + w - 1) / w
+				name := fmt.Sprintf("LMOTS_%s_N%d_W%d", hashName(shake), n, w)
+				lmotsParams[t] = LmotsParams{
+					Type: t, Name: name, N: n, W: w, P: u + v, Ls: 16 - v*w, Shake: shake}
+				t++
+			}
+		}
+	}
+}
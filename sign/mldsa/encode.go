@@ -0,0 +1,103 @@
+package mldsa
+
+// Packs coefficients of p, each on the given number of bits, little-endian
+// (SimpleBitPack, algorithm 16 of FIPS 204). Coefficients must fit.
+// out must have 32*bits bytes.
+func (p *poly) packSimple(out []byte, bits uint) {
+	var acc uint64
+	var accBits uint
+	var pos int
+	for _, c := range p {
+		acc |= uint64(c) << accBits
+		accBits += bits
+		for accBits >= 8 {
+			out[pos] = byte(acc)
+			pos++
+			acc >>= 8
+			accBits -= 8
+		}
+	}
+}
+
+// Unpacks coefficients stored on the given number of bits (SimpleBitUnpack,
+// algorithm 18 of FIPS 204).
+func (p *poly) unpackSimple(in []byte, bits uint) {
+	var acc uint64
+	var accBits uint
+	var pos int
+	for i := range p {
+		for accBits < bits {
+			acc |= uint64(in[pos]) << accBits
+			pos++
+			accBits += 8
+		}
+		p[i] = uint32(acc & (1<<bits - 1))
+		acc >>= bits
+		accBits -= bits
+	}
+}
+
+// Packs coefficients from [-a, b] as b - c, each on the given number of
+// bits (BitPack, algorithm 17 of FIPS 204).
+func (p *poly) pack(out []byte, bits uint, b uint32) {
+	var t poly
+	for i, c := range p {
+		t[i] = fieldSub(b, c)
+	}
+	t.packSimple(out, bits)
+}
+
+// Unpacks coefficients packed with pack (BitUnpack, algorithm 19 of
+// FIPS 204).
+func (p *poly) unpack(in []byte, bits uint, b uint32) {
+	p.unpackSimple(in, bits)
+	for i, v := range p {
+		p[i] = fieldSub(b, v)
+	}
+}
+
+// Encodes hint vector h, with at most omega 1's in total, into omega + k
+// bytes (HintBitPack, algorithm 20 of FIPS 204).
+func packHint(out []byte, h []poly, omega int) {
+	var idx int
+	for i := range out {
+		out[i] = 0
+	}
+	for i := range h {
+		for j, v := range h[i] {
+			if v != 0 {
+				out[idx] = byte(j)
+				idx++
+			}
+		}
+		out[omega+i] = byte(idx)
+	}
+}
+
+// Decodes hint vector h (HintBitUnpack, algorithm 21 of FIPS 204). Returns
+// false if encoding is malformed.
+func unpackHint(h []poly, in []byte, omega int) bool {
+	var idx int
+	for i := range h {
+		h[i] = poly{}
+		end := int(in[omega+i])
+		if end < idx || end > omega {
+			return false
+		}
+		first := idx
+		for ; idx < end; idx++ {
+			// indices must be strictly increasing
+			if idx > first && in[idx-1] >= in[idx] {
+				return false
+			}
+			h[i][in[idx]] = 1
+		}
+	}
+	// remaining bytes must be zero
+	for ; idx < omega; idx++ {
+		if in[idx] != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,530 @@
+// Package mldsa implements ML-DSA, the module-lattice-based digital
+// signature algorithm standardized in FIPS 204, with parameter sets
+// ML-DSA-44, ML-DSA-65 and ML-DSA-87.
+//
+// Both pure ML-DSA (Sign, Verify) and pre-hash HashML-DSA (SignHash,
+// VerifyHash) variants are provided. Signing is hedged, unless nil is
+// passed as a source of randomness, in which case deterministic variant
+// is used.
+//
+// [FIPS204] https://doi.org/10.6028/NIST.FIPS.204
+package mldsa
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+
+	"github.com/henrydcase/nobs/hash/sha3"
+)
+
+// Defines operations on public key
+type PublicKey struct {
+	params *MldsaParams
+	rho    [32]byte
+	t1     []poly
+	// H(pk), cached
+	tr [64]byte
+	// Matrix A in NTT domain, A[r][s] = a[r*l+s]
+	a []poly
+	// NTT(t1 * 2^d)
+	t1Ntt []poly
+}
+
+// Defines operations on private key
+type PrivateKey struct {
+	params *MldsaParams
+	rho    [32]byte
+	key    [32]byte
+	tr     [64]byte
+	// s1, s2, t0 in normal and NTT domain
+	s1, s2, t0          []poly
+	s1Ntt, s2Ntt, t0Ntt []poly
+	pub                 PublicKey
+}
+
+// NewPrivateKey initializes private key.
+// Usage of this function guarantees that the object is correctly initialized.
+func NewPrivateKey(id uint8) *PrivateKey {
+	params := Params(id)
+	k, l := params.K, params.L
+	return &PrivateKey{
+		params: params,
+		s1:     make([]poly, l),
+		s2:     make([]poly, k),
+		t0:     make([]poly, k),
+		s1Ntt:  make([]poly, l),
+		s2Ntt:  make([]poly, k),
+		t0Ntt:  make([]poly, k),
+		pub:    *NewPublicKey(id),
+	}
+}
+
+// NewPublicKey initializes public key.
+// Usage of this function guarantees that the object is correctly initialized.
+func NewPublicKey(id uint8) *PublicKey {
+	params := Params(id)
+	k, l := params.K, params.L
+	return &PublicKey{
+		params: params,
+		t1:     make([]poly, k),
+		a:      make([]poly, k*l),
+		t1Ntt:  make([]poly, k),
+	}
+}
+
+// Accessor to the domain parameters
+func (pub *PublicKey) Params() *MldsaParams {
+	return pub.params
+}
+
+// Accessor to the domain parameters
+func (prv *PrivateKey) Params() *MldsaParams {
+	return prv.params
+}
+
+// Public returns public key corresponding to the private key
+func (prv *PrivateKey) Public() *PublicKey {
+	return &prv.pub
+}
+
+// Size returns size of the public key in bytes
+func (pub *PublicKey) Size() int {
+	return pub.params.PublicKeySize
+}
+
+// Size returns size of the private key in bytes
+func (prv *PrivateKey) Size() int {
+	return prv.params.PrivateKeySize
+}
+
+// Computes SHAKE256 of concatenation of inputs into out
+func shake256(out []byte, in ...[]byte) {
+	h := sha3.NewShake256()
+	for _, v := range in {
+		h.Write(v)
+	}
+	h.Read(out)
+}
+
+// Expands matrix A from rho (algorithm 32 of FIPS 204)
+func expandA(a []poly, rho []byte, k, l int) {
+	for r := 0; r < k; r++ {
+		for s := 0; s < l; s++ {
+			a[r*l+s].sampleNtt(rho, byte(s), byte(r))
+		}
+	}
+}
+
+// Exports public key as rho || SimpleBitPack(t1)
+func (pub *PublicKey) Export() []byte {
+	out := make([]byte, pub.params.PublicKeySize)
+	copy(out, pub.rho[:])
+	for i := range pub.t1 {
+		pub.t1[i].packSimple(out[32+i*320:], 23-d)
+	}
+	return out
+}
+
+// Import clears content of the public key and imports key stored in the
+// byte string. Returns error in case of wrong size.
+func (pub *PublicKey) Import(input []byte) error {
+	if len(input) != pub.params.PublicKeySize {
+		return errors.New("mldsa: wrong size of the public key")
+	}
+	copy(pub.rho[:], input)
+	for i := range pub.t1 {
+		pub.t1[i].unpackSimple(input[32+i*320:], 23-d)
+	}
+	expandA(pub.a, pub.rho[:], pub.params.K, pub.params.L)
+	pub.precompute(input)
+	return nil
+}
+
+// Computes tr and NTT(t1 * 2^d) cached by public key. pk is an encoded
+// public key.
+func (pub *PublicKey) precompute(pk []byte) {
+	shake256(pub.tr[:], pk)
+	for i := range pub.t1 {
+		for j, c := range pub.t1[i] {
+			pub.t1Ntt[i][j] = c << d
+		}
+		pub.t1Ntt[i].ntt()
+	}
+}
+
+// Exports private key as rho || K || tr || BitPack(s1) || BitPack(s2) ||
+// BitPack(t0)
+func (prv *PrivateKey) Export() []byte {
+	params := prv.params
+	etaBits := params.etaBits()
+	out := make([]byte, params.PrivateKeySize)
+	copy(out, prv.rho[:])
+	copy(out[32:], prv.key[:])
+	copy(out[64:], prv.tr[:])
+	pos := 128
+	for _, v := range [][]poly{prv.s1, prv.s2} {
+		for i := range v {
+			v[i].pack(out[pos:], etaBits, uint32(params.Eta))
+			pos += 32 * int(etaBits)
+		}
+	}
+	for i := range prv.t0 {
+		prv.t0[i].pack(out[pos:], d, 1<<(d-1))
+		pos += 32 * d
+	}
+	return out
+}
+
+// Import clears content of the private key and imports key stored in the
+// byte string. Public key is recomputed. Returns error in case of wrong
+// size or if the key is malformed or inconsistent.
+func (prv *PrivateKey) Import(input []byte) error {
+	params := prv.params
+	etaBits := params.etaBits()
+	if len(input) != params.PrivateKeySize {
+		return errors.New("mldsa: wrong size of the private key")
+	}
+	copy(prv.rho[:], input)
+	copy(prv.key[:], input[32:])
+	copy(prv.tr[:], input[64:])
+	pos := 128
+	for _, v := range [][]poly{prv.s1, prv.s2} {
+		for i := range v {
+			v[i].unpack(input[pos:], etaBits, uint32(params.Eta))
+			if v[i].exceeds(int32(params.Eta) + 1) {
+				return errors.New("mldsa: private key out of range")
+			}
+			pos += 32 * int(etaBits)
+		}
+	}
+	for i := range prv.t0 {
+		prv.t0[i].unpack(input[pos:], d, 1<<(d-1))
+		pos += 32 * d
+	}
+
+	// t0 and tr must match values computed from s1 and s2
+	t0 := make([]poly, len(prv.t0))
+	copy(t0, prv.t0)
+	tr := prv.tr
+	prv.computePublic()
+	ok := subtle.ConstantTimeCompare(tr[:], prv.tr[:])
+	for i := range t0 {
+		for j := range t0[i] {
+			ok &= subtle.ConstantTimeEq(int32(t0[i][j]), int32(prv.t0[i][j]))
+		}
+	}
+	if ok != 1 {
+		return errors.New("mldsa: inconsistent private key")
+	}
+	return nil
+}
+
+// Computes public key, t0, tr and NTT forms of s1, s2, t0 from rho, s1
+// and s2.
+func (prv *PrivateKey) computePublic() {
+	params := prv.params
+	pub := &prv.pub
+	k, l := params.K, params.L
+
+	pub.rho = prv.rho
+	expandA(pub.a, pub.rho[:], k, l)
+	for i := range prv.s1 {
+		prv.s1Ntt[i] = prv.s1[i]
+		prv.s1Ntt[i].ntt()
+	}
+	for i := range prv.s2 {
+		prv.s2Ntt[i] = prv.s2[i]
+		prv.s2Ntt[i].ntt()
+	}
+
+	// t = NTT^-1(A * NTT(s1)) + s2
+	for i := 0; i < k; i++ {
+		var t poly
+		for j := 0; j < l; j++ {
+			t.mulAccNtt(&pub.a[i*l+j], &prv.s1Ntt[j])
+		}
+		t.invNtt()
+		t.add(&t, &prv.s2[i])
+		for j, c := range t {
+			pub.t1[i][j], prv.t0[i][j] = power2Round(c)
+		}
+		prv.t0Ntt[i] = prv.t0[i]
+		prv.t0Ntt[i].ntt()
+	}
+	pk := pub.Export()
+	pub.precompute(pk)
+	prv.tr = pub.tr
+}
+
+// KeyGen derives key pair deterministically from the seed of SeedSize
+// bytes (ML-DSA.KeyGen_internal, algorithm 6 of FIPS 204). Intended for
+// testing, use GenerateKey otherwise.
+func KeyGen(id uint8, seed []byte) (*PublicKey, *PrivateKey, error) {
+	var buf [128]byte
+	if len(seed) != SeedSize {
+		return nil, nil, errors.New("mldsa: wrong size of the seed")
+	}
+	prv := NewPrivateKey(id)
+	params := prv.params
+
+	// (rho, rho', K) = H(seed || k || l)
+	shake256(buf[:], seed, []byte{byte(params.K), byte(params.L)})
+	copy(prv.rho[:], buf[:32])
+	rhoPrime := buf[32:96]
+	copy(prv.key[:], buf[96:])
+
+	for i := range prv.s1 {
+		prv.s1[i].sampleBounded(rhoPrime, uint16(i), params.Eta)
+	}
+	for i := range prv.s2 {
+		prv.s2[i].sampleBounded(rhoPrime, uint16(params.L+i), params.Eta)
+	}
+	prv.computePublic()
+	return prv.Public(), prv, nil
+}
+
+// GenerateKey generates random key pair for parameter set given by id.
+// The rng must be cryptographically secure PRNG. Error is returned in
+// case PRNG fails.
+func GenerateKey(rng io.Reader, id uint8) (*PublicKey, *PrivateKey, error) {
+	var seed [SeedSize]byte
+	if _, err := io.ReadFull(rng, seed[:]); err != nil {
+		return nil, nil, err
+	}
+	return KeyGen(id, seed[:])
+}
+
+// Computes signature of the formatted message mPrime with randomness rnd
+// (ML-DSA.Sign_internal, algorithm 7 of FIPS 204).
+func (prv *PrivateKey) signInternal(mPrime []byte, rnd []byte) []byte {
+	var mu, rhoPrime [64]byte
+	var c, t poly
+	params := prv.params
+	pub := &prv.pub
+	k, l := params.K, params.L
+	beta := params.beta()
+	gamma1Bits := params.gamma1Bits()
+	w1Bits := params.w1Bits()
+	cs := params.ctildeSize()
+
+	y := make([]poly, l)
+	yNtt := make([]poly, l)
+	z := make([]poly, l)
+	w := make([]poly, k)
+	h := make([]poly, k)
+	w1 := make([]byte, k*32*int(w1Bits))
+	sig := make([]byte, params.SignatureSize)
+
+	shake256(mu[:], prv.tr[:], mPrime)
+	shake256(rhoPrime[:], prv.key[:], rnd, mu[:])
+
+	for kappa := 0; ; kappa += l {
+		// y = ExpandMask(rho', kappa)
+		for r := range y {
+			var buf [32 * 20]byte
+			nonce := uint16(kappa + r)
+			shake256(buf[:32*gamma1Bits], rhoPrime[:], []byte{byte(nonce), byte(nonce >> 8)})
+			y[r].unpack(buf[:], gamma1Bits, uint32(params.Gamma1))
+			yNtt[r] = y[r]
+			yNtt[r].ntt()
+		}
+
+		// w = NTT^-1(A * NTT(y)), w1 = HighBits(w)
+		for i := range w {
+			w[i] = poly{}
+			for j := range yNtt {
+				w[i].mulAccNtt(&pub.a[i*l+j], &yNtt[j])
+			}
+			w[i].invNtt()
+			for j, v := range w[i] {
+				t[j], _ = decompose(v, params.Gamma2)
+			}
+			t.packSimple(w1[i*32*int(w1Bits):], w1Bits)
+		}
+
+		shake256(sig[:cs], mu[:], w1)
+		c.sampleInBall(sig[:cs], params.Tau)
+		c.ntt()
+
+		// z = y + c*s1
+		reject := false
+		for r := range z {
+			z[r].mulNtt(&c, &prv.s1Ntt[r])
+			z[r].invNtt()
+			z[r].add(&z[r], &y[r])
+			reject = z[r].exceeds(params.Gamma1-beta) || reject
+		}
+		if reject {
+			continue
+		}
+
+		// r0 = LowBits(w - c*s2) and hint for -c*t0
+		var ones uint32
+		var acc int32
+		for i := range w {
+			t.mulNtt(&c, &prv.s2Ntt[i])
+			t.invNtt()
+			w[i].sub(&w[i], &t)
+			for _, v := range w[i] {
+				_, r0 := decompose(v, params.Gamma2)
+				r0 = (r0 ^ (r0 >> 31)) - (r0 >> 31)
+				acc |= int32(params.Gamma2) - beta - 1 - r0
+			}
+
+			t.mulNtt(&c, &prv.t0Ntt[i])
+			t.invNtt()
+			reject = t.exceeds(int32(params.Gamma2)) || reject
+			for j := range t {
+				h[i][j] = makeHint(fieldSub(0, t[j]), fieldAdd(w[i][j], t[j]), params.Gamma2)
+				ones += h[i][j]
+			}
+		}
+		if reject || acc < 0 || ones > uint32(params.Omega) {
+			continue
+		}
+
+		pos := cs
+		for r := range z {
+			z[r].pack(sig[pos:], gamma1Bits, uint32(params.Gamma1))
+			pos += 32 * int(gamma1Bits)
+		}
+		packHint(sig[pos:], h, params.Omega)
+		return sig
+	}
+}
+
+// Verifies signature of the formatted message mPrime
+// (ML-DSA.Verify_internal, algorithm 8 of FIPS 204).
+func (pub *PublicKey) verifyInternal(mPrime []byte, sig []byte) bool {
+	var mu [64]byte
+	var c, t poly
+	params := pub.params
+	k, l := params.K, params.L
+	gamma1Bits := params.gamma1Bits()
+	w1Bits := params.w1Bits()
+	cs := params.ctildeSize()
+
+	if len(sig) != params.SignatureSize {
+		return false
+	}
+
+	z := make([]poly, l)
+	h := make([]poly, k)
+	w1 := make([]byte, k*32*int(w1Bits))
+	ctilde := make([]byte, cs)
+
+	pos := cs
+	for r := range z {
+		z[r].unpack(sig[pos:], gamma1Bits, uint32(params.Gamma1))
+		if z[r].exceeds(params.Gamma1 - params.beta()) {
+			return false
+		}
+		z[r].ntt()
+		pos += 32 * int(gamma1Bits)
+	}
+	if !unpackHint(h, sig[pos:], params.Omega) {
+		return false
+	}
+
+	shake256(mu[:], pub.tr[:], mPrime)
+	c.sampleInBall(sig[:cs], params.Tau)
+	c.ntt()
+
+	// w1 = UseHint(h, NTT^-1(A * NTT(z) - NTT(c) * NTT(t1 * 2^d)))
+	for i := 0; i < k; i++ {
+		var w poly
+		for j := 0; j < l; j++ {
+			w.mulAccNtt(&pub.a[i*l+j], &z[j])
+		}
+		t.mulNtt(&c, &pub.t1Ntt[i])
+		w.sub(&w, &t)
+		w.invNtt()
+		for j, v := range w {
+			t[j] = useHint(h[i][j], v, params.Gamma2)
+		}
+		t.packSimple(w1[i*32*int(w1Bits):], w1Bits)
+	}
+
+	shake256(ctilde, mu[:], w1)
+	return subtle.ConstantTimeCompare(ctilde, sig[:cs]) == 1
+}
+
+// Reads randomness used for signing. nil rng selects deterministic
+// variant, with randomness set to zero.
+func signingRandomness(rng io.Reader) ([]byte, error) {
+	rnd := make([]byte, RandomnessSize)
+	if rng != nil {
+		if _, err := io.ReadFull(rng, rnd); err != nil {
+			return nil, err
+		}
+	}
+	return rnd, nil
+}
+
+// Returns M' = domain || len(ctx) || ctx || msg
+func formatMessage(domain byte, ctx []byte, msg ...[]byte) []byte {
+	out := append([]byte{domain, byte(len(ctx))}, ctx...)
+	for _, v := range msg {
+		out = append(out, v...)
+	}
+	return out
+}
+
+// Sign computes ML-DSA signature of msg with context string ctx
+// (ML-DSA.Sign, algorithm 2 of FIPS 204). If rng is nil, deterministic
+// variant is used, otherwise signing is hedged and rng must be
+// cryptographically secure PRNG. Error is returned in case PRNG fails or
+// context is longer than MaxContextSize.
+func Sign(rng io.Reader, prv *PrivateKey, msg, ctx []byte) ([]byte, error) {
+	if len(ctx) > MaxContextSize {
+		return nil, errors.New("mldsa: context too long")
+	}
+	rnd, err := signingRandomness(rng)
+	if err != nil {
+		return nil, err
+	}
+	return prv.signInternal(formatMessage(0, ctx, msg), rnd), nil
+}
+
+// Verify returns true if sig is a valid ML-DSA signature of msg with
+// context string ctx (ML-DSA.Verify, algorithm 3 of FIPS 204).
+func Verify(pub *PublicKey, msg, ctx, sig []byte) bool {
+	if len(ctx) > MaxContextSize {
+		return false
+	}
+	return pub.verifyInternal(formatMessage(0, ctx, msg), sig)
+}
+
+// SignHash computes HashML-DSA signature of msg with context string ctx,
+// message is hashed with ph first (HashML-DSA.Sign, algorithm 4 of FIPS
+// 204). rng is used as in Sign. Error is returned in case PRNG fails,
+// context is too long or ph is unknown.
+func SignHash(rng io.Reader, prv *PrivateKey, msg, ctx []byte, ph PreHash) ([]byte, error) {
+	if len(ctx) > MaxContextSize {
+		return nil, errors.New("mldsa: context too long")
+	}
+	oid, digest, err := ph.digest(msg)
+	if err != nil {
+		return nil, err
+	}
+	rnd, err := signingRandomness(rng)
+	if err != nil {
+		return nil, err
+	}
+	return prv.signInternal(formatMessage(1, ctx, oid, digest), rnd), nil
+}
+
+// VerifyHash returns true if sig is a valid HashML-DSA signature of msg
+// with context string ctx, hashed with ph (HashML-DSA.Verify, algorithm 5
+// of FIPS 204).
+func VerifyHash(pub *PublicKey, msg, ctx, sig []byte, ph PreHash) bool {
+	if len(ctx) > MaxContextSize {
+		return false
+	}
+	oid, digest, err := ph.digest(msg)
+	if err != nil {
+		return false
+	}
+	return pub.verifyInternal(formatMessage(1, ctx, oid, digest), sig)
+}
@@ -0,0 +1,371 @@
+package mldsa
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+var ids = []uint8{MLDSA44, MLDSA65, MLDSA87}
+
+// []byte encoded as hex string in JSON
+type hexBytes []byte
+
+func (b *hexBytes) UnmarshalJSON(data []byte) (err error) {
+	var s string
+	if err = json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*b, err = hex.DecodeString(s)
+	return err
+}
+
+// ACVP test vector files have a common structure, test groups with test
+// cases identified by tcId. Expected results are kept in a separate file.
+type acvpGroup struct {
+	TestType      string          `json:"testType"`
+	ParameterSet  string          `json:"parameterSet"`
+	Deterministic bool            `json:"deterministic"`
+	Pk            hexBytes        `json:"pk"`
+	Tests         json.RawMessage `json:"tests"`
+}
+
+type acvpTest struct {
+	TcID       int      `json:"tcId"`
+	Seed       hexBytes `json:"seed"`
+	Pk         hexBytes `json:"pk"`
+	Sk         hexBytes `json:"sk"`
+	Message    hexBytes `json:"message"`
+	Rnd        hexBytes `json:"rnd"`
+	Signature  hexBytes `json:"signature"`
+	TestPassed bool     `json:"testPassed"`
+}
+
+func readGzipJSON(t *testing.T, path string, v interface{}) {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = json.NewDecoder(r).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Returns test groups from prompt file and expected results indexed by tcId
+func loadACVP(t *testing.T, name string) ([]acvpGroup, map[int]acvpTest) {
+	var prompt, results struct {
+		TestGroups []acvpGroup `json:"testGroups"`
+	}
+	readGzipJSON(t, "testdata/"+name+"/prompt.json.gz", &prompt)
+	readGzipJSON(t, "testdata/"+name+"/expectedResults.json.gz", &results)
+
+	expected := make(map[int]acvpTest)
+	for _, g := range results.TestGroups {
+		for _, tc := range groupTests(t, g) {
+			expected[tc.TcID] = tc
+		}
+	}
+	return prompt.TestGroups, expected
+}
+
+func groupTests(t *testing.T, g acvpGroup) []acvpTest {
+	var tests []acvpTest
+	if err := json.Unmarshal(g.Tests, &tests); err != nil {
+		t.Fatal(err)
+	}
+	return tests
+}
+
+func idByName(t *testing.T, name string) uint8 {
+	for _, id := range ids {
+		if Params(id).Name == name {
+			return id
+		}
+	}
+	t.Fatalf("unknown parameter set %s", name)
+	return 0
+}
+
+func TestACVPKeyGen(t *testing.T) {
+	groups, expected := loadACVP(t, "ML-DSA-keyGen-FIPS204")
+	for _, g := range groups {
+		id := idByName(t, g.ParameterSet)
+		for _, tc := range groupTests(t, g) {
+			pub, prv, err := KeyGen(id, tc.Seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(pub.Export(), expected[tc.TcID].Pk) {
+				t.Errorf("tc=%d: pk doesn't match", tc.TcID)
+			}
+			if !bytes.Equal(prv.Export(), expected[tc.TcID].Sk) {
+				t.Errorf("tc=%d: sk doesn't match", tc.TcID)
+			}
+		}
+	}
+}
+
+// sigGen and sigVer vectors use internal interface
+func TestACVPSigGen(t *testing.T) {
+	groups, expected := loadACVP(t, "ML-DSA-sigGen-FIPS204")
+	for _, g := range groups {
+		id := idByName(t, g.ParameterSet)
+		for _, tc := range groupTests(t, g) {
+			prv := NewPrivateKey(id)
+			if err := prv.Import(tc.Sk); err != nil {
+				t.Fatalf("tc=%d: %v", tc.TcID, err)
+			}
+			rnd := make([]byte, RandomnessSize)
+			if !g.Deterministic {
+				rnd = tc.Rnd
+			}
+			sig := prv.signInternal(tc.Message, rnd)
+			if !bytes.Equal(sig, expected[tc.TcID].Signature) {
+				t.Errorf("tc=%d: signature doesn't match", tc.TcID)
+			}
+		}
+	}
+}
+
+func TestACVPSigVer(t *testing.T) {
+	groups, expected := loadACVP(t, "ML-DSA-sigVer-FIPS204")
+	for _, g := range groups {
+		id := idByName(t, g.ParameterSet)
+		pub := NewPublicKey(id)
+		if err := pub.Import(g.Pk); err != nil {
+			t.Fatal(err)
+		}
+		for _, tc := range groupTests(t, g) {
+			ok := pub.verifyInternal(tc.Message, tc.Signature)
+			if ok != expected[tc.TcID].TestPassed {
+				t.Errorf("tc=%d: expected %t", tc.TcID, expected[tc.TcID].TestPassed)
+			}
+		}
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	msg := []byte("message")
+	ctx := []byte("context")
+	for _, id := range ids {
+		params := Params(id)
+		pub, prv, err := GenerateKey(rand.Reader, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sig, err := Sign(rand.Reader, prv, msg, ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sig) != params.SignatureSize {
+			t.Fatalf("%s: wrong signature size", params.Name)
+		}
+		if !Verify(pub, msg, ctx, sig) {
+			t.Errorf("%s: valid signature rejected", params.Name)
+		}
+		if Verify(pub, msg, nil, sig) {
+			t.Errorf("%s: signature accepted with wrong context", params.Name)
+		}
+		if Verify(pub, msg[1:], ctx, sig) {
+			t.Errorf("%s: signature accepted for wrong message", params.Name)
+		}
+		if VerifyHash(pub, msg, ctx, sig, PreHash_SHA2_256) {
+			t.Errorf("%s: pure signature accepted as HashML-DSA", params.Name)
+		}
+		sig[len(sig)/2] ^= 1
+		if Verify(pub, msg, ctx, sig) {
+			t.Errorf("%s: modified signature accepted", params.Name)
+		}
+
+		if _, err = Sign(rand.Reader, prv, msg, make([]byte, MaxContextSize+1)); err == nil {
+			t.Errorf("%s: expected error for too long context", params.Name)
+		}
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	msg := []byte("message")
+	for _, id := range ids {
+		_, prv, err := GenerateKey(rand.Reader, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig1, err := Sign(nil, prv, msg, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig2, err := Sign(nil, prv, msg, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig3, err := Sign(rand.Reader, prv, msg, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sig1, sig2) {
+			t.Errorf("%s: deterministic signatures differ", Params(id).Name)
+		}
+		if bytes.Equal(sig1, sig3) {
+			t.Errorf("%s: hedged signature equal to deterministic", Params(id).Name)
+		}
+	}
+}
+
+func TestPreHash(t *testing.T) {
+	msg := []byte("message")
+	ctx := []byte("context")
+	pub, prv, err := GenerateKey(rand.Reader, MLDSA65)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for ph := PreHash_SHA2_224; ph <= PreHash_SHAKE256; ph++ {
+		sig, err := SignHash(rand.Reader, prv, msg, ctx, ph)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !VerifyHash(pub, msg, ctx, sig, ph) {
+			t.Errorf("%d: valid signature rejected", ph)
+		}
+		if Verify(pub, msg, ctx, sig) {
+			t.Errorf("%d: HashML-DSA signature accepted as pure", ph)
+		}
+		other := PreHash_SHA2_224 + (ph % PreHash_SHAKE256)
+		if VerifyHash(pub, msg, ctx, sig, other) {
+			t.Errorf("%d: signature accepted with wrong hash function", ph)
+		}
+	}
+	if _, err = SignHash(rand.Reader, prv, msg, ctx, 0); err == nil {
+		t.Error("expected error for unknown hash function")
+	}
+}
+
+func TestImportExport(t *testing.T) {
+	for _, id := range ids {
+		params := Params(id)
+		pub1, prv1, err := GenerateKey(rand.Reader, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub2, prv2 := NewPublicKey(id), NewPrivateKey(id)
+
+		pk, sk := pub1.Export(), prv1.Export()
+		if len(pk) != pub1.Size() || len(sk) != prv1.Size() {
+			t.Fatalf("%s: wrong key size", params.Name)
+		}
+		if err = pub2.Import(pk); err != nil {
+			t.Fatal(err)
+		}
+		if err = prv2.Import(sk); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(pub2.Export(), pk) || !bytes.Equal(prv2.Export(), sk) {
+			t.Errorf("%s: import/export failed", params.Name)
+		}
+		if !bytes.Equal(prv2.Public().Export(), pk) {
+			t.Errorf("%s: wrong public key recomputed", params.Name)
+		}
+
+		// Modified tr is detected
+		bad := append([]byte{}, sk...)
+		bad[64] ^= 1
+		if prv2.Import(bad) == nil {
+			t.Errorf("%s: inconsistent private key accepted", params.Name)
+		}
+		if prv2.Import(sk[1:]) == nil || pub2.Import(pk[1:]) == nil {
+			t.Errorf("%s: expected error for wrong key size", params.Name)
+		}
+	}
+}
+
+func TestHintEncoding(t *testing.T) {
+	params := Params(MLDSA44)
+	h := make([]poly, params.K)
+	h[0][3], h[0][200], h[2][0], h[3][255] = 1, 1, 1, 1
+	buf := make([]byte, params.Omega+params.K)
+	packHint(buf, h, params.Omega)
+
+	h2 := make([]poly, params.K)
+	if !unpackHint(h2, buf, params.Omega) {
+		t.Fatal("valid hint rejected")
+	}
+	for i := range h {
+		if h[i] != h2[i] {
+			t.Fatal("hint encoding failed")
+		}
+	}
+
+	// Indices not in increasing order
+	bad := append([]byte{}, buf...)
+	bad[0], bad[1] = bad[1], bad[0]
+	if unpackHint(h2, bad, params.Omega) {
+		t.Error("malformed hint accepted")
+	}
+	// Non-zero padding
+	bad = append([]byte{}, buf...)
+	bad[params.Omega-1] = 1
+	if unpackHint(h2, bad, params.Omega) {
+		t.Error("malformed hint accepted")
+	}
+}
+
+func TestNTT(t *testing.T) {
+	var p, r poly
+	var buf [4 * n]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		t.Fatal(err)
+	}
+	for i := range p {
+		p[i] = (uint32(buf[4*i]) | uint32(buf[4*i+1])<<8 | uint32(buf[4*i+2])<<16) % q
+	}
+	r = p
+	r.ntt()
+	r.invNtt()
+	if r != p {
+		t.Error("InvNTT(NTT(p)) != p")
+	}
+}
+
+func BenchmarkKeyGen(b *testing.B) {
+	for _, id := range ids {
+		b.Run(Params(id).Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				GenerateKey(rand.Reader, id)
+			}
+		})
+	}
+}
+
+func BenchmarkSign(b *testing.B) {
+	msg := []byte("message")
+	for _, id := range ids {
+		_, prv, _ := GenerateKey(rand.Reader, id)
+		b.Run(Params(id).Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Sign(rand.Reader, prv, msg, nil)
+			}
+		})
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	msg := []byte("message")
+	for _, id := range ids {
+		pub, prv, _ := GenerateKey(rand.Reader, id)
+		sig, _ := Sign(rand.Reader, prv, msg, nil)
+		b.Run(Params(id).Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Verify(pub, msg, nil, sig)
+			}
+		})
+	}
+}
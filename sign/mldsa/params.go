@@ -0,0 +1,123 @@
+package mldsa
+
+import "github.com/henrydcase/nobs/utils/security"
+
+// Id's of the parameter sets defined in FIPS 204
+const (
+	MLDSA44 uint8 = iota
+	MLDSA65
+	MLDSA87
+	maxParamsId
+)
+
+const (
+	// Size of the seed used by KeyGen
+	SeedSize = 32
+	// Size of the randomness used by hedged signing
+	RandomnessSize = 32
+	// Maximal size of the context string
+	MaxContextSize = 255
+)
+
+// Domain parameters of ML-DSA
+type MldsaParams struct {
+	Id   uint8
+	Name string
+	// Dimensions of the matrix A
+	K, L int
+	// Bound of coefficients of the private key
+	Eta int
+	// Number of +/-1 coefficients of the challenge
+	Tau int
+	// Bound of the masking vector y
+	Gamma1 int32
+	// Low-order rounding range
+	Gamma2 uint32
+	// Maximal number of 1's in the hint
+	Omega int
+	// Collision strength of the challenge seed, in bits
+	Lambda int
+	// Sizes in bytes
+	PublicKeySize  int
+	PrivateKeySize int
+	SignatureSize  int
+	// Security properties
+	Meta security.Metadata
+}
+
+// Keeps mapping: parameter set ID to domain parameters
+var mldsaParams = make(map[uint8]MldsaParams)
+
+// Params returns domain parameters identified by `id`. Function panics
+// in case `id` wasn't registered earlier.
+func Params(id uint8) *MldsaParams {
+	if val, ok := mldsaParams[id]; ok {
+		return &val
+	}
+	panic("mldsa: ML-DSA Params ID unregistered")
+}
+
+// Beta = tau * eta
+func (p *MldsaParams) beta() int32 {
+	return int32(p.Tau * p.Eta)
+}
+
+// Number of bits used to encode coefficients of s1 and s2
+func (p *MldsaParams) etaBits() uint {
+	if p.Eta == 2 {
+		return 3
+	}
+	return 4
+}
+
+// Number of bits used to encode coefficients of z
+func (p *MldsaParams) gamma1Bits() uint {
+	if p.Gamma1 == 1<<17 {
+		return 18
+	}
+	return 20
+}
+
+// Number of bits used to encode coefficients of w1
+func (p *MldsaParams) w1Bits() uint {
+	if p.Gamma2 == gamma2_88 {
+		return 6
+	}
+	return 4
+}
+
+// Size of the challenge seed c~ in bytes
+func (p *MldsaParams) ctildeSize() int {
+	return p.Lambda / 4
+}
+
+func newParams(id uint8, name string, level, k, l, eta, tau int, gamma1 int32, gamma2 uint32, omega, lambda int) MldsaParams {
+	p := MldsaParams{
+		Id:     id,
+		Name:   name,
+		K:      k,
+		L:      l,
+		Eta:    eta,
+		Tau:    tau,
+		Gamma1: gamma1,
+		Gamma2: gamma2,
+		Omega:  omega,
+		Lambda: lambda,
+		Meta: security.Metadata{
+			Name:      name,
+			Level:     level,
+			Status:    security.Standardized,
+			Reference: "FIPS 204, https://doi.org/10.6028/NIST.FIPS.204",
+		},
+	}
+	p.PublicKeySize = 32 + k*32*(23-d)
+	p.PrivateKeySize = 128 + 32*((k+l)*int(p.etaBits())+d*k)
+	p.SignatureSize = p.ctildeSize() + l*32*int(p.gamma1Bits()) + omega + k
+	return p
+}
+
+func init() {
+	mldsaParams[MLDSA44] = newParams(MLDSA44, "ML-DSA-44", 2, 4, 4, 2, 39, 1<<17, gamma2_88, 80, 128)
+	mldsaParams[MLDSA65] = newParams(MLDSA65, "ML-DSA-65", 3, 6, 5, 4, 49, 1<<19, gamma2_32, 55, 192)
+	mldsaParams[MLDSA87] = newParams(MLDSA87, "ML-DSA-87", 5, 8, 7, 2, 60, 1<<19, gamma2_32, 75, 256)
+}
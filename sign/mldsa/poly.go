@@ -0,0 +1,318 @@
+package mldsa
+
+import (
+	"github.com/henrydcase/nobs/hash/sha3"
+)
+
+const (
+	// Modulus of the field Z_q
+	q = 8380417
+	// Number of coefficients of a polynomial
+	n = 256
+	// Number of bits dropped from t
+	d = 13
+	// 256^-1 mod q, scales the result of the inverse NTT
+	invN = 8347681
+
+	// Possible values of gamma2
+	gamma2_88 = (q - 1) / 88
+	gamma2_32 = (q - 1) / 32
+)
+
+// Polynomial from R_q = Z_q[X]/(X^256 + 1), either in normal or in NTT
+// domain. Coefficients are always kept in [0, q).
+type poly [n]uint32
+
+// zetas[i] = 1753^BitRev8(i) mod q
+var zetas = [n]uint32{
+	1, 4808194, 3765607, 3761513, 5178923, 5496691, 5234739, 5178987,
+	7778734, 3542485, 2682288, 2129892, 3764867, 7375178, 557458, 7159240,
+	5010068, 4317364, 2663378, 6705802, 4855975, 7946292, 676590, 7044481,
+	5152541, 1714295, 2453983, 1460718, 7737789, 4795319, 2815639, 2283733,
+	3602218, 3182878, 2740543, 4793971, 5269599, 2101410, 3704823, 1159875,
+	394148, 928749, 1095468, 4874037, 2071829, 4361428, 3241972, 2156050,
+	3415069, 1759347, 7562881, 4805951, 3756790, 6444618, 6663429, 4430364,
+	5483103, 3192354, 556856, 3870317, 2917338, 1853806, 3345963, 1858416,
+	3073009, 1277625, 5744944, 3852015, 4183372, 5157610, 5258977, 8106357,
+	2508980, 2028118, 1937570, 4564692, 2811291, 5396636, 7270901, 4158088,
+	1528066, 482649, 1148858, 5418153, 7814814, 169688, 2462444, 5046034,
+	4213992, 4892034, 1987814, 5183169, 1736313, 235407, 5130263, 3258457,
+	5801164, 1787943, 5989328, 6125690, 3482206, 4197502, 7080401, 6018354,
+	7062739, 2461387, 3035980, 621164, 3901472, 7153756, 2925816, 3374250,
+	1356448, 5604662, 2683270, 5601629, 4912752, 2312838, 7727142, 7921254,
+	348812, 8052569, 1011223, 6026202, 4561790, 6458164, 6143691, 1744507,
+	1753, 6444997, 5720892, 6924527, 2660408, 6600190, 8321269, 2772600,
+	1182243, 87208, 636927, 4415111, 4423672, 6084020, 5095502, 4663471,
+	8352605, 822541, 1009365, 5926272, 6400920, 1596822, 4423473, 4620952,
+	6695264, 4969849, 2678278, 4611469, 4829411, 635956, 8129971, 5925040,
+	4234153, 6607829, 2192938, 6653329, 2387513, 4768667, 8111961, 5199961,
+	3747250, 2296099, 1239911, 4541938, 3195676, 2642980, 1254190, 8368000,
+	2998219, 141835, 8291116, 2513018, 7025525, 613238, 7070156, 6161950,
+	7921677, 6458423, 4040196, 4908348, 2039144, 6500539, 7561656, 6201452,
+	6757063, 2105286, 6006015, 6346610, 586241, 7200804, 527981, 5637006,
+	6903432, 1994046, 2491325, 6987258, 507927, 7192532, 7655613, 6545891,
+	5346675, 8041997, 2647994, 3009748, 5767564, 4148469, 749577, 4357667,
+	3980599, 2569011, 6764887, 1723229, 1665318, 2028038, 1163598, 5011144,
+	3994671, 8368538, 7009900, 3020393, 3363542, 214880, 545376, 7609976,
+	3105558, 7277073, 508145, 7826699, 860144, 3430436, 140244, 6866265,
+	6195333, 3123762, 2358373, 6187330, 5365997, 6663603, 2926054, 7987710,
+	8077412, 3531229, 4405932, 4606686, 1900052, 7598542, 1054478, 7648983,
+}
+
+// Returns x mod q for x < 2q. Constant time.
+func reduceOnce(x uint32) uint32 {
+	x -= q
+	// if x underflowed, top bit is set
+	x += uint32(int32(x)>>31) & q
+	return x
+}
+
+func fieldAdd(a, b uint32) uint32 {
+	return reduceOnce(a + b)
+}
+
+func fieldSub(a, b uint32) uint32 {
+	return reduceOnce(a - b + q)
+}
+
+func fieldMul(a, b uint32) uint32 {
+	// Division by constant is compiled to multiplication, so it's
+	// constant time.
+	return uint32((uint64(a) * uint64(b)) % q)
+}
+
+// Returns representative of x from (-(q-1)/2, (q-1)/2]. Constant time.
+func centered(x uint32) int32 {
+	v := int32(x)
+	return v - (int32((q-1)/2-x)>>31)&q
+}
+
+// Returns x mod q for x from (-q, q). Constant time.
+func fromSigned(x int32) uint32 {
+	return uint32(x + (x>>31)&q)
+}
+
+// Sets p = a + b
+func (p *poly) add(a, b *poly) {
+	for i := range p {
+		p[i] = fieldAdd(a[i], b[i])
+	}
+}
+
+// Sets p = a - b
+func (p *poly) sub(a, b *poly) {
+	for i := range p {
+		p[i] = fieldSub(a[i], b[i])
+	}
+}
+
+// Sets p = a * b, where a and b are in NTT domain
+func (p *poly) mulNtt(a, b *poly) {
+	for i := range p {
+		p[i] = fieldMul(a[i], b[i])
+	}
+}
+
+// Sets p = p + a * b, where a and b are in NTT domain
+func (p *poly) mulAccNtt(a, b *poly) {
+	for i := range p {
+		p[i] = fieldAdd(p[i], fieldMul(a[i], b[i]))
+	}
+}
+
+// Computes NTT of p in place (algorithm 41 of FIPS 204)
+func (p *poly) ntt() {
+	m := 0
+	for l := n / 2; l >= 1; l /= 2 {
+		for start := 0; start < n; start += 2 * l {
+			m++
+			zeta := zetas[m]
+			for j := start; j < start+l; j++ {
+				t := fieldMul(zeta, p[j+l])
+				p[j+l] = fieldSub(p[j], t)
+				p[j] = fieldAdd(p[j], t)
+			}
+		}
+	}
+}
+
+// Computes inverse NTT of p in place (algorithm 42 of FIPS 204)
+func (p *poly) invNtt() {
+	m := n
+	for l := 1; l < n; l *= 2 {
+		for start := 0; start < n; start += 2 * l {
+			m--
+			zeta := q - zetas[m]
+			for j := start; j < start+l; j++ {
+				t := p[j]
+				p[j] = fieldAdd(t, p[j+l])
+				p[j+l] = fieldMul(zeta, fieldSub(t, p[j+l]))
+			}
+		}
+	}
+	for i := range p {
+		p[i] = fieldMul(p[i], invN)
+	}
+}
+
+// Returns true if infinity norm of p is at least bound. Constant time
+// with respect to coefficients.
+func (p *poly) exceeds(bound int32) bool {
+	var acc int32
+	for _, c := range p {
+		v := centered(c)
+		// absolute value
+		v = (v ^ (v >> 31)) - (v >> 31)
+		acc |= bound - 1 - v
+	}
+	return acc < 0
+}
+
+// Samples polynomial in NTT domain from the output of SHAKE128(rho||s||r)
+// with rejection sampling (algorithm 30 of FIPS 204). Not constant time,
+// rho is public.
+func (p *poly) sampleNtt(rho []byte, s, r byte) {
+	var buf [168]byte
+	h := sha3.NewShake128()
+	h.Write(rho)
+	h.Write([]byte{s, r})
+
+	for c := 0; c < n; {
+		h.Read(buf[:])
+		for k := 0; k < len(buf) && c < n; k += 3 {
+			v := uint32(buf[k]) | uint32(buf[k+1])<<8 | uint32(buf[k+2]&0x7F)<<16
+			if v < q {
+				p[c] = v
+				c++
+			}
+		}
+	}
+}
+
+// Samples polynomial with coefficients from [-eta, eta] from the output of
+// SHAKE256(rho||nonce) with rejection sampling (algorithm 31 of FIPS 204).
+// Rejection depends only on random bytes, not on accepted values.
+func (p *poly) sampleBounded(rho []byte, nonce uint16, eta int) {
+	var buf [136]byte
+	h := sha3.NewShake256()
+	h.Write(rho)
+	h.Write([]byte{byte(nonce), byte(nonce >> 8)})
+
+	// Returns coefficient and true if b is accepted
+	fromHalfByte := func(b byte) (uint32, bool) {
+		if eta == 2 && b < 15 {
+			return fromSigned(2 - int32(b%5)), true
+		}
+		if eta == 4 && b < 9 {
+			return fromSigned(4 - int32(b)), true
+		}
+		return 0, false
+	}
+
+	for c := 0; c < n; {
+		h.Read(buf[:])
+		for k := 0; k < len(buf) && c < n; k++ {
+			if v, ok := fromHalfByte(buf[k] & 0xF); ok {
+				p[c] = v
+				c++
+			}
+			if v, ok := fromHalfByte(buf[k] >> 4); ok && c < n {
+				p[c] = v
+				c++
+			}
+		}
+	}
+}
+
+// Samples polynomial with tau coefficients equal to +/-1 and the rest
+// equal to 0, from the seed (algorithm 29 of FIPS 204).
+func (p *poly) sampleInBall(seed []byte, tau int) {
+	var buf [136]byte
+	h := sha3.NewShake256()
+	h.Write(seed)
+	h.Read(buf[:])
+
+	var signs uint64
+	for i := 0; i < 8; i++ {
+		signs |= uint64(buf[i]) << (8 * uint(i))
+	}
+	pos := 8
+
+	*p = poly{}
+	for i := n - tau; i < n; i++ {
+		var j int
+		for {
+			if pos == len(buf) {
+				h.Read(buf[:])
+				pos = 0
+			}
+			j = int(buf[pos])
+			pos++
+			if j <= i {
+				break
+			}
+		}
+		p[i] = p[j]
+		p[j] = fromSigned(1 - 2*int32(signs&1))
+		signs >>= 1
+	}
+}
+
+// Splits r into r1*2^d + r0 with r0 from (-2^(d-1), 2^(d-1)] (algorithm
+// 35 of FIPS 204). Returns r1 and r0 mod q.
+func power2Round(r uint32) (uint32, uint32) {
+	r0 := int32(r & (1<<d - 1))
+	// r0 > 2^(d-1) ? r0 - 2^d : r0
+	r0 -= ((1<<(d-1) - r0) >> 31) & (1 << d)
+	return uint32(int32(r)-r0) >> d, fromSigned(r0)
+}
+
+// Splits r into r1*2*gamma2 + r0 with r0 from (-gamma2, gamma2] (algorithm
+// 36 of FIPS 204). Constant time.
+func decompose(r uint32, gamma2 uint32) (uint32, int32) {
+	var r1 uint32
+	var r0 int32
+	// Divisions by constants are compiled to multiplications
+	switch gamma2 {
+	case gamma2_88:
+		r0 = int32(r % (2 * gamma2_88))
+		r0 -= ((gamma2_88 - r0) >> 31) & (2 * gamma2_88)
+		r1 = uint32(int32(r)-r0) / (2 * gamma2_88)
+	case gamma2_32:
+		r0 = int32(r % (2 * gamma2_32))
+		r0 -= ((gamma2_32 - r0) >> 31) & (2 * gamma2_32)
+		r1 = uint32(int32(r)-r0) / (2 * gamma2_32)
+	default:
+		panic("mldsa: unsupported gamma2")
+	}
+	// if r - r0 = q - 1 then r1 = 0 and r0 = r0 - 1. Mask m is all ones
+	// in such case.
+	x := uint32(int32(r)-r0) ^ (q - 1)
+	m := uint32(int32(x-1) >> 31)
+	r1 &^= m
+	r0 -= int32(m & 1)
+	return r1, r0
+}
+
+// Returns 1 if adding z to r changes high bits of r (algorithm 39 of
+// FIPS 204).
+func makeHint(z, r uint32, gamma2 uint32) uint32 {
+	r1, _ := decompose(r, gamma2)
+	v1, _ := decompose(fieldAdd(r, z), gamma2)
+	x := r1 ^ v1
+	return (x | -x) >> 31
+}
+
+// Returns high bits of r adjusted according to the hint h (algorithm 40
+// of FIPS 204). Executed on public data only.
+func useHint(h, r uint32, gamma2 uint32) uint32 {
+	m := (q - 1) / (2 * gamma2)
+	r1, r0 := decompose(r, gamma2)
+	if h == 1 {
+		if r0 > 0 {
+			return (r1 + 1) % m
+		}
+		return (r1 + m - 1) % m
+	}
+	return r1
+}
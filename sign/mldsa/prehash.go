@@ -0,0 +1,93 @@
+package mldsa
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"hash"
+
+	"github.com/henrydcase/nobs/hash/sha3"
+)
+
+// Identifies hash function used by HashML-DSA to pre-hash the message
+type PreHash uint8
+
+const (
+	PreHash_SHA2_224 PreHash = iota + 1
+	PreHash_SHA2_256
+	PreHash_SHA2_384
+	PreHash_SHA2_512
+	PreHash_SHA2_512_224
+	PreHash_SHA2_512_256
+	PreHash_SHA3_224
+	PreHash_SHA3_256
+	PreHash_SHA3_384
+	PreHash_SHA3_512
+	// SHAKE128 with 256-bit output
+	PreHash_SHAKE128
+	// SHAKE256 with 512-bit output
+	PreHash_SHAKE256
+)
+
+// DER encoding of the OID 2.16.840.1.101.3.4.2, prefix common to all
+// supported hash functions. Last byte of the OID depends on the function.
+var hashOidPrefix = []byte{0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02}
+
+// Last byte of the OID for each function
+var hashOidSuffix = map[PreHash]byte{
+	PreHash_SHA2_256:     0x01,
+	PreHash_SHA2_384:     0x02,
+	PreHash_SHA2_512:     0x03,
+	PreHash_SHA2_224:     0x04,
+	PreHash_SHA2_512_224: 0x05,
+	PreHash_SHA2_512_256: 0x06,
+	PreHash_SHA3_224:     0x07,
+	PreHash_SHA3_256:     0x08,
+	PreHash_SHA3_384:     0x09,
+	PreHash_SHA3_512:     0x0A,
+	PreHash_SHAKE128:     0x0B,
+	PreHash_SHAKE256:     0x0C,
+}
+
+// Returns DER encoded OID of the hash function and digest of msg.
+func (ph PreHash) digest(msg []byte) ([]byte, []byte, error) {
+	var h hash.Hash
+	suffix, ok := hashOidSuffix[ph]
+	if !ok {
+		return nil, nil, errors.New("mldsa: unsupported pre-hash function")
+	}
+	oid := append(append([]byte{}, hashOidPrefix...), suffix)
+
+	switch ph {
+	case PreHash_SHAKE128:
+		out := make([]byte, 32)
+		sha3.ShakeSum128(out, msg)
+		return oid, out, nil
+	case PreHash_SHAKE256:
+		out := make([]byte, 64)
+		sha3.ShakeSum256(out, msg)
+		return oid, out, nil
+	case PreHash_SHA2_224:
+		h = sha256.New224()
+	case PreHash_SHA2_256:
+		h = sha256.New()
+	case PreHash_SHA2_384:
+		h = sha512.New384()
+	case PreHash_SHA2_512:
+		h = sha512.New()
+	case PreHash_SHA2_512_224:
+		h = sha512.New512_224()
+	case PreHash_SHA2_512_256:
+		h = sha512.New512_256()
+	case PreHash_SHA3_224:
+		h = sha3.New224()
+	case PreHash_SHA3_256:
+		h = sha3.New256()
+	case PreHash_SHA3_384:
+		h = sha3.New384()
+	case PreHash_SHA3_512:
+		h = sha3.New512()
+	}
+	h.Write(msg)
+	return oid, h.Sum(nil), nil
+}
@@ -0,0 +1,65 @@
+package slhdsa
+
+import (
+	"encoding/binary"
+)
+
+// Types of the address (section 4.2 of FIPS 205)
+const (
+	addrWotsHash uint32 = iota
+	addrWotsPk
+	addrTree
+	addrForsTree
+	addrForsRoots
+	addrWotsPrf
+	addrForsPrf
+)
+
+// Address (ADRS) used for domain separation of hash calls. Layout:
+// layer (4) | tree (12) | type (4) | three words (4 each), big-endian.
+type address [32]byte
+
+func (a *address) setLayer(l uint32) {
+	binary.BigEndian.PutUint32(a[0:], l)
+}
+
+func (a *address) setTree(t uint64) {
+	binary.BigEndian.PutUint32(a[4:], 0)
+	binary.BigEndian.PutUint64(a[8:], t)
+}
+
+// Sets type and clears remaining words
+func (a *address) setTypeAndClear(t uint32) {
+	binary.BigEndian.PutUint32(a[16:], t)
+	for i := 20; i < len(a); i++ {
+		a[i] = 0
+	}
+}
+
+func (a *address) setKeyPair(i uint32) {
+	binary.BigEndian.PutUint32(a[20:], i)
+}
+
+func (a *address) keyPair() uint32 {
+	return binary.BigEndian.Uint32(a[20:])
+}
+
+func (a *address) setChain(i uint32) {
+	binary.BigEndian.PutUint32(a[24:], i)
+}
+
+func (a *address) setTreeHeight(z uint32) {
+	binary.BigEndian.PutUint32(a[24:], z)
+}
+
+func (a *address) setHash(i uint32) {
+	binary.BigEndian.PutUint32(a[28:], i)
+}
+
+func (a *address) setTreeIndex(i uint32) {
+	binary.BigEndian.PutUint32(a[28:], i)
+}
+
+func (a *address) treeIndex() uint32 {
+	return binary.BigEndian.Uint32(a[28:])
+}
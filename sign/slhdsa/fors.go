@@ -0,0 +1,74 @@
+package slhdsa
+
+// Computes secret value of the FORS leaf with global index i (algorithm
+// 14 of FIPS 205). adrs must have type addrForsTree and key pair set.
+func (h *hasher) forsSecret(out []byte, i uint32, adrs *address) {
+	skAdrs := *adrs
+	skAdrs.setTypeAndClear(addrForsPrf)
+	skAdrs.setKeyPair(adrs.keyPair())
+	skAdrs.setTreeIndex(i)
+	h.prf(out, &skAdrs)
+}
+
+// Computes i-th node at height z of the FORS trees given by adrs. The
+// index i is global, counted over nodes at height z of all K trees.
+func (h *hasher) forsNode(out []byte, adrs address, z, i uint32, in ...[]byte) {
+	adrs.setTreeHeight(z)
+	adrs.setTreeIndex(i)
+	h.hash(out, &adrs, in...)
+}
+
+// Computes t-th FORS signature element of the leaf idx: secret value
+// followed by authentication path, and the root of t-th FORS tree
+// (algorithms 15 and 16 of FIPS 205).
+func (h *hasher) forsTree(root, sig []byte, adrs address, t, idx uint32) {
+	n := h.params.N
+	a := uint(h.params.A)
+
+	h.forsSecret(sig[:n], t<<a+idx, &adrs)
+	leaf := func(out []byte, i uint32) {
+		h.forsSecret(out, t<<a+i, &adrs)
+		h.forsNode(out, adrs, 0, t<<a+i, out)
+	}
+	node := func(out []byte, z, i uint32, l, r []byte) {
+		h.forsNode(out, adrs, z, t<<(a-uint(z))+i, l, r)
+	}
+	h.treeHash(root, sig[n:], h.params.A, idx, leaf, node)
+}
+
+// Compresses FORS roots into FORS public key
+func (h *hasher) forsCompress(out, roots []byte, adrs *address) {
+	pkAdrs := *adrs
+	pkAdrs.setTypeAndClear(addrForsRoots)
+	pkAdrs.setKeyPair(adrs.keyPair())
+	h.hash(out, &pkAdrs, roots)
+}
+
+// Computes FORS public key from the signature of the message digest md
+// (algorithm 17 of FIPS 205). adrs must have type addrForsTree and key
+// pair set.
+func (h *hasher) forsPkFromSig(out, sig, md []byte, adrs *address) {
+	n := h.params.N
+	a := uint(h.params.A)
+	indices := make([]uint32, h.params.K)
+	roots := make([]byte, h.params.K*n)
+	base2b(indices, md, a)
+
+	for t, idx := range indices {
+		s := sig[t*(h.params.A+1)*n:]
+		node := roots[t*n : (t+1)*n]
+		i := uint32(t)<<a + idx
+		h.forsNode(node, *adrs, 0, i, s[:n])
+
+		auth := s[n:]
+		for j := uint32(0); j < uint32(a); j++ {
+			i >>= 1
+			if (idx>>j)&1 == 0 {
+				h.forsNode(node, *adrs, j+1, i, node, auth[j*uint32(n):(j+1)*uint32(n)])
+			} else {
+				h.forsNode(node, *adrs, j+1, i, auth[j*uint32(n):(j+1)*uint32(n)], node)
+			}
+		}
+	}
+	h.forsCompress(out, roots, adrs)
+}
@@ -0,0 +1,83 @@
+package slhdsa
+
+import (
+	"github.com/henrydcase/nobs/hash/sha3"
+)
+
+// Keeps state needed by tweakable hash functions F, H, T_l and PRF
+// instantiated with SHAKE256 (section 11.1 of FIPS 205). Not safe for
+// concurrent use, each goroutine needs own instance.
+type hasher struct {
+	params *SlhdsaParams
+	pkSeed []byte
+	// nil for verification
+	skSeed []byte
+	h      sha3.ShakeHash
+	// Scratch buffer for WOTS+ chains
+	wotsBuf []byte
+}
+
+func newHasher(params *SlhdsaParams, pkSeed, skSeed []byte) *hasher {
+	return &hasher{
+		params:  params,
+		pkSeed:  pkSeed,
+		skSeed:  skSeed,
+		h:       sha3.NewShake256(),
+		wotsBuf: make([]byte, params.wotsLen()*params.N),
+	}
+}
+
+// Computes SHAKE256(PK.seed || ADRS || in_1 || ... || in_l). Implements F,
+// H and T_l. out may overlap with inputs.
+func (h *hasher) hash(out []byte, adrs *address, in ...[]byte) {
+	h.h.Reset()
+	h.h.Write(h.pkSeed)
+	h.h.Write(adrs[:])
+	for _, v := range in {
+		h.h.Write(v)
+	}
+	h.h.Read(out[:h.params.N])
+}
+
+// PRF(PK.seed, SK.seed, ADRS)
+func (h *hasher) prf(out []byte, adrs *address) {
+	h.hash(out, adrs, h.skSeed)
+}
+
+// Computes SHAKE256 of concatenation of inputs into out
+func shake256(out []byte, in ...[]byte) {
+	h := sha3.NewShake256()
+	for _, v := range in {
+		h.Write(v)
+	}
+	h.Read(out)
+}
+
+// Splits x into outLen integers of b bits each, big-endian bit order
+// (algorithm 4 of FIPS 205).
+func base2b(out []uint32, x []byte, b uint) {
+	var total uint64
+	var bits uint
+	var pos int
+	for i := range out {
+		for bits < b {
+			total = total<<8 | uint64(x[pos])
+			pos++
+			bits += 8
+		}
+		bits -= b
+		out[i] = uint32(total>>bits) & (1<<b - 1)
+	}
+}
+
+// Interprets x as big-endian integer and returns it modulo 2^bits
+func toInt(x []byte, bits uint) uint64 {
+	var v uint64
+	for _, b := range x {
+		v = v<<8 | uint64(b)
+	}
+	if bits < 64 {
+		v &= 1<<bits - 1
+	}
+	return v
+}
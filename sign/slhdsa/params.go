@@ -0,0 +1,113 @@
+package slhdsa
+
+import "github.com/henrydcase/nobs/utils/security"
+
+// Id's of the SHAKE parameter sets defined in FIPS 205. Sets ending
+// with "s" produce small signatures, sets ending with "f" are fast.
+const (
+	SHAKE128s uint8 = iota
+	SHAKE128f
+	SHAKE192s
+	SHAKE192f
+	SHAKE256s
+	SHAKE256f
+	maxParamsId
+)
+
+const (
+	// Maximal size of the context string
+	MaxContextSize = 255
+
+	// Winternitz parameter w = 2^lgW
+	lgW = 4
+	w   = 1 << lgW
+	// Number of WOTS+ checksum chains
+	wotsLen2 = 3
+)
+
+// Domain parameters of SLH-DSA
+type SlhdsaParams struct {
+	Id   uint8
+	Name string
+	// Security parameter, size of hash outputs in bytes
+	N int
+	// Total height of the hypertree
+	H int
+	// Number of layers of the hypertree
+	D int
+	// Height of a single XMSS tree, H/D
+	Hp int
+	// Height of FORS trees
+	A int
+	// Number of FORS trees
+	K int
+	// Size of the message digest in bytes
+	M int
+	// Sizes in bytes
+	PublicKeySize  int
+	PrivateKeySize int
+	SignatureSize  int
+	// Security properties
+	Meta security.Metadata
+}
+
+// Keeps mapping: parameter set ID to domain parameters
+var slhdsaParams = make(map[uint8]SlhdsaParams)
+
+// Params returns domain parameters identified by `id`. Function panics
+// in case `id` wasn't registered earlier.
+func Params(id uint8) *SlhdsaParams {
+	if val, ok := slhdsaParams[id]; ok {
+		return &val
+	}
+	panic("slhdsa: SLH-DSA Params ID unregistered")
+}
+
+// Number of WOTS+ chains
+func (p *SlhdsaParams) wotsLen() int {
+	return 2*p.N + wotsLen2
+}
+
+// Size of WOTS+ signature together with XMSS authentication path
+func (p *SlhdsaParams) xmssSigSize() int {
+	return (p.wotsLen() + p.Hp) * p.N
+}
+
+// Size of FORS signature
+func (p *SlhdsaParams) forsSigSize() int {
+	return p.K * (p.A + 1) * p.N
+}
+
+func newParams(id uint8, name string, n, h, d, a, k, m int) SlhdsaParams {
+	p := SlhdsaParams{
+		Id:   id,
+		Name: name,
+		N:    n,
+		H:    h,
+		D:    d,
+		Hp:   h / d,
+		A:    a,
+		K:    k,
+		M:    m,
+		Meta: security.Metadata{
+			Name: name,
+			// Categories 1, 3 and 5 for n = 16, 24 and 32
+			Level:     n/4 - 3,
+			Status:    security.Standardized,
+			Reference: "FIPS 205, https://doi.org/10.6028/NIST.FIPS.205",
+		},
+	}
+	p.PublicKeySize = 2 * n
+	p.PrivateKeySize = 4 * n
+	p.SignatureSize = n + p.forsSigSize() + d*p.xmssSigSize()
+	return p
+}
+
+func init() {
+	slhdsaParams[SHAKE128s] = newParams(SHAKE128s, "SLH-DSA-SHAKE-128s", 16, 63, 7, 12, 14, 30)
+	slhdsaParams[SHAKE128f] = newParams(SHAKE128f, "SLH-DSA-SHAKE-128f", 16, 66, 22, 6, 33, 34)
+	slhdsaParams[SHAKE192s] = newParams(SHAKE192s, "SLH-DSA-SHAKE-192s", 24, 63, 7, 14, 17, 39)
+	slhdsaParams[SHAKE192f] = newParams(SHAKE192f, "SLH-DSA-SHAKE-192f", 24, 66, 22, 8, 33, 42)
+	slhdsaParams[SHAKE256s] = newParams(SHAKE256s, "SLH-DSA-SHAKE-256s", 32, 64, 8, 14, 22, 47)
+	slhdsaParams[SHAKE256f] = newParams(SHAKE256f, "SLH-DSA-SHAKE-256f", 32, 68, 17, 9, 35, 49)
+}
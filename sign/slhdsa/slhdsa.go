@@ -0,0 +1,332 @@
+// Package slhdsa implements SLH-DSA, the stateless hash-based digital
+// signature algorithm standardized in FIPS 205, with SHAKE parameter sets
+// SLH-DSA-SHAKE-128s/128f/192s/192f/256s/256f.
+//
+// Signing is hedged, unless nil is passed as a source of randomness, in
+// which case deterministic variant is used. Computation of the FORS and
+// XMSS trees needed by the signature is spread across GOMAXPROCS
+// goroutines.
+//
+// [FIPS205] https://doi.org/10.6028/NIST.FIPS.205
+package slhdsa
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// Defines operations on public key
+type PublicKey struct {
+	params *SlhdsaParams
+	seed   []byte
+	root   []byte
+}
+
+// Defines operations on private key
+type PrivateKey struct {
+	params *SlhdsaParams
+	skSeed []byte
+	skPrf  []byte
+	pub    PublicKey
+}
+
+// NewPrivateKey initializes private key.
+// Usage of this function guarantees that the object is correctly initialized.
+func NewPrivateKey(id uint8) *PrivateKey {
+	params := Params(id)
+	return &PrivateKey{
+		params: params,
+		skSeed: make([]byte, params.N),
+		skPrf:  make([]byte, params.N),
+		pub:    *NewPublicKey(id),
+	}
+}
+
+// NewPublicKey initializes public key.
+// Usage of this function guarantees that the object is correctly initialized.
+func NewPublicKey(id uint8) *PublicKey {
+	params := Params(id)
+	return &PublicKey{
+		params: params,
+		seed:   make([]byte, params.N),
+		root:   make([]byte, params.N),
+	}
+}
+
+// Accessor to the domain parameters
+func (pub *PublicKey) Params() *SlhdsaParams {
+	return pub.params
+}
+
+// Accessor to the domain parameters
+func (prv *PrivateKey) Params() *SlhdsaParams {
+	return prv.params
+}
+
+// Public returns public key corresponding to the private key
+func (prv *PrivateKey) Public() *PublicKey {
+	return &prv.pub
+}
+
+// Size returns size of the public key in bytes
+func (pub *PublicKey) Size() int {
+	return pub.params.PublicKeySize
+}
+
+// Size returns size of the private key in bytes
+func (prv *PrivateKey) Size() int {
+	return prv.params.PrivateKeySize
+}
+
+// Exports public key as PK.seed || PK.root
+func (pub *PublicKey) Export() []byte {
+	out := make([]byte, 0, pub.params.PublicKeySize)
+	return append(append(out, pub.seed...), pub.root...)
+}
+
+// Import clears content of the public key and imports key stored in the
+// byte string. Returns error in case of wrong size.
+func (pub *PublicKey) Import(input []byte) error {
+	n := pub.params.N
+	if len(input) != pub.params.PublicKeySize {
+		return errors.New("slhdsa: wrong size of the public key")
+	}
+	copy(pub.seed, input[:n])
+	copy(pub.root, input[n:])
+	return nil
+}
+
+// Exports private key as SK.seed || SK.prf || PK.seed || PK.root
+func (prv *PrivateKey) Export() []byte {
+	out := make([]byte, 0, prv.params.PrivateKeySize)
+	out = append(append(out, prv.skSeed...), prv.skPrf...)
+	return append(out, prv.pub.Export()...)
+}
+
+// Import clears content of the private key and imports key stored in the
+// byte string. PK.root is recomputed. Returns error in case of wrong size
+// or if PK.root doesn't match SK.seed and PK.seed, in which case the key
+// is not modified.
+func (prv *PrivateKey) Import(input []byte) error {
+	n := prv.params.N
+	if len(input) != prv.params.PrivateKeySize {
+		return errors.New("slhdsa: wrong size of the private key")
+	}
+	key := NewPrivateKey(prv.params.Id)
+	copy(key.skSeed, input[:n])
+	copy(key.skPrf, input[n:2*n])
+	copy(key.pub.seed, input[2*n:3*n])
+	key.computeRoot()
+	if subtle.ConstantTimeCompare(key.pub.root, input[3*n:]) != 1 {
+		return errors.New("slhdsa: inconsistent private key")
+	}
+	copy(prv.skSeed, key.skSeed)
+	copy(prv.skPrf, key.skPrf)
+	copy(prv.pub.seed, key.pub.seed)
+	copy(prv.pub.root, key.pub.root)
+	return nil
+}
+
+// Computes PK.root, root of the XMSS tree on the top layer of the
+// hypertree.
+func (prv *PrivateKey) computeRoot() {
+	var adrs address
+	params := prv.params
+	h := newHasher(params, prv.pub.seed, prv.skSeed)
+	adrs.setLayer(uint32(params.D - 1))
+	h.xmssTree(prv.pub.root, make([]byte, params.Hp*params.N), adrs, 0)
+}
+
+// KeyGen derives key pair from SK.seed, SK.prf and PK.seed, each of N
+// bytes (slh_keygen_internal, algorithm 18 of FIPS 205). Intended for
+// testing, use GenerateKey otherwise.
+func KeyGen(id uint8, skSeed, skPrf, pkSeed []byte) (*PublicKey, *PrivateKey, error) {
+	prv := NewPrivateKey(id)
+	n := prv.params.N
+	if len(skSeed) != n || len(skPrf) != n || len(pkSeed) != n {
+		return nil, nil, errors.New("slhdsa: wrong size of the seed")
+	}
+	copy(prv.skSeed, skSeed)
+	copy(prv.skPrf, skPrf)
+	copy(prv.pub.seed, pkSeed)
+	prv.computeRoot()
+	return prv.Public(), prv, nil
+}
+
+// GenerateKey generates random key pair for parameter set given by id.
+// The rng must be cryptographically secure PRNG. Error is returned in
+// case PRNG fails.
+func GenerateKey(rng io.Reader, id uint8) (*PublicKey, *PrivateKey, error) {
+	n := Params(id).N
+	seeds := make([]byte, 3*n)
+	if _, err := io.ReadFull(rng, seeds); err != nil {
+		return nil, nil, err
+	}
+	return KeyGen(id, seeds[:n], seeds[n:2*n], seeds[2*n:])
+}
+
+// Computes H_msg(R, PK.seed, PK.root, M) and splits it into FORS message
+// digest, index of the hypertree leaf and index of the tree containing it.
+func (pub *PublicKey) digest(r, msg []byte) ([]byte, uint64, uint32) {
+	params := pub.params
+	mdLen := (params.K*params.A + 7) / 8
+	treeBits := params.H - params.Hp
+	treeLen := (treeBits + 7) / 8
+	leafLen := (params.Hp + 7) / 8
+
+	buf := make([]byte, params.M)
+	shake256(buf, r, pub.seed, pub.root, msg)
+	idxTree := toInt(buf[mdLen:mdLen+treeLen], uint(treeBits))
+	idxLeaf := toInt(buf[mdLen+treeLen:mdLen+treeLen+leafLen], uint(params.Hp))
+	return buf[:mdLen], idxTree, uint32(idxLeaf)
+}
+
+// Runs tasks on a pool of goroutines, each owning a hasher
+func (prv *PrivateKey) parallel(tasks []func(h *hasher)) {
+	var wg sync.WaitGroup
+	ch := make(chan func(h *hasher), len(tasks))
+	for _, t := range tasks {
+		ch <- t
+	}
+	close(ch)
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(tasks) {
+		workers = len(tasks)
+	}
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			h := newHasher(prv.params, prv.pub.seed, prv.skSeed)
+			for t := range ch {
+				t(h)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Computes signature of the formatted message msg with additional
+// randomness addrnd of N bytes (slh_sign_internal, algorithm 19 of
+// FIPS 205).
+func (prv *PrivateKey) signInternal(msg, addrnd []byte) []byte {
+	params := prv.params
+	pub := &prv.pub
+	n, a := params.N, params.A
+	sig := make([]byte, params.SignatureSize)
+
+	// R = PRF_msg(SK.prf, addrnd, M)
+	shake256(sig[:n], prv.skPrf, addrnd, msg)
+	md, idxTree, idxLeaf := pub.digest(sig[:n], msg)
+
+	var forsAdrs address
+	forsAdrs.setTree(idxTree)
+	forsAdrs.setTypeAndClear(addrForsTree)
+	forsAdrs.setKeyPair(idxLeaf)
+
+	forsSig := sig[n : n+params.forsSigSize()]
+	htSig := sig[n+params.forsSigSize():]
+	trees, leaves := params.htIndices(idxTree, idxLeaf)
+	forsRoots := make([]byte, params.K*n)
+	xmssRoots := make([]byte, params.D*n)
+	indices := make([]uint32, params.K)
+	base2b(indices, md, uint(a))
+
+	// FORS trees and XMSS trees on each layer of the hypertree are
+	// independent, compute them concurrently. WOTS+ signatures of
+	// the XMSS trees are computed afterwards, as they depend on the
+	// root of the tree below.
+	tasks := make([]func(h *hasher), 0, params.K+params.D)
+	for t := range indices {
+		t := t
+		tasks = append(tasks, func(h *hasher) {
+			s := forsSig[t*(a+1)*n : (t+1)*(a+1)*n]
+			h.forsTree(forsRoots[t*n:(t+1)*n], s, forsAdrs, uint32(t), indices[t])
+		})
+	}
+	for j := 0; j < params.D; j++ {
+		j := j
+		tasks = append(tasks, func(h *hasher) {
+			var adrs address
+			adrs.setLayer(uint32(j))
+			adrs.setTree(trees[j])
+			auth := htSig[j*params.xmssSigSize()+params.wotsLen()*n:]
+			h.xmssTree(xmssRoots[j*n:(j+1)*n], auth[:params.Hp*n], adrs, leaves[j])
+		})
+	}
+	prv.parallel(tasks)
+
+	h := newHasher(params, pub.seed, prv.skSeed)
+	node := make([]byte, n)
+	h.forsCompress(node, forsRoots, &forsAdrs)
+	for j := 0; j < params.D; j++ {
+		var adrs address
+		adrs.setLayer(uint32(j))
+		adrs.setTree(trees[j])
+		adrs.setTypeAndClear(addrWotsHash)
+		adrs.setKeyPair(leaves[j])
+		h.wotsSign(htSig[j*params.xmssSigSize():], node, &adrs)
+		node = xmssRoots[j*n : (j+1)*n]
+	}
+	return sig
+}
+
+// Verifies signature of the formatted message msg (slh_verify_internal,
+// algorithm 20 of FIPS 205).
+func (pub *PublicKey) verifyInternal(msg, sig []byte) bool {
+	params := pub.params
+	n := params.N
+	if len(sig) != params.SignatureSize {
+		return false
+	}
+
+	md, idxTree, idxLeaf := pub.digest(sig[:n], msg)
+	var adrs address
+	adrs.setTree(idxTree)
+	adrs.setTypeAndClear(addrForsTree)
+	adrs.setKeyPair(idxLeaf)
+
+	h := newHasher(params, pub.seed, nil)
+	pkFors := make([]byte, n)
+	h.forsPkFromSig(pkFors, sig[n:n+params.forsSigSize()], md, &adrs)
+	return h.htVerify(pkFors, sig[n+params.forsSigSize():], idxTree, idxLeaf, pub.root)
+}
+
+// Returns M' = 0 || len(ctx) || ctx || msg
+func formatMessage(ctx, msg []byte) []byte {
+	out := append([]byte{0, byte(len(ctx))}, ctx...)
+	return append(out, msg...)
+}
+
+// Sign computes SLH-DSA signature of msg with context string ctx
+// (slh_sign, algorithm 22 of FIPS 205). If rng is nil, deterministic
+// variant is used, otherwise signing is hedged and rng must be
+// cryptographically secure PRNG. Error is returned in case PRNG fails or
+// context is longer than MaxContextSize.
+func Sign(rng io.Reader, prv *PrivateKey, msg, ctx []byte) ([]byte, error) {
+	if len(ctx) > MaxContextSize {
+		return nil, errors.New("slhdsa: context too long")
+	}
+	// deterministic variant uses PK.seed as randomness
+	addrnd := prv.pub.seed
+	if rng != nil {
+		addrnd = make([]byte, prv.params.N)
+		if _, err := io.ReadFull(rng, addrnd); err != nil {
+			return nil, err
+		}
+	}
+	return prv.signInternal(formatMessage(ctx, msg), addrnd), nil
+}
+
+// Verify returns true if sig is a valid SLH-DSA signature of msg with
+// context string ctx (slh_verify, algorithm 24 of FIPS 205).
+func Verify(pub *PublicKey, msg, ctx, sig []byte) bool {
+	if len(ctx) > MaxContextSize {
+		return false
+	}
+	return pub.verifyInternal(formatMessage(ctx, msg), sig)
+}
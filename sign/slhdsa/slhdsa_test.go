@@ -0,0 +1,406 @@
+package slhdsa
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+var ids = []uint8{SHAKE128s, SHAKE128f, SHAKE192s, SHAKE192f, SHAKE256s, SHAKE256f}
+
+// Parameter sets used by tests which sign many times. Small-signature
+// sets are slow, those are tested only when not in short mode.
+func testIds() []uint8 {
+	if testing.Short() {
+		return []uint8{SHAKE128f, SHAKE192f, SHAKE256f}
+	}
+	return ids
+}
+
+func inIds(id uint8, ids []uint8) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// []byte encoded as hex string in JSON
+type hexBytes []byte
+
+func (b *hexBytes) UnmarshalJSON(data []byte) (err error) {
+	var s string
+	if err = json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*b, err = hex.DecodeString(s)
+	return err
+}
+
+// ACVP test vector files have a common structure, test groups with test
+// cases identified by tcId. Expected results are kept in a separate file.
+type acvpGroup struct {
+	TestType           string          `json:"testType"`
+	ParameterSet       string          `json:"parameterSet"`
+	Deterministic      bool            `json:"deterministic"`
+	SignatureInterface string          `json:"signatureInterface"`
+	PreHash            string          `json:"preHash"`
+	Tests              json.RawMessage `json:"tests"`
+}
+
+type acvpTest struct {
+	TcID                 int      `json:"tcId"`
+	SkSeed               hexBytes `json:"skSeed"`
+	SkPrf                hexBytes `json:"skPrf"`
+	PkSeed               hexBytes `json:"pkSeed"`
+	Pk                   hexBytes `json:"pk"`
+	Sk                   hexBytes `json:"sk"`
+	Message              hexBytes `json:"message"`
+	Context              hexBytes `json:"context"`
+	AdditionalRandomness hexBytes `json:"additionalRandomness"`
+	Signature            hexBytes `json:"signature"`
+	TestPassed           bool     `json:"testPassed"`
+}
+
+func readGzipJSON(t *testing.T, path string, v interface{}) {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = json.NewDecoder(r).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Returns test groups from prompt file and expected results indexed by tcId
+func loadACVP(t *testing.T, name string) ([]acvpGroup, map[int]acvpTest) {
+	var prompt, results struct {
+		TestGroups []acvpGroup `json:"testGroups"`
+	}
+	readGzipJSON(t, "testdata/"+name+"/prompt.json.gz", &prompt)
+	readGzipJSON(t, "testdata/"+name+"/expectedResults.json.gz", &results)
+
+	expected := make(map[int]acvpTest)
+	for _, g := range results.TestGroups {
+		for _, tc := range groupTests(t, g) {
+			expected[tc.TcID] = tc
+		}
+	}
+	return prompt.TestGroups, expected
+}
+
+func groupTests(t *testing.T, g acvpGroup) []acvpTest {
+	var tests []acvpTest
+	if err := json.Unmarshal(g.Tests, &tests); err != nil {
+		t.Fatal(err)
+	}
+	return tests
+}
+
+// Returns ID of the parameter set, false if it isn't a SHAKE set
+func idByName(name string) (uint8, bool) {
+	for _, id := range ids {
+		if Params(id).Name == name {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// Formats message as required by the signature interface of the group.
+// Returns false for groups which aren't supported.
+func acvpMessage(g acvpGroup, tc acvpTest) ([]byte, bool) {
+	switch {
+	case g.SignatureInterface == "internal":
+		return tc.Message, true
+	case g.SignatureInterface == "external" && g.PreHash == "pure":
+		return formatMessage(tc.Context, tc.Message), true
+	}
+	return nil, false
+}
+
+func TestACVPKeyGen(t *testing.T) {
+	groups, expected := loadACVP(t, "SLH-DSA-keyGen-FIPS205")
+	for _, g := range groups {
+		id, ok := idByName(g.ParameterSet)
+		if !ok {
+			continue
+		}
+		for _, tc := range groupTests(t, g) {
+			pub, prv, err := KeyGen(id, tc.SkSeed, tc.SkPrf, tc.PkSeed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(pub.Export(), expected[tc.TcID].Pk) {
+				t.Errorf("tc=%d: pk doesn't match", tc.TcID)
+			}
+			if !bytes.Equal(prv.Export(), expected[tc.TcID].Sk) {
+				t.Errorf("tc=%d: sk doesn't match", tc.TcID)
+			}
+		}
+	}
+}
+
+func TestACVPSigGen(t *testing.T) {
+	groups, expected := loadACVP(t, "SLH-DSA-sigGen-FIPS205")
+	for _, g := range groups {
+		id, ok := idByName(g.ParameterSet)
+		if !ok || !inIds(id, testIds()) {
+			continue
+		}
+		for _, tc := range groupTests(t, g) {
+			msg, ok := acvpMessage(g, tc)
+			if !ok {
+				continue
+			}
+			prv := NewPrivateKey(id)
+			if err := prv.Import(tc.Sk); err != nil {
+				t.Fatalf("tc=%d: %v", tc.TcID, err)
+			}
+			addrnd := prv.pub.seed
+			if !g.Deterministic {
+				addrnd = tc.AdditionalRandomness
+			}
+			sig := prv.signInternal(msg, addrnd)
+			if !bytes.Equal(sig, expected[tc.TcID].Signature) {
+				t.Errorf("tc=%d: signature doesn't match", tc.TcID)
+			}
+		}
+	}
+}
+
+func TestACVPSigVer(t *testing.T) {
+	groups, expected := loadACVP(t, "SLH-DSA-sigVer-FIPS205")
+	for _, g := range groups {
+		id, ok := idByName(g.ParameterSet)
+		if !ok {
+			continue
+		}
+		for _, tc := range groupTests(t, g) {
+			msg, ok := acvpMessage(g, tc)
+			if !ok {
+				continue
+			}
+			pub := NewPublicKey(id)
+			if err := pub.Import(tc.Pk); err != nil {
+				t.Fatal(err)
+			}
+			ok = pub.verifyInternal(msg, tc.Signature)
+			if ok != expected[tc.TcID].TestPassed {
+				t.Errorf("tc=%d: expected %t", tc.TcID, expected[tc.TcID].TestPassed)
+			}
+		}
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	msg := []byte("message")
+	ctx := []byte("context")
+	for _, id := range testIds() {
+		params := Params(id)
+		pub, prv, err := GenerateKey(rand.Reader, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sig, err := Sign(rand.Reader, prv, msg, ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sig) != params.SignatureSize {
+			t.Fatalf("%s: wrong signature size", params.Name)
+		}
+		if !Verify(pub, msg, ctx, sig) {
+			t.Errorf("%s: valid signature rejected", params.Name)
+		}
+		if Verify(pub, msg, nil, sig) {
+			t.Errorf("%s: signature accepted with wrong context", params.Name)
+		}
+		if Verify(pub, msg[1:], ctx, sig) {
+			t.Errorf("%s: signature accepted for wrong message", params.Name)
+		}
+		if Verify(pub, msg, ctx, sig[1:]) {
+			t.Errorf("%s: truncated signature accepted", params.Name)
+		}
+		// Modify randomizer, FORS part and each layer of hypertree
+		n := params.N
+		positions := []int{0, n + params.forsSigSize()/2}
+		for j := 0; j < params.D; j++ {
+			positions = append(positions, n+params.forsSigSize()+j*params.xmssSigSize()+n)
+		}
+		for _, pos := range positions {
+			sig[pos] ^= 1
+			if Verify(pub, msg, ctx, sig) {
+				t.Errorf("%s: signature modified at %d accepted", params.Name, pos)
+			}
+			sig[pos] ^= 1
+		}
+
+		if _, err = Sign(rand.Reader, prv, msg, make([]byte, MaxContextSize+1)); err == nil {
+			t.Errorf("%s: expected error for too long context", params.Name)
+		}
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	msg := []byte("message")
+	for _, id := range testIds() {
+		pub, prv, err := GenerateKey(rand.Reader, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := Sign(nil, prv, msg, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// deterministic variant uses PK.seed as randomness
+		if !bytes.Equal(sig, prv.signInternal(formatMessage(nil, msg), pub.seed)) {
+			t.Errorf("%s: unexpected deterministic signature", Params(id).Name)
+		}
+		if !Verify(pub, msg, nil, sig) {
+			t.Errorf("%s: valid signature rejected", Params(id).Name)
+		}
+	}
+}
+
+func TestImportExport(t *testing.T) {
+	for _, id := range ids {
+		params := Params(id)
+		pub, prv, err := GenerateKey(rand.Reader, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk, sk := pub.Export(), prv.Export()
+		if len(pk) != pub.Size() || len(sk) != prv.Size() {
+			t.Fatalf("%s: wrong size of exported keys", params.Name)
+		}
+
+		pub2, prv2 := NewPublicKey(id), NewPrivateKey(id)
+		if err = pub2.Import(pk); err != nil {
+			t.Fatal(err)
+		}
+		if err = prv2.Import(sk); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(pub2.Export(), pk) || !bytes.Equal(prv2.Export(), sk) {
+			t.Errorf("%s: keys differ after import", params.Name)
+		}
+
+		if pub2.Import(pk[1:]) == nil || prv2.Import(sk[1:]) == nil {
+			t.Errorf("%s: wrong size accepted", params.Name)
+		}
+		sk[len(sk)-1] ^= 1
+		if prv2.Import(sk) == nil {
+			t.Errorf("%s: inconsistent private key accepted", params.Name)
+		}
+		sk[len(sk)-1] ^= 1
+		sk[0] ^= 1
+		if prv2.Import(sk) == nil {
+			t.Errorf("%s: inconsistent private key accepted", params.Name)
+		}
+		sk[0] ^= 1
+
+		// Failed imports don't modify keys
+		if !bytes.Equal(pub2.Export(), pk) || !bytes.Equal(prv2.Export(), sk) {
+			t.Errorf("%s: failed import modified the key", params.Name)
+		}
+	}
+}
+
+// Checks that sequential computation of the signature, as described in
+// FIPS 205, gives the same result as the parallel one
+func TestSequential(t *testing.T) {
+	msg := []byte("message")
+	id := SHAKE128f
+	params := Params(id)
+	_, prv, err := GenerateKey(rand.Reader, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := prv.signInternal(msg, prv.pub.seed)
+
+	n := params.N
+	h := newHasher(params, prv.pub.seed, prv.skSeed)
+	md, idxTree, idxLeaf := prv.pub.digest(sig[:n], msg)
+	var adrs address
+	adrs.setTree(idxTree)
+	adrs.setTypeAndClear(addrForsTree)
+	adrs.setKeyPair(idxLeaf)
+	node := make([]byte, n)
+	h.forsPkFromSig(node, sig[n:], md, &adrs)
+
+	trees, leaves := params.htIndices(idxTree, idxLeaf)
+	htSig := sig[n+params.forsSigSize():]
+	for j := 0; j < params.D; j++ {
+		var adrs address
+		adrs.setLayer(uint32(j))
+		adrs.setTree(trees[j])
+		adrs.setTypeAndClear(addrWotsHash)
+		adrs.setKeyPair(leaves[j])
+		wots := make([]byte, params.wotsLen()*n)
+		h.wotsSign(wots, node, &adrs)
+		if !bytes.Equal(wots, htSig[j*params.xmssSigSize():][:len(wots)]) {
+			t.Fatalf("WOTS+ signature on layer %d doesn't match", j)
+		}
+		h.xmssPkFromSig(node, leaves[j], htSig[j*params.xmssSigSize():], node, adrs)
+	}
+	if !bytes.Equal(node, prv.pub.root) {
+		t.Error("hypertree root doesn't match")
+	}
+}
+
+func TestBase2b(t *testing.T) {
+	var out [4]uint32
+	base2b(out[:], []byte{0xAB, 0xCD, 0xEF}, 6)
+	if out != [4]uint32{0x2A, 0x3C, 0x37, 0x2F} {
+		t.Errorf("unexpected output %x", out)
+	}
+	if toInt([]byte{0xFF, 0x12, 0x34}, 12) != 0x234 {
+		t.Error("toInt failed")
+	}
+}
+
+func BenchmarkKeyGen(b *testing.B) {
+	for _, id := range ids {
+		b.Run(Params(id).Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _, _ = GenerateKey(rand.Reader, id)
+			}
+		})
+	}
+}
+
+func BenchmarkSign(b *testing.B) {
+	msg := []byte("message")
+	for _, id := range ids {
+		_, prv, _ := GenerateKey(rand.Reader, id)
+		b.Run(Params(id).Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = Sign(rand.Reader, prv, msg, nil)
+			}
+		})
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	msg := []byte("message")
+	for _, id := range ids {
+		pub, prv, _ := GenerateKey(rand.Reader, id)
+		sig, _ := Sign(rand.Reader, prv, msg, nil)
+		b.Run(Params(id).Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = Verify(pub, msg, nil, sig)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package slhdsa
+
+// Computes root of the Merkle tree of given height together with
+// authentication path of the leaf idx, in a single pass over the leaves.
+// leaf(out, i) computes i-th leaf and node(out, z, i, l, r) computes i-th
+// node at height z from its children. auth must have height*n bytes.
+func (h *hasher) treeHash(root, auth []byte, height int, idx uint32,
+	leaf func(out []byte, i uint32),
+	node func(out []byte, z, i uint32, l, r []byte)) {
+
+	n := h.params.N
+	stack := make([]byte, (height+1)*n)
+	heights := make([]int, height+1)
+	sp := 0
+
+	for i := uint32(0); i < 1<<uint(height); i++ {
+		cur := stack[sp*n : (sp+1)*n]
+		leaf(cur, i)
+		if i == idx^1 {
+			copy(auth, cur)
+		}
+		z, index := 0, i
+		for sp > 0 && heights[sp-1] == z {
+			z++
+			index >>= 1
+			left := stack[(sp-1)*n : sp*n]
+			node(left, uint32(z), index, left, cur)
+			cur = left
+			sp--
+			if z < height && index == (idx>>uint(z))^1 {
+				copy(auth[z*n:], cur)
+			}
+		}
+		heights[sp] = z
+		sp++
+	}
+	copy(root, stack[:n])
+}
+
+// Computes i-th node at height z of the XMSS tree given by adrs from its
+// children.
+func (h *hasher) xmssNode(out []byte, adrs address, z, i uint32, l, r []byte) {
+	adrs.setTypeAndClear(addrTree)
+	adrs.setTreeHeight(z)
+	adrs.setTreeIndex(i)
+	h.hash(out, &adrs, l, r)
+}
+
+// Computes root of the XMSS tree given by adrs (layer and tree address
+// set) and authentication path of the leaf idx (algorithms 9 and 10 of
+// FIPS 205).
+func (h *hasher) xmssTree(root, auth []byte, adrs address, idx uint32) {
+	leaf := func(out []byte, i uint32) {
+		a := adrs
+		a.setTypeAndClear(addrWotsHash)
+		a.setKeyPair(i)
+		h.wotsPkGen(out, &a)
+	}
+	node := func(out []byte, z, i uint32, l, r []byte) {
+		h.xmssNode(out, adrs, z, i, l, r)
+	}
+	h.treeHash(root, auth, h.params.Hp, idx, leaf, node)
+}
+
+// Computes root of the XMSS tree from the signature of msg by the leaf
+// idx (algorithm 11 of FIPS 205).
+func (h *hasher) xmssPkFromSig(out []byte, idx uint32, sig, msg []byte, adrs address) {
+	n := h.params.N
+	wotsSize := h.params.wotsLen() * n
+
+	adrs.setTypeAndClear(addrWotsHash)
+	adrs.setKeyPair(idx)
+	h.wotsPkFromSig(out, sig[:wotsSize], msg, &adrs)
+
+	auth := sig[wotsSize:]
+	for k := 0; k < h.params.Hp; k++ {
+		i := idx >> uint(k+1)
+		if (idx>>uint(k))&1 == 0 {
+			h.xmssNode(out, adrs, uint32(k+1), i, out, auth[k*n:(k+1)*n])
+		} else {
+			h.xmssNode(out, adrs, uint32(k+1), i, auth[k*n:(k+1)*n], out)
+		}
+	}
+}
+
+// Returns tree and leaf index used on each layer of the hypertree
+func (p *SlhdsaParams) htIndices(idxTree uint64, idxLeaf uint32) ([]uint64, []uint32) {
+	trees := make([]uint64, p.D)
+	leaves := make([]uint32, p.D)
+	trees[0], leaves[0] = idxTree, idxLeaf
+	for j := 1; j < p.D; j++ {
+		leaves[j] = uint32(trees[j-1] & (1<<uint(p.Hp) - 1))
+		trees[j] = trees[j-1] >> uint(p.Hp)
+	}
+	return trees, leaves
+}
+
+// Verifies hypertree signature of msg (algorithm 13 of FIPS 205)
+func (h *hasher) htVerify(msg, sig []byte, idxTree uint64, idxLeaf uint32, root []byte) bool {
+	var adrs address
+	n := h.params.N
+	node := make([]byte, n)
+	copy(node, msg)
+
+	trees, leaves := h.params.htIndices(idxTree, idxLeaf)
+	for j := 0; j < h.params.D; j++ {
+		adrs.setLayer(uint32(j))
+		adrs.setTree(trees[j])
+		s := sig[j*h.params.xmssSigSize() : (j+1)*h.params.xmssSigSize()]
+		h.xmssPkFromSig(node, leaves[j], s, node, adrs)
+	}
+	return string(node) == string(root[:n])
+}
@@ -0,0 +1,82 @@
+package slhdsa
+
+// Computes s iterations of F on x, starting at position i (algorithm 5
+// of FIPS 205). out may overlap with x.
+func (h *hasher) chain(out, x []byte, i, s uint32, adrs *address) {
+	n := h.params.N
+	copy(out[:n], x[:n])
+	for j := i; j < i+s; j++ {
+		adrs.setHash(j)
+		h.hash(out, adrs, out[:n])
+	}
+}
+
+// Converts n-byte message to WOTS+ digits, message in base w followed
+// by checksum.
+func (h *hasher) wotsDigits(msg []byte) []uint32 {
+	var csumBytes [2]byte
+	l1 := 2 * h.params.N
+	digits := make([]uint32, h.params.wotsLen())
+	base2b(digits[:l1], msg, lgW)
+
+	var csum uint32
+	for _, v := range digits[:l1] {
+		csum += w - 1 - v
+	}
+	// shift left, so that checksum is aligned to the byte boundary
+	csum <<= (8 - (wotsLen2*lgW)%8) % 8
+	csumBytes[0], csumBytes[1] = byte(csum>>8), byte(csum)
+	base2b(digits[l1:], csumBytes[:], lgW)
+	return digits
+}
+
+// Computes secret value of i-th chain of the WOTS+ key given by adrs
+func (h *hasher) wotsSecret(out []byte, i uint32, adrs *address) {
+	skAdrs := *adrs
+	skAdrs.setTypeAndClear(addrWotsPrf)
+	skAdrs.setKeyPair(adrs.keyPair())
+	skAdrs.setChain(i)
+	h.prf(out, &skAdrs)
+}
+
+// Compresses ends of WOTS+ chains kept in h.wotsBuf into public key
+func (h *hasher) wotsCompress(out []byte, adrs *address) {
+	pkAdrs := *adrs
+	pkAdrs.setTypeAndClear(addrWotsPk)
+	pkAdrs.setKeyPair(adrs.keyPair())
+	h.hash(out, &pkAdrs, h.wotsBuf)
+}
+
+// Generates WOTS+ public key (algorithm 6 of FIPS 205). adrs must have
+// type addrWotsHash and key pair set.
+func (h *hasher) wotsPkGen(out []byte, adrs *address) {
+	n := h.params.N
+	for i := 0; i < h.params.wotsLen(); i++ {
+		tmp := h.wotsBuf[i*n : (i+1)*n]
+		h.wotsSecret(tmp, uint32(i), adrs)
+		adrs.setChain(uint32(i))
+		h.chain(tmp, tmp, 0, w-1, adrs)
+	}
+	h.wotsCompress(out, adrs)
+}
+
+// Generates WOTS+ signature of n-byte message (algorithm 7 of FIPS 205)
+func (h *hasher) wotsSign(sig, msg []byte, adrs *address) {
+	n := h.params.N
+	for i, v := range h.wotsDigits(msg) {
+		s := sig[i*n : (i+1)*n]
+		h.wotsSecret(s, uint32(i), adrs)
+		adrs.setChain(uint32(i))
+		h.chain(s, s, 0, v, adrs)
+	}
+}
+
+// Computes WOTS+ public key from the signature (algorithm 8 of FIPS 205)
+func (h *hasher) wotsPkFromSig(out, sig, msg []byte, adrs *address) {
+	n := h.params.N
+	for i, v := range h.wotsDigits(msg) {
+		adrs.setChain(uint32(i))
+		h.chain(h.wotsBuf[i*n:], sig[i*n:], v, w-1-v, adrs)
+	}
+	h.wotsCompress(out, adrs)
+}
@@ -0,0 +1,64 @@
+package xmss
+
+import (
+	"encoding/binary"
+)
+
+// Types of the address (RFC 8391, section 2.5)
+const (
+	addrOts uint32 = iota
+	addrLTree
+	addrHashTree
+)
+
+// Address used for domain separation of hash calls. Layout: layer (4) |
+// tree (8) | type (4) | three words (4 each) | keyAndMask (4), big-endian.
+// For OTS addresses words hold OTS, chain and hash address, for L-tree
+// and hash tree addresses L-tree address (zero for hash tree), tree height
+// and tree index.
+type address [32]byte
+
+func (a *address) setLayer(l uint32) {
+	binary.BigEndian.PutUint32(a[0:], l)
+}
+
+func (a *address) setTree(t uint64) {
+	binary.BigEndian.PutUint64(a[4:], t)
+}
+
+// Sets type and clears remaining words
+func (a *address) setTypeAndClear(t uint32) {
+	binary.BigEndian.PutUint32(a[12:], t)
+	for i := 16; i < len(a); i++ {
+		a[i] = 0
+	}
+}
+
+// Sets OTS address or L-tree address
+func (a *address) setKeyPair(i uint32) {
+	binary.BigEndian.PutUint32(a[16:], i)
+}
+
+func (a *address) setChain(i uint32) {
+	binary.BigEndian.PutUint32(a[20:], i)
+}
+
+func (a *address) setTreeHeight(z uint32) {
+	binary.BigEndian.PutUint32(a[20:], z)
+}
+
+func (a *address) treeHeight() uint32 {
+	return binary.BigEndian.Uint32(a[20:])
+}
+
+func (a *address) setHash(i uint32) {
+	binary.BigEndian.PutUint32(a[24:], i)
+}
+
+func (a *address) setTreeIndex(i uint32) {
+	binary.BigEndian.PutUint32(a[24:], i)
+}
+
+func (a *address) setKeyAndMask(i uint32) {
+	binary.BigEndian.PutUint32(a[28:], i)
+}
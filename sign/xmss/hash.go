@@ -0,0 +1,114 @@
+package xmss
+
+import (
+	"github.com/henrydcase/nobs/hash/sha3"
+)
+
+// Domain separators of hash function calls (RFC 8391, section 5.1 and
+// NIST SP 800-208, section 5)
+const (
+	padF         = 0
+	padH         = 1
+	padHashMsg   = 2
+	padPrf       = 3
+	padPrfKeyGen = 4
+)
+
+// Keeps state needed by keyed hash functions F, H, H_msg and PRF
+// instantiated with SHAKE. Not safe for concurrent use, each goroutine
+// needs own instance.
+type hasher struct {
+	params *XmssParams
+	seed   []byte
+	// nil for verification
+	skSeed []byte
+	h      sha3.ShakeHash
+	// Scratch buffers for keys and bitmasks
+	key, bm []byte
+	// Scratch buffer for WOTS+ chains
+	wotsBuf []byte
+}
+
+func newHasher(params *XmssParams, seed, skSeed []byte) *hasher {
+	h := &hasher{
+		params:  params,
+		seed:    seed,
+		skSeed:  skSeed,
+		key:     make([]byte, params.N),
+		bm:      make([]byte, 2*params.N),
+		wotsBuf: make([]byte, params.wotsLen()*params.N),
+	}
+	if params.N == 32 {
+		h.h = sha3.NewShake128()
+	} else {
+		h.h = sha3.NewShake256()
+	}
+	return h
+}
+
+// Computes SHAKE(toByte(pad, n) || key || in_1 || ... || in_l) into
+// out[:n]. out may overlap with inputs.
+func (h *hasher) core(out []byte, pad byte, key []byte, in ...[]byte) {
+	var prefix [64]byte
+	n := h.params.N
+	prefix[n-1] = pad
+	h.h.Reset()
+	h.h.Write(prefix[:n])
+	h.h.Write(key)
+	for _, v := range in {
+		h.h.Write(v)
+	}
+	h.h.Read(out[:n])
+}
+
+// PRF(SEED, ADRS) with keyAndMask set to i
+func (h *hasher) prf(out []byte, adrs *address, i uint32) {
+	adrs.setKeyAndMask(i)
+	h.core(out, padPrf, h.seed, adrs[:])
+}
+
+// Derives secret values from SK_SEED (NIST SP 800-208, section 7.2.1)
+func (h *hasher) prfKeyGen(out []byte, adrs *address) {
+	h.core(out, padPrfKeyGen, h.skSeed, h.seed, adrs[:])
+}
+
+// Keyed and randomized hash function F(KEY, M xor BM)
+func (h *hasher) f(out []byte, adrs *address, in []byte) {
+	n := h.params.N
+	h.prf(h.key, adrs, 0)
+	h.prf(h.bm, adrs, 1)
+	for i := 0; i < n; i++ {
+		h.bm[i] ^= in[i]
+	}
+	h.core(out, padF, h.key, h.bm[:n])
+}
+
+// RAND_HASH(LEFT, RIGHT, SEED, ADRS) (RFC 8391, algorithm 7)
+func (h *hasher) randHash(out []byte, adrs *address, l, r []byte) {
+	n := h.params.N
+	h.prf(h.key, adrs, 0)
+	h.prf(h.bm[:n], adrs, 1)
+	h.prf(h.bm[n:], adrs, 2)
+	for i := 0; i < n; i++ {
+		h.bm[i] ^= l[i]
+		h.bm[n+i] ^= r[i]
+	}
+	h.core(out, padH, h.key, h.bm)
+}
+
+// Returns x as big-endian integer of given size in bytes
+func toByte(x uint64, size int) []byte {
+	out := make([]byte, size)
+	for i := size - 1; i >= 0 && x > 0; i-- {
+		out[i] = byte(x)
+		x >>= 8
+	}
+	return out
+}
+
+// Splits x into len(out) digits of lgW bits each, big-endian bit order
+func baseW(out []uint32, x []byte) {
+	for i := range out {
+		out[i] = uint32(x[i/2]>>(4*uint(1-i%2))) & (w - 1)
+	}
+}
@@ -0,0 +1,161 @@
+package xmss
+
+import (
+	"fmt"
+
+	"github.com/henrydcase/nobs/utils/security"
+)
+
+// Id's of the XMSS and XMSS^MT parameter sets based on SHAKE (RFC 8391,
+// section 5). Sets with n=32 use SHAKE128, sets with n=64 use SHAKE256.
+const (
+	XMSS_SHAKE_10_256 uint8 = iota
+	XMSS_SHAKE_16_256
+	XMSS_SHAKE_20_256
+	XMSS_SHAKE_10_512
+	XMSS_SHAKE_16_512
+	XMSS_SHAKE_20_512
+	XMSSMT_SHAKE_20_2_256
+	XMSSMT_SHAKE_20_4_256
+	XMSSMT_SHAKE_40_2_256
+	XMSSMT_SHAKE_40_4_256
+	XMSSMT_SHAKE_40_8_256
+	XMSSMT_SHAKE_60_3_256
+	XMSSMT_SHAKE_60_6_256
+	XMSSMT_SHAKE_60_12_256
+	XMSSMT_SHAKE_20_2_512
+	XMSSMT_SHAKE_20_4_512
+	XMSSMT_SHAKE_40_2_512
+	XMSSMT_SHAKE_40_4_512
+	XMSSMT_SHAKE_40_8_512
+	XMSSMT_SHAKE_60_3_512
+	XMSSMT_SHAKE_60_6_512
+	XMSSMT_SHAKE_60_12_512
+	maxParamsId
+)
+
+const (
+	// Winternitz parameter w = 2^lgW
+	lgW = 4
+	w   = 1 << lgW
+	// Number of WOTS+ checksum chains
+	wotsLen2 = 3
+	// Size of the OID prefix of keys
+	oidSize = 4
+)
+
+// Domain parameters of XMSS and XMSS^MT
+type XmssParams struct {
+	Id   uint8
+	Name string
+	// Algorithm identifier, registered by IANA
+	Oid uint32
+	// True for XMSS^MT
+	MT bool
+	// Size of hash outputs in bytes
+	N int
+	// Total height of the tree
+	H int
+	// Number of layers, 1 for XMSS
+	D int
+	// Height of a single tree, H/D
+	Hp int
+	// Sizes in bytes
+	PublicKeySize  int
+	PrivateKeySize int
+	SignatureSize  int
+	// Security properties. RFC 8391 makes no claim of NIST security
+	// category.
+	Meta security.Metadata
+}
+
+// Keeps mapping: parameter set ID to domain parameters
+var xmssParams = make(map[uint8]XmssParams)
+
+// Params returns domain parameters identified by `id`. Function panics
+// in case `id` wasn't registered earlier.
+func Params(id uint8) *XmssParams {
+	if val, ok := xmssParams[id]; ok {
+		return &val
+	}
+	panic("xmss: XMSS Params ID unregistered")
+}
+
+// Returns ID of parameters registered with given OID
+func idByOid(oid uint32, mt bool) (uint8, bool) {
+	for id, p := range xmssParams {
+		if p.Oid == oid && p.MT == mt {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// Number of WOTS+ chains
+func (p *XmssParams) wotsLen() int {
+	return 2*p.N + wotsLen2
+}
+
+// Size of the index in the signature
+func (p *XmssParams) idxSize() int {
+	if !p.MT {
+		return 4
+	}
+	return (p.H + 7) / 8
+}
+
+// Size of WOTS+ signature together with authentication path
+func (p *XmssParams) treeSigSize() int {
+	return (p.wotsLen() + p.Hp) * p.N
+}
+
+func newParams(id uint8, oid uint32, mt bool, n, h, d int) XmssParams {
+	p := XmssParams{
+		Id:  id,
+		Oid: oid,
+		MT:  mt,
+		N:   n,
+		H:   h,
+		D:   d,
+		Hp:  h / d,
+	}
+	if mt {
+		p.Name = fmt.Sprintf("XMSSMT-SHAKE_%d/%d_%d", h, d, 8*n)
+	} else {
+		p.Name = fmt.Sprintf("XMSS-SHAKE_%d_%d", h, 8*n)
+	}
+	p.PublicKeySize = oidSize + 2*n
+	p.PrivateKeySize = oidSize + 4*n
+	p.SignatureSize = p.idxSize() + n + d*p.treeSigSize()
+	p.Meta = security.Metadata{
+		Name:      p.Name,
+		Status:    security.Standardized,
+		Reference: "RFC 8391, NIST SP 800-208",
+	}
+	return p
+}
+
+func init() {
+	xmssParams[XMSS_SHAKE_10_256] = newParams(XMSS_SHAKE_10_256, 0x07, false, 32, 10, 1)
+	xmssParams[XMSS_SHAKE_16_256] = newParams(XMSS_SHAKE_16_256, 0x08, false, 32, 16, 1)
+	xmssParams[XMSS_SHAKE_20_256] = newParams(XMSS_SHAKE_20_256, 0x09, false, 32, 20, 1)
+	xmssParams[XMSS_SHAKE_10_512] = newParams(XMSS_SHAKE_10_512, 0x0a, false, 64, 10, 1)
+	xmssParams[XMSS_SHAKE_16_512] = newParams(XMSS_SHAKE_16_512, 0x0b, false, 64, 16, 1)
+	xmssParams[XMSS_SHAKE_20_512] = newParams(XMSS_SHAKE_20_512, 0x0c, false, 64, 20, 1)
+	xmssParams[XMSSMT_SHAKE_20_2_256] = newParams(XMSSMT_SHAKE_20_2_256, 0x11, true, 32, 20, 2)
+	xmssParams[XMSSMT_SHAKE_20_4_256] = newParams(XMSSMT_SHAKE_20_4_256, 0x12, true, 32, 20, 4)
+	xmssParams[XMSSMT_SHAKE_40_2_256] = newParams(XMSSMT_SHAKE_40_2_256, 0x13, true, 32, 40, 2)
+	xmssParams[XMSSMT_SHAKE_40_4_256] = newParams(XMSSMT_SHAKE_40_4_256, 0x14, true, 32, 40, 4)
+	xmssParams[XMSSMT_SHAKE_40_8_256] = newParams(XMSSMT_SHAKE_40_8_256, 0x15, true, 32, 40, 8)
+	xmssParams[XMSSMT_SHAKE_60_3_256] = newParams(XMSSMT_SHAKE_60_3_256, 0x16, true, 32, 60, 3)
+	xmssParams[XMSSMT_SHAKE_60_6_256] = newParams(XMSSMT_SHAKE_60_6_256, 0x17, true, 32, 60, 6)
+	xmssParams[XMSSMT_SHAKE_60_12_256] = newParams(XMSSMT_SHAKE_60_12_256, 0x18, true, 32, 60, 12)
+	xmssParams[XMSSMT_SHAKE_20_2_512] = newParams(XMSSMT_SHAKE_20_2_512, 0x19, true, 64, 20, 2)
+	xmssParams[XMSSMT_SHAKE_20_4_512] = newParams(XMSSMT_SHAKE_20_4_512, 0x1a, true, 64, 20, 4)
+	xmssParams[XMSSMT_SHAKE_40_2_512] = newParams(XMSSMT_SHAKE_40_2_512, 0x1b, true, 64, 40, 2)
+	xmssParams[XMSSMT_SHAKE_40_4_512] = newParams(XMSSMT_SHAKE_40_4_512, 0x1c, true, 64, 40, 4)
+	xmssParams[XMSSMT_SHAKE_40_8_512] = newParams(XMSSMT_SHAKE_40_8_512, 0x1d, true, 64, 40, 8)
+	xmssParams[XMSSMT_SHAKE_60_3_512] = newParams(XMSSMT_SHAKE_60_3_512, 0x1e, true, 64, 60, 3)
+	xmssParams[XMSSMT_SHAKE_60_6_512] = newParams(XMSSMT_SHAKE_60_6_512, 0x1f, true, 64, 60, 6)
+	xmssParams[XMSSMT_SHAKE_60_12_512] = newParams(XMSSMT_SHAKE_60_12_512, 0x20, true, 64, 60, 12)
+}
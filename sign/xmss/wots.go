@@ -0,0 +1,92 @@
+package xmss
+
+// Computes s iterations of F on x, starting at position i (RFC 8391,
+// algorithm 2). out may overlap with x.
+func (h *hasher) chain(out, x []byte, i, s uint32, adrs *address) {
+	n := h.params.N
+	copy(out[:n], x[:n])
+	for j := i; j < i+s; j++ {
+		adrs.setHash(j)
+		h.f(out, adrs, out[:n])
+	}
+}
+
+// Converts n-byte message to WOTS+ digits, message in base w followed
+// by checksum (RFC 8391, algorithm 5).
+func (h *hasher) wotsDigits(msg []byte) []uint32 {
+	l1 := 2 * h.params.N
+	digits := make([]uint32, h.params.wotsLen())
+	baseW(digits[:l1], msg)
+
+	var csum uint64
+	for _, v := range digits[:l1] {
+		csum += w - 1 - uint64(v)
+	}
+	// shift left, so that checksum is aligned to the byte boundary
+	csum <<= (8 - (wotsLen2*lgW)%8) % 8
+	baseW(digits[l1:], toByte(csum, (wotsLen2*lgW+7)/8))
+	return digits
+}
+
+// Computes secret value of i-th chain of the WOTS+ key given by adrs
+func (h *hasher) wotsSecret(out []byte, i uint32, adrs *address) {
+	skAdrs := *adrs
+	skAdrs.setChain(i)
+	skAdrs.setHash(0)
+	skAdrs.setKeyAndMask(0)
+	h.prfKeyGen(out, &skAdrs)
+}
+
+// Generates WOTS+ public key into out, len*n bytes (RFC 8391, algorithm
+// 4). adrs must have type addrOts and OTS address set.
+func (h *hasher) wotsPkGen(out []byte, adrs *address) {
+	n := h.params.N
+	for i := 0; i < h.params.wotsLen(); i++ {
+		tmp := out[i*n : (i+1)*n]
+		h.wotsSecret(tmp, uint32(i), adrs)
+		adrs.setChain(uint32(i))
+		h.chain(tmp, tmp, 0, w-1, adrs)
+	}
+}
+
+// Generates WOTS+ signature of n-byte message (RFC 8391, algorithm 5)
+func (h *hasher) wotsSign(sig, msg []byte, adrs *address) {
+	n := h.params.N
+	for i, v := range h.wotsDigits(msg) {
+		s := sig[i*n : (i+1)*n]
+		h.wotsSecret(s, uint32(i), adrs)
+		adrs.setChain(uint32(i))
+		h.chain(s, s, 0, v, adrs)
+	}
+}
+
+// Computes WOTS+ public key from the signature into out, len*n bytes
+// (RFC 8391, algorithm 6).
+func (h *hasher) wotsPkFromSig(out, sig, msg []byte, adrs *address) {
+	n := h.params.N
+	for i, v := range h.wotsDigits(msg) {
+		adrs.setChain(uint32(i))
+		h.chain(out[i*n:], sig[i*n:], v, w-1-v, adrs)
+	}
+}
+
+// Compresses WOTS+ public key into single node with unbalanced binary
+// tree (RFC 8391, algorithm 8). pk is overwritten. adrs must have type
+// addrLTree and L-tree address set.
+func (h *hasher) lTree(out, pk []byte, adrs *address) {
+	n := h.params.N
+	l := h.params.wotsLen()
+	adrs.setTreeHeight(0)
+	for l > 1 {
+		for i := 0; i < l/2; i++ {
+			adrs.setTreeIndex(uint32(i))
+			h.randHash(pk[i*n:], adrs, pk[2*i*n:(2*i+1)*n], pk[(2*i+1)*n:(2*i+2)*n])
+		}
+		if l%2 == 1 {
+			copy(pk[(l/2)*n:], pk[(l-1)*n:l*n])
+		}
+		l = (l + 1) / 2
+		adrs.setTreeHeight(adrs.treeHeight() + 1)
+	}
+	copy(out[:n], pk[:n])
+}
@@ -0,0 +1,390 @@
+// Package xmss implements stateful hash-based signature schemes XMSS and
+// XMSS^MT specified in RFC 8391, with parameter sets based on SHAKE.
+//
+// Each signature uses a one-time key, which must never be used again.
+// Private key hands out one-time keys from hbs.StateStore, which must be
+// attached with SetStateStore before signing. Keys and signatures are
+// encoded as in RFC 8391, except that private key doesn't contain index
+// of the next one-time key, which is kept by the store instead.
+//
+// Signing keeps trees needed for the authentication paths in memory, so
+// that they are computed only once. Key generation computes the whole top
+// layer tree, and is slow for trees of height 16 and more.
+//
+// [RFC8391] https://www.rfc-editor.org/rfc/rfc8391
+package xmss
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/henrydcase/nobs/sign/hbs"
+	"github.com/henrydcase/nobs/sign/internal/merkle"
+)
+
+// Defines operations on public key
+type PublicKey struct {
+	params *XmssParams
+	root   []byte
+	seed   []byte
+}
+
+// Defines operations on private key
+type PrivateKey struct {
+	params *XmssParams
+	skSeed []byte
+	skPrf  []byte
+	pub    PublicKey
+	// Hands out indices of one-time keys, nil until state store is set
+	counter *hbs.Counter
+
+	// Protects cached trees and signatures
+	mu     sync.Mutex
+	layers []layer
+}
+
+// Cached data of one layer of the hypertree
+type layer struct {
+	// Index of the tree and the tree, nil if not computed yet
+	idx  uint64
+	tree *merkle.Tree
+	// Index of the tree on the layer below and signature of its root,
+	// nil if not computed yet. Not used on the bottom layer.
+	sigIdx uint64
+	sig    []byte
+}
+
+// NewPrivateKey initializes private key.
+// Usage of this function guarantees that the object is correctly initialized.
+func NewPrivateKey(id uint8) *PrivateKey {
+	params := Params(id)
+	return &PrivateKey{
+		params: params,
+		skSeed: make([]byte, params.N),
+		skPrf:  make([]byte, params.N),
+		pub:    *NewPublicKey(id),
+		layers: make([]layer, params.D),
+	}
+}
+
+// NewPublicKey initializes public key.
+// Usage of this function guarantees that the object is correctly initialized.
+func NewPublicKey(id uint8) *PublicKey {
+	params := Params(id)
+	return &PublicKey{
+		params: params,
+		root:   make([]byte, params.N),
+		seed:   make([]byte, params.N),
+	}
+}
+
+// Accessor to the domain parameters
+func (pub *PublicKey) Params() *XmssParams {
+	return pub.params
+}
+
+// Accessor to the domain parameters
+func (prv *PrivateKey) Params() *XmssParams {
+	return prv.params
+}
+
+// Public returns public key corresponding to the private key
+func (prv *PrivateKey) Public() *PublicKey {
+	return &prv.pub
+}
+
+// Size returns size of the public key in bytes
+func (pub *PublicKey) Size() int {
+	return pub.params.PublicKeySize
+}
+
+// Size returns size of the private key in bytes
+func (prv *PrivateKey) Size() int {
+	return prv.params.PrivateKeySize
+}
+
+// Exports public key as OID || root || SEED
+func (pub *PublicKey) Export() []byte {
+	out := make([]byte, oidSize, pub.params.PublicKeySize)
+	binary.BigEndian.PutUint32(out, pub.params.Oid)
+	return append(append(out, pub.root...), pub.seed...)
+}
+
+// Import clears content of the public key and imports key stored in the
+// byte string. Returns error in case of wrong size or OID.
+func (pub *PublicKey) Import(input []byte) error {
+	n := pub.params.N
+	if len(input) != pub.params.PublicKeySize {
+		return errors.New("xmss: wrong size of the public key")
+	}
+	if binary.BigEndian.Uint32(input) != pub.params.Oid {
+		return errors.New("xmss: wrong OID of the public key")
+	}
+	copy(pub.root, input[oidSize:oidSize+n])
+	copy(pub.seed, input[oidSize+n:])
+	return nil
+}
+
+// Exports private key as OID || SK_SEED || SK_PRF || root || SEED. Index
+// of the next one-time key is not part of the export.
+func (prv *PrivateKey) Export() []byte {
+	out := make([]byte, oidSize, prv.params.PrivateKeySize)
+	binary.BigEndian.PutUint32(out, prv.params.Oid)
+	out = append(append(out, prv.skSeed...), prv.skPrf...)
+	return append(append(out, prv.pub.root...), prv.pub.seed...)
+}
+
+// Import clears content of the private key and imports key stored in the
+// byte string. Returns error in case of wrong size or OID. Consistency of
+// root and SK_SEED is checked by first Sign, as checking it requires
+// computing the top layer tree.
+func (prv *PrivateKey) Import(input []byte) error {
+	n := prv.params.N
+	if len(input) != prv.params.PrivateKeySize {
+		return errors.New("xmss: wrong size of the private key")
+	}
+	if binary.BigEndian.Uint32(input) != prv.params.Oid {
+		return errors.New("xmss: wrong OID of the private key")
+	}
+	input = input[oidSize:]
+	copy(prv.skSeed, input[:n])
+	copy(prv.skPrf, input[n:2*n])
+	copy(prv.pub.root, input[2*n:3*n])
+	copy(prv.pub.seed, input[3*n:])
+	prv.mu.Lock()
+	prv.layers = make([]layer, prv.params.D)
+	prv.mu.Unlock()
+	return nil
+}
+
+// SetStateStore attaches the store of the index of the next one-time key.
+// The store reserves batch indices at once. Larger batches make signing
+// faster, at the cost of wasting unused reserved indices, when the key
+// is not used anymore.
+func (prv *PrivateKey) SetStateStore(store hbs.StateStore, batch uint64) {
+	prv.counter = hbs.NewCounter(store, batch, uint64(1)<<uint(prv.params.H))
+}
+
+// Adapts hasher to merkle.Hasher, computing nodes of the tree given by
+// address with layer and tree set.
+type treeHasher struct {
+	*hasher
+	adrs address
+}
+
+// Computes i-th leaf, compressed WOTS+ public key
+func (t *treeHasher) Leaf(out []byte, i uint32) {
+	adrs := t.adrs
+	adrs.setTypeAndClear(addrOts)
+	adrs.setKeyPair(i)
+	t.wotsPkGen(t.wotsBuf, &adrs)
+	adrs.setTypeAndClear(addrLTree)
+	adrs.setKeyPair(i)
+	t.lTree(out, t.wotsBuf, &adrs)
+}
+
+// Computes i-th node at height z. Address holds height of the children.
+func (t *treeHasher) Node(out []byte, z int, i uint32, l, r []byte) {
+	adrs := t.adrs
+	adrs.setTypeAndClear(addrHashTree)
+	adrs.setTreeHeight(uint32(z - 1))
+	adrs.setTreeIndex(i)
+	t.randHash(out, &adrs, l, r)
+}
+
+// Computes tree with given index on given layer
+func (prv *PrivateKey) computeTree(layer uint32, idx uint64) *merkle.Tree {
+	var adrs address
+	params := prv.params
+	adrs.setLayer(layer)
+	adrs.setTree(idx)
+	return merkle.New(params.N, params.Hp, func() merkle.Hasher {
+		return &treeHasher{newHasher(params, prv.pub.seed, prv.skSeed), adrs}
+	})
+}
+
+// KeyGen derives key pair from SK_SEED, SK_PRF and SEED, each of N bytes.
+// Intended for testing, use GenerateKey otherwise.
+func KeyGen(id uint8, skSeed, skPrf, seed []byte) (*PublicKey, *PrivateKey, error) {
+	prv := NewPrivateKey(id)
+	params := prv.params
+	n := params.N
+	if len(skSeed) != n || len(skPrf) != n || len(seed) != n {
+		return nil, nil, errors.New("xmss: wrong size of the seed")
+	}
+	copy(prv.skSeed, skSeed)
+	copy(prv.skPrf, skPrf)
+	copy(prv.pub.seed, seed)
+
+	top := &prv.layers[params.D-1]
+	top.tree = prv.computeTree(uint32(params.D-1), 0)
+	copy(prv.pub.root, top.tree.Root())
+	return prv.Public(), prv, nil
+}
+
+// GenerateKey generates random key pair for parameter set given by id.
+// The rng must be cryptographically secure PRNG. Error is returned in
+// case PRNG fails.
+func GenerateKey(rng io.Reader, id uint8) (*PublicKey, *PrivateKey, error) {
+	n := Params(id).N
+	seeds := make([]byte, 3*n)
+	if _, err := io.ReadFull(rng, seeds); err != nil {
+		return nil, nil, err
+	}
+	return KeyGen(id, seeds[:n], seeds[n:2*n], seeds[2*n:])
+}
+
+// Returns tree on given layer, computing it if it isn't cached
+func (prv *PrivateKey) layerTree(j int, idx uint64) (*merkle.Tree, error) {
+	l := &prv.layers[j]
+	if l.tree == nil || l.idx != idx {
+		l.tree, l.idx = prv.computeTree(uint32(j), idx), idx
+		l.sig = nil
+		if j == prv.params.D-1 && subtle.ConstantTimeCompare(l.tree.Root(), prv.pub.root) != 1 {
+			l.tree = nil
+			return nil, errors.New("xmss: inconsistent private key")
+		}
+	}
+	return l.tree, nil
+}
+
+// Computes WOTS+ signature of n-byte message followed by authentication
+// path (treeSig, RFC 8391 algorithm 11)
+func (prv *PrivateKey) treeSig(sig, msg []byte, tree *merkle.Tree, layer uint32, idxTree uint64, idxLeaf uint32) {
+	var adrs address
+	params := prv.params
+	h := newHasher(params, prv.pub.seed, prv.skSeed)
+	adrs.setLayer(layer)
+	adrs.setTree(idxTree)
+	adrs.setTypeAndClear(addrOts)
+	adrs.setKeyPair(idxLeaf)
+	h.wotsSign(sig, msg, &adrs)
+	tree.AuthPath(sig[params.wotsLen()*params.N:], idxLeaf)
+}
+
+// Computes signature of msg with one-time key idx (RFC 8391, algorithms
+// 12 and 16). Must be called with prv.mu held.
+func (prv *PrivateKey) signAt(idx uint64, msg []byte) ([]byte, error) {
+	params := prv.params
+	n := params.N
+	sig := make([]byte, params.SignatureSize)
+	idxSize := params.idxSize()
+	copy(sig, toByte(idx, idxSize))
+
+	// r = PRF(SK_PRF, toByte(idx, 32)), M' = H_msg(r || root || idx, M)
+	h := newHasher(params, prv.pub.seed, prv.skSeed)
+	r := sig[idxSize : idxSize+n]
+	h.core(r, padPrf, prv.skPrf, toByte(idx, 32))
+	node := make([]byte, n)
+	h.core(node, padHashMsg, r, prv.pub.root, toByte(idx, n), msg)
+
+	mask := uint64(1)<<uint(params.Hp) - 1
+	idxTree := idx >> uint(params.Hp)
+	idxLeaf := uint32(idx & mask)
+	for j := 0; j < params.D; j++ {
+		tree, err := prv.layerTree(j, idxTree)
+		if err != nil {
+			return nil, err
+		}
+		s := sig[idxSize+n+j*params.treeSigSize():][:params.treeSigSize()]
+
+		// Signatures of roots of lower trees change only when the lower
+		// tree changes, they are cached.
+		l := &prv.layers[j]
+		below := idxTree<<uint(params.Hp) | uint64(idxLeaf)
+		if j == 0 {
+			prv.treeSig(s, node, tree, 0, idxTree, idxLeaf)
+		} else if l.sig != nil && l.sigIdx == below {
+			copy(s, l.sig)
+		} else {
+			prv.treeSig(s, node, tree, uint32(j), idxTree, idxLeaf)
+			l.sig, l.sigIdx = append([]byte{}, s...), below
+		}
+
+		copy(node, tree.Root())
+		idxLeaf = uint32(idxTree & mask)
+		idxTree >>= uint(params.Hp)
+	}
+	return sig, nil
+}
+
+// Sign computes signature of msg, using next one-time key from the state
+// store. Error is returned if state store is not set, all one-time keys
+// are used, store fails or the private key is inconsistent.
+func Sign(prv *PrivateKey, msg []byte) ([]byte, error) {
+	if prv.counter == nil {
+		return nil, errors.New("xmss: state store not set")
+	}
+	idx, err := prv.counter.Next()
+	if err != nil {
+		return nil, err
+	}
+	prv.mu.Lock()
+	defer prv.mu.Unlock()
+	return prv.signAt(idx, msg)
+}
+
+// Computes root of the tree from the signature of n-byte message by the
+// leaf idxLeaf (XMSS_rootFromSig, RFC 8391 algorithm 13).
+func (h *hasher) rootFromSig(out []byte, idxLeaf uint32, sig, msg []byte, adrs address) {
+	n := h.params.N
+	wotsSize := h.params.wotsLen() * n
+
+	adrs.setTypeAndClear(addrOts)
+	adrs.setKeyPair(idxLeaf)
+	h.wotsPkFromSig(h.wotsBuf, sig[:wotsSize], msg, &adrs)
+	adrs.setTypeAndClear(addrLTree)
+	adrs.setKeyPair(idxLeaf)
+	h.lTree(out, h.wotsBuf, &adrs)
+
+	adrs.setTypeAndClear(addrHashTree)
+	auth := sig[wotsSize:]
+	for k := 0; k < h.params.Hp; k++ {
+		adrs.setTreeHeight(uint32(k))
+		adrs.setTreeIndex(idxLeaf >> uint(k+1))
+		if (idxLeaf>>uint(k))&1 == 0 {
+			h.randHash(out, &adrs, out, auth[k*n:(k+1)*n])
+		} else {
+			h.randHash(out, &adrs, auth[k*n:(k+1)*n], out)
+		}
+	}
+}
+
+// Verify returns true if sig is a valid signature of msg (RFC 8391,
+// algorithms 14 and 17).
+func Verify(pub *PublicKey, msg, sig []byte) bool {
+	params := pub.params
+	n := params.N
+	idxSize := params.idxSize()
+	if len(sig) != params.SignatureSize {
+		return false
+	}
+	var idx uint64
+	for _, v := range sig[:idxSize] {
+		idx = idx<<8 | uint64(v)
+	}
+	if params.H < 64 && idx >= uint64(1)<<uint(params.H) {
+		return false
+	}
+
+	h := newHasher(params, pub.seed, nil)
+	node := make([]byte, n)
+	h.core(node, padHashMsg, sig[idxSize:idxSize+n], pub.root, toByte(idx, n), msg)
+
+	mask := uint64(1)<<uint(params.Hp) - 1
+	idxTree := idx >> uint(params.Hp)
+	idxLeaf := uint32(idx & mask)
+	for j := 0; j < params.D; j++ {
+		var adrs address
+		adrs.setLayer(uint32(j))
+		adrs.setTree(idxTree)
+		s := sig[idxSize+n+j*params.treeSigSize():]
+		h.rootFromSig(node, idxLeaf, s, node, adrs)
+		idxLeaf = uint32(idxTree & mask)
+		idxTree >>= uint(params.Hp)
+	}
+	return subtle.ConstantTimeCompare(node, pub.root) == 1
+}
@@ -0,0 +1,284 @@
+package xmss
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"path/filepath"
+	"testing"
+
+	"github.com/henrydcase/nobs/hash/sha3"
+	"github.com/henrydcase/nobs/sign/hbs"
+)
+
+// Parameter sets with small trees, fast enough for testing
+var testIds = []uint8{
+	XMSS_SHAKE_10_256,
+	XMSSMT_SHAKE_20_4_256,
+	XMSSMT_SHAKE_20_4_512,
+}
+
+func TestParams(t *testing.T) {
+	// Sizes from RFC 8391, section 5
+	for _, v := range []struct {
+		id      uint8
+		name    string
+		pk, sig int
+	}{
+		{XMSS_SHAKE_10_256, "XMSS-SHAKE_10_256", 68, 2500},
+		{XMSS_SHAKE_20_512, "XMSS-SHAKE_20_512", 132, 9732},
+		{XMSSMT_SHAKE_20_2_256, "XMSSMT-SHAKE_20/2_256", 68, 4963},
+		{XMSSMT_SHAKE_60_12_256, "XMSSMT-SHAKE_60/12_256", 68, 27688},
+		{XMSSMT_SHAKE_40_8_512, "XMSSMT-SHAKE_40/8_512", 132, 69701},
+	} {
+		p := Params(v.id)
+		if p.Name != v.name || p.PublicKeySize != v.pk || p.SignatureSize != v.sig {
+			t.Errorf("%s: unexpected parameters %+v", v.name, p)
+		}
+	}
+}
+
+// Returns hex of the first 10 bytes of SHAKE128(in), digest used by
+// test vectors of the reference implementation
+func refHash(in []byte) string {
+	var out [10]byte
+	sha3.ShakeSum128(out[:], in)
+	return hex.EncodeToString(out[:])
+}
+
+// Test vectors generated by test/vectors of the XMSS reference
+// implementation. SK_SEED || SK_PRF || SEED is 0, 1, ..., 3n-1 and the
+// message {37} is signed with one-time key 2^(h-1). Digests are computed
+// over the public key and the signature without OID.
+func TestVectors(t *testing.T) {
+	for _, v := range []struct {
+		id      uint8
+		pk, sig string
+	}{
+		{XMSS_SHAKE_10_256, "764614ee2ce5e4bf0114", "3e9035cffa0fd4be98bd"},
+		{XMSS_SHAKE_10_512, "e47fe831b6ee463e2881", "ce2dc09cd7ad8c87ae06"},
+		{XMSSMT_SHAKE_20_4_256, "dbe6fc388fbd610b3401", "2c2a66cae9a16414088d"},
+		{XMSSMT_SHAKE_20_4_512, "3739e7d3668932d9ca44", "ec8d62bb9d4ba74c6729"},
+	} {
+		params := Params(v.id)
+		n := params.N
+		seeds := make([]byte, 3*n)
+		for i := range seeds {
+			seeds[i] = byte(i)
+		}
+		pub, prv, err := KeyGen(v.id, seeds[:n], seeds[n:2*n], seeds[2*n:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := refHash(pub.Export()[oidSize:]); got != v.pk {
+			t.Errorf("%s: public key digest %s, expected %s", params.Name, got, v.pk)
+		}
+		msg := []byte{37}
+		prv.mu.Lock()
+		sig, err := prv.signAt(uint64(1)<<uint(params.H-1), msg)
+		prv.mu.Unlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := refHash(sig); got != v.sig {
+			t.Errorf("%s: signature digest %s, expected %s", params.Name, got, v.sig)
+		}
+		if !Verify(pub, msg, sig) {
+			t.Errorf("%s: verification failed", params.Name)
+		}
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	msg := []byte("message")
+	for _, id := range testIds {
+		params := Params(id)
+		pub, prv, err := GenerateKey(rand.Reader, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = Sign(prv, msg); err == nil {
+			t.Fatalf("%s: signing without state store", params.Name)
+		}
+		prv.SetStateStore(&hbs.MemoryStateStore{}, 4)
+
+		var prev []byte
+		for i := 0; i < 3; i++ {
+			sig, err := Sign(prv, msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(sig) != params.SignatureSize {
+				t.Fatalf("%s: wrong signature size", params.Name)
+			}
+			if !Verify(pub, msg, sig) {
+				t.Errorf("%s: valid signature rejected", params.Name)
+			}
+			if bytes.Equal(sig[:params.idxSize()], toByte(uint64(i), params.idxSize())) == false {
+				t.Errorf("%s: unexpected index of one-time key", params.Name)
+			}
+			if bytes.Equal(sig, prev) {
+				t.Errorf("%s: same signature twice", params.Name)
+			}
+			prev = sig
+		}
+
+		if Verify(pub, msg[1:], prev) {
+			t.Errorf("%s: signature accepted for wrong message", params.Name)
+		}
+		if Verify(pub, msg, prev[1:]) {
+			t.Errorf("%s: truncated signature accepted", params.Name)
+		}
+		n := params.N
+		positions := []int{0, params.idxSize()}
+		for j := 0; j < params.D; j++ {
+			base := params.idxSize() + n + j*params.treeSigSize()
+			positions = append(positions, base, base+params.wotsLen()*n)
+		}
+		for _, pos := range positions {
+			prev[pos] ^= 1
+			if Verify(pub, msg, prev) {
+				t.Errorf("%s: signature modified at %d accepted", params.Name, pos)
+			}
+			prev[pos] ^= 1
+		}
+	}
+}
+
+// Signs with indices crossing boundaries of trees on all layers
+func TestTreeBoundaries(t *testing.T) {
+	msg := []byte("message")
+	id := XMSSMT_SHAKE_20_4_256
+	params := Params(id)
+	pub, prv, err := GenerateKey(rand.Reader, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prv.mu.Lock()
+	defer prv.mu.Unlock()
+	for _, idx := range []uint64{31, 32, 33, 1023, 1024, 32767, 32768, 1<<20 - 1, 5} {
+		sig, err := prv.signAt(idx, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !Verify(pub, msg, sig) {
+			t.Errorf("%s: valid signature with index %d rejected", params.Name, idx)
+		}
+	}
+}
+
+func TestExhausted(t *testing.T) {
+	msg := []byte("message")
+	id := XMSSMT_SHAKE_20_4_256
+	_, prv, err := GenerateKey(rand.Reader, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &hbs.MemoryStateStore{}
+	if _, err = store.Reserve(1<<20-1, 1<<20); err != nil {
+		t.Fatal(err)
+	}
+	prv.SetStateStore(store, 16)
+	if _, err = Sign(prv, msg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Sign(prv, msg); err != hbs.ErrExhausted {
+		t.Errorf("expected ErrExhausted, got %v", err)
+	}
+}
+
+func TestImportExport(t *testing.T) {
+	msg := []byte("message")
+	for _, id := range testIds {
+		params := Params(id)
+		pub, prv, err := GenerateKey(rand.Reader, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk, sk := pub.Export(), prv.Export()
+		if len(pk) != pub.Size() || len(sk) != prv.Size() {
+			t.Fatalf("%s: wrong size of exported keys", params.Name)
+		}
+
+		pub2, prv2 := NewPublicKey(id), NewPrivateKey(id)
+		if err = pub2.Import(pk); err != nil {
+			t.Fatal(err)
+		}
+		if err = prv2.Import(sk); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(pub2.Export(), pk) || !bytes.Equal(prv2.Export(), sk) {
+			t.Errorf("%s: keys differ after import", params.Name)
+		}
+
+		// Continue with state stored in a file
+		path := filepath.Join(t.TempDir(), "state")
+		store, err := hbs.CreateFileStateStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prv2.SetStateStore(store, 1)
+		sig, err := Sign(prv2, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !Verify(pub, msg, sig) {
+			t.Errorf("%s: signature by imported key rejected", params.Name)
+		}
+
+		if pub2.Import(pk[1:]) == nil || prv2.Import(sk[1:]) == nil {
+			t.Errorf("%s: wrong size accepted", params.Name)
+		}
+		pk[3] ^= 1
+		if pub2.Import(pk) == nil {
+			t.Errorf("%s: wrong OID accepted", params.Name)
+		}
+		// root doesn't match SK_SEED
+		sk[len(sk)-params.N-1] ^= 1
+		if err = prv2.Import(sk); err != nil {
+			t.Fatal(err)
+		}
+		prv2.SetStateStore(&hbs.MemoryStateStore{}, 1)
+		if _, err = Sign(prv2, msg); err == nil {
+			t.Errorf("%s: inconsistent private key accepted", params.Name)
+		}
+	}
+}
+
+func BenchmarkKeyGen(b *testing.B) {
+	for _, id := range testIds {
+		b.Run(Params(id).Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _, _ = GenerateKey(rand.Reader, id)
+			}
+		})
+	}
+}
+
+func BenchmarkSign(b *testing.B) {
+	msg := []byte("message")
+	for _, id := range testIds {
+		_, prv, _ := GenerateKey(rand.Reader, id)
+		prv.SetStateStore(&hbs.MemoryStateStore{}, 1024)
+		b.Run(Params(id).Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = Sign(prv, msg)
+			}
+		})
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	msg := []byte("message")
+	for _, id := range testIds {
+		pub, prv, _ := GenerateKey(rand.Reader, id)
+		prv.SetStateStore(&hbs.MemoryStateStore{}, 1)
+		sig, _ := Sign(prv, msg)
+		b.Run(Params(id).Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = Verify(pub, msg, sig)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Package cpu detects CPU features used by assembly implementations.
+//
+// Features are detected with CPUID on amd64 and from HWCAP, provided by
+// the kernel, on arm64 Linux. A feature is reported only if the OS
+// supports it too (e.g. saves AVX-512 registers). Nothing is detected on
+// other platforms and when building with the noasm tag.
+//
+// Detected features can be disabled with the NOBS_CPU environment variable,
+// which is read once at program start. It contains comma separated list
+// of "name=off" or "name=on" options, processed in order. The name is one
+// of the names below or "all". Option "on" enables only features which are
+// supported by the CPU. For example, NOBS_CPU=vaes=off forces use of AES-NI
+// instead of VAES and NOBS_CPU=all=off forces generic code everywhere.
+// Unknown options are ignored.
+//
+//	x86:   aes, pclmulqdq, rdrand, rdseed, bmi2, adx, avx2, avx512f,
+//	       avx512ifma, avx512vl, vaes, vpclmulqdq, sha
+//	arm64: aes, pmull, sha3, sm3, sm4
+package cpu
+
+import (
+	"os"
+	"strings"
+)
+
+// X86 contains features of amd64 CPUs
+var X86 struct {
+	// AES-NI
+	HasAES bool
+	// Carry-less multiplication
+	HasPCLMULQDQ bool
+	HasRDRAND    bool
+	HasRDSEED    bool
+	// BMI2, which includes MULX
+	HasBMI2 bool
+	// ADCX and ADOX
+	HasADX        bool
+	HasAVX2       bool
+	HasAVX512F    bool
+	HasAVX512IFMA bool
+	HasAVX512VL   bool
+	// Vector AES instructions
+	HasVAES bool
+	// Vector carry-less multiplication
+	HasVPCLMULQDQ bool
+	// SHA extensions (SHA-1 and SHA-256)
+	HasSHA bool
+}
+
+// ARM64 contains features of arm64 CPUs
+var ARM64 struct {
+	HasAES bool
+	// 64x64 bit polynomial multiplication
+	HasPMULL bool
+	HasSHA3  bool
+	HasSM3   bool
+	HasSM4   bool
+}
+
+// Feature which can be disabled by NOBS_CPU
+type option struct {
+	name    string
+	feature *bool
+	// Value detected at start
+	detected bool
+}
+
+// Set by doinit
+var options []option
+
+const envName = "NOBS_CPU"
+
+func init() {
+	doinit()
+	for i := range options {
+		options[i].detected = *options[i].feature
+	}
+	processOptions(os.Getenv(envName))
+}
+
+// Applies options from NOBS_CPU
+func processOptions(env string) {
+	for _, field := range strings.Split(env, ",") {
+		kv := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(kv) != 2 || (kv[1] != "on" && kv[1] != "off") {
+			continue
+		}
+		on := kv[1] == "on"
+		for i := range options {
+			o := &options[i]
+			if kv[0] == "all" || kv[0] == o.name {
+				*o.feature = on && o.detected
+			}
+		}
+	}
+}
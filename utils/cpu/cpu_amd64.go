@@ -0,0 +1,74 @@
+//go:build amd64 && !noasm
+// +build amd64,!noasm
+
+// Sets capabilities flags for x86 according to information received from
+// CPUID. It was written in accordance with
+// "Intel® 64 and IA-32 Architectures Developer's Manual: Vol. 2A".
+// https://www.intel.com/content/www/us/en/architecture-and-technology/64-ia-32-architectures-software-developer-vol-2a-manual.html
+
+package cpu
+
+// Performs CPUID and returns values of registers
+//
+//go:noescape
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// Returns value of extended control register XCR0
+//
+//go:noescape
+func xgetbv() (eax, edx uint32)
+
+// Returns true in case bit 'n' in 'bits' is set, otherwise false
+func bitn(bits uint32, n uint8) bool {
+	return (bits>>n)&1 == 1
+}
+
+func doinit() {
+	options = []option{
+		{name: "aes", feature: &X86.HasAES},
+		{name: "pclmulqdq", feature: &X86.HasPCLMULQDQ},
+		{name: "rdrand", feature: &X86.HasRDRAND},
+		{name: "rdseed", feature: &X86.HasRDSEED},
+		{name: "bmi2", feature: &X86.HasBMI2},
+		{name: "adx", feature: &X86.HasADX},
+		{name: "avx2", feature: &X86.HasAVX2},
+		{name: "avx512f", feature: &X86.HasAVX512F},
+		{name: "avx512ifma", feature: &X86.HasAVX512IFMA},
+		{name: "avx512vl", feature: &X86.HasAVX512VL},
+		{name: "vaes", feature: &X86.HasVAES},
+		{name: "vpclmulqdq", feature: &X86.HasVPCLMULQDQ},
+		{name: "sha", feature: &X86.HasSHA},
+	}
+
+	// CPUID returns max possible input that can be requested
+	max, _, _, _ := cpuid(0, 0)
+	if max < 7 {
+		return
+	}
+
+	_, _, ecx, _ := cpuid(1, 0)
+	X86.HasPCLMULQDQ = bitn(ecx, 1)
+	X86.HasAES = bitn(ecx, 25)
+	X86.HasRDRAND = bitn(ecx, 30)
+
+	// OS saves YMM registers (XCR0 bits 1, 2) and also ZMM registers
+	// and opmasks (XCR0 bits 5, 6, 7) on context switch
+	var osAVX, osAVX512 bool
+	if bitn(ecx, 27) {
+		xcr0, _ := xgetbv()
+		osAVX = xcr0&0x06 == 0x06
+		osAVX512 = osAVX && xcr0&0xe0 == 0xe0
+	}
+
+	_, ebx, ecx, _ := cpuid(7, 0)
+	X86.HasBMI2 = bitn(ebx, 8)
+	X86.HasADX = bitn(ebx, 19)
+	X86.HasRDSEED = bitn(ebx, 18)
+	X86.HasSHA = bitn(ebx, 29)
+	X86.HasAVX2 = osAVX && bitn(ebx, 5)
+	X86.HasAVX512F = osAVX512 && bitn(ebx, 16)
+	X86.HasAVX512IFMA = osAVX512 && bitn(ebx, 21)
+	X86.HasAVX512VL = osAVX512 && bitn(ebx, 31)
+	X86.HasVAES = osAVX && bitn(ecx, 9)
+	X86.HasVPCLMULQDQ = osAVX && bitn(ecx, 10)
+}
@@ -0,0 +1,67 @@
+//go:build arm64 && !noasm
+// +build arm64,!noasm
+
+// Sets capabilities flags for arm64. On Linux features are read from
+// AT_HWCAP entry of auxiliary vector, see arch/arm64/include/uapi/asm/hwcap.h
+// in the kernel sources.
+
+package cpu
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"runtime"
+)
+
+const (
+	// Type of auxiliary vector entry
+	_AT_HWCAP = 16
+
+	// HWCAP bits
+	hwcapAES   = 1 << 3
+	hwcapPMULL = 1 << 4
+	hwcapSHA3  = 1 << 17
+	hwcapSM3   = 1 << 18
+	hwcapSM4   = 1 << 19
+)
+
+// Returns value of AT_HWCAP or 0 if it can't be read
+func hwcap() uint64 {
+	// Auxiliary vector is a list of (type, value) pairs of
+	// 64-bit words, terminated by AT_NULL
+	buf, err := ioutil.ReadFile("/proc/self/auxv")
+	if err != nil {
+		return 0
+	}
+	for i := 0; i+16 <= len(buf); i += 16 {
+		tag := binary.LittleEndian.Uint64(buf[i:])
+		if tag == _AT_HWCAP {
+			return binary.LittleEndian.Uint64(buf[i+8:])
+		}
+	}
+	return 0
+}
+
+func doinit() {
+	options = []option{
+		{name: "aes", feature: &ARM64.HasAES},
+		{name: "pmull", feature: &ARM64.HasPMULL},
+		{name: "sha3", feature: &ARM64.HasSHA3},
+		{name: "sm3", feature: &ARM64.HasSM3},
+		{name: "sm4", feature: &ARM64.HasSM4},
+	}
+
+	switch runtime.GOOS {
+	case "linux", "android":
+		hw := hwcap()
+		ARM64.HasAES = hw&hwcapAES != 0
+		ARM64.HasPMULL = hw&hwcapPMULL != 0
+		ARM64.HasSHA3 = hw&hwcapSHA3 != 0
+		ARM64.HasSM3 = hw&hwcapSM3 != 0
+		ARM64.HasSM4 = hw&hwcapSM4 != 0
+	case "darwin", "ios":
+		// All Apple arm64 CPUs implement cryptographic extension
+		ARM64.HasAES = true
+		ARM64.HasPMULL = true
+	}
+}
@@ -0,0 +1,7 @@
+//go:build (!amd64 && !arm64) || noasm
+// +build !amd64,!arm64 noasm
+
+package cpu
+
+// No features are detected
+func doinit() {}
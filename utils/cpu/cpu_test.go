@@ -0,0 +1,68 @@
+package cpu
+
+import (
+	"testing"
+)
+
+// Replaces options with fake features, restored by returned function
+func fakeOptions(detected ...bool) ([]bool, func()) {
+	saved := options
+	features := make([]bool, len(detected))
+	options = make([]option, len(detected))
+	for i := range detected {
+		features[i] = detected[i]
+		options[i] = option{
+			name:     string(rune('a' + i)),
+			feature:  &features[i],
+			detected: detected[i],
+		}
+	}
+	return features, func() { options = saved }
+}
+
+func TestProcessOptions(t *testing.T) {
+	for _, v := range []struct {
+		env  string
+		want []bool
+	}{
+		{"", []bool{true, true, false}},
+		{"a=off", []bool{false, true, false}},
+		{"a=off,b=off", []bool{false, false, false}},
+		{" a=off , b=off ", []bool{false, false, false}},
+		{"all=off", []bool{false, false, false}},
+		{"all=off,b=on", []bool{false, true, false}},
+		{"a=off,a=on", []bool{true, true, false}},
+		{"a=off,all=on", []bool{true, true, false}},
+		// Features not supported by CPU can't be enabled
+		{"c=on", []bool{true, true, false}},
+		{"all=on", []bool{true, true, false}},
+		// Unknown options are ignored
+		{"d=off", []bool{true, true, false}},
+		{"a=0,b", []bool{true, true, false}},
+		{"a=off=on", []bool{true, true, false}},
+	} {
+		features, restore := fakeOptions(true, true, false)
+		processOptions(v.env)
+		restore()
+		for i := range features {
+			if features[i] != v.want[i] {
+				t.Errorf("NOBS_CPU=%q: got %v, want %v", v.env, features, v.want)
+				break
+			}
+		}
+	}
+}
+
+func TestOptionNames(t *testing.T) {
+	names := make(map[string]bool)
+	for _, o := range options {
+		if o.name == "all" || names[o.name] {
+			t.Errorf("duplicated option %q", o.name)
+		}
+		names[o.name] = true
+		// Nothing can be enabled which wasn't detected
+		if *o.feature && !o.detected {
+			t.Errorf("feature %q enabled, but not detected", o.name)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Package ctcheck helps to test that execution time of code doesn't depend
+// on secret data.
+//
+// Check measures execution time of an operation with two classes of inputs,
+// usually a fixed one (class 0) and random ones (class 1), and applies
+// Welch's t-test to the measurements, as done by dudect ("Dude, is my code
+// constant time?", O. Reparaz, J. Balasch and I. Verbauwhede, 2016,
+// ePrint #1123). The test is statistical, it can't prove that code runs
+// in constant time, but large values of t statistic show that it doesn't.
+//
+// Tests which use this package are built with ctcheck tag and can be run
+// with:
+//
+//	go test -tags ctcheck -run CT -v ./...
+//
+// When running under valgrind, Check doesn't measure anything. Instead it
+// runs the operation once for each class, while Secret marks secret inputs
+// as undefined memory (see ctgrind by A. Langley). Memcheck then reports
+// branches and memory accesses which depend on secrets as use of
+// uninitialised values:
+//
+//	go test -tags ctcheck -c -o sike.test ./kem/sike
+//	valgrind --error-exitcode=1 ./sike.test -test.run CT
+package ctcheck
+
+import (
+	"math"
+	"math/rand"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"testing"
+	"time"
+)
+
+// Threshold for the t statistic, above which execution time is considered
+// to depend on class of the input.
+const Threshold = 10
+
+// Number of tests on measurements cropped at different percentiles
+const percentiles = 100
+
+// Minimal number of measurements in each class for a test to be used
+const minSamples = 50
+
+// Target returns operation to be measured, with input of given class
+// (0 or 1). Input is prepared by Target, not by the operation.
+type Target func(class int) func()
+
+// Result of measurements
+type Result struct {
+	// Largest absolute value of t statistic over all tests
+	T float64
+	// Number of measurements
+	N int
+}
+
+// Welch's t-test with online computation of mean and variance
+type ttest struct {
+	n, mean, m2 [2]float64
+}
+
+func (t *ttest) push(x float64, class int) {
+	t.n[class]++
+	d := x - t.mean[class]
+	t.mean[class] += d / t.n[class]
+	t.m2[class] += d * (x - t.mean[class])
+}
+
+func (t *ttest) compute() float64 {
+	v0 := t.m2[0] / (t.n[0] - 1)
+	v1 := t.m2[1] / (t.n[1] - 1)
+	den := math.Sqrt(v0/t.n[0] + v1/t.n[1])
+	if den == 0 {
+		// All measurements equal in both classes, or differ only
+		// between them
+		if t.mean[0] == t.mean[1] {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return (t.mean[0] - t.mean[1]) / den
+}
+
+// Returns max |t| of tests on all measurements and measurements below
+// each of percentiles. Cropping removes measurements affected by
+// interrupts and such, and makes the test sensitive to differences in
+// lower part of distribution.
+func analyze(times []float64, classes []int) float64 {
+	sorted := append([]float64(nil), times...)
+	sort.Float64s(sorted)
+
+	var tests [percentiles + 1]ttest
+	var limits [percentiles + 1]float64
+	limits[0] = math.Inf(1)
+	for i := 1; i <= percentiles; i++ {
+		// Same percentiles as used by dudect
+		p := 1 - math.Pow(0.5, 10*float64(i)/percentiles)
+		limits[i] = sorted[int(p*float64(len(sorted)-1))]
+	}
+
+	for i, x := range times {
+		for j := range tests {
+			if x <= limits[j] {
+				tests[j].push(x, classes[i])
+			}
+		}
+	}
+
+	var max float64
+	for i := range tests {
+		if tests[i].n[0] < minSamples || tests[i].n[1] < minSamples {
+			continue
+		}
+		if t := math.Abs(tests[i].compute()); t > max {
+			max = t
+		}
+	}
+	return max
+}
+
+// Measure performs n measurements of operations returned by target for
+// randomly chosen classes.
+func Measure(n int, target Target) Result {
+	classes := make([]int, n)
+	ops := make([]func(), n)
+	for i := range ops {
+		classes[i] = rand.Intn(2)
+		ops[i] = target(classes[i])
+	}
+
+	// Warm up caches and branch predictor
+	for i := 0; i < 10; i++ {
+		target(i & 1)()
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	runtime.GC()
+	defer debug.SetGCPercent(debug.SetGCPercent(-1))
+
+	times := make([]float64, n)
+	for i, op := range ops {
+		start := time.Now()
+		op()
+		times[i] = float64(time.Since(start))
+	}
+	return Result{T: analyze(times, classes), N: n}
+}
+
+// Check fails tb if execution time of operations returned by target
+// depends on class of the input, as measured with n measurements.
+// When running under valgrind it only runs operation once for each class.
+func Check(tb testing.TB, name string, n int, target Target) {
+	tb.Helper()
+	if RunningOnValgrind() {
+		target(0)()
+		target(1)()
+		return
+	}
+	if testing.Short() {
+		n /= 10
+	}
+	r := Measure(n, target)
+	tb.Logf("%s: max |t| = %.2f, %d measurements", name, r.T, r.N)
+	if r.T > Threshold {
+		tb.Errorf("%s: execution time depends on input, max |t| = %.2f > %d", name, r.T, Threshold)
+	}
+}
@@ -0,0 +1,84 @@
+package ctcheck
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Busy loop, which compiler can't remove
+var sink uint64
+
+func work(n int) {
+	x := sink
+	for i := 0; i < n; i++ {
+		x = x*6364136223846793005 + 1442695040888963407
+	}
+	sink = x
+}
+
+func TestTTest(t *testing.T) {
+	var tt ttest
+	for _, x := range []float64{1, 2, 3, 4} {
+		tt.push(x, 0)
+	}
+	for _, x := range []float64{3, 4, 5, 6} {
+		tt.push(x, 1)
+	}
+	// Means 2.5 and 4.5, variances 5/3
+	want := -2 / math.Sqrt(2*5.0/3/4)
+	if got := tt.compute(); math.Abs(got-want) > 1e-12 {
+		t.Errorf("got t = %v, want %v", got, want)
+	}
+
+	// Equal samples
+	tt = ttest{}
+	for i := 0; i < 10; i++ {
+		tt.push(1, i&1)
+	}
+	if got := tt.compute(); got != 0 {
+		t.Errorf("got t = %v for equal samples, want 0", got)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	n := 10000
+	times := make([]float64, n)
+	classes := make([]int, n)
+	for i := range times {
+		classes[i] = r.Intn(2)
+		times[i] = 1000 + 100*r.NormFloat64()
+	}
+	if got := analyze(times, classes); got > Threshold/2 {
+		t.Errorf("same distributions: max |t| = %v", got)
+	}
+	// Shift of class 1 by 30% of standard deviation
+	for i := range times {
+		times[i] += 30 * float64(classes[i])
+	}
+	if got := analyze(times, classes); got < Threshold {
+		t.Errorf("different distributions: max |t| = %v", got)
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	if r := Measure(2000, func(class int) func() {
+		return func() { work(100 + 100*class) }
+	}); r.T < Threshold {
+		t.Errorf("variable time operation: max |t| = %v", r.T)
+	}
+}
+
+func TestValgrind(t *testing.T) {
+	if RunningOnValgrind() {
+		t.Skip("running on valgrind")
+	}
+	// Requests are ignored, when not running on valgrind
+	b := []byte{1, 2, 3}
+	Secret(b)
+	Public(b)
+	if got := clientRequest(vgRunningOnValgrind, 0, 0); got != 0 {
+		t.Errorf("RUNNING_ON_VALGRIND returned %d", got)
+	}
+}
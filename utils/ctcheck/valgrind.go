@@ -0,0 +1,51 @@
+package ctcheck
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// Valgrind client requests, see valgrind.h and memcheck.h
+const (
+	vgRunningOnValgrind = 0x1001
+	// VG_USERREQ_TOOL_BASE('M','C')
+	vgMemcheckBase   = 'M'<<24 | 'C'<<16
+	vgMakeMemUndef   = vgMemcheckBase + 1
+	vgMakeMemDefined = vgMemcheckBase + 2
+)
+
+var onValgrind = clientRequest(vgRunningOnValgrind, 0, 0) != 0
+
+// Performs valgrind client request, returns 0 when not running on valgrind
+func clientRequest(req, arg1, arg2 uintptr) uintptr {
+	args := [6]uintptr{req, arg1, arg2}
+	return valgrindRequest(&args, 0)
+}
+
+// RunningOnValgrind returns true if program runs under valgrind. It is
+// supported only on amd64, on other platforms it always returns false.
+func RunningOnValgrind() bool {
+	return onValgrind
+}
+
+// Secret marks content of b as undefined for memcheck, so that branches
+// and memory accesses depending on it are reported. Does nothing when
+// not running on valgrind.
+func Secret(b []byte) {
+	if len(b) == 0 || !onValgrind {
+		return
+	}
+	clientRequest(vgMakeMemUndef, uintptr(unsafe.Pointer(&b[0])), uintptr(len(b)))
+	runtime.KeepAlive(b)
+}
+
+// Public marks content of b as defined, it must be called on secret
+// dependent values which are made public (e.g. results compared by the
+// test). Does nothing when not running on valgrind.
+func Public(b []byte) {
+	if len(b) == 0 || !onValgrind {
+		return
+	}
+	clientRequest(vgMakeMemDefined, uintptr(unsafe.Pointer(&b[0])), uintptr(len(b)))
+	runtime.KeepAlive(b)
+}
@@ -0,0 +1,9 @@
+//go:build amd64 && !noasm
+// +build amd64,!noasm
+
+package ctcheck
+
+// Defined in valgrind_amd64.s
+//
+//go:noescape
+func valgrindRequest(args *[6]uintptr, def uintptr) uintptr
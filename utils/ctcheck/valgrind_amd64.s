@@ -0,0 +1,20 @@
+//go:build amd64 && !noasm
+// +build amd64,!noasm
+
+#include "textflag.h"
+
+// Executes special instruction sequence recognized by valgrind, see
+// __SPECIAL_INSTRUCTION_PREAMBLE in valgrind.h. Natively it doesn't
+// change DI and returns def.
+//
+// func valgrindRequest(args *[6]uintptr, def uintptr) uintptr
+TEXT ·valgrindRequest(SB),NOSPLIT,$0-24
+	MOVQ args+0(FP), AX
+	MOVQ def+8(FP), DX
+	ROLQ $3, DI
+	ROLQ $13, DI
+	ROLQ $61, DI
+	ROLQ $51, DI
+	XCHGQ BX, BX
+	MOVQ DX, ret+16(FP)
+	RET
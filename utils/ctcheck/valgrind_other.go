@@ -0,0 +1,9 @@
+//go:build !amd64 || noasm
+// +build !amd64 noasm
+
+package ctcheck
+
+// Client requests are not supported
+func valgrindRequest(args *[6]uintptr, def uintptr) uintptr {
+	return def
+}
@@ -0,0 +1,107 @@
+package keys
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+)
+
+// Octet key pair from RFC 8037. Other members of the JWK are ignored.
+type okpJWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	D   string `json:"d,omitempty"`
+}
+
+var b64 = base64.RawURLEncoding.Strict()
+
+// MarshalJWK returns JSON encoded JWK of type "OKP" (RFC 8037) of the
+// key, which is one of *ecdh.PublicKey, *ecdh.PrivateKey, *sidh.PublicKey
+// and *sidh.PrivateKey. JWK of a private key contains also the public
+// key. For SIKE it is computed, which is slow.
+func MarshalJWK(key interface{}) ([]byte, error) {
+	var jwk okpJWK
+	if a, b, err := publicKeyAlgorithm(key); err == nil {
+		jwk = okpJWK{Kty: "OKP", Crv: a.name, X: b64.EncodeToString(b)}
+	} else if a, b, err := privateKeyAlgorithm(key); err == nil {
+		jwk = okpJWK{
+			Kty: "OKP",
+			Crv: a.name,
+			X:   b64.EncodeToString(publicOf(key)),
+			D:   b64.EncodeToString(b),
+		}
+	} else {
+		return nil, err
+	}
+	return json.Marshal(&jwk)
+}
+
+// ParseJWK parses JSON encoded JWK of type "OKP". Returns public key if
+// the "d" member is absent and private key otherwise. Public key of a
+// private JWK must correspond to the private key.
+func ParseJWK(data []byte) (interface{}, error) {
+	key, err := parseJWK(data)
+	if err != nil {
+		return nil, &ParseError{"JWK", err}
+	}
+	return key, nil
+}
+
+// Returns string member of a JWK. Member names are case sensitive, as
+// opposed to field names in json.Unmarshal.
+func jwkMember(m map[string]json.RawMessage, name string) (string, error) {
+	raw, ok := m[name]
+	if !ok {
+		return "", nil
+	}
+	var s string
+	if json.Unmarshal(raw, &s) != nil || s == "" {
+		return "", ErrMalformed
+	}
+	return s, nil
+}
+
+func parseJWK(data []byte) (interface{}, error) {
+	var m map[string]json.RawMessage
+	if json.Unmarshal(data, &m) != nil {
+		return nil, ErrMalformed
+	}
+	var jwk okpJWK
+	for _, v := range []struct {
+		name string
+		dst  *string
+	}{{"kty", &jwk.Kty}, {"crv", &jwk.Crv}, {"x", &jwk.X}, {"d", &jwk.D}} {
+		s, err := jwkMember(m, v.name)
+		if err != nil {
+			return nil, err
+		}
+		*v.dst = s
+	}
+	if jwk.Kty != "OKP" {
+		return nil, ErrUnknownAlgorithm
+	}
+	a := algorithmByName(jwk.Crv)
+	if a == nil {
+		return nil, ErrUnknownAlgorithm
+	}
+	x, err := b64.DecodeString(jwk.X)
+	if err != nil || len(x) == 0 {
+		return nil, ErrMalformed
+	}
+	if jwk.D == "" {
+		return a.publicKey(x)
+	}
+	d, err := b64.DecodeString(jwk.D)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	prv, err := a.privateKey(d)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(x, publicOf(prv)) != 1 {
+		return nil, ErrKeyMismatch
+	}
+	return prv, nil
+}
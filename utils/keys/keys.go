@@ -0,0 +1,166 @@
+// Package keys implements standard encodings of X25519, X448 and SIKE
+// keys: SubjectPublicKeyInfo (RFC 5280), PKCS#8 (RFC 5958), PEM (RFC
+// 7468) and JWK (RFC 7517, RFC 8037).
+//
+// X25519 and X448 keys are *ecdh.PublicKey and *ecdh.PrivateKey and use
+// the OIDs from RFC 8410. SIKE keys are *sidh.PublicKey and
+// *sidh.PrivateKey of the KeyVariant_SIKE variant. SIKE has no assigned
+// OIDs nor JWK curve names, so this package uses provisional OIDs under
+// the experimental arc 1.3.9999 and the SIKE parameter set names as JWK
+// "crv". Such encodings interoperate only with this package.
+//
+// Parsing is strict: trailing data, algorithm parameters, keys of wrong
+// size and inconsistent key pairs are rejected. All parse errors are of
+// type *ParseError and wrap one of the Err* values.
+package keys
+
+import (
+	"encoding/asn1"
+	"errors"
+
+	"github.com/henrydcase/nobs/dh/sidh"
+	"github.com/henrydcase/nobs/ec/ecdh"
+)
+
+var (
+	// ErrMalformed is returned for syntactically invalid encodings
+	ErrMalformed = errors.New("keys: malformed encoding")
+	// ErrTrailingData is returned if there is data after the encoded key
+	ErrTrailingData = errors.New("keys: trailing data")
+	// ErrUnknownAlgorithm is returned for keys of unsupported algorithms
+	ErrUnknownAlgorithm = errors.New("keys: unknown algorithm")
+	// ErrKeySize is returned if size of the key doesn't match the algorithm
+	ErrKeySize = errors.New("keys: wrong size of the key")
+	// ErrKeyMismatch is returned if encoded public key doesn't correspond
+	// to the private key
+	ErrKeyMismatch = errors.New("keys: public key doesn't match private key")
+
+	errUnsupportedKey = errors.New("keys: unsupported key type")
+)
+
+// ParseError describes a key which couldn't be parsed
+type ParseError struct {
+	// Format is one of "PKIX", "PKCS#8", "PEM" and "JWK"
+	Format string
+	// Err is the reason
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return e.Err.Error() + " (" + e.Format + ")"
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Algorithms supported by the package
+type algorithm struct {
+	name string
+	oid  asn1.ObjectIdentifier
+	// Exactly one of curve and sikeID is set
+	curve  ecdh.Curve
+	sikeID uint8
+}
+
+var algorithms = []algorithm{
+	{name: "X25519", oid: asn1.ObjectIdentifier{1, 3, 101, 110}, curve: ecdh.X25519()},
+	{name: "X448", oid: asn1.ObjectIdentifier{1, 3, 101, 111}, curve: ecdh.X448()},
+	{name: "SIKEp503", oid: asn1.ObjectIdentifier{1, 3, 9999, 99, 503}, sikeID: sidh.FP_503},
+	{name: "SIKEp751", oid: asn1.ObjectIdentifier{1, 3, 9999, 99, 751}, sikeID: sidh.FP_751},
+}
+
+func algorithmByOID(oid asn1.ObjectIdentifier) *algorithm {
+	for i := range algorithms {
+		if algorithms[i].oid.Equal(oid) {
+			return &algorithms[i]
+		}
+	}
+	return nil
+}
+
+func algorithmByName(name string) *algorithm {
+	for i := range algorithms {
+		if algorithms[i].name == name {
+			return &algorithms[i]
+		}
+	}
+	return nil
+}
+
+// Returns algorithm and encoding of a public key
+func publicKeyAlgorithm(pub interface{}) (*algorithm, []byte, error) {
+	switch k := pub.(type) {
+	case *ecdh.PublicKey:
+		if a := algorithmByName(k.Curve().Name()); a != nil {
+			return a, k.Export(), nil
+		}
+	case *sidh.PublicKey:
+		if k.Variant() == sidh.KeyVariant_SIKE {
+			if a := algorithmByName(k.Params().Meta.Name); a != nil {
+				return a, k.Export(), nil
+			}
+		}
+	}
+	return nil, nil, errUnsupportedKey
+}
+
+// Returns algorithm and encoding of a private key
+func privateKeyAlgorithm(prv interface{}) (*algorithm, []byte, error) {
+	switch k := prv.(type) {
+	case *ecdh.PrivateKey:
+		if a := algorithmByName(k.Curve().Name()); a != nil {
+			return a, k.Export(), nil
+		}
+	case *sidh.PrivateKey:
+		if k.Variant() == sidh.KeyVariant_SIKE {
+			if a := algorithmByName(k.Params().Meta.Name); a != nil {
+				return a, k.Export(), nil
+			}
+		}
+	}
+	return nil, nil, errUnsupportedKey
+}
+
+// Returns public key of algorithm a, decoded from b
+func (a *algorithm) publicKey(b []byte) (interface{}, error) {
+	if a.curve != nil {
+		pub := ecdh.NewPublicKey(a.curve)
+		if pub.Import(b) != nil {
+			return nil, ErrKeySize
+		}
+		return pub, nil
+	}
+	pub := sidh.NewPublicKey(a.sikeID, sidh.KeyVariant_SIKE)
+	if pub.Import(b) != nil {
+		return nil, ErrKeySize
+	}
+	return pub, nil
+}
+
+// Returns private key of algorithm a, decoded from b
+func (a *algorithm) privateKey(b []byte) (interface{}, error) {
+	if a.curve != nil {
+		prv := ecdh.NewPrivateKey(a.curve)
+		if prv.Import(b) != nil {
+			return nil, ErrKeySize
+		}
+		return prv, nil
+	}
+	prv := sidh.NewPrivateKey(a.sikeID, sidh.KeyVariant_SIKE)
+	if prv.Import(b) != nil {
+		return nil, ErrKeySize
+	}
+	return prv, nil
+}
+
+// Returns encoding of the public key corresponding to prv
+func publicOf(prv interface{}) []byte {
+	switch k := prv.(type) {
+	case *ecdh.PrivateKey:
+		return k.Public().Export()
+	case *sidh.PrivateKey:
+		return k.GeneratePublicKey().Export()
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package keys
+
+import (
+	"crypto/subtle"
+	"encoding/asn1"
+	"encoding/pem"
+)
+
+// AlgorithmIdentifier from RFC 5280. Parameters must be absent.
+type algorithmIdentifier struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.RawValue `asn1:"optional"`
+}
+
+// SubjectPublicKeyInfo from RFC 5280
+type subjectPublicKeyInfo struct {
+	Algorithm algorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// OneAsymmetricKey from RFC 5958. Version 0 is PrivateKeyInfo from
+// PKCS#8, version 1 may contain the public key.
+type oneAsymmetricKey struct {
+	Version    int
+	Algorithm  algorithmIdentifier
+	PrivateKey []byte
+	Attributes asn1.RawValue  `asn1:"optional,tag:0"`
+	PublicKey  asn1.BitString `asn1:"optional,tag:1"`
+}
+
+// Unmarshals DER encoded val and checks there is no trailing data
+func unmarshalDER(der []byte, val interface{}) error {
+	rest, err := asn1.Unmarshal(der, val)
+	if err != nil {
+		return ErrMalformed
+	}
+	if len(rest) != 0 {
+		return ErrTrailingData
+	}
+	return nil
+}
+
+// Returns algorithm identified by id. Parameters must be absent.
+func parseAlgorithm(id *algorithmIdentifier) (*algorithm, error) {
+	if len(id.Parameters.FullBytes) != 0 {
+		return nil, ErrMalformed
+	}
+	a := algorithmByOID(id.Algorithm)
+	if a == nil {
+		return nil, ErrUnknownAlgorithm
+	}
+	return a, nil
+}
+
+// MarshalPKIXPublicKey returns DER encoded SubjectPublicKeyInfo of pub,
+// which is *ecdh.PublicKey or *sidh.PublicKey.
+func MarshalPKIXPublicKey(pub interface{}) ([]byte, error) {
+	a, b, err := publicKeyAlgorithm(pub)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(subjectPublicKeyInfo{
+		Algorithm: algorithmIdentifier{Algorithm: a.oid},
+		PublicKey: asn1.BitString{Bytes: b, BitLength: 8 * len(b)},
+	})
+}
+
+// ParsePKIXPublicKey parses DER encoded SubjectPublicKeyInfo. Returns
+// *ecdh.PublicKey or *sidh.PublicKey.
+func ParsePKIXPublicKey(der []byte) (interface{}, error) {
+	pub, err := parsePKIXPublicKey(der)
+	if err != nil {
+		return nil, &ParseError{"PKIX", err}
+	}
+	return pub, nil
+}
+
+func parsePKIXPublicKey(der []byte) (interface{}, error) {
+	var spki subjectPublicKeyInfo
+	if err := unmarshalDER(der, &spki); err != nil {
+		return nil, err
+	}
+	a, err := parseAlgorithm(&spki.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if spki.PublicKey.BitLength != 8*len(spki.PublicKey.Bytes) {
+		return nil, ErrMalformed
+	}
+	return a.publicKey(spki.PublicKey.Bytes)
+}
+
+// MarshalPKCS8PrivateKey returns DER encoded PKCS#8 PrivateKeyInfo of
+// prv, which is *ecdh.PrivateKey or *sidh.PrivateKey. As in RFC 8410,
+// the private key is wrapped in an OCTET STRING. For SIKE it is S
+// followed by the secret scalar, as returned by sidh.PrivateKey.Export.
+func MarshalPKCS8PrivateKey(prv interface{}) ([]byte, error) {
+	a, b, err := privateKeyAlgorithm(prv)
+	if err != nil {
+		return nil, err
+	}
+	inner, err := asn1.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(oneAsymmetricKey{
+		Algorithm:  algorithmIdentifier{Algorithm: a.oid},
+		PrivateKey: inner,
+	})
+}
+
+// ParsePKCS8PrivateKey parses DER encoded PKCS#8 PrivateKeyInfo or
+// OneAsymmetricKey. Returns *ecdh.PrivateKey or *sidh.PrivateKey. If the
+// public key is present, it must correspond to the private key.
+func ParsePKCS8PrivateKey(der []byte) (interface{}, error) {
+	prv, err := parsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, &ParseError{"PKCS#8", err}
+	}
+	return prv, nil
+}
+
+func parsePKCS8PrivateKey(der []byte) (interface{}, error) {
+	var oak oneAsymmetricKey
+	if err := unmarshalDER(der, &oak); err != nil {
+		return nil, err
+	}
+	hasPublic := len(oak.PublicKey.Bytes) != 0
+	if (oak.Version != 0 && oak.Version != 1) || (oak.Version == 0 && hasPublic) {
+		return nil, ErrMalformed
+	}
+	a, err := parseAlgorithm(&oak.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	var b []byte
+	if err = unmarshalDER(oak.PrivateKey, &b); err != nil {
+		return nil, err
+	}
+	prv, err := a.privateKey(b)
+	if err != nil {
+		return nil, err
+	}
+	if hasPublic {
+		pub := oak.PublicKey
+		if pub.BitLength != 8*len(pub.Bytes) {
+			return nil, ErrMalformed
+		}
+		if subtle.ConstantTimeCompare(pub.Bytes, publicOf(prv)) != 1 {
+			return nil, ErrKeyMismatch
+		}
+	}
+	return prv, nil
+}
+
+// PEM block types
+const (
+	pemPublicKey  = "PUBLIC KEY"
+	pemPrivateKey = "PRIVATE KEY"
+)
+
+// MarshalPEM returns PEM encoded key. Public keys are encoded as
+// "PUBLIC KEY" with SubjectPublicKeyInfo, private keys as "PRIVATE KEY"
+// with PKCS#8.
+func MarshalPEM(key interface{}) ([]byte, error) {
+	if der, err := MarshalPKIXPublicKey(key); err == nil {
+		return pem.EncodeToMemory(&pem.Block{Type: pemPublicKey, Bytes: der}), nil
+	}
+	der, err := MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemPrivateKey, Bytes: der}), nil
+}
+
+// ParsePEM parses the first PEM block in data, which must be either
+// "PUBLIC KEY" or "PRIVATE KEY". Headers are not allowed. Returns the key
+// and the remainder of data.
+func ParsePEM(data []byte) (key interface{}, rest []byte, err error) {
+	block, rest := pem.Decode(data)
+	if block == nil || len(block.Headers) != 0 {
+		return nil, data, &ParseError{"PEM", ErrMalformed}
+	}
+	switch block.Type {
+	case pemPublicKey:
+		key, err = ParsePKIXPublicKey(block.Bytes)
+	case pemPrivateKey:
+		key, err = ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		err = &ParseError{"PEM", ErrMalformed}
+	}
+	if err != nil {
+		return nil, data, err
+	}
+	return key, rest, nil
+}
@@ -0,0 +1,8 @@
+//go:build nobs_insecure
+// +build nobs_insecure
+
+package security
+
+func init() {
+	allowInsecure = 1
+}
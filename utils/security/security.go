@@ -0,0 +1,92 @@
+// Package security describes security properties of the algorithms
+// implemented in this repository and guards use of the broken ones.
+//
+// Algorithms with status Broken refuse to run and return an error
+// matching ErrInsecure, unless use of insecure algorithms was explicitly
+// enabled, either by calling AllowInsecure(true) or by building with the
+// nobs_insecure build tag. This is meant for research, interoperability
+// testing and decrypting old data only.
+package security
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// Status of an algorithm
+type Status uint8
+
+const (
+	// Standardized and considered secure
+	Standardized Status = iota
+	// Under standardization or recommended by national agencies
+	Candidate
+	// Not standardized, security is less understood
+	Experimental
+	// Secure, but shouldn't be used in new applications
+	Deprecated
+	// Practical attacks are known, must not be used
+	Broken
+)
+
+func (s Status) String() string {
+	switch s {
+	case Standardized:
+		return "standardized"
+	case Candidate:
+		return "candidate"
+	case Experimental:
+		return "experimental"
+	case Deprecated:
+		return "deprecated"
+	case Broken:
+		return "broken"
+	}
+	return fmt.Sprintf("Status(%d)", uint8(s))
+}
+
+// Describes security properties of an algorithm
+type Metadata struct {
+	// Name of the algorithm or parameter set
+	Name string
+	// Claimed NIST security category (1 to 5), 0 if no claim is made
+	Level  int
+	Status Status
+	// Specification of the algorithm or, for broken algorithms, the attack
+	Reference string
+}
+
+// Returned by Check for broken algorithms, unless insecure algorithms
+// are allowed.
+var ErrInsecure = errors.New("security: algorithm is broken")
+
+// Non-zero if use of broken algorithms is allowed. Set by build tag
+// nobs_insecure or AllowInsecure.
+var allowInsecure int32
+
+// AllowInsecure enables or disables use of broken algorithms for the
+// whole program. Safe for concurrent use.
+func AllowInsecure(allow bool) {
+	var v int32
+	if allow {
+		v = 1
+	}
+	atomic.StoreInt32(&allowInsecure, v)
+}
+
+// InsecureAllowed reports whether use of broken algorithms is allowed
+func InsecureAllowed() bool {
+	return atomic.LoadInt32(&allowInsecure) != 0
+}
+
+// Check returns an error wrapping ErrInsecure if the algorithm is broken
+// and use of insecure algorithms wasn't enabled. Returns nil otherwise.
+func (m *Metadata) Check() error {
+	if m.Status != Broken || InsecureAllowed() {
+		return nil
+	}
+	return fmt.Errorf("%w: %s must not be used (%s); call "+
+		"security.AllowInsecure(true) or build with tag nobs_insecure to "+
+		"enable it", ErrInsecure, m.Name, m.Reference)
+}
@@ -0,0 +1,33 @@
+package security
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	defer AllowInsecure(InsecureAllowed())
+
+	broken := Metadata{Name: "Foo", Status: Broken, Reference: "attack"}
+	AllowInsecure(false)
+	err := broken.Check()
+	if !errors.Is(err, ErrInsecure) {
+		t.Fatalf("expected ErrInsecure, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Foo") || !strings.Contains(err.Error(), "attack") {
+		t.Errorf("error doesn't name the algorithm: %v", err)
+	}
+	AllowInsecure(true)
+	if err = broken.Check(); err != nil {
+		t.Errorf("insecure algorithm not allowed: %v", err)
+	}
+
+	AllowInsecure(false)
+	for _, s := range []Status{Standardized, Candidate, Experimental, Deprecated} {
+		m := Metadata{Name: "Bar", Status: s}
+		if err = m.Check(); err != nil {
+			t.Errorf("%v algorithm refused: %v", s, err)
+		}
+	}
+}